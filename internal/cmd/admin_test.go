@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/licensing/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_AdminLicenses(t *testing.T) {
+	origNew := newLicensingService
+	t.Cleanup(func() { newLicensingService = origNew })
+
+	assignment := func(user, sku, skuName string) map[string]any {
+		return map[string]any{"userId": user, "productId": "Google-Apps", "productName": "Google Workspace", "skuId": sku, "skuName": skuName}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/product/Google-Apps/users"):
+			if r.URL.Query().Get("customerId") != "example.com" {
+				t.Errorf("customerId = %q", r.URL.Query().Get("customerId"))
+			}
+			if r.URL.Query().Get("pageToken") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"items": []any{
+					assignment("ada@example.com", "1010020028", "Business Standard"),
+					assignment("bob@example.com", "1010020028", "Business Standard"),
+				}, "nextPageToken": "p2"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []any{assignment("ada@example.com", "1010330003", "Google Voice Starter")}})
+		case strings.HasSuffix(r.URL.Path, "/product/Google-Apps/sku/1010020028/user/ada@example.com"):
+			_ = json.NewEncoder(w).Encode(assignment("ada@example.com", "1010020028", "Business Standard"))
+		case strings.Contains(r.URL.Path, "/sku/1010020028/user/"):
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 404, "message": "not found"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := licensing.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newLicensingService = func(context.Context, string) (*licensing.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "admin@example.com", "admin", "licenses", "list", "--user", "Ada@example.com"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	var listed struct {
+		Licenses []licensing.LicenseAssignment `json:"licenses"`
+	}
+	if err := json.Unmarshal([]byte(out), &listed); err != nil || len(listed.Licenses) != 2 || listed.Licenses[1].SkuName != "Google Voice Starter" {
+		t.Fatalf("list = %s err=%v", out, err)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--plain", "--account", "admin@example.com", "admin", "licenses", "list", "--user", "ada@example.com", "--sku", "1010020028"}); err != nil {
+			t.Fatalf("get: %v", err)
+		}
+	})
+	if !strings.Contains(out, "ada@example.com\tGoogle Workspace\tBusiness Standard\t1010020028") {
+		t.Fatalf("get = %q", out)
+	}
+	_ = captureStderr(t, func() {
+		if err := Execute([]string{"--account", "admin@example.com", "admin", "licenses", "list", "--user", "eve@example.com", "--sku", "1010020028"}); err != nil {
+			t.Fatalf("unlicensed user: %v", err)
+		}
+	})
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "admin@example.com", "admin", "licenses", "summary"}); err != nil {
+			t.Fatalf("summary: %v", err)
+		}
+	})
+	var summary struct {
+		Assigned int                  `json:"assigned"`
+		Skus     []licenseSeatSummary `json:"skus"`
+	}
+	if err := json.Unmarshal([]byte(out), &summary); err != nil || summary.Assigned != 3 || len(summary.Skus) != 2 ||
+		summary.Skus[0].SkuID != "1010020028" || summary.Skus[0].Assigned != 2 {
+		t.Fatalf("summary = %s err=%v", out, err)
+	}
+}
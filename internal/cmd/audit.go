@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// auditLogEnv points the audit log somewhere other than
+// <config>/audit.jsonl; "off" disables it.
+const auditLogEnv = "GOG_AUDIT_LOG"
+
+// maxAuditArg bounds each recorded argument (message bodies, JSON payloads).
+const maxAuditArg = 200
+
+// auditEntry is one mutating API call made by gog.
+type auditEntry struct {
+	ID         string    `json:"id"`
+	Time       time.Time `json:"time"`
+	Account    string    `json:"account,omitempty"`
+	Command    string    `json:"command"`
+	Args       []string  `json:"args,omitempty"`
+	Method     string    `json:"method"`
+	URL        string    `json:"url"`
+	Status     int       `json:"status,omitempty"`
+	ResourceID string    `json:"resourceId,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// auditNow is swapped in tests.
+var auditNow = time.Now
+
+var auditMu sync.Mutex
+
+// auditLogPath returns the log path, or "" when auditing is off.
+func auditLogPath() (string, error) {
+	switch v := strings.TrimSpace(os.Getenv(auditLogEnv)); strings.ToLower(v) {
+	case "":
+		return config.AuditLogPath()
+	case "off", "0", "false", "none":
+		return "", nil
+	default:
+		return v, nil
+	}
+}
+
+// appendAuditEntry appends e as one JSON line. The file is only ever opened
+// for appending, so earlier entries are never rewritten.
+func appendAuditEntry(path string, e auditEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func loadAuditLog(path string) ([]auditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var out []auditEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var e auditEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue // a torn line from an interrupted write
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
+
+// auditRecorder returns the googleapi.TransportOptions.Audit hook for one
+// command run, or nil when auditing is off.
+func auditRecorder(cmd *cobra.Command, args []string) func(googleapi.AuditCall) {
+	path, err := auditLogPath()
+	if err != nil || path == "" {
+		return nil
+	}
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	recorded := redactAuditArgs(args)
+	var warnOnce sync.Once
+	return func(c googleapi.AuditCall) {
+		e := auditEntry{
+			ID:         newAuditID(),
+			Time:       auditNow().UTC(),
+			Account:    c.Account,
+			Command:    command,
+			Args:       recorded,
+			Method:     c.Method,
+			URL:        c.URL,
+			Status:     c.Status,
+			ResourceID: c.ID,
+			Error:      c.Err,
+		}
+		if err := appendAuditEntry(path, e); err != nil {
+			warnOnce.Do(func() { slog.Warn("audit log write failed", "path", path, "err", err) })
+		}
+	}
+}
+
+func newAuditID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// redactAuditArgs hides the values of secret-looking flags and truncates
+// long arguments.
+func redactAuditArgs(args []string) []string {
+	out := make([]string, 0, len(args))
+	redactNext := false
+	for _, a := range args {
+		switch {
+		case redactNext:
+			a = "[redacted]"
+			redactNext = false
+		case strings.HasPrefix(a, "--") && isSecretFlag(a):
+			if name, _, ok := strings.Cut(a, "="); ok {
+				a = name + "=[redacted]"
+			} else {
+				redactNext = true
+			}
+		}
+		if len(a) > maxAuditArg {
+			a = fmt.Sprintf("%s…(%d bytes)", a[:maxAuditArg], len(a))
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func isSecretFlag(arg string) bool {
+	name, _, _ := strings.Cut(strings.ToLower(arg), "=")
+	for _, s := range []string{"token", "password", "passphrase", "secret"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func newAuditCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the log of mutating API calls",
+		Long: `Every mutating API call (sends, label changes, deletes, settings updates;
+anything but a read) is appended to <config>/audit.jsonl with the time,
+account, command, arguments, HTTP status and the returned resource ID.
+
+Set GOG_AUDIT_LOG to log elsewhere, or GOG_AUDIT_LOG=off to disable it.`,
+	}
+	cmd.AddCommand(newAuditListCmd(flags))
+	cmd.AddCommand(newAuditShowCmd(flags))
+	return cmd
+}
+
+func newAuditListCmd(flags *rootFlags) *cobra.Command {
+	var since, command string
+	var failed bool
+	var max int64
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recorded API calls, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			path, err := auditLogPath()
+			if err != nil {
+				return err
+			}
+			if path == "" {
+				return usagef("audit log is disabled (%s=off)", auditLogEnv)
+			}
+			var cutoff time.Time
+			if strings.TrimSpace(since) != "" {
+				window, err := parseFollowupDelay(since)
+				if err != nil {
+					return usagef("invalid --since %q (use e.g. 7d, 2w, 12h)", since)
+				}
+				cutoff = auditNow().Add(-window)
+			}
+			all, err := loadAuditLog(path)
+			if err != nil {
+				return err
+			}
+
+			account := strings.TrimSpace(flags.Account)
+			command = strings.ToLower(strings.Join(strings.Fields(command), " "))
+			var entries []auditEntry
+			for i := len(all) - 1; i >= 0; i-- {
+				e := all[i]
+				switch {
+				case e.Time.Before(cutoff),
+					account != "" && !strings.EqualFold(e.Account, account),
+					command != "" && matchCommandRule([]string{command}, e.Command) == "",
+					failed && e.Error == "" && e.Status < 400:
+					continue
+				}
+				entries = append(entries, e)
+				if max > 0 && int64(len(entries)) >= max {
+					break
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				if entries == nil {
+					entries = []auditEntry{}
+				}
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"entries": entries})
+			}
+			if len(entries) == 0 {
+				u.Err().Println("No audit entries")
+				return nil
+			}
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "ID\tTIME\tACCOUNT\tCOMMAND\tMETHOD\tURL\tSTATUS\tRESOURCE")
+			for _, e := range entries {
+				status := orDash(e.Error)
+				if e.Status != 0 {
+					status = fmt.Sprint(e.Status)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", e.ID, e.Time.Local().Format(time.RFC3339), orDash(e.Account),
+					e.Command, e.Method, e.URL, status, orDash(e.ResourceID))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", "Only calls newer than this (e.g. 24h, 7d, 2w)")
+	cmd.Flags().StringVar(&command, "command", "", "Only calls made by this command path (e.g. \"gmail send\", \"drive\")")
+	cmd.Flags().BoolVar(&failed, "failed", false, "Only calls that errored or returned HTTP >= 400")
+	cmd.Flags().Int64Var(&max, "max", 50, "Max entries (0 = all)")
+	return cmd
+}
+
+func newAuditShowCmd(_ *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show one recorded API call",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			path, err := auditLogPath()
+			if err != nil {
+				return err
+			}
+			if path == "" {
+				return usagef("audit log is disabled (%s=off)", auditLogEnv)
+			}
+			all, err := loadAuditLog(path)
+			if err != nil {
+				return err
+			}
+			id := strings.TrimSpace(args[0])
+			for _, e := range all {
+				if e.ID != id {
+					continue
+				}
+				if outfmt.IsJSON(cmd.Context()) {
+					return outfmt.WriteJSON(os.Stdout, e)
+				}
+				u.Out().Printf("id\t%s", e.ID)
+				u.Out().Printf("time\t%s", e.Time.Local().Format(time.RFC3339))
+				u.Out().Printf("account\t%s", orDash(e.Account))
+				u.Out().Printf("command\t%s", e.Command)
+				u.Out().Printf("args\t%s", strings.Join(e.Args, " "))
+				u.Out().Printf("method\t%s", e.Method)
+				u.Out().Printf("url\t%s", e.URL)
+				if e.Status != 0 {
+					u.Out().Printf("status\t%d", e.Status)
+				}
+				if e.ResourceID != "" {
+					u.Out().Printf("resource_id\t%s", e.ResourceID)
+				}
+				if e.Error != "" {
+					u.Out().Printf("error\t%s", e.Error)
+				}
+				return nil
+			}
+			return &ExitError{Code: 1, Err: fmt.Errorf("audit entry %q not found", id)}
+		},
+	}
+}
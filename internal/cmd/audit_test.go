@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_AuditRecordsMutations(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	t.Setenv(auditLogEnv, logPath)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX"}}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "Label_9", "name": "Receipts"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// Build the service the way the real factory does, so the audit
+	// transport from the command context wraps it.
+	newGmailService = func(ctx context.Context, email string) (*gmail.Service, error) {
+		c := srv.Client()
+		if rec := googleapi.TransportOptionsFromContext(ctx).Audit; rec != nil {
+			c = &http.Client{Transport: &googleapi.AuditTransport{Base: c.Transport, Account: email, Record: rec}}
+		}
+		return gmail.NewService(ctx, option.WithoutAuthentication(), option.WithHTTPClient(c), option.WithEndpoint(srv.URL+"/"))
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "labels", "list"}); err != nil {
+			t.Fatalf("labels list: %v", err)
+		}
+		if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "labels", "create", "Receipts"}); err != nil {
+			t.Fatalf("labels create: %v", err)
+		}
+	})
+
+	entries, err := loadAuditLog(logPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	e := entries[0]
+	if e.Command != "gmail labels create" || e.Account != "a@b.com" || e.Method != http.MethodPost ||
+		e.Status != http.StatusOK || e.ResourceID != "Label_9" || !strings.HasSuffix(e.URL, "/users/me/labels") {
+		t.Fatalf("entry = %+v", e)
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "audit", "list", "--command", "gmail labels"}); err != nil {
+			t.Fatalf("audit list: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"resourceId": "Label_9"`) {
+		t.Fatalf("list = %s", out)
+	}
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"audit", "show", e.ID}); err != nil {
+			t.Fatalf("audit show: %v", err)
+		}
+	})
+	if !strings.Contains(out, "args\t--json --account a@b.com gmail labels create Receipts") {
+		t.Fatalf("show = %s", out)
+	}
+
+	t.Setenv(auditLogEnv, "off")
+	_ = captureStdout(t, func() {
+		_ = Execute([]string{"--json", "--account", "a@b.com", "gmail", "labels", "create", "Receipts"})
+	})
+	if data, _ := os.ReadFile(logPath); strings.Count(string(data), "\n") != 1 {
+		t.Fatalf("GOG_AUDIT_LOG=off still logged:\n%s", data)
+	}
+}
+
+func TestRedactAuditArgs(t *testing.T) {
+	got := redactAuditArgs([]string{"--hook-token", "s3cret", "--password=hunter2", "--body", strings.Repeat("x", 300)})
+	if got[1] != "[redacted]" || got[2] != "--password=[redacted]" || !strings.HasSuffix(got[4], "…(300 bytes)") {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -49,7 +48,7 @@ func newAuthCredentialsCmd() *cobra.Command {
 			var b []byte
 			var err error
 			if inPath == "-" {
-				b, err = io.ReadAll(os.Stdin)
+				b, err = readStdin()
 			} else {
 				b, err = os.ReadFile(inPath)
 			}
@@ -118,6 +117,7 @@ func newAuthTokensCmd(flags *rootFlags) *cobra.Command {
 
 	cmd.AddCommand(newAuthTokensExportCmd())
 	cmd.AddCommand(newAuthTokensImportCmd())
+	cmd.AddCommand(newAuthTokensMigrateCmd(flags))
 
 	cmd.AddCommand(&cobra.Command{
 		Use:   "delete <email>",
@@ -254,7 +254,7 @@ func newAuthTokensImportCmd() *cobra.Command {
 			var b []byte
 			var err error
 			if inPath == "-" {
-				b, err = io.ReadAll(os.Stdin)
+				b, err = readStdin()
 			} else {
 				b, err = os.ReadFile(inPath)
 			}
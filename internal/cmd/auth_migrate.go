@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/secrets"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+var openSecretsBackend = secrets.Open
+
+func newAuthTokensMigrateCmd(flags *rootFlags) *cobra.Command {
+	var from string
+	var to string
+	var deleteSource bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy stored tokens between token store backends",
+		Long: fmt.Sprintf(`Copy every stored refresh token (and the default account) from one token
+store backend to another. Backends: %s.
+
+--from defaults to the current store (--token-store / GOG_TOKEN_STORE).
+The env backend is read-only, so it can only be a source. --delete-source
+removes the tokens from the source once all of them were copied.`, strings.Join(secrets.Backends(), ", ")),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			var err error
+			if strings.TrimSpace(from) == "" {
+				from, err = secrets.CurrentBackend()
+			} else {
+				from, err = secrets.ParseBackend(from)
+			}
+			if err != nil {
+				return usage(err.Error())
+			}
+			if strings.TrimSpace(to) == "" {
+				return usage("--to is required")
+			}
+			to, err = secrets.ParseBackend(to)
+			if err != nil {
+				return usage(err.Error())
+			}
+			if from == to {
+				return usagef("--from and --to are both %s", from)
+			}
+			if to == secrets.BackendEnv {
+				return usage("the env token store is read-only; export tokens with gog auth tokens export instead")
+			}
+			if deleteSource {
+				if err := confirmDestructive(cmd, flags, fmt.Sprintf("delete tokens from the %s store after migrating", from)); err != nil {
+					return err
+				}
+			}
+
+			src, err := openSecretsBackend(from)
+			if err != nil {
+				return fmt.Errorf("open %s store: %w", from, err)
+			}
+			dst, err := openSecretsBackend(to)
+			if err != nil {
+				return fmt.Errorf("open %s store: %w", to, err)
+			}
+			emails, err := secrets.Migrate(src, dst, deleteSource)
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"from":          from,
+					"to":            to,
+					"migrated":      emails,
+					"deletedSource": deleteSource,
+				})
+			}
+			if len(emails) == 0 {
+				u.Err().Printf("No tokens in the %s store", from)
+				return nil
+			}
+			for _, email := range emails {
+				u.Out().Printf("migrated\t%s", email)
+			}
+			u.Err().Printf("Set GOG_TOKEN_STORE=%s (or pass --token-store %s) to use the new store", to, to)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Source backend: keyring|file|pass|env (default: current store)")
+	cmd.Flags().StringVar(&to, "to", "", "Destination backend: keyring|file|pass (required)")
+	cmd.Flags().BoolVar(&deleteSource, "delete-source", false, "Delete tokens from the source store after copying")
+	return cmd
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+func TestAuthTokensMigrate(t *testing.T) {
+	origOpen := openSecretsBackend
+	t.Cleanup(func() { openSecretsBackend = origOpen })
+
+	stores := map[string]*memSecretsStore{
+		secrets.BackendKeyring: newMemSecretsStore(),
+		secrets.BackendFile:    newMemSecretsStore(),
+	}
+	if err := stores[secrets.BackendKeyring].SetToken("a@b.com", secrets.Token{RefreshToken: "rt"}); err != nil {
+		t.Fatalf("SetToken: %v", err)
+	}
+	openSecretsBackend = func(name string) (secrets.Store, error) { return stores[name], nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--force", "auth", "tokens", "migrate", "--to", "file", "--delete-source"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, `"from": "keyring"`) || !strings.Contains(out, `"a@b.com"`) {
+		t.Fatalf("unexpected out=%q", out)
+	}
+	if tok, err := stores[secrets.BackendFile].GetToken("a@b.com"); err != nil || tok.RefreshToken != "rt" {
+		t.Fatalf("not migrated: %#v %v", tok, err)
+	}
+	if len(stores[secrets.BackendKeyring].tokens) != 0 {
+		t.Fatalf("expected source emptied")
+	}
+
+	for _, args := range [][]string{
+		{"auth", "tokens", "migrate", "--to", "keyring"},
+		{"auth", "tokens", "migrate", "--to", "env"},
+		{"auth", "tokens", "migrate", "--to", "vault"},
+		{"--token-store", "vault", "auth", "tokens", "list"},
+	} {
+		_ = captureStderr(t, func() {
+			if err := Execute(args); err == nil {
+				t.Fatalf("expected error for %v", args)
+			}
+		})
+	}
+}
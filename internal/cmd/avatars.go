@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/statefile"
+	"google.golang.org/api/people/v1"
+)
+
+const (
+	// avatarCacheTTL is how long a cached photo (or a known miss) is reused.
+	avatarCacheTTL = 7 * 24 * time.Hour
+	maxAvatarBytes = 1 << 20
+)
+
+// avatarHTTPClient downloads photo URLs; tests swap it.
+var avatarHTTPClient = &http.Client{Timeout: 20 * time.Second}
+
+// avatarCache fetches contact photos by email address (contacts first, then
+// other contacts) and keeps them in the avatar cache dir. Misses are cached
+// too, so a sender without a photo costs one lookup per TTL.
+type avatarCache struct {
+	account string
+	dir     string
+	refresh bool
+
+	contacts *people.Service
+	other    *people.Service
+}
+
+func newAvatarCache(account string) (*avatarCache, error) {
+	dir, err := config.AvatarCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &avatarCache{account: account, dir: dir}, nil
+}
+
+func avatarKey(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:16])
+}
+
+// cached returns the cached photo for email; ok reports whether the cache
+// had a fresh answer (data is nil for a cached miss).
+func (c *avatarCache) cached(email string) (data []byte, ok bool) {
+	if c.refresh {
+		return nil, false
+	}
+	base := filepath.Join(c.dir, avatarKey(email))
+	for _, ext := range []string{".img", ".none"} {
+		st, err := os.Stat(base + ext)
+		if err != nil || time.Since(st.ModTime()) > avatarCacheTTL {
+			continue
+		}
+		if ext == ".none" {
+			return nil, true
+		}
+		b, err := statefile.ReadFile(base + ext)
+		if err != nil {
+			return nil, false
+		}
+		return b, true
+	}
+	return nil, false
+}
+
+func (c *avatarCache) store(email string, data []byte) error {
+	if _, err := config.EnsureAvatarCacheDir(); err != nil {
+		return err
+	}
+	base := filepath.Join(c.dir, avatarKey(email))
+	if data == nil {
+		_ = os.Remove(base + ".img")
+		return os.WriteFile(base+".none", nil, 0o600)
+	}
+	_ = os.Remove(base + ".none")
+	return statefile.WriteFile(base+".img", data, 0o600)
+}
+
+// get returns the photo of email, or nil when the address has none.
+func (c *avatarCache) get(ctx context.Context, email string) ([]byte, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil, nil
+	}
+	if data, ok := c.cached(email); ok {
+		return data, nil
+	}
+	url, err := c.photoURL(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	if url != "" {
+		if data, err = downloadAvatar(ctx, url); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.store(email, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// photoURL finds a non-default contact photo for email.
+func (c *avatarCache) photoURL(ctx context.Context, email string) (string, error) {
+	var firstErr error
+	if c.contacts == nil {
+		if c.contacts, firstErr = newPeopleContactsService(ctx, c.account); firstErr != nil {
+			c.contacts = nil
+		}
+	}
+	if c.contacts != nil {
+		resp, err := c.contacts.People.SearchContacts().Query(email).ReadMask("emailAddresses,photos").Context(ctx).Do()
+		if err == nil {
+			for _, r := range resp.Results {
+				if url := personPhotoFor(r.Person, email); url != "" {
+					return url, nil
+				}
+			}
+		} else {
+			firstErr = err
+		}
+	}
+
+	if c.other == nil {
+		var err error
+		if c.other, err = newPeopleOtherContactsService(ctx, c.account); err != nil {
+			c.other = nil
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if c.other != nil {
+		resp, err := c.other.OtherContacts.Search().Query(email).ReadMask("emailAddresses,photos").Context(ctx).Do()
+		if err == nil {
+			for _, r := range resp.Results {
+				if url := personPhotoFor(r.Person, email); url != "" {
+					return url, nil
+				}
+			}
+			return "", nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return "", firstErr
+}
+
+// personPhotoFor returns p's photo URL when p has the address email and a
+// photo other than the generated letter placeholder.
+func personPhotoFor(p *people.Person, email string) string {
+	if p == nil {
+		return ""
+	}
+	match := false
+	for _, e := range p.EmailAddresses {
+		if e != nil && strings.EqualFold(strings.TrimSpace(e.Value), email) {
+			match = true
+		}
+	}
+	if !match {
+		return ""
+	}
+	for _, ph := range p.Photos {
+		if ph != nil && ph.Url != "" && !ph.Default {
+			return ph.Url
+		}
+	}
+	return ""
+}
+
+func downloadAvatar(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := avatarHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download photo: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAvatarBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxAvatarBytes {
+		return nil, errors.New("download photo: too large")
+	}
+	return data, nil
+}
+
+// avatarDataURI embeds an image for self-contained HTML.
+func avatarDataURI(data []byte) string {
+	return "data:" + http.DetectContentType(data) + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// senderAddress is the bare, lowercased address of a From header.
+func senderAddress(from string) string {
+	addr, err := mail.ParseAddress(strings.TrimSpace(from))
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(from))
+	}
+	return strings.ToLower(addr.Address)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/people/v1"
+)
+
+func TestAvatarCache(t *testing.T) {
+	t.Setenv("GOG_CACHE_DIR", t.TempDir())
+	origContacts, origOther, origHTTP := newPeopleContactsService, newPeopleOtherContactsService, avatarHTTPClient
+	t.Cleanup(func() {
+		newPeopleContactsService, newPeopleOtherContactsService, avatarHTTPClient = origContacts, origOther, origHTTP
+	})
+
+	png := []byte("\x89PNG\r\n\x1a\nfake")
+	lookups := 0
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/photo/ana":
+			_, _ = w.Write(png)
+		case strings.HasSuffix(r.URL.Path, "people:searchContacts"):
+			lookups++
+			w.Header().Set("Content-Type", "application/json")
+			results := []map[string]any{}
+			if r.URL.Query().Get("query") == "ana@example.com" {
+				results = append(results, map[string]any{"person": map[string]any{
+					"emailAddresses": []map[string]any{{"value": "Ana@Example.com"}},
+					"photos":         []map[string]any{{"url": srv.URL + "/photo/ana"}},
+				}})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"results": results})
+		case strings.HasSuffix(r.URL.Path, "otherContacts:search"):
+			lookups++
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"results": []any{}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := people.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("people: %v", err)
+	}
+	newPeopleContactsService = func(context.Context, string) (*people.Service, error) { return svc, nil }
+	newPeopleOtherContactsService = func(context.Context, string) (*people.Service, error) { return svc, nil }
+	avatarHTTPClient = srv.Client()
+
+	cache, err := newAvatarCache("a@b.com")
+	if err != nil {
+		t.Fatalf("cache: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		data, err := cache.get(context.Background(), "ana@example.com")
+		if err != nil || string(data) != string(png) {
+			t.Fatalf("get #%d = %q, %v", i, data, err)
+		}
+		if data, err := cache.get(context.Background(), "nobody@example.com"); err != nil || data != nil {
+			t.Fatalf("miss #%d = %q, %v", i, data, err)
+		}
+	}
+	// One contacts lookup for ana; contacts + other contacts for the miss.
+	if lookups != 3 {
+		t.Fatalf("lookups = %d, want 3 (second round cached)", lookups)
+	}
+	if got := avatarDataURI(png); !strings.HasPrefix(got, "data:image/png;base64,") {
+		t.Fatalf("data uri = %q", got)
+	}
+}
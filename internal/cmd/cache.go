@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// httpCacheEnv turns the HTTP response cache on (1/true) or off (0/false),
+// overriding http.cache in config.json.
+const httpCacheEnv = "GOG_HTTP_CACHE"
+
+// httpCacheFromEnv returns the HTTP cache when GOG_HTTP_CACHE or
+// http.cache in config.json enables it, else nil.
+func httpCacheFromEnv(cfg config.HTTPConfig) (*googleapi.HTTPCache, error) {
+	enabled := cfg.Cache
+	if raw := strings.TrimSpace(os.Getenv(httpCacheEnv)); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, usagef("invalid %s=%q (use 1 or 0)", httpCacheEnv, raw)
+		}
+		enabled = v
+	}
+	if !enabled {
+		return nil, nil
+	}
+	dir, err := config.HTTPCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &googleapi.HTTPCache{Dir: dir}, nil
+}
+
+func newCacheCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the HTTP response cache",
+		Long: `With GOG_HTTP_CACHE=1 (or "http": {"cache": true} in config.json), GET
+responses that carry an ETag are kept in <cache>/http per account. Repeated
+calls send If-None-Match, and a 304 Not Modified is answered from the cache,
+so scripts re-reading unchanged lists and files download nothing. Every
+call is still revalidated, so results are never stale.
+
+--no-cache bypasses the cache for one run.`,
+	}
+	cmd.AddCommand(newCacheClearCmd(flags))
+	return cmd
+}
+
+func newCacheClearCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Delete cached API responses (only --account's, when given)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			dir, err := config.HTTPCacheDir()
+			if err != nil {
+				return err
+			}
+			account := strings.TrimSpace(flags.Account)
+			removed, err := (&googleapi.HTTPCache{Dir: dir}).Clear(account)
+			if err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"removed": removed, "account": account})
+			}
+			u.Out().Printf("removed\t%d", removed)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func TestHTTPCacheFromEnv(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	cacheDir := t.TempDir()
+	t.Setenv("GOG_CACHE_DIR", cacheDir)
+
+	t.Setenv(httpCacheEnv, "")
+	if c, err := httpCacheFromEnv(config.HTTPConfig{}); err != nil || c != nil {
+		t.Fatalf("default should be off: %v %v", c, err)
+	}
+	c, err := httpCacheFromEnv(config.HTTPConfig{Cache: true})
+	if err != nil || c == nil || c.Dir != filepath.Join(cacheDir, "http") {
+		t.Fatalf("config.json should enable: %+v %v", c, err)
+	}
+	t.Setenv(httpCacheEnv, "0")
+	if c, err := httpCacheFromEnv(config.HTTPConfig{Cache: true}); err != nil || c != nil {
+		t.Fatalf("env should override config: %v %v", c, err)
+	}
+	t.Setenv(httpCacheEnv, "maybe")
+	if _, err := httpCacheFromEnv(config.HTTPConfig{}); err == nil {
+		t.Fatalf("expected error for invalid %s", httpCacheEnv)
+	}
+
+	t.Setenv(httpCacheEnv, "1")
+	flags := &rootFlags{HedgePercentile: 95, NoCache: true}
+	if opts, err := transportOptionsFromFlags(flags); err != nil || opts.Cache != nil {
+		t.Fatalf("--no-cache should bypass: %+v %v", opts.Cache, err)
+	}
+}
+
+func TestExecute_CacheClear(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	cacheDir := t.TempDir()
+	t.Setenv("GOG_CACHE_DIR", cacheDir)
+	for _, p := range []string{"http/aaaa/1.json", "http/aaaa/2.json", "http/bbbb/3.json"} {
+		path := filepath.Join(cacheDir, p)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"cache", "clear"}); err != nil {
+			t.Fatalf("clear: %v", err)
+		}
+	})
+	if strings.TrimSpace(out) != "removed\t3" {
+		t.Fatalf("out = %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "http")); !os.IsNotExist(err) {
+		t.Fatalf("cache dir should be gone: %v", err)
+	}
+}
+
+func TestExecute_ConfigPathsWithBrokenConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOG_CONFIG_DIR", dir)
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_ = captureStderr(t, func() {
+		_ = captureStdout(t, func() {
+			if err := Execute([]string{"config", "paths"}); err != nil {
+				t.Fatalf("config paths with a broken config.json: %v", err)
+			}
+		})
+	})
+}
+
+func TestExecute_GmailSearchHelpListsBothCacheFlags(t *testing.T) {
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			_ = Execute([]string{"gmail", "search", "--help"})
+		})
+	})
+	if !strings.Contains(out, "--no-sync-cache") || !strings.Contains(out, "Bypass the HTTP response cache") {
+		t.Fatalf("search help should list --no-sync-cache and the global --no-cache:\n%s", out)
+	}
+}
@@ -33,12 +33,15 @@ func newCalendarCmd(flags *rootFlags) *cobra.Command {
 	cmd.AddCommand(newCalendarConflictsCmd(flags))
 	cmd.AddCommand(newCalendarSearchCmd(flags))
 	cmd.AddCommand(newCalendarTimeCmd(flags))
+	cmd.AddCommand(newCalendarReportCmd(flags))
+	cmd.AddCommand(newCalendarRemindCmd(flags))
 	return cmd
 }
 
 func newCalendarCalendarsCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "calendars",
@@ -56,17 +59,27 @@ func newCalendarCalendarsCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			resp, err := svc.CalendarList.List().MaxResults(max).PageToken(page).Do()
+			items, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*calendar.CalendarListEntry, string, error) {
+				resp, err := svc.CalendarList.List().MaxResults(max).PageToken(pageToken).Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Items, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(items))
 			if err != nil {
 				return err
 			}
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
-					"calendars":     resp.Items,
-					"nextPageToken": resp.NextPageToken,
-				})
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"calendars":     items,
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
-			if len(resp.Items) == 0 {
+			if len(items) == 0 {
 				u.Err().Println("No calendars")
 				return nil
 			}
@@ -74,22 +87,24 @@ func newCalendarCalendarsCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "ID\tNAME\tROLE")
-			for _, c := range resp.Items {
+			for _, c := range items {
 				fmt.Fprintf(w, "%s\t%s\t%s\n", c.Id, c.Summary, c.AccessRole)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 100, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 	return cmd
 }
 
 func newCalendarAclCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "acl <calendarId>",
@@ -108,17 +123,27 @@ func newCalendarAclCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			resp, err := svc.Acl.List(calendarID).MaxResults(max).PageToken(page).Do()
+			items, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*calendar.AclRule, string, error) {
+				resp, err := svc.Acl.List(calendarID).MaxResults(max).PageToken(pageToken).Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Items, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(items))
 			if err != nil {
 				return err
 			}
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
-					"rules":         resp.Items,
-					"nextPageToken": resp.NextPageToken,
-				})
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"rules":         items,
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
-			if len(resp.Items) == 0 {
+			if len(items) == 0 {
 				u.Err().Println("No ACL rules")
 				return nil
 			}
@@ -126,7 +151,7 @@ func newCalendarAclCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "SCOPE_TYPE\tSCOPE_VALUE\tROLE")
-			for _, rule := range resp.Items {
+			for _, rule := range items {
 				scopeType := ""
 				scopeValue := ""
 				if rule.Scope != nil {
@@ -135,13 +160,14 @@ func newCalendarAclCmd(flags *rootFlags) *cobra.Command {
 				}
 				fmt.Fprintf(w, "%s\t%s\t%s\n", scopeType, scopeValue, rule.Role)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 100, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 	return cmd
 }
 
@@ -152,11 +178,19 @@ func newCalendarEventsCmd(flags *rootFlags) *cobra.Command {
 	var page string
 	var query string
 	var all bool
+	var pages pageFlags
+	var columns calendarColumnFlags
 
 	cmd := &cobra.Command{
 		Use:   "events [<calendarId>]",
 		Short: "List events from a calendar or all calendars",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `List events from a calendar or, with --all, every calendar in the list.
+
+The table can add an ISO week number column (--week-numbers) and a column
+with start times in a second timezone (--tz2 Europe/London); set
+calendar.weekNumbers / calendar.secondaryTimezone in config.json to make
+them the default.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			account, err := requireAccount(flags)
 			if err != nil {
@@ -180,16 +214,21 @@ func newCalendarEventsCmd(flags *rootFlags) *cobra.Command {
 				to = oneWeekLater.Format(time.RFC3339)
 			}
 
+			cols, err := columns.resolve(cmd)
+			if err != nil {
+				return err
+			}
+
 			svc, err := newCalendarService(cmd.Context(), account)
 			if err != nil {
 				return err
 			}
 
 			if all {
-				return listAllCalendarsEvents(cmd, svc, from, to, max, page, query)
+				return listAllCalendarsEvents(cmd, svc, from, to, max, page, query, pages, cols)
 			}
 			calendarID := args[0]
-			return listCalendarEvents(cmd, svc, calendarID, from, to, max, page, query)
+			return listCalendarEvents(cmd, svc, calendarID, from, to, max, page, query, pages, cols)
 		},
 	}
 
@@ -199,34 +238,52 @@ func newCalendarEventsCmd(flags *rootFlags) *cobra.Command {
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
 	cmd.Flags().StringVar(&query, "query", "", "Free text search")
 	cmd.Flags().BoolVar(&all, "all", false, "Fetch events from all calendars")
+	pages.addFlagsNamed(cmd, "all-pages")
+	columns.addFlags(cmd)
 	return cmd
 }
 
-func listCalendarEvents(cmd *cobra.Command, svc *calendar.Service, calendarID, from, to string, max int64, page, query string) error {
+// fetchCalendarEvents lists expanded events of one calendar, following
+// pages with --all-pages.
+func fetchCalendarEvents(cmd *cobra.Command, svc *calendar.Service, calendarID, from, to string, max int64, page, query string, pages pageFlags) ([]*calendar.Event, string, error) {
+	return fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*calendar.Event, string, error) {
+		call := svc.Events.List(calendarID).
+			TimeMin(from).
+			TimeMax(to).
+			MaxResults(max).
+			PageToken(pageToken).
+			SingleEvents(true).
+			OrderBy("startTime")
+		if strings.TrimSpace(query) != "" {
+			call = call.Q(query)
+		}
+		resp, err := call.Context(cmd.Context()).Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Items, resp.NextPageToken, nil
+	})
+}
+
+func listCalendarEvents(cmd *cobra.Command, svc *calendar.Service, calendarID, from, to string, max int64, page, query string, pages pageFlags, cols calendarColumns) error {
 	u := ui.FromContext(cmd.Context())
 
-	call := svc.Events.List(calendarID).
-		TimeMin(from).
-		TimeMax(to).
-		MaxResults(max).
-		PageToken(page).
-		SingleEvents(true).
-		OrderBy("startTime")
-	if strings.TrimSpace(query) != "" {
-		call = call.Q(query)
-	}
-	resp, err := call.Context(cmd.Context()).Do()
+	items, nextPageToken, err := fetchCalendarEvents(cmd, svc, calendarID, from, to, max, page, query, pages)
+	partial, err := splitPageError(cmd.Context(), err, len(items))
 	if err != nil {
 		return err
 	}
 	if outfmt.IsJSON(cmd.Context()) {
-		return outfmt.WriteJSON(os.Stdout, map[string]any{
-			"events":        resp.Items,
-			"nextPageToken": resp.NextPageToken,
-		})
+		if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+			"events":        items,
+			"nextPageToken": nextPageToken,
+		}); err != nil {
+			return err
+		}
+		return partial
 	}
 
-	if len(resp.Items) == 0 {
+	if len(items) == 0 {
 		u.Err().Println("No events")
 		return nil
 	}
@@ -234,12 +291,12 @@ func listCalendarEvents(cmd *cobra.Command, svc *calendar.Service, calendarID, f
 	w, flush := tableWriter(cmd.Context())
 	defer flush()
 
-	fmt.Fprintln(w, "ID\tSTART\tEND\tSUMMARY")
-	for _, e := range resp.Items {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Id, eventStart(e), eventEnd(e), e.Summary)
+	fmt.Fprintln(w, cols.header("ID"))
+	for _, e := range items {
+		fmt.Fprintln(w, cols.row(e, e.Id))
 	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	printNextPageHint(u, nextPageToken)
+	return partial
 }
 
 type eventWithCalendar struct {
@@ -247,7 +304,7 @@ type eventWithCalendar struct {
 	CalendarID string
 }
 
-func listAllCalendarsEvents(cmd *cobra.Command, svc *calendar.Service, from, to string, max int64, page, query string) error {
+func listAllCalendarsEvents(cmd *cobra.Command, svc *calendar.Service, from, to string, max int64, page, query string, pages pageFlags, cols calendarColumns) error {
 	u := ui.FromContext(cmd.Context())
 
 	// Get all calendars
@@ -263,34 +320,37 @@ func listAllCalendarsEvents(cmd *cobra.Command, svc *calendar.Service, from, to
 
 	// Collect events from all calendars
 	var allEvents []*eventWithCalendar
+	var partial error
 	for _, cal := range calResp.Items {
-		call := svc.Events.List(cal.Id).
-			TimeMin(from).
-			TimeMax(to).
-			MaxResults(max).
-			PageToken(page).
-			SingleEvents(true).
-			OrderBy("startTime")
-		if strings.TrimSpace(query) != "" {
-			call = call.Q(query)
-		}
-		resp, err := call.Context(cmd.Context()).Do()
-		if err != nil {
+		items, _, err := fetchCalendarEvents(cmd, svc, cal.Id, from, to, max, page, query, pages)
+		if googleapi.IsBudgetExceededError(err) {
+			// Print what was fetched before the budget ran out.
+			partial = err
+		} else if err != nil {
 			// Skip calendars that fail (e.g., due to permissions)
 			continue
 		}
-		for _, e := range resp.Items {
+		for _, e := range items {
 			allEvents = append(allEvents, &eventWithCalendar{
 				Event:      e,
 				CalendarID: cal.Id,
 			})
 		}
+		if partial != nil {
+			break
+		}
 	}
 
 	if len(allEvents) == 0 {
+		if partial != nil {
+			return partial
+		}
 		u.Err().Println("No events")
 		return nil
 	}
+	if partial != nil {
+		u.Err().Printf("WARN: listing stopped after %d events: %v", len(allEvents), partial)
+	}
 
 	// Sort events by start time
 	sortEventsByStartTime(allEvents)
@@ -317,17 +377,20 @@ func listAllCalendarsEvents(cmd *cobra.Command, svc *calendar.Service, from, to
 			}
 			events = append(events, eventMap)
 		}
-		return outfmt.WriteJSON(os.Stdout, map[string]any{"events": events})
+		if err := outfmt.WriteJSON(os.Stdout, map[string]any{"events": events}); err != nil {
+			return err
+		}
+		return partial
 	}
 
 	w, flush := tableWriter(cmd.Context())
 	defer flush()
 
-	fmt.Fprintln(w, "CALENDAR\tID\tSTART\tEND\tSUMMARY")
+	fmt.Fprintln(w, cols.header("CALENDAR", "ID"))
 	for _, e := range allEvents {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.CalendarID, e.Id, eventStart(e.Event), eventEnd(e.Event), e.Summary)
+		fmt.Fprintln(w, cols.row(e.Event, e.CalendarID, e.Id))
 	}
-	return nil
+	return partial
 }
 
 func sortEventsByStartTime(events []*eventWithCalendar) {
@@ -345,7 +408,10 @@ func sortEventsByStartTime(events []*eventWithCalendar) {
 }
 
 func newCalendarEventCmd(flags *rootFlags) *cobra.Command {
-	return &cobra.Command{
+	var downloadAttachments bool
+	var outDir string
+
+	cmd := &cobra.Command{
 		Use:   "event <calendarId> <eventId>",
 		Short: "Get event details",
 		Args:  cobra.ExactArgs(2),
@@ -367,8 +433,19 @@ func newCalendarEventCmd(flags *rootFlags) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			var downloads []eventAttachmentDownload
+			if downloadAttachments {
+				downloads, err = downloadEventAttachments(cmd.Context(), account, e.Attachments, outDir)
+				if err != nil {
+					return err
+				}
+			}
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{"event": e})
+				out := map[string]any{"event": e}
+				if downloadAttachments {
+					out["downloaded"] = downloads
+				}
+				return outfmt.WriteJSON(os.Stdout, out)
 			}
 
 			u.Out().Printf("id\t%s", e.Id)
@@ -379,7 +456,7 @@ func newCalendarEventCmd(flags *rootFlags) *cobra.Command {
 				u.Out().Printf("location\t%s", e.Location)
 			}
 			if e.Description != "" {
-				u.Out().Printf("description\t%s", e.Description)
+				u.Out().Printf("description\t%s", simpleHTMLToMarkdown(e.Description))
 			}
 			if len(e.Attendees) > 0 {
 				addrs := make([]string, 0, len(e.Attendees))
@@ -398,9 +475,21 @@ func newCalendarEventCmd(flags *rootFlags) *cobra.Command {
 			if e.HtmlLink != "" {
 				u.Out().Printf("link\t%s", e.HtmlLink)
 			}
+			for _, a := range e.Attachments {
+				if a != nil {
+					u.Out().Printf("attachment\t%s\t%s", orEmpty(a.Title, a.FileId), a.FileUrl)
+				}
+			}
+			for _, d := range downloads {
+				u.Out().Printf("downloaded\t%s\t%s", d.Path, formatDriveSize(d.Size))
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&downloadAttachments, "download-attachments", false, "Download the event's Drive attachments")
+	cmd.Flags().StringVar(&outDir, "out", "", "Directory for --download-attachments (default: gogcli Drive downloads dir)")
+	return cmd
 }
 
 func newCalendarCreateCmd(flags *rootFlags) *cobra.Command {
@@ -408,9 +497,11 @@ func newCalendarCreateCmd(flags *rootFlags) *cobra.Command {
 	var from string
 	var to string
 	var description string
+	var descriptionMD string
 	var location string
 	var attendees string
 	var allDay bool
+	var attachDrive []string
 
 	cmd := &cobra.Command{
 		Use:   "create <calendarId>",
@@ -427,6 +518,15 @@ func newCalendarCreateCmd(flags *rootFlags) *cobra.Command {
 			if strings.TrimSpace(summary) == "" || strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
 				return usage("required: --summary, --from, --to")
 			}
+			if descriptionMD != "" {
+				if cmd.Flags().Changed("description") {
+					return usage("use either --description or --description-md")
+				}
+				description, err = readDescriptionMarkdown(descriptionMD)
+				if err != nil {
+					return err
+				}
+			}
 
 			svc, err := newCalendarService(cmd.Context(), account)
 			if err != nil {
@@ -441,8 +541,15 @@ func newCalendarCreateCmd(flags *rootFlags) *cobra.Command {
 				End:         buildEventDateTime(to, allDay),
 				Attendees:   buildAttendees(attendees),
 			}
+			attachments, err := driveEventAttachments(cmd.Context(), account, attachDrive)
+			if err != nil {
+				return err
+			}
+			if event.Attachments, err = mergeEventAttachments(nil, attachments); err != nil {
+				return err
+			}
 
-			created, err := svc.Events.Insert(calendarID, event).Do()
+			created, err := svc.Events.Insert(calendarID, event).SupportsAttachments(true).Do()
 			if err != nil {
 				return err
 			}
@@ -461,9 +568,11 @@ func newCalendarCreateCmd(flags *rootFlags) *cobra.Command {
 	cmd.Flags().StringVar(&from, "from", "", "Start time/date (required)")
 	cmd.Flags().StringVar(&to, "to", "", "End time/date (required)")
 	cmd.Flags().StringVar(&description, "description", "", "Event description")
+	cmd.Flags().StringVar(&descriptionMD, "description-md", "", "Event description from a Markdown file (- for stdin)")
 	cmd.Flags().StringVar(&location, "location", "", "Event location")
 	cmd.Flags().StringVar(&attendees, "attendees", "", "Attendees (comma-separated)")
 	cmd.Flags().BoolVar(&allDay, "all-day", false, "Create all-day event (use YYYY-MM-DD for from/to)")
+	cmd.Flags().StringSliceVar(&attachDrive, "attach-drive", nil, "Attach a Drive file by ID (repeatable)")
 	return cmd
 }
 
@@ -472,9 +581,11 @@ func newCalendarUpdateCmd(flags *rootFlags) *cobra.Command {
 	var from string
 	var to string
 	var description string
+	var descriptionMD string
 	var location string
 	var attendees string
 	var allDay bool
+	var attachDrive []string
 
 	cmd := &cobra.Command{
 		Use:   "update <calendarId> <eventId>",
@@ -488,6 +599,9 @@ func newCalendarUpdateCmd(flags *rootFlags) *cobra.Command {
 			}
 			calendarID := args[0]
 			eventID := args[1]
+			if descriptionMD != "" && cmd.Flags().Changed("description") {
+				return usage("use either --description or --description-md")
+			}
 
 			svc, err := newCalendarService(cmd.Context(), account)
 			if err != nil {
@@ -518,6 +632,13 @@ func newCalendarUpdateCmd(flags *rootFlags) *cobra.Command {
 				existing.Description = description
 				changed = true
 			}
+			if descriptionMD != "" {
+				existing.Description, err = readDescriptionMarkdown(descriptionMD)
+				if err != nil {
+					return err
+				}
+				changed = true
+			}
 			if cmd.Flags().Changed("location") {
 				existing.Location = location
 				changed = true
@@ -537,11 +658,22 @@ func newCalendarUpdateCmd(flags *rootFlags) *cobra.Command {
 				changed = true
 			}
 
+			if len(attachDrive) > 0 {
+				attachments, err := driveEventAttachments(cmd.Context(), account, attachDrive)
+				if err != nil {
+					return err
+				}
+				if existing.Attachments, err = mergeEventAttachments(existing.Attachments, attachments); err != nil {
+					return err
+				}
+				changed = true
+			}
+
 			if !changed {
 				return usage("no updates provided")
 			}
 
-			updated, err := svc.Events.Update(calendarID, eventID, existing).Do()
+			updated, err := svc.Events.Update(calendarID, eventID, existing).SupportsAttachments(true).Do()
 			if err != nil {
 				return err
 			}
@@ -560,9 +692,11 @@ func newCalendarUpdateCmd(flags *rootFlags) *cobra.Command {
 	cmd.Flags().StringVar(&from, "from", "", "Start time/date (RFC3339 or YYYY-MM-DD)")
 	cmd.Flags().StringVar(&to, "to", "", "End time/date (RFC3339 or YYYY-MM-DD)")
 	cmd.Flags().StringVar(&description, "description", "", "Event description")
+	cmd.Flags().StringVar(&descriptionMD, "description-md", "", "Event description from a Markdown file (- for stdin)")
 	cmd.Flags().StringVar(&location, "location", "", "Event location")
 	cmd.Flags().StringVar(&attendees, "attendees", "", "Attendees (comma-separated)")
 	cmd.Flags().BoolVar(&allDay, "all-day", false, "Treat from/to as all-day (YYYY-MM-DD)")
+	cmd.Flags().StringSliceVar(&attachDrive, "attach-drive", nil, "Attach a Drive file by ID (repeatable; keeps existing attachments)")
 	return cmd
 }
 
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// calendarMaxAttachments is the Calendar API limit per event.
+const calendarMaxAttachments = 25
+
+type eventAttachmentDownload struct {
+	FileID string `json:"fileId"`
+	Title  string `json:"title"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+}
+
+// driveEventAttachments looks up Drive files and turns them into event
+// attachments (Calendar only accepts Drive files, linked by URL).
+func driveEventAttachments(ctx context.Context, account string, fileIDs []string) ([]*calendar.EventAttachment, error) {
+	if len(fileIDs) == 0 {
+		return nil, nil
+	}
+	svc, err := newDriveService(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*calendar.EventAttachment, 0, len(fileIDs))
+	for _, id := range fileIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		f, err := svc.Files.Get(id).
+			SupportsAllDrives(true).
+			Fields("id, name, mimeType, webViewLink, iconLink").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("drive file %s: %w", id, err)
+		}
+		if f.WebViewLink == "" {
+			return nil, fmt.Errorf("drive file %s has no web link", id)
+		}
+		out = append(out, &calendar.EventAttachment{
+			FileId:   f.Id,
+			FileUrl:  f.WebViewLink,
+			Title:    f.Name,
+			MimeType: f.MimeType,
+			IconLink: f.IconLink,
+		})
+	}
+	return out, nil
+}
+
+// mergeEventAttachments appends add to existing, skipping files already
+// attached, and enforces the per-event limit.
+func mergeEventAttachments(existing, add []*calendar.EventAttachment) ([]*calendar.EventAttachment, error) {
+	seen := make(map[string]bool, len(existing))
+	for _, a := range existing {
+		if a == nil {
+			continue
+		}
+		for _, key := range []string{a.FileId, a.FileUrl} {
+			if key != "" {
+				seen[key] = true
+			}
+		}
+	}
+	out := existing
+	for _, a := range add {
+		if seen[a.FileId] || seen[a.FileUrl] {
+			continue
+		}
+		seen[a.FileId] = true
+		out = append(out, a)
+	}
+	if len(out) > calendarMaxAttachments {
+		return nil, usagef("events can have at most %d attachments (would have %d)", calendarMaxAttachments, len(out))
+	}
+	return out, nil
+}
+
+// downloadEventAttachments saves the event's Drive attachments to outDir
+// (default: the Drive downloads dir), exporting Google Docs formats.
+func downloadEventAttachments(ctx context.Context, account string, attachments []*calendar.EventAttachment, outDir string) ([]eventAttachmentDownload, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+	if outDir != "" {
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	svc, err := newDriveService(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]eventAttachmentDownload, 0, len(attachments))
+	for _, a := range attachments {
+		if a == nil || a.FileId == "" {
+			continue
+		}
+		meta, err := svc.Files.Get(a.FileId).
+			SupportsAllDrives(true).
+			Fields("id, name, mimeType").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", orEmpty(a.Title, a.FileId), err)
+		}
+		destPath, err := resolveDriveDownloadDestPath(meta, outDir)
+		if err != nil {
+			return nil, err
+		}
+		path, size, err := downloadDriveFile(ctx, svc, meta, destPath, "")
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", orEmpty(a.Title, a.FileId), err)
+		}
+		out = append(out, eventAttachmentDownload{FileID: a.FileId, Title: a.Title, Path: path, Size: size})
+	}
+	return out, nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestMergeEventAttachments(t *testing.T) {
+	existing := []*calendar.EventAttachment{{FileId: "f1", FileUrl: "https://drive/f1"}}
+	out, err := mergeEventAttachments(existing, []*calendar.EventAttachment{
+		{FileId: "f1", FileUrl: "https://drive/f1"},
+		{FileId: "f2", FileUrl: "https://drive/f2"},
+	})
+	if err != nil || len(out) != 2 || out[1].FileId != "f2" {
+		t.Fatalf("merge = %#v, %v", out, err)
+	}
+
+	many := make([]*calendar.EventAttachment, calendarMaxAttachments)
+	for i := range many {
+		many[i] = &calendar.EventAttachment{FileId: string(rune('a' + i))}
+	}
+	if _, err := mergeEventAttachments(many, []*calendar.EventAttachment{{FileId: "extra"}}); err == nil {
+		t.Fatalf("expected limit error")
+	}
+}
+
+func TestCalendarAttachDriveAndDownload(t *testing.T) {
+	origCal, origDrive, origDL := newCalendarService, newDriveService, driveDownload
+	t.Cleanup(func() { newCalendarService, newDriveService, driveDownload = origCal, origDrive, origDL })
+
+	var inserted calendar.Event
+	var supportsAttachments string
+	calSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/calendars/primary/events"):
+			supportsAttachments = r.URL.Query().Get("supportsAttachments")
+			_ = json.NewDecoder(r.Body).Decode(&inserted)
+			inserted.Id = "ev1"
+			_ = json.NewEncoder(w).Encode(inserted)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/calendars/primary/events/ev1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":      "ev1",
+				"summary": "Review",
+				"attachments": []map[string]any{
+					{"fileId": "doc1", "fileUrl": "https://docs.google.com/document/d/doc1", "title": "Agenda"},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer calSrv.Close()
+
+	driveSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/files/doc1") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":          "doc1",
+			"name":        "agenda.pdf",
+			"mimeType":    "application/pdf",
+			"webViewLink": "https://docs.google.com/document/d/doc1",
+		})
+	}))
+	defer driveSrv.Close()
+
+	calSvc, err := calendar.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(calSrv.Client()), option.WithEndpoint(calSrv.URL+"/"))
+	if err != nil {
+		t.Fatalf("calendar: %v", err)
+	}
+	driveSvc, err := drive.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(driveSrv.Client()), option.WithEndpoint(driveSrv.URL+"/"))
+	if err != nil {
+		t.Fatalf("drive: %v", err)
+	}
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return calSvc, nil }
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return driveSvc, nil }
+	driveDownload = func(context.Context, *drive.Service, string) (*http.Response, error) {
+		return &http.Response{Status: "200 OK", StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("pdf"))}, nil
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "calendar", "create", "primary",
+			"--summary", "Review", "--from", "2025-01-15T10:00:00Z", "--to", "2025-01-15T11:00:00Z",
+			"--attach-drive", "doc1"}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	})
+	if supportsAttachments != "true" || len(inserted.Attachments) != 1 || inserted.Attachments[0].Title != "agenda.pdf" {
+		t.Fatalf("inserted attachments = %#v (supportsAttachments=%q)", inserted.Attachments, supportsAttachments)
+	}
+
+	outDir := filepath.Join(t.TempDir(), "att")
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "calendar", "event", "primary", "ev1", "--download-attachments", "--out", outDir}); err != nil {
+			t.Fatalf("event: %v", err)
+		}
+	})
+	if !strings.Contains(out, "attachment\tAgenda\thttps://docs.google.com/document/d/doc1") {
+		t.Fatalf("missing attachment line:\n%s", out)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "doc1_agenda.pdf"))
+	if err != nil || string(data) != "pdf" {
+		t.Fatalf("downloaded = %q, %v", data, err)
+	}
+}
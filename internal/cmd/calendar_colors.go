@@ -5,7 +5,6 @@ import (
 	"os"
 	"sort"
 	"strconv"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/outfmt"
@@ -54,7 +53,7 @@ Calendar colors can be used when creating or updating calendars.`,
 			// Event colors
 			if len(colors.Event) > 0 {
 				fmt.Println("EVENT COLORS:")
-				tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+				tw, flush := tableWriter(cmd.Context())
 				fmt.Fprintln(tw, "ID\tBACKGROUND\tFOREGROUND")
 
 				// Sort color IDs numerically
@@ -71,14 +70,14 @@ Calendar colors can be used when creating or updating calendars.`,
 					c := colors.Event[id]
 					fmt.Fprintf(tw, "%s\t%s\t%s\n", id, c.Background, c.Foreground)
 				}
-				_ = tw.Flush()
+				flush()
 				fmt.Println()
 			}
 
 			// Calendar colors
 			if len(colors.Calendar) > 0 {
 				fmt.Println("CALENDAR COLORS:")
-				tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+				tw, flush := tableWriter(cmd.Context())
 				fmt.Fprintln(tw, "ID\tBACKGROUND\tFOREGROUND")
 
 				// Sort color IDs numerically
@@ -95,7 +94,7 @@ Calendar colors can be used when creating or updating calendars.`,
 					c := colors.Calendar[id]
 					fmt.Fprintf(tw, "%s\t%s\t%s\n", id, c.Background, c.Foreground)
 				}
-				_ = tw.Flush()
+				flush()
 			}
 
 			return nil
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"google.golang.org/api/calendar/v3"
+)
+
+// calendarColumnFlags are the table options shared by calendar listings;
+// unset flags fall back to the calendar section of config.json.
+type calendarColumnFlags struct {
+	SecondaryTZ string
+	WeekNumbers bool
+}
+
+func (f *calendarColumnFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.SecondaryTZ, "tz2", "", "Add a column with start times in this IANA timezone (default: calendar.secondaryTimezone; \"none\" disables)")
+	cmd.Flags().BoolVar(&f.WeekNumbers, "week-numbers", false, "Add an ISO week number column (default: calendar.weekNumbers)")
+}
+
+// calendarColumns renders the optional WEEK and secondary-timezone columns
+// around START/END in calendar tables.
+type calendarColumns struct {
+	weekNumbers bool
+	tz          *time.Location
+}
+
+func (f calendarColumnFlags) resolve(cmd *cobra.Command) (calendarColumns, error) {
+	cfg, err := config.ReadConfigFile()
+	if err != nil {
+		return calendarColumns{}, err
+	}
+	cols := calendarColumns{weekNumbers: cfg.Calendar.WeekNumbers}
+	if cmd.Flags().Changed("week-numbers") {
+		cols.weekNumbers = f.WeekNumbers
+	}
+	name := strings.TrimSpace(cfg.Calendar.SecondaryTimezone)
+	if cmd.Flags().Changed("tz2") {
+		name = strings.TrimSpace(f.SecondaryTZ)
+	}
+	if name != "" && !strings.EqualFold(name, "none") {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return calendarColumns{}, usagef("invalid secondary timezone %q: %v", name, err)
+		}
+		cols.tz = loc
+	}
+	return cols, nil
+}
+
+// header returns the tab-separated header: lead, [WEEK], START, END,
+// [START (<tz>)], SUMMARY.
+func (c calendarColumns) header(lead ...string) string {
+	cells := append([]string{}, lead...)
+	if c.weekNumbers {
+		cells = append(cells, "WEEK")
+	}
+	cells = append(cells, "START", "END")
+	if c.tz != nil {
+		cells = append(cells, fmt.Sprintf("START (%s)", c.tz))
+	}
+	return strings.Join(append(cells, "SUMMARY"), "\t")
+}
+
+// row renders e in the order of header.
+func (c calendarColumns) row(e *calendar.Event, lead ...string) string {
+	cells := append([]string{}, lead...)
+	start, hasStart := eventStartTime(e)
+	if c.weekNumbers {
+		week := ""
+		if hasStart {
+			y, w := start.ISOWeek()
+			week = fmt.Sprintf("%d-W%02d", y, w)
+		}
+		cells = append(cells, week)
+	}
+	cells = append(cells, eventStart(e), eventEnd(e))
+	if c.tz != nil {
+		secondary := ""
+		if hasStart && !isAllDayEvent(e) {
+			secondary = start.In(c.tz).Format("2006-01-02 15:04 MST")
+		}
+		cells = append(cells, secondary)
+	}
+	return strings.Join(append(cells, e.Summary), "\t")
+}
+
+// eventStartTime parses the event start; all-day events start at midnight
+// UTC of their date.
+func eventStartTime(e *calendar.Event) (time.Time, bool) {
+	if e == nil || e.Start == nil {
+		return time.Time{}, false
+	}
+	if e.Start.DateTime != "" {
+		t, err := time.Parse(time.RFC3339, e.Start.DateTime)
+		return t, err == nil
+	}
+	t, err := time.Parse("2006-01-02", e.Start.Date)
+	return t, err == nil
+}
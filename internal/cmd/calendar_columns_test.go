@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestCalendarColumns_ConfigAndFlags(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOG_CONFIG_DIR", dir)
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"calendar":{"secondaryTimezone":"Asia/Tokyo","weekNumbers":true}}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	resolve := func(args ...string) calendarColumns {
+		t.Helper()
+		var f calendarColumnFlags
+		cmd := &cobra.Command{Use: "x"}
+		f.addFlags(cmd)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		cols, err := f.resolve(cmd)
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		return cols
+	}
+
+	e := &calendar.Event{
+		Summary: "Standup",
+		Start:   &calendar.EventDateTime{DateTime: "2026-01-01T09:00:00-05:00"},
+		End:     &calendar.EventDateTime{DateTime: "2026-01-01T09:15:00-05:00"},
+	}
+	cols := resolve()
+	if got := cols.header("ID"); got != "ID\tWEEK\tSTART\tEND\tSTART (Asia/Tokyo)\tSUMMARY" {
+		t.Fatalf("header = %q", got)
+	}
+	if got := cols.row(e, "e1"); got != "e1\t2026-W01\t2026-01-01T09:00:00-05:00\t2026-01-01T09:15:00-05:00\t2026-01-01 23:00 JST\tStandup" {
+		t.Fatalf("row = %q", got)
+	}
+
+	cols = resolve("--tz2", "none", "--week-numbers=false")
+	if got := cols.row(e, "e1"); got != "e1\t2026-01-01T09:00:00-05:00\t2026-01-01T09:15:00-05:00\tStandup" {
+		t.Fatalf("plain row = %q", got)
+	}
+
+	allDay := &calendar.Event{Summary: "Holiday", Start: &calendar.EventDateTime{Date: "2027-01-01"}, End: &calendar.EventDateTime{Date: "2027-01-02"}}
+	if got := resolve().row(allDay); got != "2026-W53\t2027-01-01\t2027-01-02\t\tHoliday" {
+		t.Fatalf("all-day row = %q", got)
+	}
+
+	var f calendarColumnFlags
+	cmd := &cobra.Command{Use: "x"}
+	f.addFlags(cmd)
+	_ = cmd.ParseFlags([]string{"--tz2", "Mars/Olympus"})
+	if _, err := f.resolve(cmd); err == nil {
+		t.Fatalf("expected invalid timezone error")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -96,12 +95,12 @@ Default calendars: "primary"`,
 			}
 
 			fmt.Printf("CONFLICTS FOUND: %d\n\n", len(conflicts))
-			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			tw, flush := tableWriter(cmd.Context())
 			fmt.Fprintln(tw, "START\tEND\tCALENDARS")
 			for _, c := range conflicts {
 				fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Start, c.End, strings.Join(c.Calendars, ", "))
 			}
-			_ = tw.Flush()
+			flush()
 			return nil
 		},
 	}
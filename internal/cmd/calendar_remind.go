@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	defaultRemindBefore   = 10 * time.Minute
+	defaultRemindInterval = time.Minute
+)
+
+// calendarReminder is one upcoming event reported by calendar remind.
+type calendarReminder struct {
+	ID       string `json:"id"`
+	Summary  string `json:"summary"`
+	Start    string `json:"start"`
+	In       string `json:"in"`
+	Location string `json:"location,omitempty"`
+	Link     string `json:"htmlLink,omitempty"`
+}
+
+func newCalendarRemindCmd(flags *rootFlags) *cobra.Command {
+	var before time.Duration
+	var follow bool
+	var interval time.Duration
+	var notify desktopNotifyFlag
+
+	cmd := &cobra.Command{
+		Use:   "remind [calendarId]",
+		Short: "Report events starting soon (optionally as desktop notifications)",
+		Long: `List timed events on a calendar (default: primary) that start within
+--before. With --follow it keeps polling every --interval and reports each
+event once, as it comes within --before of its start. Events you declined
+are skipped. --notify-desktop raises a desktop notification per reminder.`,
+		Example: `  gog calendar remind --before 15m
+  gog calendar remind --follow --notify-desktop --notify-rule 'summary~standup|1:1'`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			calendarID := "primary"
+			if len(args) > 0 && strings.TrimSpace(args[0]) != "" {
+				calendarID = strings.TrimSpace(args[0])
+			}
+			if before <= 0 || interval <= 0 {
+				return usage("--before and --interval must be > 0")
+			}
+			notifier, err := notify.notifier(u)
+			if err != nil {
+				return err
+			}
+
+			svc, err := newCalendarService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			seen := map[string]bool{}
+			for {
+				reminders, err := upcomingReminders(ctx, svc, calendarID, time.Now(), before)
+				if err != nil {
+					if !follow {
+						return err
+					}
+					if ctx.Err() != nil {
+						return nil
+					}
+					u.Err().Printf("WARN: calendar remind: %v", err)
+				}
+				for _, r := range reminders {
+					if seen[r.ID+r.Start] {
+						continue
+					}
+					seen[r.ID+r.Start] = true
+					if outfmt.IsJSON(ctx) {
+						if err := outfmt.WriteNDJSON(os.Stdout, r); err != nil {
+							return err
+						}
+					} else {
+						u.Out().Printf("%s\t%s\t%s", r.In, formatDateTime(r.Start), r.Summary)
+					}
+					notifier.notify(ctx, orDash(r.Summary), "Starts in "+r.In, map[string]string{
+						"summary":  r.Summary,
+						"location": r.Location,
+						"calendar": calendarID,
+					})
+				}
+				if !follow {
+					if len(reminders) == 0 && !outfmt.IsJSON(ctx) {
+						u.Err().Println("No events starting soon")
+					}
+					return nil
+				}
+				if err := watchDaemonSleep(ctx, interval); err != nil {
+					return nil
+				}
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&before, "before", defaultRemindBefore, "Report events starting within this window")
+	cmd.Flags().BoolVar(&follow, "follow", false, "Keep polling and report each event once")
+	cmd.Flags().DurationVar(&interval, "interval", defaultRemindInterval, "Poll interval with --follow")
+	notify.addFlags(cmd, "summary,location,calendar")
+	return cmd
+}
+
+// upcomingReminders lists timed, not-declined events starting in
+// [now, now+before].
+func upcomingReminders(ctx context.Context, svc *calendar.Service, calendarID string, now time.Time, before time.Duration) ([]calendarReminder, error) {
+	resp, err := svc.Events.List(calendarID).
+		TimeMin(now.Format(time.RFC3339)).
+		TimeMax(now.Add(before).Format(time.RFC3339)).
+		SingleEvents(true).
+		OrderBy("startTime").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	var out []calendarReminder
+	for _, e := range resp.Items {
+		if e == nil || e.Start == nil || e.Start.DateTime == "" || selfDeclined(e) {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339, e.Start.DateTime)
+		if err != nil || start.Before(now) {
+			continue
+		}
+		out = append(out, calendarReminder{
+			ID:       e.Id,
+			Summary:  e.Summary,
+			Start:    e.Start.DateTime,
+			In:       formatStatusDuration(start.Sub(now)),
+			Location: e.Location,
+			Link:     e.HtmlLink,
+		})
+	}
+	return out, nil
+}
+
+func selfDeclined(e *calendar.Event) bool {
+	for _, a := range e.Attendees {
+		if a != nil && a.Self && a.ResponseStatus == "declined" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/calendar/v3"
+)
+
+// attendanceResponses are the attendee responseStatus values, in report order.
+var attendanceResponses = []string{"accepted", "tentative", "declined", "needsAction"}
+
+type attendanceAttendee struct {
+	Email    string `json:"email"`
+	Name     string `json:"name,omitempty"`
+	Response string `json:"response"`
+	Optional bool   `json:"optional,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type attendanceEvent struct {
+	ID        string               `json:"id"`
+	Summary   string               `json:"summary"`
+	Start     string               `json:"start"`
+	Counts    map[string]int       `json:"counts"`
+	Attendees []attendanceAttendee `json:"attendees"`
+}
+
+func newCalendarReportCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Reports across calendar events",
+	}
+	cmd.AddCommand(newCalendarReportAttendanceCmd(flags))
+	return cmd
+}
+
+func newCalendarReportAttendanceCmd(flags *rootFlags) *cobra.Command {
+	var eventIDs []string
+	var query string
+	var from string
+	var to string
+	var calendarID string
+	var max int64
+	var byAttendee bool
+	var asCSV bool
+
+	cmd := &cobra.Command{
+		Use:   "attendance",
+		Short: "Summarize attendee responses (RSVPs) for events",
+		Long: `Summarize attendee response status for one or more events: --event (repeatable)
+or every event matching --query (default range 30 days ago to 90 days ahead).
+
+The default report has one row per event with accepted/tentative/declined/
+no-reply counts and who has not replied; --attendees lists every attendee
+instead. --csv writes the same rows as CSV. Resources (rooms) are skipped.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			query = strings.TrimSpace(query)
+			if (len(eventIDs) == 0) == (query == "") {
+				return usage("specify --event or --query")
+			}
+			if query == "" && (cmd.Flags().Changed("from") || cmd.Flags().Changed("to")) {
+				return usage("--from/--to only apply to --query")
+			}
+
+			svc, err := newCalendarService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			var events []*calendar.Event
+			if query != "" {
+				now := time.Now().UTC()
+				if strings.TrimSpace(from) == "" {
+					from = now.Add(-30 * 24 * time.Hour).Format(time.RFC3339)
+				}
+				if strings.TrimSpace(to) == "" {
+					to = now.Add(90 * 24 * time.Hour).Format(time.RFC3339)
+				}
+				resp, err := svc.Events.List(calendarID).Q(query).TimeMin(from).TimeMax(to).
+					MaxResults(max).SingleEvents(true).OrderBy("startTime").Context(cmd.Context()).Do()
+				if err != nil {
+					return err
+				}
+				events = resp.Items
+			} else {
+				for _, id := range eventIDs {
+					e, err := svc.Events.Get(calendarID, id).Context(cmd.Context()).Do()
+					if err != nil {
+						return fmt.Errorf("%s: %w", id, err)
+					}
+					events = append(events, e)
+				}
+			}
+
+			report := make([]attendanceEvent, 0, len(events))
+			for _, e := range events {
+				report = append(report, summarizeAttendance(e))
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"events": report})
+			}
+			if len(report) == 0 {
+				u.Err().Println("No events found")
+				return nil
+			}
+
+			header, rows := attendanceRows(report, byAttendee)
+			if asCSV {
+				w := csv.NewWriter(os.Stdout)
+				_ = w.Write(header)
+				_ = w.WriteAll(rows)
+				return w.Error()
+			}
+			tw, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(tw, strings.Join(header, "\t"))
+			for _, r := range rows {
+				for i := range r {
+					r[i] = sanitizeTab(r[i])
+				}
+				fmt.Fprintln(tw, strings.Join(r, "\t"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&eventIDs, "event", nil, "Event ID (repeatable, comma-separated)")
+	cmd.Flags().StringVar(&query, "query", "", "Report on events matching this text query")
+	cmd.Flags().StringVar(&from, "from", "", "Start time for --query (RFC3339; default: 30 days ago)")
+	cmd.Flags().StringVar(&to, "to", "", "End time for --query (RFC3339; default: 90 days from now)")
+	cmd.Flags().StringVar(&calendarID, "calendar", "primary", "Calendar ID")
+	cmd.Flags().Int64Var(&max, "max", 25, "Max events for --query")
+	cmd.Flags().BoolVar(&byAttendee, "attendees", false, "One row per attendee instead of per event")
+	cmd.Flags().BoolVar(&asCSV, "csv", false, "Write CSV instead of a table")
+	return cmd
+}
+
+func summarizeAttendance(e *calendar.Event) attendanceEvent {
+	out := attendanceEvent{
+		ID:        e.Id,
+		Summary:   e.Summary,
+		Start:     eventStart(e),
+		Counts:    map[string]int{},
+		Attendees: []attendanceAttendee{},
+	}
+	for _, r := range attendanceResponses {
+		out.Counts[r] = 0
+	}
+	for _, a := range e.Attendees {
+		if a == nil || a.Resource {
+			continue
+		}
+		resp := a.ResponseStatus
+		if resp == "" {
+			resp = "needsAction"
+		}
+		out.Counts[resp]++
+		out.Attendees = append(out.Attendees, attendanceAttendee{
+			Email:    a.Email,
+			Name:     a.DisplayName,
+			Response: resp,
+			Optional: a.Optional,
+			Comment:  a.Comment,
+		})
+	}
+	order := make(map[string]int, len(attendanceResponses))
+	for i, r := range attendanceResponses {
+		order[r] = i
+	}
+	sort.SliceStable(out.Attendees, func(i, j int) bool {
+		return order[out.Attendees[i].Response] < order[out.Attendees[j].Response]
+	})
+	return out
+}
+
+func attendanceRows(report []attendanceEvent, byAttendee bool) ([]string, [][]string) {
+	var rows [][]string
+	if byAttendee {
+		for _, e := range report {
+			for _, a := range e.Attendees {
+				optional := ""
+				if a.Optional {
+					optional = "optional"
+				}
+				rows = append(rows, []string{e.ID, e.Start, e.Summary, a.Email, a.Name, a.Response, optional})
+			}
+		}
+		return []string{"EVENT", "START", "SUMMARY", "EMAIL", "NAME", "RESPONSE", "OPTIONAL"}, rows
+	}
+	for _, e := range report {
+		var pending []string
+		for _, a := range e.Attendees {
+			if a.Response == "needsAction" {
+				pending = append(pending, a.Email)
+			}
+		}
+		rows = append(rows, []string{
+			e.ID, e.Start, e.Summary,
+			fmt.Sprint(e.Counts["accepted"]),
+			fmt.Sprint(e.Counts["tentative"]),
+			fmt.Sprint(e.Counts["declined"]),
+			fmt.Sprint(e.Counts["needsAction"]),
+			strings.Join(pending, ","),
+		})
+	}
+	return []string{"EVENT", "START", "SUMMARY", "ACCEPTED", "TENTATIVE", "DECLINED", "NO_REPLY", "AWAITING"}, rows
+}
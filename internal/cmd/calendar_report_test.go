@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+func TestSummarizeAttendance(t *testing.T) {
+	rep := summarizeAttendance(&calendar.Event{
+		Id:      "e1",
+		Summary: "Planning",
+		Start:   &calendar.EventDateTime{DateTime: "2025-01-15T10:00:00Z"},
+		Attendees: []*calendar.EventAttendee{
+			{Email: "a@x.com", ResponseStatus: "needsAction"},
+			{Email: "b@x.com", ResponseStatus: "accepted"},
+			{Email: "room@resource.calendar.google.com", Resource: true, ResponseStatus: "accepted"},
+			{Email: "c@x.com", ResponseStatus: "declined", Optional: true},
+			{Email: "d@x.com"},
+		},
+	})
+	if rep.Counts["accepted"] != 1 || rep.Counts["declined"] != 1 || rep.Counts["needsAction"] != 2 || rep.Counts["tentative"] != 0 {
+		t.Fatalf("counts = %v", rep.Counts)
+	}
+	if len(rep.Attendees) != 4 || rep.Attendees[0].Email != "b@x.com" || rep.Attendees[3].Email != "d@x.com" {
+		t.Fatalf("attendees = %#v", rep.Attendees)
+	}
+
+	header, rows := attendanceRows([]attendanceEvent{rep}, false)
+	if header[7] != "AWAITING" || rows[0][7] != "a@x.com,d@x.com" || rows[0][6] != "2" {
+		t.Fatalf("rows = %v", rows)
+	}
+}
+
+func TestCalendarReportAttendance_QueryCSV(t *testing.T) {
+	origNew := newCalendarService
+	t.Cleanup(func() { newCalendarService = origNew })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/calendars/primary/events") {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("q") != "standup" {
+			t.Errorf("q = %q", r.URL.Query().Get("q"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"items": []map[string]any{{
+				"id":      "e1",
+				"summary": "Standup, daily",
+				"start":   map[string]any{"dateTime": "2025-01-15T10:00:00Z"},
+				"attendees": []map[string]any{
+					{"email": "a@x.com", "responseStatus": "tentative", "displayName": "Ann"},
+					{"email": "b@x.com", "responseStatus": "needsAction"},
+				},
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := calendar.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "calendar", "report", "attendance", "--query", "standup", "--attendees", "--csv"}); err != nil {
+			t.Fatalf("report: %v", err)
+		}
+	})
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("csv: %v\n%s", err, out)
+	}
+	if len(records) != 3 || records[1][2] != "Standup, daily" || records[1][4] != "Ann" || records[2][5] != "needsAction" {
+		t.Fatalf("records = %v", records)
+	}
+
+	if err := Execute([]string{"--account", "a@b.com", "calendar", "report", "attendance"}); err == nil {
+		t.Fatalf("expected usage error without --event/--query")
+	}
+}
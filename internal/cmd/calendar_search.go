@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -17,6 +16,7 @@ func newCalendarSearchCmd(flags *rootFlags) *cobra.Command {
 	var to string
 	var calendarID string
 	var max int64
+	var columns calendarColumnFlags
 
 	cmd := &cobra.Command{
 		Use:   "search <query>",
@@ -49,6 +49,11 @@ Default time range is 30 days ago to 90 days from now.`,
 				to = ninetyDaysLater.Format(time.RFC3339)
 			}
 
+			cols, err := columns.resolve(cmd)
+			if err != nil {
+				return err
+			}
+
 			svc, err := newCalendarService(cmd.Context(), account)
 			if err != nil {
 				return err
@@ -79,12 +84,12 @@ Default time range is 30 days ago to 90 days from now.`,
 				return nil
 			}
 
-			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
-			fmt.Fprintln(tw, "ID\tSTART\tEND\tSUMMARY")
+			tw, flush := tableWriter(cmd.Context())
+			fmt.Fprintln(tw, cols.header("ID"))
 			for _, e := range resp.Items {
-				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Id, eventStart(e), eventEnd(e), e.Summary)
+				fmt.Fprintln(tw, cols.row(e, e.Id))
 			}
-			_ = tw.Flush()
+			flush()
 			return nil
 		},
 	}
@@ -93,6 +98,7 @@ Default time range is 30 days ago to 90 days from now.`,
 	cmd.Flags().StringVar(&to, "to", "", "End time (RFC3339; default: 90 days from now)")
 	cmd.Flags().StringVar(&calendarID, "calendar", "primary", "Calendar ID")
 	cmd.Flags().Int64Var(&max, "max", 25, "Max results")
+	columns.addFlags(cmd)
 
 	return cmd
 }
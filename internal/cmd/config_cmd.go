@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect local configuration",
+	}
+	cmd.AddCommand(newConfigPathsCmd())
+	return cmd
+}
+
+type configPath struct {
+	Name string
+	Fn   func() (string, error)
+}
+
+func configPaths() []configPath {
+	return []configPath{
+		{"config", config.Dir},
+		{"config_file", config.ConfigFilePath},
+		{"credentials", config.ClientCredentialsPath},
+		{"keyring", config.KeyringDir},
+		{"secure_settings", config.SecureSettingsPath},
+		{"gmail_allowlist", config.GmailAllowlistPath},
+		{"policy", config.PolicyPath},
+		{"audit_log", config.AuditLogPath},
+		{"state", config.StateDir},
+		{"gmail_watch", config.GmailWatchDir},
+		{"gmail_history", config.GmailHistoryDir},
+		{"outbox", config.OutboxDir},
+		{"followups", config.FollowupsDir},
+		{"sent_log", config.SentLogPath},
+		{"shell_history", config.ShellHistoryPath},
+		{"usage_log", config.UsageLogPath},
+		{"quarantine", config.QuarantineDir},
+		{"cache", config.CacheDir},
+		{"gmail_cache", config.GmailCacheDir},
+		{"http_cache", config.HTTPCacheDir},
+		{"drive_downloads", config.DriveDownloadsDir},
+		{"gmail_attachments", config.GmailAttachmentsDir},
+	}
+}
+
+func newConfigPathsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "paths",
+		Short: "Print where config, state, and cache files live",
+		Long: `Print where config, state, and cache files live.
+
+Override the base directories with GOG_CONFIG_DIR, GOG_STATE_DIR, and GOG_CACHE_DIR.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			paths := configPaths()
+			resolved := make(map[string]string, len(paths))
+			for _, p := range paths {
+				v, err := p.Fn()
+				if err != nil {
+					return err
+				}
+				resolved[p.Name] = v
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"paths": resolved})
+			}
+			u := ui.FromContext(cmd.Context())
+			for _, p := range paths {
+				u.Out().Printf("%s\t%s", p.Name, resolved[p.Name])
+			}
+			return nil
+		},
+	}
+}
@@ -1,41 +1,42 @@
 package cmd
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/ui"
-	"golang.org/x/term"
 )
 
+// confirmDestructive asks before a destructive action. --yes/--force skips
+// the prompt; without a terminal (or with --no-input) it refuses instead.
 func confirmDestructive(cmd *cobra.Command, flags *rootFlags, action string) error {
 	if flags.Force {
 		return nil
 	}
 
-	// Never prompt in non-interactive contexts.
-	if flags.NoInput || !term.IsTerminal(int(os.Stdin.Fd())) {
-		return usagef("refusing to %s without --force (non-interactive)", action)
+	var u *ui.UI
+	if ctx := cmd.Context(); ctx != nil {
+		u = ui.FromContext(ctx)
+	}
+	if u == nil {
+		var err error
+		if u, err = ui.New(ui.Options{}); err != nil {
+			return err
+		}
 	}
 
-	prompt := fmt.Sprintf("Proceed to %s? [y/N]: ", action)
-	if u := ui.FromContext(cmd.Context()); u != nil {
-		u.Err().Println(prompt)
-	} else {
-		_, _ = fmt.Fprintln(os.Stderr, prompt)
+	// Never prompt in non-interactive contexts.
+	if flags.NoInput || !u.Interactive() {
+		return usagef("refusing to %s without --yes/--force (non-interactive)", action)
 	}
 
-	line, readErr := bufio.NewReader(os.Stdin).ReadString('\n')
-	if readErr != nil && !errors.Is(readErr, os.ErrClosed) {
-		return readErr
+	ok, err := u.Confirm(fmt.Sprintf("Proceed to %s?", action))
+	if err != nil {
+		return err
 	}
-	ans := strings.TrimSpace(strings.ToLower(line))
-	if ans == "y" || ans == "yes" {
-		return nil
+	if !ok {
+		return &ExitError{Code: 1, Err: errors.New("cancelled")}
 	}
-	return &ExitError{Code: 1, Err: errors.New("cancelled")}
+	return nil
 }
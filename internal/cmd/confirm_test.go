@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"io"
 	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/ui"
 )
 
 func TestConfirmDestructive(t *testing.T) {
@@ -20,4 +23,17 @@ func TestConfirmDestructive(t *testing.T) {
 	if err == nil || !strings.Contains(err.Error(), "refusing") {
 		t.Fatalf("expected refusal, got: %v", err)
 	}
+
+	// Interactive: the answer decides.
+	for answer, ok := range map[string]bool{"y\n": true, "no\n": false} {
+		u, uiErr := ui.New(ui.Options{Stdin: strings.NewReader(answer), Stdout: io.Discard, Stderr: io.Discard})
+		if uiErr != nil {
+			t.Fatalf("ui.New: %v", uiErr)
+		}
+		cmd.SetContext(ui.WithUI(context.Background(), u))
+		err := confirmDestructive(cmd, &rootFlags{}, "delete thing")
+		if (err == nil) != ok {
+			t.Fatalf("answer %q: %v", answer, err)
+		}
+	}
 }
@@ -22,8 +22,11 @@ func newContactsCmd(flags *rootFlags) *cobra.Command {
 	cmd.AddCommand(newContactsCreateCmd(flags))
 	cmd.AddCommand(newContactsUpdateCmd(flags))
 	cmd.AddCommand(newContactsDeleteCmd(flags))
+	cmd.AddCommand(newContactsExportCmd(flags))
+	cmd.AddCommand(newContactsImportCmd(flags))
 	cmd.AddCommand(newContactsDirectoryCmd(flags))
 	cmd.AddCommand(newContactsOtherCmd(flags))
+	cmd.AddCommand(newContactsGraphCmd(flags))
 	return cmd
 }
 
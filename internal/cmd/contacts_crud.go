@@ -16,6 +16,7 @@ const contactsReadMask = "names,emailAddresses,phoneNumbers"
 func newContactsListCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -33,11 +34,18 @@ func newContactsListCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			resp, err := svc.People.Connections.List("people/me").
-				PersonFields(contactsReadMask).
-				PageSize(max).
-				PageToken(page).
-				Do()
+			contacts, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*people.Person, string, error) {
+				resp, err := svc.People.Connections.List("people/me").
+					PersonFields(contactsReadMask).
+					PageSize(max).
+					PageToken(pageToken).
+					Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Connections, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(contacts))
 			if err != nil {
 				return err
 			}
@@ -48,8 +56,8 @@ func newContactsListCmd(flags *rootFlags) *cobra.Command {
 					Email    string `json:"email,omitempty"`
 					Phone    string `json:"phone,omitempty"`
 				}
-				items := make([]item, 0, len(resp.Connections))
-				for _, p := range resp.Connections {
+				items := make([]item, 0, len(contacts))
+				for _, p := range contacts {
 					if p == nil {
 						continue
 					}
@@ -60,12 +68,15 @@ func newContactsListCmd(flags *rootFlags) *cobra.Command {
 						Phone:    primaryPhone(p),
 					})
 				}
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
 					"contacts":      items,
-					"nextPageToken": resp.NextPageToken,
-				})
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
-			if len(resp.Connections) == 0 {
+			if len(contacts) == 0 {
 				u.Err().Println("No contacts")
 				return nil
 			}
@@ -73,7 +84,7 @@ func newContactsListCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "RESOURCE\tNAME\tEMAIL\tPHONE")
-			for _, p := range resp.Connections {
+			for _, p := range contacts {
 				if p == nil {
 					continue
 				}
@@ -85,13 +96,14 @@ func newContactsListCmd(flags *rootFlags) *cobra.Command {
 				)
 			}
 
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 100, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 	return cmd
 }
 
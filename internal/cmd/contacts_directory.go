@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/people/v1"
 )
 
 const (
@@ -30,6 +31,7 @@ func newContactsDirectoryCmd(flags *rootFlags) *cobra.Command {
 func newContactsDirectoryListCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -47,16 +49,22 @@ func newContactsDirectoryListCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			ctx, cancel := context.WithTimeout(cmd.Context(), directoryRequestTimeout)
-			defer cancel()
-
-			resp, err := svc.People.ListDirectoryPeople().
-				Sources("DIRECTORY_SOURCE_TYPE_DOMAIN_PROFILE").
-				ReadMask(directoryReadMask).
-				PageSize(max).
-				PageToken(page).
-				Context(ctx).
-				Do()
+			contacts, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*people.Person, string, error) {
+				ctx, cancel := context.WithTimeout(cmd.Context(), directoryRequestTimeout)
+				defer cancel()
+				resp, err := svc.People.ListDirectoryPeople().
+					Sources("DIRECTORY_SOURCE_TYPE_DOMAIN_PROFILE").
+					ReadMask(directoryReadMask).
+					PageSize(max).
+					PageToken(pageToken).
+					Context(ctx).
+					Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.People, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(contacts))
 			if err != nil {
 				return err
 			}
@@ -66,8 +74,8 @@ func newContactsDirectoryListCmd(flags *rootFlags) *cobra.Command {
 					Name     string `json:"name,omitempty"`
 					Email    string `json:"email,omitempty"`
 				}
-				items := make([]item, 0, len(resp.People))
-				for _, p := range resp.People {
+				items := make([]item, 0, len(contacts))
+				for _, p := range contacts {
 					if p == nil {
 						continue
 					}
@@ -77,13 +85,16 @@ func newContactsDirectoryListCmd(flags *rootFlags) *cobra.Command {
 						Email:    primaryEmail(p),
 					})
 				}
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
 					"people":        items,
-					"nextPageToken": resp.NextPageToken,
-				})
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
 
-			if len(resp.People) == 0 {
+			if len(contacts) == 0 {
 				u.Err().Println("No results")
 				return nil
 			}
@@ -91,7 +102,7 @@ func newContactsDirectoryListCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "RESOURCE\tNAME\tEMAIL")
-			for _, p := range resp.People {
+			for _, p := range contacts {
 				if p == nil {
 					continue
 				}
@@ -101,19 +112,21 @@ func newContactsDirectoryListCmd(flags *rootFlags) *cobra.Command {
 					sanitizeTab(primaryEmail(p)),
 				)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 50, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 	return cmd
 }
 
 func newContactsDirectorySearchCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "search <query>",
@@ -132,17 +145,23 @@ func newContactsDirectorySearchCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			ctx, cancel := context.WithTimeout(cmd.Context(), directoryRequestTimeout)
-			defer cancel()
-
-			resp, err := svc.People.SearchDirectoryPeople().
-				Query(query).
-				Sources("DIRECTORY_SOURCE_TYPE_DOMAIN_PROFILE").
-				ReadMask(directoryReadMask).
-				PageSize(max).
-				PageToken(page).
-				Context(ctx).
-				Do()
+			contacts, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*people.Person, string, error) {
+				ctx, cancel := context.WithTimeout(cmd.Context(), directoryRequestTimeout)
+				defer cancel()
+				resp, err := svc.People.SearchDirectoryPeople().
+					Query(query).
+					Sources("DIRECTORY_SOURCE_TYPE_DOMAIN_PROFILE").
+					ReadMask(directoryReadMask).
+					PageSize(max).
+					PageToken(pageToken).
+					Context(ctx).
+					Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.People, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(contacts))
 			if err != nil {
 				return err
 			}
@@ -152,8 +171,8 @@ func newContactsDirectorySearchCmd(flags *rootFlags) *cobra.Command {
 					Name     string `json:"name,omitempty"`
 					Email    string `json:"email,omitempty"`
 				}
-				items := make([]item, 0, len(resp.People))
-				for _, p := range resp.People {
+				items := make([]item, 0, len(contacts))
+				for _, p := range contacts {
 					if p == nil {
 						continue
 					}
@@ -163,13 +182,16 @@ func newContactsDirectorySearchCmd(flags *rootFlags) *cobra.Command {
 						Email:    primaryEmail(p),
 					})
 				}
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
 					"people":        items,
-					"nextPageToken": resp.NextPageToken,
-				})
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
 
-			if len(resp.People) == 0 {
+			if len(contacts) == 0 {
 				u.Err().Println("No results")
 				return nil
 			}
@@ -177,7 +199,7 @@ func newContactsDirectorySearchCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "RESOURCE\tNAME\tEMAIL")
-			for _, p := range resp.People {
+			for _, p := range contacts {
 				if p == nil {
 					continue
 				}
@@ -187,13 +209,14 @@ func newContactsDirectorySearchCmd(flags *rootFlags) *cobra.Command {
 					sanitizeTab(primaryEmail(p)),
 				)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 50, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 	return cmd
 }
 
@@ -210,6 +233,7 @@ func newContactsOtherCmd(flags *rootFlags) *cobra.Command {
 func newContactsOtherListCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -227,11 +251,18 @@ func newContactsOtherListCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			resp, err := svc.OtherContacts.List().
-				ReadMask(contactsReadMask).
-				PageSize(max).
-				PageToken(page).
-				Do()
+			contacts, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*people.Person, string, error) {
+				resp, err := svc.OtherContacts.List().
+					ReadMask(contactsReadMask).
+					PageSize(max).
+					PageToken(pageToken).
+					Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.OtherContacts, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(contacts))
 			if err != nil {
 				return err
 			}
@@ -242,8 +273,8 @@ func newContactsOtherListCmd(flags *rootFlags) *cobra.Command {
 					Email    string `json:"email,omitempty"`
 					Phone    string `json:"phone,omitempty"`
 				}
-				items := make([]item, 0, len(resp.OtherContacts))
-				for _, p := range resp.OtherContacts {
+				items := make([]item, 0, len(contacts))
+				for _, p := range contacts {
 					if p == nil {
 						continue
 					}
@@ -254,13 +285,16 @@ func newContactsOtherListCmd(flags *rootFlags) *cobra.Command {
 						Phone:    primaryPhone(p),
 					})
 				}
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
 					"contacts":      items,
-					"nextPageToken": resp.NextPageToken,
-				})
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
 
-			if len(resp.OtherContacts) == 0 {
+			if len(contacts) == 0 {
 				u.Err().Println("No results")
 				return nil
 			}
@@ -268,7 +302,7 @@ func newContactsOtherListCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "RESOURCE\tNAME\tEMAIL\tPHONE")
-			for _, p := range resp.OtherContacts {
+			for _, p := range contacts {
 				if p == nil {
 					continue
 				}
@@ -279,13 +313,14 @@ func newContactsOtherListCmd(flags *rootFlags) *cobra.Command {
 					sanitizeTab(primaryPhone(p)),
 				)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 100, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 	return cmd
 }
 
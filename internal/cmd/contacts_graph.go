@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"google.golang.org/api/gmail/v1"
+)
+
+// contactGraphNode is one address seen in From/To/Cc.
+type contactGraphNode struct {
+	Email    string `json:"email"`
+	Name     string `json:"name,omitempty"`
+	Self     bool   `json:"self,omitempty"`
+	Sent     int    `json:"sent"`
+	Received int    `json:"received"`
+	Last     string `json:"last,omitempty"`
+	last     time.Time
+}
+
+// contactGraphEdge counts messages from one address to another.
+type contactGraphEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Messages int    `json:"messages"`
+	Last     string `json:"last,omitempty"`
+	last     time.Time
+}
+
+type contactGraph struct {
+	Account  string              `json:"account"`
+	Since    string              `json:"since"`
+	Messages int                 `json:"messages"`
+	Nodes    []*contactGraphNode `json:"nodes"`
+	Edges    []*contactGraphEdge `json:"edges"`
+}
+
+func newContactsGraphCmd(flags *rootFlags) *cobra.Command {
+	var since, format, query string
+	var max int64
+	var minMessages int
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export who emails whom (Sent/Inbox metadata) as DOT or JSON",
+		Long: `Build a communication graph from the From/To/Cc headers of your Sent and
+Inbox messages in a time window: one node per address (messages sent and
+received, last seen) and one edge per sender → recipient pair (message
+count, most recent).
+
+Only message metadata is read. --format dot renders with Graphviz, e.g.
+gog contacts graph | dot -Tsvg > graph.svg.`,
+		Example: `  gog contacts graph --since 90d | dot -Tsvg > graph.svg
+  gog contacts graph --since 30d --format json --min-messages 3`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			format = strings.ToLower(strings.TrimSpace(format))
+			if outfmt.IsJSON(cmd.Context()) {
+				format = "json"
+			}
+			if format != "dot" && format != "json" {
+				return usagef("invalid --format %q (expected dot|json)", format)
+			}
+			window, err := parseFollowupDelay(since)
+			if err != nil {
+				return usagef("invalid --since %q (use e.g. 30d, 12w)", since)
+			}
+			if minMessages < 1 {
+				return usagef("invalid --min-messages %d (must be >= 1)", minMessages)
+			}
+			cutoff := time.Now().Add(-window)
+			q := fmt.Sprintf("{in:sent in:inbox} after:%s", cutoff.Format("2006/01/02"))
+			if extra := strings.TrimSpace(query); extra != "" {
+				q += " " + extra
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			ids, err := listGraphMessageIDs(cmd.Context(), svc, q, max)
+			if err != nil {
+				return err
+			}
+			msgs, err := fetchGraphHeaders(cmd.Context(), svc, ids)
+			if err != nil {
+				return err
+			}
+			g := buildContactGraph(account, msgs, minMessages)
+			g.Since = cutoff.UTC().Format(time.RFC3339)
+
+			if format == "json" {
+				return outfmt.WriteJSON(os.Stdout, g)
+			}
+			return writeContactGraphDOT(os.Stdout, g)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "90d", "Time window (e.g. 30d, 12w)")
+	cmd.Flags().StringVar(&format, "format", "dot", "Output format: dot|json")
+	cmd.Flags().StringVar(&query, "query", "", "Extra Gmail search terms to narrow the messages")
+	cmd.Flags().Int64Var(&max, "max", 2000, "Max messages to read (0 = all)")
+	cmd.Flags().IntVar(&minMessages, "min-messages", 1, "Drop edges with fewer messages than this")
+	return cmd
+}
+
+func listGraphMessageIDs(ctx context.Context, svc *gmail.Service, q string, max int64) ([]string, error) {
+	var ids []string
+	pageToken := ""
+	for {
+		call := svc.Users.Messages.List("me").Q(q).MaxResults(500).Fields("messages(id),nextPageToken").Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range resp.Messages {
+			if m == nil || m.Id == "" {
+				continue
+			}
+			ids = append(ids, m.Id)
+			if max > 0 && int64(len(ids)) >= max {
+				return ids, nil
+			}
+		}
+		if resp.NextPageToken == "" {
+			return ids, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// fetchGraphHeaders reads the address headers of ids.
+func fetchGraphHeaders(ctx context.Context, svc *gmail.Service, ids []string) ([]*gmail.Message, error) {
+	return fetchMessagesBounded(ctx, ids, func(id string) *gmail.UsersMessagesGetCall {
+		return svc.Users.Messages.Get("me", id).
+			Format("metadata").
+			MetadataHeaders("From", "To", "Cc").
+			Fields("id,internalDate,payload/headers")
+	})
+}
+
+// fetchMessagesBounded runs get for each of ids with the same bounded
+// parallelism as fetchMessageDetails, keeping the order of ids.
+func fetchMessagesBounded(ctx context.Context, ids []string, get func(id string) *gmail.UsersMessagesGetCall) ([]*gmail.Message, error) {
+	const maxConcurrency = 10
+	sem := make(chan struct{}, maxConcurrency)
+	out := make([]*gmail.Message, len(ids))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for i, id := range ids {
+		wg.Add(1)
+		go func(idx int, id string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			msg, err := get(id).Context(ctx).Do()
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			out[idx] = msg
+		}(i, id)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func buildContactGraph(account string, msgs []*gmail.Message, minMessages int) *contactGraph {
+	self := strings.ToLower(strings.TrimSpace(account))
+	g := &contactGraph{Account: self}
+	nodes := map[string]*contactGraphNode{}
+	edges := map[[2]string]*contactGraphEdge{}
+	node := func(a string, name string) *contactGraphNode {
+		n, ok := nodes[a]
+		if !ok {
+			n = &contactGraphNode{Email: a, Self: a == self}
+			nodes[a] = n
+		}
+		if n.Name == "" && name != "" && !strings.EqualFold(name, a) {
+			n.Name = name
+		}
+		return n
+	}
+	seen := func(n *contactGraphNode, at time.Time) {
+		if at.After(n.last) {
+			n.last = at
+		}
+	}
+
+	for _, m := range msgs {
+		if m == nil {
+			continue
+		}
+		from := parseAddressHeader(headerValue(m.Payload, "From"))
+		if len(from) == 0 {
+			continue
+		}
+		at := time.UnixMilli(m.InternalDate)
+		sender := strings.ToLower(from[0].Address)
+		s := node(sender, from[0].Name)
+		s.Sent++
+		seen(s, at)
+		g.Messages++
+
+		recipients := map[string]bool{}
+		for _, h := range []string{"To", "Cc"} {
+			for _, a := range parseAddressHeader(headerValue(m.Payload, h)) {
+				addr := strings.ToLower(a.Address)
+				if addr == "" || addr == sender || recipients[addr] {
+					continue
+				}
+				recipients[addr] = true
+				r := node(addr, a.Name)
+				r.Received++
+				seen(r, at)
+				key := [2]string{sender, addr}
+				e, ok := edges[key]
+				if !ok {
+					e = &contactGraphEdge{From: sender, To: addr}
+					edges[key] = e
+				}
+				e.Messages++
+				if at.After(e.last) {
+					e.last = at
+				}
+			}
+		}
+	}
+
+	used := map[string]bool{}
+	for _, e := range edges {
+		if e.Messages < minMessages {
+			continue
+		}
+		e.Last = e.last.UTC().Format(time.RFC3339)
+		g.Edges = append(g.Edges, e)
+		used[e.From], used[e.To] = true, true
+	}
+	for a, n := range nodes {
+		if !used[a] && minMessages > 1 {
+			continue
+		}
+		n.Last = n.last.UTC().Format(time.RFC3339)
+		g.Nodes = append(g.Nodes, n)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if ti, tj := g.Nodes[i].Sent+g.Nodes[i].Received, g.Nodes[j].Sent+g.Nodes[j].Received; ti != tj {
+			return ti > tj
+		}
+		return g.Nodes[i].Email < g.Nodes[j].Email
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].Messages != g.Edges[j].Messages {
+			return g.Edges[i].Messages > g.Edges[j].Messages
+		}
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	if g.Nodes == nil {
+		g.Nodes = []*contactGraphNode{}
+	}
+	if g.Edges == nil {
+		g.Edges = []*contactGraphEdge{}
+	}
+	return g
+}
+
+// writeContactGraphDOT renders g for Graphviz; edge width grows with the
+// message count.
+func writeContactGraphDOT(w io.Writer, g *contactGraph) error {
+	esc := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace
+	quote := func(s string) string { return `"` + esc(s) + `"` }
+	var b strings.Builder
+	b.WriteString("digraph contacts {\n")
+	b.WriteString("  rankdir=LR;\n  node [shape=box, style=rounded];\n")
+	for _, n := range g.Nodes {
+		label := quote(n.Email)
+		if n.Name != "" {
+			label = `"` + esc(n.Name) + `\n` + esc(n.Email) + `"`
+		}
+		style := ""
+		if n.Self {
+			style = `, style="rounded,bold"`
+		}
+		fmt.Fprintf(&b, "  %s [label=%s%s];\n", quote(n.Email), label, style)
+	}
+	for _, e := range g.Edges {
+		width := 1 + float64(e.Messages)/5
+		if width > 8 {
+			width = 8
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=\"%d\", penwidth=%.1f, tooltip=%s];\n", quote(e.From), quote(e.To), e.Messages, width, quote("last "+e.Last))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
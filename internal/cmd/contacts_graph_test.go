@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func graphMsg(id, at, from, to, cc string) map[string]any {
+	headers := []map[string]any{{"name": "From", "value": from}, {"name": "To", "value": to}}
+	if cc != "" {
+		headers = append(headers, map[string]any{"name": "Cc", "value": cc})
+	}
+	return map[string]any{"id": id, "internalDate": at, "payload": map[string]any{"headers": headers}}
+}
+
+func TestExecute_ContactsGraph(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	msgs := map[string]map[string]any{
+		"m1": graphMsg("m1", "1700000000000", "Me <me@x.com>", "Ada <ada@y.com>", "bob@z.com"),
+		"m2": graphMsg("m2", "1700000500000", "me@x.com", "ADA@y.com, ada@y.com", ""),
+		"m3": graphMsg("m3", "1700000900000", `"Lovelace, Ada" <ada@y.com>`, "me@x.com", ""),
+	}
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/gmail/v1/users/me/messages":
+			query = r.URL.Query().Get("q")
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1"}, {"id": "m2"}, {"id": "m3"}}})
+		case strings.HasPrefix(r.URL.Path, "/gmail/v1/users/me/messages/"):
+			_ = json.NewEncoder(w).Encode(msgs[strings.TrimPrefix(r.URL.Path, "/gmail/v1/users/me/messages/")])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "me@x.com", "contacts", "graph", "--since", "30d", "--format", "json"}); err != nil {
+			t.Fatalf("graph: %v", err)
+		}
+	})
+	if !strings.HasPrefix(query, "{in:sent in:inbox} after:") {
+		t.Fatalf("query = %q", query)
+	}
+	var g contactGraph
+	if err := json.Unmarshal([]byte(out), &g); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if g.Messages != 3 || len(g.Nodes) != 3 || len(g.Edges) != 3 {
+		t.Fatalf("graph = %s", out)
+	}
+	if e := g.Edges[0]; e.From != "me@x.com" || e.To != "ada@y.com" || e.Messages != 2 || e.Last != "2023-11-14T22:21:40Z" {
+		t.Fatalf("top edge = %+v", e)
+	}
+	if n := g.Nodes[1]; n.Email != "me@x.com" || !n.Self || n.Sent != 2 || n.Received != 1 || n.Name != "Me" {
+		t.Fatalf("self node = %+v", n)
+	}
+	if n := g.Nodes[2]; n.Email != "bob@z.com" || n.Received != 1 || n.Last != "2023-11-14T22:13:20Z" {
+		t.Fatalf("bob node = %+v", n)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "me@x.com", "contacts", "graph", "--min-messages", "2"}); err != nil {
+			t.Fatalf("graph dot: %v", err)
+		}
+	})
+	for _, want := range []string{"digraph contacts {", `"me@x.com" [label="Me\nme@x.com", style="rounded,bold"];`, `"me@x.com" -> "ada@y.com" [label="2"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "bob@z.com") {
+		t.Fatalf("--min-messages should drop bob:\n%s", out)
+	}
+}
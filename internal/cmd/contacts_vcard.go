@@ -0,0 +1,514 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/people/v1"
+)
+
+const contactsVCardFields = "names,emailAddresses,phoneNumbers,organizations,biographies,urls,birthdays"
+
+// People API caps batchCreateContacts at 200 contacts per call.
+const contactsBatchCreateMax = 200
+
+func newContactsExportCmd(flags *rootFlags) *cobra.Command {
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all contacts as vCard 3.0",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+
+			svc, err := newPeopleContactsService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			var all []*people.Person
+			page := ""
+			for {
+				resp, err := svc.People.Connections.List("people/me").
+					PersonFields(contactsVCardFields).
+					PageSize(1000).
+					PageToken(page).
+					Do()
+				if err != nil {
+					return err
+				}
+				all = append(all, resp.Connections...)
+				if resp.NextPageToken == "" {
+					break
+				}
+				page = resp.NextPageToken
+			}
+
+			var w io.Writer = os.Stdout
+			if outPath != "-" {
+				f, err := os.OpenFile(outPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			bw := bufio.NewWriter(w)
+			count := 0
+			for _, p := range all {
+				if p == nil {
+					continue
+				}
+				if _, err := io.WriteString(bw, personToVCard(p)); err != nil {
+					return err
+				}
+				count++
+			}
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+
+			if outPath == "-" {
+				return nil
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"path": outPath, "count": count})
+			}
+			u.Out().Printf("path\t%s", outPath)
+			u.Out().Printf("count\t%d", count)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&outPath, "out", "-", "Output .vcf path (- for stdout)")
+	return cmd
+}
+
+func newContactsImportCmd(flags *rootFlags) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file.vcf|->",
+		Short: "Create contacts from a vCard file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+
+			var r io.Reader
+			if args[0] == "-" {
+				r = stdinReader()
+			} else {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			persons, err := parseVCards(r)
+			if err != nil {
+				return err
+			}
+			if len(persons) == 0 {
+				return usage("no vCards found in input")
+			}
+
+			created := make([]*people.Person, 0, len(persons))
+			if dryRun {
+				created = persons
+			} else {
+				svc, err := newPeopleContactsService(cmd.Context(), account)
+				if err != nil {
+					return err
+				}
+				for start := 0; start < len(persons); start += contactsBatchCreateMax {
+					end := min(start+contactsBatchCreateMax, len(persons))
+					req := &people.BatchCreateContactsRequest{ReadMask: contactsReadMask}
+					for _, p := range persons[start:end] {
+						req.Contacts = append(req.Contacts, &people.ContactToCreate{ContactPerson: p})
+					}
+					resp, err := svc.People.BatchCreateContacts(req).Do()
+					if err != nil {
+						return fmt.Errorf("import contacts %d-%d: %w", start+1, end, err)
+					}
+					for _, pr := range resp.CreatedPeople {
+						if pr != nil && pr.Person != nil {
+							created = append(created, pr.Person)
+						}
+					}
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				type item struct {
+					Resource string `json:"resource,omitempty"`
+					Name     string `json:"name,omitempty"`
+					Email    string `json:"email,omitempty"`
+				}
+				items := make([]item, 0, len(created))
+				for _, p := range created {
+					items = append(items, item{Resource: p.ResourceName, Name: primaryName(p), Email: primaryEmail(p)})
+				}
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"dryRun":   dryRun,
+					"count":    len(items),
+					"contacts": items,
+				})
+			}
+
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "RESOURCE\tNAME\tEMAIL")
+			for _, p := range created {
+				resource := p.ResourceName
+				if resource == "" {
+					resource = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", resource, sanitizeTab(primaryName(p)), sanitizeTab(primaryEmail(p)))
+			}
+			if dryRun {
+				u.Err().Printf("Dry run: %d contacts parsed, nothing created", len(created))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Parse and print contacts without creating them")
+	return cmd
+}
+
+// personToVCard renders p as a vCard 3.0 entry (CRLF line endings, folded at 75 octets).
+func personToVCard(p *people.Person) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldVCardLine(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCARD")
+	line("VERSION:3.0")
+	fn := primaryName(p)
+	if fn == "" {
+		fn = primaryEmail(p)
+	}
+	line("FN:" + escapeVCard(fn))
+	if len(p.Names) > 0 && p.Names[0] != nil {
+		n := p.Names[0]
+		line("N:" + strings.Join([]string{
+			escapeVCard(n.FamilyName),
+			escapeVCard(n.GivenName),
+			escapeVCard(n.MiddleName),
+			escapeVCard(n.HonorificPrefix),
+			escapeVCard(n.HonorificSuffix),
+		}, ";"))
+	} else {
+		line("N:;;;;")
+	}
+	for _, e := range p.EmailAddresses {
+		if e == nil || e.Value == "" {
+			continue
+		}
+		line("EMAIL" + vCardTypeParam(e.Type) + ":" + escapeVCard(e.Value))
+	}
+	for _, t := range p.PhoneNumbers {
+		if t == nil || t.Value == "" {
+			continue
+		}
+		line("TEL" + vCardTypeParam(t.Type) + ":" + escapeVCard(t.Value))
+	}
+	for _, o := range p.Organizations {
+		if o == nil {
+			continue
+		}
+		if o.Name != "" || o.Department != "" {
+			line("ORG:" + escapeVCard(o.Name) + ";" + escapeVCard(o.Department))
+		}
+		if o.Title != "" {
+			line("TITLE:" + escapeVCard(o.Title))
+		}
+	}
+	for _, u := range p.Urls {
+		if u == nil || u.Value == "" {
+			continue
+		}
+		line("URL:" + escapeVCard(u.Value))
+	}
+	for _, bd := range p.Birthdays {
+		if bd == nil || bd.Date == nil || bd.Date.Month == 0 || bd.Date.Day == 0 {
+			continue
+		}
+		if bd.Date.Year > 0 {
+			line(fmt.Sprintf("BDAY:%04d-%02d-%02d", bd.Date.Year, bd.Date.Month, bd.Date.Day))
+		} else {
+			line(fmt.Sprintf("BDAY:--%02d-%02d", bd.Date.Month, bd.Date.Day))
+		}
+		break
+	}
+	for _, bio := range p.Biographies {
+		if bio == nil || bio.Value == "" {
+			continue
+		}
+		line("NOTE:" + escapeVCard(bio.Value))
+		break
+	}
+	if p.ResourceName != "" {
+		line("X-GOOGLE-RESOURCE:" + escapeVCard(p.ResourceName))
+	}
+	line("END:VCARD")
+	return b.String()
+}
+
+// parseVCards reads vCard 2.1/3.0/4.0 entries into People API persons.
+// Unknown properties are ignored.
+func parseVCards(r io.Reader) ([]*people.Person, error) {
+	lines, err := unfoldVCardLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*people.Person
+	var cur *people.Person
+	var fn string
+	for i, raw := range lines {
+		name, params, value, ok := splitVCardLine(raw)
+		if !ok {
+			continue
+		}
+		switch name {
+		case "BEGIN":
+			if strings.EqualFold(value, "VCARD") {
+				cur = &people.Person{}
+				fn = ""
+			}
+			continue
+		case "END":
+			if cur != nil && strings.EqualFold(value, "VCARD") {
+				if len(cur.Names) == 0 && fn != "" {
+					cur.Names = []*people.Name{{UnstructuredName: fn}}
+				}
+				out = append(out, cur)
+				cur = nil
+			}
+			continue
+		}
+		if cur == nil {
+			return nil, fmt.Errorf("vcard line %d: %s outside BEGIN:VCARD", i+1, name)
+		}
+
+		switch name {
+		case "FN":
+			fn = unescapeVCard(value)
+		case "N":
+			parts := splitVCardValue(value, ';')
+			for len(parts) < 5 {
+				parts = append(parts, "")
+			}
+			n := &people.Name{
+				FamilyName:      parts[0],
+				GivenName:       parts[1],
+				MiddleName:      parts[2],
+				HonorificPrefix: parts[3],
+				HonorificSuffix: parts[4],
+			}
+			if n.FamilyName != "" || n.GivenName != "" || n.MiddleName != "" {
+				cur.Names = []*people.Name{n}
+			}
+		case "EMAIL":
+			cur.EmailAddresses = append(cur.EmailAddresses, &people.EmailAddress{Value: unescapeVCard(value), Type: vCardType(params)})
+		case "TEL":
+			cur.PhoneNumbers = append(cur.PhoneNumbers, &people.PhoneNumber{Value: unescapeVCard(value), Type: vCardType(params)})
+		case "ORG":
+			parts := splitVCardValue(value, ';')
+			org := &people.Organization{Name: parts[0]}
+			if len(parts) > 1 {
+				org.Department = parts[1]
+			}
+			if len(cur.Organizations) > 0 {
+				cur.Organizations[0].Name, cur.Organizations[0].Department = org.Name, org.Department
+			} else {
+				cur.Organizations = append(cur.Organizations, org)
+			}
+		case "TITLE":
+			if len(cur.Organizations) > 0 {
+				cur.Organizations[0].Title = unescapeVCard(value)
+			} else {
+				cur.Organizations = append(cur.Organizations, &people.Organization{Title: unescapeVCard(value)})
+			}
+		case "URL":
+			cur.Urls = append(cur.Urls, &people.Url{Value: unescapeVCard(value)})
+		case "NOTE":
+			cur.Biographies = []*people.Biography{{Value: unescapeVCard(value), ContentType: "TEXT_PLAIN"}}
+		case "BDAY":
+			if d := parseVCardDate(value); d != nil {
+				cur.Birthdays = []*people.Birthday{{Date: d}}
+			}
+		}
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("vcard: missing END:VCARD")
+	}
+	return out, nil
+}
+
+func unfoldVCardLines(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	var lines []string
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return lines, sc.Err()
+}
+
+// splitVCardLine splits "group.NAME;PARAM=x:value" into its upper-cased name,
+// raw params, and value.
+func splitVCardLine(l string) (name string, params []string, value string, ok bool) {
+	head, value, ok := strings.Cut(l, ":")
+	if !ok {
+		return "", nil, "", false
+	}
+	parts := strings.Split(head, ";")
+	name = strings.ToUpper(parts[0])
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name, parts[1:], value, true
+}
+
+func vCardType(params []string) string {
+	for _, p := range params {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok {
+			// vCard 2.1 bare types, e.g. TEL;CELL:...
+			v = k
+		} else if !strings.EqualFold(k, "TYPE") {
+			continue
+		}
+		for _, t := range strings.Split(strings.Trim(v, `"`), ",") {
+			switch strings.ToLower(t) {
+			case "home", "work", "other":
+				return strings.ToLower(t)
+			case "cell":
+				return "mobile"
+			}
+		}
+	}
+	return ""
+}
+
+func vCardTypeParam(t string) string {
+	switch strings.ToLower(t) {
+	case "home", "work", "other":
+		return ";TYPE=" + strings.ToUpper(t)
+	case "mobile":
+		return ";TYPE=CELL"
+	}
+	return ""
+}
+
+func parseVCardDate(v string) *people.Date {
+	v = strings.TrimSpace(v)
+	var y, m, d int64
+	switch {
+	case strings.HasPrefix(v, "--"):
+		s := strings.ReplaceAll(v[2:], "-", "")
+		if _, err := fmt.Sscanf(s, "%02d%02d", &m, &d); err != nil {
+			return nil
+		}
+	default:
+		s := strings.ReplaceAll(v, "-", "")
+		if len(s) > 8 {
+			s = s[:8]
+		}
+		if _, err := fmt.Sscanf(s, "%04d%02d%02d", &y, &m, &d); err != nil {
+			return nil
+		}
+	}
+	if m < 1 || m > 12 || d < 1 || d > 31 {
+		return nil
+	}
+	return &people.Date{Year: y, Month: m, Day: d}
+}
+
+func escapeVCard(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "\r\n", `\n`, "\n", `\n`, ",", `\,`, ";", `\;`)
+	return r.Replace(s)
+}
+
+func unescapeVCard(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// splitVCardValue splits a structured value on unescaped sep and unescapes each part.
+func splitVCardValue(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == sep {
+			parts = append(parts, unescapeVCard(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, unescapeVCard(s[start:]))
+}
+
+// foldVCardLine folds at 75 octets without splitting UTF-8 sequences.
+func foldVCardLine(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		n := len(string(r))
+		if width+n > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/api/people/v1"
+)
+
+func TestVCard_RoundTrip(t *testing.T) {
+	p := &people.Person{
+		ResourceName:   "people/c1",
+		Names:          []*people.Name{{GivenName: "Ada", FamilyName: "Lovelace, Countess", DisplayName: "Ada Lovelace"}},
+		EmailAddresses: []*people.EmailAddress{{Value: "ada@example.com", Type: "work"}},
+		PhoneNumbers:   []*people.PhoneNumber{{Value: "+44 1", Type: "mobile"}},
+		Organizations:  []*people.Organization{{Name: "Analytical; Engines", Title: "Programmer"}},
+		Biographies:    []*people.Biography{{Value: "line1\nline2 " + strings.Repeat("é", 60)}},
+		Birthdays:      []*people.Birthday{{Date: &people.Date{Month: 12, Day: 10}}},
+	}
+	card := personToVCard(p)
+	for _, l := range strings.Split(card, "\r\n") {
+		if len(l) > 75 {
+			t.Fatalf("unfolded line: %q", l)
+		}
+	}
+	if !strings.Contains(card, "EMAIL;TYPE=WORK:ada@example.com") || !strings.Contains(card, "TEL;TYPE=CELL:") {
+		t.Fatalf("unexpected card:\n%s", card)
+	}
+
+	got, err := parseVCards(strings.NewReader(card))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 card, got %d", len(got))
+	}
+	q := got[0]
+	if q.Names[0].FamilyName != "Lovelace, Countess" || q.Names[0].GivenName != "Ada" {
+		t.Fatalf("names: %#v", q.Names[0])
+	}
+	if q.EmailAddresses[0].Type != "work" || q.PhoneNumbers[0].Type != "mobile" {
+		t.Fatalf("types: %#v %#v", q.EmailAddresses[0], q.PhoneNumbers[0])
+	}
+	if q.Organizations[0].Name != "Analytical; Engines" || q.Organizations[0].Title != "Programmer" {
+		t.Fatalf("org: %#v", q.Organizations[0])
+	}
+	if q.Biographies[0].Value != p.Biographies[0].Value {
+		t.Fatalf("note: %q", q.Biographies[0].Value)
+	}
+	if d := q.Birthdays[0].Date; d.Month != 12 || d.Day != 10 || d.Year != 0 {
+		t.Fatalf("bday: %#v", d)
+	}
+}
+
+func TestParseVCards_V21AndFNOnly(t *testing.T) {
+	in := "BEGIN:VCARD\nVERSION:2.1\nFN:Solo Name\nTEL;CELL:123\nitem1.EMAIL;type=INTERNET:x@y.z\nEND:VCARD\n"
+	got, err := parseVCards(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(got) != 1 || got[0].Names[0].UnstructuredName != "Solo Name" {
+		t.Fatalf("unexpected: %#v", got)
+	}
+	if got[0].PhoneNumbers[0].Type != "mobile" || got[0].EmailAddresses[0].Value != "x@y.z" {
+		t.Fatalf("unexpected fields: %#v %#v", got[0].PhoneNumbers[0], got[0].EmailAddresses[0])
+	}
+
+	if _, err := parseVCards(strings.NewReader("BEGIN:VCARD\nFN:x\n")); err == nil {
+		t.Fatalf("expected error for unterminated card")
+	}
+}
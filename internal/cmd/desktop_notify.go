@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+const desktopNotifyTimeout = 10 * time.Second
+
+// windowsToastScript shows a toast with the title and body passed in
+// GOG_NOTIFY_TITLE/GOG_NOTIFY_BODY, which avoids quoting them for PowerShell.
+const windowsToastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$x = $t.GetElementsByTagName('text')
+$x.Item(0).AppendChild($t.CreateTextNode($env:GOG_NOTIFY_TITLE)) > $null
+$x.Item(1).AppendChild($t.CreateTextNode($env:GOG_NOTIFY_BODY)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('gog').Show([Windows.UI.Notifications.ToastNotification]::new($t))`
+
+// sendDesktopNotification raises a native notification: osascript on macOS,
+// notify-send (libnotify) on Linux/BSD, a PowerShell toast on Windows.
+// Swapped in tests.
+var sendDesktopNotification = func(ctx context.Context, title, body string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.CommandContext(ctx, "osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body)
+	case "windows":
+		c = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToastScript)
+		c.Env = append(os.Environ(), "GOG_NOTIFY_TITLE="+title, "GOG_NOTIFY_BODY="+body)
+	default:
+		c = exec.CommandContext(ctx, "notify-send", "--app-name=gog", title, body)
+	}
+	if out, err := c.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// desktopNotifyFlag is --notify-desktop plus its --notify-rule filters,
+// shared by the follow/watch/serve commands.
+type desktopNotifyFlag struct {
+	enabled bool
+	rules   []string
+}
+
+func (f *desktopNotifyFlag) addFlags(cmd *cobra.Command, fields string) {
+	cmd.Flags().BoolVar(&f.enabled, "notify-desktop", false, "Raise a native desktop notification for each new item")
+	cmd.Flags().StringArrayVar(&f.rules, "notify-rule", nil, "Only notify when field~regex matches (repeatable; any rule matches; fields: "+fields+")")
+}
+
+// notifier returns nil when --notify-desktop is off.
+func (f *desktopNotifyFlag) notifier(u *ui.UI) (*desktopNotifier, error) {
+	if !f.enabled {
+		if len(f.rules) > 0 {
+			return nil, usage("--notify-rule requires --notify-desktop")
+		}
+		return nil, nil
+	}
+	rules, err := parseNotifyRules(f.rules)
+	if err != nil {
+		return nil, err
+	}
+	n := &desktopNotifier{rules: rules}
+	if u != nil {
+		n.warnf = u.Err().Printf
+	}
+	return n, nil
+}
+
+type notifyRule struct {
+	field string
+	re    *regexp.Regexp
+}
+
+// parseNotifyRules parses field~regex specs; matching is case-insensitive
+// unless the regex sets its own flags.
+func parseNotifyRules(specs []string) ([]notifyRule, error) {
+	rules := make([]notifyRule, 0, len(specs))
+	for _, spec := range specs {
+		field, expr, ok := strings.Cut(spec, "~")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || field == "" || expr == "" {
+			return nil, usagef("invalid --notify-rule %q (expected field~regex, e.g. from~@boss\\.com)", spec)
+		}
+		if !strings.HasPrefix(expr, "(?") {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, usagef("invalid --notify-rule %q: %v", spec, err)
+		}
+		rules = append(rules, notifyRule{field: field, re: re})
+	}
+	return rules, nil
+}
+
+type desktopNotifier struct {
+	rules []notifyRule
+	warnf func(string, ...any)
+}
+
+// matches reports whether fields pass the rules: any rule matching is
+// enough, and no rules means everything passes.
+func (n *desktopNotifier) matches(fields map[string]string) bool {
+	if len(n.rules) == 0 {
+		return true
+	}
+	for _, r := range n.rules {
+		if r.re.MatchString(fields[r.field]) {
+			return true
+		}
+	}
+	return false
+}
+
+// notify raises a notification if fields pass the rules. It is safe on a
+// nil notifier; failures only warn so a watch keeps running.
+func (n *desktopNotifier) notify(ctx context.Context, title, body string, fields map[string]string) {
+	if n == nil || !n.matches(fields) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, desktopNotifyTimeout)
+	defer cancel()
+	if err := sendDesktopNotification(ctx, title, body); err != nil && n.warnf != nil {
+		n.warnf("WARN: desktop notification failed: %v", err)
+	}
+}
+
+// notifyGmailMessageID fetches a new message's headers and notifies about it.
+func (n *desktopNotifier) notifyGmailMessageID(ctx context.Context, svc *gmail.Service, id string) {
+	msg, err := svc.Users.Messages.Get("me", id).
+		Format("metadata").
+		MetadataHeaders("From", "To", "Subject").
+		Context(ctx).
+		Do()
+	if err != nil {
+		if n.warnf != nil {
+			n.warnf("WARN: notification for %s: %v", id, err)
+		}
+		return
+	}
+	n.notifyGmailMessage(ctx, gmailHookMessage{
+		ID:      msg.Id,
+		From:    headerValue(msg.Payload, "From"),
+		To:      headerValue(msg.Payload, "To"),
+		Subject: headerValue(msg.Payload, "Subject"),
+		Snippet: gmailSnippet(msg.Snippet),
+		Labels:  msg.LabelIds,
+	})
+}
+
+// notifyGmailMessage notifies about a new message.
+func (n *desktopNotifier) notifyGmailMessage(ctx context.Context, m gmailHookMessage) {
+	n.notify(ctx, orDash(m.From), orDash(m.Subject), map[string]string{
+		"from":    m.From,
+		"to":      m.To,
+		"subject": m.Subject,
+		"snippet": m.Snippet,
+		"labels":  strings.Join(m.Labels, ","),
+	})
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+func TestDesktopNotifierRules(t *testing.T) {
+	rules, err := parseNotifyRules([]string{`from~@boss\.com`, "subject~urgent"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	n := &desktopNotifier{rules: rules}
+	cases := []struct {
+		fields map[string]string
+		want   bool
+	}{
+		{map[string]string{"from": "Big Boss <ceo@BOSS.com>"}, true},
+		{map[string]string{"from": "x@y.com", "subject": "URGENT: fix"}, true},
+		{map[string]string{"from": "x@y.com", "subject": "lunch"}, false},
+	}
+	for _, tc := range cases {
+		if got := n.matches(tc.fields); got != tc.want {
+			t.Fatalf("matches(%v) = %v", tc.fields, got)
+		}
+	}
+	if !(&desktopNotifier{}).matches(nil) {
+		t.Fatalf("no rules should match everything")
+	}
+	for _, bad := range []string{"subject", "~x", "from~("} {
+		if _, err := parseNotifyRules([]string{bad}); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+	if _, err := (&desktopNotifyFlag{rules: []string{"from~x"}}).notifier(nil); err == nil {
+		t.Fatalf("--notify-rule without --notify-desktop should fail")
+	}
+}
+
+func TestExecute_CalendarRemindNotifies(t *testing.T) {
+	origCal, origSend := newCalendarService, sendDesktopNotification
+	t.Cleanup(func() { newCalendarService, sendDesktopNotification = origCal, origSend })
+
+	soon := time.Now().Add(5 * time.Minute).UTC().Format(time.RFC3339)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/calendars/primary/events") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{
+			{"id": "e1", "summary": "Standup", "start": map[string]any{"dateTime": soon}},
+			{"id": "e2", "summary": "Budget review", "start": map[string]any{"dateTime": soon}},
+			{"id": "e3", "summary": "Standup (declined)", "start": map[string]any{"dateTime": soon},
+				"attendees": []map[string]any{{"email": "a@b.com", "self": true, "responseStatus": "declined"}}},
+			{"id": "e4", "summary": "Offsite", "start": map[string]any{"date": "2025-03-01"}},
+		}})
+	}))
+	defer srv.Close()
+
+	svc, err := calendar.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return svc, nil }
+	var titles []string
+	sendDesktopNotification = func(_ context.Context, title, _ string) error {
+		titles = append(titles, title)
+		return nil
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "calendar", "remind", "--notify-desktop", "--notify-rule", "summary~standup"}); err != nil {
+			t.Fatalf("remind: %v", err)
+		}
+	})
+	if strings.Count(out, `"id"`) != 2 || strings.Contains(out, "e3") || strings.Contains(out, "e4") {
+		t.Fatalf("out = %s", out)
+	}
+	if len(titles) != 1 || titles[0] != "Standup" {
+		t.Fatalf("notifications = %v", titles)
+	}
+}
@@ -16,8 +16,10 @@ import (
 func newDocsCmd(flags *rootFlags) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "docs",
-		Short: "Google Docs (export via Drive)",
+		Short: "Google Docs",
 	}
+	cmd.AddCommand(newDocsGetCmd(flags))
+	cmd.AddCommand(newDocsAppendCmd(flags))
 	cmd.AddCommand(newDocsExportCmd(flags))
 	cmd.AddCommand(newDocsInfoCmd(flags))
 	cmd.AddCommand(newDocsCreateCmd(flags))
@@ -29,12 +31,12 @@ func newDocsCmd(flags *rootFlags) *cobra.Command {
 func newDocsExportCmd(flags *rootFlags) *cobra.Command {
 	return newExportViaDriveCmd(flags, exportViaDriveOptions{
 		Use:           "export <docId>",
-		Short:         "Export a Google Doc (pdf|docx|txt)",
+		Short:         "Export a Google Doc (pdf|docx|txt|md|html)",
 		ArgName:       "docId",
 		ExpectedMime:  "application/vnd.google-apps.document",
 		KindLabel:     "Google Doc",
 		DefaultFormat: "pdf",
-		FormatHelp:    "Export format: pdf|docx|txt",
+		FormatHelp:    "Export format: pdf|docx|txt|md|html",
 	})
 }
 
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/docs/v1"
+)
+
+var newDocsService = googleapi.NewDocs
+
+func newDocsGetCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <docId>",
+		Short: "Get a Google Doc (metadata + text) via the Docs API",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			id := strings.TrimSpace(args[0])
+			if id == "" {
+				return usage("empty docId")
+			}
+
+			svc, err := newDocsService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			doc, err := svc.Documents.Get(id).Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"document": doc,
+					"text":     docsPlainText(doc),
+				})
+			}
+
+			u.Out().Printf("id\t%s", doc.DocumentId)
+			u.Out().Printf("title\t%s", doc.Title)
+			u.Out().Printf("revision\t%s", doc.RevisionId)
+			u.Out().Println("")
+			u.Out().Println(strings.TrimRight(docsPlainText(doc), "\n"))
+			return nil
+		},
+	}
+}
+
+func newDocsAppendCmd(flags *rootFlags) *cobra.Command {
+	var file string
+	var inline bool
+
+	cmd := &cobra.Command{
+		Use:   "append <docId> [text]",
+		Short: "Append text to the end of a Google Doc",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			id := strings.TrimSpace(args[0])
+			if id == "" {
+				return usage("empty docId")
+			}
+
+			var text string
+			switch {
+			case len(args) == 2 && strings.TrimSpace(file) != "":
+				return usage("use either [text] or --file, not both")
+			case len(args) == 2:
+				text = args[1]
+			case strings.TrimSpace(file) != "":
+				var b []byte
+				if file == "-" {
+					b, err = readStdin()
+				} else {
+					b, err = os.ReadFile(file)
+				}
+				if err != nil {
+					return err
+				}
+				text = string(b)
+			default:
+				return usage("missing text (pass [text] or --file PATH|-)")
+			}
+			if text == "" {
+				return usage("empty text")
+			}
+
+			svc, err := newDocsService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			doc, err := svc.Documents.Get(id).Fields("documentId,revisionId,body(content(endIndex))").Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			// Start a new paragraph unless the document is empty (body is just "\n").
+			if !inline && docsEndIndex(doc) > 2 && !strings.HasPrefix(text, "\n") {
+				text = "\n" + text
+			}
+
+			resp, err := svc.Documents.BatchUpdate(id, &docs.BatchUpdateDocumentRequest{
+				Requests: []*docs.Request{{
+					InsertText: &docs.InsertTextRequest{
+						Text:                 text,
+						EndOfSegmentLocation: &docs.EndOfSegmentLocation{},
+					},
+				}},
+				WriteControl: &docs.WriteControl{RequiredRevisionId: doc.RevisionId},
+			}).Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			if resp == nil {
+				return errors.New("append failed")
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"documentId": resp.DocumentId,
+					"appended":   len(text),
+				})
+			}
+			u.Out().Printf("id\t%s", resp.DocumentId)
+			u.Out().Printf("appended\t%d", len(text))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "Read text from file (- for stdin)")
+	cmd.Flags().BoolVar(&inline, "inline", false, "Append to the last paragraph instead of starting a new one")
+	return cmd
+}
+
+func docsEndIndex(doc *docs.Document) int64 {
+	if doc == nil || doc.Body == nil || len(doc.Body.Content) == 0 {
+		return 0
+	}
+	return doc.Body.Content[len(doc.Body.Content)-1].EndIndex
+}
+
+// docsPlainText flattens paragraphs and table cells into plain text.
+func docsPlainText(doc *docs.Document) string {
+	if doc == nil || doc.Body == nil {
+		return ""
+	}
+	var b strings.Builder
+	writeDocsElements(&b, doc.Body.Content)
+	return b.String()
+}
+
+func writeDocsElements(b *strings.Builder, elems []*docs.StructuralElement) {
+	for _, el := range elems {
+		if el == nil {
+			continue
+		}
+		switch {
+		case el.Paragraph != nil:
+			for _, pe := range el.Paragraph.Elements {
+				if pe != nil && pe.TextRun != nil {
+					b.WriteString(pe.TextRun.Content)
+				}
+			}
+		case el.Table != nil:
+			for _, row := range el.Table.TableRows {
+				for _, cell := range row.TableCells {
+					writeDocsElements(b, cell.Content)
+				}
+			}
+		case el.TableOfContents != nil:
+			writeDocsElements(b, el.TableOfContents.Content)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+const (
+	scanHookEnv     = "GOG_SCAN_HOOK"
+	scanHookTimeout = 5 * time.Minute
+)
+
+// runScanHook runs download.scanHook through the shell; swapped in tests.
+var runScanHook = func(ctx context.Context, command string, env []string) error {
+	return runNotifyExec(ctx, command, env, nil)
+}
+
+// errScanBlocked reports a download the scan hook rejected.
+var errScanBlocked = errors.New("blocked by download.scanHook")
+
+// downloadScanConfig returns the scan hook (GOG_SCAN_HOOK overrides
+// config.json download.scanHook) and the quarantine dir; an empty hook
+// means downloads are written directly.
+func downloadScanConfig() (hook, quarantine string, err error) {
+	cfg, err := config.ReadConfigFile()
+	if err != nil {
+		return "", "", err
+	}
+	hook = strings.TrimSpace(cfg.Download.ScanHook)
+	if v := strings.TrimSpace(os.Getenv(scanHookEnv)); v != "" {
+		hook = v
+	}
+	if hook == "" {
+		return "", "", nil
+	}
+	quarantine = strings.TrimSpace(cfg.Download.QuarantineDir)
+	if quarantine == "" {
+		if quarantine, err = config.QuarantineDir(); err != nil {
+			return "", "", err
+		}
+	}
+	return hook, quarantine, nil
+}
+
+// saveDownload writes r to outPath. With a scan hook configured the data
+// lands in the quarantine dir first and is moved to outPath only if the
+// hook exits 0; a rejected file stays in quarantine and errScanBlocked is
+// returned.
+func saveDownload(ctx context.Context, source, outPath string, r io.Reader, perm os.FileMode) (int64, error) {
+	hook, quarantine, err := downloadScanConfig()
+	if err != nil {
+		return 0, err
+	}
+	if hook == "" {
+		return writeDownloadFile(outPath, r, perm)
+	}
+
+	if err := os.MkdirAll(quarantine, 0o700); err != nil {
+		return 0, err
+	}
+	tmp, err := os.CreateTemp(quarantine, "*-"+filepath.Base(outPath))
+	if err != nil {
+		return 0, err
+	}
+	held := tmp.Name()
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(held)
+		return 0, err
+	}
+
+	hookCtx, cancel := context.WithTimeout(ctx, scanHookTimeout)
+	defer cancel()
+	env := []string{
+		"GOG_SCAN_FILE=" + held,
+		"GOG_SCAN_NAME=" + filepath.Base(outPath),
+		"GOG_SCAN_DEST=" + outPath,
+		"GOG_SCAN_SOURCE=" + source,
+	}
+	if err := runScanHook(hookCtx, hook, env); err != nil {
+		return 0, fmt.Errorf("%s: %w (kept in %s): %v", filepath.Base(outPath), errScanBlocked, held, err)
+	}
+	if err := moveFile(held, outPath, perm); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func writeDownloadFile(outPath string, r io.Reader, perm os.FileMode) (int64, error) {
+	f, err := os.OpenFile(outPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
+
+// moveFile renames src to dst with mode perm, copying when they are on
+// different filesystems.
+func moveFile(src, dst string, perm os.FileMode) error {
+	if err := os.Rename(src, dst); err == nil {
+		return os.Chmod(dst, perm)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if _, err := writeDownloadFile(dst, in, perm); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
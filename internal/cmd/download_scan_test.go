@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSaveDownloadScanHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook uses sh")
+	}
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	state := t.TempDir()
+	t.Setenv("GOG_STATE_DIR", state)
+	t.Setenv(scanHookEnv, `! grep -q EICAR "$GOG_SCAN_FILE" && test "$GOG_SCAN_SOURCE" = gmail`)
+	dir := t.TempDir()
+
+	clean := filepath.Join(dir, "report.pdf")
+	if _, err := saveDownload(context.Background(), "gmail", clean, strings.NewReader("fine"), 0o600); err != nil {
+		t.Fatalf("clean: %v", err)
+	}
+	if b, err := os.ReadFile(clean); err != nil || string(b) != "fine" {
+		t.Fatalf("clean file = %q %v", b, err)
+	}
+
+	bad := filepath.Join(dir, "invoice.exe")
+	_, err := saveDownload(context.Background(), "gmail", bad, strings.NewReader("X5O!P%@AP EICAR"), 0o600)
+	if !errors.Is(err, errScanBlocked) {
+		t.Fatalf("err = %v", err)
+	}
+	if _, statErr := os.Stat(bad); !os.IsNotExist(statErr) {
+		t.Fatalf("blocked file reached its destination")
+	}
+	held, _ := filepath.Glob(filepath.Join(state, "quarantine", "*-invoice.exe"))
+	if len(held) != 1 {
+		t.Fatalf("quarantine = %v", held)
+	}
+}
+
+func TestSaveDownloadWithoutHook(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	t.Setenv(scanHookEnv, "")
+	called := false
+	orig := runScanHook
+	t.Cleanup(func() { runScanHook = orig })
+	runScanHook = func(context.Context, string, []string) error { called = true; return nil }
+
+	out := filepath.Join(t.TempDir(), "a.txt")
+	if n, err := saveDownload(context.Background(), "drive", out, strings.NewReader("abc"), 0o644); err != nil || n != 3 {
+		t.Fatalf("save: %d %v", n, err)
+	}
+	if called {
+		t.Fatalf("hook ran without configuration")
+	}
+}
@@ -32,7 +32,9 @@ func newDriveCmd(flags *rootFlags) *cobra.Command {
 	cmd.AddCommand(newDriveDownloadCmd(flags))
 	cmd.AddCommand(newDriveCopyCmd(flags))
 	cmd.AddCommand(newDriveUploadCmd(flags))
+	cmd.AddCommand(newDriveAppendCmd(flags))
 	cmd.AddCommand(newDriveMkdirCmd(flags))
+	cmd.AddCommand(newDriveTrashCmd(flags))
 	cmd.AddCommand(newDriveDeleteCmd(flags))
 	cmd.AddCommand(newDriveMoveCmd(flags))
 	cmd.AddCommand(newDriveRenameCmd(flags))
@@ -40,6 +42,7 @@ func newDriveCmd(flags *rootFlags) *cobra.Command {
 	cmd.AddCommand(newDriveUnshareCmd(flags))
 	cmd.AddCommand(newDrivePermissionsCmd(flags))
 	cmd.AddCommand(newDriveURLCmd(flags))
+	cmd.AddCommand(newDriveWatchCmd(flags))
 
 	return cmd
 }
@@ -47,13 +50,15 @@ func newDriveCmd(flags *rootFlags) *cobra.Command {
 func newDriveLsCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 	var query string
 	var parent string
 
 	cmd := &cobra.Command{
-		Use:   "ls",
-		Short: "List files in a folder (default: root)",
-		Args:  cobra.NoArgs,
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List files in a folder (default: root)",
+		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			u := ui.FromContext(cmd.Context())
 			account, err := requireAccount(flags)
@@ -72,29 +77,42 @@ func newDriveLsCmd(flags *rootFlags) *cobra.Command {
 			}
 
 			q := buildDriveListQuery(folderID, query)
-
-			resp, err := svc.Files.List().
-				Q(q).
-				PageSize(max).
-				PageToken(page).
-				OrderBy("modifiedTime desc").
-				SupportsAllDrives(true).
-				IncludeItemsFromAllDrives(true).
-				Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
-				Context(cmd.Context()).
-				Do()
+			if outfmt.IsNDJSON(cmd.Context()) {
+				return streamDriveFilesNDJSON(cmd.Context(), svc, q, page, pages.ndjsonCap(max))
+			}
+
+			files, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*drive.File, string, error) {
+				resp, err := svc.Files.List().
+					Q(q).
+					PageSize(max).
+					PageToken(pageToken).
+					OrderBy("modifiedTime desc").
+					SupportsAllDrives(true).
+					IncludeItemsFromAllDrives(true).
+					Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
+					Context(cmd.Context()).
+					Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Files, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(files))
 			if err != nil {
 				return err
 			}
 
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
-					"files":         resp.Files,
-					"nextPageToken": resp.NextPageToken,
-				})
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"files":         files,
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
 
-			if len(resp.Files) == 0 {
+			if len(files) == 0 {
 				u.Err().Println("No files")
 				return nil
 			}
@@ -102,7 +120,7 @@ func newDriveLsCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "ID\tNAME\tTYPE\tSIZE\tMODIFIED")
-			for _, f := range resp.Files {
+			for _, f := range files {
 				fmt.Fprintf(
 					w,
 					"%s\t%s\t%s\t%s\t%s\n",
@@ -113,13 +131,14 @@ func newDriveLsCmd(flags *rootFlags) *cobra.Command {
 					formatDateTime(f.ModifiedTime),
 				)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 20, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 	cmd.Flags().StringVar(&query, "query", "", "Drive query filter")
 	cmd.Flags().StringVar(&parent, "parent", "", "Folder ID to list (default: root)")
 	return cmd
@@ -128,6 +147,7 @@ func newDriveLsCmd(flags *rootFlags) *cobra.Command {
 func newDriveSearchCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "search <text>",
@@ -146,28 +166,42 @@ func newDriveSearchCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			resp, err := svc.Files.List().
-				Q(buildDriveSearchQuery(text)).
-				PageSize(max).
-				PageToken(page).
-				OrderBy("modifiedTime desc").
-				SupportsAllDrives(true).
-				IncludeItemsFromAllDrives(true).
-				Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
-				Context(cmd.Context()).
-				Do()
+			if outfmt.IsNDJSON(cmd.Context()) {
+				return streamDriveFilesNDJSON(cmd.Context(), svc, buildDriveSearchQuery(text), page, pages.ndjsonCap(max))
+			}
+
+			files, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*drive.File, string, error) {
+				resp, err := svc.Files.List().
+					Q(buildDriveSearchQuery(text)).
+					PageSize(max).
+					PageToken(pageToken).
+					OrderBy("modifiedTime desc").
+					SupportsAllDrives(true).
+					IncludeItemsFromAllDrives(true).
+					Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
+					Context(cmd.Context()).
+					Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Files, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(files))
 			if err != nil {
 				return err
 			}
 
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
-					"files":         resp.Files,
-					"nextPageToken": resp.NextPageToken,
-				})
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"files":         files,
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
 
-			if len(resp.Files) == 0 {
+			if len(files) == 0 {
 				u.Err().Println("No results")
 				return nil
 			}
@@ -175,7 +209,7 @@ func newDriveSearchCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "ID\tNAME\tTYPE\tSIZE\tMODIFIED")
-			for _, f := range resp.Files {
+			for _, f := range files {
 				fmt.Fprintf(
 					w,
 					"%s\t%s\t%s\t%s\t%s\n",
@@ -186,16 +220,38 @@ func newDriveSearchCmd(flags *rootFlags) *cobra.Command {
 					formatDateTime(f.ModifiedTime),
 				)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 20, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 	return cmd
 }
 
+// streamDriveFilesNDJSON streams files matching q as JSON lines (see
+// streamNDJSONPages).
+func streamDriveFilesNDJSON(ctx context.Context, svc *drive.Service, q, page string, max int64) error {
+	return streamNDJSONPages(ctx, page, max, 1000, func(pageToken string, pageSize int64) ([]*drive.File, string, error) {
+		resp, err := svc.Files.List().
+			Q(q).
+			PageSize(pageSize).
+			PageToken(pageToken).
+			OrderBy("modifiedTime desc").
+			SupportsAllDrives(true).
+			IncludeItemsFromAllDrives(true).
+			Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Files, resp.NextPageToken, nil
+	})
+}
+
 func newDriveGetCmd(flags *rootFlags) *cobra.Command {
 	return &cobra.Command{
 		Use:   "get <fileId>",
@@ -303,13 +359,14 @@ func newDriveDownloadCmd(flags *rootFlags) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&outPathFlag, "out", "", "Output file path (default: gogcli config dir)")
-	cmd.Flags().StringVar(&format, "format", "", "Export format for Google Docs files: pdf|csv|xlsx|pptx|txt|png|docx (default: auto)")
+	cmd.Flags().StringVar(&format, "format", "", "Export format for Google Docs files: pdf|csv|xlsx|pptx|txt|png|docx|md|html (default: auto)")
 	return cmd
 }
 
 func newDriveUploadCmd(flags *rootFlags) *cobra.Command {
 	var name string
 	var parent string
+	var chunkMB int
 
 	cmd := &cobra.Command{
 		Use:   "upload <localPath>",
@@ -345,13 +402,23 @@ func newDriveUploadCmd(flags *rootFlags) *cobra.Command {
 				meta.Parents = []string{parent}
 			}
 
+			if chunkMB <= 0 {
+				return usage("--chunk-size must be > 0")
+			}
 			mimeType := guessMimeType(localPath)
-			created, err := svc.Files.Create(meta).
+			// Files larger than one chunk use a resumable upload session; each chunk
+			// is retried independently, so large uploads survive transient failures.
+			call := svc.Files.Create(meta).
 				SupportsAllDrives(true).
-				Media(f, gapi.ContentType(mimeType)).
-				Fields("id, name, mimeType, size, webViewLink").
-				Context(cmd.Context()).
-				Do()
+				Media(f, gapi.ContentType(mimeType), gapi.ChunkSize(chunkMB*1024*1024)).
+				Fields("id, name, mimeType, size, webViewLink")
+			if st, statErr := f.Stat(); statErr == nil && st.Size() > int64(chunkMB)*1024*1024 && !outfmt.IsJSON(cmd.Context()) {
+				total := st.Size()
+				call = call.ProgressUpdater(func(current, _ int64) {
+					u.Err().Printf("uploaded %s / %s", formatDriveSize(current), formatDriveSize(total))
+				})
+			}
+			created, err := call.Context(cmd.Context()).Do()
 			if err != nil {
 				return err
 			}
@@ -371,6 +438,7 @@ func newDriveUploadCmd(flags *rootFlags) *cobra.Command {
 
 	cmd.Flags().StringVar(&name, "name", "", "Override filename")
 	cmd.Flags().StringVar(&parent, "parent", "", "Destination folder ID")
+	cmd.Flags().IntVar(&chunkMB, "chunk-size", 16, "Resumable upload chunk size in MiB (larger files upload in chunks)")
 	return cmd
 }
 
@@ -429,10 +497,59 @@ func newDriveMkdirCmd(flags *rootFlags) *cobra.Command {
 	return cmd
 }
 
+func newDriveTrashCmd(flags *rootFlags) *cobra.Command {
+	var restore bool
+
+	cmd := &cobra.Command{
+		Use:   "trash <fileId>",
+		Short: "Move a file to trash (or restore it with --restore)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			fileID := args[0]
+
+			if !restore {
+				if confirmErr := confirmDestructive(cmd, flags, fmt.Sprintf("move drive file %s to trash", fileID)); confirmErr != nil {
+					return confirmErr
+				}
+			}
+
+			svc, err := newDriveService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			updated, err := svc.Files.Update(fileID, &drive.File{Trashed: !restore, ForceSendFields: []string{"Trashed"}}).
+				SupportsAllDrives(true).
+				Fields("id, name, trashed").
+				Context(cmd.Context()).
+				Do()
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"file": updated})
+			}
+			u.Out().Printf("id\t%s", updated.Id)
+			u.Out().Printf("name\t%s", updated.Name)
+			u.Out().Printf("trashed\t%t", updated.Trashed)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&restore, "restore", false, "Restore the file from trash")
+	return cmd
+}
+
 func newDriveDeleteCmd(flags *rootFlags) *cobra.Command {
 	return &cobra.Command{
 		Use:   "delete <fileId>",
-		Short: "Delete a file (moves to trash)",
+		Short: "Permanently delete a file (skips trash; see: drive trash)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			u := ui.FromContext(cmd.Context())
@@ -571,11 +688,19 @@ func newDriveShareCmd(flags *rootFlags) *cobra.Command {
 	var email string
 	var role string
 	var discoverable bool
+	var recursive bool
 
 	cmd := &cobra.Command{
 		Use:   "share <fileId>",
 		Short: "Share a file or folder",
-		Args:  cobra.ExactArgs(1),
+		Long: `Share a file or folder.
+
+--recursive also adds the permission to everything inside a folder. Outside
+shared drives, Drive doesn't reliably cascade a new folder permission to
+existing children, so each descendant gets its own permission. Progress goes
+to stderr; failures are collected and summarized at the end (the command
+fails if any item could not be shared).`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			u := ui.FromContext(cmd.Context())
 			account, err := requireAccount(flags)
@@ -618,22 +743,46 @@ func newDriveShareCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
+			var tree *driveShareTreeResult
+			if recursive {
+				res, err := shareDriveTree(cmd.Context(), u, svc, fileID, perm, !outfmt.IsJSON(cmd.Context()))
+				if err != nil {
+					return err
+				}
+				tree = &res
+			}
+
 			link, err := driveWebLink(cmd.Context(), svc, fileID)
 			if err != nil {
 				return err
 			}
 
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				out := map[string]any{
 					"link":         link,
 					"permissionId": created.Id,
 					"permission":   created,
-				})
+				}
+				if tree != nil {
+					out["recursive"] = tree
+				}
+				if err := outfmt.WriteJSON(os.Stdout, out); err != nil {
+					return err
+				}
+				return driveShareTreeError(tree)
 			}
 
 			u.Out().Printf("link\t%s", link)
 			u.Out().Printf("permission_id\t%s", created.Id)
-			return nil
+			if tree != nil {
+				u.Out().Printf("descendants\t%d", tree.Total)
+				u.Out().Printf("shared\t%d", tree.Shared)
+				u.Out().Printf("failed\t%d", len(tree.Failed))
+				for _, f := range tree.Failed {
+					u.Err().Printf("FAILED\t%s\t%s\t%s", f.ID, f.Name, f.Error)
+				}
+			}
+			return driveShareTreeError(tree)
 		},
 	}
 
@@ -641,6 +790,7 @@ func newDriveShareCmd(flags *rootFlags) *cobra.Command {
 	cmd.Flags().StringVar(&email, "email", "", "Share with specific user")
 	cmd.Flags().StringVar(&role, "role", "reader", "Permission: reader|writer")
 	cmd.Flags().BoolVar(&discoverable, "discoverable", false, "Allow file discovery in search (anyone/domain only)")
+	cmd.Flags().BoolVar(&recursive, "recursive", false, "Also share every file and folder inside the folder")
 	return cmd
 }
 
@@ -690,6 +840,7 @@ func newDriveUnshareCmd(flags *rootFlags) *cobra.Command {
 func newDrivePermissionsCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "permissions <fileId>",
@@ -715,23 +866,33 @@ func newDrivePermissionsCmd(flags *rootFlags) *cobra.Command {
 			if max > 0 {
 				call = call.PageSize(max)
 			}
-			if strings.TrimSpace(page) != "" {
-				call = call.PageToken(page)
-			}
 
-			resp, err := call.Do()
+			perms, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*drive.Permission, string, error) {
+				if strings.TrimSpace(pageToken) != "" {
+					call = call.PageToken(pageToken)
+				}
+				resp, err := call.Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Permissions, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(perms))
 			if err != nil {
 				return err
 			}
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
 					"fileId":          fileID,
-					"permissions":     resp.Permissions,
-					"permissionCount": len(resp.Permissions),
-					"nextPageToken":   resp.NextPageToken,
-				})
+					"permissions":     perms,
+					"permissionCount": len(perms),
+					"nextPageToken":   nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
-			if len(resp.Permissions) == 0 {
+			if len(perms) == 0 {
 				u.Err().Println("No permissions")
 				return nil
 			}
@@ -739,20 +900,21 @@ func newDrivePermissionsCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "ID\tTYPE\tROLE\tEMAIL")
-			for _, p := range resp.Permissions {
+			for _, p := range perms {
 				email := p.EmailAddress
 				if email == "" {
 					email = "-"
 				}
 				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Id, p.Type, p.Role, email)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 100, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 	return cmd
 }
 
@@ -941,13 +1103,7 @@ func downloadDriveFile(ctx context.Context, svc *drive.Service, meta *drive.File
 		return "", 0, fmt.Errorf("download failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
 	}
 
-	f, err := os.Create(outPath)
-	if err != nil {
-		return "", 0, err
-	}
-	defer f.Close()
-
-	n, err := io.Copy(f, resp.Body)
+	n, err := saveDownload(ctx, "drive", outPath, resp.Body, 0o644)
 	if err != nil {
 		return "", 0, err
 	}
@@ -997,8 +1153,12 @@ func driveExportMimeTypeForFormat(googleMimeType string, format string) (string,
 			return "application/vnd.openxmlformats-officedocument.wordprocessingml.document", nil
 		case "txt":
 			return "text/plain", nil
+		case "md":
+			return "text/markdown", nil
+		case "html":
+			return "text/html", nil
 		default:
-			return "", fmt.Errorf("invalid --format %q for Google Doc (use pdf|docx|txt)", format)
+			return "", fmt.Errorf("invalid --format %q for Google Doc (use pdf|docx|txt|md|html)", format)
 		}
 	case "application/vnd.google-apps.spreadsheet":
 		switch format {
@@ -1053,6 +1213,10 @@ func driveExportExtension(mimeType string) string {
 		return ".png"
 	case "text/plain":
 		return ".txt"
+	case "text/markdown":
+		return ".md"
+	case "text/html":
+		return ".html"
 	default:
 		return ".pdf"
 	}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/drive/v3"
+	gapi "google.golang.org/api/googleapi"
+)
+
+const defaultDriveAppendRetries = 3
+
+// errDriveAppendConflict means the file got a new revision between our
+// download and upload.
+var errDriveAppendConflict = errors.New("drive append: file changed while appending")
+
+// errDriveAppendLostUpdate means another revision landed between the head
+// check and our upload, so the upload replaced it.
+var errDriveAppendLostUpdate = errors.New("drive append: another write landed during the upload and was overwritten")
+
+func newDriveAppendCmd(flags *rootFlags) *cobra.Command {
+	var lines []string
+	var retries int
+
+	cmd := &cobra.Command{
+		Use:   "append <fileId>",
+		Short: "Append lines to a text/CSV file as a new revision",
+		Long: `Append lines to a plain-text Drive file (text/*, CSV, JSON lines), uploading
+the result as a new revision. The file's headRevisionId is checked again
+right before the upload; if someone else wrote in between, the append is
+redone on top of their revision (up to --retries times). Drive has no
+conditional upload, so a write landing between that check and the upload
+can still be overwritten; the revision history is checked afterwards and
+such a lost update is reported with the revision to restore from (or a
+warning printed when the new revision is not listed yet).
+Google Docs files are not supported.`,
+		Example: `  gog drive append <fileId> --text "2026-10-16 backup ok"
+  gog drive append <fileId> --text "a,b,c" --text "d,e,f"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if len(lines) == 0 {
+				return usage("--text is required")
+			}
+			if retries < 0 {
+				return usage("--retries must be >= 0")
+			}
+
+			svc, err := newDriveService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			var updated *drive.File
+			for attempt := 0; ; attempt++ {
+				updated, err = appendDriveFile(cmd.Context(), svc, args[0], lines)
+				if !errors.Is(err, errDriveAppendConflict) || attempt >= retries {
+					break
+				}
+				u.Err().Printf("WARN: %v; retrying", err)
+			}
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"file": updated, "appended": len(lines)})
+			}
+			u.Out().Printf("id\t%s", updated.Id)
+			u.Out().Printf("name\t%s", updated.Name)
+			u.Out().Printf("revision\t%s", updated.HeadRevisionId)
+			u.Out().Printf("appended\t%d", len(lines))
+			u.Out().Printf("size\t%s", formatDriveSize(updated.Size))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&lines, "text", nil, "Line to append (repeatable)")
+	cmd.Flags().IntVar(&retries, "retries", defaultDriveAppendRetries, "Retries when the file changes during the append")
+	return cmd
+}
+
+// appendDriveFile downloads fileID, appends lines and uploads the result,
+// returning errDriveAppendConflict when the head revision moved before the
+// upload and errDriveAppendLostUpdate when it moved during it.
+func appendDriveFile(ctx context.Context, svc *drive.Service, fileID string, lines []string) (*drive.File, error) {
+	meta, err := svc.Files.Get(fileID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType, headRevisionId").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	if !driveAppendableMime(meta.MimeType) {
+		return nil, usagef("drive append supports text files only (file is %s)", meta.MimeType)
+	}
+
+	resp, err := driveDownload(ctx, svc, fileID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("download failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	newline := "\n"
+	if bytes.Contains(content, []byte("\r\n")) {
+		newline = "\r\n"
+	}
+	var buf bytes.Buffer
+	buf.Write(content)
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		buf.WriteString(newline)
+	}
+	for _, line := range lines {
+		buf.WriteString(line)
+		buf.WriteString(newline)
+	}
+
+	head, err := svc.Files.Get(fileID).SupportsAllDrives(true).Fields("headRevisionId").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if head.HeadRevisionId != meta.HeadRevisionId {
+		return nil, errDriveAppendConflict
+	}
+
+	updated, err := svc.Files.Update(fileID, &drive.File{}).
+		SupportsAllDrives(true).
+		Media(&buf, gapi.ContentType(meta.MimeType)).
+		Fields("id, name, mimeType, size, headRevisionId, modifiedTime").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+
+	// The upload went through either way; only a different predecessor
+	// proves a lost update. revisions.list can lag behind the new head, so
+	// an unlisted revision just stays unverified.
+	prev, listed, err := driveRevisionBefore(ctx, svc, fileID, updated.HeadRevisionId)
+	switch {
+	case err != nil || !listed:
+		if err == nil {
+			err = errors.New("not in the revision list yet")
+		}
+		if u := ui.FromContext(ctx); u != nil {
+			u.Err().Printf("WARN: could not verify revision %s was appended to %s: %v", updated.HeadRevisionId, meta.HeadRevisionId, err)
+		}
+	case prev != meta.HeadRevisionId:
+		return nil, fmt.Errorf("%w (appended to revision %s, but revision %s came in between; restore it from the revision history)",
+			errDriveAppendLostUpdate, meta.HeadRevisionId, orDash(prev))
+	}
+	return updated, nil
+}
+
+// driveRevisionBefore returns the ID of the revision preceding revisionID
+// ("" when it is the first); listed is false when revisionID is not listed.
+func driveRevisionBefore(ctx context.Context, svc *drive.Service, fileID, revisionID string) (prev string, listed bool, err error) {
+	last, pageToken := "", ""
+	for {
+		call := svc.Revisions.List(fileID).
+			Fields("nextPageToken, revisions(id)").
+			PageSize(1000).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return "", false, err
+		}
+		for _, r := range resp.Revisions {
+			if r.Id == revisionID {
+				return last, true, nil
+			}
+			last = r.Id
+		}
+		if resp.NextPageToken == "" {
+			return "", false, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func driveAppendableMime(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	if strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	switch mimeType {
+	case "application/csv", "application/json", "application/x-ndjson", "application/jsonl", "application/x-yaml":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_DriveAppend_RetriesOnConflict(t *testing.T) {
+	origNew, origDownload := newDriveService, driveDownload
+	t.Cleanup(func() {
+		newDriveService, driveDownload = origNew, origDownload
+	})
+
+	// The first head check sees a revision written by someone else; the
+	// second attempt downloads that revision and appends on top of it.
+	revs := []string{"r1", "r2", "r2", "r2"}
+	gets := 0
+	var uploaded string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/upload/drive/v3/files/log1") && r.Method == http.MethodPatch:
+			body, _ := io.ReadAll(r.Body)
+			uploaded = string(body)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "log1", "name": "log.csv", "headRevisionId": "r3", "size": "20"})
+		case strings.HasSuffix(r.URL.Path, "/files/log1/revisions"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"revisions": []map[string]any{{"id": "r1"}, {"id": "r2"}, {"id": "r3"}}})
+		case strings.Contains(r.URL.Path, "/files/log1") && r.Method == http.MethodGet:
+			rev := revs[min(gets, len(revs)-1)]
+			gets++
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "log1", "name": "log.csv", "mimeType": "text/csv", "headRevisionId": rev})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+	downloads := 0
+	driveDownload = func(context.Context, *drive.Service, string) (*http.Response, error) {
+		downloads++
+		return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(strings.NewReader("a,b\nc,d"))}, nil
+	}
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "drive", "append", "log1", "--text", "e,f", "--text", "g,h"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if downloads != 2 {
+		t.Fatalf("downloads = %d, want 2 (one retry)", downloads)
+	}
+	if !strings.Contains(uploaded, "a,b\nc,d\ne,f\ng,h\n") {
+		t.Fatalf("uploaded = %q", uploaded)
+	}
+	var parsed struct {
+		File     drive.File `json:"file"`
+		Appended int        `json:"appended"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if parsed.File.HeadRevisionId != "r3" || parsed.Appended != 2 {
+		t.Fatalf("out = %+v", parsed)
+	}
+}
+
+func TestExecute_DriveAppend_LostUpdate(t *testing.T) {
+	origNew, origDownload := newDriveService, driveDownload
+	t.Cleanup(func() {
+		newDriveService, driveDownload = origNew, origDownload
+	})
+
+	// Both head checks see r1, but r2 landed before our upload became r3.
+	revisions := []map[string]any{{"id": "r1"}, {"id": "r2"}, {"id": "r3"}}
+	uploads := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/upload/drive/v3/files/log1") && r.Method == http.MethodPatch:
+			uploads++
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "log1", "name": "log.csv", "headRevisionId": "r3"})
+		case strings.HasSuffix(r.URL.Path, "/files/log1/revisions"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"revisions": revisions})
+		case strings.Contains(r.URL.Path, "/files/log1") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "log1", "name": "log.csv", "mimeType": "text/plain", "headRevisionId": "r1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+	driveDownload = func(context.Context, *drive.Service, string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(strings.NewReader("a\n"))}, nil
+	}
+
+	_ = captureStderr(t, func() {
+		err = Execute([]string{"--account", "a@b.com", "drive", "append", "log1", "--text", "b"})
+	})
+	if !errors.Is(err, errDriveAppendLostUpdate) || !strings.Contains(err.Error(), "revision r2") {
+		t.Fatalf("err = %v, want lost update naming r2", err)
+	}
+	if uploads != 1 {
+		t.Fatalf("uploads = %d, want 1 (no retry after a lost update)", uploads)
+	}
+
+	// A revision list lagging behind the upload only warns.
+	revisions = []map[string]any{{"id": "r1"}}
+	errOut := captureStderr(t, func() {
+		_ = captureStdout(t, func() {
+			err = Execute([]string{"--account", "a@b.com", "drive", "append", "log1", "--text", "b"})
+		})
+	})
+	if err != nil || !strings.Contains(errOut, "could not verify revision r3") {
+		t.Fatalf("err = %v, stderr = %q", err, errOut)
+	}
+}
+
+func TestDriveAppendableMime(t *testing.T) {
+	for mime, want := range map[string]bool{
+		"text/plain":           true,
+		"text/csv":             true,
+		"application/x-ndjson": true,
+		"application/vnd.google-apps.spreadsheet": false,
+		"application/pdf":                         false,
+	} {
+		if got := driveAppendableMime(mime); got != want {
+			t.Fatalf("%s = %v, want %v", mime, got, want)
+		}
+	}
+}
@@ -46,6 +46,18 @@ func TestDriveExportMimeTypeForFormat(t *testing.T) {
 			format:     "txt",
 			wantMime:   "text/plain",
 		},
+		{
+			name:       "doc_md",
+			googleMime: "application/vnd.google-apps.document",
+			format:     "md",
+			wantMime:   "text/markdown",
+		},
+		{
+			name:       "doc_html",
+			googleMime: "application/vnd.google-apps.document",
+			format:     "html",
+			wantMime:   "text/html",
+		},
 		{
 			name:        "doc_invalid",
 			googleMime:  "application/vnd.google-apps.document",
@@ -147,3 +147,65 @@ func TestDriveLsCmd_TextAndJSON(t *testing.T) {
 		t.Fatalf("expected TSV header, got: %q", plainOut)
 	}
 }
+
+func TestExecute_DriveLs_NDJSON(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	var pageSizes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || (r.URL.Path != "/drive/v3/files" && r.URL.Path != "/files") {
+			http.NotFound(w, r)
+			return
+		}
+		pageSizes = append(pageSizes, r.URL.Query().Get("pageSize"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("pageToken") == "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"files":         []map[string]any{{"id": "f1", "name": "One"}, {"id": "f2", "name": "Two"}},
+				"nextPageToken": "p2",
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"files":         []map[string]any{{"id": "f3", "name": "Three"}},
+			"nextPageToken": "p3",
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	var errOut string
+	out := captureStdout(t, func() {
+		errOut = captureStderr(t, func() {
+			if err := Execute([]string{"--output", "ndjson", "--account", "a@b.com", "drive", "ls", "--max", "3"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", out)
+	}
+	for i, id := range []string{"f1", "f2", "f3"} {
+		var f drive.File
+		if err := json.Unmarshal([]byte(lines[i]), &f); err != nil || f.Id != id {
+			t.Fatalf("line %d: %q (%v)", i, lines[i], err)
+		}
+	}
+	if strings.Join(pageSizes, ",") != "3,1" {
+		t.Fatalf("unexpected page sizes %v", pageSizes)
+	}
+	if !strings.Contains(errOut, "--page p3") {
+		t.Fatalf("expected next page hint, got %q", errOut)
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/drive/v3"
+)
+
+const driveFolderMimeType = "application/vnd.google-apps.folder"
+
+// driveShareFailure is one descendant drive share --recursive couldn't share.
+type driveShareFailure struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// driveShareTreeResult summarizes drive share --recursive.
+type driveShareTreeResult struct {
+	Total  int                 `json:"total"`
+	Shared int                 `json:"shared"`
+	Failed []driveShareFailure `json:"failed,omitempty"`
+}
+
+// listDriveDescendants walks folderID breadth-first and returns every file
+// and folder below it (trashed items excluded).
+func listDriveDescendants(ctx context.Context, svc *drive.Service, folderID string) ([]*drive.File, error) {
+	var out []*drive.File
+	queue := []string{folderID}
+	seen := map[string]bool{folderID: true}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		page := ""
+		for {
+			resp, err := svc.Files.List().
+				Q(fmt.Sprintf("'%s' in parents and trashed = false", escapeDriveQueryString(parent))).
+				PageSize(1000).
+				PageToken(page).
+				SupportsAllDrives(true).
+				IncludeItemsFromAllDrives(true).
+				Fields("nextPageToken, files(id, name, mimeType)").
+				Context(ctx).
+				Do()
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range resp.Files {
+				// A file can sit in several folders of the tree.
+				if f == nil || seen[f.Id] {
+					continue
+				}
+				seen[f.Id] = true
+				out = append(out, f)
+				if f.MimeType == driveFolderMimeType {
+					queue = append(queue, f.Id)
+				}
+			}
+			if resp.NextPageToken == "" {
+				break
+			}
+			page = resp.NextPageToken
+		}
+	}
+	return out, nil
+}
+
+// shareDriveTree adds perm to every descendant of folderID, reporting
+// progress on stderr and collecting failures instead of stopping at the
+// first one.
+func shareDriveTree(ctx context.Context, u *ui.UI, svc *drive.Service, folderID string, perm *drive.Permission, progress bool) (driveShareTreeResult, error) {
+	files, err := listDriveDescendants(ctx, svc, folderID)
+	if err != nil {
+		return driveShareTreeResult{}, err
+	}
+	res := driveShareTreeResult{Total: len(files)}
+	for i, f := range files {
+		p := *perm
+		_, err := svc.Permissions.Create(f.Id, &p).
+			SupportsAllDrives(true).
+			SendNotificationEmail(false).
+			Fields("id").
+			Context(ctx).
+			Do()
+		if err != nil {
+			if ctx.Err() != nil {
+				return res, ctx.Err()
+			}
+			res.Failed = append(res.Failed, driveShareFailure{ID: f.Id, Name: f.Name, Error: err.Error()})
+		} else {
+			res.Shared++
+		}
+		if progress && u != nil {
+			u.Err().Printf("shared %d/%d\t%s", i+1, len(files), f.Name)
+		}
+	}
+	return res, nil
+}
+
+func driveShareTreeError(res *driveShareTreeResult) error {
+	if res == nil || len(res.Failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("failed to share %d of %d items", len(res.Failed), res.Total)
+}
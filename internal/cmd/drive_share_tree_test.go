@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_DriveShareRecursive(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	folder := map[string]any{"id": "sub", "name": "Sub", "mimeType": driveFolderMimeType}
+	children := map[string][]map[string]any{
+		"root1": {folder, {"id": "b", "name": "b.txt", "mimeType": "text/plain"}},
+		// b also lives in the subfolder; it is shared once.
+		"sub": {{"id": "c", "name": "c.txt", "mimeType": "text/plain"}, {"id": "b", "name": "b.txt", "mimeType": "text/plain"}},
+	}
+	shared := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/permissions") && r.Method == http.MethodPost:
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path[strings.Index(r.URL.Path, "/files/"):], "/files/"), "/permissions")
+			if id == "c" {
+				w.WriteHeader(http.StatusForbidden)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 403, "message": "insufficientFilePermissions"}})
+				return
+			}
+			shared[id]++
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "p-" + id, "type": "user", "role": "reader"})
+		case strings.HasSuffix(r.URL.Path, "/files") && r.Method == http.MethodGet:
+			q := r.URL.Query().Get("q")
+			parent := q[1 : strings.Index(q[1:], "'")+1]
+			_ = json.NewEncoder(w).Encode(map[string]any{"files": children[parent]})
+		case strings.Contains(r.URL.Path, "/files/root1") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "root1", "webViewLink": "https://drive.example/root1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	var execErr error
+	out := captureStdout(t, func() {
+		execErr = Execute([]string{"--json", "--account", "a@b.com", "drive", "share", "root1", "--email", "x@example.com", "--recursive"})
+	})
+	if execErr == nil || !strings.Contains(execErr.Error(), "1 of 3") {
+		t.Fatalf("err = %v, want failure summary", execErr)
+	}
+	var parsed struct {
+		Recursive driveShareTreeResult `json:"recursive"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	res := parsed.Recursive
+	if res.Total != 3 || res.Shared != 2 || len(res.Failed) != 1 || res.Failed[0].ID != "c" {
+		t.Fatalf("result = %+v", res)
+	}
+	if shared["root1"] != 1 || shared["sub"] != 1 || shared["b"] != 1 {
+		t.Fatalf("shared = %v", shared)
+	}
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/drive/v3"
+)
+
+const defaultDriveWatchInterval = 30 * time.Second
+
+// driveMirrorResult is one refresh check of `drive watch`.
+type driveMirrorResult struct {
+	ID       string `json:"id"`
+	Path     string `json:"path"`
+	Modified string `json:"modified"`
+	Version  int64  `json:"version,omitempty"`
+	Updated  bool   `json:"updated"`
+	Size     int64  `json:"size,omitempty"`
+}
+
+func newDriveWatchCmd(flags *rootFlags) *cobra.Command {
+	var outPath string
+	var format string
+	var follow bool
+	var interval time.Duration
+	var notify desktopNotifyFlag
+
+	cmd := &cobra.Command{
+		Use:   "watch <fileId>",
+		Short: "Keep a local mirror of a Drive file up to date",
+		Long: `Download (or export) a Drive file to --out whenever it changes. The file's
+modifiedTime is polled and copied onto the local mirror's mtime, so a plain
+run (e.g. from cron) only downloads when the Drive copy is newer; --follow
+keeps polling every --interval until interrupted. Mirrors are replaced
+atomically, so readers never see a partial file.
+
+Google Docs formats are exported using --format, or the --out extension
+(local.xlsx exports a Sheet as xlsx). With --follow, --notify-desktop raises
+a desktop notification whenever the mirror is refreshed.`,
+		Example: `  gog drive watch <fileId> --out local.xlsx --follow
+  gog drive watch <fileId> --out notes.md --follow --interval 2m`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			outPath = strings.TrimSpace(outPath)
+			if outPath == "" {
+				return usage("--out is required")
+			}
+			if st, statErr := os.Stat(outPath); statErr == nil && st.IsDir() {
+				return usage("--out must be a file path, not a directory")
+			}
+			if interval <= 0 {
+				return usage("--interval must be > 0")
+			}
+			if notify.enabled && !follow {
+				return usage("--notify-desktop requires --follow")
+			}
+			notifier, err := notify.notifier(u)
+			if err != nil {
+				return err
+			}
+
+			svc, err := newDriveService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			fileID := args[0]
+			report := func(res driveMirrorResult) error {
+				if outfmt.IsJSON(cmd.Context()) {
+					return outfmt.WriteNDJSON(os.Stdout, res)
+				}
+				state := "unchanged"
+				if res.Updated {
+					state = "updated"
+				}
+				u.Out().Printf("%s\t%s\t%s", state, res.Path, res.Modified)
+				return nil
+			}
+
+			res, err := refreshDriveMirror(cmd.Context(), svc, fileID, outPath, format)
+			if err != nil {
+				return err
+			}
+			if !follow || res.Updated {
+				if err := report(res); err != nil {
+					return err
+				}
+			}
+			if !follow {
+				return nil
+			}
+
+			ctx := cmd.Context()
+			for {
+				if err := watchDaemonSleep(ctx, interval); err != nil {
+					return nil
+				}
+				res, err := refreshDriveMirror(ctx, svc, fileID, outPath, format)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					u.Err().Printf("WARN: drive watch: %v", err)
+					continue
+				}
+				if res.Updated {
+					if err := report(res); err != nil {
+						return err
+					}
+					notifier.notify(ctx, "Drive file updated", filepath.Base(res.Path), map[string]string{
+						"id":   res.ID,
+						"path": res.Path,
+					})
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&outPath, "out", "", "Local mirror path (required)")
+	cmd.Flags().StringVar(&format, "format", "", "Export format for Google Docs files (default: from --out extension, else auto)")
+	cmd.Flags().BoolVar(&follow, "follow", false, "Keep polling and refresh the mirror on every change")
+	cmd.Flags().DurationVar(&interval, "interval", defaultDriveWatchInterval, "Poll interval with --follow")
+	notify.addFlags(cmd, "id,path")
+	return cmd
+}
+
+// refreshDriveMirror downloads fileID to outPath unless the mirror's mtime
+// already matches the Drive modifiedTime.
+func refreshDriveMirror(ctx context.Context, svc *drive.Service, fileID, outPath, format string) (driveMirrorResult, error) {
+	meta, err := svc.Files.Get(fileID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType, modifiedTime, version").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return driveMirrorResult{}, err
+	}
+	modified, err := time.Parse(time.RFC3339, meta.ModifiedTime)
+	if err != nil {
+		return driveMirrorResult{}, errors.New("file has no modifiedTime")
+	}
+
+	isGoogleDoc := strings.HasPrefix(meta.MimeType, "application/vnd.google-apps.")
+	if isGoogleDoc && strings.TrimSpace(format) == "" {
+		format = driveFormatFromPath(meta.MimeType, outPath)
+	}
+
+	finalPath := outPath
+	if isGoogleDoc {
+		exportMimeType, err := driveExportMimeTypeForFormat(meta.MimeType, format)
+		if err != nil {
+			return driveMirrorResult{}, err
+		}
+		finalPath = replaceExt(outPath, driveExportExtension(exportMimeType))
+	}
+
+	res := driveMirrorResult{ID: meta.Id, Path: finalPath, Modified: meta.ModifiedTime, Version: meta.Version}
+	if st, err := os.Stat(finalPath); err == nil && st.ModTime().Equal(modified) {
+		return res, nil
+	}
+
+	dir := filepath.Dir(finalPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return res, err
+	}
+	tmp, err := os.CreateTemp(dir, ".gog-watch-*"+filepath.Ext(finalPath))
+	if err != nil {
+		return res, err
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+	defer os.Remove(tmpPath)
+
+	written, size, err := downloadDriveFile(ctx, svc, meta, tmpPath, format)
+	if err != nil {
+		return res, err
+	}
+	if written != tmpPath {
+		defer os.Remove(written)
+	}
+	if err := os.Chtimes(written, modified, modified); err != nil {
+		return res, err
+	}
+	if err := os.Rename(written, finalPath); err != nil {
+		return res, err
+	}
+	res.Updated = true
+	res.Size = size
+	return res, nil
+}
+
+// driveFormatFromPath picks an export format from the mirror's extension
+// when it is valid for the Google Docs type; otherwise the default is used.
+func driveFormatFromPath(googleMimeType, path string) string {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if format == "" {
+		return ""
+	}
+	if _, err := driveExportMimeTypeForFormat(googleMimeType, format); err != nil {
+		return ""
+	}
+	return format
+}
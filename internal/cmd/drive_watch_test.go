@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_DriveWatch_RefreshesOnlyWhenChanged(t *testing.T) {
+	origNew, origExport := newDriveService, driveExportDownload
+	t.Cleanup(func() {
+		newDriveService, driveExportDownload = origNew, origExport
+	})
+
+	modified := "2026-01-02T03:04:05Z"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/files/sheet1") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":           "sheet1",
+			"name":         "Budget",
+			"mimeType":     "application/vnd.google-apps.spreadsheet",
+			"modifiedTime": modified,
+			"version":      "7",
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	exports := 0
+	var exportMime string
+	driveExportDownload = func(_ context.Context, _ *drive.Service, _ string, mimeType string) (*http.Response, error) {
+		exports++
+		exportMime = mimeType
+		return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(strings.NewReader("xlsx-bytes"))}, nil
+	}
+
+	outPath := filepath.Join(t.TempDir(), "mirror", "local.xlsx")
+	run := func() driveMirrorResult {
+		t.Helper()
+		out := captureStdout(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "drive", "watch", "sheet1", "--out", outPath}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+		var res driveMirrorResult
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("json: %v\n%s", err, out)
+		}
+		return res
+	}
+
+	if res := run(); !res.Updated || res.Path != outPath || res.Version != 7 {
+		t.Fatalf("first run = %+v", res)
+	}
+	if exportMime != "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" {
+		t.Fatalf("export mime = %q", exportMime)
+	}
+	st, err := os.Stat(outPath)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if want, _ := time.Parse(time.RFC3339, modified); !st.ModTime().Equal(want) {
+		t.Fatalf("mtime = %v, want %v", st.ModTime(), want)
+	}
+
+	if res := run(); res.Updated || exports != 1 {
+		t.Fatalf("second run = %+v (exports %d), want unchanged", res, exports)
+	}
+
+	modified = "2026-01-02T04:00:00Z"
+	if res := run(); !res.Updated || exports != 2 {
+		t.Fatalf("third run = %+v (exports %d), want updated", res, exports)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(outPath))
+	if len(entries) != 1 {
+		t.Fatalf("leftover temp files: %v", entries)
+	}
+}
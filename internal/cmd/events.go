@@ -0,0 +1,457 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/pubsub/v1"
+	"google.golang.org/api/workspaceevents/v1"
+)
+
+var newWorkspaceEventsService = googleapi.NewWorkspaceEvents
+
+const (
+	eventsOperationWait = 10 * time.Second
+	eventsOperationPoll = time.Second
+)
+
+// eventsTarget is a resource Workspace Events can subscribe to.
+type eventsTarget struct {
+	Kind     string // chat|drive|meet
+	Resource string // full resource name, e.g. //chat.googleapis.com/spaces/AAA
+}
+
+// eventsKinds maps target kinds to their API host, collection, read scope
+// and default event types (Drive has none; pass --event-types).
+var eventsKinds = map[string]struct {
+	host, collection, scope string
+	defaultTypes            []string
+}{
+	"chat":  {"chat.googleapis.com", "spaces", workspaceevents.ChatMessagesReadonlyScope, []string{"google.workspace.chat.message.v1.created"}},
+	"drive": {"drive.googleapis.com", "files", workspaceevents.DriveReadonlyScope, nil},
+	"meet":  {"meet.googleapis.com", "spaces", workspaceevents.MeetingsSpaceReadonlyScope, []string{"google.workspace.meet.conference.v2.started", "google.workspace.meet.conference.v2.ended"}},
+}
+
+// parseEventsTarget accepts chat:<spaceId>, drive:<fileId>, meet:<spaceId>,
+// spaces/<id> (Chat) or a full //<api>.googleapis.com/... resource name.
+func parseEventsTarget(raw string) (eventsTarget, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "//") {
+		host, _, _ := strings.Cut(strings.TrimPrefix(raw, "//"), "/")
+		for kind, k := range eventsKinds {
+			if host == k.host {
+				return eventsTarget{Kind: kind, Resource: raw}, nil
+			}
+		}
+		return eventsTarget{}, usagef("unsupported target %q (chat, drive or meet resources)", raw)
+	}
+	if id, ok := strings.CutPrefix(raw, "spaces/"); ok && id != "" {
+		raw = "chat:" + id
+	}
+	kind, id, ok := strings.Cut(raw, ":")
+	k, known := eventsKinds[strings.ToLower(kind)]
+	id = strings.TrimSpace(id)
+	if !ok || !known || id == "" {
+		return eventsTarget{}, usagef("invalid target %q (expected chat:<spaceId>, drive:<fileId> or meet:<spaceId>)", raw)
+	}
+	return eventsTarget{Kind: strings.ToLower(kind), Resource: "//" + k.host + "/" + k.collection + "/" + id}, nil
+}
+
+// eventsKindForTypes infers the target kind from event type names.
+func eventsKindForTypes(types []string) string {
+	for _, t := range types {
+		for kind := range eventsKinds {
+			if strings.HasPrefix(t, "google.workspace."+kind+".") {
+				return kind
+			}
+		}
+	}
+	return ""
+}
+
+func newEventsCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Workspace Events subscriptions (Chat, Drive, Meet) delivered via Pub/Sub",
+		Long: `Subscribe to changes on a Chat space, Drive file or Meet space with the
+Google Workspace Events API. Events are published to a Pub/Sub topic you own
+(grant the Workspace Events service account publish rights); "events tail"
+reads them back from a pull subscription on that topic.`,
+	}
+	cmd.AddCommand(newEventsSubscribeCmd(flags))
+	cmd.AddCommand(newEventsListCmd(flags))
+	cmd.AddCommand(newEventsDeleteCmd(flags))
+	cmd.AddCommand(newEventsTailCmd())
+	return cmd
+}
+
+func newEventsSubscribeCmd(flags *rootFlags) *cobra.Command {
+	var target string
+	var topic string
+	var eventTypes []string
+	var ttl time.Duration
+	var includeResource bool
+
+	cmd := &cobra.Command{
+		Use:   "subscribe",
+		Short: "Create a subscription for a Chat space, Drive file or Meet space",
+		Long: `Create a Workspace Events subscription that publishes to --topic.
+
+--target is chat:<spaceId>, drive:<fileId>, meet:<spaceId> or a full resource
+name. Chat defaults to new messages and Meet to conference start/end;
+Drive needs --event-types. --ttl 0 asks for the longest lifetime the API
+allows; renew with another subscribe before it expires.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			t, err := parseEventsTarget(target)
+			if err != nil {
+				return err
+			}
+			topic = strings.TrimSpace(topic)
+			if !strings.HasPrefix(topic, "projects/") || !strings.Contains(topic, "/topics/") {
+				return usage("--topic must be projects/<project>/topics/<topic>")
+			}
+			if len(eventTypes) == 0 {
+				eventTypes = eventsKinds[t.Kind].defaultTypes
+			}
+			if len(eventTypes) == 0 {
+				return usagef("--event-types is required for %s targets", t.Kind)
+			}
+			if ttl < 0 {
+				return usage("--ttl must be >= 0")
+			}
+
+			svc, err := newWorkspaceEventsService(cmd.Context(), account, []string{eventsKinds[t.Kind].scope})
+			if err != nil {
+				return err
+			}
+			sub := &workspaceevents.Subscription{
+				TargetResource:       t.Resource,
+				EventTypes:           eventTypes,
+				NotificationEndpoint: &workspaceevents.NotificationEndpoint{PubsubTopic: topic},
+				Ttl:                  fmt.Sprintf("%ds", int64(ttl/time.Second)),
+				ForceSendFields:      []string{"Ttl"},
+			}
+			if includeResource {
+				sub.PayloadOptions = &workspaceevents.PayloadOptions{IncludeResource: true}
+			}
+			op, err := svc.Subscriptions.Create(sub).Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			created, err := waitEventsOperation(cmd.Context(), svc, op)
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				if created == nil {
+					return outfmt.WriteJSON(os.Stdout, map[string]any{"operation": op.Name, "done": false})
+				}
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"subscription": created})
+			}
+			if created == nil {
+				u.Out().Printf("operation\t%s", op.Name)
+				u.Err().Println("Subscription is still being created; check with gog events list")
+				return nil
+			}
+			printEventsSubscription(u, created)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&target, "target", "", "chat:<spaceId>, drive:<fileId>, meet:<spaceId> or a full resource name")
+	cmd.Flags().StringVar(&topic, "topic", "", "Pub/Sub topic for events (projects/<p>/topics/<t>)")
+	cmd.Flags().StringSliceVar(&eventTypes, "event-types", nil, "Event types (comma-separated; default depends on --target)")
+	cmd.Flags().DurationVar(&ttl, "ttl", 0, "Subscription lifetime (0: the maximum allowed)")
+	cmd.Flags().BoolVar(&includeResource, "include-resource", false, "Include the changed resource in event payloads")
+	_ = cmd.MarkFlagRequired("target")
+	_ = cmd.MarkFlagRequired("topic")
+	return cmd
+}
+
+// waitEventsOperation waits briefly for a long-running subscription
+// operation; nil means it is still running.
+func waitEventsOperation(ctx context.Context, svc *workspaceevents.Service, op *workspaceevents.Operation) (*workspaceevents.Subscription, error) {
+	deadline := time.Now().Add(eventsOperationWait)
+	for !op.Done {
+		if time.Now().After(deadline) {
+			return nil, nil
+		}
+		if err := watchDaemonSleep(ctx, eventsOperationPoll); err != nil {
+			return nil, err
+		}
+		next, err := svc.Operations.Get(op.Name).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		op = next
+	}
+	if op.Error != nil {
+		return nil, fmt.Errorf("%s (code %d)", op.Error.Message, op.Error.Code)
+	}
+	var sub workspaceevents.Subscription
+	if len(op.Response) > 0 {
+		if err := json.Unmarshal(op.Response, &sub); err != nil {
+			return nil, err
+		}
+	}
+	return &sub, nil
+}
+
+func printEventsSubscription(u *ui.UI, s *workspaceevents.Subscription) {
+	u.Out().Printf("name\t%s", s.Name)
+	u.Out().Printf("target\t%s", s.TargetResource)
+	u.Out().Printf("event_types\t%s", strings.Join(s.EventTypes, ","))
+	if s.NotificationEndpoint != nil {
+		u.Out().Printf("topic\t%s", s.NotificationEndpoint.PubsubTopic)
+	}
+	u.Out().Printf("state\t%s", s.State)
+	if s.ExpireTime != "" {
+		u.Out().Printf("expires\t%s", s.ExpireTime)
+	}
+}
+
+func newEventsListCmd(flags *rootFlags) *cobra.Command {
+	var target string
+	var eventTypes []string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List subscriptions for a target or event types",
+		Long: `List Workspace Events subscriptions. The API filters by event type, so
+pass --event-types, or --target to use that target's default types.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			var t eventsTarget
+			if strings.TrimSpace(target) != "" {
+				if t, err = parseEventsTarget(target); err != nil {
+					return err
+				}
+				if len(eventTypes) == 0 {
+					eventTypes = eventsKinds[t.Kind].defaultTypes
+				}
+			}
+			if len(eventTypes) == 0 {
+				return usage("specify --event-types (or a --target with default types)")
+			}
+			kind := t.Kind
+			if kind == "" {
+				kind = eventsKindForTypes(eventTypes)
+			}
+			k, ok := eventsKinds[kind]
+			if !ok {
+				return usagef("cannot tell the API of event types %s", strings.Join(eventTypes, ","))
+			}
+
+			parts := make([]string, 0, len(eventTypes))
+			for _, et := range eventTypes {
+				parts = append(parts, fmt.Sprintf("event_types:%q", et))
+			}
+			filter := strings.Join(parts, " OR ")
+			if t.Resource != "" {
+				filter = fmt.Sprintf("(%s) AND target_resource=%q", filter, t.Resource)
+			}
+
+			svc, err := newWorkspaceEventsService(cmd.Context(), account, []string{k.scope})
+			if err != nil {
+				return err
+			}
+			var subs []*workspaceevents.Subscription
+			err = svc.Subscriptions.List().Filter(filter).Pages(cmd.Context(), func(resp *workspaceevents.ListSubscriptionsResponse) error {
+				subs = append(subs, resp.Subscriptions...)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"subscriptions": subs})
+			}
+			if len(subs) == 0 {
+				u.Err().Println("No subscriptions")
+				return nil
+			}
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "NAME\tTARGET\tSTATE\tEXPIRES\tEVENT_TYPES")
+			for _, s := range subs {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Name, s.TargetResource, s.State, s.ExpireTime, strings.Join(s.EventTypes, ","))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&target, "target", "", "chat:<spaceId>, drive:<fileId>, meet:<spaceId> or a full resource name")
+	cmd.Flags().StringSliceVar(&eventTypes, "event-types", nil, "Event types to list subscriptions for (comma-separated)")
+	return cmd
+}
+
+func newEventsDeleteCmd(flags *rootFlags) *cobra.Command {
+	var kind string
+
+	cmd := &cobra.Command{
+		Use:   "delete <subscription>",
+		Short: "Delete a subscription (subscriptions/<id>)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSpace(args[0])
+			if !strings.HasPrefix(name, "subscriptions/") {
+				name = "subscriptions/" + name
+			}
+			k, ok := eventsKinds[strings.ToLower(kind)]
+			if !ok {
+				return usagef("invalid --kind %q (expected chat|drive|meet)", kind)
+			}
+			if err := confirmDestructive(cmd, flags, fmt.Sprintf("delete events subscription %s", name)); err != nil {
+				return err
+			}
+			svc, err := newWorkspaceEventsService(cmd.Context(), account, []string{k.scope})
+			if err != nil {
+				return err
+			}
+			if _, err := svc.Subscriptions.Delete(name).Context(cmd.Context()).Do(); err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"deleted": true, "name": name})
+			}
+			u.Out().Printf("deleted\t%s", name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&kind, "kind", "chat", "Target kind of the subscription, for authorization: chat|drive|meet")
+	return cmd
+}
+
+// workspaceEvent is one NDJSON line of `events tail`: the CloudEvents
+// attributes Pub/Sub carries plus the JSON payload.
+type workspaceEvent struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type,omitempty"`
+	Source  string          `json:"source,omitempty"`
+	Subject string          `json:"subject,omitempty"`
+	Time    string          `json:"time,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func newEventsTailCmd() *cobra.Command {
+	var subscription string
+	var once bool
+
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Print events from a Pub/Sub pull subscription as JSON lines",
+		Long: `Pull a Pub/Sub subscription on the events topic and print each event as
+one JSON line ({id, type, source, subject, time, data}), acknowledging it
+once printed. Runs until interrupted; --once prints one batch and exits.
+Pub/Sub is reached with Application Default Credentials.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			subscription = strings.TrimSpace(subscription)
+			if !strings.HasPrefix(subscription, "projects/") || !strings.Contains(subscription, "/subscriptions/") {
+				return usage("--subscription must be projects/<project>/subscriptions/<subscription>")
+			}
+			psvc, err := newPubSubService(cmd.Context())
+			if err != nil {
+				return err
+			}
+			return tailWorkspaceEvents(cmd.Context(), psvc, subscription, once)
+		},
+	}
+
+	cmd.Flags().StringVar(&subscription, "subscription", "", "Pub/Sub pull subscription (projects/<p>/subscriptions/<s>)")
+	cmd.Flags().BoolVar(&once, "once", false, "Pull one batch, print it, and exit")
+	return cmd
+}
+
+func tailWorkspaceEvents(ctx context.Context, psvc *pubsub.Service, subscription string, once bool) error {
+	u := ui.FromContext(ctx)
+	backoff := notifyPullRetryMin
+	for {
+		resp, err := psvc.Projects.Subscriptions.Pull(subscription, &pubsub.PullRequest{MaxMessages: defaultNotifyPullMax}).Context(ctx).Do()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if once {
+				return err
+			}
+			if u != nil {
+				u.Err().Printf("WARN: events: pull failed (retrying in %s): %v", backoff, err)
+			}
+			if err := watchDaemonSleep(ctx, backoff); err != nil {
+				return nil
+			}
+			backoff = min(backoff*2, notifyPullRetryMax)
+			continue
+		}
+		backoff = notifyPullRetryMin
+
+		var ack []string
+		for _, rm := range resp.ReceivedMessages {
+			if rm == nil || rm.Message == nil {
+				continue
+			}
+			if err := outfmt.WriteNDJSON(os.Stdout, workspaceEventFrom(rm.Message)); err != nil {
+				return err
+			}
+			ack = append(ack, rm.AckId)
+		}
+		if len(ack) > 0 {
+			if _, err := psvc.Projects.Subscriptions.Acknowledge(subscription, &pubsub.AcknowledgeRequest{AckIds: ack}).Context(ctx).Do(); err != nil && ctx.Err() == nil && u != nil {
+				u.Err().Printf("WARN: events: acknowledge failed: %v", err)
+			}
+		}
+		if once {
+			return nil
+		}
+	}
+}
+
+func workspaceEventFrom(m *pubsub.PubsubMessage) workspaceEvent {
+	ev := workspaceEvent{
+		ID:      m.Attributes["ce-id"],
+		Type:    m.Attributes["ce-type"],
+		Source:  m.Attributes["ce-source"],
+		Subject: m.Attributes["ce-subject"],
+		Time:    m.Attributes["ce-time"],
+	}
+	if ev.ID == "" {
+		ev.ID = m.MessageId
+	}
+	if data, err := base64.StdEncoding.DecodeString(m.Data); err == nil && len(data) > 0 {
+		if json.Valid(data) {
+			ev.Data = data
+		} else {
+			ev.Data, _ = json.Marshal(string(data))
+		}
+	}
+	return ev
+}
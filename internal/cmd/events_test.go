@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/pubsub/v1"
+	"google.golang.org/api/workspaceevents/v1"
+)
+
+func TestParseEventsTarget(t *testing.T) {
+	cases := map[string]eventsTarget{
+		"chat:AAA":                       {Kind: "chat", Resource: "//chat.googleapis.com/spaces/AAA"},
+		"spaces/AAA":                     {Kind: "chat", Resource: "//chat.googleapis.com/spaces/AAA"},
+		"drive:file1":                    {Kind: "drive", Resource: "//drive.googleapis.com/files/file1"},
+		"Meet:abc-defg-hij":              {Kind: "meet", Resource: "//meet.googleapis.com/spaces/abc-defg-hij"},
+		"//meet.googleapis.com/spaces/x": {Kind: "meet", Resource: "//meet.googleapis.com/spaces/x"},
+	}
+	for in, want := range cases {
+		got, err := parseEventsTarget(in)
+		if err != nil || got != want {
+			t.Errorf("parseEventsTarget(%q) = %#v, %v", in, got, err)
+		}
+	}
+	for _, bad := range []string{"", "chat:", "docs:1", "//docs.googleapis.com/documents/1"} {
+		if _, err := parseEventsTarget(bad); err == nil {
+			t.Errorf("parseEventsTarget(%q): expected error", bad)
+		}
+	}
+	if got := eventsKindForTypes([]string{"google.workspace.meet.conference.v2.started"}); got != "meet" {
+		t.Fatalf("eventsKindForTypes = %q", got)
+	}
+}
+
+func TestExecute_EventsSubscribe(t *testing.T) {
+	orig := newWorkspaceEventsService
+	t.Cleanup(func() { newWorkspaceEventsService = orig })
+
+	var created workspaceevents.Subscription
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/v1/subscriptions") {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		created.Name = "subscriptions/s1"
+		created.State = "ACTIVE"
+		resp, _ := json.Marshal(created)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": "operations/o1", "done": true, "response": json.RawMessage(resp)})
+	}))
+	defer srv.Close()
+
+	var gotScopes []string
+	newWorkspaceEventsService = func(ctx context.Context, _ string, scopes []string) (*workspaceevents.Service, error) {
+		gotScopes = scopes
+		return workspaceevents.NewService(ctx, option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "events", "subscribe", "--target", "chat:AAA", "--topic", "projects/p/topics/t", "--ttl", "1h"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if created.TargetResource != "//chat.googleapis.com/spaces/AAA" || created.NotificationEndpoint.PubsubTopic != "projects/p/topics/t" || created.Ttl != "3600s" {
+		t.Fatalf("request = %#v", created)
+	}
+	if len(created.EventTypes) != 1 || created.EventTypes[0] != "google.workspace.chat.message.v1.created" {
+		t.Fatalf("event types = %v", created.EventTypes)
+	}
+	if len(gotScopes) != 1 || gotScopes[0] != workspaceevents.ChatMessagesReadonlyScope {
+		t.Fatalf("scopes = %v", gotScopes)
+	}
+	if !strings.Contains(out, `"subscriptions/s1"`) {
+		t.Fatalf("out = %q", out)
+	}
+
+	if err := Execute([]string{"--account", "a@b.com", "events", "subscribe", "--target", "drive:f1", "--topic", "projects/p/topics/t"}); err == nil {
+		t.Fatalf("expected drive target without --event-types to fail")
+	}
+}
+
+func TestTailWorkspaceEvents(t *testing.T) {
+	var acked []string
+	data := base64.StdEncoding.EncodeToString([]byte(`{"message":{"name":"spaces/AAA/messages/1"}}`))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "projects/p/subscriptions/s:pull"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"receivedMessages": []map[string]any{{"ackId": "ack-1", "message": map[string]any{
+					"data":       data,
+					"messageId":  "pm1",
+					"attributes": map[string]string{"ce-id": "ev1", "ce-type": "google.workspace.chat.message.v1.created", "ce-subject": "//chat.googleapis.com/spaces/AAA"},
+				}}},
+			})
+		case strings.HasSuffix(r.URL.Path, "projects/p/subscriptions/s:acknowledge"):
+			var req struct {
+				AckIds []string `json:"ackIds"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			acked = append(acked, req.AckIds...)
+			_, _ = io.WriteString(w, "{}")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	psvc, err := pubsub.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("pubsub: %v", err)
+	}
+	out := captureStdout(t, func() {
+		if err := tailWorkspaceEvents(context.Background(), psvc, "projects/p/subscriptions/s", true); err != nil {
+			t.Fatalf("tail: %v", err)
+		}
+	})
+	var ev workspaceEvent
+	if err := json.Unmarshal([]byte(out), &ev); err != nil {
+		t.Fatalf("json: %v\nout=%q", err, out)
+	}
+	if ev.ID != "ev1" || ev.Type != "google.workspace.chat.message.v1.created" || !strings.Contains(string(ev.Data), "spaces/AAA/messages/1") {
+		t.Fatalf("event = %#v", ev)
+	}
+	if len(acked) != 1 || acked[0] != "ack-1" {
+		t.Fatalf("acked = %v", acked)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecute_ConfigPaths_JSON(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("GOG_CONFIG_DIR", filepath.Join(root, "cfg"))
+	t.Setenv("GOG_STATE_DIR", filepath.Join(root, "state"))
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "config", "paths"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	var parsed struct {
+		Paths map[string]string `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if parsed.Paths["config"] != filepath.Join(root, "cfg") {
+		t.Fatalf("unexpected config path: %#v", parsed.Paths)
+	}
+	if parsed.Paths["credentials"] != filepath.Join(root, "cfg", "credentials.json") {
+		t.Fatalf("unexpected credentials path: %#v", parsed.Paths)
+	}
+	if parsed.Paths["gmail_watch"] != filepath.Join(root, "state", "gmail-watch") {
+		t.Fatalf("unexpected watch path: %#v", parsed.Paths)
+	}
+	if parsed.Paths["cache"] == "" {
+		t.Fatalf("missing cache path: %#v", parsed.Paths)
+	}
+}
+
+func TestExecute_ConfigPaths_Text(t *testing.T) {
+	t.Setenv("GOG_STATE_DIR", filepath.Join(t.TempDir(), "state"))
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"config", "paths"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "state\t") || !strings.Contains(out, "keyring\t") {
+		t.Fatalf("unexpected out=%q", out)
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/people/v1"
+)
+
+func TestExecute_ContactsExportImport_VCard(t *testing.T) {
+	origNew := newPeopleContactsService
+	t.Cleanup(func() { newPeopleContactsService = origNew })
+
+	var created people.BatchCreateContactsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/people/me/connections"):
+			if r.URL.Query().Get("pageToken") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"connections": []map[string]any{{
+						"resourceName":   "people/c1",
+						"names":          []map[string]any{{"givenName": "Ada", "familyName": "Lovelace"}},
+						"emailAddresses": []map[string]any{{"value": "ada@example.com"}},
+					}},
+					"nextPageToken": "p2",
+				})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"connections": []map[string]any{{
+					"resourceName": "people/c2",
+					"names":        []map[string]any{{"givenName": "Bob"}},
+				}},
+			})
+		case strings.Contains(r.URL.Path, "people:batchCreateContacts") && r.Method == http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			results := make([]map[string]any, 0, len(created.Contacts))
+			for i, c := range created.Contacts {
+				results = append(results, map[string]any{
+					"person": map[string]any{
+						"resourceName": "people/new" + string(rune('0'+i)),
+						"names":        []map[string]any{{"givenName": c.ContactPerson.Names[0].GivenName}},
+					},
+				})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"createdPeople": results})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := people.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newPeopleContactsService = func(context.Context, string) (*people.Service, error) { return svc, nil }
+
+	vcf := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "contacts", "export"}); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+		})
+	})
+	if strings.Count(vcf, "BEGIN:VCARD") != 2 || !strings.Contains(vcf, "N:Lovelace;Ada;;;") {
+		t.Fatalf("unexpected vcf=%q", vcf)
+	}
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			withStdin(t, vcf, func() {
+				if err := Execute([]string{"--json", "--account", "a@b.com", "contacts", "import", "-"}); err != nil {
+					t.Fatalf("import: %v", err)
+				}
+			})
+		})
+	})
+	var parsed struct {
+		Count    int `json:"count"`
+		Contacts []struct {
+			Resource string `json:"resource"`
+		} `json:"contacts"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if parsed.Count != 2 || parsed.Contacts[0].Resource != "people/new0" {
+		t.Fatalf("unexpected import out: %#v", parsed)
+	}
+	if len(created.Contacts) != 2 || created.Contacts[0].ContactPerson.EmailAddresses[0].Value != "ada@example.com" {
+		t.Fatalf("unexpected create request: %#v", created.Contacts)
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/docs/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_DocsGetAppend_JSON(t *testing.T) {
+	origNew := newDocsService
+	t.Cleanup(func() { newDocsService = origNew })
+
+	var batch docs.BatchUpdateDocumentRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/documents/d1") && r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"documentId": "d1",
+				"title":      "Notes",
+				"revisionId": "rev1",
+				"body": map[string]any{
+					"content": []map[string]any{
+						{"endIndex": 1, "sectionBreak": map[string]any{}},
+						{"endIndex": 7, "paragraph": map[string]any{
+							"elements": []map[string]any{{"textRun": map[string]any{"content": "Hello\n"}}},
+						}},
+						{"endIndex": 20, "table": map[string]any{
+							"tableRows": []map[string]any{{
+								"tableCells": []map[string]any{{
+									"content": []map[string]any{{"paragraph": map[string]any{
+										"elements": []map[string]any{{"textRun": map[string]any{"content": "cell\n"}}},
+									}}},
+								}},
+							}},
+						}},
+					},
+				},
+			})
+			return
+		case strings.HasSuffix(path, "/documents/d1:batchUpdate") && r.Method == http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&batch)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"documentId": "d1"})
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := docs.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDocsService = func(context.Context, string) (*docs.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "docs", "get", "d1"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	var got struct {
+		Document struct {
+			Title string `json:"title"`
+		} `json:"document"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if got.Document.Title != "Notes" || got.Text != "Hello\ncell\n" {
+		t.Fatalf("unexpected: %#v", got)
+	}
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "docs", "append", "d1", "More"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if len(batch.Requests) != 1 || batch.Requests[0].InsertText == nil {
+		t.Fatalf("unexpected batch: %#v", batch)
+	}
+	if batch.Requests[0].InsertText.Text != "\nMore" || batch.Requests[0].InsertText.EndOfSegmentLocation == nil {
+		t.Fatalf("unexpected insert: %#v", batch.Requests[0].InsertText)
+	}
+	if batch.WriteControl == nil || batch.WriteControl.RequiredRevisionId != "rev1" {
+		t.Fatalf("expected revision guard, got %#v", batch.WriteControl)
+	}
+}
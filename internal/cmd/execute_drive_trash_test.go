@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_DriveTrashAndList_JSON(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	var trashedBodies []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.Contains(path, "/files/id1") && r.Method == http.MethodPatch:
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			trashedBodies = append(trashedBodies, body)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "id1", "name": "Doc", "trashed": body["trashed"]})
+			return
+		case strings.HasSuffix(path, "/files") && r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"files": []map[string]any{{"id": "id1", "name": "Doc", "mimeType": "application/pdf"}},
+			})
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := drive.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(append([]string{"--json", "--account", "a@b.com", "drive"}, args...)); err != nil {
+					t.Fatalf("Execute %v: %v", args, err)
+				}
+			})
+		})
+	}
+
+	var listed struct {
+		Files []struct {
+			ID string `json:"id"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal([]byte(run("list")), &listed); err != nil || len(listed.Files) != 1 {
+		t.Fatalf("unexpected list output: %#v %v", listed, err)
+	}
+
+	var trashed struct {
+		File struct {
+			ID      string `json:"id"`
+			Trashed bool   `json:"trashed"`
+		} `json:"file"`
+	}
+	// Trashing asks like delete does; restoring does not.
+	_ = captureStderr(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "--no-input", "drive", "trash", "id1"}); err == nil {
+			t.Fatalf("expected trash without --force to be refused")
+		}
+	})
+	if len(trashedBodies) != 0 {
+		t.Fatalf("refused trash sent %d updates", len(trashedBodies))
+	}
+	if err := json.Unmarshal([]byte(run("trash", "id1", "--force")), &trashed); err != nil {
+		t.Fatalf("json parse: %v", err)
+	}
+	if trashed.File.ID != "id1" || !trashed.File.Trashed {
+		t.Fatalf("unexpected trash output: %#v", trashed)
+	}
+
+	_ = run("trash", "id1", "--restore")
+	if len(trashedBodies) != 2 || trashedBodies[0]["trashed"] != true || trashedBodies[1]["trashed"] != false {
+		t.Fatalf("unexpected update bodies: %#v", trashedBodies)
+	}
+}
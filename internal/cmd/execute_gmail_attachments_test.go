@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func newAttachmentsTestService(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+}
+
+func attachmentTestMessage(id string, parts ...map[string]any) map[string]any {
+	return map[string]any{
+		"id":       id,
+		"threadId": "t-" + id,
+		"payload": map[string]any{
+			"mimeType": "multipart/mixed",
+			"headers": []map[string]any{
+				{"name": "From", "value": "Ada <ada@example.com>"},
+				{"name": "Date", "value": "Mon, 02 Jan 2006 15:04:05 -0700"},
+			},
+			"parts": append([]map[string]any{{"mimeType": "text/plain", "body": map[string]any{"size": 2, "data": "aGk"}}}, parts...),
+		},
+	}
+}
+
+func attachmentTestPart(filename, mimeType, attachmentID string, size int) map[string]any {
+	return map[string]any{
+		"filename": filename,
+		"mimeType": mimeType,
+		"body":     map[string]any{"attachmentId": attachmentID, "size": size},
+	}
+}
+
+func TestExecute_GmailAttachmentsList(t *testing.T) {
+	msgs := map[string]map[string]any{
+		"m1": attachmentTestMessage("m1", attachmentTestPart("a.pdf", "application/pdf", "att1", 100)),
+		"m2": attachmentTestMessage("m2",
+			attachmentTestPart("b.png", "image/png", "att2", 20),
+			attachmentTestPart("c.txt", "text/plain", "att3", 3),
+		),
+	}
+	var gotQuery string
+	newAttachmentsTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(path, "/users/me/messages"):
+			gotQuery = r.URL.Query().Get("q")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"messages":      []map[string]any{{"id": "m1"}, {"id": "m2"}},
+				"nextPageToken": "npt",
+			})
+		case strings.Contains(path, "/users/me/messages/"):
+			id := path[strings.LastIndex(path, "/")+1:]
+			_ = json.NewEncoder(w).Encode(msgs[id])
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "attachments", "list", "--query", "from:ada"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if gotQuery != "(from:ada) has:attachment" {
+		t.Fatalf("unexpected query %q", gotQuery)
+	}
+	var parsed struct {
+		Attachments []attachmentRow `json:"attachments"`
+		Next        string          `json:"nextPageToken"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if len(parsed.Attachments) != 3 || parsed.Next != "npt" {
+		t.Fatalf("unexpected: %#v", parsed)
+	}
+	first := parsed.Attachments[0]
+	if first.MessageID != "m1" || first.AttachmentID != "att1" || first.Filename != "a.pdf" || first.Size != 100 || first.From != "Ada <ada@example.com>" || first.Date == "" {
+		t.Fatalf("unexpected first row: %#v", first)
+	}
+
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "attachments", "list", "--query", "from:ada"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "FILENAME") || !strings.Contains(out, "c.txt") {
+		t.Fatalf("unexpected out=%q", out)
+	}
+
+	if err := Execute([]string{"--account", "a@b.com", "gmail", "attachments", "list"}); err == nil {
+		t.Fatalf("expected usage error without --query")
+	}
+}
+
+func TestExecute_GmailAttachmentDownloadAll(t *testing.T) {
+	msgs := map[string]map[string]any{
+		"m1": attachmentTestMessage("m1", attachmentTestPart("report.pdf", "application/pdf", "att1", 5)),
+		"m2": attachmentTestMessage("m2",
+			attachmentTestPart("report.pdf", "application/pdf", "att2", 5),
+			attachmentTestPart("big.png", "image/png", "att3", 5<<20),
+			attachmentTestPart("notes.txt", "text/plain", "att4", 5),
+		),
+	}
+	newAttachmentsTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(path, "/users/me/messages"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"messages": []map[string]any{{"id": "m1"}, {"id": "m2"}},
+			})
+		case strings.Contains(path, "/attachments/"):
+			id := path[strings.LastIndex(path, "/")+1:]
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": base64.RawURLEncoding.EncodeToString([]byte("data-" + id))})
+		case strings.Contains(path, "/users/me/messages/"):
+			id := path[strings.LastIndex(path, "/")+1:]
+			_ = json.NewEncoder(w).Encode(msgs[id])
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "report.pdf"), []byte("existing"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{
+				"--json", "--account", "a@b.com",
+				"gmail", "attachment", "download-all",
+				"--query", "from:ada",
+				"--out-dir", dir,
+				"--mime-filter", "application/*,image/*",
+				"--max-size", "1MB",
+			}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	var parsed struct {
+		Manifest string            `json:"manifest"`
+		Saved    []savedAttachment `json:"saved"`
+		Skipped  int               `json:"skipped"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if len(parsed.Saved) != 2 || parsed.Skipped != 2 {
+		t.Fatalf("unexpected result: %#v", parsed)
+	}
+	if filepath.Base(parsed.Saved[0].Path) != "report-2.pdf" || filepath.Base(parsed.Saved[1].Path) != "report-3.pdf" {
+		t.Fatalf("unexpected paths: %q %q", parsed.Saved[0].Path, parsed.Saved[1].Path)
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "report.pdf")); err != nil || string(b) != "existing" {
+		t.Fatalf("existing file overwritten: %q %v", b, err)
+	}
+	if b, err := os.ReadFile(parsed.Saved[1].Path); err != nil || string(b) != "data-att2" {
+		t.Fatalf("unexpected content: %q %v", b, err)
+	}
+	var manifest attachmentManifest
+	b, err := os.ReadFile(parsed.Manifest)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if err := json.Unmarshal(b, &manifest); err != nil || len(manifest.Saved) != 2 || manifest.Query != "from:ada" {
+		t.Fatalf("unexpected manifest: %s (%v)", b, err)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	cases := map[string]int64{"": 0, "1500": 1500, "10KB": 10 << 10, "1.5mb": 3 << 19, "2G": 2 << 30, "7 B": 7}
+	for in, want := range cases {
+		got, err := parseByteSize(in)
+		if err != nil || got != want {
+			t.Fatalf("parseByteSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := parseByteSize("lots"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestAttachmentNamer(t *testing.T) {
+	n := newAttachmentNamer(t.TempDir())
+	got := []string{
+		filepath.Base(n.next("a.txt")),
+		filepath.Base(n.next("A.txt")),
+		filepath.Base(n.next("../../etc/passwd")),
+		filepath.Base(n.next("manifest.json")),
+		filepath.Base(n.next("")),
+	}
+	want := []string{"a.txt", "A-2.txt", "passwd", "manifest-2.json", "attachment"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if !matchesMimeFilter("image/png", []string{"image/*"}) || matchesMimeFilter("text/plain", []string{"image/*"}) {
+		t.Fatalf("unexpected mime filter result")
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestExecute_GmailSend_DryRun(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) {
+		t.Fatalf("dry run must not create a Gmail service")
+		return nil, errors.New("unreachable")
+	}
+	t.Setenv("GOG_GMAIL_ALLOWLIST", "example.com")
+	t.Setenv("GOG_GMAIL_REQUIRE_ARM", "1")
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{
+				"--account", "a@b.com",
+				"gmail", "send", "--dry-run",
+				"--to", "x@example.com",
+				"--subject", "Hello",
+				"--body", "Body text",
+			}); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "Subject: Hello") || !strings.Contains(out, "To: x@example.com") || !strings.Contains(out, "Body text") {
+		t.Fatalf("unexpected out=%q", out)
+	}
+
+	err := Execute([]string{
+		"--account", "a@b.com",
+		"gmail", "send", "--dry-run",
+		"--to", "x@elsewhere.org",
+		"--subject", "Hello",
+		"--body", "Body text",
+	})
+	if err == nil || !strings.Contains(err.Error(), "allowlist") {
+		t.Fatalf("expected allowlist error, got %v", err)
+	}
+
+	if err := Execute([]string{
+		"--account", "a@b.com",
+		"gmail", "send", "--out", "x.eml",
+		"--to", "x@example.com",
+		"--subject", "Hello",
+		"--body", "Body text",
+	}); err == nil {
+		t.Fatalf("expected --out without --dry-run to fail")
+	}
+}
+
+func TestExecute_GmailDraftsCreate_DryRunOut(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) {
+		t.Fatalf("dry run must not create a Gmail service")
+		return nil, errors.New("unreachable")
+	}
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+
+	path := filepath.Join(t.TempDir(), "draft.eml")
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{
+				"--json", "--account", "a@b.com",
+				"gmail", "drafts", "create", "--dry-run", "--out", path,
+				"--to", "x@example.com",
+				"--subject", "Draft",
+				"--body", "Hi",
+				"--from", "alias@b.com",
+			}); err != nil {
+				t.Fatalf("drafts create: %v", err)
+			}
+		})
+	})
+	var parsed struct {
+		DryRun bool   `json:"dryRun"`
+		Path   string `json:"path"`
+		Bytes  int    `json:"bytes"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if !parsed.DryRun || parsed.Path != path || parsed.Bytes == 0 {
+		t.Fatalf("unexpected out: %#v", parsed)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(raw), "From: alias@b.com") || !strings.Contains(string(raw), "Subject: Draft") {
+		t.Fatalf("unexpected eml=%q", raw)
+	}
+}
+
+func TestExecute_GmailSend_TemplateDryRun(t *testing.T) {
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+	path := filepath.Join(t.TempDir(), "welcome.tmpl")
+	if err := os.WriteFile(path, []byte(`{{define "subject"}}Welcome {{.name}}{{end}}Hi {{.name}}!`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{
+				"--account", "a@b.com",
+				"gmail", "send", "--dry-run",
+				"--to", "ada@example.com",
+				"--template", path,
+				"--vars", "name=Ada",
+			}); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "Subject: Welcome Ada") || !strings.Contains(out, "Hi Ada!") {
+		t.Fatalf("unexpected out=%q", out)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -225,3 +227,110 @@ func TestExecute_GmailGet_Metadata_Text(t *testing.T) {
 		t.Fatalf("unexpected out=%q", out)
 	}
 }
+
+func TestExecute_GmailGet_EML(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	raw := "From: a@example.com\r\nSubject: hi\r\n\r\nbody\r\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("format"); got != "raw" {
+			t.Fatalf("format=%q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":  "m1",
+			"raw": base64.URLEncoding.EncodeToString([]byte(raw)),
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "get", "m1", "--format", "eml"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if out != raw {
+		t.Fatalf("unexpected stdout %q", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "msg.eml")
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "get", "m1", "--format", "eml", "--out", path}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if b, err := os.ReadFile(path); err != nil || string(b) != raw {
+		t.Fatalf("unexpected file %q (%v)", b, err)
+	}
+	if !strings.Contains(out, `"path"`) {
+		t.Fatalf("unexpected out=%q", out)
+	}
+
+	_ = captureStderr(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "get", "m1", "--out", path}); err == nil {
+			t.Fatalf("expected usage error for --out with --format full")
+		}
+	})
+}
+
+func TestExecute_GmailGet_Parts(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id": "m1",
+			"payload": map[string]any{
+				"mimeType": "multipart/mixed",
+				"parts": []map[string]any{
+					{"partId": "0", "mimeType": "text/plain", "body": map[string]any{"size": 4}},
+					{"partId": "1", "mimeType": "application/pdf", "filename": "a.pdf", "body": map[string]any{"size": 10, "attachmentId": "att"}},
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "get", "m1", "--parts"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "CONTENT_ID") || !strings.Contains(out, "  application/pdf") || !strings.Contains(out, "a.pdf") {
+		t.Fatalf("unexpected out=%q", out)
+	}
+
+	_ = captureStderr(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "get", "m1", "--parts", "--format", "raw"}); err == nil {
+			t.Fatalf("expected usage error")
+		}
+	})
+}
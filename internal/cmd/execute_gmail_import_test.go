@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_GmailImportInsert(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	type call struct {
+		path  string
+		query map[string]string
+		body  string
+	}
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{
+				{"id": "INBOX", "name": "INBOX"},
+				{"id": "Label_7", "name": "Migrated"},
+			}})
+		case strings.HasSuffix(r.URL.Path, "/users/me/messages/import") || strings.HasSuffix(r.URL.Path, "/users/me/messages"):
+			b, _ := io.ReadAll(r.Body)
+			c := call{path: r.URL.Path, query: map[string]string{}, body: string(b)}
+			for k := range r.URL.Query() {
+				c.query[k] = r.URL.Query().Get(k)
+			}
+			calls = append(calls, c)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1", "threadId": "t1", "labelIds": []string{"INBOX", "Label_7"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	path := filepath.Join(t.TempDir(), "m.eml")
+	if err := os.WriteFile(path, []byte("From: a@example.com\r\nSubject: old\r\n\r\nhello\r\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "import", path, "--label", "inbox,migrated", "--internal-date-source", "received", "--no-spam-check"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, `"messageId": "m1"`) {
+		t.Fatalf("unexpected out=%q", out)
+	}
+	if len(calls) != 1 || !strings.HasSuffix(calls[0].path, "/messages/import") {
+		t.Fatalf("unexpected calls: %#v", calls)
+	}
+	c := calls[0]
+	if c.query["internalDateSource"] != "receivedTime" || c.query["neverMarkSpam"] != "true" {
+		t.Fatalf("unexpected query: %#v", c.query)
+	}
+	if !strings.Contains(c.body, "Subject: old") || !strings.Contains(c.body, "Label_7") || !strings.Contains(c.body, "message/rfc822") {
+		t.Fatalf("unexpected body: %q", c.body)
+	}
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "insert", path, "--internal-date-from-header"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if len(calls) != 2 || strings.HasSuffix(calls[1].path, "/import") {
+		t.Fatalf("expected insert call, got %#v", calls)
+	}
+	if _, ok := calls[1].query["neverMarkSpam"]; ok {
+		t.Fatalf("insert should not send neverMarkSpam")
+	}
+	if calls[1].query["internalDateSource"] != "dateHeader" {
+		t.Fatalf("insert query: %#v", calls[1].query)
+	}
+	if err := Execute([]string{"--account", "a@b.com", "gmail", "insert", path, "--internal-date-from-header", "--internal-date-source", "received"}); err == nil {
+		t.Fatalf("expected conflicting date source error")
+	}
+
+	if err := Execute([]string{"--account", "a@b.com", "gmail", "insert", path, "--no-spam-check"}); err == nil {
+		t.Fatalf("expected unknown flag error for insert --no-spam-check")
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_GmailSearch_GroupBy(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	msg := func(id, thread, from, rfcID string) map[string]any {
+		return map[string]any{
+			"id":           id,
+			"threadId":     thread,
+			"internalDate": "1735732800000",
+			"snippet":      "Hi &amp; welcome " + id,
+			"payload": map[string]any{
+				"headers": []map[string]any{
+					{"name": "From", "value": from},
+					{"name": "Subject", "value": "S " + id},
+					{"name": "Message-ID", "value": rfcID},
+				},
+			},
+		}
+	}
+	msgs := map[string]map[string]any{
+		"m1": msg("m1", "t1", "Ada <ada@example.com>", "<1@x>"),
+		"m2": msg("m2", "t2", "ada@example.com", "<2@x>"),
+		"m3": msg("m3", "t2", "ada@example.com", "<2@x>"),
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(path, "/users/me/messages"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"messages": []map[string]any{{"id": "m1"}, {"id": "m2"}, {"id": "m3"}},
+			})
+		case strings.Contains(path, "/users/me/messages/"):
+			id := path[strings.LastIndex(path, "/")+1:]
+			_ = json.NewEncoder(w).Encode(msgs[id])
+		case strings.Contains(path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "search", "from:ada", "--group-by", "message"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	var messages struct {
+		Messages []struct {
+			ID       string `json:"id"`
+			ThreadID string `json:"threadId"`
+			Snippet  string `json:"snippet"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(out), &messages); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if len(messages.Messages) != 2 || messages.Messages[1].ThreadID != "t2" || messages.Messages[0].Snippet != "Hi & welcome m1" {
+		t.Fatalf("unexpected messages: %#v", messages.Messages)
+	}
+
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "search", "from:ada", "--group-by", "sender"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "SENDER") || !strings.Contains(out, "ada@example.com") {
+		t.Fatalf("unexpected out=%q", out)
+	}
+
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "search", "from:ada", "--group-by", "message", "--preview", "8"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "PREVIEW") || !strings.Contains(out, "Hi & we…") {
+		t.Fatalf("unexpected out=%q", out)
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_GmailThreadModify_JSON(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var modifyReq gmail.ModifyThreadRequest
+	trashed := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.Contains(path, "/users/me/labels"):
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"labels": []map[string]any{
+					{"id": "INBOX", "name": "INBOX", "type": "system"},
+					{"id": "Label_1", "name": "Work", "type": "user"},
+				},
+			})
+			return
+		case strings.HasSuffix(path, "/users/me/threads/t1/modify") && r.Method == http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&modifyReq)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1"})
+			return
+		case strings.HasSuffix(path, "/users/me/threads/t1/trash") && r.Method == http.MethodPost:
+			trashed = true
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1"})
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "thread", "modify", "t1", "--add-label", "work", "--archive", "--trash", "--force"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	var parsed struct {
+		ThreadID      string   `json:"threadId"`
+		AddedLabels   []string `json:"addedLabels"`
+		RemovedLabels []string `json:"removedLabels"`
+		Trashed       bool     `json:"trashed"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if parsed.ThreadID != "t1" || !parsed.Trashed {
+		t.Fatalf("unexpected: %#v", parsed)
+	}
+	if len(modifyReq.AddLabelIds) != 1 || modifyReq.AddLabelIds[0] != "Label_1" {
+		t.Fatalf("unexpected add labels: %#v", modifyReq.AddLabelIds)
+	}
+	if len(modifyReq.RemoveLabelIds) != 1 || modifyReq.RemoveLabelIds[0] != "INBOX" {
+		t.Fatalf("unexpected remove labels: %#v", modifyReq.RemoveLabelIds)
+	}
+	if !trashed {
+		t.Fatalf("expected thread to be trashed")
+	}
+}
+
+func TestExecute_GmailThreadModify_RequiresChange(t *testing.T) {
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--account", "a@b.com", "gmail", "thread", "modify", "t1"})
+		if err == nil || ExitCode(err) != 2 {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+}
@@ -12,6 +12,8 @@ import (
 
 	"google.golang.org/api/gmail/v1"
 	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/config"
 )
 
 func TestExecute_GmailWatch_MoreCommands(t *testing.T) {
@@ -121,7 +123,11 @@ func TestExecute_GmailWatch_MoreCommands(t *testing.T) {
 	}
 
 	// Ensure dir exists but file doesn't.
-	if !strings.Contains(p, filepath.Join("gogcli", "state", "gmail-watch")) {
+	watchDir, err := config.GmailWatchDir()
+	if err != nil {
+		t.Fatalf("watch dir: %v", err)
+	}
+	if filepath.Dir(p) != watchDir {
 		t.Fatalf("unexpected state path: %s", p)
 	}
 }
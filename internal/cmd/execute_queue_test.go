@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_GmailSendAt_QueueRun(t *testing.T) {
+	t.Setenv("GOG_STATE_DIR", t.TempDir())
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+
+	origNew, origNow := newGmailService, queueNow
+	t.Cleanup(func() { newGmailService, queueNow = origNew, origNow })
+	now := time.Date(2025, 7, 1, 8, 0, 0, 0, time.UTC)
+	queueNow = func() time.Time { return now }
+
+	var sends atomic.Int32
+	var sentRaw string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/users/me/messages/send") {
+			http.NotFound(w, r)
+			return
+		}
+		sends.Add(1)
+		var msg gmail.Message
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		sentRaw = msg.Raw
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "sent1", "threadId": "t1"})
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{
+				"--json", "--account", "a@b.com",
+				"gmail", "send", "--to", "x@example.com", "--subject", "Later", "--body", "B",
+				"--send-at", "2025-07-01T09:00:00Z",
+			}); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+		})
+	})
+	var queued struct {
+		Queued bool   `json:"queued"`
+		ID     string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(out), &queued); err != nil || !queued.Queued || queued.ID == "" {
+		t.Fatalf("unexpected queue out=%q err=%v", out, err)
+	}
+	if sends.Load() != 0 {
+		t.Fatalf("send-at must not send immediately")
+	}
+
+	// Not yet due.
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"queue", "run"}); err != nil {
+				t.Fatalf("run: %v", err)
+			}
+		})
+	})
+	if sends.Load() != 0 {
+		t.Fatalf("expected nothing sent before send-at")
+	}
+
+	now = now.Add(2 * time.Hour)
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "queue", "run"}); err != nil {
+				t.Fatalf("run: %v", err)
+			}
+		})
+	})
+	if sends.Load() != 1 || sentRaw == "" || !strings.Contains(out, `"messageId": "sent1"`) {
+		t.Fatalf("expected one send, got %d out=%q", sends.Load(), out)
+	}
+
+	items, err := listQueuedMessages()
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected empty queue, got %#v %v", items, err)
+	}
+}
+
+func TestExecute_QueueRemove(t *testing.T) {
+	t.Setenv("GOG_STATE_DIR", t.TempDir())
+	m := queuedMessage{ID: "20250701T080000Z-abcd", Account: "a@b.com", SendAt: time.Now().Add(time.Hour), Raw: "eA"}
+	if err := saveQueuedMessage(m); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--force", "queue", "remove", m.ID}); err != nil {
+				t.Fatalf("remove: %v", err)
+			}
+		})
+	})
+	if _, err := loadQueuedMessage(m.ID); err == nil {
+		t.Fatalf("expected message removed")
+	}
+	if _, err := queuedMessagePath("../x"); err == nil {
+		t.Fatalf("expected invalid id error")
+	}
+}
+
+func TestParseSendAt(t *testing.T) {
+	now := time.Date(2025, 7, 1, 8, 0, 0, 0, time.UTC)
+	if v, err := parseSendAt("", now); err != nil || !v.IsZero() {
+		t.Fatalf("empty: %v %v", v, err)
+	}
+	if _, err := parseSendAt("2025-07-01T07:00:00Z", now); err == nil {
+		t.Fatalf("expected past error")
+	}
+	if _, err := parseSendAt("tomorrow", now); err == nil {
+		t.Fatalf("expected parse error")
+	}
+	if v, err := parseSendAt("2030-01-02 03:04", now); err != nil || v.Year() != 2030 {
+		t.Fatalf("local: %v %v", v, err)
+	}
+}
+
+// TestQueueRunHelperProcess is "gog queue run" in a child process, started by
+// TestExecute_QueueRun_Overlapping against its fake Gmail endpoint.
+func TestQueueRunHelperProcess(t *testing.T) {
+	endpoint := os.Getenv("GOG_TEST_QUEUE_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("helper process")
+	}
+	// TestMain clears the GOG_* dirs, so the parent passes its own.
+	t.Setenv("GOG_STATE_DIR", os.Getenv("GOG_TEST_QUEUE_STATE_DIR"))
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithEndpoint(endpoint+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+	if err := Execute([]string{"queue", "run"}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
+
+func TestExecute_QueueRun_Overlapping(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("GOG_STATE_DIR", stateDir)
+
+	var sends atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/users/me/messages/send") {
+			http.NotFound(w, r)
+			return
+		}
+		n := sends.Add(1)
+		time.Sleep(50 * time.Millisecond) // keep both runs in flight
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": fmt.Sprintf("sent%d", n), "threadId": "t1"})
+	}))
+	defer srv.Close()
+
+	for i := 0; i < 4; i++ {
+		m := queuedMessage{ID: fmt.Sprintf("20250701T080000Z-000%d", i), Account: "a@b.com", SendAt: time.Now().Add(-time.Minute), Raw: "eA"}
+		if err := saveQueuedMessage(m); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	// Two separate processes, like cron and a manual run.
+	runs := make([]*exec.Cmd, 2)
+	for i := range runs {
+		c := exec.Command(os.Args[0], "-test.run=^TestQueueRunHelperProcess$")
+		c.Env = append(os.Environ(),
+			"GOG_TEST_QUEUE_ENDPOINT="+srv.URL,
+			"GOG_TEST_QUEUE_STATE_DIR="+stateDir,
+		)
+		if err := c.Start(); err != nil {
+			t.Fatalf("start: %v", err)
+		}
+		runs[i] = c
+	}
+	for _, c := range runs {
+		if err := c.Wait(); err != nil {
+			t.Fatalf("queue run process: %v", err)
+		}
+	}
+
+	if got := sends.Load(); got != 4 {
+		t.Fatalf("sends = %d, want 4 (each message exactly once)", got)
+	}
+	items, err := listQueuedMessages()
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected empty queue, got %#v %v", items, err)
+	}
+}
+
+func TestExecute_QueueRun_PermanentFailure(t *testing.T) {
+	t.Setenv("GOG_STATE_DIR", t.TempDir())
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var sends atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sends.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 400, "message": "Invalid To header"}})
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	m := queuedMessage{ID: "20250701T080000Z-bad0", Account: "a@b.com", SendAt: time.Now().Add(-time.Minute), Raw: "eA"}
+	if err := saveQueuedMessage(m); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		_ = captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				_ = Execute([]string{"queue", "run"})
+			})
+		})
+	}
+	if got := sends.Load(); got != 1 {
+		t.Fatalf("sends = %d, want 1 (400 is not retried)", got)
+	}
+	got, err := loadQueuedMessage(m.ID)
+	if err != nil || !got.Failed || got.Attempts != 1 || got.LastError == "" {
+		t.Fatalf("expected failed message, got %#v %v", got, err)
+	}
+}
@@ -120,7 +120,7 @@ func TestExecute_SheetsMoreCommands(t *testing.T) {
 			}
 		})
 		_ = captureStdout(t, func() {
-			if err := Execute([]string{"--json", "sheets", "clear", "id1", "Sheet1!A1:B1"}); err != nil {
+			if err := Execute([]string{"--json", "--force", "sheets", "clear", "id1", "Sheet1!A1:B1"}); err != nil {
 				t.Fatalf("clear: %v", err)
 			}
 		})
@@ -136,3 +136,48 @@ func TestExecute_SheetsMoreCommands(t *testing.T) {
 		})
 	})
 }
+
+func TestExecute_SheetsAppend_ValuesFileStdin(t *testing.T) {
+	origNew := newSheetsService
+	t.Cleanup(func() { newSheetsService = origNew })
+
+	var got sheets.ValueRange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, ":append") || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"updates": map[string]any{"updatedRange": "Sheet1!A1:B2", "updatedCells": 4},
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := sheets.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newSheetsService = func(context.Context, string) (*sheets.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			withStdin(t, "a\tb\nc\td\n", func() {
+				if err := Execute([]string{"--account", "a@b.com", "sheets", "append", "id1", "Sheet1!A:B", "--values-file", "-"}); err != nil {
+					t.Fatalf("append: %v", err)
+				}
+			})
+		})
+	})
+	if !strings.Contains(out, "Appended 4 cells") {
+		t.Fatalf("unexpected out=%q", out)
+	}
+	if len(got.Values) != 2 || got.Values[1][1] != "d" {
+		t.Fatalf("unexpected values: %#v", got.Values)
+	}
+}
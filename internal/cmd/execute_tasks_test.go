@@ -496,3 +496,49 @@ func TestExecute_TasksClear_JSON(t *testing.T) {
 		t.Fatalf("unexpected response: %#v", parsed)
 	}
 }
+
+func TestExecute_TasksLists_CSV(t *testing.T) {
+	origNew := newTasksService
+	t.Cleanup(func() { newTasksService = origNew })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"items": []map[string]any{
+				{"id": "l1", "title": "Errands, home"},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := tasks.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newTasksService = func(context.Context, string) (*tasks.Service, error) { return svc, nil }
+
+	for format, want := range map[string]string{
+		"csv": "l1,\"Errands, home\"",
+		"tsv": "l1\tErrands, home",
+	} {
+		out := captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute([]string{"--output", format, "--account", "a@b.com", "tasks", "lists"}); err != nil {
+					t.Fatalf("Execute: %v", err)
+				}
+			})
+		})
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[1], want) {
+			t.Fatalf("%s: unexpected out=%q", format, out)
+		}
+	}
+
+	if err := Execute([]string{"--output", "xml", "--account", "a@b.com", "tasks", "lists"}); err == nil {
+		t.Fatalf("expected error for unknown --output")
+	}
+}
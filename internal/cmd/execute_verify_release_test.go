@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecute_VerifyRelease(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	origKey := releasePublicKey
+	t.Cleanup(func() { releasePublicKey = origKey })
+	releasePublicKey = base64.StdEncoding.EncodeToString(pub)
+
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "gogcli_1.0.0_linux_amd64.tar.gz")
+	if err := os.WriteFile(archive, []byte("payload"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	sum := sha256.Sum256([]byte("payload"))
+	checksums := hex.EncodeToString(sum[:]) + "  gogcli_1.0.0_linux_amd64.tar.gz\n"
+	_ = os.WriteFile(filepath.Join(dir, "checksums.txt"), []byte(checksums), 0o600)
+	_ = os.WriteFile(filepath.Join(dir, "checksums.txt.sig"), ed25519.Sign(priv, []byte(checksums)), 0o600)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "verify-release", archive}); err != nil {
+				t.Fatalf("verify-release: %v", err)
+			}
+		})
+	})
+	var parsed struct {
+		Verified bool   `json:"verified"`
+		SHA256   string `json:"sha256"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if !parsed.Verified || parsed.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Fatalf("unexpected out: %#v", parsed)
+	}
+
+	releasePublicKey = ""
+	_ = captureStderr(t, func() {
+		if err := Execute([]string{"verify-release", archive}); err == nil {
+			t.Fatalf("expected error without a release key")
+		}
+	})
+}
@@ -0,0 +1,667 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/people/v1"
+)
+
+const exportManifestName = "manifest.json"
+
+var exportServices = []string{"gmail", "drive", "calendar", "contacts"}
+
+// exportManifest is written to <out>/manifest.json after every export run.
+type exportManifest struct {
+	Version    int                    `json:"version"`
+	Account    string                 `json:"account"`
+	ExportedAt string                 `json:"exportedAt"`
+	Services   []*exportServiceResult `json:"services"`
+}
+
+// exportServiceResult summarizes one service of an export run. Paths are
+// relative to the export dir.
+type exportServiceResult struct {
+	Service   string          `json:"service"`
+	Path      string          `json:"path"`
+	Items     int             `json:"items"`
+	Bytes     int64           `json:"bytes"`
+	Unchanged int             `json:"unchanged,omitempty"`
+	Skipped   int             `json:"skipped,omitempty"`
+	Files     []exportFile    `json:"files,omitempty"`
+	Failed    []exportFailure `json:"failed,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+type exportFile struct {
+	ID       string `json:"id"`
+	Path     string `json:"path"`
+	Bytes    int64  `json:"bytes"`
+	Modified string `json:"modified,omitempty"`
+}
+
+type exportFailure struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+}
+
+type exportOptions struct {
+	out         string
+	gmailQuery  string
+	gmailMax    int64
+	driveFolder string
+	calendars   []string
+	previous    *exportManifest
+}
+
+func newExportCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export account data for backups",
+	}
+	cmd.AddCommand(newExportAllCmd(flags))
+	return cmd
+}
+
+func newExportAllCmd(flags *rootFlags) *cobra.Command {
+	var services string
+	var opts exportOptions
+
+	cmd := &cobra.Command{
+		Use:   "all",
+		Short: "Export Gmail, Drive, Calendar and Contacts into a Takeout-like tree",
+		Long: `Export several services into --out using Google Takeout's layout:
+
+  Takeout/Mail/All mail.mbox           Gmail messages (mboxrd, with X-Gmail-Labels)
+  Takeout/Drive/...                    My Drive (or --drive-folder) as a file tree;
+                                       Google Docs/Sheets/Slides/Drawings exported
+  Takeout/Calendar/<calendar>.ics      one iCalendar file per calendar
+  Takeout/Contacts/All Contacts.vcf    contacts as vCard 3.0
+  manifest.json                        what was exported, with sizes and failures
+
+Re-running into the same --out (e.g. from cron) rewrites the mailbox,
+calendars and contacts, and downloads only Drive files whose modified time
+changed since the manifest of the previous run. Items that fail are listed
+in the manifest and make the command exit non-zero once everything else is
+written.`,
+		Example: `  gog export all --out ~/Backups/google
+  gog export all --services gmail,calendar --gmail-query 'newer_than:1y' --out backup/`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			opts.out = strings.TrimSpace(opts.out)
+			if opts.out == "" {
+				return usage("--out is required")
+			}
+			selected := splitCSV(strings.ToLower(services))
+			if len(selected) == 0 {
+				return usage("--services is empty")
+			}
+			for _, s := range selected {
+				if !slices.Contains(exportServices, s) {
+					return usagef("unknown service %q (use %s)", s, strings.Join(exportServices, ","))
+				}
+			}
+			if opts.gmailMax < 0 {
+				return usage("--gmail-max must be >= 0")
+			}
+			if err := os.MkdirAll(opts.out, 0o700); err != nil {
+				return err
+			}
+			if opts.previous, err = readExportManifest(opts.out); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			manifest := &exportManifest{Version: 1, Account: account, ExportedAt: time.Now().UTC().Format(time.RFC3339)}
+			failures := 0
+			for _, service := range exportServices {
+				if !slices.Contains(selected, service) {
+					continue
+				}
+				res := runExportService(ctx, account, service, opts)
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				manifest.Services = append(manifest.Services, res)
+				failures += len(res.Failed)
+				if res.Error != "" {
+					failures++
+					u.Err().Printf("WARN: export %s: %s", service, res.Error)
+				}
+			}
+			if err := writeExportManifest(opts.out, manifest); err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(ctx) {
+				if err := outfmt.WriteJSON(os.Stdout, manifest); err != nil {
+					return err
+				}
+			} else {
+				w, flush := tableWriter(ctx)
+				fmt.Fprintln(w, "SERVICE\tITEMS\tSIZE\tFAILED\tPATH")
+				for _, s := range manifest.Services {
+					fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%s\n", s.Service, s.Items, formatDriveSize(s.Bytes), len(s.Failed), filepath.Join(opts.out, s.Path))
+				}
+				flush()
+			}
+			if failures > 0 {
+				return fmt.Errorf("export finished with %d failure(s); see %s", failures, filepath.Join(opts.out, exportManifestName))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&services, "services", "gmail,drive,calendar", "Services to export (comma-separated: "+strings.Join(exportServices, ",")+")")
+	cmd.Flags().StringVar(&opts.out, "out", "", "Export directory (required)")
+	cmd.Flags().StringVar(&opts.gmailQuery, "gmail-query", "", "Only export messages matching this Gmail query")
+	cmd.Flags().Int64Var(&opts.gmailMax, "gmail-max", 0, "Export at most this many messages, newest first (0 = all)")
+	cmd.Flags().StringVar(&opts.driveFolder, "drive-folder", "root", "Drive folder ID to export (default: My Drive)")
+	cmd.Flags().StringSliceVar(&opts.calendars, "calendar", nil, "Calendar ID to export (repeatable; default: every calendar in your list)")
+	return cmd
+}
+
+// runExportService exports one service; errors that stop the whole service
+// are recorded in the result rather than returned.
+func runExportService(ctx context.Context, account, service string, opts exportOptions) *exportServiceResult {
+	res := &exportServiceResult{Service: service}
+	var err error
+	switch service {
+	case "gmail":
+		res.Path = filepath.Join("Takeout", "Mail", "All mail.mbox")
+		var svc *gmail.Service
+		if svc, err = newGmailService(ctx, account); err == nil {
+			err = exportGmail(ctx, svc, opts, res)
+		}
+	case "drive":
+		res.Path = filepath.Join("Takeout", "Drive")
+		var svc *drive.Service
+		if svc, err = newDriveService(ctx, account); err == nil {
+			err = exportDrive(ctx, svc, opts, res)
+		}
+	case "calendar":
+		res.Path = filepath.Join("Takeout", "Calendar")
+		var svc *calendar.Service
+		if svc, err = newCalendarService(ctx, account); err == nil {
+			err = exportCalendars(ctx, svc, opts, res)
+		}
+	case "contacts":
+		res.Path = filepath.Join("Takeout", "Contacts", "All Contacts.vcf")
+		var svc *people.Service
+		if svc, err = newPeopleContactsService(ctx, account); err == nil {
+			err = exportContacts(ctx, svc, opts, res)
+		}
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
+
+func readExportManifest(dir string) (*exportManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, exportManifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m exportManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("read %s: %w", exportManifestName, err)
+	}
+	return &m, nil
+}
+
+func writeExportManifest(dir string, m *exportManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, exportManifestName+".tmp")
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, exportManifestName))
+}
+
+// createExportFile creates path (and its parent dirs) for writing.
+func createExportFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+}
+
+// exportGmail writes matching messages to an mboxrd file, newest first,
+// with Takeout's X-GM-THRID and X-Gmail-Labels headers.
+func exportGmail(ctx context.Context, svc *gmail.Service, opts exportOptions, res *exportServiceResult) error {
+	labelNames, err := fetchLabelIDToName(ctx, svc)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	page := ""
+	for opts.gmailMax == 0 || int64(len(ids)) < opts.gmailMax {
+		call := svc.Users.Messages.List("me").MaxResults(500).PageToken(page).Context(ctx)
+		if opts.gmailQuery != "" {
+			call = call.Q(opts.gmailQuery)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return err
+		}
+		for _, m := range resp.Messages {
+			if m != nil && m.Id != "" {
+				ids = append(ids, m.Id)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		page = resp.NextPageToken
+	}
+	if opts.gmailMax > 0 && int64(len(ids)) > opts.gmailMax {
+		ids = ids[:opts.gmailMax]
+	}
+
+	f, err := createExportFile(filepath.Join(opts.out, res.Path))
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, id := range ids {
+		msg, err := svc.Users.Messages.Get("me", id).Format("raw").Context(ctx).Do()
+		if err == nil {
+			var raw []byte
+			if raw, err = decodeGmailRaw(msg.Raw); err == nil {
+				err = writeMboxMessage(w, msg, raw, labelNames)
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				_ = f.Close()
+				return ctx.Err()
+			}
+			res.Failed = append(res.Failed, exportFailure{ID: id, Error: err.Error()})
+			continue
+		}
+		res.Items++
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if st, err := os.Stat(filepath.Join(opts.out, res.Path)); err == nil {
+		res.Bytes = st.Size()
+	}
+	return nil
+}
+
+// writeMboxMessage appends one message in mboxrd format: a "From " line,
+// LF line endings and ">"-quoted body lines that start with ">*From ".
+func writeMboxMessage(w io.Writer, msg *gmail.Message, raw []byte, labelNames map[string]string) error {
+	date := time.UnixMilli(msg.InternalDate).UTC()
+	var b strings.Builder
+	fmt.Fprintf(&b, "From %s@xxx %s\n", msg.Id, date.Format("Mon Jan _2 15:04:05 -0700 2006"))
+	if msg.ThreadId != "" {
+		fmt.Fprintf(&b, "X-GM-THRID: %s\n", msg.ThreadId)
+	}
+	labels := make([]string, 0, len(msg.LabelIds))
+	for _, id := range msg.LabelIds {
+		name := labelNames[id]
+		if name == "" {
+			name = id
+		}
+		labels = append(labels, name)
+	}
+	if len(labels) > 0 {
+		fmt.Fprintf(&b, "X-Gmail-Labels: %s\n", strings.Join(labels, ","))
+	}
+	text := strings.ReplaceAll(string(raw), "\r\n", "\n")
+	for _, line := range strings.SplitAfter(strings.TrimRight(text, "\n")+"\n", "\n") {
+		if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+			b.WriteByte('>')
+		}
+		b.WriteString(line)
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// exportDrive mirrors opts.driveFolder into the Drive dir. Files whose
+// modified time matches the previous manifest and that are still on disk
+// are kept as they are.
+func exportDrive(ctx context.Context, svc *drive.Service, opts exportOptions, res *exportServiceResult) error {
+	prev := map[string]exportFile{}
+	if opts.previous != nil {
+		for _, s := range opts.previous.Services {
+			if s.Service != "drive" {
+				continue
+			}
+			for _, f := range s.Files {
+				prev[f.ID] = f
+			}
+		}
+	}
+	root := filepath.Join(opts.out, res.Path)
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return err
+	}
+	return exportDriveFolder(ctx, svc, opts.driveFolder, opts.out, res.Path, map[string]bool{opts.driveFolder: true}, prev, res)
+}
+
+func exportDriveFolder(ctx context.Context, svc *drive.Service, folderID, out, rel string, seen map[string]bool, prev map[string]exportFile, res *exportServiceResult) error {
+	var files []*drive.File
+	page := ""
+	for {
+		resp, err := svc.Files.List().
+			Q(fmt.Sprintf("'%s' in parents and trashed = false", escapeDriveQueryString(folderID))).
+			PageSize(1000).
+			PageToken(page).
+			SupportsAllDrives(true).
+			IncludeItemsFromAllDrives(true).
+			OrderBy("name").
+			Fields("nextPageToken, files(id, name, mimeType, modifiedTime)").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return err
+		}
+		files = append(files, resp.Files...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		page = resp.NextPageToken
+	}
+
+	used := map[string]bool{}
+	for _, f := range files {
+		if f == nil || seen[f.Id] {
+			continue
+		}
+		seen[f.Id] = true
+		if f.MimeType == driveFolderMimeType {
+			sub := filepath.Join(rel, uniqueExportName(used, exportSafeName(f.Name)))
+			if err := os.MkdirAll(filepath.Join(out, sub), 0o700); err != nil {
+				return err
+			}
+			if err := exportDriveFolder(ctx, svc, f.Id, out, sub, seen, prev, res); err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				res.Failed = append(res.Failed, exportFailure{ID: f.Id, Name: f.Name, Error: err.Error()})
+			}
+			continue
+		}
+		name := exportSafeName(f.Name)
+		if strings.HasPrefix(f.MimeType, "application/vnd.google-apps.") {
+			if !driveExportable(f.MimeType) {
+				res.Skipped++
+				continue
+			}
+			name = replaceExt(name, driveExportExtension(driveExportMimeType(f.MimeType)))
+		}
+		name = uniqueExportName(used, name)
+		relPath := filepath.Join(rel, name)
+
+		if p, ok := prev[f.Id]; ok && p.Path == relPath && p.Modified == f.ModifiedTime {
+			if st, err := os.Stat(filepath.Join(out, relPath)); err == nil && st.Size() == p.Bytes {
+				res.Files = append(res.Files, p)
+				res.Items++
+				res.Unchanged++
+				res.Bytes += p.Bytes
+				continue
+			}
+		}
+		outPath, n, err := downloadDriveFile(ctx, svc, f, filepath.Join(out, relPath), "")
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			res.Failed = append(res.Failed, exportFailure{ID: f.Id, Name: f.Name, Error: err.Error()})
+			continue
+		}
+		relOut, _ := filepath.Rel(out, outPath)
+		res.Files = append(res.Files, exportFile{ID: f.Id, Path: relOut, Bytes: n, Modified: f.ModifiedTime})
+		res.Items++
+		res.Bytes += n
+	}
+	return nil
+}
+
+// driveExportable reports whether a Google-native file has an export
+// format (Forms, Sites, shortcuts, ... don't).
+func driveExportable(mimeType string) bool {
+	switch mimeType {
+	case "application/vnd.google-apps.document",
+		"application/vnd.google-apps.spreadsheet",
+		"application/vnd.google-apps.presentation",
+		"application/vnd.google-apps.drawing":
+		return true
+	default:
+		return false
+	}
+}
+
+// exportCalendars writes each calendar's events (recurring events as
+// RRULE masters) to <summary>.ics.
+func exportCalendars(ctx context.Context, svc *calendar.Service, opts exportOptions, res *exportServiceResult) error {
+	type cal struct{ id, name string }
+	var cals []cal
+	if len(opts.calendars) > 0 {
+		for _, id := range opts.calendars {
+			cals = append(cals, cal{id: id, name: id})
+		}
+	} else {
+		page := ""
+		for {
+			resp, err := svc.CalendarList.List().PageToken(page).Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			for _, c := range resp.Items {
+				if c != nil {
+					cals = append(cals, cal{id: c.Id, name: orDash(c.Summary)})
+				}
+			}
+			if resp.NextPageToken == "" {
+				break
+			}
+			page = resp.NextPageToken
+		}
+	}
+
+	used := map[string]bool{}
+	for _, c := range cals {
+		var events []*calendar.Event
+		page := ""
+		var err error
+		for {
+			var resp *calendar.Events
+			resp, err = svc.Events.List(c.id).MaxResults(2500).PageToken(page).Context(ctx).Do()
+			if err != nil {
+				break
+			}
+			events = append(events, resp.Items...)
+			if resp.NextPageToken == "" {
+				break
+			}
+			page = resp.NextPageToken
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			res.Failed = append(res.Failed, exportFailure{ID: c.id, Name: c.name, Error: err.Error()})
+			continue
+		}
+		relPath := filepath.Join(res.Path, uniqueExportName(used, exportSafeName(c.name)+".ics"))
+		data := eventsToICS(c.name, events)
+		f, err := createExportFile(filepath.Join(opts.out, relPath))
+		if err == nil {
+			_, err = f.WriteString(data)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			return err
+		}
+		res.Files = append(res.Files, exportFile{ID: c.id, Path: relPath, Bytes: int64(len(data))})
+		res.Items += len(events)
+		res.Bytes += int64(len(data))
+	}
+	return nil
+}
+
+// eventsToICS renders events as an iCalendar (RFC 5545) calendar.
+func eventsToICS(name string, events []*calendar.Event) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldVCardLine(s))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//gogcli//export//EN")
+	line("X-WR-CALNAME:" + escapeVCard(name))
+	for _, e := range events {
+		if e == nil || e.Start == nil {
+			continue
+		}
+		line("BEGIN:VEVENT")
+		uid := e.ICalUID
+		if uid == "" {
+			uid = e.Id
+		}
+		line("UID:" + uid)
+		stamp := time.Now().UTC()
+		if t, err := time.Parse(time.RFC3339, e.Updated); err == nil {
+			stamp = t.UTC()
+		}
+		line("DTSTAMP:" + stamp.Format("20060102T150405Z"))
+		line(icsTime("DTSTART", e.Start))
+		if e.End != nil {
+			line(icsTime("DTEND", e.End))
+		}
+		if e.RecurringEventId != "" && e.OriginalStartTime != nil {
+			line(icsTime("RECURRENCE-ID", e.OriginalStartTime))
+		}
+		for _, r := range e.Recurrence {
+			line(r)
+		}
+		if e.Summary != "" {
+			line("SUMMARY:" + escapeVCard(e.Summary))
+		}
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeVCard(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION:" + escapeVCard(e.Location))
+		}
+		if e.Status != "" {
+			line("STATUS:" + strings.ToUpper(e.Status))
+		}
+		if e.HtmlLink != "" {
+			line("URL:" + e.HtmlLink)
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// icsTime formats an all-day date as VALUE=DATE and a timed start in UTC.
+func icsTime(prop string, t *calendar.EventDateTime) string {
+	if t.Date != "" {
+		return prop + ";VALUE=DATE:" + strings.ReplaceAll(t.Date, "-", "")
+	}
+	parsed, err := time.Parse(time.RFC3339, t.DateTime)
+	if err != nil {
+		return prop + ":" + t.DateTime
+	}
+	return prop + ":" + parsed.UTC().Format("20060102T150405Z")
+}
+
+func exportContacts(ctx context.Context, svc *people.Service, opts exportOptions, res *exportServiceResult) error {
+	var b strings.Builder
+	page := ""
+	for {
+		resp, err := svc.People.Connections.List("people/me").
+			PersonFields(contactsVCardFields).
+			PageSize(1000).
+			PageToken(page).
+			Context(ctx).
+			Do()
+		if err != nil {
+			return err
+		}
+		for _, p := range resp.Connections {
+			if p != nil {
+				b.WriteString(personToVCard(p))
+				res.Items++
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		page = resp.NextPageToken
+	}
+	f, err := createExportFile(filepath.Join(opts.out, res.Path))
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(b.String())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	res.Bytes = int64(b.Len())
+	return err
+}
+
+// exportSafeName turns a Drive or calendar name into a single path element.
+func exportSafeName(name string) string {
+	name = strings.TrimSpace(strings.NewReplacer("/", "_", `\`, "_", "\x00", "").Replace(name))
+	if name == "" || name == "." || name == ".." {
+		return "untitled"
+	}
+	return name
+}
+
+// uniqueExportName returns name, or "name (n).ext" when a sibling already
+// uses it, the way Takeout disambiguates duplicates.
+func uniqueExportName(used map[string]bool, name string) string {
+	candidate := name
+	ext := filepath.Ext(name)
+	for n := 1; used[strings.ToLower(candidate)]; n++ {
+		candidate = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, ext), n, ext)
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_ExportAll(t *testing.T) {
+	origGmail, origDrive, origCal := newGmailService, newDriveService, newCalendarService
+	origDL, origExport := driveDownload, driveExportDownload
+	t.Cleanup(func() {
+		newGmailService, newDriveService, newCalendarService = origGmail, origDrive, origCal
+		driveDownload, driveExportDownload = origDL, origExport
+	})
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+
+	raw := base64.RawURLEncoding.EncodeToString([]byte("Subject: hi\r\n\r\nFrom the top\r\nbye\r\n"))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body any
+		switch p := r.URL.Path; {
+		case strings.HasSuffix(p, "/users/me/labels"):
+			body = map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX"}, {"id": "Label_1", "name": "Receipts"}}}
+		case strings.HasSuffix(p, "/users/me/messages"):
+			body = map[string]any{"messages": []map[string]any{{"id": "m1"}}}
+		case strings.HasSuffix(p, "/users/me/messages/m1"):
+			body = map[string]any{"id": "m1", "threadId": "t1", "labelIds": []string{"INBOX", "Label_1"}, "internalDate": "1735689600000", "raw": raw}
+		case strings.HasSuffix(p, "/files"):
+			switch q := r.URL.Query().Get("q"); {
+			case strings.Contains(q, "'root' in parents"):
+				body = map[string]any{"files": []map[string]any{
+					{"id": "f1", "name": "Work", "mimeType": driveFolderMimeType},
+					{"id": "d1", "name": "Plan", "mimeType": "application/vnd.google-apps.document", "modifiedTime": "2025-01-01T00:00:00Z"},
+					{"id": "x1", "name": "Survey", "mimeType": "application/vnd.google-apps.form"},
+				}}
+			case strings.Contains(q, "'f1' in parents"):
+				body = map[string]any{"files": []map[string]any{
+					{"id": "b1", "name": "notes.txt", "mimeType": "text/plain", "modifiedTime": "2025-01-02T00:00:00Z"},
+					{"id": "b2", "name": "notes.txt", "mimeType": "text/plain", "modifiedTime": "2025-01-02T00:00:00Z"},
+				}}
+			default:
+				body = map[string]any{"files": []any{}}
+			}
+		case strings.HasSuffix(p, "/users/me/calendarList"):
+			body = map[string]any{"items": []map[string]any{{"id": "primary", "summary": "Me"}}}
+		case strings.HasSuffix(p, "/calendars/primary/events"):
+			body = map[string]any{"items": []map[string]any{
+				{"id": "e1", "iCalUID": "e1@google.com", "summary": "Standup; daily", "start": map[string]any{"dateTime": "2025-01-06T09:00:00+01:00"},
+					"end": map[string]any{"dateTime": "2025-01-06T09:15:00+01:00"}, "recurrence": []string{"RRULE:FREQ=DAILY"}},
+				{"id": "e2", "summary": "Offsite", "start": map[string]any{"date": "2025-03-01"}, "end": map[string]any{"date": "2025-03-02"}},
+			}}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer srv.Close()
+
+	opts := []option.ClientOption{option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL + "/")}
+	gsvc, _ := gmail.NewService(context.Background(), opts...)
+	dsvc, _ := drive.NewService(context.Background(), opts...)
+	csvc, _ := calendar.NewService(context.Background(), opts...)
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return gsvc, nil }
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return dsvc, nil }
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return csvc, nil }
+	downloads := 0
+	driveDownload = func(_ context.Context, _ *drive.Service, id string) (*http.Response, error) {
+		downloads++
+		return &http.Response{Status: "200 OK", StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("text " + id))}, nil
+	}
+	driveExportDownload = func(context.Context, *drive.Service, string, string) (*http.Response, error) {
+		downloads++
+		return &http.Response{Status: "200 OK", StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("%PDF"))}, nil
+	}
+
+	out := t.TempDir()
+	run := func() exportManifest {
+		t.Helper()
+		stdout := captureStdout(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "export", "all", "--out", out}); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+		})
+		var m exportManifest
+		if err := json.Unmarshal([]byte(stdout), &m); err != nil {
+			t.Fatalf("decode %q: %v", stdout, err)
+		}
+		return m
+	}
+
+	m := run()
+	if len(m.Services) != 3 || m.Services[0].Items != 1 || m.Services[1].Items != 3 || m.Services[1].Skipped != 1 || m.Services[2].Items != 2 {
+		t.Fatalf("manifest = %+v", m.Services)
+	}
+
+	mbox, err := os.ReadFile(filepath.Join(out, "Takeout", "Mail", "All mail.mbox"))
+	if err != nil {
+		t.Fatalf("mbox: %v", err)
+	}
+	for _, want := range []string{"From m1@xxx Wed Jan  1 00:00:00 +0000 2025\n", "X-Gmail-Labels: INBOX,Receipts\n", "\n>From the top\nbye\n\n"} {
+		if !strings.Contains(string(mbox), want) {
+			t.Fatalf("mbox missing %q:\n%s", want, mbox)
+		}
+	}
+
+	for _, p := range []string{"Plan.pdf", filepath.Join("Work", "notes.txt"), filepath.Join("Work", "notes (1).txt")} {
+		if _, err := os.Stat(filepath.Join(out, "Takeout", "Drive", p)); err != nil {
+			t.Fatalf("drive file %s: %v", p, err)
+		}
+	}
+
+	ics, err := os.ReadFile(filepath.Join(out, "Takeout", "Calendar", "Me.ics"))
+	if err != nil {
+		t.Fatalf("ics: %v", err)
+	}
+	for _, want := range []string{"UID:e1@google.com\r\n", "DTSTART:20250106T080000Z\r\n", "RRULE:FREQ=DAILY\r\n", `SUMMARY:Standup\; daily`, "DTSTART;VALUE=DATE:20250301\r\n"} {
+		if !strings.Contains(string(ics), want) {
+			t.Fatalf("ics missing %q:\n%s", want, ics)
+		}
+	}
+
+	// A second run only downloads files that changed.
+	downloads = 0
+	m = run()
+	if downloads != 0 || m.Services[1].Unchanged != 3 {
+		t.Fatalf("second run downloads=%d drive=%+v", downloads, m.Services[1])
+	}
+}
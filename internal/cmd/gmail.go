@@ -3,38 +3,53 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"html"
 	"net/mail"
 	"os"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
-	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/ui"
 	"google.golang.org/api/gmail/v1"
 )
 
-var newGmailService = googleapi.NewGmail
-
 func newGmailCmd(flags *rootFlags) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "gmail",
 		Short: "Gmail",
 	}
 	cmd.AddCommand(newGmailSearchCmd(flags))
+	cmd.AddCommand(newGmailSavedSearchCmd(flags))
 	cmd.AddCommand(newGmailThreadCmd(flags))
 	cmd.AddCommand(newGmailGetCmd(flags))
 	cmd.AddCommand(newGmailAttachmentCmd(flags))
+	cmd.AddCommand(newGmailAttachmentsCmd(flags))
 	cmd.AddCommand(newGmailURLCmd(flags))
 	cmd.AddCommand(newGmailLabelsCmd(flags))
+	cmd.AddCommand(newGmailStarCmd(flags))
+	cmd.AddCommand(newGmailUnstarCmd(flags))
+	cmd.AddCommand(newGmailStarredCmd(flags))
 	cmd.AddCommand(newGmailSendCmd(flags))
+	cmd.AddCommand(newGmailReplyCmd(flags))
+	cmd.AddCommand(newGmailSentCmd(flags))
+	cmd.AddCommand(newGmailFollowupCmd(flags))
+	cmd.AddCommand(newGmailDigestCmd(flags))
 	cmd.AddCommand(newGmailDraftsCmd(flags))
+	cmd.AddCommand(newGmailImportCmd(flags))
+	cmd.AddCommand(newGmailInsertCmd(flags))
 	cmd.AddCommand(newGmailWatchCmd(flags))
+	cmd.AddCommand(newGmailNotifyCmd(flags))
 	cmd.AddCommand(newGmailHistoryCmd(flags))
+	cmd.AddCommand(newGmailSyncCmd(flags))
 	cmd.AddCommand(newGmailAutoForwardCmd(flags))
 	cmd.AddCommand(newGmailBatchCmd(flags))
+	cmd.AddCommand(newGmailTUICmd(flags))
+	cmd.AddCommand(newGmailTrashCmd(flags))
+	cmd.AddCommand(newGmailSpamCmd(flags))
 	cmd.AddCommand(newGmailDelegatesCmd(flags))
 	cmd.AddCommand(newGmailFiltersCmd(flags))
 	cmd.AddCommand(newGmailForwardingCmd(flags))
@@ -46,11 +61,57 @@ func newGmailCmd(flags *rootFlags) *cobra.Command {
 func newGmailSearchCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var groupBy string
+	var preview int
+	var unreadOnly bool
+	var unansweredOnly bool
+	var categories []string
+	var noSyncCache bool
+	var local bool
+	var regex bool
+	var fuzzy bool
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "search <query>",
 		Short: "Search threads using Gmail query syntax",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Search using Gmail query syntax.
+
+--group-by controls how rows are aggregated:
+  thread   one row per conversation (default)
+  message  one row per message (copies with the same Message-ID are dropped)
+  sender   one row per sender address with message/thread counts
+  day      one row per day (newest first) with message/thread counts
+
+With message/sender/day, --max and --page apply to messages, not threads.
+
+With --output ndjson, thread results stream one JSON object per line and
+follow page tokens until --max threads were written; the other groupings
+print one row per line for the requested page.
+
+JSON output always includes each row's snippet; --preview N adds a PREVIEW
+column with the snippet truncated to N characters.
+
+The STATE column marks unread threads and threads whose newest message was
+sent by you (answered). --unread-only adds is:unread to the query;
+--unanswered-only keeps threads whose newest message is incoming, so a page
+may show fewer than --max rows.
+
+--category limits results to inbox tabs (primary, social, promotions,
+updates, forums; several are ORed). JSON rows carry the tab as "category".
+
+After gmail sync, thread details come from the local cache while Gmail
+reports the thread unchanged; --no-sync-cache always fetches them (--no-cache
+bypasses the HTTP response cache).
+
+--local searches the gmail sync cache offline instead of Gmail. The query
+is a list of words that must all appear (from:, to:, subject:, body: and
+label: restrict a word to one field); --fuzzy also accepts near-misses
+(typos) and --regex treats the query as a case-insensitive regular
+expression. Bodies are searched when the cache was synced with --bodies.
+Rows are messages; the default thread grouping keeps the newest match
+per thread.`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			u := ui.FromContext(cmd.Context())
 			account, err := requireAccount(flags)
@@ -58,38 +119,123 @@ func newGmailSearchCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 			query := strings.Join(args, " ")
+			groupBy, err := validateSearchGroupBy(groupBy)
+			if err != nil {
+				return err
+			}
+			if preview < 0 {
+				return usage("--preview must be >= 0")
+			}
+			if unansweredOnly && groupBy != searchGroupThread {
+				return usage("--unanswered-only requires --group-by thread")
+			}
+			if (regex || fuzzy) && !local {
+				return usage("--regex and --fuzzy require --local")
+			}
+			if local {
+				if regex && fuzzy {
+					return usage("use either --regex or --fuzzy")
+				}
+				if page != "" || pages.All || unansweredOnly || len(categories) > 0 {
+					return usage("--page, --all, --unanswered-only and --category do not apply to --local")
+				}
+				mode := localSearchTerms
+				if regex {
+					mode = localSearchRegex
+				} else if fuzzy {
+					mode = localSearchFuzzy
+				}
+				return runGmailLocalSearch(cmd.Context(), account, query, max, groupBy, preview, unreadOnly, mode)
+			}
+			if unreadOnly {
+				query = "(" + query + ") is:unread"
+			}
+			catQuery, err := categoryQuery(categories)
+			if err != nil {
+				return err
+			}
+			if catQuery != "" {
+				query = "(" + query + ") " + catQuery
+			}
 
 			svc, err := newGmailService(cmd.Context(), account)
 			if err != nil {
 				return err
 			}
 
-			resp, err := svc.Users.Threads.List("me").
-				Q(query).
-				MaxResults(max).
-				PageToken(page).
-				Context(cmd.Context()).
-				Do()
+			if groupBy != searchGroupThread {
+				return runGmailMessageSearch(cmd.Context(), svc, query, max, page, pages, groupBy, preview)
+			}
+
+			ctx := cmd.Context()
+			if !noSyncCache {
+				threads, err := openGmailThreadCache(account)
+				if err != nil {
+					u.Err().Printf("WARN: ignoring thread cache: %v", err)
+				}
+				ctx = withGmailThreadCache(ctx, threads)
+			}
+
+			if outfmt.IsNDJSON(cmd.Context()) {
+				idToName, err := fetchLabelIDToName(cmd.Context(), svc)
+				if err != nil {
+					return err
+				}
+				return streamNDJSONPages(cmd.Context(), page, pages.ndjsonCap(max), 500, func(pageToken string, pageSize int64) ([]threadItem, string, error) {
+					resp, err := svc.Users.Threads.List("me").Q(query).MaxResults(pageSize).PageToken(pageToken).Context(cmd.Context()).Do()
+					if err != nil {
+						return nil, "", err
+					}
+					items, err := fetchThreadDetails(ctx, svc, resp.Threads, idToName)
+					if err != nil {
+						return nil, "", err
+					}
+					if unansweredOnly {
+						items = filterNeedsReply(items)
+					}
+					return items, resp.NextPageToken, nil
+				})
+			}
+
+			threads, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*gmail.Thread, string, error) {
+				resp, err := svc.Users.Threads.List("me").
+					Q(query).
+					MaxResults(max).
+					PageToken(pageToken).
+					Context(cmd.Context()).
+					Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Threads, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(threads))
 			if err != nil {
 				return err
 			}
 
-			idToName, err := fetchLabelIDToName(svc)
+			idToName, err := fetchLabelIDToName(cmd.Context(), svc)
 			if err != nil {
 				return err
 			}
 
 			// Fetch thread details concurrently (fixes N+1 query pattern)
-			items, err := fetchThreadDetails(cmd.Context(), svc, resp.Threads, idToName)
+			items, err := fetchThreadDetails(ctx, svc, threads, idToName)
 			if err != nil {
 				return err
 			}
+			if unansweredOnly {
+				items = filterNeedsReply(items)
+			}
 
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
 					"threads":       items,
-					"nextPageToken": resp.NextPageToken,
-				})
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
 
 			if len(items) == 0 {
@@ -100,20 +246,50 @@ func newGmailSearchCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 
-			fmt.Fprintln(w, "ID\tDATE\tFROM\tSUBJECT\tLABELS")
+			if preview > 0 {
+				fmt.Fprintln(w, "ID\tMSGS\tDATE\tFROM\tSUBJECT\tLABELS\tPREVIEW\tSTATE")
+			} else {
+				fmt.Fprintln(w, "ID\tMSGS\tDATE\tFROM\tSUBJECT\tLABELS\tSTATE")
+			}
 			for _, it := range items {
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", it.ID, it.Date, it.From, it.Subject, strings.Join(it.Labels, ","))
+				state := stateCell(u, it.Unread, it.Answered)
+				if preview > 0 {
+					fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", it.ID, it.Messages, it.Date, it.From, it.Subject, strings.Join(it.Labels, ","), previewText(it.Snippet, preview), state)
+					continue
+				}
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", it.ID, it.Messages, it.Date, it.From, it.Subject, strings.Join(it.Labels, ","), state)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 10, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	cmd.Flags().StringVar(&groupBy, "group-by", searchGroupThread, "Row aggregation: thread|message|sender|day")
+	cmd.Flags().IntVar(&preview, "preview", 0, "Add a PREVIEW column with the snippet truncated to N characters")
+	cmd.Flags().BoolVar(&unreadOnly, "unread-only", false, "Only unread results (adds is:unread to the query)")
+	cmd.Flags().BoolVar(&unansweredOnly, "unanswered-only", false, "Only threads whose newest message is incoming")
+	cmd.Flags().BoolVar(&local, "local", false, "Search the gmail sync cache offline instead of Gmail")
+	cmd.Flags().BoolVar(&regex, "regex", false, "With --local: treat the query as a regular expression")
+	cmd.Flags().BoolVar(&fuzzy, "fuzzy", false, "With --local: also match words within a small edit distance")
+	cmd.Flags().BoolVar(&noSyncCache, "no-sync-cache", false, "Fetch thread details even when gmail sync has cached them")
+	cmd.Flags().StringSliceVar(&categories, "category", nil, "Only these inbox tabs: primary|social|promotions|updates|forums")
+	pages.addFlags(cmd)
 	return cmd
 }
 
+// filterNeedsReply keeps threads whose newest message is incoming.
+func filterNeedsReply(items []threadItem) []threadItem {
+	kept := items[:0]
+	for _, it := range items {
+		if it.needsReply {
+			kept = append(kept, it)
+		}
+	}
+	return kept
+}
+
 func firstMessage(t *gmail.Thread) *gmail.Message {
 	if t == nil || len(t.Messages) == 0 {
 		return nil
@@ -148,6 +324,24 @@ func sanitizeTab(s string) string {
 	return strings.ReplaceAll(s, "\t", " ")
 }
 
+// gmailSnippet decodes the HTML entities Gmail uses in snippets.
+func gmailSnippet(s string) string {
+	return strings.TrimSpace(html.UnescapeString(s))
+}
+
+// previewText collapses whitespace and truncates s to n characters with an ellipsis.
+func previewText(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n == 1 {
+		return "…"
+	}
+	r := []rune(s)
+	return strings.TrimRight(string(r[:n-1]), " ") + "…"
+}
+
 func mailParseDate(s string) (time.Time, error) {
 	// net/mail has the most compatible Date parser, but we keep this isolated for easier tests/mocks later.
 	return mail.ParseDate(s)
@@ -155,19 +349,31 @@ func mailParseDate(s string) (time.Time, error) {
 
 // threadItem holds parsed thread metadata for display/JSON output
 type threadItem struct {
-	ID      string   `json:"id"`
-	Date    string   `json:"date,omitempty"`
-	From    string   `json:"from,omitempty"`
-	Subject string   `json:"subject,omitempty"`
-	Labels  []string `json:"labels,omitempty"`
+	ID       string   `json:"id"`
+	Date     string   `json:"date,omitempty"`
+	From     string   `json:"from,omitempty"`
+	Subject  string   `json:"subject,omitempty"`
+	Labels   []string `json:"labels,omitempty"`
+	Category string   `json:"category,omitempty"`
+	Snippet  string   `json:"snippet,omitempty"`
+	Unread   bool     `json:"unread"`
+	Answered bool     `json:"answered"`
+	// Messages, Participants and LastActivity come from summarizeThread.
+	Messages     int      `json:"messageCount"`
+	Participants []string `json:"participants,omitempty"`
+	LastActivity string   `json:"lastActivity,omitempty"`
+
+	needsReply bool
 }
 
 // fetchThreadDetails fetches thread metadata concurrently with bounded parallelism.
-// This eliminates N+1 queries by fetching all threads in parallel.
+// This eliminates N+1 queries by fetching all threads in parallel. Threads
+// unchanged since the gmail sync cache on ctx stored them are not refetched.
 func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmail.Thread, idToName map[string]string) ([]threadItem, error) {
 	if len(threads) == 0 {
 		return nil, nil
 	}
+	cache := gmailThreadCacheFromContext(ctx)
 
 	const maxConcurrency = 10 // Limit parallel requests to avoid rate limiting
 	sem := make(chan struct{}, maxConcurrency)
@@ -187,7 +393,7 @@ func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmai
 		}
 
 		wg.Add(1)
-		go func(idx int, threadID string) {
+		go func(idx int, threadID string, historyID uint64) {
 			defer wg.Done()
 
 			// Acquire semaphore
@@ -199,14 +405,19 @@ func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmai
 				return
 			}
 
-			thread, err := svc.Users.Threads.Get("me", threadID).
-				Format("metadata").
-				MetadataHeaders("From", "Subject", "Date").
-				Context(ctx).
-				Do()
-			if err != nil {
-				results <- result{index: idx, err: err}
-				return
+			thread := cache.thread(threadID, historyID)
+			if thread == nil {
+				var err error
+				thread, err = svc.Users.Threads.Get("me", threadID).
+					Format("metadata").
+					MetadataHeaders("From", "To", "Cc", "Subject", "Date").
+					Context(ctx).
+					Do()
+				if err != nil {
+					results <- result{index: idx, err: err}
+					return
+				}
+				_ = cache.putThread(thread) // best effort; a miss only costs a refetch
 			}
 
 			item := threadItem{ID: threadID}
@@ -214,21 +425,21 @@ func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmai
 				item.Date = formatGmailDate(headerValue(msg.Payload, "Date"))
 				item.From = sanitizeTab(headerValue(msg.Payload, "From"))
 				item.Subject = sanitizeTab(headerValue(msg.Payload, "Subject"))
-				if len(msg.LabelIds) > 0 {
-					names := make([]string, 0, len(msg.LabelIds))
-					for _, id := range msg.LabelIds {
-						if n, ok := idToName[id]; ok {
-							names = append(names, n)
-						} else {
-							names = append(names, id)
-						}
-					}
-					item.Labels = names
-				}
+				item.Labels = labelNames(msg.LabelIds, idToName)
+				item.Category = categoryOf(msg.LabelIds)
+			}
+			item.Unread, item.Answered, item.needsReply = threadState(thread)
+			summary := summarizeThread(thread)
+			item.Messages = summary.Messages
+			item.Participants = summary.participantEmails()
+			item.LastActivity = summary.LastActivity
+			// Like the Gmail UI, preview the newest message in the thread.
+			if n := len(thread.Messages); n > 0 && thread.Messages[n-1] != nil {
+				item.Snippet = gmailSnippet(thread.Messages[n-1].Snippet)
 			}
 
 			results <- result{index: idx, item: item}
-		}(i, t.Id)
+		}(i, t.Id, t.HistoryId)
 	}
 
 	// Close results channel when all goroutines complete
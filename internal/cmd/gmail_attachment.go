@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -87,6 +88,7 @@ func newGmailAttachmentCmd(flags *rootFlags) *cobra.Command {
 
 	cmd.Flags().StringVar(&outPath, "out", "", "Write to a specific path (default: gogcli config dir)")
 	cmd.Flags().StringVar(&name, "name", "", "Filename (only used when --out is empty)")
+	cmd.AddCommand(newGmailAttachmentDownloadAllCmd(flags))
 	return cmd
 }
 
@@ -131,7 +133,7 @@ func downloadAttachmentToPath(
 	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
 		return "", false, 0, err
 	}
-	if err := os.WriteFile(outPath, data, 0o600); err != nil {
+	if _, err := saveDownload(cmd.Context(), "gmail", outPath, bytes.NewReader(data), 0o600); err != nil {
 		return "", false, 0, err
 	}
 	return outPath, false, int64(len(data)), nil
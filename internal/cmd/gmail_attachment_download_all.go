@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+const attachmentManifestName = "manifest.json"
+
+// savedAttachment is one manifest entry.
+type savedAttachment struct {
+	attachmentRow
+	Path string `json:"path"`
+}
+
+type attachmentManifest struct {
+	Query     string            `json:"query"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Messages  int               `json:"messages"`
+	Skipped   int               `json:"skipped"`
+	Saved     []savedAttachment `json:"saved"`
+}
+
+func newGmailAttachmentDownloadAllCmd(flags *rootFlags) *cobra.Command {
+	var query string
+	var outDir string
+	var max int64
+	var mimeFilter string
+	var minSize string
+	var maxSize string
+
+	cmd := &cobra.Command{
+		Use:   "download-all",
+		Short: "Download every attachment of messages matching a query",
+		Long: `Download every attachment of the messages matching --query into --out-dir.
+
+Files keep their original names; collisions get a numeric suffix
+(report.pdf, report-2.pdf, ...) and existing files are never overwritten.
+--mime-filter takes a comma-separated list (image/*,application/pdf);
+--min-size/--max-size accept bytes or KB/MB/GB. A manifest.json describing
+the saved files is written to --out-dir.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(query) == "" {
+				return usage("--query is required")
+			}
+			if strings.TrimSpace(outDir) == "" {
+				return usage("--out-dir is required")
+			}
+			if max <= 0 {
+				return usage("--max must be > 0")
+			}
+			minBytes, err := parseByteSize(minSize)
+			if err != nil {
+				return usagef("invalid --min-size: %v", err)
+			}
+			maxBytes, err := parseByteSize(maxSize)
+			if err != nil {
+				return usagef("invalid --max-size: %v", err)
+			}
+			if maxBytes > 0 && minBytes > maxBytes {
+				return usage("--min-size is larger than --max-size")
+			}
+			mimes := splitCSV(mimeFilter)
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			var refs []*gmail.Message
+			page := ""
+			for int64(len(refs)) < max {
+				resp, listErr := svc.Users.Messages.List("me").
+					Q("(" + strings.TrimSpace(query) + ") has:attachment").
+					MaxResults(min(max-int64(len(refs)), 500)).
+					PageToken(page).
+					Context(cmd.Context()).
+					Do()
+				if listErr != nil {
+					return listErr
+				}
+				refs = append(refs, resp.Messages...)
+				page = resp.NextPageToken
+				if page == "" {
+					break
+				}
+			}
+			msgs, err := fetchFullMessages(cmd.Context(), svc, refs)
+			if err != nil {
+				return err
+			}
+
+			dir := filepath.Clean(outDir)
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+			names := newAttachmentNamer(dir)
+			manifest := attachmentManifest{
+				Query:     query,
+				CreatedAt: time.Now().UTC(),
+				Messages:  len(msgs),
+				Saved:     make([]savedAttachment, 0),
+			}
+			for _, msg := range msgs {
+				from := headerValue(msg.Payload, "From")
+				date := formatGmailDate(headerValue(msg.Payload, "Date"))
+				for _, a := range collectAttachments(msg.Payload) {
+					if !matchesMimeFilter(a.MimeType, mimes) || a.Size < minBytes || (maxBytes > 0 && a.Size > maxBytes) {
+						manifest.Skipped++
+						continue
+					}
+					outPath := names.next(a.Filename)
+					_, _, size, dlErr := downloadAttachmentToPath(cmd, svc, msg.Id, a.AttachmentID, outPath, 0)
+					if dlErr != nil {
+						return fmt.Errorf("message %s attachment %s: %w", msg.Id, a.Filename, dlErr)
+					}
+					manifest.Saved = append(manifest.Saved, savedAttachment{
+						attachmentRow: attachmentRow{
+							MessageID:    msg.Id,
+							ThreadID:     msg.ThreadId,
+							AttachmentID: a.AttachmentID,
+							Filename:     a.Filename,
+							MimeType:     a.MimeType,
+							Size:         size,
+							From:         from,
+							Date:         date,
+						},
+						Path: outPath,
+					})
+				}
+			}
+
+			manifestPath := filepath.Join(dir, attachmentManifestName)
+			payload, err := json.MarshalIndent(manifest, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(manifestPath, append(payload, '\n'), 0o600); err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"manifest": manifestPath,
+					"messages": manifest.Messages,
+					"saved":    manifest.Saved,
+					"skipped":  manifest.Skipped,
+				})
+			}
+			for _, s := range manifest.Saved {
+				u.Out().Successf("Saved: %s", s.Path)
+			}
+			u.Out().Printf("saved\t%d", len(manifest.Saved))
+			u.Out().Printf("skipped\t%d", manifest.Skipped)
+			u.Out().Printf("manifest\t%s", manifestPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&query, "query", "", "Gmail search query (required)")
+	cmd.Flags().StringVar(&outDir, "out-dir", "", "Directory to write attachments to (required)")
+	cmd.Flags().Int64Var(&max, "max", 100, "Max messages to scan")
+	cmd.Flags().StringVar(&mimeFilter, "mime-filter", "", "Only these MIME types, comma-separated (supports type/*)")
+	cmd.Flags().StringVar(&minSize, "min-size", "", "Skip attachments smaller than this (e.g. 10KB)")
+	cmd.Flags().StringVar(&maxSize, "max-size", "", "Skip attachments larger than this (e.g. 5MB)")
+	return cmd
+}
+
+// attachmentNamer hands out unused file names in dir. manifest.json is
+// reserved, and names handed out earlier in the run count as taken.
+type attachmentNamer struct {
+	dir   string
+	taken map[string]struct{}
+}
+
+func newAttachmentNamer(dir string) *attachmentNamer {
+	return &attachmentNamer{dir: dir, taken: map[string]struct{}{attachmentManifestName: {}}}
+}
+
+func (n *attachmentNamer) next(filename string) string {
+	base := filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if base == "" || base == "." || base == ".." || base == "/" {
+		base = "attachment"
+	}
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	candidate := base
+	for i := 2; ; i++ {
+		if _, ok := n.taken[strings.ToLower(candidate)]; !ok {
+			if _, err := os.Lstat(filepath.Join(n.dir, candidate)); os.IsNotExist(err) {
+				break
+			}
+		}
+		candidate = fmt.Sprintf("%s-%d%s", stem, i, ext)
+	}
+	n.taken[strings.ToLower(candidate)] = struct{}{}
+	return filepath.Join(n.dir, candidate)
+}
+
+func matchesMimeFilter(mimeType string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	for _, f := range filters {
+		if ok, _ := path.Match(strings.ToLower(f), mimeType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseByteSize parses "1500", "10KB", "1.5MB" (1024-based, like
+// formatDriveSize). Empty means 0.
+func parseByteSize(raw string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(raw))
+	if s == "" {
+		return 0, nil
+	}
+	mult := int64(1)
+	for _, u := range []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			mult = u.mult
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%q is not a size (use bytes or KB/MB/GB)", raw)
+	}
+	return int64(v * float64(mult)), nil
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+// attachmentRow is one attachment found by a message search.
+type attachmentRow struct {
+	MessageID    string `json:"messageId"`
+	ThreadID     string `json:"threadId,omitempty"`
+	AttachmentID string `json:"attachmentId"`
+	Filename     string `json:"filename"`
+	MimeType     string `json:"mimeType,omitempty"`
+	Size         int64  `json:"size"`
+	From         string `json:"from,omitempty"`
+	Date         string `json:"date,omitempty"`
+}
+
+func newGmailAttachmentsCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attachments",
+		Short: "Find attachments across messages",
+	}
+	cmd.AddCommand(newGmailAttachmentsListCmd(flags))
+	return cmd
+}
+
+func newGmailAttachmentsListCmd(flags *rootFlags) *cobra.Command {
+	var query string
+	var max int64
+	var page string
+	var pages pageFlags
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List attachments of messages matching a query (no downloads)",
+		Long: `List one row per attachment of the messages matching --query.
+
+has:attachment is added to the query. --max and --page apply to messages.
+Use the message and attachment IDs with "gog gmail attachment" to download.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(query) == "" {
+				return usage("--query is required")
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			rows, next, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]attachmentRow, string, error) {
+				return searchAttachments(cmd.Context(), svc, query, max, pageToken)
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(rows))
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"attachments":   rows,
+					"nextPageToken": next,
+				}); err != nil {
+					return err
+				}
+				return partial
+			}
+			if len(rows) == 0 {
+				u.Err().Println("No attachments")
+				printNextPageHint(u, next)
+				return nil
+			}
+
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "MESSAGE\tATTACHMENT\tFILENAME\tTYPE\tSIZE\tFROM\tDATE")
+			for _, r := range rows {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+					r.MessageID,
+					r.AttachmentID,
+					sanitizeTab(r.Filename),
+					r.MimeType,
+					r.Size,
+					r.From,
+					r.Date,
+				)
+			}
+			printNextPageHint(u, next)
+			return partial
+		},
+	}
+
+	cmd.Flags().StringVar(&query, "query", "", "Gmail search query (required)")
+	cmd.Flags().Int64Var(&max, "max", 20, "Max messages")
+	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
+	return cmd
+}
+
+// searchAttachments lists messages matching query (restricted to
+// has:attachment) and returns their attachments in message order.
+func searchAttachments(ctx context.Context, svc *gmail.Service, query string, max int64, page string) ([]attachmentRow, string, error) {
+	resp, err := svc.Users.Messages.List("me").
+		Q("(" + strings.TrimSpace(query) + ") has:attachment").
+		MaxResults(max).
+		PageToken(page).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, "", err
+	}
+	msgs, err := fetchFullMessages(ctx, svc, resp.Messages)
+	if err != nil {
+		return nil, "", err
+	}
+	rows := make([]attachmentRow, 0)
+	for _, msg := range msgs {
+		from := sanitizeTab(headerValue(msg.Payload, "From"))
+		date := formatGmailDate(headerValue(msg.Payload, "Date"))
+		for _, a := range collectAttachments(msg.Payload) {
+			rows = append(rows, attachmentRow{
+				MessageID:    msg.Id,
+				ThreadID:     msg.ThreadId,
+				AttachmentID: a.AttachmentID,
+				Filename:     a.Filename,
+				MimeType:     a.MimeType,
+				Size:         a.Size,
+				From:         from,
+				Date:         date,
+			})
+		}
+	}
+	return rows, resp.NextPageToken, nil
+}
+
+// fetchFullMessages fetches messages (format=full) with bounded parallelism,
+// preserving input order.
+func fetchFullMessages(ctx context.Context, svc *gmail.Service, refs []*gmail.Message) ([]*gmail.Message, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	const maxConcurrency = 10
+	sem := make(chan struct{}, maxConcurrency)
+
+	type result struct {
+		index int
+		msg   *gmail.Message
+		err   error
+	}
+
+	results := make(chan result, len(refs))
+	var wg sync.WaitGroup
+
+	for i, m := range refs {
+		if m == nil || m.Id == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(idx int, id string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results <- result{index: idx, err: ctx.Err()}
+				return
+			}
+
+			msg, err := svc.Users.Messages.Get("me", id).Format("full").Context(ctx).Do()
+			results <- result{index: idx, msg: msg, err: err}
+		}(i, m.Id)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	msgs := make([]*gmail.Message, len(refs))
+	for r := range results {
+		if r.err != nil {
+			return nil, r.err
+		}
+		msgs[r.index] = r.msg
+	}
+
+	out := make([]*gmail.Message, 0, len(msgs))
+	for _, m := range msgs {
+		if m != nil {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -34,6 +35,9 @@ The messages are immediately and permanently deleted, not moved to trash.`,
 			if err != nil {
 				return err
 			}
+			if confirmErr := confirmDestructive(cmd, flags, fmt.Sprintf("permanently delete %d message(s)", len(args))); confirmErr != nil {
+				return confirmErr
+			}
 
 			svc, err := newGmailService(cmd.Context(), account)
 			if err != nil {
@@ -80,13 +84,16 @@ func newGmailBatchModifyCmd(flags *rootFlags) *cobra.Command {
 			if len(addLabels) == 0 && len(removeLabels) == 0 {
 				return errors.New("must specify --add and/or --remove")
 			}
+			if confirmErr := confirmDestructive(cmd, flags, fmt.Sprintf("modify labels on %d message(s)", len(args))); confirmErr != nil {
+				return confirmErr
+			}
 
 			svc, err := newGmailService(cmd.Context(), account)
 			if err != nil {
 				return err
 			}
 
-			idMap, err := fetchLabelNameToID(svc)
+			idMap, err := fetchLabelNameToID(cmd.Context(), svc)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/statefile"
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	gmailCacheVersion      = 1
+	defaultGmailCacheTTL   = 24 * time.Hour
+	defaultGmailSyncMax    = 1000
+	gmailSyncFetchParallel = 10
+	// maxCachedBodyBytes caps the text kept per message by gmail sync --bodies.
+	maxCachedBodyBytes = 32 << 10
+)
+
+// gmailCache is the local copy of an account's message metadata kept by
+// `gmail sync`. Only sync writes it, always from a complete snapshot, so a
+// concurrent sync finishing last at worst leaves an older history ID for the
+// next sync to catch up from. Thread details reused by `gmail search` live in
+// a gmailThreadCache next to it.
+type gmailCache struct {
+	Version    int                       `json:"version"`
+	Account    string                    `json:"account"`
+	HistoryID  string                    `json:"historyId"`
+	SyncedAtMs int64                     `json:"syncedAtMs"`
+	TTLSeconds int64                     `json:"ttlSeconds,omitempty"`
+	Bodies     bool                      `json:"bodies,omitempty"`
+	LabelNames map[string]string         `json:"labelNames,omitempty"`
+	Messages   map[string]*cachedMessage `json:"messages"`
+
+	path string
+}
+
+type cachedMessage struct {
+	ID           string   `json:"id"`
+	ThreadID     string   `json:"threadId"`
+	HistoryID    string   `json:"historyId,omitempty"`
+	InternalDate int64    `json:"internalDate,omitempty"`
+	LabelIDs     []string `json:"labelIds,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           string   `json:"to,omitempty"`
+	Cc           string   `json:"cc,omitempty"`
+	Subject      string   `json:"subject,omitempty"`
+	Date         string   `json:"date,omitempty"`
+	Snippet      string   `json:"snippet,omitempty"`
+	RFCMessageID string   `json:"rfcMessageId,omitempty"`
+	Body         string   `json:"body,omitempty"`
+}
+
+// gmailThreadCache holds the thread details gmail search fetched, one file
+// per thread, so a search reads only the threads it lists and concurrent
+// processes never rewrite each other's entries. A thread is reused while its
+// historyId is unchanged and it is younger than ttl. gmail sync creates it
+// and drops the threads it sees change.
+type gmailThreadCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// gmailThreadSettingsFile keeps the TTL set by gmail sync; thread files are
+// named by their hex thread ID, so the name cannot clash.
+const gmailThreadSettingsFile = "_settings.json"
+
+type gmailThreadSettings struct {
+	TTLSeconds int64 `json:"ttlSeconds,omitempty"`
+}
+
+type cachedThread struct {
+	HistoryID   string        `json:"historyId"`
+	FetchedAtMs int64         `json:"fetchedAtMs"`
+	Thread      *gmail.Thread `json:"thread"`
+}
+
+func gmailCachePath(account string) (string, error) {
+	dir, err := config.GmailCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sanitizeAccountForPath(account)+".json"), nil
+}
+
+func newGmailCache(account, path string) *gmailCache {
+	return &gmailCache{
+		Version:  gmailCacheVersion,
+		Account:  account,
+		Messages: map[string]*cachedMessage{},
+		path:     path,
+	}
+}
+
+// loadGmailCache returns the account's cache, or nil when there is none
+// (or it was written by an incompatible version).
+func loadGmailCache(account string) (*gmailCache, error) {
+	path, err := gmailCachePath(account)
+	if err != nil {
+		return nil, err
+	}
+	data, err := statefile.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	c := newGmailCache(account, path)
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("%s: %w (run gmail sync --rebuild)", path, err)
+	}
+	if c.Version != gmailCacheVersion {
+		return nil, nil
+	}
+	if c.Messages == nil {
+		c.Messages = map[string]*cachedMessage{}
+	}
+	c.path = path
+	return c, nil
+}
+
+func (c *gmailCache) ttl() time.Duration {
+	if c.TTLSeconds <= 0 {
+		return defaultGmailCacheTTL
+	}
+	return time.Duration(c.TTLSeconds) * time.Second
+}
+
+func (c *gmailCache) save() error {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if _, err := config.EnsureGmailCacheDir(); err != nil {
+		return err
+	}
+	return statefile.WriteFile(c.path, payload, 0o600)
+}
+
+func gmailThreadCacheDir(account string) (string, error) {
+	dir, err := config.GmailCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sanitizeAccountForPath(account)+".threads"), nil
+}
+
+// openGmailThreadCache returns the account's thread cache, or nil when gmail
+// sync has not set one up.
+func openGmailThreadCache(account string) (*gmailThreadCache, error) {
+	dir, err := gmailThreadCacheDir(account)
+	if err != nil {
+		return nil, err
+	}
+	data, err := statefile.ReadFile(filepath.Join(dir, gmailThreadSettingsFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s gmailThreadSettings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	c := &gmailThreadCache{dir: dir, ttl: defaultGmailCacheTTL}
+	if s.TTLSeconds > 0 {
+		c.ttl = time.Duration(s.TTLSeconds) * time.Second
+	}
+	return c, nil
+}
+
+// setupGmailThreadCache creates (or, with reset, empties) the account's
+// thread cache and stores ttlSeconds for gmail search.
+func setupGmailThreadCache(account string, ttlSeconds int64, reset bool) (*gmailThreadCache, error) {
+	dir, err := gmailThreadCacheDir(account)
+	if err != nil {
+		return nil, err
+	}
+	if reset {
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := config.EnsureGmailCacheDir(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(gmailThreadSettings{TTLSeconds: ttlSeconds})
+	if err != nil {
+		return nil, err
+	}
+	if err := statefile.WriteFile(filepath.Join(dir, gmailThreadSettingsFile), payload, 0o600); err != nil {
+		return nil, err
+	}
+	return openGmailThreadCache(account)
+}
+
+func (c *gmailThreadCache) path(id string) string {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") || strings.HasPrefix(id, "_") {
+		return ""
+	}
+	return filepath.Join(c.dir, id+".json")
+}
+
+// thread returns the cached thread if Gmail still reports historyID for it.
+// Unreadable entries are treated as missing and fetched again.
+func (c *gmailThreadCache) thread(id string, historyID uint64) *gmail.Thread {
+	if c == nil || historyID == 0 {
+		return nil
+	}
+	path := c.path(id)
+	if path == "" {
+		return nil
+	}
+	data, err := statefile.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var t cachedThread
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil
+	}
+	if t.Thread == nil || t.HistoryID != formatHistoryID(historyID) {
+		return nil
+	}
+	if time.Since(time.UnixMilli(t.FetchedAtMs)) > c.ttl {
+		return nil
+	}
+	return t.Thread
+}
+
+// putThread stores t through a temporary file and a rename, so concurrent
+// readers never see a partial entry.
+func (c *gmailThreadCache) putThread(t *gmail.Thread) error {
+	if c == nil || t == nil || t.HistoryId == 0 {
+		return nil
+	}
+	path := c.path(t.Id)
+	if path == "" {
+		return nil
+	}
+	payload, err := json.Marshal(cachedThread{
+		HistoryID:   formatHistoryID(t.HistoryId),
+		FetchedAtMs: time.Now().UnixMilli(),
+		Thread:      t,
+	})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+	if err := statefile.WriteFile(tmpPath, payload, 0o600); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// drop forgets a thread that changed.
+func (c *gmailThreadCache) drop(id string) error {
+	if c == nil {
+		return nil
+	}
+	path := c.path(id)
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// count returns the number of cached threads.
+func (c *gmailThreadCache) count() int {
+	if c == nil {
+		return 0
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		if name := e.Name(); !e.IsDir() && strings.HasSuffix(name, ".json") && c.path(strings.TrimSuffix(name, ".json")) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func cachedMessageFrom(msg *gmail.Message) *cachedMessage {
+	body, _ := truncateUTF8Bytes(bestBodyText(msg.Payload), maxCachedBodyBytes)
+	return &cachedMessage{
+		ID:           msg.Id,
+		ThreadID:     msg.ThreadId,
+		HistoryID:    formatHistoryID(msg.HistoryId),
+		InternalDate: msg.InternalDate,
+		LabelIDs:     msg.LabelIds,
+		From:         headerValue(msg.Payload, "From"),
+		To:           headerValue(msg.Payload, "To"),
+		Cc:           headerValue(msg.Payload, "Cc"),
+		Subject:      headerValue(msg.Payload, "Subject"),
+		Date:         headerValue(msg.Payload, "Date"),
+		Snippet:      gmailSnippet(msg.Snippet),
+		RFCMessageID: strings.TrimSpace(headerValue(msg.Payload, "Message-ID")),
+		Body:         body,
+	}
+}
+
+type gmailThreadCacheKey struct{}
+
+func withGmailThreadCache(ctx context.Context, c *gmailThreadCache) context.Context {
+	if c == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, gmailThreadCacheKey{}, c)
+}
+
+func gmailThreadCacheFromContext(ctx context.Context) *gmailThreadCache {
+	c, _ := ctx.Value(gmailThreadCacheKey{}).(*gmailThreadCache)
+	return c
+}
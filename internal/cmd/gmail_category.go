@@ -0,0 +1,58 @@
+package cmd
+
+import "strings"
+
+// gmailCategories maps inbox tab names (the category: search operator) to
+// their system label IDs. The Primary tab is labeled CATEGORY_PERSONAL.
+var gmailCategories = []struct {
+	Name    string
+	LabelID string
+}{
+	{"primary", "CATEGORY_PERSONAL"},
+	{"social", "CATEGORY_SOCIAL"},
+	{"promotions", "CATEGORY_PROMOTIONS"},
+	{"updates", "CATEGORY_UPDATES"},
+	{"forums", "CATEGORY_FORUMS"},
+}
+
+// categoryQuery turns --category values (comma-separated or repeated) into a
+// category: clause; several tabs are ORed.
+func categoryQuery(values []string) (string, error) {
+	var terms []string
+	for _, raw := range values {
+		for _, v := range splitCSV(raw) {
+			name := strings.ToLower(v)
+			known := false
+			for _, c := range gmailCategories {
+				if c.Name == name {
+					known = true
+					break
+				}
+			}
+			if !known {
+				return "", usagef("invalid --category %q (expected primary|social|promotions|updates|forums)", v)
+			}
+			terms = append(terms, "category:"+name)
+		}
+	}
+	switch len(terms) {
+	case 0:
+		return "", nil
+	case 1:
+		return terms[0], nil
+	default:
+		return "{" + strings.Join(terms, " ") + "}", nil
+	}
+}
+
+// categoryOf returns the inbox tab for a message's labels ("" if none).
+func categoryOf(labelIDs []string) string {
+	for _, id := range labelIDs {
+		for _, c := range gmailCategories {
+			if id == c.LabelID {
+				return c.Name
+			}
+		}
+	}
+	return ""
+}
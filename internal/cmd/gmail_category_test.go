@@ -0,0 +1,32 @@
+package cmd
+
+import "testing"
+
+func TestCategoryQuery(t *testing.T) {
+	cases := []struct {
+		in   []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"Social"}, "category:social"},
+		{[]string{"updates,forums", "primary"}, "{category:updates category:forums category:primary}"},
+	}
+	for _, tc := range cases {
+		got, err := categoryQuery(tc.in)
+		if err != nil || got != tc.want {
+			t.Fatalf("%v: got %q err=%v", tc.in, got, err)
+		}
+	}
+	if _, err := categoryQuery([]string{"news"}); err == nil {
+		t.Fatalf("expected error for unknown category")
+	}
+}
+
+func TestCategoryOf(t *testing.T) {
+	if got := categoryOf([]string{"INBOX", "CATEGORY_PERSONAL"}); got != "primary" {
+		t.Fatalf("unexpected category %q", got)
+	}
+	if got := categoryOf([]string{"INBOX"}); got != "" {
+		t.Fatalf("unexpected category %q", got)
+	}
+}
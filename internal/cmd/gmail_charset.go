@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"google.golang.org/api/gmail/v1"
+)
+
+// partCharset returns the lower-cased charset parameter of a part's
+// Content-Type header, or "" when none is declared.
+func partCharset(p *gmail.MessagePart) string {
+	if p == nil {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(headerValue(p, "Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(params["charset"]))
+}
+
+// decodeCharset converts a text part body to UTF-8.
+//
+// Gmail returns part bodies in their original charset. Labels are resolved
+// with the WHATWG index (so iso-8859-1 decodes as windows-1252, like
+// browsers do). Bytes that are not valid UTF-8 with no usable charset
+// (missing, us-ascii, or unknown) are decoded as windows-1252 rather than
+// emitted as mojibake.
+func decodeCharset(b []byte, charset string) string {
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		if utf8.Valid(b) {
+			return string(b)
+		}
+		charset = "windows-1252"
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		if utf8.Valid(b) {
+			return string(b)
+		}
+		enc, _ = htmlindex.Get("windows-1252")
+	}
+	out, err := enc.NewDecoder().Bytes(b)
+	if err != nil {
+		return string(b)
+	}
+	return string(out)
+}
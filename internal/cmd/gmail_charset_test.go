@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestDecodeCharset(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      []byte
+		charset string
+		want    string
+	}{
+		{"utf8", []byte("héllo"), "utf-8", "héllo"},
+		{"latin1", []byte{'c', 'a', 'f', 0xe9}, "iso-8859-1", "café"},
+		{"cp1252 quotes", []byte{0x93, 'q', 0x94}, "windows-1252", "“q”"},
+		{"shift_jis", []byte{0x93, 0xfa, 0x96, 0x7b}, "shift_jis", "日本"},
+		{"latin2", []byte{0xb3, 0xf3, 0x64, 0xbc}, "iso-8859-2", "łódź"},
+		{"undeclared 8bit", []byte{'n', 0xe4, 'h'}, "", "näh"},
+		{"unknown charset keeps utf8", []byte("ok"), "x-made-up", "ok"},
+	}
+	for _, tc := range cases {
+		if got := decodeCharset(tc.in, tc.charset); got != tc.want {
+			t.Fatalf("%s: got %q want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBestBodyText_Charset(t *testing.T) {
+	payload := &gmail.MessagePart{
+		MimeType: "text/plain",
+		Headers:  []*gmail.MessagePartHeader{{Name: "Content-Type", Value: `text/plain; charset="ISO-8859-1"`}},
+		Body:     &gmail.MessagePartBody{Data: base64.RawURLEncoding.EncodeToString([]byte{'G', 'r', 0xfc, 0xdf, 'e'})},
+	}
+	if got := bestBodyText(payload); got != "Grüße" {
+		t.Fatalf("unexpected body %q", got)
+	}
+}
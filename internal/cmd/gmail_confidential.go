@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// confidentialInfo describes a message sent with Gmail's confidential mode.
+// The API only returns Gmail's placeholder body (a notice plus a link to view
+// the message in Gmail), so detection works off that placeholder.
+type confidentialInfo struct {
+	Expires      string   `json:"expires,omitempty"`
+	Restrictions []string `json:"restrictions"`
+}
+
+var confidentialExpiresRe = regexp.MustCompile(`(?i)(?:expires|expiration date|will expire)(?:\s+on)?:?\s*([^\n<]{4,60}?)(?:\.\s|\.?$|\n|<)`)
+
+// detectConfidential reports whether the given body texts are Gmail's
+// confidential-mode placeholder (nil otherwise).
+func detectConfidential(bodies ...string) *confidentialInfo {
+	text := strings.Join(bodies, "\n")
+	lower := strings.ToLower(text)
+	if !strings.Contains(lower, "confidential-mail.google.com") &&
+		!(strings.Contains(lower, "confidential mode") && strings.Contains(lower, "gmail")) {
+		return nil
+	}
+	info := &confidentialInfo{Restrictions: []string{"no-forward", "no-copy", "no-print", "no-download"}}
+	if strings.Contains(lower, "passcode") {
+		info.Restrictions = append(info.Restrictions, "passcode")
+	}
+	if m := confidentialExpiresRe.FindStringSubmatch(text); m != nil {
+		info.Expires = strings.TrimSpace(m[1])
+	}
+	return info
+}
+
+func detectConfidentialPayload(p *gmail.MessagePart) *confidentialInfo {
+	return detectConfidential(findPartBody(p, "text/plain"), findPartBody(p, "text/html"))
+}
+
+// detectConfidentialEML runs detectConfidential on the text parts of an
+// RFC822 message.
+func detectConfidentialEML(eml []byte) *confidentialInfo {
+	msg, err := mail.ReadMessage(bytes.NewReader(eml))
+	if err != nil {
+		return nil
+	}
+	return detectConfidential(emlTextParts(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body, 0)...)
+}
+
+func emlTextParts(contentType, encoding string, body io.Reader, depth int) []string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+	if strings.HasPrefix(mediaType, "multipart/") && depth < 8 {
+		var out []string
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err != nil {
+				return out
+			}
+			out = append(out, emlTextParts(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part, depth+1)...)
+		}
+	}
+	if !strings.HasPrefix(mediaType, "text/") {
+		return nil
+	}
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, newlineStripper{body})
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	b, err := io.ReadAll(io.LimitReader(body, 1<<20))
+	if err != nil && len(b) == 0 {
+		return nil
+	}
+	return []string{string(b)}
+}
+
+// newlineStripper drops CR/LF so base64 bodies with line breaks decode.
+type newlineStripper struct{ r io.Reader }
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	for {
+		c, err := n.r.Read(p)
+		k := 0
+		for _, b := range p[:c] {
+			if b != '\r' && b != '\n' {
+				p[k] = b
+				k++
+			}
+		}
+		if k > 0 || err != nil {
+			return k, err
+		}
+	}
+}
+
+func confidentialExportError(messageID string, info *confidentialInfo) error {
+	msg := fmt.Sprintf("message %s was sent with Gmail confidential mode; its content is only viewable in Gmail and cannot be exported", messageID)
+	if info.Expires != "" {
+		msg += " (expires " + info.Expires + ")"
+	}
+	return errors.New(msg)
+}
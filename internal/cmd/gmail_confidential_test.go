@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+const confidentialPlaceholder = "Alice has sent you a message with Gmail confidential mode.\nThis message expires on Oct 20, 2026.\nView the email: https://confidential-mail.google.com/abc\n"
+
+func TestDetectConfidential(t *testing.T) {
+	info := detectConfidential(confidentialPlaceholder)
+	if info == nil || info.Expires != "Oct 20, 2026" || len(info.Restrictions) != 4 {
+		t.Fatalf("unexpected info %#v", info)
+	}
+	if detectConfidential("Quarterly report attached.") != nil {
+		t.Fatalf("plain message detected as confidential")
+	}
+}
+
+func TestDetectConfidentialEML(t *testing.T) {
+	body := base64.StdEncoding.EncodeToString([]byte(confidentialPlaceholder))
+	eml := "From: a@example.com\r\nContent-Type: multipart/alternative; boundary=b1\r\n\r\n" +
+		"--b1\r\nContent-Type: text/plain\r\nContent-Transfer-Encoding: base64\r\n\r\n" + body[:40] + "\r\n" + body[40:] + "\r\n--b1--\r\n"
+	if detectConfidentialEML([]byte(eml)) == nil {
+		t.Fatalf("expected confidential eml")
+	}
+	if detectConfidentialEML([]byte("From: a@example.com\r\n\r\nhello\r\n")) != nil {
+		t.Fatalf("plain eml detected as confidential")
+	}
+}
+
+func TestExecute_GmailGet_ConfidentialExportFails(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	raw := base64.RawURLEncoding.EncodeToString([]byte("From: a@example.com\r\nContent-Type: text/plain\r\n\r\n" + confidentialPlaceholder))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/users/me/messages/m1") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1", "raw": raw})
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		err = Execute([]string{"--account", "a@b.com", "gmail", "get", "m1", "--format", "eml"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "confidential mode") || !strings.Contains(err.Error(), "Oct 20, 2026") {
+		t.Fatalf("expected confidential error, got %v", err)
+	}
+	if out != "" {
+		t.Fatalf("expected no output, got %q", out)
+	}
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/outfmt"
@@ -59,14 +58,14 @@ func newGmailDelegatesListCmd(flags *rootFlags) *cobra.Command {
 				return nil
 			}
 
-			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			tw, flush := tableWriter(cmd.Context())
 			fmt.Fprintln(tw, "EMAIL\tSTATUS")
 			for _, d := range resp.Delegates {
 				fmt.Fprintf(tw, "%s\t%s\n",
 					d.DelegateEmail,
 					d.VerificationStatus)
 			}
-			_ = tw.Flush()
+			flush()
 			return nil
 		},
 	}
@@ -170,6 +169,9 @@ func newGmailDelegatesRemoveCmd(flags *rootFlags) *cobra.Command {
 			}
 
 			delegateEmail := args[0]
+			if confirmErr := confirmDestructive(cmd, flags, fmt.Sprintf("remove delegate %s", delegateEmail)); confirmErr != nil {
+				return confirmErr
+			}
 			err = svc.Users.Settings.Delegates.Delete("me", delegateEmail).Do()
 			if err != nil {
 				return err
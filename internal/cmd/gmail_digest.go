@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+// maxDigestLinks bounds the links listed per message.
+const maxDigestLinks = 3
+
+var digestLinkRe = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+
+// digestItem is one message summarised in a digest.
+type digestItem struct {
+	ID      string   `json:"id"`
+	Subject string   `json:"subject"`
+	From    string   `json:"from"`
+	Date    string   `json:"date"`
+	Snippet string   `json:"snippet,omitempty"`
+	Links   []string `json:"links,omitempty"`
+}
+
+func newGmailDigestCmd(flags *rootFlags) *cobra.Command {
+	var query, format, emailTo, subject string
+	var max int64
+	var archive, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Summarise matching messages into one digest (printed or emailed)",
+		Long: `Collect the messages matching --query into a single digest: subject,
+sender, date, snippet and the first few links of each message.
+
+The digest is printed in --format md, text or html. With --email-to it is
+sent instead (through gmail send, so send policies and the sent log apply);
+"me" is the account itself. --archive removes the summarised messages from
+the inbox once the digest was printed or sent.`,
+		Example: `  gog gmail digest --query "label:newsletters newer_than:7d"
+  gog gmail digest --query "label:newsletters newer_than:7d" --format md --email-to me --archive`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			query = strings.TrimSpace(query)
+			if query == "" {
+				return usage("--query is required")
+			}
+			format = strings.ToLower(strings.TrimSpace(format))
+			if format != "md" && format != "text" && format != "html" {
+				return usagef("invalid --format %q (expected md|text|html)", format)
+			}
+			to := strings.TrimSpace(emailTo)
+			if strings.EqualFold(to, "me") {
+				to = account
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			ids, err := listGraphMessageIDs(cmd.Context(), svc, query, max)
+			if err != nil {
+				return err
+			}
+			msgs, err := fetchMessagesBounded(cmd.Context(), ids, func(id string) *gmail.UsersMessagesGetCall {
+				return svc.Users.Messages.Get("me", id).Format("full").Fields("id,internalDate,snippet,payload")
+			})
+			if err != nil {
+				return err
+			}
+			items := make([]digestItem, 0, len(msgs))
+			for _, m := range msgs {
+				if m != nil {
+					items = append(items, digestItemFromMessage(m))
+				}
+			}
+			if len(items) == 0 {
+				u.Err().Printf("No messages match %q", query)
+				return nil
+			}
+			if strings.TrimSpace(subject) == "" {
+				subject = fmt.Sprintf("Digest: %d messages (%s)", len(items), query)
+			}
+			md := digestMarkdown(subject, items)
+
+			switch {
+			case dryRun:
+				if outfmt.IsJSON(cmd.Context()) {
+					return outfmt.WriteJSON(os.Stdout, map[string]any{"subject": subject, "to": to, "dryRun": true, "messages": items})
+				}
+				u.Err().Printf("Would digest %d messages", len(items))
+				return writeDigest(md, format)
+			case to != "":
+				if err := sendDigest(cmd, flags, to, subject, md, format); err != nil {
+					return err
+				}
+			case outfmt.IsJSON(cmd.Context()):
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{"subject": subject, "messages": items}); err != nil {
+					return err
+				}
+			default:
+				if err := writeDigest(md, format); err != nil {
+					return err
+				}
+			}
+
+			if archive {
+				archived := make([]string, 0, len(items))
+				for _, it := range items {
+					archived = append(archived, it.ID)
+				}
+				for start := 0; start < len(archived); start += gmailBatchModifyMax {
+					end := min(start+gmailBatchModifyMax, len(archived))
+					if err := svc.Users.Messages.BatchModify("me", &gmail.BatchModifyMessagesRequest{
+						Ids:            archived[start:end],
+						RemoveLabelIds: []string{"INBOX"},
+					}).Context(cmd.Context()).Do(); err != nil {
+						return fmt.Errorf("archived %d of %d messages: %w", start, len(archived), err)
+					}
+				}
+				u.Err().Printf("Archived %d messages", len(archived))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&query, "query", "", "Gmail search query selecting the messages (required)")
+	cmd.Flags().StringVar(&format, "format", "md", "Digest format: md|text|html")
+	cmd.Flags().StringVar(&emailTo, "email-to", "", "Send the digest to this address (me = the account) instead of printing it")
+	cmd.Flags().StringVar(&subject, "subject", "", "Digest subject (default: Digest: N messages (query))")
+	cmd.Flags().Int64Var(&max, "max", 100, "Max messages to include (0 = all)")
+	cmd.Flags().BoolVar(&archive, "archive", false, "Archive the summarised messages (remove INBOX) afterwards")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the digest without sending or archiving")
+	return cmd
+}
+
+func digestItemFromMessage(m *gmail.Message) digestItem {
+	it := digestItem{
+		ID:      m.Id,
+		Subject: headerValue(m.Payload, "Subject"),
+		From:    headerValue(m.Payload, "From"),
+		Snippet: strings.TrimSpace(html.UnescapeString(m.Snippet)),
+	}
+	if it.Subject == "" {
+		it.Subject = "(no subject)"
+	}
+	if m.InternalDate > 0 {
+		it.Date = time.UnixMilli(m.InternalDate).Local().Format("2006-01-02 15:04")
+	}
+	it.Links = digestLinks(m.Payload)
+	return it
+}
+
+// digestLinks returns the first distinct links of the message body, skipping
+// unsubscribe and tracking-pixel style URLs.
+func digestLinks(p *gmail.MessagePart) []string {
+	body := findPartBody(p, "text/plain")
+	if body == "" {
+		body = html.UnescapeString(findPartBody(p, "text/html"))
+	}
+	var links []string
+	seen := map[string]bool{}
+	for _, l := range digestLinkRe.FindAllString(body, -1) {
+		l = strings.TrimRight(l, ".,;:!?")
+		lower := strings.ToLower(l)
+		if seen[l] || strings.Contains(lower, "unsubscribe") || strings.HasSuffix(lower, ".png") || strings.HasSuffix(lower, ".gif") {
+			continue
+		}
+		seen[l] = true
+		links = append(links, l)
+		if len(links) == maxDigestLinks {
+			break
+		}
+	}
+	return links
+}
+
+func digestMarkdown(subject string, items []digestItem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", subject)
+	for i, it := range items {
+		fmt.Fprintf(&b, "## %d. %s\n\n", i+1, it.Subject)
+		from := senderName(it.From)
+		if addr := senderAddress(it.From); addr != "" && !strings.EqualFold(addr, from) {
+			from += " <" + addr + ">"
+		}
+		if it.Date != "" {
+			from += " · " + it.Date
+		}
+		fmt.Fprintf(&b, "**From:** %s\n\n", from)
+		if it.Snippet != "" {
+			fmt.Fprintf(&b, "%s\n\n", it.Snippet)
+		}
+		for _, l := range it.Links {
+			fmt.Fprintf(&b, "- %s\n", l)
+		}
+		if len(it.Links) > 0 {
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func writeDigest(md, format string) error {
+	out := md
+	switch format {
+	case "text":
+		_, out = markdownToEmail(md)
+	case "html":
+		out, _ = markdownToEmail(md)
+	}
+	_, err := fmt.Fprintln(os.Stdout, strings.TrimRight(out, "\n"))
+	return err
+}
+
+// sendDigest sends the digest through gmail send. Plain text digests go out
+// as text only; md and html as HTML with a plain-text part.
+func sendDigest(cmd *cobra.Command, flags *rootFlags, to, subject, md, format string) error {
+	htmlBody, plain := markdownToEmail(md)
+	send := newGmailSendCmd(flags)
+	set := map[string]string{"to": to, "subject": subject, "body": plain}
+	if format != "text" {
+		set["body-html"] = htmlBody
+	}
+	for name, value := range set {
+		if err := send.Flags().Set(name, value); err != nil {
+			return err
+		}
+	}
+	send.SetContext(cmd.Context())
+	return send.RunE(send, nil)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestDigestLinks(t *testing.T) {
+	body := "Read https://example.com/a, then https://example.com/a again.\n" +
+		"Unsubscribe: https://example.com/unsubscribe?u=1 pixel https://t.example.com/p.gif\n" +
+		"More: https://example.com/b https://example.com/c https://example.com/d"
+	p := &gmail.MessagePart{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: base64.RawURLEncoding.EncodeToString([]byte(body))}}
+	got := digestLinks(p)
+	want := []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("links = %v, want %v", got, want)
+	}
+}
+
+func TestExecute_GmailDigest_EmailAndArchive(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+
+	var query, raw, modifyBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(path, "/users/me/messages"):
+			query = r.URL.Query().Get("q")
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1"}, {"id": "m2"}}})
+		case r.Method == http.MethodGet && strings.HasSuffix(path, "/users/me/messages/m1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1", "internalDate": "1772463600000", "snippet": "Top stories &amp; more",
+				"payload": map[string]any{"mimeType": "text/plain", "headers": []map[string]any{
+					{"name": "Subject", "value": "Weekly News"},
+					{"name": "From", "value": "News <news@example.com>"},
+				}, "body": map[string]any{"data": base64.RawURLEncoding.EncodeToString([]byte("See https://example.com/story"))}}})
+		case r.Method == http.MethodGet && strings.HasSuffix(path, "/users/me/messages/m2"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m2", "internalDate": "1772550000000",
+				"payload": map[string]any{"mimeType": "text/plain", "headers": []map[string]any{
+					{"name": "From", "value": "tips@example.com"},
+				}}})
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/users/me/messages/send"):
+			var msg gmail.Message
+			_ = json.NewDecoder(r.Body).Decode(&msg)
+			b, _ := base64.URLEncoding.DecodeString(msg.Raw)
+			raw = string(b)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1", "threadId": "t1"})
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/users/me/messages/batchModify"):
+			b, _ := io.ReadAll(r.Body)
+			modifyBody = string(b)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "digest", "--query", "label:newsletters newer_than:7d",
+				"--email-to", "me", "--subject", "Newsletters", "--archive"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if query != "label:newsletters newer_than:7d" {
+		t.Fatalf("query = %q", query)
+	}
+	if !strings.Contains(out, "message_id\ts1") {
+		t.Fatalf("out = %q", out)
+	}
+	for _, want := range []string{"To: a@b.com", "Subject: Newsletters", "multipart/alternative", "Weekly News", "Top stories & more", "https://example.com/story", "(no subject)"} {
+		if !strings.Contains(raw, want) {
+			t.Fatalf("raw missing %q:\n%s", want, raw)
+		}
+	}
+	if !strings.Contains(modifyBody, `"ids":["m1","m2"]`) || !strings.Contains(modifyBody, `"removeLabelIds":["INBOX"]`) {
+		t.Fatalf("modify body = %q", modifyBody)
+	}
+}
+
+func TestDigestMarkdown(t *testing.T) {
+	md := digestMarkdown("Digest", []digestItem{{Subject: "Hello", From: "Ana <ana@example.com>", Date: "2026-03-02 15:00", Snippet: "Hi", Links: []string{"https://x.test"}}})
+	want := "# Digest\n\n## 1. Hello\n\n**From:** Ana <ana@example.com> · 2026-03-02 15:00\n\nHi\n\n- https://x.test\n"
+	if md != want {
+		t.Fatalf("md = %q", md)
+	}
+}
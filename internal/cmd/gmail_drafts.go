@@ -1,8 +1,8 @@
 package cmd
 
 import (
-	"encoding/base64"
 	"fmt"
+	"net/mail"
 	"os"
 	"strings"
 
@@ -23,17 +23,22 @@ func newGmailDraftsCmd(flags *rootFlags) *cobra.Command {
 	cmd.AddCommand(newGmailDraftsDeleteCmd(flags))
 	cmd.AddCommand(newGmailDraftsSendCmd(flags))
 	cmd.AddCommand(newGmailDraftsCreateCmd(flags))
+	cmd.AddCommand(newGmailDraftsUpdateCmd(flags))
 	return cmd
 }
 
 func newGmailDraftsListCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
+	var noPreview bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List drafts",
-		Args:  cobra.NoArgs,
+		Long: `List drafts with a preview (To, Subject, Date and the start of the body),
+fetched per draft; --no-preview lists IDs only in one request.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			u := ui.FromContext(cmd.Context())
 			account, err := requireAccount(flags)
@@ -46,18 +51,39 @@ func newGmailDraftsListCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			resp, err := svc.Users.Drafts.List("me").MaxResults(max).PageToken(page).Do()
+			drafts, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*gmail.Draft, string, error) {
+				resp, err := svc.Users.Drafts.List("me").MaxResults(max).PageToken(pageToken).Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Drafts, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(drafts))
 			if err != nil {
 				return err
 			}
+			previews := map[string]draftPreview{}
+			if !noPreview {
+				for _, d := range drafts {
+					if d == nil {
+						continue
+					}
+					full, err := svc.Users.Drafts.Get("me", d.Id).Format("metadata").Context(cmd.Context()).Do()
+					if err != nil {
+						return err
+					}
+					previews[d.Id] = newDraftPreview(full.Message)
+				}
+			}
 			if outfmt.IsJSON(cmd.Context()) {
 				type item struct {
 					ID        string `json:"id"`
 					MessageID string `json:"messageId,omitempty"`
 					ThreadID  string `json:"threadId,omitempty"`
+					draftPreview
 				}
-				items := make([]item, 0, len(resp.Drafts))
-				for _, d := range resp.Drafts {
+				items := make([]item, 0, len(drafts))
+				for _, d := range drafts {
 					if d == nil {
 						continue
 					}
@@ -66,38 +92,72 @@ func newGmailDraftsListCmd(flags *rootFlags) *cobra.Command {
 						msgID = d.Message.Id
 						threadID = d.Message.ThreadId
 					}
-					items = append(items, item{ID: d.Id, MessageID: msgID, ThreadID: threadID})
+					items = append(items, item{ID: d.Id, MessageID: msgID, ThreadID: threadID, draftPreview: previews[d.Id]})
 				}
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
 					"drafts":        items,
-					"nextPageToken": resp.NextPageToken,
-				})
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
-			if len(resp.Drafts) == 0 {
+			if len(drafts) == 0 {
 				u.Err().Println("No drafts")
 				return nil
 			}
 
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
-			fmt.Fprintln(w, "ID\tMESSAGE_ID")
-			for _, d := range resp.Drafts {
+			if noPreview {
+				fmt.Fprintln(w, "ID\tMESSAGE_ID")
+			} else {
+				fmt.Fprintln(w, "ID\tMESSAGE_ID\tTO\tSUBJECT\tPREVIEW")
+			}
+			for _, d := range drafts {
 				msgID := ""
 				if d.Message != nil {
 					msgID = d.Message.Id
 				}
-				fmt.Fprintf(w, "%s\t%s\n", d.Id, msgID)
+				if noPreview {
+					fmt.Fprintf(w, "%s\t%s\n", d.Id, msgID)
+					continue
+				}
+				pv := previews[d.Id]
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Id, msgID, orDash(sanitizeTab(pv.To)), orDash(sanitizeTab(pv.Subject)), sanitizeTab(previewText(pv.Snippet, 60)))
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 20, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	cmd.Flags().BoolVar(&noPreview, "no-preview", false, "List IDs only (skip the per-draft preview fetch)")
+	pages.addFlags(cmd)
 	return cmd
 }
 
+// draftPreview is the decoded summary drafts list shows per draft.
+type draftPreview struct {
+	To      string `json:"to,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+func newDraftPreview(m *gmail.Message) draftPreview {
+	if m == nil {
+		return draftPreview{}
+	}
+	return draftPreview{
+		To:      headerValue(m.Payload, "To"),
+		Subject: headerValue(m.Payload, "Subject"),
+		Date:    headerValue(m.Payload, "Date"),
+		Snippet: gmailSnippet(m.Snippet),
+	}
+}
+
 func newGmailDraftsGetCmd(flags *rootFlags) *cobra.Command {
 	var download bool
 
@@ -277,17 +337,34 @@ func newGmailDraftsSendCmd(flags *rootFlags) *cobra.Command {
 				recipients = append(recipients, headerValue(draft.Message.Payload, "Cc"))
 				recipients = append(recipients, headerValue(draft.Message.Payload, "Bcc"))
 			}
-			if err := checkGmailAllowlist(u, recipients); err != nil {
+			if err := checkGmailAllowlist(u, account, recipients); err != nil {
+				return err
+			}
+			if err := requireGmailSendArm(account); err != nil {
 				return err
 			}
-			if err := requireGmailSendArm(); err != nil {
+			if err := checkPolicySendQuota(account); err != nil {
 				return err
 			}
 
 			msg, err := svc.Users.Drafts.Send("me", &gmail.Draft{Id: draftID}).Do()
+			logEntry := sentLogEntry{Account: account, Source: "drafts"}
+			if draft != nil && draft.Message != nil {
+				logEntry.Subject = headerValue(draft.Message.Payload, "Subject")
+				logEntry.RFC822ID = strings.TrimSpace(headerValue(draft.Message.Payload, "Message-ID"))
+				if addrs, err := mail.ParseAddressList(headerValue(draft.Message.Payload, "To")); err == nil {
+					for _, a := range addrs {
+						logEntry.To = append(logEntry.To, a.Address)
+					}
+				}
+			}
 			if err != nil {
+				logEntry.Error = err.Error()
+				recordSend(u, logEntry)
 				return err
 			}
+			logEntry.MessageID, logEntry.ThreadID = msg.Id, msg.ThreadId
+			recordSend(u, logEntry)
 			if outfmt.IsJSON(cmd.Context()) {
 				return outfmt.WriteJSON(os.Stdout, map[string]any{
 					"messageId": msg.Id,
@@ -310,17 +387,31 @@ func newGmailDraftsCreateCmd(flags *rootFlags) *cobra.Command {
 	var subject string
 	var body string
 	var bodyHTML string
+	var bodyMD string
 	var replyToMessageID string
 	var replyTo string
 	var attach []string
+	var inlineSpecs []string
+	var tracking trackingFlag
 	var from string
+	var dryRun gmailDryRun
+	var verify bool
+	var quoteHTML bool
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a draft",
 		Long: `Create a draft. Use --from to send from a configured send-as alias.
 
-To see available send-as aliases: gog gmail sendas list`,
+To see available send-as aliases: gog gmail sendas list
+
+With --dry-run the RFC822 message is built and printed (or written to --out)
+after allowlist checks, without calling the Gmail API.
+
+--body-md renders a Markdown file to styled HTML plus a plain-text part.
+
+--verify-recipients warns about recipients not found in contacts or recent
+mail, suggesting the closest match for likely typos.`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			u := ui.FromContext(cmd.Context())
@@ -328,48 +419,91 @@ To see available send-as aliases: gog gmail sendas list`,
 			if err != nil {
 				return err
 			}
+			if err := dryRun.validate(); err != nil {
+				return err
+			}
+			if err := applyBodyMarkdown(bodyMD, &body, &bodyHTML); err != nil {
+				return err
+			}
 			if strings.TrimSpace(to) == "" || strings.TrimSpace(subject) == "" {
 				return usage("required: --to, --subject")
 			}
 			if strings.TrimSpace(body) == "" && strings.TrimSpace(bodyHTML) == "" {
-				return usage("required: --body or --body-html")
+				return usage("required: --body, --body-html or --body-md")
+			}
+			if quoteHTML {
+				if strings.TrimSpace(replyToMessageID) == "" || strings.TrimSpace(bodyHTML) == "" {
+					return usage("--quote-html requires --reply-to-message-id and --body-html")
+				}
+				if dryRun.Enabled {
+					return usage("--quote-html cannot be combined with --dry-run")
+				}
 			}
 
-			svc, err := newGmailService(cmd.Context(), account)
+			recipients := append([]string{}, splitCSV(to)...)
+			recipients = append(recipients, splitCSV(cc)...)
+			recipients = append(recipients, splitCSV(bcc)...)
+			atts, err := parseAttachSpecs(attach)
 			if err != nil {
 				return err
 			}
-
-			// Determine the From address
-			fromAddr := account
-			if strings.TrimSpace(from) != "" {
-				// Validate that this is a configured send-as alias
-				var sa *gmail.SendAs
-				sa, err = svc.Users.Settings.SendAs.Get("me", from).Context(cmd.Context()).Do()
+			if err := checkPolicyAttachments(account, attachmentPaths(atts)); err != nil {
+				return err
+			}
+			var svc *gmail.Service
+			if dryRun.Enabled {
+				if err := checkGmailAllowlist(u, account, recipients); err != nil {
+					return err
+				}
+			} else {
+				svc, err = newGmailService(cmd.Context(), account)
 				if err != nil {
-					return fmt.Errorf("invalid --from address %q: %w", from, err)
+					return err
 				}
-				if sa.VerificationStatus != "accepted" {
-					return fmt.Errorf("--from address %q is not verified (status: %s)", from, sa.VerificationStatus)
+			}
+			if verify {
+				verifyRecipients(cmd.Context(), u, account, svc, recipients)
+			}
+
+			fromAddr, err := resolveFromAddress(cmd.Context(), svc, account, from)
+			if err != nil {
+				return err
+			}
+
+			var inReplyTo, references, threadID string
+			if svc != nil {
+				inReplyTo, references, threadID, err = replyHeaders(cmd, svc, replyToMessageID)
+				if err != nil {
+					return err
 				}
-				fromAddr = from
-				// Include display name if set
-				if sa.DisplayName != "" {
-					fromAddr = sa.DisplayName + " <" + from + ">"
+				if quoteHTML {
+					bodyHTML, err = quotedReplyHTML(cmd.Context(), svc, replyToMessageID, bodyHTML)
+					if err != nil {
+						return err
+					}
 				}
 			}
 
-			inReplyTo, references, threadID, err := replyHeaders(cmd, svc, replyToMessageID)
-			if err != nil {
+			if err := tracking.apply(cmd, &body, &bodyHTML); err != nil {
 				return err
 			}
 
-			atts := make([]mailAttachment, 0, len(attach))
-			for _, p := range attach {
-				atts = append(atts, mailAttachment{Path: p})
+			inline, err := parseInlineImages(inlineSpecs)
+			if err != nil {
+				return err
+			}
+			if len(inline) > 0 {
+				if strings.TrimSpace(bodyHTML) == "" {
+					return usage("--inline requires --body-html or --body-md")
+				}
+				warnUnreferencedInline(u, bodyHTML, inline)
 			}
 
-			raw, err := buildRFC822(mailOptions{
+			hdrs, err := resolveComposeHeaders(account, fromAddr)
+			if err != nil {
+				return err
+			}
+			composed, err := composeRFC822(u, mailOptions{
 				From:        fromAddr,
 				To:          splitCSV(to),
 				Cc:          splitCSV(cc),
@@ -381,19 +515,20 @@ To see available send-as aliases: gog gmail sendas list`,
 				InReplyTo:   inReplyTo,
 				References:  references,
 				Attachments: atts,
+				Inline:      inline,
+
+				AdditionalHeaders: hdrs.Extra,
+				MessageIDDomain:   hdrs.MessageIDDomain,
 			})
 			if err != nil {
 				return err
 			}
-
-			msg := &gmail.Message{
-				Raw: base64.RawURLEncoding.EncodeToString(raw),
-			}
-			if threadID != "" {
-				msg.ThreadId = threadID
+			defer composed.Close()
+			if dryRun.Enabled {
+				return dryRun.write(cmd.Context(), composed, replyToMessageID)
 			}
 
-			draft, err := svc.Users.Drafts.Create("me", &gmail.Draft{Message: msg}).Do()
+			draft, err := createDraftComposed(cmd.Context(), svc, composed, threadID)
 			if err != nil {
 				return err
 			}
@@ -421,9 +556,15 @@ To see available send-as aliases: gog gmail sendas list`,
 	cmd.Flags().StringVar(&subject, "subject", "", "Subject (required)")
 	cmd.Flags().StringVar(&body, "body", "", "Body (plain text; required unless --body-html is set)")
 	cmd.Flags().StringVar(&bodyHTML, "body-html", "", "Body (HTML; optional)")
+	cmd.Flags().StringVar(&bodyMD, "body-md", "", "Body from a Markdown file (- for stdin), sent as styled HTML plus a plain-text part")
 	cmd.Flags().StringVar(&replyToMessageID, "reply-to-message-id", "", "Reply to Gmail message ID (sets In-Reply-To/References and thread)")
+	cmd.Flags().BoolVar(&quoteHTML, "quote-html", false, "Append the replied-to message's HTML in a collapsed gmail_quote block (needs --reply-to-message-id and --body-html)")
 	cmd.Flags().StringVar(&replyTo, "reply-to", "", "Reply-To header address")
-	cmd.Flags().StringSliceVar(&attach, "attach", nil, "Attachment file path (repeatable)")
+	cmd.Flags().StringSliceVar(&attach, "attach", nil, attachFlagUsage)
+	tracking.addFlag(cmd)
+	cmd.Flags().StringArrayVar(&inlineSpecs, "inline", nil, "Inline image path[:cid] for <img src=\"cid:...\"> in the HTML body (repeatable; cid defaults to the file name)")
 	cmd.Flags().StringVar(&from, "from", "", "Send from this email address (must be a verified send-as alias)")
+	cmd.Flags().BoolVar(&verify, "verify-recipients", false, "Warn about recipients not found in contacts or recent mail (suggests likely typo fixes)")
+	dryRun.addFlags(cmd)
 	return cmd
 }
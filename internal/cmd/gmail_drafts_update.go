@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+func newGmailDraftsUpdateCmd(flags *rootFlags) *cobra.Command {
+	var to string
+	var cc string
+	var bcc string
+	var subject string
+	var body string
+	var bodyHTML string
+	var bodyMD string
+	var attach []string
+	var noAttachments bool
+	var dryRun gmailDryRun
+
+	cmd := &cobra.Command{
+		Use:   "update <draftId>",
+		Short: "Replace parts of a draft (keeps its thread)",
+		Long: `Rewrite a draft. Only the given parts change: --to/--cc/--bcc/--subject
+replace those headers, --body/--body-html/--body-md replace the body (a new
+plain body drops the old HTML and vice versa unless both are given), and
+--attach replaces the attachments (--no-attachments removes them). Everything
+else, including From, threading headers and the thread, is kept.`,
+		Example: `  gog gmail drafts update <draftId> --subject "v2" --body-md notes.md
+  gog gmail drafts update <draftId> --to ana@example.com --attach report.pdf`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if err := dryRun.validate(); err != nil {
+				return err
+			}
+			if noAttachments && len(attach) > 0 {
+				return usage("--attach and --no-attachments are mutually exclusive")
+			}
+			draftID := strings.TrimSpace(args[0])
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			draft, err := svc.Users.Drafts.Get("me", draftID).Format("full").Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			if draft.Message == nil || draft.Message.Payload == nil {
+				return usagef("draft %s has no message", draftID)
+			}
+			msg := draft.Message
+			p := msg.Payload
+
+			changed := func(name string) bool { return cmd.Flags().Changed(name) }
+			opts := mailOptions{
+				From:       headerValue(p, "From"),
+				To:         splitCSV(headerValue(p, "To")),
+				Cc:         splitCSV(headerValue(p, "Cc")),
+				Bcc:        splitCSV(headerValue(p, "Bcc")),
+				ReplyTo:    headerValue(p, "Reply-To"),
+				Subject:    headerValue(p, "Subject"),
+				Body:       findPartBody(p, "text/plain"),
+				BodyHTML:   findPartBody(p, "text/html"),
+				InReplyTo:  headerValue(p, "In-Reply-To"),
+				References: headerValue(p, "References"),
+			}
+			if opts.From == "" {
+				opts.From = account
+			}
+			if changed("to") {
+				opts.To = splitCSV(to)
+			}
+			if changed("cc") {
+				opts.Cc = splitCSV(cc)
+			}
+			if changed("bcc") {
+				opts.Bcc = splitCSV(bcc)
+			}
+			if changed("subject") {
+				opts.Subject = subject
+			}
+			if err := applyBodyMarkdown(bodyMD, &body, &bodyHTML); err != nil {
+				return err
+			}
+			newPlain := changed("body") || (bodyMD != "" && body != "")
+			newHTML := changed("body-html") || bodyMD != ""
+			if newPlain || newHTML {
+				opts.Body, opts.BodyHTML = body, bodyHTML
+			}
+			if len(opts.To) == 0 || strings.TrimSpace(opts.Subject) == "" {
+				return usage("the updated draft needs --to and --subject")
+			}
+			if strings.TrimSpace(opts.Body) == "" && strings.TrimSpace(opts.BodyHTML) == "" {
+				return usage("the updated draft needs a body (--body, --body-html or --body-md)")
+			}
+
+			switch {
+			case noAttachments:
+			case len(attach) > 0:
+				atts, err := parseAttachSpecs(attach)
+				if err != nil {
+					return err
+				}
+				if err := checkPolicyAttachments(account, attachmentPaths(atts)); err != nil {
+					return err
+				}
+				opts.Attachments = atts
+			default:
+				opts.Attachments, err = draftAttachments(cmd.Context(), svc, msg)
+				if err != nil {
+					return err
+				}
+			}
+
+			recipients := append(append(append([]string{}, opts.To...), opts.Cc...), opts.Bcc...)
+			if dryRun.Enabled {
+				if err := checkGmailAllowlist(u, account, recipients); err != nil {
+					return err
+				}
+			}
+			hdrs, err := resolveComposeHeaders(account, opts.From)
+			if err != nil {
+				return err
+			}
+			opts.AdditionalHeaders = hdrs.Extra
+			opts.MessageIDDomain = hdrs.MessageIDDomain
+
+			composed, err := composeRFC822(u, opts)
+			if err != nil {
+				return err
+			}
+			defer composed.Close()
+			if dryRun.Enabled {
+				return dryRun.write(cmd.Context(), composed, "")
+			}
+
+			updated, err := updateDraftComposed(cmd.Context(), svc, draftID, composed, msg.ThreadId)
+			if err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"draftId":  updated.Id,
+					"message":  updated.Message,
+					"threadId": msg.ThreadId,
+				})
+			}
+			u.Out().Printf("draft_id\t%s", updated.Id)
+			if updated.Message != nil && updated.Message.Id != "" {
+				u.Out().Printf("message_id\t%s", updated.Message.Id)
+			}
+			if msg.ThreadId != "" {
+				u.Out().Printf("thread_id\t%s", msg.ThreadId)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Replace recipients (comma-separated)")
+	cmd.Flags().StringVar(&cc, "cc", "", "Replace CC recipients (comma-separated; empty clears)")
+	cmd.Flags().StringVar(&bcc, "bcc", "", "Replace BCC recipients (comma-separated; empty clears)")
+	cmd.Flags().StringVar(&subject, "subject", "", "Replace the subject")
+	cmd.Flags().StringVar(&body, "body", "", "Replace the body (plain text)")
+	cmd.Flags().StringVar(&bodyHTML, "body-html", "", "Replace the body (HTML)")
+	cmd.Flags().StringVar(&bodyMD, "body-md", "", "Replace the body from a Markdown file (- for stdin): styled HTML plus a plain-text part")
+	cmd.Flags().StringSliceVar(&attach, "attach", nil, "Replace attachments with these files: path[::name[::mime/type]] (repeatable)")
+	cmd.Flags().BoolVar(&noAttachments, "no-attachments", false, "Remove all attachments")
+	dryRun.addFlags(cmd)
+	return cmd
+}
+
+// draftAttachments downloads the attachments of a draft message so they
+// survive a rewrite.
+func draftAttachments(ctx context.Context, svc *gmail.Service, msg *gmail.Message) ([]mailAttachment, error) {
+	var out []mailAttachment
+	for _, a := range collectAttachments(msg.Payload) {
+		att, err := svc.Users.Messages.Attachments.Get("me", msg.Id, a.AttachmentID).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		data, err := base64.URLEncoding.DecodeString(padBase64URL(att.Data))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, mailAttachment{Filename: a.Filename, MIMEType: a.MimeType, Data: data})
+	}
+	return out, nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestGmailDraftsUpdateKeepsThreadAndHeaders(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	b64 := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	var put gmail.Draft
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/drafts/d1") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id": "d1",
+				"message": map[string]any{
+					"id":       "m1",
+					"threadId": "t1",
+					"payload": map[string]any{
+						"mimeType": "multipart/mixed",
+						"headers": []map[string]any{
+							{"name": "From", "value": "a@b.com"},
+							{"name": "To", "value": "ana@example.com, bo@example.com"},
+							{"name": "Subject", "value": "v1"},
+							{"name": "In-Reply-To", "value": "<orig@example.com>"},
+							{"name": "References", "value": "<orig@example.com>"},
+						},
+						"parts": []map[string]any{
+							{"mimeType": "text/plain", "body": map[string]any{"data": b64("old body")}},
+							{"mimeType": "text/plain", "filename": "notes.txt", "body": map[string]any{"attachmentId": "att1"}},
+						},
+					},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/messages/m1/attachments/att1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": b64("attached")})
+		case strings.HasSuffix(r.URL.Path, "/users/me/drafts/d1") && r.Method == http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(&put); err != nil {
+				t.Errorf("decode: %v", err)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "d1", "message": map[string]any{"id": "m2", "threadId": "t1"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "drafts", "update", "d1", "--subject", "v2"}); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"threadId": "t1"`) {
+		t.Fatalf("out = %s", out)
+	}
+	if put.Message == nil || put.Message.ThreadId != "t1" {
+		t.Fatalf("thread not preserved: %+v", put.Message)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(put.Message.Raw)
+	if err != nil {
+		t.Fatalf("raw: %v", err)
+	}
+	msg := string(raw)
+	for _, want := range []string{
+		"Subject: v2",
+		"To: ana@example.com, bo@example.com",
+		"In-Reply-To: <orig@example.com>",
+		"old body",
+		`filename="notes.txt"`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("missing %q in:\n%s", want, msg)
+		}
+	}
+}
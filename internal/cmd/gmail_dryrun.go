@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+// gmailDryRun renders the RFC822 message locally instead of calling the API.
+type gmailDryRun struct {
+	Enabled bool
+	Out     string
+}
+
+func (d *gmailDryRun) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&d.Enabled, "dry-run", false, "Build and print the RFC822 message without calling the API")
+	cmd.Flags().StringVar(&d.Out, "out", "", "With --dry-run: write the message to this .eml path instead of stdout")
+}
+
+func (d *gmailDryRun) validate() error {
+	if !d.Enabled && strings.TrimSpace(d.Out) != "" {
+		return usage("--out requires --dry-run")
+	}
+	return nil
+}
+
+// resolveFromAddress validates --from against the account's send-as aliases.
+// In --dry-run (svc == nil) the address is used as given.
+func resolveFromAddress(ctx context.Context, svc *gmail.Service, account, from string) (string, error) {
+	from = strings.TrimSpace(from)
+	if from == "" {
+		return account, nil
+	}
+	if svc == nil {
+		return from, nil
+	}
+	sa, err := svc.Users.Settings.SendAs.Get("me", from).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("invalid --from address %q: %w", from, err)
+	}
+	if sa.VerificationStatus != "accepted" {
+		return "", fmt.Errorf("--from address %q is not verified (status: %s)", from, sa.VerificationStatus)
+	}
+	if sa.DisplayName != "" {
+		return sa.DisplayName + " <" + from + ">", nil
+	}
+	return from, nil
+}
+
+// write prints the message (or saves it to --out) and reports what would
+// have been sent. Spooled messages are copied, not loaded.
+func (d *gmailDryRun) write(ctx context.Context, m *composedMessage, replyToMessageID string) error {
+	u := ui.FromContext(ctx)
+	if strings.TrimSpace(replyToMessageID) != "" {
+		u.Err().Printf("WARN: --dry-run does not fetch %s; In-Reply-To/References and thread are omitted", replyToMessageID)
+	}
+
+	path := strings.TrimSpace(d.Out)
+	if path != "" {
+		if err := copyComposed(m, path); err != nil {
+			return err
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		out := map[string]any{
+			"dryRun": true,
+			"bytes":  m.size,
+		}
+		if path != "" {
+			out["path"] = path
+		} else {
+			raw, err := m.bytes()
+			if err != nil {
+				return err
+			}
+			out["rfc822"] = string(raw)
+		}
+		return outfmt.WriteJSON(os.Stdout, out)
+	}
+	if path != "" {
+		u.Out().Printf("dry_run\ttrue")
+		u.Out().Printf("path\t%s", path)
+		u.Out().Printf("bytes\t%d", m.size)
+		return nil
+	}
+	r, err := m.open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if _, err := io.Copy(os.Stdout, r); err != nil {
+		return err
+	}
+	u.Err().Printf("Dry run: %d bytes, nothing sent", m.size)
+	return nil
+}
+
+func copyComposed(m *composedMessage, path string) error {
+	r, err := m.open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+// olderThanRe matches Gmail's older_than: operand (days, months, years).
+var olderThanRe = regexp.MustCompile(`^[1-9][0-9]*[dmy]$`)
+
+func newGmailTrashCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Trash maintenance",
+	}
+	cmd.AddCommand(newGmailEmptyCmd(flags, "TRASH", "Trash"))
+	return cmd
+}
+
+func newGmailSpamCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "spam",
+		Short: "Spam maintenance",
+	}
+	cmd.AddCommand(newGmailEmptyCmd(flags, "SPAM", "Spam"))
+	return cmd
+}
+
+// newGmailEmptyCmd builds `gmail trash empty` / `gmail spam empty`.
+func newGmailEmptyCmd(flags *rootFlags, labelID, name string) *cobra.Command {
+	var olderThan string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "empty",
+		Short: fmt.Sprintf("Permanently delete messages in %s", name),
+		Long: fmt.Sprintf(`Permanently delete every message in %s (or only those older than
+--older-than, e.g. 30d, 6m, 1y). This cannot be undone; asks for confirmation
+unless --force. --dry-run only counts the messages.`, name),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			query := ""
+			if olderThan = strings.ToLower(strings.TrimSpace(olderThan)); olderThan != "" {
+				if !olderThanRe.MatchString(olderThan) {
+					return usagef("invalid --older-than %q (use e.g. 30d, 6m, 1y)", olderThan)
+				}
+				query = "older_than:" + olderThan
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			ids, err := listLabelMessageIDs(cmd.Context(), svc, labelID, query)
+			if err != nil {
+				return err
+			}
+
+			if !dryRun && len(ids) > 0 {
+				if err := confirmDestructive(cmd, flags, fmt.Sprintf("permanently delete %d message(s) in %s", len(ids), name)); err != nil {
+					return err
+				}
+				// batchDelete shares batchModify's 1000-ID limit.
+				for start := 0; start < len(ids); start += gmailBatchModifyMax {
+					end := min(start+gmailBatchModifyMax, len(ids))
+					if err := svc.Users.Messages.BatchDelete("me", &gmail.BatchDeleteMessagesRequest{
+						Ids: ids[start:end],
+					}).Context(cmd.Context()).Do(); err != nil {
+						return fmt.Errorf("deleted %d of %d messages: %w", start, len(ids), err)
+					}
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"label":     labelID,
+					"olderThan": olderThan,
+					"dryRun":    dryRun,
+					"count":     len(ids),
+				})
+			}
+			switch {
+			case len(ids) == 0 && olderThan != "":
+				u.Err().Printf("No messages in %s older than %s", name, olderThan)
+			case len(ids) == 0:
+				u.Err().Printf("%s is empty", name)
+			case dryRun:
+				u.Out().Printf("Would delete %d messages in %s", len(ids), name)
+			default:
+				u.Out().Printf("Deleted %d messages in %s", len(ids), name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Only messages older than this (e.g. 30d, 6m, 1y)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Count the messages without deleting them")
+	return cmd
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_GmailTrashEmpty(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var listQuery string
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages"):
+			listQuery = r.URL.RawQuery
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1"}, {"id": "m2"}}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/messages/batchDelete"):
+			b, _ := io.ReadAll(r.Body)
+			deleted = append(deleted, string(b))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "spam", "empty", "--dry-run"}); err != nil {
+			t.Fatalf("dry-run: %v", err)
+		}
+	})
+	if len(deleted) != 0 || !strings.Contains(listQuery, "labelIds=SPAM") {
+		t.Fatalf("dry-run deleted %v (query %q)", deleted, listQuery)
+	}
+
+	if err := Execute([]string{"--no-input", "--account", "a@b.com", "gmail", "trash", "empty"}); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected confirmation error, got %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--force", "--account", "a@b.com", "gmail", "trash", "empty", "--older-than", "30d"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if !strings.Contains(listQuery, "labelIds=TRASH") || !strings.Contains(listQuery, "q=older_than%3A30d") {
+		t.Fatalf("unexpected list query %q", listQuery)
+	}
+	if len(deleted) != 1 || !strings.Contains(deleted[0], `"ids":["m1","m2"]`) {
+		t.Fatalf("unexpected batchDelete %v", deleted)
+	}
+	if !strings.Contains(out, `"count": 2`) {
+		t.Fatalf("unexpected out %q", out)
+	}
+
+	if err := Execute([]string{"--account", "a@b.com", "gmail", "trash", "empty", "--older-than", "soon"}); err == nil {
+		t.Fatalf("expected --older-than error")
+	}
+}
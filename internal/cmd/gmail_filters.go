@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/outfmt"
@@ -57,7 +56,7 @@ func newGmailFiltersListCmd(flags *rootFlags) *cobra.Command {
 				return nil
 			}
 
-			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			tw, flush := tableWriter(cmd.Context())
 			fmt.Fprintln(tw, "ID\tFROM\tTO\tSUBJECT\tQUERY")
 			for _, f := range resp.Filter {
 				criteria := f.Criteria
@@ -78,7 +77,7 @@ func newGmailFiltersListCmd(flags *rootFlags) *cobra.Command {
 					sanitizeTab(subject),
 					sanitizeTab(query))
 			}
-			_ = tw.Flush()
+			flush()
 			return nil
 		},
 	}
@@ -225,7 +224,7 @@ func newGmailFiltersCreateCmd(flags *rootFlags) *cobra.Command {
 			// Resolve label names to IDs for add/remove operations
 			var labelMap map[string]string
 			if addLabel != "" || removeLabel != "" {
-				labelMap, err = fetchLabelNameToID(svc)
+				labelMap, err = fetchLabelNameToID(cmd.Context(), svc)
 				if err != nil {
 					return err
 				}
@@ -369,6 +368,9 @@ func newGmailFiltersDeleteCmd(flags *rootFlags) *cobra.Command {
 			}
 
 			filterID := args[0]
+			if confirmErr := confirmDestructive(cmd, flags, fmt.Sprintf("delete gmail filter %s", filterID)); confirmErr != nil {
+				return confirmErr
+			}
 			err = svc.Users.Settings.Filters.Delete("me", filterID).Do()
 			if err != nil {
 				return err
@@ -0,0 +1,508 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+// runFollowupExec runs a reminder's --exec command; swapped in tests.
+var runFollowupExec = func(ctx context.Context, command string, env []string) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	c.Env = append(os.Environ(), env...)
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+func newGmailFollowupCmd(flags *rootFlags) *cobra.Command {
+	var in string
+	var lastSent bool
+	var label string
+	var execCmd string
+	var hookURL string
+	var hookToken string
+
+	cmd := &cobra.Command{
+		Use:   "followup <messageId>",
+		Short: "Remind me when a sent message gets no reply",
+		Long: `Record a follow-up reminder for a sent message (or the latest one with
+--last-sent). Reminders are checked by "gog queue run" (or "gog gmail followup
+run"), so schedule that periodically (cron, launchd, systemd timer).
+
+Once --in has passed without a reply from someone else in the thread, the
+thread is re-surfaced: --label (default "Follow-up", created if missing) and
+INBOX/UNREAD are added, --exec runs through the shell with GOG_FOLLOWUP_*
+variables set, and --hook-url receives the reminder as JSON. A reply resolves
+the reminder silently. A failed step is retried on the next run without
+repeating the steps that succeeded, up to 5 attempts.
+
+  gog gmail followup --last-sent --in 3d
+  gog gmail followup <messageId> --in 1w --exec 'notify-send "No reply: $GOG_FOLLOWUP_SUBJECT"'`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if lastSent == (len(args) == 1) {
+				return usage("specify a message ID or --last-sent")
+			}
+			delay, err := parseFollowupDelay(in)
+			if err != nil {
+				return err
+			}
+			label = strings.TrimSpace(label)
+			if label == "" {
+				return usage("--label must not be empty")
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			messageID := ""
+			if len(args) == 1 {
+				messageID = strings.TrimSpace(args[0])
+			} else {
+				messageID, err = lastSentMessageID(cmd.Context(), svc)
+				if err != nil {
+					return err
+				}
+			}
+			msg, err := svc.Users.Messages.Get("me", messageID).
+				Format("metadata").
+				MetadataHeaders("Subject", "To").
+				Context(cmd.Context()).
+				Do()
+			if err != nil {
+				return err
+			}
+
+			now := queueNow()
+			sentAt := now
+			if msg.InternalDate > 0 {
+				sentAt = time.UnixMilli(msg.InternalDate)
+			}
+			f := followupReminder{
+				ID:        newQueueID(now),
+				Account:   account,
+				MessageID: msg.Id,
+				ThreadID:  msg.ThreadId,
+				Subject:   headerValue(msg.Payload, "Subject"),
+				To:        splitCSV(headerValue(msg.Payload, "To")),
+				SentAt:    sentAt.UTC(),
+				DueAt:     now.Add(delay).UTC(),
+				CreatedAt: now.UTC(),
+				Label:     label,
+				Exec:      strings.TrimSpace(execCmd),
+				HookURL:   strings.TrimSpace(hookURL),
+				HookToken: hookToken,
+			}
+			if err := saveFollowup(f); err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"id":        f.ID,
+					"messageId": f.MessageID,
+					"threadId":  f.ThreadID,
+					"dueAt":     f.DueAt,
+				})
+			}
+			u.Out().Printf("followup\t%s", f.ID)
+			u.Out().Printf("thread_id\t%s", f.ThreadID)
+			u.Out().Printf("due_at\t%s", f.DueAt.Local().Format(time.RFC3339))
+			u.Err().Println("Run `gog queue run` (e.g. from cron) to check due follow-ups")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&in, "in", "3d", "Remind after this long without a reply (e.g. 3d, 1w, 36h)")
+	cmd.Flags().BoolVar(&lastSent, "last-sent", false, "Use the most recently sent message")
+	cmd.Flags().StringVar(&label, "label", defaultFollowupLabel, "Label added to the thread when the reminder fires")
+	cmd.Flags().StringVar(&execCmd, "exec", "", "Shell command to run when the reminder fires (GOG_FOLLOWUP_* env vars set)")
+	cmd.Flags().StringVar(&hookURL, "hook-url", "", "Webhook URL to POST the reminder to when it fires")
+	cmd.Flags().StringVar(&hookToken, "hook-token", "", "Webhook bearer token")
+
+	cmd.AddCommand(newGmailFollowupListCmd(flags))
+	cmd.AddCommand(newGmailFollowupRunCmd(flags))
+	cmd.AddCommand(newGmailFollowupRemoveCmd(flags))
+	return cmd
+}
+
+func newGmailFollowupListCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List pending follow-up reminders",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			items, err := listFollowups()
+			if err != nil {
+				return err
+			}
+			items = filterFollowupsByAccount(items, flags.Account)
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"followups": items})
+			}
+			if len(items) == 0 {
+				u.Err().Println("No follow-ups")
+				return nil
+			}
+
+			now := queueNow()
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "ID\tDUE_AT\tSTATUS\tACCOUNT\tTO\tSUBJECT")
+			for _, f := range items {
+				status := "waiting"
+				switch {
+				case f.Firing:
+					status = "firing"
+				case f.Failed:
+					status = "failed"
+				case f.LastError != "":
+					status = "retrying"
+				case f.due(now):
+					status = "due"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					f.ID,
+					f.DueAt.Local().Format("2006-01-02 15:04"),
+					status,
+					f.Account,
+					sanitizeTab(strings.Join(f.To, ",")),
+					sanitizeTab(f.Subject),
+				)
+			}
+			return nil
+		},
+	}
+}
+
+func newGmailFollowupRunCmd(flags *rootFlags) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Check due follow-ups (also done by gog queue run)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			results, err := runDueFollowups(cmd.Context(), flags.Account, dryRun, map[string]*gmail.Service{})
+			if err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{"dryRun": dryRun, "followups": results}); err != nil {
+					return err
+				}
+			} else if len(results) == 0 {
+				u.Err().Println("No follow-ups due")
+			} else {
+				writeFollowupResults(cmd.Context(), results)
+			}
+			return followupFailures(results)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List due follow-ups without checking or firing them")
+	return cmd
+}
+
+func newGmailFollowupRemoveCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <id>",
+		Aliases: []string{"rm", "cancel"},
+		Short:   "Cancel a follow-up reminder",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := strings.TrimSpace(args[0])
+			if _, err := loadFollowup(id); err != nil {
+				return err
+			}
+			if err := confirmDestructive(cmd, flags, fmt.Sprintf("cancel follow-up %s", id)); err != nil {
+				return err
+			}
+			if err := removeFollowup(id); err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"removed": true, "id": id})
+			}
+			u := ui.FromContext(cmd.Context())
+			u.Out().Printf("removed\ttrue")
+			u.Out().Printf("id\t%s", id)
+			return nil
+		},
+	}
+}
+
+func lastSentMessageID(ctx context.Context, svc *gmail.Service) (string, error) {
+	resp, err := svc.Users.Messages.List("me").LabelIds("SENT").MaxResults(1).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Messages) == 0 || resp.Messages[0].Id == "" {
+		return "", usage("no sent messages found")
+	}
+	return resp.Messages[0].Id, nil
+}
+
+type followupResult struct {
+	ID       string `json:"id"`
+	Account  string `json:"account"`
+	ThreadID string `json:"threadId"`
+	// Status is due (dry run), replied, fired, or failed.
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// runDueFollowups resolves every due reminder: replied threads are dropped,
+// the rest are re-surfaced and notified. Each reminder is claimed first, so
+// overlapping runs fire it once. Failed reminders stay pending with their
+// error and the steps that succeeded, and are retried on the next run until
+// they fail permanently or run out of attempts.
+func runDueFollowups(ctx context.Context, account string, dryRun bool, services map[string]*gmail.Service) ([]followupResult, error) {
+	items, err := listFollowups()
+	if err != nil {
+		return nil, err
+	}
+	items = filterFollowupsByAccount(items, account)
+
+	now := queueNow()
+	results := []followupResult{}
+	for _, f := range items {
+		if !f.due(now) {
+			continue
+		}
+		r := followupResult{ID: f.ID, Account: f.Account, ThreadID: f.ThreadID, Status: "due"}
+		if dryRun {
+			results = append(results, r)
+			continue
+		}
+		f, claimed, err := claimFollowup(f.ID)
+		if err != nil {
+			return results, err
+		}
+		if !claimed {
+			continue // a concurrent run has it
+		}
+		if !f.due(now) {
+			// Updated by a concurrent run since it was listed.
+			if err := finishFollowup(f, false); err != nil {
+				return results, err
+			}
+			continue
+		}
+		status, runErr := func() (string, error) {
+			svc, ok := services[f.Account]
+			if !ok {
+				var err error
+				svc, err = newGmailService(ctx, f.Account)
+				if err != nil {
+					return "", err
+				}
+				services[f.Account] = svc
+			}
+			replied, err := threadHasReply(ctx, svc, f)
+			if err != nil {
+				return "", err
+			}
+			if replied {
+				return "replied", nil
+			}
+			return "fired", fireFollowup(ctx, svc, &f)
+		}()
+		if runErr != nil {
+			r.Status = "failed"
+			r.Error = runErr.Error()
+			f.Attempts++
+			f.LastError = runErr.Error()
+			f.Failed = permanentSendError(runErr) || f.Attempts >= maxQueueAttempts
+			if err := finishFollowup(f, false); err != nil {
+				return results, err
+			}
+			results = append(results, r)
+			continue
+		}
+		r.Status = status
+		if err := finishFollowup(f, true); err != nil {
+			return results, err
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// threadHasReply reports whether someone else wrote in the thread after the
+// reminder's message was sent.
+func threadHasReply(ctx context.Context, svc *gmail.Service, f followupReminder) (bool, error) {
+	thread, err := svc.Users.Threads.Get("me", f.ThreadID).Format("minimal").Context(ctx).Do()
+	if err != nil {
+		return false, err
+	}
+	sentMs := f.SentAt.UnixMilli()
+	for _, m := range thread.Messages {
+		if m.Id == f.MessageID || m.InternalDate <= sentMs {
+			continue
+		}
+		own := false
+		for _, l := range m.LabelIds {
+			if l == "SENT" || l == "DRAFT" {
+				own = true
+				break
+			}
+		}
+		if !own {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fireFollowup re-surfaces the thread and runs the reminder's hooks,
+// skipping steps a previous attempt finished and recording new ones in f.Done.
+func fireFollowup(ctx context.Context, svc *gmail.Service, f *followupReminder) error {
+	if !f.stepDone(followupStepLabel) {
+		labelID, err := ensureLabelID(ctx, svc, f.Label)
+		if err != nil {
+			return err
+		}
+		if _, err := svc.Users.Threads.Modify("me", f.ThreadID, &gmail.ModifyThreadRequest{
+			AddLabelIds: []string{labelID, "INBOX", "UNREAD"},
+		}).Context(ctx).Do(); err != nil {
+			return err
+		}
+		f.Done = append(f.Done, followupStepLabel)
+	}
+
+	threadURL := fmt.Sprintf("https://mail.google.com/mail/?authuser=%s#all/%s", url.QueryEscape(f.Account), f.ThreadID)
+	if f.Exec != "" && !f.stepDone(followupStepExec) {
+		env := []string{
+			"GOG_FOLLOWUP_ID=" + f.ID,
+			"GOG_FOLLOWUP_ACCOUNT=" + f.Account,
+			"GOG_FOLLOWUP_MESSAGE_ID=" + f.MessageID,
+			"GOG_FOLLOWUP_THREAD_ID=" + f.ThreadID,
+			"GOG_FOLLOWUP_SUBJECT=" + f.Subject,
+			"GOG_FOLLOWUP_TO=" + strings.Join(f.To, ","),
+			"GOG_FOLLOWUP_URL=" + threadURL,
+		}
+		if err := runFollowupExec(ctx, f.Exec, env); err != nil {
+			return fmt.Errorf("exec hook: %w", err)
+		}
+		f.Done = append(f.Done, followupStepExec)
+	}
+	if f.HookURL != "" && !f.stepDone(followupStepWebhook) {
+		if err := postFollowupHook(ctx, *f, threadURL); err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+		f.Done = append(f.Done, followupStepWebhook)
+	}
+	return nil
+}
+
+func postFollowupHook(ctx context.Context, f followupReminder, threadURL string) error {
+	data, err := json.Marshal(map[string]any{
+		"type":      "followup",
+		"id":        f.ID,
+		"account":   f.Account,
+		"messageId": f.MessageID,
+		"threadId":  f.ThreadID,
+		"subject":   f.Subject,
+		"to":        f.To,
+		"sentAt":    f.SentAt,
+		"dueAt":     f.DueAt,
+		"url":       threadURL,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.HookURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if f.HookToken != "" {
+		req.Header.Set("Authorization", "Bearer "+f.HookToken)
+	}
+	client := &http.Client{Timeout: defaultHookRequestTimeoutSec * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("hook status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ensureLabelID returns the ID of the named label, creating it if needed.
+func ensureLabelID(ctx context.Context, svc *gmail.Service, name string) (string, error) {
+	nameToID, err := fetchLabelNameToID(ctx, svc)
+	if err != nil {
+		return "", err
+	}
+	if id, ok := nameToID[strings.ToLower(name)]; ok {
+		return id, nil
+	}
+	created, err := svc.Users.Labels.Create("me", &gmail.Label{
+		Name:                  name,
+		LabelListVisibility:   "labelShow",
+		MessageListVisibility: "show",
+	}).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("create label %q: %w", name, err)
+	}
+	gmailLabels.forget(svc)
+	return created.Id, nil
+}
+
+func writeFollowupResults(ctx context.Context, results []followupResult) {
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "FOLLOWUP\tACCOUNT\tTHREAD\tRESULT")
+	for _, r := range results {
+		status := r.Status
+		if r.Error != "" {
+			status = "failed: " + sanitizeTab(r.Error)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.ID, r.Account, r.ThreadID, status)
+	}
+}
+
+func followupFailures(results []followupResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.Status == "failed" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d due follow-ups failed", failed, len(results))
+	}
+	return nil
+}
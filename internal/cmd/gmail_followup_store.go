@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/statefile"
+)
+
+const defaultFollowupLabel = "Follow-up"
+
+// followupFiringSuffix marks a reminder claimed by a running queue run or
+// followup run, the same way queueSendingSuffix does for queued messages.
+const followupFiringSuffix = ".firing"
+
+// Steps of firing a reminder, recorded in followupReminder.Done so a retry
+// does not repeat the ones that already succeeded.
+const (
+	followupStepLabel   = "label"
+	followupStepExec    = "exec"
+	followupStepWebhook = "webhook"
+)
+
+// followupReminder is one pending `gmail followup` (one JSON file each).
+type followupReminder struct {
+	ID        string    `json:"id"`
+	Account   string    `json:"account"`
+	MessageID string    `json:"messageId"`
+	ThreadID  string    `json:"threadId"`
+	Subject   string    `json:"subject,omitempty"`
+	To        []string  `json:"to,omitempty"`
+	SentAt    time.Time `json:"sentAt"`
+	DueAt     time.Time `json:"dueAt"`
+	CreatedAt time.Time `json:"createdAt"`
+	Label     string    `json:"label"`
+	Exec      string    `json:"exec,omitempty"`
+	HookURL   string    `json:"hookUrl,omitempty"`
+	HookToken string    `json:"hookToken,omitempty"`
+	Attempts  int       `json:"attempts,omitempty"`
+	LastError string    `json:"lastError,omitempty"`
+	// Done lists the steps (label, exec, webhook) that already succeeded.
+	Done []string `json:"done,omitempty"`
+	// Failed is set once firing got a permanent error or ran out of
+	// attempts; runs no longer fire it.
+	Failed bool `json:"failed,omitempty"`
+	// Firing is set for a reminder claimed by a run that has not finished
+	// (or crashed); it is never fired again automatically.
+	Firing bool `json:"-"`
+}
+
+func (f followupReminder) due(now time.Time) bool {
+	return !f.Failed && !f.Firing && !f.DueAt.After(now)
+}
+
+func (f followupReminder) stepDone(step string) bool {
+	for _, s := range f.Done {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+func followupPath(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", usagef("invalid follow-up id %q", id)
+	}
+	dir, err := config.EnsureFollowupsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, id+".json"), nil
+}
+
+func saveFollowup(f followupReminder) error {
+	path, err := followupPath(f.ID)
+	if err != nil {
+		return err
+	}
+	payload, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return statefile.WriteFile(path, append(payload, '\n'), 0o600)
+}
+
+func readFollowupFile(path string) (followupReminder, error) {
+	data, err := statefile.ReadFile(path)
+	if err != nil {
+		return followupReminder{}, err
+	}
+	var f followupReminder
+	if err := json.Unmarshal(data, &f); err != nil {
+		return followupReminder{}, fmt.Errorf("%s: %w", path, err)
+	}
+	f.Firing = strings.HasSuffix(path, followupFiringSuffix)
+	return f, nil
+}
+
+// loadFollowup reads a pending reminder, or one claimed for firing.
+func loadFollowup(id string) (followupReminder, error) {
+	path, err := followupPath(id)
+	if err != nil {
+		return followupReminder{}, err
+	}
+	f, err := readFollowupFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = readFollowupFile(path + followupFiringSuffix)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return followupReminder{}, fmt.Errorf("follow-up %s not found", id)
+	}
+	return f, err
+}
+
+func removeFollowup(id string) error {
+	path, err := followupPath(id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = os.Remove(path + followupFiringSuffix)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("follow-up %s not found", id)
+	}
+	return err
+}
+
+// claimFollowup takes the reminder for firing and returns it as stored now.
+// ok is false when another run claimed it first.
+func claimFollowup(id string) (f followupReminder, ok bool, err error) {
+	path, err := followupPath(id)
+	if err != nil {
+		return followupReminder{}, false, err
+	}
+	if err := os.Rename(path, path+followupFiringSuffix); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return followupReminder{}, false, nil
+		}
+		return followupReminder{}, false, err
+	}
+	f, err = readFollowupFile(path + followupFiringSuffix)
+	f.Firing = false
+	return f, err == nil, err
+}
+
+// finishFollowup ends a claim: a resolved reminder is dropped, an
+// unresolved one (f, with its error and finished steps) is put back.
+func finishFollowup(f followupReminder, resolved bool) error {
+	path, err := followupPath(f.ID)
+	if err != nil {
+		return err
+	}
+	if !resolved {
+		f.Firing = false
+		if err := saveFollowup(f); err != nil {
+			return err
+		}
+	}
+	return os.Remove(path + followupFiringSuffix)
+}
+
+// listFollowups returns every pending reminder ordered by due time.
+func listFollowups() ([]followupReminder, error) {
+	dir, err := config.FollowupsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out := make([]followupReminder, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".json"+followupFiringSuffix) {
+			continue
+		}
+		f, err := readFollowupFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue // finished by a concurrent run
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].DueAt.Equal(out[j].DueAt) {
+			return out[i].DueAt.Before(out[j].DueAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func filterFollowupsByAccount(items []followupReminder, account string) []followupReminder {
+	account = strings.TrimSpace(account)
+	if account == "" {
+		return items
+	}
+	out := make([]followupReminder, 0, len(items))
+	for _, f := range items {
+		if strings.EqualFold(f.Account, account) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// parseFollowupDelay parses --in: Go durations plus d (days) and w (weeks).
+func parseFollowupDelay(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	invalid := usagef("invalid --in %q (use e.g. 3d, 1w, 36h)", raw)
+	if raw == "" {
+		return 0, invalid
+	}
+	var d time.Duration
+	switch unit := raw[len(raw)-1]; unit {
+	case 'd', 'w':
+		n, err := strconv.Atoi(raw[:len(raw)-1])
+		if err != nil {
+			return 0, invalid
+		}
+		d = time.Duration(n) * 24 * time.Hour
+		if unit == 'w' {
+			d *= 7
+		}
+	default:
+		var err error
+		if d, err = time.ParseDuration(raw); err != nil {
+			return 0, invalid
+		}
+	}
+	if d <= 0 {
+		return 0, usagef("--in must be positive, got %q", raw)
+	}
+	return d, nil
+}
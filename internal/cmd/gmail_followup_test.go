@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestParseFollowupDelay(t *testing.T) {
+	for raw, want := range map[string]time.Duration{
+		"3d":  72 * time.Hour,
+		"1w":  7 * 24 * time.Hour,
+		"36h": 36 * time.Hour,
+	} {
+		got, err := parseFollowupDelay(raw)
+		if err != nil || got != want {
+			t.Fatalf("%s: got %v, %v", raw, got, err)
+		}
+	}
+	for _, raw := range []string{"", "d", "0d", "-1h", "soon"} {
+		if _, err := parseFollowupDelay(raw); err == nil {
+			t.Fatalf("%q: expected error", raw)
+		}
+	}
+}
+
+func TestExecute_GmailFollowup_QueueRun(t *testing.T) {
+	t.Setenv("GOG_STATE_DIR", t.TempDir())
+
+	origNew, origNow, origExec := newGmailService, queueNow, runFollowupExec
+	t.Cleanup(func() { newGmailService, queueNow, runFollowupExec = origNew, origNow, origExec })
+	sentAt := time.Date(2025, 7, 1, 8, 0, 0, 0, time.UTC)
+	now := sentAt
+	queueNow = func() time.Time { return now }
+
+	var execEnv []string
+	runFollowupExec = func(_ context.Context, command string, env []string) error {
+		if command != "notify" {
+			t.Fatalf("unexpected command %q", command)
+		}
+		execEnv = env
+		return nil
+	}
+
+	// Thread t1 never gets a reply; thread t2 gets one from the recipient.
+	var modifyBody, createdLabel string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1", "threadId": "t1"}}})
+		case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/users/me/messages/"):
+			id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			thread := map[string]string{"m1": "t1", "m2": "t2"}[id]
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id": id, "threadId": thread, "internalDate": strconv.FormatInt(sentAt.UnixMilli(), 10),
+				"payload": map[string]any{"headers": []map[string]any{
+					{"name": "Subject", "value": "Proposal " + id},
+					{"name": "To", "value": "ada@example.com"},
+				}},
+			})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/threads/t1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1", "messages": []map[string]any{
+				{"id": "m1", "labelIds": []string{"SENT"}, "internalDate": strconv.FormatInt(sentAt.UnixMilli(), 10)},
+				{"id": "m1b", "labelIds": []string{"SENT"}, "internalDate": strconv.FormatInt(sentAt.Add(time.Hour).UnixMilli(), 10)},
+			}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/threads/t2"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t2", "messages": []map[string]any{
+				{"id": "m2", "labelIds": []string{"SENT"}, "internalDate": strconv.FormatInt(sentAt.UnixMilli(), 10)},
+				{"id": "r2", "labelIds": []string{"INBOX"}, "internalDate": strconv.FormatInt(sentAt.Add(time.Hour).UnixMilli(), 10)},
+			}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX"}}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			var l gmail.Label
+			_ = json.NewDecoder(r.Body).Decode(&l)
+			createdLabel = l.Name
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "Label_1", "name": l.Name})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/threads/t1/modify"):
+			b, _ := io.ReadAll(r.Body)
+			modifyBody = string(b)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(args); err != nil {
+					t.Fatalf("Execute %v: %v", args, err)
+				}
+			})
+		})
+	}
+	run("--account", "a@b.com", "gmail", "followup", "--last-sent", "--in", "3d", "--exec", "notify")
+	run("--account", "a@b.com", "gmail", "followup", "m2", "--in", "2d")
+
+	// Nothing due yet.
+	out := run("--json", "queue", "run")
+	if !strings.Contains(out, `"followups": []`) {
+		t.Fatalf("expected no due follow-ups: %q", out)
+	}
+
+	now = sentAt.Add(4 * 24 * time.Hour)
+	out = run("--json", "queue", "run")
+	var parsed struct {
+		Followups []followupResult `json:"followups"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v out=%q", err, out)
+	}
+	statuses := map[string]string{}
+	for _, r := range parsed.Followups {
+		statuses[r.ThreadID] = r.Status
+	}
+	if statuses["t1"] != "fired" || statuses["t2"] != "replied" {
+		t.Fatalf("unexpected results: %#v", parsed.Followups)
+	}
+	if createdLabel != defaultFollowupLabel {
+		t.Fatalf("expected label %q to be created, got %q", defaultFollowupLabel, createdLabel)
+	}
+	if !strings.Contains(modifyBody, `"addLabelIds":["Label_1","INBOX","UNREAD"]`) {
+		t.Fatalf("unexpected modify body: %q", modifyBody)
+	}
+	if !strings.Contains(strings.Join(execEnv, "\n"), "GOG_FOLLOWUP_SUBJECT=Proposal m1") {
+		t.Fatalf("unexpected exec env: %v", execEnv)
+	}
+
+	items, err := listFollowups()
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected resolved follow-ups to be removed, got %v, %v", items, err)
+	}
+}
+
+func TestExecute_GmailFollowup_RetrySkipsDoneSteps(t *testing.T) {
+	t.Setenv("GOG_STATE_DIR", t.TempDir())
+
+	origNew, origNow, origExec := newGmailService, queueNow, runFollowupExec
+	t.Cleanup(func() { newGmailService, queueNow, runFollowupExec = origNew, origNow, origExec })
+	sentAt := time.Date(2025, 7, 1, 8, 0, 0, 0, time.UTC)
+	now := sentAt.Add(4 * 24 * time.Hour)
+	queueNow = func() time.Time { return now }
+
+	execs := 0
+	runFollowupExec = func(context.Context, string, []string) error {
+		execs++
+		return nil
+	}
+	hookStatus, hooks := http.StatusBadGateway, 0
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hooks++
+		w.WriteHeader(hookStatus)
+	}))
+	defer hook.Close()
+
+	modifies := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/threads/t1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1", "messages": []map[string]any{
+				{"id": "m1", "labelIds": []string{"SENT"}, "internalDate": strconv.FormatInt(sentAt.UnixMilli(), 10)},
+			}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "Label_1", "name": defaultFollowupLabel}}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/threads/t1/modify"):
+			modifies++
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	if err := saveFollowup(followupReminder{
+		ID: "f1", Account: "a@b.com", MessageID: "m1", ThreadID: "t1", SentAt: sentAt, DueAt: sentAt.Add(time.Hour),
+		Label: defaultFollowupLabel, Exec: "notify", HookURL: hook.URL,
+	}); err != nil {
+		t.Fatalf("saveFollowup: %v", err)
+	}
+
+	// A claimed reminder belongs to another run and is left alone.
+	claimed, ok, err := claimFollowup("f1")
+	if err != nil || !ok {
+		t.Fatalf("claim: %v, %v", ok, err)
+	}
+	if _, again, _ := claimFollowup("f1"); again {
+		t.Fatalf("expected a second claim to fail")
+	}
+	if _, err := runDueFollowups(context.Background(), "", false, map[string]*gmail.Service{}); err != nil || modifies != 0 {
+		t.Fatalf("claimed reminder fired: modifies=%d err=%v", modifies, err)
+	}
+	if err := finishFollowup(claimed, false); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+
+	// The webhook fails: label and exec are recorded as done.
+	results, err := runDueFollowups(context.Background(), "", false, map[string]*gmail.Service{})
+	if err != nil || len(results) != 1 || results[0].Status != "failed" {
+		t.Fatalf("first run: %#v, %v", results, err)
+	}
+	f, err := loadFollowup("f1")
+	if err != nil || f.Attempts != 1 || strings.Join(f.Done, ",") != "label,exec" {
+		t.Fatalf("after failure: %+v, %v", f, err)
+	}
+
+	// The retry only posts the webhook.
+	hookStatus = http.StatusOK
+	results, err = runDueFollowups(context.Background(), "", false, map[string]*gmail.Service{})
+	if err != nil || len(results) != 1 || results[0].Status != "fired" {
+		t.Fatalf("retry: %#v, %v", results, err)
+	}
+	if modifies != 1 || execs != 1 || hooks != 2 {
+		t.Fatalf("modifies=%d execs=%d hooks=%d, want 1/1/2", modifies, execs, hooks)
+	}
+	if items, err := listFollowups(); err != nil || len(items) != 0 {
+		t.Fatalf("expected the fired reminder to be removed: %v, %v", items, err)
+	}
+
+	// Reminders that keep failing stop after maxQueueAttempts.
+	hookStatus = http.StatusBadGateway
+	if err := saveFollowup(followupReminder{
+		ID: "f2", Account: "a@b.com", MessageID: "m1", ThreadID: "t1", SentAt: sentAt, DueAt: sentAt.Add(time.Hour),
+		Label: defaultFollowupLabel, HookURL: hook.URL,
+	}); err != nil {
+		t.Fatalf("saveFollowup: %v", err)
+	}
+	for i := 0; i < maxQueueAttempts+2; i++ {
+		if _, err := runDueFollowups(context.Background(), "", false, map[string]*gmail.Service{}); err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+	}
+	f, err = loadFollowup("f2")
+	if err != nil || !f.Failed || f.Attempts != maxQueueAttempts {
+		t.Fatalf("expected f2 failed after %d attempts: %+v, %v", maxQueueAttempts, f, err)
+	}
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/outfmt"
@@ -59,14 +58,14 @@ func newGmailForwardingListCmd(flags *rootFlags) *cobra.Command {
 				return nil
 			}
 
-			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			tw, flush := tableWriter(cmd.Context())
 			fmt.Fprintln(tw, "EMAIL\tSTATUS")
 			for _, f := range resp.ForwardingAddresses {
 				fmt.Fprintf(tw, "%s\t%s\n",
 					f.ForwardingEmail,
 					f.VerificationStatus)
 			}
-			_ = tw.Flush()
+			flush()
 			return nil
 		},
 	}
@@ -171,6 +170,9 @@ func newGmailForwardingDeleteCmd(flags *rootFlags) *cobra.Command {
 			}
 
 			forwardingEmail := args[0]
+			if confirmErr := confirmDestructive(cmd, flags, fmt.Sprintf("delete forwarding address %s", forwardingEmail)); confirmErr != nil {
+				return confirmErr
+			}
 			err = svc.Users.Settings.ForwardingAddresses.Delete("me", forwardingEmail).Do()
 			if err != nil {
 				return err
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -9,16 +10,43 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
 )
 
 func newGmailGetCmd(flags *rootFlags) *cobra.Command {
 	var format string
 	var headers string
+	var outPath string
+	var parts bool
+	var redactSpec string
+	var pgpDecrypt bool
 
 	cmd := &cobra.Command{
 		Use:   "get <messageId>",
-		Short: "Get a message (full|metadata|raw)",
-		Args:  cobra.ExactArgs(1),
+		Short: "Get a message (full|metadata|raw|eml)",
+		Long: `Get a message.
+
+--format eml fetches the RAW message and writes the decoded RFC822 bytes
+(a standard .eml file) to --out, or to stdout without --out. --out also
+works with --format raw.
+
+--parts prints the MIME part tree (types, sizes, charsets, dispositions,
+filenames, content IDs) instead of the body; it needs --format full.
+
+Messages sent with Gmail's confidential mode only carry a placeholder body.
+--format full shows them as confidential (with expiry and restrictions when
+Gmail states them); --format eml/raw exports fail with an explanatory error.
+
+--redact emails,phones,amounts,cards (or all) masks those patterns in the
+headers and body with numbered placeholders ([EMAIL-1], [PHONE-1], ...; the
+same value keeps its number), for pasting into tickets or prompts. With
+--json it prints the redacted fields instead of the API message.
+
+--pgp-decrypt decrypts PGP/MIME (multipart/encrypted) or inline-PGP bodies
+with the local GnuPG keyring and prints the plaintext body, plus the
+signature verdict when the content was signed. With --json the result is
+under "pgp".`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			u := ui.FromContext(cmd.Context())
 			account, err := requireAccount(flags)
@@ -35,9 +63,29 @@ func newGmailGetCmd(flags *rootFlags) *cobra.Command {
 				format = "full"
 			}
 			switch format {
-			case "full", "metadata", "raw":
+			case "full", "metadata", "raw", "eml":
 			default:
-				return fmt.Errorf("invalid --format: %q (expected full|metadata|raw)", format)
+				return fmt.Errorf("invalid --format: %q (expected full|metadata|raw|eml)", format)
+			}
+			outPath = strings.TrimSpace(outPath)
+			if outPath != "" && format != "raw" && format != "eml" {
+				return usage("--out requires --format raw or eml")
+			}
+			if parts && format != "full" {
+				return usage("--parts requires --format full")
+			}
+			if format == "eml" && outPath == "" && outfmt.IsJSON(cmd.Context()) {
+				return usage("--format eml with --json requires --out")
+			}
+			red, err := parseRedact(redactSpec)
+			if err != nil {
+				return err
+			}
+			if red != nil && (format == "raw" || format == "eml" || parts) {
+				return usage("--redact works with --format full|metadata only")
+			}
+			if pgpDecrypt && (format != "full" || parts || red != nil) {
+				return usage("--pgp-decrypt requires --format full (without --parts or --redact)")
 			}
 
 			svc, err := newGmailService(cmd.Context(), account)
@@ -45,7 +93,11 @@ func newGmailGetCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			call := svc.Users.Messages.Get("me", messageID).Format(format).Context(cmd.Context())
+			apiFormat := format
+			if format == "eml" {
+				apiFormat = "raw"
+			}
+			call := svc.Users.Messages.Get("me", messageID).Format(apiFormat).Context(cmd.Context())
 			if format == "metadata" {
 				headerList := splitCSV(headers)
 				if len(headerList) == 0 {
@@ -59,8 +111,78 @@ func newGmailGetCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
+			if format == "eml" || outPath != "" {
+				eml, decodeErr := decodeGmailRaw(msg.Raw)
+				if decodeErr != nil {
+					return decodeErr
+				}
+				if info := detectConfidentialEML(eml); info != nil {
+					return confidentialExportError(msg.Id, info)
+				}
+				if outPath == "" || outPath == "-" {
+					_, err = os.Stdout.Write(eml)
+					return err
+				}
+				if err := os.WriteFile(outPath, eml, 0o600); err != nil {
+					return err
+				}
+				if outfmt.IsJSON(cmd.Context()) {
+					return outfmt.WriteJSON(os.Stdout, map[string]any{"id": msg.Id, "path": outPath, "bytes": len(eml)})
+				}
+				u.Out().Printf("id\t%s", msg.Id)
+				u.Out().Printf("path\t%s", outPath)
+				u.Out().Printf("bytes\t%d", len(eml))
+				return nil
+			}
+
+			if parts {
+				rows := mimePartTree(msg.Payload)
+				if outfmt.IsJSON(cmd.Context()) {
+					return outfmt.WriteJSON(os.Stdout, map[string]any{"id": msg.Id, "parts": rows})
+				}
+				w, flush := tableWriter(cmd.Context())
+				defer flush()
+				fmt.Fprintln(w, "PART\tTYPE\tSIZE\tCHARSET\tENCODING\tDISPOSITION\tFILENAME\tCONTENT_ID")
+				for _, r := range rows {
+					fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+						r.PartID,
+						formatMimePartType(r),
+						r.Size,
+						orDash(r.Charset),
+						orDash(r.Encoding),
+						orDash(r.Disposition),
+						orDash(sanitizeTab(r.Filename)),
+						orDash(sanitizeTab(r.ContentID)),
+					)
+				}
+				return nil
+			}
+
+			var confidential *confidentialInfo
+			if format == "full" {
+				confidential = detectConfidentialPayload(msg.Payload)
+			}
+
+			if red != nil {
+				return writeRedactedMessage(cmd, msg, format, red)
+			}
+
+			var decrypted *pgpDecrypted
+			if pgpDecrypt {
+				if decrypted, err = decryptGmailMessage(cmd.Context(), svc, msg); err != nil {
+					return err
+				}
+			}
+
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{"message": msg})
+				out := map[string]any{"message": msg}
+				if confidential != nil {
+					out["confidential"] = confidential
+				}
+				if decrypted != nil {
+					out["pgp"] = decrypted
+				}
+				return outfmt.WriteJSON(os.Stdout, out)
 			}
 
 			u.Out().Printf("id\t%s", msg.Id)
@@ -73,7 +195,7 @@ func newGmailGetCmd(flags *rootFlags) *cobra.Command {
 					u.Err().Println("Empty raw message")
 					return nil
 				}
-				decoded, err := base64.RawURLEncoding.DecodeString(msg.Raw)
+				decoded, err := decodeGmailRaw(msg.Raw)
 				if err != nil {
 					return err
 				}
@@ -85,6 +207,23 @@ func newGmailGetCmd(flags *rootFlags) *cobra.Command {
 				u.Out().Printf("to\t%s", headerValue(msg.Payload, "To"))
 				u.Out().Printf("subject\t%s", headerValue(msg.Payload, "Subject"))
 				u.Out().Printf("date\t%s", headerValue(msg.Payload, "Date"))
+				if confidential != nil {
+					u.Out().Printf("confidential\ttrue")
+					if confidential.Expires != "" {
+						u.Out().Printf("expires\t%s", confidential.Expires)
+					}
+					u.Out().Printf("restrictions\t%s", strings.Join(confidential.Restrictions, ","))
+					u.Err().Println("Sent with Gmail confidential mode: the body below is Gmail's placeholder; open the message in Gmail to read it.")
+				}
+				if decrypted != nil {
+					u.Out().Printf("pgp\tdecrypted")
+					if decrypted.Signature != "" {
+						u.Out().Printf("signature\t%s", strings.TrimSpace(decrypted.Signature+" "+decrypted.Signer))
+					}
+					u.Out().Println("")
+					u.Out().Println(decrypted.Body)
+					return nil
+				}
 				if format == "full" {
 					body := bestBodyText(msg.Payload)
 					if body != "" {
@@ -99,7 +238,68 @@ func newGmailGetCmd(flags *rootFlags) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&format, "format", "full", "Message format: full|metadata|raw")
+	cmd.Flags().StringVar(&format, "format", "full", "Message format: full|metadata|raw|eml")
 	cmd.Flags().StringVar(&headers, "headers", "", "Metadata headers (comma-separated; only for --format=metadata)")
+	cmd.Flags().BoolVar(&parts, "parts", false, "Print the MIME part tree instead of the body")
+	cmd.Flags().StringVar(&outPath, "out", "", "Write the decoded message (.eml) to a file (raw|eml; - for stdout)")
+	cmd.Flags().BoolVar(&pgpDecrypt, "pgp-decrypt", false, "Decrypt an OpenPGP-encrypted body with the local GnuPG keyring")
+	cmd.Flags().StringVar(&redactSpec, "redact", "", "Mask PII in the output: emails,phones,amounts,cards or all")
 	return cmd
 }
+
+// decodeGmailRaw decodes users.messages.get format=raw output, which may or may
+// not be padded.
+func decodeGmailRaw(raw string) ([]byte, error) {
+	if raw == "" {
+		return nil, errors.New("empty raw message")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		b, err = base64.URLEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode raw message: %w", err)
+		}
+	}
+	return b, nil
+}
+
+// writeRedactedMessage prints the headers and body of msg with PII masked.
+func writeRedactedMessage(cmd *cobra.Command, msg *gmail.Message, format string, red *redactor) error {
+	fields := [][2]string{
+		{"from", red.redact(headerValue(msg.Payload, "From"))},
+		{"to", red.redact(headerValue(msg.Payload, "To"))},
+		{"cc", red.redact(headerValue(msg.Payload, "Cc"))},
+		{"subject", red.redact(headerValue(msg.Payload, "Subject"))},
+		{"date", headerValue(msg.Payload, "Date")},
+	}
+	body := ""
+	if format == "full" {
+		body = red.redact(bestBodyText(msg.Payload))
+	}
+
+	if outfmt.IsJSON(cmd.Context()) {
+		out := map[string]any{"id": msg.Id, "threadId": msg.ThreadId, "labelIds": msg.LabelIds, "redacted": red.summary()}
+		for _, f := range fields {
+			if f[1] != "" {
+				out[f[0]] = f[1]
+			}
+		}
+		if body != "" {
+			out["body"] = body
+		}
+		return outfmt.WriteJSON(os.Stdout, out)
+	}
+	u := ui.FromContext(cmd.Context())
+	u.Out().Printf("id\t%s", msg.Id)
+	u.Out().Printf("thread_id\t%s", msg.ThreadId)
+	for _, f := range fields {
+		if f[1] != "" || f[0] != "cc" {
+			u.Out().Printf("%s\t%s", f[0], f[1])
+		}
+	}
+	if body != "" {
+		u.Out().Println("")
+		u.Out().Println(body)
+	}
+	return nil
+}
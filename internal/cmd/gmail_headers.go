@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// composeHeaders are the configurable parts of outgoing message headers.
+type composeHeaders struct {
+	MessageIDDomain string
+	Extra           map[string]string
+}
+
+// resolveComposeHeaders reads gmail.messageIdDomain/xMailer/userAgent from
+// config.json; GOG_MESSAGE_ID_DOMAIN, GOG_X_MAILER and GOG_USER_AGENT
+// override the file.
+func resolveComposeHeaders(account, from string) (composeHeaders, error) {
+	cfg, err := config.ReadConfigFile()
+	if err != nil {
+		return composeHeaders{}, err
+	}
+	g := cfg.Gmail
+	if v, ok := os.LookupEnv("GOG_MESSAGE_ID_DOMAIN"); ok {
+		g.MessageIDDomain = v
+	}
+	if v, ok := os.LookupEnv("GOG_X_MAILER"); ok {
+		g.XMailer = v
+	}
+	if v, ok := os.LookupEnv("GOG_USER_AGENT"); ok {
+		g.UserAgent = v
+	}
+
+	out := composeHeaders{MessageIDDomain: strings.TrimSpace(g.MessageIDDomain)}
+	if d := out.MessageIDDomain; d != "" && strings.ContainsAny(d, "@<> \t\r\n") {
+		return composeHeaders{}, fmt.Errorf("invalid Message-ID domain %q", d)
+	}
+	vars := map[string]string{"Version": version, "Account": account, "From": from}
+	for _, h := range []struct{ name, tmpl string }{{"X-Mailer", g.XMailer}, {"User-Agent", g.UserAgent}} {
+		if strings.TrimSpace(h.tmpl) == "" {
+			continue
+		}
+		v, err := renderHeaderTemplate(h.name, h.tmpl, vars)
+		if err != nil {
+			return composeHeaders{}, err
+		}
+		if out.Extra == nil {
+			out.Extra = map[string]string{}
+		}
+		out.Extra[h.name] = v
+	}
+	return out, nil
+}
+
+func renderHeaderTemplate(name, text string, vars map[string]string) (string, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%s template: %w", name, err)
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("%s template: %w", name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
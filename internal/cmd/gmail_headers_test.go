@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveComposeHeaders(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOG_CONFIG_DIR", dir)
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"gmail":{"messageIdDomain":"mail.corp.example","xMailer":"gogcli {{.Version}} ({{.Account}})"}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	h, err := resolveComposeHeaders("a@b.com", "a@b.com")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if h.MessageIDDomain != "mail.corp.example" || h.Extra["X-Mailer"] != "gogcli "+version+" (a@b.com)" {
+		t.Fatalf("unexpected: %#v", h)
+	}
+	if _, ok := h.Extra["User-Agent"]; ok {
+		t.Fatalf("unexpected User-Agent: %#v", h)
+	}
+
+	t.Setenv("GOG_USER_AGENT", "bot/{{.From}}")
+	t.Setenv("GOG_MESSAGE_ID_DOMAIN", "")
+	h, err = resolveComposeHeaders("a@b.com", "alias@b.com")
+	if err != nil || h.Extra["User-Agent"] != "bot/alias@b.com" || h.MessageIDDomain != "" {
+		t.Fatalf("env override: %#v %v", h, err)
+	}
+
+	t.Setenv("GOG_X_MAILER", "{{.Nope}}")
+	if _, err := resolveComposeHeaders("a@b.com", "a@b.com"); err == nil || !strings.Contains(err.Error(), "X-Mailer") {
+		t.Fatalf("expected template error, got %v", err)
+	}
+	t.Setenv("GOG_X_MAILER", "")
+	t.Setenv("GOG_MESSAGE_ID_DOMAIN", "bad domain")
+	if _, err := resolveComposeHeaders("a@b.com", "a@b.com"); err == nil {
+		t.Fatalf("expected domain error")
+	}
+}
+
+func TestBuildRFC822_ComposeHeaders(t *testing.T) {
+	raw, err := buildRFC822(mailOptions{
+		From:              "a@b.com",
+		To:                []string{"c@d.com"},
+		Subject:           "S",
+		Body:              "B",
+		AdditionalHeaders: map[string]string{"X-Mailer": "gog", "User-Agent": "gog-ua"},
+		MessageIDDomain:   "corp.example",
+	})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	s := string(raw)
+	if !strings.Contains(s, "@corp.example>\r\n") || !strings.Contains(s, "User-Agent: gog-ua\r\nX-Mailer: gog\r\n") {
+		t.Fatalf("unexpected raw:\n%s", s)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/outfmt"
@@ -13,17 +14,40 @@ func newGmailHistoryCmd(flags *rootFlags) *cobra.Command {
 	var since string
 	var max int64
 	var page string
+	var pages pageFlags
+	var follow bool
+	var interval time.Duration
+	var types []string
+	var notify desktopNotifyFlag
 
 	cmd := &cobra.Command{
 		Use:   "history",
 		Short: "List Gmail history entries",
-		Args:  cobra.NoArgs,
+		Long: `List messages added since a history ID.
+
+--follow keeps polling instead and prints every change as an NDJSON event
+(messageAdded, messageDeleted, labelAdded, labelRemoved; --types narrows it)
+until interrupted. It starts at --since, else where the last --follow for the
+account stopped, else now, and saves its cursor after every poll — a way to
+watch a mailbox without Pub/Sub. With --notify-desktop, new Inbox messages
+also raise a desktop notification (filtered by --notify-rule).`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			u := ui.FromContext(cmd.Context())
 			account, err := requireAccount(flags)
 			if err != nil {
 				return err
 			}
+			if follow {
+				notifier, err := notify.notifier(u)
+				if err != nil {
+					return err
+				}
+				return runGmailHistoryFollow(cmd, account, since, types, interval, notifier)
+			}
+			if cmd.Flags().Changed("types") || cmd.Flags().Changed("interval") || notify.enabled {
+				return usage("--types, --interval and --notify-desktop require --follow")
+			}
 			if strings.TrimSpace(since) == "" {
 				return usage("--since is required")
 			}
@@ -37,23 +61,56 @@ func newGmailHistoryCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			call := svc.Users.History.List("me").StartHistoryId(startID).MaxResults(max)
-			call.HistoryTypes("messageAdded")
-			if strings.TrimSpace(page) != "" {
-				call.PageToken(page)
+			if outfmt.IsNDJSON(cmd.Context()) {
+				type historyLine struct {
+					MessageID string `json:"messageId"`
+				}
+				return streamNDJSONPages(cmd.Context(), page, pages.ndjsonCap(max), 500, func(pageToken string, pageSize int64) ([]historyLine, string, error) {
+					call := svc.Users.History.List("me").StartHistoryId(startID).MaxResults(pageSize).HistoryTypes("messageAdded")
+					if pageToken != "" {
+						call.PageToken(pageToken)
+					}
+					resp, err := call.Context(cmd.Context()).Do()
+					if err != nil {
+						return nil, "", err
+					}
+					ids := collectHistoryMessageIDs(resp)
+					lines := make([]historyLine, 0, len(ids))
+					for _, id := range ids {
+						lines = append(lines, historyLine{MessageID: id})
+					}
+					return lines, resp.NextPageToken, nil
+				})
 			}
-			resp, err := call.Do()
+
+			var historyID uint64
+			ids, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]string, string, error) {
+				call := svc.Users.History.List("me").StartHistoryId(startID).MaxResults(max)
+				call.HistoryTypes("messageAdded")
+				if strings.TrimSpace(pageToken) != "" {
+					call.PageToken(pageToken)
+				}
+				resp, err := call.Do()
+				if err != nil {
+					return nil, "", err
+				}
+				historyID = resp.HistoryId
+				return collectHistoryMessageIDs(resp), resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(ids))
 			if err != nil {
 				return err
 			}
 
-			ids := collectHistoryMessageIDs(resp)
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
-					"historyId":     formatHistoryID(resp.HistoryId),
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"historyId":     formatHistoryID(historyID),
 					"messages":      ids,
-					"nextPageToken": resp.NextPageToken,
-				})
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
 			if len(ids) == 0 {
 				u.Err().Println("No history")
@@ -63,13 +120,53 @@ func newGmailHistoryCmd(flags *rootFlags) *cobra.Command {
 			for _, id := range ids {
 				u.Out().Println(id)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().StringVar(&since, "since", "", "Start history ID")
 	cmd.Flags().Int64Var(&max, "max", defaultHistoryMaxResults, "Max results")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
+	cmd.Flags().BoolVar(&follow, "follow", false, "Keep polling and print changes as NDJSON events")
+	cmd.Flags().DurationVar(&interval, "interval", defaultHistoryFollowInterval, "Poll interval for --follow")
+	cmd.Flags().StringSliceVar(&types, "types", nil, "Event types for --follow: messageAdded,messageDeleted,labelAdded,labelRemoved (default: all)")
+	notify.addFlags(cmd, "from,to,subject,snippet,labels")
 	return cmd
 }
+
+func runGmailHistoryFollow(cmd *cobra.Command, account, since string, rawTypes []string, interval time.Duration, notifier *desktopNotifier) error {
+	if cmd.Flags().Changed("page") || cmd.Flags().Changed("all") {
+		return usage("--page/--all do not apply to --follow")
+	}
+	if interval <= 0 {
+		return usage("--interval must be positive")
+	}
+	types, err := parseHistoryTypes(rawTypes)
+	if err != nil {
+		return err
+	}
+
+	var startID uint64
+	if strings.TrimSpace(since) != "" {
+		if startID, err = parseHistoryID(since); err != nil {
+			return err
+		}
+	} else if startID, err = loadHistoryCursor(account); err != nil {
+		return err
+	}
+
+	svc, err := newGmailService(cmd.Context(), account)
+	if err != nil {
+		return err
+	}
+	if startID == 0 {
+		profile, err := svc.Users.GetProfile("me").Context(cmd.Context()).Do()
+		if err != nil {
+			return err
+		}
+		startID = profile.HistoryId
+	}
+	return followGmailHistory(cmd.Context(), svc, os.Stdout, account, startID, types, interval, notifier)
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/statefile"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const defaultHistoryFollowInterval = 30 * time.Second
+
+var gmailHistoryTypes = []string{"messageAdded", "messageDeleted", "labelAdded", "labelRemoved"}
+
+// historyEvent is one NDJSON line of `gmail history --follow`. LabelIDs are
+// the message's labels for messageAdded and the changed labels otherwise.
+type historyEvent struct {
+	Type      string   `json:"type"`
+	HistoryID string   `json:"historyId"`
+	MessageID string   `json:"messageId,omitempty"`
+	ThreadID  string   `json:"threadId,omitempty"`
+	LabelIDs  []string `json:"labelIds,omitempty"`
+}
+
+type historyCursor struct {
+	Account     string `json:"account"`
+	HistoryID   string `json:"historyId"`
+	UpdatedAtMs int64  `json:"updatedAtMs"`
+}
+
+func historyCursorPath(account string) (string, error) {
+	dir, err := config.EnsureGmailHistoryDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sanitizeAccountForPath(account)+".json"), nil
+}
+
+// loadHistoryCursor returns the stored follow cursor (0 if none).
+func loadHistoryCursor(account string) (uint64, error) {
+	path, err := historyCursorPath(account)
+	if err != nil {
+		return 0, err
+	}
+	data, err := statefile.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	var c historyCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+	if c.HistoryID == "" {
+		return 0, nil
+	}
+	return parseHistoryID(c.HistoryID)
+}
+
+func saveHistoryCursor(account string, id uint64) error {
+	path, err := historyCursorPath(account)
+	if err != nil {
+		return err
+	}
+	payload, err := json.MarshalIndent(historyCursor{
+		Account:     account,
+		HistoryID:   formatHistoryID(id),
+		UpdatedAtMs: time.Now().UnixMilli(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return statefile.WriteFile(path, append(payload, '\n'), 0o600)
+}
+
+func parseHistoryTypes(raw []string) ([]string, error) {
+	if len(raw) == 0 {
+		return gmailHistoryTypes, nil
+	}
+	out := make([]string, 0, len(raw))
+	for _, r := range raw {
+		found := ""
+		for _, t := range gmailHistoryTypes {
+			if strings.EqualFold(strings.TrimSpace(r), t) {
+				found = t
+			}
+		}
+		if found == "" {
+			return nil, usagef("unknown history type %q (expected %s)", r, strings.Join(gmailHistoryTypes, "|"))
+		}
+		out = append(out, found)
+	}
+	return out, nil
+}
+
+// historyEvents flattens a history page into events, in history order.
+func historyEvents(resp *gmail.ListHistoryResponse) []historyEvent {
+	if resp == nil {
+		return nil
+	}
+	var out []historyEvent
+	for _, h := range resp.History {
+		if h == nil {
+			continue
+		}
+		id := formatHistoryID(h.Id)
+		for _, a := range h.MessagesAdded {
+			if a != nil && a.Message != nil {
+				out = append(out, historyEvent{Type: "messageAdded", HistoryID: id, MessageID: a.Message.Id, ThreadID: a.Message.ThreadId, LabelIDs: a.Message.LabelIds})
+			}
+		}
+		for _, d := range h.MessagesDeleted {
+			if d != nil && d.Message != nil {
+				out = append(out, historyEvent{Type: "messageDeleted", HistoryID: id, MessageID: d.Message.Id, ThreadID: d.Message.ThreadId})
+			}
+		}
+		for _, l := range h.LabelsAdded {
+			if l != nil && l.Message != nil {
+				out = append(out, historyEvent{Type: "labelAdded", HistoryID: id, MessageID: l.Message.Id, ThreadID: l.Message.ThreadId, LabelIDs: l.LabelIds})
+			}
+		}
+		for _, l := range h.LabelsRemoved {
+			if l != nil && l.Message != nil {
+				out = append(out, historyEvent{Type: "labelRemoved", HistoryID: id, MessageID: l.Message.Id, ThreadID: l.Message.ThreadId, LabelIDs: l.LabelIds})
+			}
+		}
+	}
+	return out
+}
+
+// followGmailHistory polls history from startID, writing events to w and
+// saving the cursor after every poll, until ctx is done. A cursor Gmail no
+// longer has (about a week old) restarts from the current mailbox state
+// with a "reset" event. A non-nil notifier is told about messages added to
+// the Inbox.
+func followGmailHistory(ctx context.Context, svc *gmail.Service, w io.Writer, account string, startID uint64, types []string, interval time.Duration, notifier *desktopNotifier) error {
+	u := ui.FromContext(ctx)
+	cursor := startID
+	for {
+		latest := cursor
+		pageToken := ""
+		for {
+			call := svc.Users.History.List("me").StartHistoryId(cursor).HistoryTypes(types...).MaxResults(500)
+			if pageToken != "" {
+				call = call.PageToken(pageToken)
+			}
+			resp, err := call.Context(ctx).Do()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				var apiErr *googleapi.Error
+				if !errors.As(err, &apiErr) || apiErr.Code != http.StatusNotFound {
+					return err
+				}
+				profile, perr := svc.Users.GetProfile("me").Context(ctx).Do()
+				if perr != nil {
+					return perr
+				}
+				if u != nil {
+					u.Err().Printf("WARN: history %d expired; restarting from %d (changes in between were missed)", cursor, profile.HistoryId)
+				}
+				latest = profile.HistoryId
+				if err := outfmt.WriteNDJSON(w, historyEvent{Type: "reset", HistoryID: formatHistoryID(latest)}); err != nil {
+					return err
+				}
+				break
+			}
+			for _, ev := range historyEvents(resp) {
+				if err := outfmt.WriteNDJSON(w, ev); err != nil {
+					return err
+				}
+				if notifier != nil && ev.Type == "messageAdded" && slices.Contains(ev.LabelIDs, "INBOX") {
+					notifier.notifyGmailMessageID(ctx, svc, ev.MessageID)
+				}
+			}
+			if resp.HistoryId > latest {
+				latest = resp.HistoryId
+			}
+			if resp.NextPageToken == "" {
+				break
+			}
+			pageToken = resp.NextPageToken
+		}
+		cursor = latest
+		if err := saveHistoryCursor(account, cursor); err != nil {
+			return err
+		}
+		if err := watchDaemonSleep(ctx, interval); err != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestParseHistoryTypes(t *testing.T) {
+	got, err := parseHistoryTypes([]string{"messageadded", "LabelRemoved"})
+	if err != nil || len(got) != 2 || got[0] != "messageAdded" || got[1] != "labelRemoved" {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	if _, err := parseHistoryTypes([]string{"draftAdded"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestFollowGmailHistory(t *testing.T) {
+	t.Setenv("GOG_STATE_DIR", t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/history"):
+			polls++
+			if polls == 1 {
+				if r.URL.Query().Get("startHistoryId") != "100" {
+					t.Errorf("startHistoryId = %q", r.URL.Query().Get("startHistoryId"))
+				}
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found."}}`))
+				return
+			}
+			if polls == 3 {
+				// Stop following; the second poll's cursor is already saved.
+				cancel()
+				_ = json.NewEncoder(w).Encode(map[string]any{"historyId": "520"})
+				return
+			}
+			if r.URL.Query().Get("startHistoryId") != "500" {
+				t.Errorf("startHistoryId after reset = %q", r.URL.Query().Get("startHistoryId"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"historyId": "520",
+				"history": []map[string]any{
+					{"id": "510", "messagesAdded": []map[string]any{{"message": map[string]any{"id": "m1", "threadId": "t1", "labelIds": []string{"INBOX"}}}}},
+					{"id": "515", "labelsRemoved": []map[string]any{{"message": map[string]any{"id": "m1", "threadId": "t1"}, "labelIds": []string{"UNREAD"}}}},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/users/me/profile"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"emailAddress": "a@b.com", "historyId": "500"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := followGmailHistory(ctx, svc, &buf, "a@b.com", 100, gmailHistoryTypes, time.Millisecond, nil); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+
+	var events []historyEvent
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var ev historyEvent
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		events = append(events, ev)
+	}
+	if len(events) != 3 || events[0].Type != "reset" || events[1].Type != "messageAdded" || events[2].Type != "labelRemoved" || events[2].LabelIDs[0] != "UNREAD" {
+		t.Fatalf("events = %#v", events)
+	}
+
+	cursor, err := loadHistoryCursor("a@b.com")
+	if err != nil || cursor != 520 {
+		t.Fatalf("cursor = %d, %v", cursor, err)
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+	gapi "google.golang.org/api/googleapi"
+)
+
+func newGmailImportCmd(flags *rootFlags) *cobra.Command {
+	return newGmailImportInsertCmd(flags, true)
+}
+
+func newGmailInsertCmd(flags *rootFlags) *cobra.Command {
+	return newGmailImportInsertCmd(flags, false)
+}
+
+// newGmailImportInsertCmd builds "gmail import" (users.messages.import: spam
+// scanning and delivery-like handling) or "gmail insert"
+// (users.messages.insert: stored as-is, like IMAP APPEND).
+func newGmailImportInsertCmd(flags *rootFlags, importMode bool) *cobra.Command {
+	var labels string
+	var dateSource string
+	var dateFromHeader bool
+	var noSpamCheck bool
+
+	use, short, long := "insert <file.eml|->", "Insert an .eml file into the mailbox as-is (users.messages.insert)", `Insert an RFC822 (.eml) message directly into the mailbox, like IMAP APPEND.
+No scanning or classification happens. Use - to read from stdin.
+
+Without --label the message is only visible in All Mail.
+--internal-date-source: receivedTime (default) or dateHeader
+(--internal-date-from-header is short for dateHeader).`
+	if importMode {
+		use, short, long = "import <file.eml|->", "Import an .eml file like received mail (users.messages.import)", `Import an RFC822 (.eml) message with standard email delivery scanning and
+classification, e.g. when migrating from another mail system. Use - to read
+from stdin.
+
+Without --label the message is only visible in All Mail.
+--internal-date-source: dateHeader (default) or receivedTime
+(--internal-date-from-header is short for dateHeader).
+--no-spam-check never marks the message as spam.`
+	}
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			source, err := parseInternalDateSource(dateSource)
+			if err != nil {
+				return err
+			}
+			if dateFromHeader {
+				if source != "" && source != "dateHeader" {
+					return usage("--internal-date-from-header conflicts with --internal-date-source " + source)
+				}
+				source = "dateHeader"
+			}
+
+			var raw []byte
+			if args[0] == "-" {
+				raw, err = readStdin()
+			} else {
+				raw, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return err
+			}
+			if len(bytes.TrimSpace(raw)) == 0 {
+				return usage("empty message")
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			msg := &gmail.Message{}
+			if labelList := splitCSV(labels); len(labelList) > 0 {
+				ids, labelErr := resolveLabelIDsWithService(cmd.Context(), svc, labelList)
+				if labelErr != nil {
+					return labelErr
+				}
+				msg.LabelIds = ids
+			}
+
+			media := gapi.ContentType("message/rfc822")
+			var saved *gmail.Message
+			if importMode {
+				call := svc.Users.Messages.Import("me", msg).
+					Media(bytes.NewReader(raw), media).
+					NeverMarkSpam(noSpamCheck).
+					Context(cmd.Context())
+				if source != "" {
+					call = call.InternalDateSource(source)
+				}
+				saved, err = call.Do()
+			} else {
+				call := svc.Users.Messages.Insert("me", msg).
+					Media(bytes.NewReader(raw), media).
+					Context(cmd.Context())
+				if source != "" {
+					call = call.InternalDateSource(source)
+				}
+				saved, err = call.Do()
+			}
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"messageId": saved.Id,
+					"threadId":  saved.ThreadId,
+					"labelIds":  saved.LabelIds,
+				})
+			}
+			u.Out().Printf("message_id\t%s", saved.Id)
+			if saved.ThreadId != "" {
+				u.Out().Printf("thread_id\t%s", saved.ThreadId)
+			}
+			if len(saved.LabelIds) > 0 {
+				u.Out().Printf("label_ids\t%s", strings.Join(saved.LabelIds, ","))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&labels, "label", "", "Labels to apply (comma-separated, name or ID; e.g. INBOX,UNREAD)")
+	cmd.Flags().StringVar(&dateSource, "internal-date-source", "", "Internal date source: dateHeader|receivedTime")
+	cmd.Flags().BoolVar(&dateFromHeader, "internal-date-from-header", false, "Use the Date header as the internal date (same as --internal-date-source dateHeader)")
+	if importMode {
+		cmd.Flags().BoolVar(&noSpamCheck, "no-spam-check", false, "Never mark the imported message as spam")
+	}
+	return cmd
+}
+
+func parseInternalDateSource(v string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "":
+		return "", nil
+	case "dateheader", "date-header", "header":
+		return "dateHeader", nil
+	case "receivedtime", "received-time", "received":
+		return "receivedTime", nil
+	default:
+		return "", fmt.Errorf("invalid --internal-date-source: %q (expected dateHeader|receivedTime)", v)
+	}
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/statefile"
+	"google.golang.org/api/gmail/v1"
+)
+
+// labelCacheTTLEnv overrides gmail.labelCacheTTL (e.g. 10m; 0 disables).
+const labelCacheTTLEnv = "GOG_LABEL_CACHE_TTL"
+
+// inProcessLabelTTL bounds how long one invocation (gmail tui, watch serve)
+// reuses a labels.list result.
+const inProcessLabelTTL = 5 * time.Minute
+
+// gmailServiceAccounts remembers the account each Gmail service was built
+// for, so services of the same account share cached labels.
+var gmailServiceAccounts sync.Map // *gmail.Service -> account
+
+var newGmailService = func(ctx context.Context, email string) (*gmail.Service, error) {
+	svc, err := googleapi.NewGmail(ctx, email)
+	if err == nil {
+		gmailServiceAccounts.Store(svc, strings.ToLower(strings.TrimSpace(email)))
+	}
+	return svc, err
+}
+
+// gmailLabelCache shares labels.list results per account within one
+// invocation, so composite commands (search, thread, rules, ...) list labels
+// once. With gmail.labelCacheTTL the result is also kept on disk for later
+// runs. Concurrent callers for the same account wait for one fetch.
+type gmailLabelCache struct {
+	mu      sync.Mutex
+	entries map[any]*labelCacheEntry
+}
+
+type labelCacheEntry struct {
+	mu      sync.Mutex
+	labels  []*gmail.Label
+	fetched time.Time
+}
+
+type diskLabelCache struct {
+	Fetched time.Time      `json:"fetched"`
+	Labels  []*gmail.Label `json:"labels"`
+}
+
+var gmailLabels = &gmailLabelCache{entries: map[any]*labelCacheEntry{}}
+
+// labelCacheNow is swapped in tests.
+var labelCacheNow = time.Now
+
+// resetGmailLabelCache starts a new invocation with an empty cache.
+func resetGmailLabelCache() {
+	gmailLabels.mu.Lock()
+	gmailLabels.entries = map[any]*labelCacheEntry{}
+	gmailLabels.mu.Unlock()
+	gmailServiceAccounts.Clear()
+}
+
+// labelCacheKey is the service's account, or the service itself when it was
+// not built by newGmailService (tests).
+func labelCacheKey(svc *gmail.Service) (any, string) {
+	if v, ok := gmailServiceAccounts.Load(svc); ok {
+		account := v.(string)
+		return account, account
+	}
+	return svc, ""
+}
+
+func (c *gmailLabelCache) entry(key any) *labelCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &labelCacheEntry{}
+		c.entries[key] = e
+	}
+	return e
+}
+
+// list returns the labels of svc's account, fetching them at most once per
+// inProcessLabelTTL.
+func (c *gmailLabelCache) list(ctx context.Context, svc *gmail.Service) ([]*gmail.Label, error) {
+	key, account := labelCacheKey(svc)
+	e := c.entry(key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := labelCacheNow()
+	if e.labels != nil && now.Sub(e.fetched) < inProcessLabelTTL {
+		return e.labels, nil
+	}
+
+	ttl := labelCacheDiskTTL()
+	if e.labels == nil && account != "" && ttl > 0 {
+		if d, ok := readDiskLabelCache(account); ok && now.Sub(d.Fetched) < ttl {
+			e.labels, e.fetched = d.Labels, d.Fetched
+			return e.labels, nil
+		}
+	}
+
+	resp, err := svc.Users.Labels.List("me").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	e.labels, e.fetched = resp.Labels, now
+	if e.labels == nil {
+		e.labels = []*gmail.Label{}
+	}
+	if account != "" && ttl > 0 {
+		if err := writeDiskLabelCache(account, diskLabelCache{Fetched: now, Labels: e.labels}); err != nil {
+			slog.Debug("label cache write failed", "account", account, "err", err)
+		}
+	}
+	return e.labels, nil
+}
+
+// forget drops svc's account's labels after a label was created, renamed or
+// deleted.
+func (c *gmailLabelCache) forget(svc *gmail.Service) {
+	key, account := labelCacheKey(svc)
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+	if account != "" {
+		if path, err := labelCachePath(account); err == nil {
+			_ = os.Remove(path)
+		}
+	}
+}
+
+func labelCacheDiskTTL() time.Duration {
+	raw := strings.TrimSpace(os.Getenv(labelCacheTTLEnv))
+	if raw == "" {
+		cfg, err := config.ReadConfigFile()
+		if err != nil {
+			return 0
+		}
+		raw = strings.TrimSpace(cfg.Gmail.LabelCacheTTL)
+	}
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		slog.Warn("ignoring invalid label cache TTL", "value", raw)
+		return 0
+	}
+	return d
+}
+
+func labelCachePath(account string) (string, error) {
+	dir, err := config.GmailCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sanitizeAccountForPath(account)+".labels.json"), nil
+}
+
+func readDiskLabelCache(account string) (diskLabelCache, bool) {
+	var d diskLabelCache
+	path, err := labelCachePath(account)
+	if err != nil {
+		return d, false
+	}
+	data, err := statefile.ReadFile(path)
+	if err != nil {
+		return d, false
+	}
+	if json.Unmarshal(data, &d) != nil || d.Labels == nil {
+		return d, false
+	}
+	return d, true
+}
+
+func writeDiskLabelCache(account string, d diskLabelCache) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	path, err := labelCachePath(account)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return statefile.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func newLabelCacheTestService(t *testing.T, calls *atomic.Int32) *gmail.Service {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/users/me/labels") {
+			http.NotFound(w, r)
+			return
+		}
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"labels": []map[string]any{
+				{"id": "INBOX", "name": "INBOX", "type": "system"},
+				{"id": "Label_1", "name": "Work", "type": "user"},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	return svc
+}
+
+func TestGmailLabelCache_SharedAcrossGoroutines(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	t.Setenv(labelCacheTTLEnv, "")
+	resetGmailLabelCache()
+	t.Cleanup(resetGmailLabelCache)
+
+	var calls atomic.Int32
+	svc := newLabelCacheTestService(t, &calls)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m, err := fetchLabelIDToName(context.Background(), svc)
+			if err != nil || m["Label_1"] != "Work" {
+				t.Errorf("fetchLabelIDToName = %v, %v", m, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := fetchLabelNameToID(context.Background(), svc); err != nil {
+		t.Fatalf("fetchLabelNameToID: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("labels.list calls = %d, want 1", got)
+	}
+
+	gmailLabels.forget(svc)
+	if _, err := fetchLabelNameToID(context.Background(), svc); err != nil {
+		t.Fatalf("fetchLabelNameToID: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("labels.list calls after forget = %d, want 2", got)
+	}
+}
+
+func TestGmailLabelCache_DiskTTL(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	t.Setenv("GOG_CACHE_DIR", t.TempDir())
+	t.Setenv(labelCacheTTLEnv, "10m")
+	t.Cleanup(resetGmailLabelCache)
+	origNow := labelCacheNow
+	t.Cleanup(func() { labelCacheNow = origNow })
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	labelCacheNow = func() time.Time { return now }
+
+	var calls atomic.Int32
+	run := func() {
+		t.Helper()
+		// Each run starts with an empty in-process cache and a new service.
+		resetGmailLabelCache()
+		svc := newLabelCacheTestService(t, &calls)
+		gmailServiceAccounts.Store(svc, "a@b.com")
+		if _, err := fetchLabelIDToName(context.Background(), svc); err != nil {
+			t.Fatalf("fetchLabelIDToName: %v", err)
+		}
+	}
+
+	run()
+	now = now.Add(5 * time.Minute)
+	run()
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("labels.list calls within TTL = %d, want 1", got)
+	}
+	now = now.Add(6 * time.Minute)
+	run()
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("labels.list calls after TTL = %d, want 2", got)
+	}
+}
+
+func TestExecute_GmailLabelsRename_DropsDiskCache(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	t.Setenv("GOG_CACHE_DIR", t.TempDir())
+	t.Setenv(labelCacheTTLEnv, "10m")
+	resetGmailLabelCache()
+	t.Cleanup(resetGmailLabelCache)
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	name := "Work"
+	var lists atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			lists.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "Label_1", "name": name, "type": "user"}}})
+		case r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/users/me/labels/Label_1"):
+			var l gmail.Label
+			_ = json.NewDecoder(r.Body).Decode(&l)
+			name = l.Name
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "Label_1", "name": name})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) {
+		gmailServiceAccounts.Store(svc, "a@b.com")
+		return svc, nil
+	}
+
+	prime, _ := newGmailService(context.Background(), "a@b.com")
+	if m, err := fetchLabelNameToID(context.Background(), prime); err != nil || m["work"] != "Label_1" {
+		t.Fatalf("prime cache: %v, %v", m, err)
+	}
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "labels", "rename", "Work", "Job"}); err != nil {
+				t.Fatalf("rename: %v", err)
+			}
+		})
+	})
+
+	// A later run must not read the pre-rename labels from disk.
+	resetGmailLabelCache()
+	later, _ := newGmailService(context.Background(), "a@b.com")
+	m, err := fetchLabelNameToID(context.Background(), later)
+	if err != nil || m["job"] != "Label_1" {
+		t.Fatalf("after rename: %v, %v (labels.list calls %d)", m, err, lists.Load())
+	}
+}
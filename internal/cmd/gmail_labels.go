@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -14,12 +15,18 @@ import (
 func newGmailLabelsCmd(flags *rootFlags) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "labels",
-		Short: "List and modify labels",
+		Short: "List, create and modify labels",
 	}
 
 	cmd.AddCommand(newGmailLabelsListCmd(flags))
 	cmd.AddCommand(newGmailLabelsGetCmd(flags))
 	cmd.AddCommand(newGmailLabelsModifyCmd(flags))
+	cmd.AddCommand(newGmailLabelsCreateCmd(flags))
+	cmd.AddCommand(newGmailLabelsRenameCmd(flags))
+	cmd.AddCommand(newGmailLabelsDeleteCmd(flags))
+	cmd.AddCommand(newGmailLabelsColorCmd(flags))
+	cmd.AddCommand(newGmailLabelsMoveCmd(flags))
+	cmd.AddCommand(newGmailLabelsApplyCmd(flags))
 	return cmd
 }
 
@@ -39,7 +46,7 @@ func newGmailLabelsGetCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			idMap, err := fetchLabelNameToID(svc)
+			idMap, err := fetchLabelNameToID(cmd.Context(), svc)
 			if err != nil {
 				return err
 			}
@@ -138,7 +145,7 @@ func newGmailLabelsModifyCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			idMap, err := fetchLabelNameToID(svc)
+			idMap, err := fetchLabelNameToID(cmd.Context(), svc)
 			if err != nil {
 				return err
 			}
@@ -182,13 +189,13 @@ func newGmailLabelsModifyCmd(flags *rootFlags) *cobra.Command {
 	return cmd
 }
 
-func fetchLabelNameToID(svc *gmail.Service) (map[string]string, error) {
-	resp, err := svc.Users.Labels.List("me").Do()
+func fetchLabelNameToID(ctx context.Context, svc *gmail.Service) (map[string]string, error) {
+	labels, err := gmailLabels.list(ctx, svc)
 	if err != nil {
 		return nil, err
 	}
-	m := make(map[string]string, len(resp.Labels))
-	for _, l := range resp.Labels {
+	m := make(map[string]string, len(labels))
+	for _, l := range labels {
 		if l.Id == "" {
 			continue
 		}
@@ -200,13 +207,13 @@ func fetchLabelNameToID(svc *gmail.Service) (map[string]string, error) {
 	return m, nil
 }
 
-func fetchLabelIDToName(svc *gmail.Service) (map[string]string, error) {
-	resp, err := svc.Users.Labels.List("me").Do()
+func fetchLabelIDToName(ctx context.Context, svc *gmail.Service) (map[string]string, error) {
+	labels, err := gmailLabels.list(ctx, svc)
 	if err != nil {
 		return nil, err
 	}
-	m := make(map[string]string, len(resp.Labels))
-	for _, l := range resp.Labels {
+	m := make(map[string]string, len(labels))
+	for _, l := range labels {
 		if l.Id == "" {
 			continue
 		}
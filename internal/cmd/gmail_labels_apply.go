@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+// labelApplyResult summarizes a labels apply run.
+type labelApplyResult struct {
+	Query         string   `json:"query"`
+	DryRun        bool     `json:"dryRun"`
+	Matched       int      `json:"matched"`
+	Modified      int      `json:"modified"`
+	Batches       int      `json:"batches"`
+	AddedLabels   []string `json:"addedLabels,omitempty"`
+	RemovedLabels []string `json:"removedLabels,omitempty"`
+}
+
+func newGmailLabelsApplyCmd(flags *rootFlags) *cobra.Command {
+	var query, add, remove string
+	var batchSize int
+	var delay time.Duration
+	var max int64
+	var includeSpamTrash, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "apply --query <q> [--add L,...] [--remove L,...]",
+		Short: "Add/remove labels on every message matching a search",
+		Long: `Relabel every message matching a Gmail search with users.messages.batchModify.
+
+Matching IDs are collected page by page and sent in chunks of --batch-size
+(at most 1000), sleeping --delay between chunks to stay under the per-user
+quota. Progress goes to stderr. Asks for confirmation unless --force.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(query) == "" {
+				return usage("--query is required")
+			}
+			addLabels, removeLabels := splitCSV(add), splitCSV(remove)
+			if len(addLabels) == 0 && len(removeLabels) == 0 {
+				return usage("must specify --add and/or --remove")
+			}
+			if batchSize < 1 || batchSize > gmailBatchModifyMax {
+				return usagef("invalid --batch-size %d (must be 1-%d)", batchSize, gmailBatchModifyMax)
+			}
+			if delay < 0 {
+				return usagef("invalid --delay %s (must be >= 0)", delay)
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			idMap, err := fetchLabelNameToID(cmd.Context(), svc)
+			if err != nil {
+				return err
+			}
+			addIDs, err := lookupLabelIDs(addLabels, idMap)
+			if err != nil {
+				return err
+			}
+			removeIDs, err := lookupLabelIDs(removeLabels, idMap)
+			if err != nil {
+				return err
+			}
+
+			if !dryRun {
+				if err := confirmDestructive(cmd, flags, fmt.Sprintf("change labels on every message matching %q", query)); err != nil {
+					return err
+				}
+			}
+
+			res := labelApplyResult{Query: query, DryRun: dryRun, AddedLabels: addIDs, RemovedLabels: removeIDs}
+			err = applyLabelsByQuery(cmd.Context(), svc, query, includeSpamTrash, max, batchSize, func(ids []string) error {
+				res.Matched += len(ids)
+				if dryRun {
+					return nil
+				}
+				if res.Batches > 0 {
+					if err := sleepContext(cmd.Context(), delay); err != nil {
+						return err
+					}
+				}
+				if err := svc.Users.Messages.BatchModify("me", &gmail.BatchModifyMessagesRequest{
+					Ids:            ids,
+					AddLabelIds:    addIDs,
+					RemoveLabelIds: removeIDs,
+				}).Context(cmd.Context()).Do(); err != nil {
+					return fmt.Errorf("batch %d (after %d modified): %w", res.Batches+1, res.Modified, err)
+				}
+				res.Batches++
+				res.Modified += len(ids)
+				if u != nil {
+					u.Err().Printf("labeled %d (batch %d)", res.Modified, res.Batches)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, res)
+			}
+			u.Out().Printf("matched\t%d", res.Matched)
+			if dryRun {
+				return nil
+			}
+			u.Out().Printf("modified\t%d", res.Modified)
+			u.Out().Printf("batches\t%d", res.Batches)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&query, "query", "", "Gmail search query selecting the messages")
+	cmd.Flags().StringVar(&add, "add", "", "Labels to add (comma-separated, name or ID)")
+	cmd.Flags().StringVar(&remove, "remove", "", "Labels to remove (comma-separated, name or ID)")
+	cmd.Flags().IntVar(&batchSize, "batch-size", gmailBatchModifyMax, "Message IDs per batchModify call (max 1000)")
+	cmd.Flags().DurationVar(&delay, "delay", 250*time.Millisecond, "Pause between batchModify calls")
+	cmd.Flags().Int64Var(&max, "max", 0, "Stop after this many messages (0 = all)")
+	cmd.Flags().BoolVar(&includeSpamTrash, "include-spam-trash", false, "Also match messages in Spam and Trash")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only count matching messages")
+	return cmd
+}
+
+// lookupLabelIDs resolves label names or IDs, failing on unknown labels
+// rather than sending them to the API.
+func lookupLabelIDs(values []string, nameToID map[string]string) ([]string, error) {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		id, ok := nameToID[strings.ToLower(strings.TrimSpace(v))]
+		if !ok {
+			return nil, usagef("label %q not found", v)
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// applyLabelsByQuery pages through the messages matching query and hands
+// them to apply in chunks of batchSize.
+func applyLabelsByQuery(ctx context.Context, svc *gmail.Service, query string, includeSpamTrash bool, max int64, batchSize int, apply func([]string) error) error {
+	var pending []string
+	seen := int64(0)
+	pageToken := ""
+	for {
+		call := svc.Users.Messages.List("me").Q(query).IncludeSpamTrash(includeSpamTrash).MaxResults(500).Fields("messages(id),nextPageToken").Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return err
+		}
+		for _, msg := range resp.Messages {
+			if msg == nil || msg.Id == "" {
+				continue
+			}
+			if max > 0 && seen >= max {
+				break
+			}
+			seen++
+			pending = append(pending, msg.Id)
+			if len(pending) == batchSize {
+				if err := apply(pending); err != nil {
+					return err
+				}
+				pending = nil
+			}
+		}
+		if resp.NextPageToken == "" || (max > 0 && seen >= max) {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	if len(pending) > 0 {
+		return apply(pending)
+	}
+	return nil
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
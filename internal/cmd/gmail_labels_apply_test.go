@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_GmailLabelsApply(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var batches []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX"}, {"id": "Label_3", "name": "Receipts"}}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages"):
+			if r.URL.Query().Get("q") != "from:billing" {
+				t.Errorf("q = %q", r.URL.Query().Get("q"))
+			}
+			if r.URL.Query().Get("pageToken") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1"}, {"id": "m2"}, {"id": "m3"}}, "nextPageToken": "p2"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m4"}, {"id": "m5"}}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/messages/batchModify"):
+			b, _ := io.ReadAll(r.Body)
+			batches = append(batches, string(b))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	var out string
+	stderr := captureStderr(t, func() {
+		out = captureStdout(t, func() {
+			if err := Execute([]string{"--json", "--yes", "--account", "a@b.com", "gmail", "labels", "apply",
+				"--query", "from:billing", "--add", "receipts", "--remove", "INBOX", "--batch-size", "2", "--delay", "0"}); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+		})
+	})
+	var res labelApplyResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil || res.Matched != 5 || res.Modified != 5 || res.Batches != 3 {
+		t.Fatalf("res = %+v err=%v out=%q", res, err, out)
+	}
+	if len(batches) != 3 || !strings.Contains(batches[0], `"ids":["m1","m2"]`) || !strings.Contains(batches[1], `"ids":["m3","m4"]`) ||
+		!strings.Contains(batches[2], `"ids":["m5"]`) || !strings.Contains(batches[0], `"addLabelIds":["Label_3"]`) ||
+		!strings.Contains(batches[0], `"removeLabelIds":["INBOX"]`) {
+		t.Fatalf("batches = %v", batches)
+	}
+	if !strings.Contains(stderr, "labeled 5 (batch 3)") {
+		t.Fatalf("progress = %q", stderr)
+	}
+
+	batches = nil
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "labels", "apply", "--query", "from:billing", "--add", "Receipts", "--max", "4", "--dry-run"}); err != nil {
+			t.Fatalf("dry run: %v", err)
+		}
+	})
+	if len(batches) != 0 {
+		t.Fatalf("dry run modified mail: %v", batches)
+	}
+
+	if err := Execute([]string{"--yes", "--account", "a@b.com", "gmail", "labels", "apply", "--query", "from:billing", "--add", "Nope"}); err == nil || !strings.Contains(err.Error(), `"Nope" not found`) {
+		t.Fatalf("unknown label: %v", err)
+	}
+}
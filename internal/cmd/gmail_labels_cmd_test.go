@@ -320,7 +320,7 @@ func TestFetchLabelIDToName(t *testing.T) {
 		t.Fatalf("NewService: %v", err)
 	}
 
-	m, err := fetchLabelIDToName(svc)
+	m, err := fetchLabelIDToName(context.Background(), svc)
 	if err != nil {
 		t.Fatalf("fetchLabelIDToName: %v", err)
 	}
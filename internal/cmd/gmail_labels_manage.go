@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+var labelColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// findLabel matches a label by ID or (case-insensitive) name.
+func findLabel(labels []*gmail.Label, raw string) *gmail.Label {
+	raw = strings.Trim(strings.TrimSpace(raw), "/")
+	for _, l := range labels {
+		if l != nil && (l.Id == raw || strings.EqualFold(l.Name, raw)) {
+			return l
+		}
+	}
+	return nil
+}
+
+// labelChildren returns the labels nested under parent, deepest first.
+func labelChildren(labels []*gmail.Label, parent string) []*gmail.Label {
+	prefix := strings.ToLower(parent) + "/"
+	var out []*gmail.Label
+	for _, l := range labels {
+		if l != nil && strings.HasPrefix(strings.ToLower(l.Name), prefix) {
+			out = append(out, l)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if di, dj := strings.Count(out[i].Name, "/"), strings.Count(out[j].Name, "/"); di != dj {
+			return di > dj
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// missingLabelParents lists the parents of path (Parent, Parent/Child, ...)
+// that do not exist yet, outermost first.
+func missingLabelParents(labels []*gmail.Label, path string) []string {
+	var out []string
+	parts := strings.Split(path, "/")
+	for i := 1; i < len(parts); i++ {
+		parent := strings.Join(parts[:i], "/")
+		if findLabel(labels, parent) == nil {
+			out = append(out, parent)
+		}
+	}
+	return out
+}
+
+func parseLabelListVisibility(v string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "show":
+		return "labelShow", nil
+	case "hide":
+		return "labelHide", nil
+	case "unread", "show-if-unread":
+		return "labelShowIfUnread", nil
+	default:
+		return "", usagef("invalid --label-list %q (expected show|hide|show-if-unread)", v)
+	}
+}
+
+func parseMessageListVisibility(v string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "show":
+		return "show", nil
+	case "hide":
+		return "hide", nil
+	default:
+		return "", usagef("invalid --message-list %q (expected show|hide)", v)
+	}
+}
+
+func labelColor(bg, text string) (*gmail.LabelColor, error) {
+	bg, text = strings.TrimSpace(bg), strings.TrimSpace(text)
+	if bg == "" && text == "" {
+		return nil, nil
+	}
+	if bg == "" || text == "" {
+		return nil, usage("--bg and --text must be set together")
+	}
+	for _, c := range []string{bg, text} {
+		if !labelColorRe.MatchString(c) {
+			return nil, usagef("invalid color %q (expected #rrggbb from Gmail's label palette)", c)
+		}
+	}
+	return &gmail.LabelColor{BackgroundColor: strings.ToLower(bg), TextColor: strings.ToLower(text)}, nil
+}
+
+func createLabel(cmd *cobra.Command, svc *gmail.Service, l *gmail.Label) (*gmail.Label, error) {
+	created, err := svc.Users.Labels.Create("me", l).Context(cmd.Context()).Do()
+	if err != nil {
+		return nil, fmt.Errorf("create label %q: %w", l.Name, err)
+	}
+	gmailLabels.forget(svc)
+	return created, nil
+}
+
+func newGmailLabelsCreateCmd(flags *rootFlags) *cobra.Command {
+	var labelList, messageList, bg, text string
+
+	cmd := &cobra.Command{
+		Use:   "create <Parent/Child>",
+		Short: "Create a label (missing parents are created too)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			name := strings.Trim(strings.TrimSpace(args[0]), "/")
+			if name == "" {
+				return usage("empty label name")
+			}
+			listVis, err := parseLabelListVisibility(labelList)
+			if err != nil {
+				return err
+			}
+			msgVis, err := parseMessageListVisibility(messageList)
+			if err != nil {
+				return err
+			}
+			color, err := labelColor(bg, text)
+			if err != nil {
+				return err
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			resp, err := svc.Users.Labels.List("me").Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			if existing := findLabel(resp.Labels, name); existing != nil {
+				return usagef("label %q already exists (%s)", existing.Name, existing.Id)
+			}
+
+			parents := missingLabelParents(resp.Labels, name)
+			for _, p := range parents {
+				if _, err := createLabel(cmd, svc, &gmail.Label{Name: p, LabelListVisibility: "labelShow", MessageListVisibility: "show"}); err != nil {
+					return err
+				}
+			}
+			label, err := createLabel(cmd, svc, &gmail.Label{
+				Name:                  name,
+				LabelListVisibility:   listVis,
+				MessageListVisibility: msgVis,
+				Color:                 color,
+			})
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"label": label, "createdParents": parents})
+			}
+			for _, p := range parents {
+				u.Err().Printf("Created parent %s", p)
+			}
+			u.Out().Printf("id\t%s", label.Id)
+			u.Out().Printf("name\t%s", label.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&labelList, "label-list", "show", "Visibility in the label list: show|hide|show-if-unread")
+	cmd.Flags().StringVar(&messageList, "message-list", "show", "Visibility in the message list: show|hide")
+	cmd.Flags().StringVar(&bg, "bg", "", "Background color (#rrggbb from Gmail's palette)")
+	cmd.Flags().StringVar(&text, "text", "", "Text color (#rrggbb from Gmail's palette)")
+	return cmd
+}
+
+func newGmailLabelsRenameCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <label> <New/Name>",
+		Short: "Rename a label; nested labels keep their place under it",
+		Long: `Rename a label by name or ID. Its nested labels are renamed along with it
+and missing parents of the new name are created. Fails when the new name
+already exists; use "gog gmail labels move" to merge labels.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			resp, err := svc.Users.Labels.List("me").Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			src := findLabel(resp.Labels, args[0])
+			if src == nil {
+				return usagef("label %q not found", args[0])
+			}
+			moves, created, _, err := planLabelMove(resp.Labels, src.Name, args[1], true)
+			if err != nil {
+				return err
+			}
+			for _, m := range moves {
+				if m.Action == "merge" {
+					return usagef("label %q already exists (use gog gmail labels move to merge)", m.To)
+				}
+			}
+
+			defer gmailLabels.forget(svc)
+			for _, name := range created {
+				if _, err := createLabel(cmd, svc, &gmail.Label{Name: name, LabelListVisibility: "labelShow", MessageListVisibility: "show"}); err != nil {
+					return err
+				}
+			}
+			for i := range moves {
+				if err := applyLabelMove(cmd.Context(), svc, &moves[i]); err != nil {
+					return fmt.Errorf("%s -> %s: %w", moves[i].From, moves[i].To, err)
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"renamed": moves, "created": created})
+			}
+			for _, m := range moves {
+				u.Out().Printf("%s\t%s", m.From, m.To)
+			}
+			return nil
+		},
+	}
+}
+
+func newGmailLabelsDeleteCmd(flags *rootFlags) *cobra.Command {
+	var recursive bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <label>",
+		Short: "Delete a label (messages keep their other labels)",
+		Long: `Delete a user label by name or ID. Messages are not deleted.
+
+A label with nested labels is only deleted with --recursive, which removes
+the nested labels too (deepest first). Asks for confirmation unless --force.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			resp, err := svc.Users.Labels.List("me").Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			label := findLabel(resp.Labels, args[0])
+			if label == nil {
+				return usagef("label %q not found", args[0])
+			}
+			if label.Type == "system" {
+				return usagef("cannot delete system label %s", label.Name)
+			}
+			children := labelChildren(resp.Labels, label.Name)
+			if len(children) > 0 && !recursive {
+				return usagef("label %s has %d nested label(s); use --recursive to delete them too", label.Name, len(children))
+			}
+
+			targets := append(children, label)
+			action := fmt.Sprintf("delete label %s", label.Name)
+			if len(children) > 0 {
+				action = fmt.Sprintf("delete label %s and %d nested label(s)", label.Name, len(children))
+			}
+			if err := confirmDestructive(cmd, flags, action); err != nil {
+				return err
+			}
+
+			deleted := make([]string, 0, len(targets))
+			defer gmailLabels.forget(svc)
+			for _, l := range targets {
+				if err := svc.Users.Labels.Delete("me", l.Id).Context(cmd.Context()).Do(); err != nil {
+					return fmt.Errorf("delete label %q: %w", l.Name, err)
+				}
+				deleted = append(deleted, l.Name)
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"deleted": deleted})
+			}
+			for _, name := range deleted {
+				u.Out().Printf("deleted\t%s", name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&recursive, "recursive", false, "Also delete nested labels")
+	return cmd
+}
+
+func newGmailLabelsColorCmd(flags *rootFlags) *cobra.Command {
+	var bg, text string
+
+	cmd := &cobra.Command{
+		Use:   "color <label> --bg #rrggbb --text #rrggbb",
+		Short: "Set a label's colors",
+		Long: `Set a label's background and text colors. Gmail only accepts colors from
+its label palette (e.g. #fb4c2f on #ffffff); others are rejected by the API.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			color, err := labelColor(bg, text)
+			if err != nil {
+				return err
+			}
+			if color == nil {
+				return usage("--bg and --text are required")
+			}
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			resp, err := svc.Users.Labels.List("me").Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			label := findLabel(resp.Labels, args[0])
+			if label == nil {
+				return usagef("label %q not found", args[0])
+			}
+			defer gmailLabels.forget(svc)
+			updated, err := svc.Users.Labels.Patch("me", label.Id, &gmail.Label{Color: color}).Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"label": updated})
+			}
+			u.Out().Printf("id\t%s", updated.Id)
+			u.Out().Printf("name\t%s", updated.Name)
+			u.Out().Printf("color\t%s on %s", color.TextColor, color.BackgroundColor)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&bg, "bg", "", "Background color (#rrggbb)")
+	cmd.Flags().StringVar(&text, "text", "", "Text color (#rrggbb)")
+	return cmd
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestLabelHierarchyHelpers(t *testing.T) {
+	labels := testMoveLabels()
+	if got := missingLabelParents(labels, "Clients/Beta/Q1"); !reflect.DeepEqual(got, []string{"Clients/Beta"}) {
+		t.Fatalf("unexpected parents %v", got)
+	}
+	var names []string
+	for _, l := range labelChildren(labels, "clients") {
+		names = append(names, l.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Clients/Acme/Invoices", "Clients/Acme"}) {
+		t.Fatalf("unexpected children %v", names)
+	}
+	if _, err := labelColor("#fb4c2f", ""); err == nil {
+		t.Fatalf("expected error for half a color")
+	}
+}
+
+func TestExecute_GmailLabelsCreateAndDelete(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var created []gmail.Label
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": testMoveLabels()})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			var l gmail.Label
+			_ = json.NewDecoder(r.Body).Decode(&l)
+			created = append(created, l)
+			l.Id = "NEW"
+			_ = json.NewEncoder(w).Encode(l)
+		case r.Method == http.MethodDelete && strings.Contains(r.URL.Path, "/users/me/labels/"):
+			deleted = append(deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "labels", "create", "Projects/2026", "--label-list", "hide", "--bg", "#FB4C2F", "--text", "#ffffff"}); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+		})
+	})
+	if len(created) != 2 || created[0].Name != "Projects" || created[1].Name != "Projects/2026" {
+		t.Fatalf("unexpected creates %#v", created)
+	}
+	if created[1].LabelListVisibility != "labelHide" || created[1].Color == nil || created[1].Color.BackgroundColor != "#fb4c2f" {
+		t.Fatalf("unexpected label %#v", created[1])
+	}
+
+	if err := Execute([]string{"--force", "--account", "a@b.com", "gmail", "labels", "delete", "Clients"}); err == nil || !strings.Contains(err.Error(), "--recursive") {
+		t.Fatalf("expected --recursive error, got %v", err)
+	}
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--force", "--account", "a@b.com", "gmail", "labels", "delete", "Clients", "--recursive"}); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+	})
+	if !reflect.DeepEqual(deleted, []string{"L3", "L2", "L1"}) {
+		t.Fatalf("unexpected deletes %v", deleted)
+	}
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+// gmailBatchModifyMax is the users.messages.batchModify ID limit.
+const gmailBatchModifyMax = 1000
+
+// labelMove is one step of a labels move: rename the label in place, or merge
+// its messages into an existing label with the target name and delete it.
+type labelMove struct {
+	ID       string `json:"id"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Action   string `json:"action"`
+	TargetID string `json:"targetId,omitempty"`
+	Messages int    `json:"messages,omitempty"`
+}
+
+func newGmailLabelsMoveCmd(flags *rootFlags) *cobra.Command {
+	var includeChildren bool
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "move <old/path> <new/path>",
+		Short: "Rename a label (and optionally its sub-labels)",
+		Long: `Rename a user label to a new path. With --include-children every nested
+label (old/path/...) moves along, keeping its relative path.
+
+Gmail nests labels by name only, so missing parents of the new path are
+created. When a label with the target name already exists, the source label's
+messages are relabeled onto it and the source label is deleted (asks for
+confirmation unless --force).`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			resp, err := svc.Users.Labels.List("me").Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			moves, created, skipped, err := planLabelMove(resp.Labels, args[0], args[1], includeChildren)
+			if err != nil {
+				return err
+			}
+			if len(skipped) > 0 {
+				u.Err().Printf("WARN: %d sub-label(s) stay under %s (use --include-children to move them)", len(skipped), strings.Trim(args[0], "/ "))
+			}
+
+			merges := 0
+			for _, m := range moves {
+				if m.Action == "merge" {
+					merges++
+				}
+			}
+			if !dryRun && merges > 0 {
+				if err := confirmDestructive(cmd, flags, fmt.Sprintf("merge %d label(s) into existing labels and delete the source labels", merges)); err != nil {
+					return err
+				}
+			}
+
+			if !dryRun {
+				defer gmailLabels.forget(svc)
+				for _, name := range created {
+					if _, err := svc.Users.Labels.Create("me", &gmail.Label{
+						Name:                  name,
+						LabelListVisibility:   "labelShow",
+						MessageListVisibility: "show",
+					}).Context(cmd.Context()).Do(); err != nil {
+						return fmt.Errorf("create parent label %q: %w", name, err)
+					}
+				}
+				for i := range moves {
+					if err := applyLabelMove(cmd.Context(), svc, &moves[i]); err != nil {
+						return fmt.Errorf("%s -> %s: %w", moves[i].From, moves[i].To, err)
+					}
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"dryRun":  dryRun,
+					"moves":   moves,
+					"created": created,
+				})
+			}
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "FROM\tTO\tACTION\tMESSAGES")
+			for _, name := range created {
+				fmt.Fprintf(w, "\t%s\tcreate\t\n", name)
+			}
+			for _, m := range moves {
+				msgs := ""
+				if m.Action == "merge" && !dryRun {
+					msgs = fmt.Sprintf("%d", m.Messages)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.From, m.To, m.Action, msgs)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&includeChildren, "include-children", false, "Also move nested labels (old/path/...)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the plan without changing labels")
+	return cmd
+}
+
+// planLabelMove works out the renames/merges for moving oldPath to newPath,
+// the missing parent labels to create, and the sub-labels left behind.
+func planLabelMove(labels []*gmail.Label, oldPath, newPath string, includeChildren bool) (moves []labelMove, created []string, skipped []string, err error) {
+	oldPath = strings.Trim(strings.TrimSpace(oldPath), "/")
+	newPath = strings.Trim(strings.TrimSpace(newPath), "/")
+	if oldPath == "" || newPath == "" {
+		return nil, nil, nil, usage("label paths must not be empty")
+	}
+	oldKey, newKey := strings.ToLower(oldPath), strings.ToLower(newPath)
+	if oldKey == newKey {
+		return nil, nil, nil, usage("old and new label paths are the same")
+	}
+	if strings.HasPrefix(newKey, oldKey+"/") {
+		return nil, nil, nil, usagef("cannot move %s inside itself", oldPath)
+	}
+
+	byName := make(map[string]*gmail.Label, len(labels))
+	for _, l := range labels {
+		if l != nil && l.Name != "" {
+			byName[strings.ToLower(l.Name)] = l
+		}
+	}
+	src, ok := byName[oldKey]
+	if !ok {
+		return nil, nil, nil, usagef("label %q not found", oldPath)
+	}
+	if src.Type == "system" {
+		return nil, nil, nil, usagef("cannot move system label %s", src.Name)
+	}
+
+	sources := []*gmail.Label{src}
+	for _, l := range labels {
+		if l == nil || !strings.HasPrefix(strings.ToLower(l.Name), oldKey+"/") {
+			continue
+		}
+		if includeChildren {
+			sources = append(sources, l)
+		} else {
+			skipped = append(skipped, l.Name)
+		}
+	}
+	sort.SliceStable(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+
+	targets := map[string]bool{}
+	for _, l := range sources {
+		to := newPath + l.Name[len(oldPath):]
+		m := labelMove{ID: l.Id, From: l.Name, To: to, Action: "rename"}
+		if existing, ok := byName[strings.ToLower(to)]; ok && existing.Id != l.Id {
+			m.Action = "merge"
+			m.TargetID = existing.Id
+		}
+		targets[strings.ToLower(to)] = true
+		moves = append(moves, m)
+	}
+
+	// Parents of the new path that neither exist nor result from the move.
+	parts := strings.Split(newPath, "/")
+	for i := 1; i < len(parts); i++ {
+		parent := strings.Join(parts[:i], "/")
+		key := strings.ToLower(parent)
+		if _, ok := byName[key]; ok || targets[key] {
+			continue
+		}
+		created = append(created, parent)
+	}
+	return moves, created, skipped, nil
+}
+
+func applyLabelMove(ctx context.Context, svc *gmail.Service, m *labelMove) error {
+	if m.Action == "rename" {
+		_, err := svc.Users.Labels.Patch("me", m.ID, &gmail.Label{Name: m.To}).Context(ctx).Do()
+		return err
+	}
+
+	ids, err := listLabelMessageIDs(ctx, svc, m.ID, "")
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(ids); start += gmailBatchModifyMax {
+		end := min(start+gmailBatchModifyMax, len(ids))
+		if err := svc.Users.Messages.BatchModify("me", &gmail.BatchModifyMessagesRequest{
+			Ids:            ids[start:end],
+			AddLabelIds:    []string{m.TargetID},
+			RemoveLabelIds: []string{m.ID},
+		}).Context(ctx).Do(); err != nil {
+			return err
+		}
+	}
+	m.Messages = len(ids)
+	return svc.Users.Labels.Delete("me", m.ID).Context(ctx).Do()
+}
+
+// listLabelMessageIDs returns the IDs of every message with labelID that
+// matches query (all of them when query is empty).
+func listLabelMessageIDs(ctx context.Context, svc *gmail.Service, labelID, query string) ([]string, error) {
+	var ids []string
+	pageToken := ""
+	for {
+		call := svc.Users.Messages.List("me").LabelIds(labelID).IncludeSpamTrash(true).MaxResults(500).Context(ctx)
+		if query != "" {
+			call = call.Q(query)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range resp.Messages {
+			if msg != nil && msg.Id != "" {
+				ids = append(ids, msg.Id)
+			}
+		}
+		if resp.NextPageToken == "" {
+			return ids, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func testMoveLabels() []*gmail.Label {
+	return []*gmail.Label{
+		{Id: "INBOX", Name: "INBOX", Type: "system"},
+		{Id: "L1", Name: "Clients", Type: "user"},
+		{Id: "L2", Name: "Clients/Acme", Type: "user"},
+		{Id: "L3", Name: "Clients/Acme/Invoices", Type: "user"},
+		{Id: "L4", Name: "Archive/Acme/Invoices", Type: "user"},
+		{Id: "L5", Name: "Archive", Type: "user"},
+	}
+}
+
+func TestPlanLabelMove(t *testing.T) {
+	moves, created, skipped, err := planLabelMove(testMoveLabels(), "clients/acme", "Archive/2024/Acme", true)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	want := []labelMove{
+		{ID: "L2", From: "Clients/Acme", To: "Archive/2024/Acme", Action: "rename"},
+		{ID: "L3", From: "Clients/Acme/Invoices", To: "Archive/2024/Acme/Invoices", Action: "rename"},
+	}
+	if !reflect.DeepEqual(moves, want) || len(skipped) != 0 {
+		t.Fatalf("unexpected moves %#v skipped %v", moves, skipped)
+	}
+	if !reflect.DeepEqual(created, []string{"Archive/2024"}) {
+		t.Fatalf("unexpected created %v", created)
+	}
+
+	moves, created, skipped, err = planLabelMove(testMoveLabels(), "Clients/Acme", "Archive/Acme", false)
+	if err != nil || len(moves) != 1 || len(created) != 0 || !reflect.DeepEqual(skipped, []string{"Clients/Acme/Invoices"}) {
+		t.Fatalf("unexpected plan without children: %#v %v %v %v", moves, created, skipped, err)
+	}
+
+	for _, tc := range [][2]string{{"INBOX", "Old"}, {"Missing", "X"}, {"Clients", "Clients/Sub"}, {"Clients", "clients"}} {
+		if _, _, _, err := planLabelMove(testMoveLabels(), tc[0], tc[1], true); err == nil {
+			t.Fatalf("%v: expected error", tc)
+		}
+	}
+}
+
+func TestExecute_GmailLabelsMove_Merge(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var patched, batch, deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": testMoveLabels()})
+		case r.Method == http.MethodPatch && strings.Contains(r.URL.Path, "/users/me/labels/"):
+			var l gmail.Label
+			_ = json.NewDecoder(r.Body).Decode(&l)
+			patched = append(patched, l.Name)
+			_ = json.NewEncoder(w).Encode(l)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages"):
+			if r.URL.Query().Get("labelIds") != "L3" {
+				t.Fatalf("unexpected label list %q", r.URL.RawQuery)
+			}
+			if r.URL.Query().Get("pageToken") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1"}}, "nextPageToken": "p2"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m2"}}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/messages/batchModify"):
+			b, _ := io.ReadAll(r.Body)
+			batch = append(batch, string(b))
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodDelete && strings.Contains(r.URL.Path, "/users/me/labels/"):
+			deleted = append(deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--force", "--account", "a@b.com", "gmail", "labels", "move", "Clients/Acme", "Archive/Acme", "--include-children"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !reflect.DeepEqual(patched, []string{"Archive/Acme"}) {
+		t.Fatalf("unexpected renames %v", patched)
+	}
+	if len(batch) != 1 || !strings.Contains(batch[0], `"ids":["m1","m2"]`) || !strings.Contains(batch[0], `"addLabelIds":["L4"]`) || !strings.Contains(batch[0], `"removeLabelIds":["L3"]`) {
+		t.Fatalf("unexpected batchModify %v", batch)
+	}
+	if !reflect.DeepEqual(deleted, []string{"L3"}) {
+		t.Fatalf("unexpected deletes %v", deleted)
+	}
+	var parsed struct {
+		Moves []labelMove `json:"moves"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil || len(parsed.Moves) != 2 || parsed.Moves[1].Messages != 2 {
+		t.Fatalf("unexpected out %q err=%v", out, err)
+	}
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// localSearchFields are the field prefixes gmail search --local accepts
+// (from:alice subject:invoice); bare terms match any field.
+var localSearchFields = []string{"from", "to", "subject", "body", "label"}
+
+type localSearchMode int
+
+const (
+	localSearchTerms localSearchMode = iota
+	localSearchFuzzy
+	localSearchRegex
+)
+
+// localIndex is an inverted index over a gmail sync cache. Postings are
+// keyed by "field:term"; the vocabulary backs fuzzy lookups.
+type localIndex struct {
+	postings map[string]map[string]struct{}
+	vocab    map[string][]string // field -> distinct terms
+}
+
+func buildLocalIndex(cache *gmailCache) *localIndex {
+	idx := &localIndex{
+		postings: map[string]map[string]struct{}{},
+		vocab:    map[string][]string{},
+	}
+	for id, m := range cache.Messages {
+		for field, text := range localMessageFields(cache, m) {
+			for _, term := range localTerms(text) {
+				key := field + ":" + term
+				ids, ok := idx.postings[key]
+				if !ok {
+					ids = map[string]struct{}{}
+					idx.postings[key] = ids
+					idx.vocab[field] = append(idx.vocab[field], term)
+				}
+				ids[id] = struct{}{}
+			}
+		}
+	}
+	return idx
+}
+
+// localMessageFields is the searchable text of m by field. The snippet
+// counts as body so caches synced without --bodies still match it.
+func localMessageFields(cache *gmailCache, m *cachedMessage) map[string]string {
+	labels := make([]string, 0, len(m.LabelIDs))
+	for _, id := range m.LabelIDs {
+		labels = append(labels, id)
+		if name, ok := cache.LabelNames[id]; ok && name != id {
+			labels = append(labels, name)
+		}
+	}
+	return map[string]string{
+		"from":    m.From,
+		"to":      m.To + " " + m.Cc,
+		"subject": m.Subject,
+		"body":    m.Snippet + " " + m.Body,
+		"label":   strings.Join(labels, " "),
+	}
+}
+
+// localTerms lowercases text and splits it into letter/digit runs.
+func localTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+type localQueryTerm struct {
+	field string // "" for any field
+	term  string
+}
+
+func parseLocalQuery(query string) ([]localQueryTerm, error) {
+	var out []localQueryTerm
+	for _, word := range strings.Fields(query) {
+		field := ""
+		if i := strings.Index(word, ":"); i > 0 {
+			f := strings.ToLower(word[:i])
+			known := false
+			for _, lf := range localSearchFields {
+				if f == lf {
+					known = true
+				}
+			}
+			if !known {
+				return nil, usagef("unknown field %q in local query (expected %s)", f, strings.Join(localSearchFields, "|"))
+			}
+			field, word = f, word[i+1:]
+		}
+		for _, term := range localTerms(word) {
+			out = append(out, localQueryTerm{field: field, term: term})
+		}
+	}
+	if len(out) == 0 {
+		return nil, usage("empty local query")
+	}
+	return out, nil
+}
+
+// match returns the IDs of messages containing every term.
+func (idx *localIndex) match(terms []localQueryTerm, fuzzy bool) map[string]struct{} {
+	var result map[string]struct{}
+	for _, qt := range terms {
+		fields := localSearchFields
+		if qt.field != "" {
+			fields = []string{qt.field}
+		}
+		hits := map[string]struct{}{}
+		for _, field := range fields {
+			words := []string{qt.term}
+			if fuzzy {
+				words = fuzzyTerms(idx.vocab[field], qt.term)
+			}
+			for _, w := range words {
+				for id := range idx.postings[field+":"+w] {
+					hits[id] = struct{}{}
+				}
+			}
+		}
+		if result == nil {
+			result = hits
+			continue
+		}
+		for id := range result {
+			if _, ok := hits[id]; !ok {
+				delete(result, id)
+			}
+		}
+	}
+	return result
+}
+
+// fuzzyTerms returns vocabulary words within a small edit distance of term
+// (1, or 2 for terms of 6+ characters).
+func fuzzyTerms(vocab []string, term string) []string {
+	maxDist := 1
+	if len([]rune(term)) >= 6 {
+		maxDist = 2
+	}
+	n := len([]rune(term))
+	var out []string
+	for _, w := range vocab {
+		if d := len([]rune(w)) - n; d > maxDist || -d > maxDist {
+			continue
+		}
+		if editDistance(w, term) <= maxDist {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// localSearch returns matching cached messages, newest first.
+func localSearch(cache *gmailCache, query string, mode localSearchMode) ([]*cachedMessage, error) {
+	var ids map[string]struct{}
+	if mode == localSearchRegex {
+		re, err := regexp.Compile("(?i)" + query)
+		if err != nil {
+			return nil, usagef("invalid --regex query: %v", err)
+		}
+		ids = map[string]struct{}{}
+		for id, m := range cache.Messages {
+			for _, text := range localMessageFields(cache, m) {
+				if re.MatchString(text) {
+					ids[id] = struct{}{}
+					break
+				}
+			}
+		}
+	} else {
+		terms, err := parseLocalQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		ids = buildLocalIndex(cache).match(terms, mode == localSearchFuzzy)
+	}
+
+	out := make([]*cachedMessage, 0, len(ids))
+	for id := range ids {
+		out = append(out, cache.Messages[id])
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].InternalDate != out[j].InternalDate {
+			return out[i].InternalDate > out[j].InternalDate
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// runGmailLocalSearch answers gmail search --local from the sync cache
+// without calling the API. The thread grouping keeps the newest matching
+// message of each thread.
+func runGmailLocalSearch(ctx context.Context, account, query string, max int64, groupBy string, preview int, unreadOnly bool, mode localSearchMode) error {
+	cache, err := loadGmailCache(account)
+	if err != nil {
+		return err
+	}
+	if cache == nil {
+		return usage("no local cache for this account (run gog gmail sync first)")
+	}
+	if !cache.Bodies {
+		if u := ui.FromContext(ctx); u != nil {
+			u.Err().Println("Note: cache has no message bodies; matching snippets only (gmail sync --bodies)")
+		}
+	}
+
+	msgs, err := localSearch(cache, query, mode)
+	if err != nil {
+		return err
+	}
+	seenThreads := map[string]bool{}
+	items := make([]messageItem, 0, len(msgs))
+	for _, m := range msgs {
+		if unreadOnly && !hasLabel(m.LabelIDs, "UNREAD") {
+			continue
+		}
+		if groupBy == searchGroupThread {
+			if seenThreads[m.ThreadID] {
+				continue
+			}
+			seenThreads[m.ThreadID] = true
+		}
+		item := messageItem{
+			ID:       m.ID,
+			ThreadID: m.ThreadID,
+			Date:     formatGmailDate(m.Date),
+			From:     sanitizeTab(m.From),
+			Subject:  sanitizeTab(m.Subject),
+			Labels:   labelNames(m.LabelIDs, cache.LabelNames),
+			Category: categoryOf(m.LabelIDs),
+			Snippet:  m.Snippet,
+			Unread:   hasLabel(m.LabelIDs, "UNREAD"),
+			rfcID:    m.RFCMessageID,
+		}
+		if m.InternalDate > 0 {
+			item.internal = time.UnixMilli(m.InternalDate)
+		}
+		items = append(items, item)
+	}
+	items = dedupeMessages(items)
+	if groupBy == searchGroupThread || groupBy == searchGroupMessage {
+		if int64(len(items)) > max {
+			items = items[:max]
+		}
+		groupBy = searchGroupMessage
+	}
+	return writeMessageSearch(ctx, items, "", groupBy, preview)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testLocalCache() *gmailCache {
+	c := newGmailCache("a@b.com", "")
+	c.Bodies = true
+	c.LabelNames = map[string]string{"Label_1": "Receipts"}
+	c.Messages["m1"] = &cachedMessage{ID: "m1", ThreadID: "t1", InternalDate: 1000, From: "Alice <alice@example.com>", Subject: "Invoice for March", Body: "Total due: 42 EUR", LabelIDs: []string{"INBOX", "Label_1"}}
+	c.Messages["m2"] = &cachedMessage{ID: "m2", ThreadID: "t1", InternalDate: 2000, From: "Bob <bob@example.com>", Subject: "Re: Invoice for March", Body: "Paid, thanks", LabelIDs: []string{"INBOX", "UNREAD"}}
+	c.Messages["m3"] = &cachedMessage{ID: "m3", ThreadID: "t3", InternalDate: 3000, From: "Carol <carol@example.com>", Subject: "Lunch", Snippet: "tomorrow at noon?"}
+	return c
+}
+
+func localIDs(t *testing.T, c *gmailCache, query string, mode localSearchMode) string {
+	t.Helper()
+	msgs, err := localSearch(c, query, mode)
+	if err != nil {
+		t.Fatalf("localSearch(%q): %v", query, err)
+	}
+	ids := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		ids = append(ids, m.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestLocalSearch(t *testing.T) {
+	c := testLocalCache()
+	cases := []struct {
+		query string
+		mode  localSearchMode
+		want  string
+	}{
+		{"invoice", localSearchTerms, "m2,m1"},
+		{"invoice from:alice", localSearchTerms, "m1"},
+		{"label:receipts", localSearchTerms, "m1"},
+		{"body:paid", localSearchTerms, "m2"},
+		{"noon", localSearchTerms, "m3"},
+		{"invoise", localSearchTerms, ""},
+		{"invoise", localSearchFuzzy, "m2,m1"},
+		{`due: \d+ EUR`, localSearchRegex, "m1"},
+	}
+	for _, tc := range cases {
+		if got := localIDs(t, c, tc.query, tc.mode); got != tc.want {
+			t.Errorf("%q (mode %d) = %q, want %q", tc.query, tc.mode, got, tc.want)
+		}
+	}
+	if _, err := localSearch(c, "cc:bob", localSearchTerms); err == nil {
+		t.Fatalf("expected unknown field error")
+	}
+	if _, err := localSearch(c, "(", localSearchRegex); err == nil {
+		t.Fatalf("expected regex error")
+	}
+}
+
+func TestExecute_GmailSearchLocal(t *testing.T) {
+	t.Setenv("GOG_CACHE_DIR", t.TempDir())
+	c := testLocalCache()
+	path, err := gmailCachePath("a@b.com")
+	if err != nil {
+		t.Fatalf("cache path: %v", err)
+	}
+	c.path = path
+	if err := c.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "search", "--local", "invoice"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var parsed struct {
+		Messages []messageItem `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	// One row per thread: the newest match.
+	if len(parsed.Messages) != 1 || parsed.Messages[0].ID != "m2" || !parsed.Messages[0].Unread {
+		t.Fatalf("messages = %#v", parsed.Messages)
+	}
+
+	if err := Execute([]string{"--account", "a@b.com", "gmail", "search", "--regex", "x"}); err == nil {
+		t.Fatalf("expected --regex without --local to fail")
+	}
+}
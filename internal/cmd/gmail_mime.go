@@ -1,16 +1,19 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"mime"
 	"net/mail"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
@@ -20,6 +23,8 @@ type mailAttachment struct {
 	Filename string
 	MIMEType string
 	Data     []byte
+	// ContentID marks an inline image referenced from the HTML as cid:<ID>.
+	ContentID string
 }
 
 type mailOptions struct {
@@ -35,203 +40,318 @@ type mailOptions struct {
 	References        string
 	AdditionalHeaders map[string]string
 	Attachments       []mailAttachment
+	// Inline images go into a multipart/related part next to the HTML body.
+	Inline []mailAttachment
+	// MessageIDDomain overrides the From domain in the generated Message-ID.
+	MessageIDDomain string
+	// PGP, when set, signs and/or encrypts the content as PGP/MIME.
+	PGP *pgpOptions
 }
 
 func buildRFC822(opts mailOptions) ([]byte, error) {
+	var b bytes.Buffer
+	if err := writeRFC822(&b, opts); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// writeRFC822 streams the message to w. Attachment files are read and
+// base64-encoded in chunks, so their size doesn't bound memory.
+func writeRFC822(w io.Writer, opts mailOptions) error {
 	if strings.TrimSpace(opts.From) == "" {
-		return nil, errors.New("missing From")
+		return errors.New("missing From")
 	}
 	if len(opts.To) == 0 {
-		return nil, errors.New("missing To")
+		return errors.New("missing To")
 	}
 	if strings.TrimSpace(opts.Subject) == "" {
-		return nil, errors.New("missing Subject")
+		return errors.New("missing Subject")
 	}
 
-	var b bytes.Buffer
+	b := bufio.NewWriterSize(w, 64<<10)
 
 	if err := validateHeaderValue(opts.From); err != nil {
-		return nil, fmt.Errorf("invalid From: %w", err)
+		return fmt.Errorf("invalid From: %w", err)
 	}
 	for _, a := range append(append([]string{}, opts.To...), append(opts.Cc, opts.Bcc...)...) {
 		if err := validateHeaderValue(a); err != nil {
-			return nil, fmt.Errorf("invalid address: %w", err)
+			return fmt.Errorf("invalid address: %w", err)
 		}
 	}
 
-	writeHeader(&b, "From", opts.From)
-	writeHeader(&b, "To", strings.Join(opts.To, ", "))
+	writeHeader(b, "From", opts.From)
+	writeHeader(b, "To", strings.Join(opts.To, ", "))
 	if len(opts.Cc) > 0 {
-		writeHeader(&b, "Cc", strings.Join(opts.Cc, ", "))
+		writeHeader(b, "Cc", strings.Join(opts.Cc, ", "))
 	}
 	if len(opts.Bcc) > 0 {
-		writeHeader(&b, "Bcc", strings.Join(opts.Bcc, ", "))
+		writeHeader(b, "Bcc", strings.Join(opts.Bcc, ", "))
 	}
 	if strings.TrimSpace(opts.ReplyTo) != "" {
 		if err := validateHeaderValue(opts.ReplyTo); err != nil {
-			return nil, fmt.Errorf("invalid Reply-To: %w", err)
+			return fmt.Errorf("invalid Reply-To: %w", err)
 		}
-		writeHeader(&b, "Reply-To", strings.TrimSpace(opts.ReplyTo))
+		writeHeader(b, "Reply-To", strings.TrimSpace(opts.ReplyTo))
 	}
 	if err := validateHeaderValue(opts.Subject); err != nil {
-		return nil, fmt.Errorf("invalid Subject: %w", err)
+		return fmt.Errorf("invalid Subject: %w", err)
 	}
-	writeHeader(&b, "Subject", encodeHeaderIfNeeded(opts.Subject))
-	writeHeader(&b, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(b, "Subject", encodeHeaderIfNeeded(opts.Subject))
+	writeHeader(b, "Date", time.Now().Format(time.RFC1123Z))
 	if !hasHeader(opts.AdditionalHeaders, "Message-ID") && !hasHeader(opts.AdditionalHeaders, "Message-Id") {
-		messageID, err := randomMessageID(opts.From)
+		messageID, err := randomMessageID(opts.From, opts.MessageIDDomain)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		writeHeader(&b, "Message-ID", messageID)
+		writeHeader(b, "Message-ID", messageID)
 	}
-	writeHeader(&b, "MIME-Version", "1.0")
+	writeHeader(b, "MIME-Version", "1.0")
 	if strings.TrimSpace(opts.InReplyTo) != "" {
 		if err := validateHeaderValue(opts.InReplyTo); err != nil {
-			return nil, fmt.Errorf("invalid In-Reply-To: %w", err)
+			return fmt.Errorf("invalid In-Reply-To: %w", err)
 		}
-		writeHeader(&b, "In-Reply-To", strings.TrimSpace(opts.InReplyTo))
+		writeHeader(b, "In-Reply-To", strings.TrimSpace(opts.InReplyTo))
 	}
 	if strings.TrimSpace(opts.References) != "" {
 		if err := validateHeaderValue(opts.References); err != nil {
-			return nil, fmt.Errorf("invalid References: %w", err)
+			return fmt.Errorf("invalid References: %w", err)
 		}
-		writeHeader(&b, "References", strings.TrimSpace(opts.References))
+		writeHeader(b, "References", strings.TrimSpace(opts.References))
+	}
+	headerNames := make([]string, 0, len(opts.AdditionalHeaders))
+	for k := range opts.AdditionalHeaders {
+		headerNames = append(headerNames, k)
 	}
-	for k, v := range opts.AdditionalHeaders {
+	sort.Strings(headerNames)
+	for _, k := range headerNames {
+		v := opts.AdditionalHeaders[k]
 		if strings.TrimSpace(k) != "" && strings.TrimSpace(v) != "" {
 			if err := validateHeaderValue(v); err != nil {
-				return nil, fmt.Errorf("invalid header %s: %w", k, err)
+				return fmt.Errorf("invalid header %s: %w", k, err)
 			}
-			writeHeader(&b, k, v)
+			writeHeader(b, k, v)
+		}
+	}
+
+	if len(opts.Inline) > 0 && strings.TrimSpace(opts.BodyHTML) == "" {
+		return errors.New("inline images need an HTML body")
+	}
+
+	if opts.PGP != nil {
+		// PGP/MIME signs or encrypts the whole content entity, so it is
+		// built in memory first.
+		var entity bytes.Buffer
+		if err := writeContentEntity(&entity, opts); err != nil {
+			return err
+		}
+		if err := writePGPEntity(b, entity.Bytes(), opts.PGP); err != nil {
+			return err
 		}
+		return b.Flush()
 	}
+	if err := writeContentEntity(b, opts); err != nil {
+		return err
+	}
+	return b.Flush()
+}
 
+// writeContentEntity writes the Content-Type header, blank line and content:
+// the body entity, wrapped in multipart/mixed when there are attachments.
+func writeContentEntity(b mimeWriter, opts mailOptions) error {
 	plainBody := normalizeCRLF(opts.Body)
 	htmlBody := normalizeCRLF(opts.BodyHTML)
-	hasPlain := strings.TrimSpace(plainBody) != ""
-	hasHTML := strings.TrimSpace(htmlBody) != ""
 
 	if len(opts.Attachments) == 0 {
-		switch {
-		case hasPlain && hasHTML:
-			altBoundary, err := randomBoundary()
-			if err != nil {
-				return nil, err
-			}
-			writeHeader(&b, "Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", altBoundary))
-			b.WriteString("\r\n")
-
-			writeTextPart(&b, altBoundary, "text/plain; charset=\"utf-8\"", plainBody)
-			writeTextPart(&b, altBoundary, "text/html; charset=\"utf-8\"", htmlBody)
-			b.WriteString(fmt.Sprintf("--%s--\r\n", altBoundary))
-			return b.Bytes(), nil
-		case hasHTML && !hasPlain:
-			writeHeader(&b, "Content-Type", "text/html; charset=\"utf-8\"")
-			writeHeader(&b, "Content-Transfer-Encoding", "7bit")
-			b.WriteString("\r\n")
-			writeBodyWithTrailingCRLF(&b, htmlBody)
-			return b.Bytes(), nil
-		default:
-			writeHeader(&b, "Content-Type", "text/plain; charset=\"utf-8\"")
-			writeHeader(&b, "Content-Transfer-Encoding", "7bit")
-			b.WriteString("\r\n")
-			writeBodyWithTrailingCRLF(&b, plainBody)
-			return b.Bytes(), nil
-		}
+		return writeBodyEntity(b, plainBody, htmlBody, opts.Inline)
 	}
 
 	mixedBoundary, err := randomBoundary()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	writeHeader(&b, "Content-Type", fmt.Sprintf("multipart/mixed; boundary=%q", mixedBoundary))
+	writeHeader(b, "Content-Type", fmt.Sprintf("multipart/mixed; boundary=%q", mixedBoundary))
 	b.WriteString("\r\n")
 
 	// Body part
 	b.WriteString(fmt.Sprintf("--%s\r\n", mixedBoundary))
+	if err := writeBodyEntity(b, plainBody, htmlBody, opts.Inline); err != nil {
+		return err
+	}
+
+	// Attachments
+	for _, a := range opts.Attachments {
+		prepareAttachment(&a)
+
+		b.WriteString(fmt.Sprintf("\r\n--%s\r\n", mixedBoundary))
+		b.WriteString(fmt.Sprintf("Content-Type: %s\r\n", a.MIMEType))
+		b.WriteString("Content-Transfer-Encoding: base64\r\n")
+		b.WriteString(fmt.Sprintf("Content-Disposition: attachment; %s\r\n\r\n", contentDispositionFilename(a.Filename)))
+		if err := writeAttachmentData(b, a); err != nil {
+			return err
+		}
+		b.WriteString("\r\n")
+	}
+
+	b.WriteString(fmt.Sprintf("--%s--\r\n", mixedBoundary))
+	return nil
+}
+
+// writeBodyEntity writes the Content-Type header(s), blank line and content
+// of the message body: plain, HTML, or both as multipart/alternative. With
+// inline images the HTML becomes multipart/related with the images.
+func writeBodyEntity(b mimeWriter, plainBody, htmlBody string, inline []mailAttachment) error {
+	hasPlain := strings.TrimSpace(plainBody) != ""
+	hasHTML := strings.TrimSpace(htmlBody) != ""
+
 	switch {
 	case hasPlain && hasHTML:
 		altBoundary, err := randomBoundary()
 		if err != nil {
-			return nil, err
+			return err
 		}
 		b.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q\r\n\r\n", altBoundary))
-		writeTextPart(&b, altBoundary, "text/plain; charset=\"utf-8\"", plainBody)
-		writeTextPart(&b, altBoundary, "text/html; charset=\"utf-8\"", htmlBody)
+		writeTextPart(b, altBoundary, "text/plain; charset=\"utf-8\"", plainBody)
+		if len(inline) > 0 {
+			b.WriteString(fmt.Sprintf("--%s\r\n", altBoundary))
+			if err := writeRelatedHTML(b, htmlBody, inline); err != nil {
+				return err
+			}
+		} else {
+			writeTextPart(b, altBoundary, "text/html; charset=\"utf-8\"", htmlBody)
+		}
 		b.WriteString(fmt.Sprintf("--%s--\r\n", altBoundary))
-	case hasHTML && !hasPlain:
+	case hasHTML && len(inline) > 0:
+		return writeRelatedHTML(b, htmlBody, inline)
+	case hasHTML:
 		b.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
 		b.WriteString("Content-Transfer-Encoding: 7bit\r\n\r\n")
-		writeBodyWithTrailingCRLF(&b, htmlBody)
+		writeBodyWithTrailingCRLF(b, htmlBody)
 	default:
 		b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
 		b.WriteString("Content-Transfer-Encoding: 7bit\r\n\r\n")
-		writeBodyWithTrailingCRLF(&b, plainBody)
+		writeBodyWithTrailingCRLF(b, plainBody)
 	}
+	return nil
+}
 
-	// Attachments
-	for _, a := range opts.Attachments {
-		if a.Filename == "" {
-			a.Filename = filepath.Base(a.Path)
-		}
-		if a.MIMEType == "" {
-			a.MIMEType = mime.TypeByExtension(strings.ToLower(filepath.Ext(a.Filename)))
-			if a.MIMEType == "" {
-				a.MIMEType = "application/octet-stream"
-			}
+// writeRelatedHTML writes a multipart/related entity: the HTML followed by
+// the images it references by Content-ID.
+func writeRelatedHTML(b mimeWriter, htmlBody string, inline []mailAttachment) error {
+	relBoundary, err := randomBoundary()
+	if err != nil {
+		return err
+	}
+	b.WriteString(fmt.Sprintf("Content-Type: multipart/related; boundary=%q; type=\"text/html\"\r\n\r\n", relBoundary))
+	writeTextPart(b, relBoundary, "text/html; charset=\"utf-8\"", htmlBody)
+	for _, a := range inline {
+		prepareAttachment(&a)
+		cid := strings.Trim(strings.TrimSpace(a.ContentID), "<>")
+		if cid == "" {
+			cid = a.Filename
 		}
-		if len(a.Data) == 0 {
-			data, err := os.ReadFile(a.Path)
-			if err != nil {
-				return nil, err
-			}
-			a.Data = data
+		if err := validateHeaderValue(cid); err != nil {
+			return fmt.Errorf("invalid Content-ID: %w", err)
 		}
-
-		b.WriteString(fmt.Sprintf("\r\n--%s\r\n", mixedBoundary))
+		b.WriteString(fmt.Sprintf("--%s\r\n", relBoundary))
 		b.WriteString(fmt.Sprintf("Content-Type: %s\r\n", a.MIMEType))
 		b.WriteString("Content-Transfer-Encoding: base64\r\n")
-		b.WriteString(fmt.Sprintf("Content-Disposition: attachment; %s\r\n\r\n", contentDispositionFilename(a.Filename)))
-		b.WriteString(wrapBase64(a.Data))
+		b.WriteString(fmt.Sprintf("Content-ID: <%s>\r\n", cid))
+		b.WriteString(fmt.Sprintf("Content-Disposition: inline; %s\r\n\r\n", contentDispositionFilename(a.Filename)))
+		if err := writeAttachmentData(b, a); err != nil {
+			return err
+		}
 		b.WriteString("\r\n")
 	}
+	b.WriteString(fmt.Sprintf("--%s--\r\n", relBoundary))
+	return nil
+}
 
-	b.WriteString(fmt.Sprintf("--%s--\r\n", mixedBoundary))
-	return b.Bytes(), nil
+// mimeWriter is what the message writers need; *bytes.Buffer and
+// *bufio.Writer both qualify.
+type mimeWriter interface {
+	io.Writer
+	io.StringWriter
 }
 
-func writeHeader(b *bytes.Buffer, name, value string) {
-	b.WriteString(name)
-	b.WriteString(": ")
-	b.WriteString(value)
-	b.WriteString("\r\n")
+// prepareAttachment fills in a's filename and MIME type from its path.
+func prepareAttachment(a *mailAttachment) {
+	if a.Filename == "" {
+		a.Filename = filepath.Base(a.Path)
+	}
+	if a.MIMEType == "" {
+		a.MIMEType = mime.TypeByExtension(strings.ToLower(filepath.Ext(a.Filename)))
+		if a.MIMEType == "" {
+			a.MIMEType = "application/octet-stream"
+		}
+	}
 }
 
-func wrapBase64(b []byte) string {
-	s := base64.StdEncoding.EncodeToString(b)
-	const width = 76
-	var out strings.Builder
-	for len(s) > width {
-		out.WriteString(s[:width])
-		out.WriteString("\r\n")
-		s = s[width:]
+// writeAttachmentData base64-encodes a's data (or streams its file) in
+// 76-column lines, without a trailing line break.
+func writeAttachmentData(w io.Writer, a mailAttachment) error {
+	enc := base64.NewEncoder(base64.StdEncoding, &base64LineWriter{w: w})
+	if len(a.Data) > 0 {
+		if _, err := enc.Write(a.Data); err != nil {
+			return err
+		}
+		return enc.Close()
 	}
-	if len(s) > 0 {
-		out.WriteString(s)
+	f, err := os.Open(a.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(enc, f); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// base64LineWriter breaks base64 output into 76-column CRLF lines. The
+// break is written before the next byte, so output never ends in CRLF.
+type base64LineWriter struct {
+	w   io.Writer
+	col int
+}
+
+func (l *base64LineWriter) Write(p []byte) (int, error) {
+	const width = 76
+	written := 0
+	for len(p) > 0 {
+		if l.col == width {
+			if _, err := io.WriteString(l.w, "\r\n"); err != nil {
+				return written, err
+			}
+			l.col = 0
+		}
+		n := min(width-l.col, len(p))
+		if _, err := l.w.Write(p[:n]); err != nil {
+			return written, err
+		}
+		l.col += n
+		written += n
+		p = p[n:]
 	}
-	return out.String()
+	return written, nil
+}
+
+func writeHeader(b mimeWriter, name, value string) {
+	b.WriteString(name)
+	b.WriteString(": ")
+	b.WriteString(value)
+	b.WriteString("\r\n")
 }
 
-func writeBodyWithTrailingCRLF(b *bytes.Buffer, body string) {
+func writeBodyWithTrailingCRLF(b mimeWriter, body string) {
 	b.WriteString(body)
 	if !strings.HasSuffix(body, "\r\n") {
 		b.WriteString("\r\n")
 	}
 }
 
-func writeTextPart(b *bytes.Buffer, boundary string, contentType string, body string) {
+func writeTextPart(b mimeWriter, boundary string, contentType string, body string) {
 	_, _ = fmt.Fprintf(b, "--%s\r\n", boundary)
 	_, _ = fmt.Fprintf(b, "Content-Type: %s\r\n", contentType)
 	b.WriteString("Content-Transfer-Encoding: 7bit\r\n\r\n")
@@ -262,9 +382,11 @@ func hasHeader(headers map[string]string, name string) bool {
 	return false
 }
 
-func randomMessageID(from string) (string, error) {
+func randomMessageID(from string, domainOverride string) (string, error) {
 	domain := "gogcli.local"
-	if addr, err := mail.ParseAddress(strings.TrimSpace(from)); err == nil && addr != nil {
+	if d := strings.TrimSpace(domainOverride); d != "" {
+		domain = d
+	} else if addr, err := mail.ParseAddress(strings.TrimSpace(from)); err == nil && addr != nil {
 		if at := strings.LastIndex(addr.Address, "@"); at != -1 && at+1 < len(addr.Address) {
 			domain = strings.TrimSpace(addr.Address[at+1:])
 		}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+	gapi "google.golang.org/api/googleapi"
+)
+
+const (
+	// gmailMediaUploadThreshold is the encoded size above which a message is
+	// spooled to a temp file and sent through the resumable upload endpoint
+	// instead of as a base64 "raw" field in the JSON request.
+	gmailMediaUploadThreshold = 5 << 20
+	// gmailUploadChunkSize must be a multiple of 256 KiB.
+	gmailUploadChunkSize = 4 << 20
+)
+
+// composedMessage is a built RFC822 message: in memory when small, spooled
+// to a temp file when large. Close removes the spool file.
+type composedMessage struct {
+	raw  []byte
+	path string
+	size int64
+}
+
+// composeRFC822 builds the message, streaming attachments into a temp file
+// when the estimated size is over gmailMediaUploadThreshold. A message
+// that can't fit Gmail's limit fails before anything is read.
+func composeRFC822(u *ui.UI, opts mailOptions) (*composedMessage, error) {
+	estimate := estimateRFC822Size(opts)
+	if estimate > gmailMaxMessageBytes {
+		return nil, checkGmailMessageBytes(u, estimate)
+	}
+	if estimate <= gmailMediaUploadThreshold {
+		raw, err := buildRFC822(opts)
+		if err != nil {
+			return nil, err
+		}
+		m := &composedMessage{raw: raw, size: int64(len(raw))}
+		return m, checkGmailMessageBytes(u, m.size)
+	}
+
+	f, err := os.CreateTemp("", "gog-message-*.eml")
+	if err != nil {
+		return nil, err
+	}
+	m := &composedMessage{path: f.Name()}
+	if err := writeRFC822(f, opts); err != nil {
+		_ = f.Close()
+		_ = m.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		_ = m.Close()
+		return nil, err
+	}
+	st, err := os.Stat(m.path)
+	if err != nil {
+		_ = m.Close()
+		return nil, err
+	}
+	m.size = st.Size()
+	if err := checkGmailMessageBytes(u, m.size); err != nil {
+		_ = m.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// estimateRFC822Size approximates the encoded size: bodies plus base64
+// attachments (4/3, plus a CRLF per 76 columns). Headers are ignored.
+func estimateRFC822Size(opts mailOptions) int64 {
+	size := int64(len(opts.Body) + len(opts.BodyHTML))
+	for _, a := range append(append([]mailAttachment{}, opts.Attachments...), opts.Inline...) {
+		n := int64(len(a.Data))
+		if n == 0 {
+			if st, err := os.Stat(a.Path); err == nil {
+				n = st.Size()
+			}
+		}
+		encoded := (n + 2) / 3 * 4
+		size += encoded + encoded/76*2
+	}
+	return size
+}
+
+func (m *composedMessage) Close() error {
+	if m == nil || m.path == "" {
+		return nil
+	}
+	err := os.Remove(m.path)
+	m.path = ""
+	return err
+}
+
+func (m *composedMessage) spooled() bool { return m.path != "" }
+
+func (m *composedMessage) open() (io.ReadCloser, error) {
+	if !m.spooled() {
+		return io.NopCloser(bytes.NewReader(m.raw)), nil
+	}
+	return os.Open(m.path)
+}
+
+// bytes returns the whole message; spooled messages are read back.
+func (m *composedMessage) bytes() ([]byte, error) {
+	if !m.spooled() {
+		return m.raw, nil
+	}
+	return os.ReadFile(m.path)
+}
+
+// header returns the header section, for the sent log.
+func (m *composedMessage) header() []byte {
+	if !m.spooled() {
+		return m.raw
+	}
+	f, err := os.Open(m.path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	buf := make([]byte, 64<<10)
+	n, _ := io.ReadFull(f, buf)
+	buf = buf[:n]
+	if i := bytes.Index(buf, []byte("\r\n\r\n")); i >= 0 {
+		return buf[:i+4]
+	}
+	return buf
+}
+
+// sendComposed sends m; spooled messages go through the upload endpoint
+// in resumable chunks.
+func sendComposed(ctx context.Context, svc *gmail.Service, m *composedMessage, threadID string) (*gmail.Message, error) {
+	if !m.spooled() {
+		msg := &gmail.Message{Raw: base64.RawURLEncoding.EncodeToString(m.raw), ThreadId: threadID}
+		return svc.Users.Messages.Send("me", msg).Context(ctx).Do()
+	}
+	f, err := m.open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return svc.Users.Messages.Send("me", &gmail.Message{ThreadId: threadID}).
+		Media(f, gapi.ContentType("message/rfc822"), gapi.ChunkSize(gmailUploadChunkSize)).
+		Context(ctx).
+		Do()
+}
+
+// createDraftComposed creates a draft from m, uploading spooled messages.
+func createDraftComposed(ctx context.Context, svc *gmail.Service, m *composedMessage, threadID string) (*gmail.Draft, error) {
+	if !m.spooled() {
+		msg := &gmail.Message{Raw: base64.RawURLEncoding.EncodeToString(m.raw), ThreadId: threadID}
+		return svc.Users.Drafts.Create("me", &gmail.Draft{Message: msg}).Context(ctx).Do()
+	}
+	f, err := m.open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return svc.Users.Drafts.Create("me", &gmail.Draft{Message: &gmail.Message{ThreadId: threadID}}).
+		Media(f, gapi.ContentType("message/rfc822"), gapi.ChunkSize(gmailUploadChunkSize)).
+		Context(ctx).
+		Do()
+}
+
+// updateDraftComposed replaces draft draftID's message with m.
+func updateDraftComposed(ctx context.Context, svc *gmail.Service, draftID string, m *composedMessage, threadID string) (*gmail.Draft, error) {
+	if !m.spooled() {
+		msg := &gmail.Message{Raw: base64.RawURLEncoding.EncodeToString(m.raw), ThreadId: threadID}
+		return svc.Users.Drafts.Update("me", draftID, &gmail.Draft{Id: draftID, Message: msg}).Context(ctx).Do()
+	}
+	f, err := m.open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return svc.Users.Drafts.Update("me", draftID, &gmail.Draft{Id: draftID, Message: &gmail.Message{ThreadId: threadID}}).
+		Media(f, gapi.ContentType("message/rfc822"), gapi.ChunkSize(gmailUploadChunkSize)).
+		Context(ctx).
+		Do()
+}
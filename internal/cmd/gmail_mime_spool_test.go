@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestWriteAttachmentDataWraps(t *testing.T) {
+	for _, n := range []int{1, 56, 57, 58, 114, 300} {
+		data := bytes.Repeat([]byte{0xfb}, n)
+		var got bytes.Buffer
+		if err := writeAttachmentData(&got, mailAttachment{Data: data}); err != nil {
+			t.Fatalf("n=%d: %v", n, err)
+		}
+		enc := base64.StdEncoding.EncodeToString(data)
+		var lines []string
+		for len(enc) > 76 {
+			lines, enc = append(lines, enc[:76]), enc[76:]
+		}
+		if enc != "" {
+			lines = append(lines, enc)
+		}
+		if want := strings.Join(lines, "\r\n"); got.String() != want {
+			t.Fatalf("n=%d: got %q, want %q", n, got.String(), want)
+		}
+	}
+}
+
+func TestComposeRFC822SpoolsAndUploads(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "big.bin")
+	if err := os.WriteFile(path, bytes.Repeat([]byte("x"), 5<<20), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := composeRFC822(nil, mailOptions{From: "a@b.com", To: []string{"c@d.com"}, Subject: "Big", Body: "see attached", Attachments: []mailAttachment{{Path: path}}})
+	if err != nil {
+		t.Fatalf("compose: %v", err)
+	}
+	defer m.Close()
+	if !m.spooled() || m.size < 5<<20 {
+		t.Fatalf("expected a spooled message, got spooled=%v size=%d", m.spooled(), m.size)
+	}
+	if !strings.Contains(string(m.header()), "Subject: Big\r\n") {
+		t.Fatalf("header = %q", m.header())
+	}
+
+	var uploaded bytes.Buffer
+	var initiated bool
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/upload/gmail/v1/users/me/messages/send"):
+			if r.URL.Query().Get("uploadType") != "resumable" {
+				t.Errorf("uploadType = %q", r.URL.Query().Get("uploadType"))
+			}
+			initiated = true
+			w.Header().Set("Location", srv.URL+"/session")
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/session":
+			_, _ = io.Copy(&uploaded, r.Body)
+			if !strings.HasSuffix(r.Header.Get("Content-Range"), "/*") {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1", "threadId": "t1"})
+				return
+			}
+			// Incomplete chunk: the client asks for 200 + this override instead of 308.
+			w.Header().Set("X-Http-Status-Code-Override", "308")
+			w.Header().Set("Range", "bytes=0-"+strconv.Itoa(uploaded.Len()-1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	sent, err := sendComposed(context.Background(), svc, m, "")
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if !initiated || sent.Id != "m1" || int64(uploaded.Len()) != m.size {
+		t.Fatalf("initiated=%v sent=%#v uploaded=%d size=%d", initiated, sent, uploaded.Len(), m.size)
+	}
+
+	spool := m.path
+	if err := m.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := os.Stat(spool); !os.IsNotExist(err) {
+		t.Fatalf("spool file not removed: %v", err)
+	}
+}
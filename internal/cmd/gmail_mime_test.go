@@ -1,6 +1,13 @@
 package cmd
 
 import (
+	"bytes"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -124,6 +131,106 @@ func TestBuildRFC822AlternativeWithAttachment(t *testing.T) {
 	}
 }
 
+func TestBuildRFC822InlineImages(t *testing.T) {
+	raw, err := buildRFC822(mailOptions{
+		From:     "a@b.com",
+		To:       []string{"c@d.com"},
+		Subject:  "Hi",
+		Body:     "Plain",
+		BodyHTML: `<p><img src="cid:logo"></p>`,
+		Inline:   []mailAttachment{{Filename: "logo.png", MIMEType: "image/png", Data: []byte("png"), ContentID: "logo"}},
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("top-level = %s", mediaType)
+	}
+	alt := multipart.NewReader(msg.Body, params["boundary"])
+	if _, err := alt.NextPart(); err != nil { // text/plain
+		t.Fatalf("plain part: %v", err)
+	}
+	relPart, err := alt.NextPart()
+	if err != nil {
+		t.Fatalf("related part: %v", err)
+	}
+	mediaType, params, _ = mime.ParseMediaType(relPart.Header.Get("Content-Type"))
+	if mediaType != "multipart/related" {
+		t.Fatalf("second part = %s", mediaType)
+	}
+	rel := multipart.NewReader(relPart, params["boundary"])
+	if p, err := rel.NextPart(); err != nil || !strings.HasPrefix(p.Header.Get("Content-Type"), "text/html") {
+		t.Fatalf("html part: %v", err)
+	}
+	img, err := rel.NextPart()
+	if err != nil {
+		t.Fatalf("image part: %v", err)
+	}
+	if img.Header.Get("Content-ID") != "<logo>" || !strings.HasPrefix(img.Header.Get("Content-Disposition"), "inline;") {
+		t.Fatalf("image headers = %v", img.Header)
+	}
+
+	if _, err := buildRFC822(mailOptions{From: "a@b.com", To: []string{"c@d.com"}, Subject: "Hi", Body: "Plain", Inline: []mailAttachment{{Filename: "x.png", Data: []byte("x")}}}); err == nil {
+		t.Fatalf("expected inline without HTML to fail")
+	}
+}
+
+func TestParseInlineImages(t *testing.T) {
+	got, err := parseInlineImages([]string{"img/logo.png", "chart.png:chart1", `C:\pics\a.png`})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	want := []string{"img/logo.png|logo.png", "chart.png|chart1", `C:\pics\a.png|` + filepath.Base(`C:\pics\a.png`)}
+	for i, a := range got {
+		if a.Path+"|"+a.ContentID != want[i] {
+			t.Fatalf("got[%d] = %s|%s, want %s", i, a.Path, a.ContentID, want[i])
+		}
+	}
+	if _, err := parseInlineImages([]string{"a.png:bad id"}); err == nil {
+		t.Fatalf("expected invalid cid error")
+	}
+}
+
+func TestParseAttachSpecs(t *testing.T) {
+	got, err := parseAttachSpecs([]string{"out/r.bin", "out/r.bin::Report Q1.pdf", "scan.dat::::image/tiff", "x.txt::notes.md::text/markdown; charset=utf-8"})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	want := []mailAttachment{
+		{Path: "out/r.bin"},
+		{Path: "out/r.bin", Filename: "Report Q1.pdf"},
+		{Path: "scan.dat", MIMEType: "image/tiff"},
+		{Path: "x.txt", Filename: "notes.md", MIMEType: "text/markdown; charset=utf-8"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+	for _, bad := range []string{"::x.pdf", "a::b::c/d::e", "a::../x.pdf", "a::x::notatype"} {
+		if _, err := parseAttachSpecs([]string{bad}); err == nil {
+			t.Fatalf("%q: expected error", bad)
+		}
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "r.bin")
+	if err := os.WriteFile(path, []byte("%PDF"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	atts, _ := parseAttachSpecs([]string{path + "::Report.pdf"})
+	raw, err := buildRFC822(mailOptions{From: "a@b.com", To: []string{"c@d.com"}, Subject: "Hi", Body: "Hi", Attachments: atts})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if s := string(raw); !strings.Contains(s, "Content-Type: application/pdf") || !strings.Contains(s, `filename="Report.pdf"`) {
+		t.Fatalf("raw = %s", s)
+	}
+}
+
 func TestBuildRFC822UTF8Subject(t *testing.T) {
 	raw, err := buildRFC822(mailOptions{
 		From:    "a@b.com",
@@ -239,7 +346,7 @@ func TestHasHeader(t *testing.T) {
 }
 
 func TestRandomMessageID(t *testing.T) {
-	id, err := randomMessageID("A <a@b.com>")
+	id, err := randomMessageID("A <a@b.com>", "")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -247,11 +354,19 @@ func TestRandomMessageID(t *testing.T) {
 		t.Fatalf("unexpected: %q", id)
 	}
 
-	id, err = randomMessageID("not-an-email")
+	id, err = randomMessageID("not-an-email", "")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 	if !regexp.MustCompile(`^<[A-Za-z0-9_-]+@gogcli\.local>$`).MatchString(id) {
 		t.Fatalf("unexpected: %q", id)
 	}
+
+	id, err = randomMessageID("A <a@b.com>", "mail.corp.example")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !regexp.MustCompile(`^<[A-Za-z0-9_-]+@mail\.corp\.example>$`).MatchString(id) {
+		t.Fatalf("unexpected: %q", id)
+	}
 }
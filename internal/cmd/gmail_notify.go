@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/pubsub/v1"
+)
+
+const (
+	defaultNotifyPullMax = 10
+	notifyPullRetryMin   = 5 * time.Second
+	notifyPullRetryMax   = 5 * time.Minute
+	notifyExecTimeout    = 5 * time.Minute
+)
+
+var newPubSubService = googleapi.NewPubSub
+
+// runNotifyExec runs --exec with the event JSON on stdin; swapped in tests.
+var runNotifyExec = func(ctx context.Context, command string, env []string, stdin []byte) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	c.Env = append(os.Environ(), env...)
+	c.Stdin = bytes.NewReader(stdin)
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+// gmailNotifyEvent is one new message, as sent to --webhook, fed to --exec
+// on stdin, or printed as a JSON line.
+type gmailNotifyEvent struct {
+	Source    string           `json:"source"`
+	Account   string           `json:"account"`
+	HistoryID string           `json:"historyId"`
+	Message   gmailHookMessage `json:"message"`
+}
+
+type gmailNotifier struct {
+	account      string
+	execCmd      string
+	webhookURL   string
+	webhookToken string
+	client       *http.Client
+	desktop      *desktopNotifier
+}
+
+func newGmailNotifyCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Process new mail from Gmail push notifications",
+	}
+	cmd.AddCommand(newGmailNotifyServeCmd(flags))
+	return cmd
+}
+
+func newGmailNotifyServeCmd(flags *rootFlags) *cobra.Command {
+	var subscription string
+	var push bool
+	var bind string
+	var port int
+	var path string
+	var verifyOIDC bool
+	var oidcEmail string
+	var oidcAudience string
+	var sharedToken string
+	var execCmd string
+	var webhookURL string
+	var webhookToken string
+	var includeBody bool
+	var maxBytes int
+	var once bool
+	var notify desktopNotifyFlag
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a command or webhook for every new message",
+		Long: `Receive Gmail watch notifications, resolve them to new messages through the
+history API, and handle each message: --exec runs through the shell with the
+event JSON on stdin and GOG_NOTIFY_* variables set, --webhook receives the event
+as a JSON POST, and with neither the events are printed as JSON lines.
+--notify-desktop also raises a desktop notification per message (filtered by
+--notify-rule, e.g. --notify-rule 'from~@boss\.com').
+
+Notifications arrive either by pulling --subscription (default: gmail.
+pubsubSubscription in config.json; authenticates with Application Default
+Credentials) or, with --push, on a local HTTP endpoint for a Pub/Sub push
+subscription (same options as gmail watch serve).
+
+The history cursor is shared with gmail watch, so run gmail watch start first
+(and gmail watch daemon to keep the watch alive).
+
+  gog gmail notify serve --subscription projects/p/subscriptions/gog --exec 'notify-send "$GOG_NOTIFY_FROM" "$GOG_NOTIFY_SUBJECT"'
+  gog gmail notify serve --push --port 8788 --token <shared> --webhook http://127.0.0.1:9000/mail`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if webhookToken != "" && webhookURL == "" {
+				return usage("--webhook-token requires --webhook")
+			}
+			if maxBytes <= 0 {
+				return usage("--max-bytes must be > 0")
+			}
+			if !push && strings.TrimSpace(subscription) == "" {
+				cfg, err := config.ReadConfigFile()
+				if err != nil {
+					return err
+				}
+				subscription = strings.TrimSpace(cfg.Gmail.PubSubSubscription)
+				if subscription == "" {
+					return usage("--subscription (or gmail.pubsubSubscription in config.json) or --push required")
+				}
+			}
+			if push && cmd.Flags().Changed("subscription") {
+				return usage("--subscription and --push are mutually exclusive")
+			}
+			if push && once {
+				return usage("--once only applies to pull mode")
+			}
+			if push {
+				if !strings.HasPrefix(path, "/") {
+					return usage("--path must start with '/'")
+				}
+				if port <= 0 {
+					return usage("--port must be > 0")
+				}
+				if !verifyOIDC && sharedToken == "" && !isLoopbackHost(bind) {
+					return usage("--verify-oidc or --token required when binding non-loopback")
+				}
+				if (oidcEmail != "" || oidcAudience != "") && !verifyOIDC {
+					return usage("--oidc-email/--oidc-audience require --verify-oidc")
+				}
+			}
+
+			desktop, err := notify.notifier(u)
+			if err != nil {
+				return err
+			}
+			store, err := loadGmailWatchStore(account)
+			if err != nil {
+				return err
+			}
+			notifier := &gmailNotifier{
+				desktop:      desktop,
+				account:      account,
+				execCmd:      execCmd,
+				webhookURL:   webhookURL,
+				webhookToken: webhookToken,
+				client:       &http.Client{Timeout: defaultHookRequestTimeoutSec * time.Second},
+			}
+			server := &gmailWatchServer{
+				cfg: gmailWatchServeConfig{
+					Account:      account,
+					Bind:         bind,
+					Port:         port,
+					Path:         path,
+					VerifyOIDC:   verifyOIDC,
+					OIDCEmail:    oidcEmail,
+					OIDCAudience: oidcAudience,
+					SharedToken:  sharedToken,
+					IncludeBody:  includeBody,
+					MaxBodyBytes: maxBytes,
+					HistoryMax:   defaultHistoryMaxResults,
+					ResyncMax:    defaultHistoryResyncMax,
+				},
+				store:      store,
+				newService: newGmailService,
+				logf:       u.Err().Printf,
+				warnf:      u.Err().Printf,
+				dispatch:   notifier.dispatch,
+			}
+
+			if !push {
+				psvc, err := newPubSubService(cmd.Context())
+				if err != nil {
+					return fmt.Errorf("pubsub client (needs Application Default Credentials): %w", err)
+				}
+				u.Err().Printf("notify: pulling %s", subscription)
+				return pullGmailNotifications(cmd.Context(), psvc, subscription, server, once)
+			}
+
+			if verifyOIDC {
+				server.validator, err = newOIDCValidator(cmd.Context())
+				if err != nil {
+					return err
+				}
+			}
+			addr := net.JoinHostPort(bind, strconv.Itoa(port))
+			u.Err().Printf("notify: listening on %s%s", addr, path)
+			httpServer := &http.Server{
+				Addr:              addr,
+				Handler:           server,
+				ReadHeaderTimeout: 5 * time.Second,
+			}
+			return httpServer.ListenAndServe()
+		},
+	}
+
+	cmd.Flags().StringVar(&subscription, "subscription", "", "Pub/Sub subscription to pull (projects/<p>/subscriptions/<s>)")
+	cmd.Flags().BoolVar(&push, "push", false, "Serve a Pub/Sub push endpoint instead of pulling")
+	cmd.Flags().StringVar(&bind, "bind", "127.0.0.1", "Bind address (--push)")
+	cmd.Flags().IntVar(&port, "port", defaultWatchPort, "Listen port (--push)")
+	cmd.Flags().StringVar(&path, "path", defaultWatchPath, "Push handler path (--push)")
+	cmd.Flags().BoolVar(&verifyOIDC, "verify-oidc", false, "Verify Pub/Sub OIDC tokens (--push)")
+	cmd.Flags().StringVar(&oidcEmail, "oidc-email", "", "Expected service account email")
+	cmd.Flags().StringVar(&oidcAudience, "oidc-audience", "", "Expected OIDC audience")
+	cmd.Flags().StringVar(&sharedToken, "token", "", "Shared token for x-gog-token or ?token= (--push)")
+	cmd.Flags().StringVar(&execCmd, "exec", "", "Shell command to run per message (event JSON on stdin)")
+	cmd.Flags().StringVar(&webhookURL, "webhook", "", "URL to POST each message event to")
+	cmd.Flags().StringVar(&webhookToken, "webhook-token", "", "Webhook bearer token")
+	cmd.Flags().BoolVar(&includeBody, "include-body", false, "Include the text body in events")
+	cmd.Flags().IntVar(&maxBytes, "max-bytes", defaultHookMaxBytes, "Max bytes of body to include")
+	cmd.Flags().BoolVar(&once, "once", false, "Pull one batch, handle it, and exit")
+	notify.addFlags(cmd, "from,to,subject,snippet,labels")
+	return cmd
+}
+
+// pullGmailNotifications pulls the subscription until ctx is done. Messages
+// are acknowledged once their history has been resolved; a failed resolve
+// leaves them for Pub/Sub to redeliver.
+func pullGmailNotifications(ctx context.Context, psvc *pubsub.Service, subscription string, server *gmailWatchServer, once bool) error {
+	backoff := notifyPullRetryMin
+	for {
+		resp, err := psvc.Projects.Subscriptions.Pull(subscription, &pubsub.PullRequest{MaxMessages: defaultNotifyPullMax}).Context(ctx).Do()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if once {
+				return err
+			}
+			server.warnf("notify: pull failed (retrying in %s): %v", backoff, err)
+			if err := watchDaemonSleep(ctx, backoff); err != nil {
+				return nil
+			}
+			backoff = min(backoff*2, notifyPullRetryMax)
+			continue
+		}
+		backoff = notifyPullRetryMin
+
+		var ack []string
+		for _, rm := range resp.ReceivedMessages {
+			if rm == nil || rm.Message == nil {
+				continue
+			}
+			if err := handlePulledNotification(ctx, server, rm.Message.Data); err != nil {
+				server.warnf("notify: %v", err)
+				continue
+			}
+			ack = append(ack, rm.AckId)
+		}
+		if len(ack) > 0 {
+			if _, err := psvc.Projects.Subscriptions.Acknowledge(subscription, &pubsub.AcknowledgeRequest{AckIds: ack}).Context(ctx).Do(); err != nil && ctx.Err() == nil {
+				server.warnf("notify: acknowledge failed: %v", err)
+			}
+		}
+		if once {
+			return nil
+		}
+	}
+}
+
+// handlePulledNotification resolves one pulled notification. Undecodable
+// or foreign notifications return nil so they are acknowledged and dropped.
+func handlePulledNotification(ctx context.Context, server *gmailWatchServer, data string) error {
+	var envelope pubsubPushEnvelope
+	envelope.Message.Data = data
+	payload, err := decodeGmailPushPayload(&envelope)
+	if err != nil {
+		server.warnf("notify: dropping invalid notification: %v", err)
+		return nil
+	}
+	if payload.EmailAddress != "" && !strings.EqualFold(payload.EmailAddress, server.cfg.Account) {
+		server.warnf("notify: ignoring notification for %s", payload.EmailAddress)
+		return nil
+	}
+	result, err := server.handlePush(ctx, payload)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	if err := server.dispatch(ctx, result); err != nil {
+		server.warnf("notify: %v", err)
+	}
+	return nil
+}
+
+// dispatch hands every resolved message to --exec/--webhook (or stdout).
+// Failures are reported per message and do not stop the others.
+func (n *gmailNotifier) dispatch(ctx context.Context, payload *gmailHookPayload) error {
+	var errs []error
+	for _, msg := range payload.Messages {
+		event := gmailNotifyEvent{
+			Source:    "gmail",
+			Account:   n.account,
+			HistoryID: payload.HistoryID,
+			Message:   msg,
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		n.desktop.notifyGmailMessage(ctx, msg)
+		if n.execCmd == "" && n.webhookURL == "" {
+			if _, err := fmt.Fprintf(os.Stdout, "%s\n", data); err != nil {
+				return err
+			}
+			continue
+		}
+		if n.execCmd != "" {
+			execCtx, cancel := context.WithTimeout(ctx, notifyExecTimeout)
+			err := runNotifyExec(execCtx, n.execCmd, notifyEnv(event), data)
+			cancel()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: exec: %w", msg.ID, err))
+			}
+		}
+		if n.webhookURL != "" {
+			if err := n.postWebhook(ctx, data); err != nil {
+				errs = append(errs, fmt.Errorf("%s: webhook: %w", msg.ID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *gmailNotifier) postWebhook(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.webhookToken != "" {
+		req.Header.Set("Authorization", "Bearer "+n.webhookToken)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func notifyEnv(e gmailNotifyEvent) []string {
+	return []string{
+		"GOG_NOTIFY_ACCOUNT=" + e.Account,
+		"GOG_NOTIFY_HISTORY_ID=" + e.HistoryID,
+		"GOG_NOTIFY_MESSAGE_ID=" + e.Message.ID,
+		"GOG_NOTIFY_THREAD_ID=" + e.Message.ThreadID,
+		"GOG_NOTIFY_FROM=" + e.Message.From,
+		"GOG_NOTIFY_TO=" + e.Message.To,
+		"GOG_NOTIFY_SUBJECT=" + e.Message.Subject,
+		"GOG_NOTIFY_DATE=" + e.Message.Date,
+		"GOG_NOTIFY_LABELS=" + strings.Join(e.Message.Labels, ","),
+	}
+}
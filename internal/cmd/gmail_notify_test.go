@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+	"google.golang.org/api/pubsub/v1"
+)
+
+func TestGmailNotifyServe_PullOnce(t *testing.T) {
+	origGmail, origPubSub, origExec := newGmailService, newPubSubService, runNotifyExec
+	t.Cleanup(func() {
+		newGmailService, newPubSubService, runNotifyExec = origGmail, origPubSub, origExec
+	})
+	t.Setenv("HOME", t.TempDir())
+
+	gmailSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/history"):
+			if r.URL.Query().Get("startHistoryId") != "500" {
+				t.Errorf("startHistoryId = %q", r.URL.Query().Get("startHistoryId"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"historyId": "610",
+				"history": []map[string]any{{
+					"messagesAdded": []map[string]any{{"message": map[string]any{"id": "m1", "threadId": "t1"}}},
+				}},
+			})
+		case strings.HasSuffix(r.URL.Path, "/users/me/messages/m1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":       "m1",
+				"threadId": "t1",
+				"labelIds": []string{"INBOX", "UNREAD"},
+				"payload": map[string]any{"headers": []map[string]any{
+					{"name": "From", "value": "alice@example.com"},
+					{"name": "Subject", "value": "Hello"},
+				}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gmailSrv.Close()
+
+	var acked []string
+	data := base64.StdEncoding.EncodeToString([]byte(`{"emailAddress":"a@b.com","historyId":600}`))
+	pubsubSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "projects/p/subscriptions/s:pull"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"receivedMessages": []map[string]any{{"ackId": "ack-1", "message": map[string]any{"data": data}}},
+			})
+		case strings.HasSuffix(r.URL.Path, "projects/p/subscriptions/s:acknowledge"):
+			var req struct {
+				AckIds []string `json:"ackIds"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			acked = append(acked, req.AckIds...)
+			_, _ = io.WriteString(w, "{}")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer pubsubSrv.Close()
+
+	gsvc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(gmailSrv.Client()), option.WithEndpoint(gmailSrv.URL+"/"))
+	if err != nil {
+		t.Fatalf("gmail: %v", err)
+	}
+	psvc, err := pubsub.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(pubsubSrv.Client()), option.WithEndpoint(pubsubSrv.URL+"/"))
+	if err != nil {
+		t.Fatalf("pubsub: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return gsvc, nil }
+	newPubSubService = func(context.Context) (*pubsub.Service, error) { return psvc, nil }
+
+	var mu sync.Mutex
+	var execEnv []string
+	var execStdin []byte
+	runNotifyExec = func(_ context.Context, command string, env []string, stdin []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if command != "handle-mail" {
+			t.Errorf("command = %q", command)
+		}
+		execEnv, execStdin = env, stdin
+		return nil
+	}
+
+	var hooked gmailNotifyEvent
+	var hookAuth string
+	hookSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hookAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&hooked)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hookSrv.Close()
+
+	store, err := newGmailWatchStore("a@b.com")
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	store.state = gmailWatchState{Account: "a@b.com", Topic: "projects/p/topics/t", HistoryID: "500"}
+	if err := store.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	_ = captureStderr(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "notify", "serve",
+			"--subscription", "projects/p/subscriptions/s", "--once",
+			"--exec", "handle-mail", "--webhook", hookSrv.URL, "--webhook-token", "tok"}); err != nil {
+			t.Fatalf("notify serve: %v", err)
+		}
+	})
+
+	if len(acked) != 1 || acked[0] != "ack-1" {
+		t.Fatalf("acked = %v", acked)
+	}
+	if !strings.Contains(strings.Join(execEnv, "\n"), "GOG_NOTIFY_SUBJECT=Hello") || !strings.Contains(strings.Join(execEnv, "\n"), "GOG_NOTIFY_MESSAGE_ID=m1") {
+		t.Fatalf("exec env = %v", execEnv)
+	}
+	var event gmailNotifyEvent
+	if err := json.Unmarshal(execStdin, &event); err != nil || event.Message.From != "alice@example.com" || event.HistoryID != "610" {
+		t.Fatalf("exec stdin = %s (%v)", execStdin, err)
+	}
+	if hooked.Message.ID != "m1" || hookAuth != "Bearer tok" {
+		t.Fatalf("webhook got %#v auth %q", hooked, hookAuth)
+	}
+
+	reloaded, err := loadGmailWatchStore("a@b.com")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := reloaded.Get().HistoryID; got != "610" {
+		t.Fatalf("history cursor = %q", got)
+	}
+}
+
+func TestGmailNotifyServe_RequiresSubscriptionOrPush(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	err := Execute([]string{"--account", "a@b.com", "gmail", "notify", "serve"})
+	if err == nil || !strings.Contains(err.Error(), "--subscription") {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
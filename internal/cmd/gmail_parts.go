@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// mimePartRow is one node of a message's MIME tree, in depth-first order.
+type mimePartRow struct {
+	PartID       string `json:"partId"`
+	Depth        int    `json:"depth"`
+	MimeType     string `json:"mimeType"`
+	Size         int64  `json:"size"`
+	Charset      string `json:"charset,omitempty"`
+	Encoding     string `json:"encoding,omitempty"`
+	Disposition  string `json:"disposition,omitempty"`
+	Filename     string `json:"filename,omitempty"`
+	ContentID    string `json:"contentId,omitempty"`
+	AttachmentID string `json:"attachmentId,omitempty"`
+}
+
+func mimePartTree(p *gmail.MessagePart) []mimePartRow {
+	var out []mimePartRow
+	var walk func(p *gmail.MessagePart, depth int)
+	walk = func(p *gmail.MessagePart, depth int) {
+		if p == nil {
+			return
+		}
+		row := mimePartRow{
+			PartID:    p.PartId,
+			Depth:     depth,
+			MimeType:  p.MimeType,
+			Filename:  p.Filename,
+			Encoding:  strings.ToLower(strings.TrimSpace(headerValue(p, "Content-Transfer-Encoding"))),
+			ContentID: strings.Trim(strings.TrimSpace(headerValue(p, "Content-ID")), "<>"),
+		}
+		if row.PartID == "" && depth == 0 {
+			row.PartID = "(root)"
+		}
+		if p.Body != nil {
+			row.Size = p.Body.Size
+			row.AttachmentID = p.Body.AttachmentId
+		}
+		if _, params, err := mime.ParseMediaType(headerValue(p, "Content-Type")); err == nil {
+			row.Charset = strings.ToLower(params["charset"])
+		}
+		if cd := strings.TrimSpace(headerValue(p, "Content-Disposition")); cd != "" {
+			if disp, _, err := mime.ParseMediaType(cd); err == nil {
+				row.Disposition = disp
+			} else {
+				row.Disposition = strings.ToLower(strings.TrimSpace(strings.SplitN(cd, ";", 2)[0]))
+			}
+		}
+		out = append(out, row)
+		for _, child := range p.Parts {
+			walk(child, depth+1)
+		}
+	}
+	walk(p, 0)
+	return out
+}
+
+// formatMimePartType indents the MIME type by tree depth for table output.
+func formatMimePartType(r mimePartRow) string {
+	return fmt.Sprintf("%s%s", strings.Repeat("  ", r.Depth), r.MimeType)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestMimePartTree(t *testing.T) {
+	h := func(kv ...string) []*gmail.MessagePartHeader {
+		var out []*gmail.MessagePartHeader
+		for i := 0; i+1 < len(kv); i += 2 {
+			out = append(out, &gmail.MessagePartHeader{Name: kv[i], Value: kv[i+1]})
+		}
+		return out
+	}
+	payload := &gmail.MessagePart{
+		MimeType: "multipart/mixed",
+		Body:     &gmail.MessagePartBody{},
+		Parts: []*gmail.MessagePart{
+			{
+				PartId:   "0",
+				MimeType: "multipart/alternative",
+				Parts: []*gmail.MessagePart{
+					{PartId: "0.0", MimeType: "text/plain", Headers: h("Content-Type", `text/plain; charset="ISO-8859-1"`, "Content-Transfer-Encoding", "Quoted-Printable"), Body: &gmail.MessagePartBody{Size: 12}},
+					{PartId: "0.1", MimeType: "text/html", Body: &gmail.MessagePartBody{Size: 40}},
+				},
+			},
+			{
+				PartId:   "1",
+				MimeType: "image/png",
+				Filename: "logo.png",
+				Headers:  h("Content-Disposition", `inline; filename="logo.png"`, "Content-ID", "<logo@x>"),
+				Body:     &gmail.MessagePartBody{Size: 99, AttachmentId: "att"},
+			},
+		},
+	}
+
+	rows := mimePartTree(payload)
+	if len(rows) != 5 {
+		t.Fatalf("expected 5 rows, got %#v", rows)
+	}
+	if rows[0].PartID != "(root)" || rows[2].Depth != 2 || rows[2].Charset != "iso-8859-1" || rows[2].Encoding != "quoted-printable" {
+		t.Fatalf("unexpected rows: %#v", rows[:3])
+	}
+	img := rows[4]
+	if img.Disposition != "inline" || img.ContentID != "logo@x" || img.AttachmentID != "att" || img.Size != 99 {
+		t.Fatalf("unexpected image row: %#v", img)
+	}
+	if got := formatMimePartType(rows[2]); got != "    text/plain" {
+		t.Fatalf("unexpected indent %q", got)
+	}
+}
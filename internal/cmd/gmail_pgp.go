@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"google.golang.org/api/gmail/v1"
+)
+
+// gpgCommand is the GnuPG binary; keys come from its keyring (GNUPGHOME).
+var gpgCommand = "gpg"
+
+// pgpOptions selects PGP/MIME (RFC 3156) signing and/or encryption.
+type pgpOptions struct {
+	Sign    bool
+	Encrypt bool
+	// Key is the signing key (gpg --local-user); empty uses gpg's default.
+	Key string
+	// Recipients get the message encrypted to them; Hidden ones are not
+	// named in the ciphertext (Bcc).
+	Recipients []string
+	Hidden     []string
+}
+
+// pgpFlag holds the --pgp-* flags of gmail send.
+type pgpFlag struct {
+	sign    bool
+	encrypt bool
+	key     string
+}
+
+func (f *pgpFlag) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.sign, "pgp-sign", false, "Sign the message with OpenPGP (PGP/MIME, via gpg)")
+	cmd.Flags().BoolVar(&f.encrypt, "pgp-encrypt", false, "Encrypt the message with OpenPGP to all recipients and the sender (via gpg)")
+	cmd.Flags().StringVar(&f.key, "pgp-key", "", "Signing key ID or address for --pgp-sign (default: config gmail.pgpKey, else the From address)")
+}
+
+// options returns nil when neither --pgp-sign nor --pgp-encrypt is set.
+func (f *pgpFlag) options(from string, to, cc, bcc []string) (*pgpOptions, error) {
+	if !f.sign && !f.encrypt {
+		return nil, nil
+	}
+	key := strings.TrimSpace(f.key)
+	if key == "" {
+		cfg, err := config.ReadConfigFile()
+		if err != nil {
+			return nil, err
+		}
+		key = strings.TrimSpace(cfg.Gmail.PGPKey)
+	}
+	if key == "" {
+		key = senderAddress(from)
+	}
+	p := &pgpOptions{Sign: f.sign, Encrypt: f.encrypt, Key: key}
+	if f.encrypt {
+		for _, a := range append(append([]string{from}, to...), cc...) {
+			if addr := senderAddress(a); addr != "" {
+				p.Recipients = append(p.Recipients, addr)
+			}
+		}
+		for _, a := range bcc {
+			if addr := senderAddress(a); addr != "" {
+				p.Hidden = append(p.Hidden, addr)
+			}
+		}
+	}
+	return p, nil
+}
+
+// runGPG pipes stdin through gpg. stderr carries --status-fd lines, so
+// callers can read signature results from it.
+func runGPG(ctx context.Context, stdin []byte, args ...string) (stdout, stderr []byte, err error) {
+	if _, lookErr := exec.LookPath(gpgCommand); lookErr != nil {
+		return nil, nil, fmt.Errorf("OpenPGP needs GnuPG (%s not found in PATH)", gpgCommand)
+	}
+	var out, errOut bytes.Buffer
+	c := exec.CommandContext(ctx, gpgCommand, append([]string{"--batch", "--no-tty", "--status-fd", "2"}, args...)...)
+	c.Stdin = bytes.NewReader(stdin)
+	c.Stdout = &out
+	c.Stderr = &errOut
+	if err := c.Run(); err != nil {
+		return out.Bytes(), errOut.Bytes(), fmt.Errorf("gpg: %s", gpgErrorText(errOut.Bytes(), err))
+	}
+	return out.Bytes(), errOut.Bytes(), nil
+}
+
+// gpgErrorText keeps gpg's human-readable lines, dropping status lines.
+func gpgErrorText(stderr []byte, err error) string {
+	var lines []string
+	for _, l := range strings.Split(string(stderr), "\n") {
+		l = strings.TrimSpace(l)
+		if l != "" && !strings.HasPrefix(l, "[GNUPG:]") {
+			lines = append(lines, strings.TrimPrefix(l, "gpg: "))
+		}
+	}
+	if len(lines) == 0 {
+		return err.Error()
+	}
+	return strings.Join(lines, "; ")
+}
+
+// writePGPEntity writes entity (a Content-Type header, blank line and
+// content) as multipart/signed or multipart/encrypted per RFC 3156. With
+// both options the entity is signed and encrypted in one OpenPGP message.
+func writePGPEntity(b mimeWriter, entity []byte, p *pgpOptions) error {
+	ctx := context.Background()
+	boundary, err := randomBoundary()
+	if err != nil {
+		return err
+	}
+
+	if !p.Encrypt {
+		args := []string{"--armor", "--detach-sign", "--digest-algo", "SHA256"}
+		if p.Key != "" {
+			args = append(args, "--local-user", p.Key)
+		}
+		sig, _, err := runGPG(ctx, entity, args...)
+		if err != nil {
+			return err
+		}
+		b.WriteString(fmt.Sprintf("Content-Type: multipart/signed; boundary=%q; micalg=pgp-sha256; protocol=\"application/pgp-signature\"\r\n\r\n", boundary))
+		b.WriteString("This is an OpenPGP/MIME signed message (RFC 4880 and 3156)\r\n")
+		b.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+		// The CRLF before the next delimiter belongs to the delimiter, so
+		// the signed entity keeps its own trailing line break.
+		_, _ = b.Write(entity)
+		b.WriteString(fmt.Sprintf("\r\n--%s\r\n", boundary))
+		b.WriteString("Content-Type: application/pgp-signature; name=\"signature.asc\"\r\n")
+		b.WriteString("Content-Description: OpenPGP digital signature\r\n")
+		b.WriteString("Content-Disposition: attachment; filename=\"signature.asc\"\r\n\r\n")
+		writeBodyWithTrailingCRLF(b, normalizeCRLF(string(sig)))
+		b.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+		return nil
+	}
+
+	if len(p.Recipients)+len(p.Hidden) == 0 {
+		return errors.New("--pgp-encrypt needs at least one recipient")
+	}
+	args := []string{"--armor", "--encrypt"}
+	for _, r := range p.Recipients {
+		args = append(args, "--recipient", r)
+	}
+	for _, r := range p.Hidden {
+		args = append(args, "--hidden-recipient", r)
+	}
+	if p.Sign {
+		args = append(args, "--sign", "--digest-algo", "SHA256")
+		if p.Key != "" {
+			args = append(args, "--local-user", p.Key)
+		}
+	}
+	enc, _, err := runGPG(ctx, entity, args...)
+	if err != nil {
+		return err
+	}
+	b.WriteString(fmt.Sprintf("Content-Type: multipart/encrypted; boundary=%q; protocol=\"application/pgp-encrypted\"\r\n\r\n", boundary))
+	b.WriteString("This is an OpenPGP/MIME encrypted message (RFC 4880 and 3156)\r\n")
+	b.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+	b.WriteString("Content-Type: application/pgp-encrypted\r\n")
+	b.WriteString("Content-Description: PGP/MIME version identification\r\n\r\n")
+	b.WriteString("Version: 1\r\n\r\n")
+	b.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+	b.WriteString("Content-Type: application/octet-stream; name=\"encrypted.asc\"\r\n")
+	b.WriteString("Content-Description: OpenPGP encrypted message\r\n")
+	b.WriteString("Content-Disposition: inline; filename=\"encrypted.asc\"\r\n\r\n")
+	writeBodyWithTrailingCRLF(b, normalizeCRLF(string(enc)))
+	b.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+	return nil
+}
+
+// pgpDecrypted is the result of gmail get --pgp-decrypt.
+type pgpDecrypted struct {
+	Body string `json:"body"`
+	// Signature is good, bad or unknown-key (empty when unsigned); Signer
+	// is the user ID gpg reported.
+	Signature string `json:"signature,omitempty"`
+	Signer    string `json:"signer,omitempty"`
+}
+
+var pgpArmoredMessageRe = regexp.MustCompile(`(?s)-----BEGIN PGP MESSAGE-----.*?-----END PGP MESSAGE-----`)
+
+// decryptGmailMessage decrypts a PGP/MIME (multipart/encrypted) message or
+// an inline-PGP armored block in the body.
+func decryptGmailMessage(ctx context.Context, svc *gmail.Service, msg *gmail.Message) (*pgpDecrypted, error) {
+	var ciphertext []byte
+	pgpMIME := false
+	if part := findEncryptedPart(msg.Payload); part != nil {
+		data, err := gmailPartData(ctx, svc, msg.Id, part)
+		if err != nil {
+			return nil, err
+		}
+		ciphertext, pgpMIME = data, true
+	} else if block := pgpArmoredMessageRe.FindString(bestBodyText(msg.Payload)); block != "" {
+		ciphertext = []byte(block)
+	} else {
+		return nil, errors.New("message is not OpenPGP-encrypted")
+	}
+
+	plain, status, err := runGPG(ctx, ciphertext, "--decrypt")
+	if err != nil {
+		return nil, err
+	}
+	out := &pgpDecrypted{Body: string(plain)}
+	out.Signature, out.Signer = gpgSignatureStatus(status)
+	if pgpMIME {
+		text, err := mimeEntityText(plain)
+		if err != nil {
+			return nil, fmt.Errorf("decrypted content: %w", err)
+		}
+		out.Body = text
+	}
+	return out, nil
+}
+
+// findEncryptedPart returns the ciphertext part of a multipart/encrypted
+// payload (its second part).
+func findEncryptedPart(p *gmail.MessagePart) *gmail.MessagePart {
+	if p == nil {
+		return nil
+	}
+	if strings.EqualFold(p.MimeType, "multipart/encrypted") && len(p.Parts) >= 2 {
+		return p.Parts[1]
+	}
+	for _, part := range p.Parts {
+		if found := findEncryptedPart(part); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// gmailPartData returns a part's decoded body, fetching it when Gmail
+// stored it as an attachment.
+func gmailPartData(ctx context.Context, svc *gmail.Service, messageID string, p *gmail.MessagePart) ([]byte, error) {
+	if p.Body == nil {
+		return nil, errors.New("empty message part")
+	}
+	data := p.Body.Data
+	if data == "" && p.Body.AttachmentId != "" {
+		att, err := svc.Users.Messages.Attachments.Get("me", messageID, p.Body.AttachmentId).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		data = att.Data
+	}
+	return base64.URLEncoding.DecodeString(padBase64URL(data))
+}
+
+func padBase64URL(s string) string {
+	if m := len(s) % 4; m != 0 {
+		s += strings.Repeat("=", 4-m)
+	}
+	return s
+}
+
+// gpgSignatureStatus reads the signature verdict from gpg status lines.
+func gpgSignatureStatus(status []byte) (verdict, signer string) {
+	sc := bufio.NewScanner(bytes.NewReader(status))
+	for sc.Scan() {
+		fields := strings.SplitN(strings.TrimPrefix(sc.Text(), "[GNUPG:] "), " ", 3)
+		if len(fields) == 0 {
+			continue
+		}
+		uid := ""
+		if len(fields) == 3 {
+			uid = fields[2]
+		}
+		switch fields[0] {
+		case "GOODSIG":
+			return "good", uid
+		case "BADSIG":
+			return "bad", uid
+		case "ERRSIG":
+			return "unknown-key", ""
+		}
+	}
+	return "", ""
+}
+
+// mimeEntityText extracts readable text from a MIME entity: text/plain
+// preferred, HTML converted, first part of multipart/signed.
+func mimeEntityText(entity []byte) (string, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(entity))
+	if err != nil {
+		return "", err
+	}
+	return mimePartText(m.Header.Get("Content-Type"), m.Header.Get("Content-Transfer-Encoding"), m.Body)
+}
+
+func mimePartText(contentType, encoding string, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		var html string
+		for {
+			part, err := mr.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+			partType := part.Header.Get("Content-Type")
+			if strings.HasPrefix(strings.ToLower(part.Header.Get("Content-Disposition")), "attachment") {
+				continue
+			}
+			text, err := mimePartText(partType, part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", err
+			}
+			if strings.HasPrefix(strings.ToLower(partType), "text/html") {
+				if html == "" {
+					html = text
+				}
+				continue
+			}
+			if text != "" {
+				return text, nil
+			}
+		}
+		return html, nil
+	}
+	if !strings.HasPrefix(mediaType, "text/") {
+		return "", nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	text := strings.ReplaceAll(decodeCharset(data, params["charset"]), "\r\n", "\n")
+	if mediaType == "text/html" {
+		return htmlToText(text), nil
+	}
+	return text, nil
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// newTestGPGHome creates a keyring with an unprotected key for me@example.com.
+func newTestGPGHome(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath(gpgCommand); err != nil {
+		t.Skip("gpg not installed")
+	}
+	// Short path: gpg-agent's socket path has a length limit.
+	home, err := os.MkdirTemp("", "gpg")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	t.Setenv("GNUPGHOME", home)
+	t.Cleanup(func() {
+		_ = exec.Command("gpgconf", "--kill", "gpg-agent").Run()
+		_ = os.RemoveAll(home)
+	})
+	out, err := exec.Command(gpgCommand, "--batch", "--passphrase", "", "--quick-gen-key", "Me <me@example.com>", "future-default", "default", "never").CombinedOutput()
+	if err != nil {
+		t.Skipf("gpg key generation failed: %v\n%s", err, out)
+	}
+}
+
+func pgpTestOptions(p *pgpOptions) mailOptions {
+	return mailOptions{
+		From:     "Me <me@example.com>",
+		To:       []string{"me@example.com"},
+		Subject:  "Secret",
+		Body:     "launch code: 1234\n",
+		BodyHTML: "<p>launch code: <b>1234</b></p>",
+		PGP:      p,
+	}
+}
+
+func TestPGPSignedMessageVerifies(t *testing.T) {
+	newTestGPGHome(t)
+
+	raw, err := buildRFC822(pgpTestOptions(&pgpOptions{Sign: true, Key: "me@example.com"}))
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	msg := string(raw)
+	ct := msg[strings.Index(msg, "Content-Type: multipart/signed"):]
+	ct = ct[len("Content-Type: "):strings.Index(ct, "\r\n")]
+	_, params, err := mime.ParseMediaType(ct)
+	if err != nil || params["micalg"] != "pgp-sha256" || params["protocol"] != "application/pgp-signature" {
+		t.Fatalf("content type %q: %v %v", ct, params, err)
+	}
+	delim := "--" + params["boundary"]
+	start := strings.Index(msg, delim+"\r\n") + len(delim) + 2
+	end := strings.Index(msg[start:], "\r\n"+delim+"\r\n") + start
+	entity := msg[start:end]
+	sig := msg[end+len(delim)+4:]
+	sig = sig[strings.Index(sig, "-----BEGIN PGP SIGNATURE-----"):]
+
+	dir := t.TempDir()
+	entityPath, sigPath := filepath.Join(dir, "entity"), filepath.Join(dir, "sig.asc")
+	if err := os.WriteFile(entityPath, []byte(entity), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(sigPath, []byte(sig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command(gpgCommand, "--batch", "--verify", sigPath, entityPath).CombinedOutput(); err != nil {
+		t.Fatalf("verify: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(entity, "Content-Type: multipart/alternative") {
+		t.Fatalf("signed entity = %q", entity[:min(len(entity), 80)])
+	}
+}
+
+func TestPGPEncryptedMessageRoundTrip(t *testing.T) {
+	newTestGPGHome(t)
+
+	raw, err := buildRFC822(pgpTestOptions(&pgpOptions{Sign: true, Encrypt: true, Key: "me@example.com", Recipients: []string{"me@example.com"}}))
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	msg := string(raw)
+	if !strings.Contains(msg, "Content-Type: multipart/encrypted;") || !strings.Contains(msg, "Version: 1") {
+		t.Fatalf("not PGP/MIME encrypted:\n%s", msg)
+	}
+	if strings.Contains(msg, "launch code") {
+		t.Fatalf("plaintext leaked:\n%s", msg)
+	}
+	if !strings.Contains(msg, "Subject: Secret") {
+		t.Fatalf("headers should stay readable")
+	}
+
+	armored := msg[strings.Index(msg, "-----BEGIN PGP MESSAGE-----"):]
+	armored = armored[:strings.Index(armored, "-----END PGP MESSAGE-----")+len("-----END PGP MESSAGE-----")]
+	gm := &gmail.Message{Id: "m1", Payload: &gmail.MessagePart{
+		MimeType: "multipart/encrypted",
+		Parts: []*gmail.MessagePart{
+			{MimeType: "application/pgp-encrypted", Body: &gmail.MessagePartBody{Data: base64.RawURLEncoding.EncodeToString([]byte("Version: 1\r\n"))}},
+			{MimeType: "application/octet-stream", Body: &gmail.MessagePartBody{Data: base64.RawURLEncoding.EncodeToString([]byte(armored))}},
+		},
+	}}
+	got, err := decryptGmailMessage(context.Background(), nil, gm)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if strings.TrimSpace(got.Body) != "launch code: 1234" {
+		t.Fatalf("body = %q", got.Body)
+	}
+	if got.Signature != "good" || !strings.Contains(got.Signer, "me@example.com") {
+		t.Fatalf("signature = %q %q", got.Signature, got.Signer)
+	}
+
+	inline := &gmail.Message{Id: "m2", Payload: &gmail.MessagePart{
+		MimeType: "text/plain",
+		Body:     &gmail.MessagePartBody{Data: base64.RawURLEncoding.EncodeToString([]byte("see below\n\n" + armored + "\n"))},
+	}}
+	if _, err := decryptGmailMessage(context.Background(), nil, inline); err != nil {
+		t.Fatalf("inline decrypt: %v", err)
+	}
+}
+
+func TestMimeEntityText(t *testing.T) {
+	entity := "Content-Type: multipart/mixed; boundary=\"b\"\r\n\r\n" +
+		"--b\r\nContent-Type: text/html; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n<p>Gr=C3=BC=C3=9Fe</p>\r\n" +
+		"--b\r\nContent-Type: text/plain\r\nContent-Disposition: attachment; filename=\"a.txt\"\r\n\r\nignored\r\n" +
+		"--b--\r\n"
+	got, err := mimeEntityText([]byte(entity))
+	if err != nil {
+		t.Fatalf("text: %v", err)
+	}
+	if !bytes.Contains([]byte(got), []byte("Grüße")) || strings.Contains(got, "ignored") {
+		t.Fatalf("text = %q", got)
+	}
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/statefile"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/googleapi"
+)
+
+// maxQueueAttempts bounds how often queue run tries a message that keeps
+// failing before marking it failed.
+const maxQueueAttempts = 5
+
+// queueSendingSuffix marks a message claimed by a running queue run: the
+// run renames <id>.json to <id>.json.sending before sending, so overlapping
+// runs cannot both send it, and only puts it back if the send failed.
+const queueSendingSuffix = ".sending"
+
+// queuedMessage is one entry in the local outbox (one JSON file per message).
+type queuedMessage struct {
+	ID        string    `json:"id"`
+	Account   string    `json:"account"`
+	SendAt    time.Time `json:"sendAt"`
+	CreatedAt time.Time `json:"createdAt"`
+	To        []string  `json:"to,omitempty"`
+	Subject   string    `json:"subject,omitempty"`
+	ThreadID  string    `json:"threadId,omitempty"`
+	// Raw is the base64url-encoded RFC822 message, as sent to users.messages.send.
+	Raw       string `json:"raw"`
+	Attempts  int    `json:"attempts,omitempty"`
+	LastError string `json:"lastError,omitempty"`
+	// Failed is set once the message got a permanent error or ran out of
+	// attempts; queue run no longer sends it.
+	Failed bool `json:"failed,omitempty"`
+	// Sending is set for a message claimed by a queue run that has not
+	// finished (or crashed mid-send); it is never sent again automatically.
+	Sending bool `json:"-"`
+}
+
+func (m queuedMessage) due(now time.Time) bool {
+	return !m.Failed && !m.Sending && !m.SendAt.After(now)
+}
+
+func newQueueID(now time.Time) string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return now.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(b)
+}
+
+func queuedMessagePath(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", usagef("invalid queue id %q", id)
+	}
+	dir, err := config.EnsureOutboxDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, id+".json"), nil
+}
+
+func saveQueuedMessage(m queuedMessage) error {
+	path, err := queuedMessagePath(m.ID)
+	if err != nil {
+		return err
+	}
+	payload, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return statefile.WriteFile(path, append(payload, '\n'), 0o600)
+}
+
+func readQueuedFile(path string) (queuedMessage, error) {
+	data, err := statefile.ReadFile(path)
+	if err != nil {
+		return queuedMessage{}, err
+	}
+	var m queuedMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return queuedMessage{}, fmt.Errorf("%s: %w", path, err)
+	}
+	m.Sending = strings.HasSuffix(path, queueSendingSuffix)
+	return m, nil
+}
+
+// loadQueuedMessage reads a queued message, or one claimed for sending.
+func loadQueuedMessage(id string) (queuedMessage, error) {
+	path, err := queuedMessagePath(id)
+	if err != nil {
+		return queuedMessage{}, err
+	}
+	m, err := readQueuedFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		m, err = readQueuedFile(path + queueSendingSuffix)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return queuedMessage{}, fmt.Errorf("queued message %s not found", id)
+	}
+	return m, err
+}
+
+func removeQueuedMessage(id string) error {
+	path, err := queuedMessagePath(id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = os.Remove(path + queueSendingSuffix)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("queued message %s not found", id)
+	}
+	return err
+}
+
+// claimQueuedMessage takes the message out of the queue for sending and
+// returns it as stored now (a concurrent run may have updated it). ok is
+// false when another run claimed it first.
+func claimQueuedMessage(id string) (m queuedMessage, ok bool, err error) {
+	path, err := queuedMessagePath(id)
+	if err != nil {
+		return queuedMessage{}, false, err
+	}
+	if err := os.Rename(path, path+queueSendingSuffix); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return queuedMessage{}, false, nil
+		}
+		return queuedMessage{}, false, err
+	}
+	m, err = readQueuedFile(path + queueSendingSuffix)
+	m.Sending = false
+	return m, err == nil, err
+}
+
+// permanentSendError reports send errors a retry cannot fix: 4xx responses
+// other than auth, quota (403), timeout and rate limit ones.
+func permanentSendError(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code < 400 || apiErr.Code >= 500 {
+		return false
+	}
+	switch apiErr.Code {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return true
+}
+
+// finishQueuedMessage ends a claim: a sent message is dropped, an unsent
+// one (m, with its error recorded) goes back into the queue.
+func finishQueuedMessage(m queuedMessage, sent bool) error {
+	path, err := queuedMessagePath(m.ID)
+	if err != nil {
+		return err
+	}
+	if !sent {
+		m.Sending = false
+		if err := saveQueuedMessage(m); err != nil {
+			return err
+		}
+	}
+	return os.Remove(path + queueSendingSuffix)
+}
+
+// listQueuedMessages returns every queued message ordered by send time.
+func listQueuedMessages() ([]queuedMessage, error) {
+	dir, err := config.OutboxDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out := make([]queuedMessage, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".json"+queueSendingSuffix) {
+			continue
+		}
+		m, err := readQueuedFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue // claimed or finished by a concurrent run meanwhile
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].SendAt.Equal(out[j].SendAt) {
+			return out[i].SendAt.Before(out[j].SendAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// parseSendAt parses --send-at; an empty value means "send now".
+func parseSendAt(raw string, now time.Time) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		var localErr error
+		for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+			if t, localErr = time.ParseInLocation(layout, raw, time.Local); localErr == nil {
+				break
+			}
+		}
+		if localErr != nil {
+			return time.Time{}, usagef("invalid --send-at %q (use RFC3339, e.g. 2025-07-01T09:00:00Z, or YYYY-MM-DD HH:MM)", raw)
+		}
+	}
+	if !t.After(now) {
+		return time.Time{}, usagef("--send-at %s is not in the future", t.Format(time.RFC3339))
+	}
+	return t, nil
+}
+
+func enqueueGmailSend(ctx context.Context, m queuedMessage) error {
+	now := queueNow()
+	m.ID = newQueueID(now)
+	m.CreatedAt = now.UTC()
+	m.SendAt = m.SendAt.UTC()
+	if err := saveQueuedMessage(m); err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"queued": true,
+			"id":     m.ID,
+			"sendAt": m.SendAt,
+		})
+	}
+	u := ui.FromContext(ctx)
+	u.Out().Printf("queued\t%s", m.ID)
+	u.Out().Printf("send_at\t%s", m.SendAt.Local().Format(time.RFC3339))
+	u.Err().Println("Run `gog queue run` (e.g. from cron) to send due messages")
+	return nil
+}
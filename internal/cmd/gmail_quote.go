@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"html"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// gmailQuoteStyle is the inline style Gmail's web UI puts on reply blockquotes.
+const gmailQuoteStyle = "margin:0px 0px 0px 0.8ex;border-left:1px solid rgb(204,204,204);padding-left:1ex"
+
+var htmlBodyRe = regexp.MustCompile(`(?is)<body[^>]*>(.*)</body>`)
+
+// quotedReplyHTML fetches the message being replied to and returns
+// bodyHTML followed by the original wrapped in Gmail's gmail_quote
+// structure, which Gmail (and most clients) render collapsed.
+func quotedReplyHTML(ctx context.Context, svc *gmail.Service, messageID string, bodyHTML string) (string, error) {
+	msg, err := svc.Users.Messages.Get("me", strings.TrimSpace(messageID)).Format("full").Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	return bodyHTML + gmailQuoteBlock(msg), nil
+}
+
+// gmailQuoteBlock renders the "On <date> <sender> wrote:" attribution and
+// the original body (HTML, or escaped plain text) as Gmail's UI does.
+func gmailQuoteBlock(msg *gmail.Message) string {
+	original := findPartBody(msg.Payload, "text/html")
+	if m := htmlBodyRe.FindStringSubmatch(original); m != nil {
+		original = m[1]
+	}
+	if strings.TrimSpace(original) == "" {
+		plain := strings.ReplaceAll(findPartBody(msg.Payload, "text/plain"), "\r\n", "\n")
+		original = strings.ReplaceAll(html.EscapeString(plain), "\n", "<br>\n")
+	}
+
+	var b strings.Builder
+	b.WriteString(`<br><div class="gmail_quote"><div dir="ltr" class="gmail_attr">`)
+	b.WriteString(html.EscapeString(quoteAttribution(headerValue(msg.Payload, "Date"), headerValue(msg.Payload, "From"))))
+	b.WriteString(`<br></div><blockquote class="gmail_quote" style="` + gmailQuoteStyle + `">`)
+	b.WriteString(original)
+	b.WriteString(`</blockquote></div>`)
+	return b.String()
+}
+
+// quoteAttribution formats "On Mon, Jan 2, 2006 at 3:04 PM Ada <ada@x> wrote:".
+func quoteAttribution(date string, from string) string {
+	from = strings.TrimSpace(from)
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if addr.Name != "" {
+			from = addr.Name + " <" + addr.Address + ">"
+		} else {
+			from = "<" + addr.Address + ">"
+		}
+	}
+	date = strings.TrimSpace(date)
+	if t, err := mailParseDate(date); err == nil {
+		date = t.Format("Mon, Jan 2, 2006 at 3:04 PM")
+	}
+	switch {
+	case date != "" && from != "":
+		return "On " + date + " " + from + " wrote:"
+	case from != "":
+		return from + " wrote:"
+	default:
+		return "Original message:"
+	}
+}
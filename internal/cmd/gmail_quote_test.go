@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestQuoteAttribution(t *testing.T) {
+	got := quoteAttribution("Tue, 3 Jun 2025 14:05:00 +0000", `"Ada Lovelace" <ada@example.com>`)
+	if got != "On Tue, Jun 3, 2025 at 2:05 PM Ada Lovelace <ada@example.com> wrote:" {
+		t.Fatalf("got %q", got)
+	}
+	if got := quoteAttribution("", "ada@example.com"); got != "<ada@example.com> wrote:" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestGmailQuoteBlock(t *testing.T) {
+	enc := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	msg := &gmail.Message{Payload: &gmail.MessagePart{
+		MimeType: "multipart/alternative",
+		Headers:  []*gmail.MessagePartHeader{{Name: "From", Value: "Ada <ada@example.com>"}},
+		Parts: []*gmail.MessagePart{
+			{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: enc("hi <there>")}},
+			{MimeType: "text/html", Body: &gmail.MessagePartBody{Data: enc("<html><body style=\"x\"><p><b>hi</b></p></body></html>")}},
+		},
+	}}
+	got := gmailQuoteBlock(msg)
+	if !strings.Contains(got, `<div class="gmail_quote"><div dir="ltr" class="gmail_attr">Ada &lt;ada@example.com&gt; wrote:<br></div>`) {
+		t.Fatalf("missing attribution: %q", got)
+	}
+	if !strings.Contains(got, `<blockquote class="gmail_quote" style="`+gmailQuoteStyle+`"><p><b>hi</b></p></blockquote></div>`) {
+		t.Fatalf("missing original html: %q", got)
+	}
+
+	msg.Payload.Parts = msg.Payload.Parts[:1]
+	if got := gmailQuoteBlock(msg); !strings.Contains(got, "hi &lt;there&gt;</blockquote>") {
+		t.Fatalf("expected escaped plain fallback: %q", got)
+	}
+}
+
+func TestExecute_GmailSend_QuoteHTML(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+
+	var sentRaw string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages/m0"):
+			payload := map[string]any{
+				"headers": []map[string]any{
+					{"name": "Message-ID", "value": "<orig@id>"},
+					{"name": "From", "value": "Ada <ada@example.com>"},
+				},
+			}
+			if r.URL.Query().Get("format") == "full" {
+				payload["mimeType"] = "text/html"
+				payload["body"] = map[string]any{"data": base64.RawURLEncoding.EncodeToString([]byte("<p>original</p>"))}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m0", "threadId": "t0", "payload": payload})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/messages/send"):
+			body, _ := io.ReadAll(r.Body)
+			var msg gmail.Message
+			_ = json.Unmarshal(body, &msg)
+			raw, _ := base64.RawURLEncoding.DecodeString(msg.Raw)
+			sentRaw = string(raw)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1", "threadId": "t0"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "send", "--to", "ada@example.com", "--subject", "Re: x",
+				"--body", "thanks", "--body-html", "<p>thanks</p>", "--reply-to-message-id", "m0", "--quote-html"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(sentRaw, `<p>thanks</p><br><div class="gmail_quote">`) || !strings.Contains(sentRaw, "<p>original</p></blockquote>") {
+		t.Fatalf("unexpected raw:\n%s", sentRaw)
+	}
+
+	err = Execute([]string{"--account", "a@b.com", "gmail", "send", "--to", "ada@example.com", "--subject", "S", "--body", "B", "--quote-html"})
+	if err == nil || !strings.Contains(err.Error(), "--quote-html requires") {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
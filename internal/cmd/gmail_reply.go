@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/gmail/v1"
+)
+
+// replyComputedFlags are set by gmail reply and hidden from its help.
+var replyComputedFlags = []string{"to", "reply-to-message-id"}
+
+func newGmailReplyCmd(flags *rootFlags) *cobra.Command {
+	var all bool
+	// reply runs gmail send with computed recipients; sharing its flag set
+	// keeps every send option (--dry-run, --attach, --quote-html, ...).
+	send := newGmailSendCmd(flags)
+
+	cmd := &cobra.Command{
+		Use:   "reply <messageId>",
+		Short: "Reply to a message (recipients and threading from the original)",
+		Long: `Reply to a message. The reply goes to the original Reply-To (else From);
+for a message you sent, it goes back to the original To. --all also Ccs the
+original To and Cc recipients. Your own addresses (the account and send-as
+aliases) are removed. In-Reply-To, References and the thread are set from
+the original, and the subject gets a "Re: " prefix (config gmail.replyPrefix)
+unless --subject is given; localized prefixes such as AW:, SV: or RES: are
+replaced rather than stacked.
+
+--cc adds recipients on top of the computed ones. All gmail send flags
+(--body, --body-html, --body-md, --attach, --quote-html, --dry-run, ...)
+work the same way; with --dry-run threading headers are not resolved.`,
+		Example: `  gog gmail reply <messageId> --body "Thanks!"
+  gog gmail reply <messageId> --all --body-md reply.md`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			messageID := strings.TrimSpace(args[0])
+			if messageID == "" {
+				return usage("empty messageId")
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			orig, err := svc.Users.Messages.Get("me", messageID).
+				Format("metadata").
+				MetadataHeaders("From", "Reply-To", "To", "Cc", "Subject").
+				Context(cmd.Context()).
+				Do()
+			if err != nil {
+				return err
+			}
+
+			from := send.Flags().Lookup("from").Value.String()
+			if alias := send.Flags().Lookup("from-alias").Value.String(); alias != "" {
+				from = alias
+			}
+			self := replySelfAddresses(cmd.Context(), svc, account, from)
+			to, cc := replyRecipients(orig.Payload, self, all)
+			if len(to) == 0 {
+				return usage("could not determine reply recipients from the original message")
+			}
+			cc = append(cc, splitCSV(send.Flags().Lookup("cc").Value.String())...)
+
+			set := map[string]string{
+				"to":                  strings.Join(to, ", "),
+				"cc":                  strings.Join(cc, ", "),
+				"reply-to-message-id": messageID,
+			}
+			if !cmd.Flags().Changed("subject") {
+				set["subject"] = replySubject(headerValue(orig.Payload, "Subject"))
+			}
+			for name, value := range set {
+				if err := send.Flags().Set(name, value); err != nil {
+					return err
+				}
+			}
+			send.SetContext(cmd.Context())
+			return send.RunE(send, nil)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Reply to all: also Cc the original To and Cc recipients")
+	cmd.Flags().AddFlagSet(send.Flags())
+	for _, name := range replyComputedFlags {
+		_ = cmd.Flags().MarkHidden(name)
+	}
+	cmd.Flags().Lookup("subject").Usage = "Subject (default: \"Re: \" or gmail.replyPrefix + the original subject)"
+	cmd.Flags().Lookup("cc").Usage = "Additional CC recipients (comma-separated)"
+	return cmd
+}
+
+// replyRecipients computes To and Cc for a reply to the message with
+// header part p. self holds lowercased addresses that must not be
+// addressed; when the original came from self, the reply goes to its To.
+func replyRecipients(p *gmail.MessagePart, self map[string]bool, all bool) (to, cc []string) {
+	from := parseAddressHeader(headerValue(p, "From"))
+	origTo := parseAddressHeader(headerValue(p, "To"))
+	origCc := parseAddressHeader(headerValue(p, "Cc"))
+
+	fromSelf := len(from) > 0 && self[strings.ToLower(from[0].Address)]
+	primary := parseAddressHeader(headerValue(p, "Reply-To"))
+	if len(primary) == 0 {
+		primary = from
+	}
+	if fromSelf {
+		primary = origTo
+	}
+
+	seen := map[string]bool{}
+	add := func(list []string, addrs []*mail.Address) []string {
+		for _, a := range addrs {
+			key := strings.ToLower(a.Address)
+			if key == "" || self[key] || seen[key] {
+				continue
+			}
+			seen[key] = true
+			list = append(list, formatReplyAddress(a))
+		}
+		return list
+	}
+	to = add(nil, primary)
+	if all {
+		if !fromSelf {
+			cc = add(cc, origTo)
+		}
+		cc = add(cc, origCc)
+	}
+	return to, cc
+}
+
+// parseAddressHeader parses an address list, falling back to bare
+// comma-separated addresses when the header isn't RFC 5322 clean.
+func parseAddressHeader(v string) []*mail.Address {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil
+	}
+	if list, err := mail.ParseAddressList(v); err == nil {
+		return list
+	}
+	var out []*mail.Address
+	for _, s := range splitCSV(v) {
+		if addr := senderAddress(s); strings.Contains(addr, "@") {
+			out = append(out, &mail.Address{Address: addr})
+		}
+	}
+	return out
+}
+
+// formatReplyAddress keeps the display name unless it contains a comma,
+// which the comma-separated --to/--cc flags can't carry.
+func formatReplyAddress(a *mail.Address) string {
+	if a.Name == "" || strings.Contains(a.Name, ",") {
+		return a.Address
+	}
+	return a.String()
+}
+
+// replySelfAddresses is the account, its send-as aliases and --from.
+func replySelfAddresses(ctx context.Context, svc *gmail.Service, account, from string) map[string]bool {
+	self := map[string]bool{strings.ToLower(account): true}
+	if from = strings.TrimSpace(from); from != "" {
+		self[senderAddress(from)] = true
+	}
+	if resp, err := svc.Users.Settings.SendAs.List("me").Context(ctx).Do(); err == nil {
+		for _, sa := range resp.SendAs {
+			if sa != nil && sa.SendAsEmail != "" {
+				self[strings.ToLower(sa.SendAsEmail)] = true
+			}
+		}
+	}
+	return self
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func replyTestPart(headers map[string]string) *gmail.MessagePart {
+	p := &gmail.MessagePart{}
+	for k, v := range headers {
+		p.Headers = append(p.Headers, &gmail.MessagePartHeader{Name: k, Value: v})
+	}
+	return p
+}
+
+func TestReplyRecipients(t *testing.T) {
+	self := map[string]bool{"me@example.com": true, "alias@example.com": true}
+	orig := replyTestPart(map[string]string{
+		"From": "Ana <ana@example.com>",
+		"To":   "me@example.com, Bob <bob@example.com>",
+		"Cc":   "\"Doe, Jane\" <jane@example.com>, ALIAS@example.com, ana@example.com",
+	})
+
+	to, cc := replyRecipients(orig, self, false)
+	if !reflect.DeepEqual(to, []string{`"Ana" <ana@example.com>`}) || cc != nil {
+		t.Fatalf("reply = %v / %v", to, cc)
+	}
+	to, cc = replyRecipients(orig, self, true)
+	if !reflect.DeepEqual(cc, []string{`"Bob" <bob@example.com>`, "jane@example.com"}) || len(to) != 1 {
+		t.Fatalf("reply all = %v / %v", to, cc)
+	}
+
+	withReplyTo := replyTestPart(map[string]string{"From": "ana@example.com", "Reply-To": "list@example.com", "To": "me@example.com"})
+	if to, _ := replyRecipients(withReplyTo, self, false); !reflect.DeepEqual(to, []string{"list@example.com"}) {
+		t.Fatalf("reply-to = %v", to)
+	}
+
+	// Replying to my own sent message goes back to its recipients.
+	sent := replyTestPart(map[string]string{"From": "me@example.com", "To": "bob@example.com", "Cc": "carol@example.com"})
+	to, cc = replyRecipients(sent, self, true)
+	if !reflect.DeepEqual(to, []string{"bob@example.com"}) || !reflect.DeepEqual(cc, []string{"carol@example.com"}) {
+		t.Fatalf("own message = %v / %v", to, cc)
+	}
+
+	if got := replySubject("RE: Plans"); got != "Re: Plans" {
+		t.Fatalf("subject = %q", got)
+	}
+	if got := replySubject("Plans"); got != "Re: Plans" {
+		t.Fatalf("subject = %q", got)
+	}
+}
+
+func TestExecute_GmailReplyAll_DryRun(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	t.Setenv("GOG_GMAIL_ALLOWLIST", "example.com")
+	t.Setenv("GOG_GMAIL_REQUIRE_ARM", "1")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/settings/sendAs"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"sendAs": []map[string]any{{"sendAsEmail": "a@b.com"}, {"sendAsEmail": "alias@example.com"}}})
+		case strings.HasSuffix(r.URL.Path, "/messages/m1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1", "threadId": "t1", "payload": map[string]any{"headers": []map[string]any{
+				{"name": "From", "value": "ana@example.com"},
+				{"name": "To", "value": "alias@example.com, bob@example.com"},
+				{"name": "Cc", "value": "carol@example.com"},
+				{"name": "Subject", "value": "Plans"},
+			}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "reply", "m1", "--all", "--cc", "dave@example.com", "--body", "Sounds good", "--dry-run"}); err != nil {
+				t.Fatalf("reply: %v", err)
+			}
+		})
+	})
+	for _, want := range []string{"To: ana@example.com\r\n", "Cc: bob@example.com, carol@example.com, dave@example.com\r\n", "Subject: Re: Plans\r\n", "Sounds good"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
@@ -218,7 +218,10 @@ func blockedRecipients(allowlist *gmailAllowlist, recipients []string) []string
 	return blocked
 }
 
-func checkGmailAllowlist(u *ui.UI, recipients []string) error {
+func checkGmailAllowlist(u *ui.UI, account string, recipients []string) error {
+	if err := checkPolicyRecipients(account, recipients); err != nil {
+		return err
+	}
 	mode, err := gmailAllowlistMode()
 	if err != nil {
 		return err
@@ -250,9 +253,17 @@ func checkGmailAllowlist(u *ui.UI, recipients []string) error {
 	return errors.New(msg)
 }
 
-func requireGmailSendArm() error {
+// requireGmailSendArm enforces the send arm when GOG_GMAIL_REQUIRE_ARM=1 or
+// policy.yaml sets gmail.requireArm for account.
+func requireGmailSendArm(account string) error {
 	if strings.TrimSpace(os.Getenv("GOG_GMAIL_REQUIRE_ARM")) != "1" {
-		return nil
+		p, err := loadPolicy(account)
+		if err != nil {
+			return err
+		}
+		if p == nil || !p.requireArm {
+			return nil
+		}
 	}
 	if strings.TrimSpace(os.Getenv("GOG_GMAIL_SEND_ARMED")) == "1" {
 		return nil
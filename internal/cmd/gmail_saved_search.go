@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+var savedSearchNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// savedSearchRow is one saved search in list output.
+type savedSearchRow struct {
+	Name string `json:"name"`
+	config.SavedSearch
+}
+
+func newGmailSavedSearchCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "saved-search",
+		Aliases: []string{"saved"},
+		Short:   "Named gmail search queries kept in config.json",
+		Long: `Save operator-heavy queries under a name instead of in shell history.
+Saved searches live in config.json (gmail.savedSearches) with the gmail
+search flags they run with by default, e.g. --max or --group-by.`,
+	}
+	cmd.AddCommand(newGmailSavedSearchAddCmd())
+	cmd.AddCommand(newGmailSavedSearchListCmd())
+	cmd.AddCommand(newGmailSavedSearchRunCmd(flags))
+	cmd.AddCommand(newGmailSavedSearchRemoveCmd())
+	return cmd
+}
+
+func loadSavedSearches() (map[string]config.SavedSearch, error) {
+	cfg, err := config.ReadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Gmail.SavedSearches == nil {
+		return map[string]config.SavedSearch{}, nil
+	}
+	return cfg.Gmail.SavedSearches, nil
+}
+
+// flagValueString renders a flag value so that Set accepts it again.
+func flagValueString(f *pflag.Flag) string {
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		return strings.Join(sv.GetSlice(), ",")
+	}
+	return f.Value.String()
+}
+
+func newGmailSavedSearchAddCmd() *cobra.Command {
+	var description string
+	// The search command only lends its flags (and their parsing) to add.
+	search := newGmailSearchCmd(&rootFlags{})
+
+	cmd := &cobra.Command{
+		Use:   "add <name> <query>",
+		Short: "Save (or replace) a named search with default search flags",
+		Long: `Save a gmail search query under a name. Any gmail search flag given here
+(--max, --group-by, --unread-only, --category, --local, ...) is stored and
+applied whenever the search is run; flags given to run override them.`,
+		Example: `  gog gmail saved-search add triage 'in:inbox -category:promotions -from:noreply' --max 50 --unread-only
+  gog gmail saved-search add senders 'newer_than:30d' --group-by sender`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			name := strings.TrimSpace(args[0])
+			if !savedSearchNameRe.MatchString(name) {
+				return usagef("invalid name %q (letters, digits, '.', '_' and '-')", name)
+			}
+			query := strings.TrimSpace(strings.Join(args[1:], " "))
+			if query == "" {
+				return usage("query is required")
+			}
+
+			s := config.SavedSearch{Query: query, Description: strings.TrimSpace(description)}
+			cmd.Flags().Visit(func(f *pflag.Flag) {
+				if search.Flags().Lookup(f.Name) == nil {
+					return // --description
+				}
+				if s.Flags == nil {
+					s.Flags = map[string]string{}
+				}
+				s.Flags[f.Name] = flagValueString(f)
+			})
+			if g, ok := s.Flags["group-by"]; ok {
+				if _, err := validateSearchGroupBy(g); err != nil {
+					return err
+				}
+			}
+
+			searches, err := loadSavedSearches()
+			if err != nil {
+				return err
+			}
+			_, replaced := searches[name]
+			searches[name] = s
+			if err := config.SetGmailSavedSearches(searches); err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"saved": savedSearchRow{Name: name, SavedSearch: s}, "replaced": replaced})
+			}
+			if replaced {
+				u.Out().Printf("Replaced saved search %s", name)
+			} else {
+				u.Out().Printf("Saved search %s", name)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().AddFlagSet(search.Flags())
+	cmd.Flags().StringVar(&description, "description", "", "What the search is for (shown by list)")
+	return cmd
+}
+
+func newGmailSavedSearchListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List saved searches",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			searches, err := loadSavedSearches()
+			if err != nil {
+				return err
+			}
+			rows := make([]savedSearchRow, 0, len(searches))
+			for name, s := range searches {
+				rows = append(rows, savedSearchRow{Name: name, SavedSearch: s})
+			}
+			sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"savedSearches": rows})
+			}
+			if len(rows) == 0 {
+				u.Err().Println("No saved searches (add one with gog gmail saved-search add)")
+				return nil
+			}
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "NAME\tQUERY\tFLAGS\tDESCRIPTION")
+			for _, r := range rows {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, r.Query, orDash(savedSearchFlagsText(r.Flags)), orDash(r.Description))
+			}
+			return nil
+		},
+	}
+}
+
+// savedSearchFlagsText renders stored flags as "--max=50 --unread-only".
+func savedSearchFlagsText(flags map[string]string) string {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		if v := flags[name]; v != "true" {
+			parts = append(parts, "--"+name+"="+v)
+		} else {
+			parts = append(parts, "--"+name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func newGmailSavedSearchRunCmd(flags *rootFlags) *cobra.Command {
+	search := newGmailSearchCmd(flags)
+
+	cmd := &cobra.Command{
+		Use:   "run <name> [extra query terms...]",
+		Short: "Run a saved search (gmail search flags override the saved ones)",
+		Long: `Run a saved search like gmail search, with its stored flags. Flags given
+here override them; extra arguments are ANDed to the saved query.`,
+		Example: `  gog gmail saved-search run triage
+  gog gmail saved-search run triage newer_than:1d --max 5`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searches, err := loadSavedSearches()
+			if err != nil {
+				return err
+			}
+			s, ok := searches[args[0]]
+			if !ok {
+				return usagef("no saved search %q (see gog gmail saved-search list)", args[0])
+			}
+			for name, value := range s.Flags {
+				f := search.Flags().Lookup(name)
+				if f == nil {
+					return fmt.Errorf("saved search %q: unknown flag --%s", args[0], name)
+				}
+				if f.Changed {
+					continue
+				}
+				if err := f.Value.Set(value); err != nil {
+					return fmt.Errorf("saved search %q: --%s: %w", args[0], name, err)
+				}
+			}
+			query := s.Query
+			if extra := strings.TrimSpace(strings.Join(args[1:], " ")); extra != "" {
+				query = "(" + query + ") " + extra
+			}
+			search.SetContext(cmd.Context())
+			return search.RunE(search, []string{query})
+		},
+	}
+	cmd.Flags().AddFlagSet(search.Flags())
+	return cmd
+}
+
+func newGmailSavedSearchRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a saved search",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			searches, err := loadSavedSearches()
+			if err != nil {
+				return err
+			}
+			if _, ok := searches[args[0]]; !ok {
+				return usagef("no saved search %q", args[0])
+			}
+			delete(searches, args[0])
+			if err := config.SetGmailSavedSearches(searches); err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"removed": args[0]})
+			}
+			u.Out().Printf("Removed saved search %s", args[0])
+			return nil
+		},
+	}
+}
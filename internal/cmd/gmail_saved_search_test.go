@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_GmailSavedSearch(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var gotQ, gotMax string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/messages"):
+			gotQ, gotMax = r.URL.Query().Get("q"), r.URL.Query().Get("maxResults")
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{}})
+		case strings.Contains(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	run := func(args ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(args); err != nil {
+					t.Fatalf("Execute %v: %v", args, err)
+				}
+			})
+		})
+	}
+
+	run("gmail", "saved-search", "add", "senders", "from:ada -in:chats", "--max", "50", "--group-by", "message", "--unread-only", "--description", "Ada mail")
+
+	out := run("--json", "gmail", "saved-search", "list")
+	var listed struct {
+		SavedSearches []struct {
+			Name        string            `json:"name"`
+			Query       string            `json:"query"`
+			Flags       map[string]string `json:"flags"`
+			Description string            `json:"description"`
+		} `json:"savedSearches"`
+	}
+	if err := json.Unmarshal([]byte(out), &listed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if len(listed.SavedSearches) != 1 {
+		t.Fatalf("unexpected list: %#v", listed)
+	}
+	s := listed.SavedSearches[0]
+	if s.Name != "senders" || s.Query != "from:ada -in:chats" || s.Description != "Ada mail" ||
+		s.Flags["max"] != "50" || s.Flags["group-by"] != "message" || s.Flags["unread-only"] != "true" || len(s.Flags) != 3 {
+		t.Fatalf("unexpected saved search: %#v", s)
+	}
+	if out := run("gmail", "saved-search", "list"); !strings.Contains(out, "--group-by=message --max=50 --unread-only") {
+		t.Fatalf("unexpected table: %q", out)
+	}
+
+	run("--account", "a@b.com", "--json", "gmail", "saved-search", "run", "senders")
+	if gotQ != "(from:ada -in:chats) is:unread" || gotMax != "50" {
+		t.Fatalf("run: q=%q max=%q", gotQ, gotMax)
+	}
+
+	run("--account", "a@b.com", "--json", "gmail", "saved-search", "run", "senders", "newer_than:1d", "--max", "5")
+	if gotQ != "((from:ada -in:chats) newer_than:1d) is:unread" || gotMax != "5" {
+		t.Fatalf("run with overrides: q=%q max=%q", gotQ, gotMax)
+	}
+
+	run("gmail", "saved-search", "remove", "senders")
+	if err := Execute([]string{"--account", "a@b.com", "gmail", "saved-search", "run", "senders"}); err == nil {
+		t.Fatalf("expected error for removed search")
+	}
+}
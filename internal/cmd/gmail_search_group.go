@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+// Search --group-by modes. "thread" is the historical one-row-per-conversation view.
+const (
+	searchGroupThread  = "thread"
+	searchGroupMessage = "message"
+	searchGroupSender  = "sender"
+	searchGroupDay     = "day"
+)
+
+func validateSearchGroupBy(v string) (string, error) {
+	switch v = strings.ToLower(strings.TrimSpace(v)); v {
+	case "", searchGroupThread:
+		return searchGroupThread, nil
+	case searchGroupMessage, searchGroupSender, searchGroupDay:
+		return v, nil
+	default:
+		return "", usagef("invalid --group-by %q (expected thread|message|sender|day)", v)
+	}
+}
+
+// messageItem holds parsed message metadata for message-level search output.
+type messageItem struct {
+	ID       string    `json:"id"`
+	ThreadID string    `json:"threadId,omitempty"`
+	Date     string    `json:"date,omitempty"`
+	From     string    `json:"from,omitempty"`
+	Subject  string    `json:"subject,omitempty"`
+	Labels   []string  `json:"labels,omitempty"`
+	Category string    `json:"category,omitempty"`
+	Snippet  string    `json:"snippet,omitempty"`
+	Unread   bool      `json:"unread"`
+	internal time.Time // server receive time, used for grouping
+	rfcID    string    // Message-ID header, used for dedup
+}
+
+// searchGroup is one aggregated row for --group-by sender|day.
+type searchGroup struct {
+	Key           string `json:"key"`
+	Messages      int    `json:"messages"`
+	Threads       int    `json:"threads"`
+	LatestDate    string `json:"latestDate,omitempty"`
+	LatestSubject string `json:"latestSubject,omitempty"`
+	latest        time.Time
+	threadIDs     map[string]struct{}
+}
+
+// fetchMessageDetails fetches message metadata with the same bounded
+// parallelism as fetchThreadDetails.
+func fetchMessageDetails(ctx context.Context, svc *gmail.Service, msgs []*gmail.Message, idToName map[string]string) ([]messageItem, error) {
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+
+	const maxConcurrency = 10
+	sem := make(chan struct{}, maxConcurrency)
+
+	type result struct {
+		index int
+		item  messageItem
+		err   error
+	}
+
+	results := make(chan result, len(msgs))
+	var wg sync.WaitGroup
+
+	for i, m := range msgs {
+		if m == nil || m.Id == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(idx int, id string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results <- result{index: idx, err: ctx.Err()}
+				return
+			}
+
+			msg, err := svc.Users.Messages.Get("me", id).
+				Format("metadata").
+				MetadataHeaders("From", "Subject", "Date", "Message-ID").
+				Context(ctx).
+				Do()
+			if err != nil {
+				results <- result{index: idx, err: err}
+				return
+			}
+
+			item := messageItem{
+				ID:       msg.Id,
+				ThreadID: msg.ThreadId,
+				Date:     formatGmailDate(headerValue(msg.Payload, "Date")),
+				From:     sanitizeTab(headerValue(msg.Payload, "From")),
+				Subject:  sanitizeTab(headerValue(msg.Payload, "Subject")),
+				Labels:   labelNames(msg.LabelIds, idToName),
+				Category: categoryOf(msg.LabelIds),
+				Snippet:  gmailSnippet(msg.Snippet),
+				Unread:   hasLabel(msg.LabelIds, "UNREAD"),
+				rfcID:    strings.TrimSpace(headerValue(msg.Payload, "Message-ID")),
+			}
+			if msg.InternalDate > 0 {
+				item.internal = time.UnixMilli(msg.InternalDate)
+			}
+			results <- result{index: idx, item: item}
+		}(i, m.Id)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	items := make([]messageItem, len(msgs))
+	for r := range results {
+		if r.err != nil {
+			return nil, r.err
+		}
+		items[r.index] = r.item
+	}
+
+	filtered := make([]messageItem, 0, len(items))
+	for _, item := range items {
+		if item.ID != "" {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+func labelNames(ids []string, idToName map[string]string) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if n, ok := idToName[id]; ok {
+			names = append(names, n)
+		} else {
+			names = append(names, id)
+		}
+	}
+	return names
+}
+
+// dedupeMessages drops copies of the same RFC822 message (same Message-ID),
+// e.g. mail sent to yourself or delivered via several aliases.
+func dedupeMessages(items []messageItem) []messageItem {
+	seen := make(map[string]struct{}, len(items))
+	out := items[:0]
+	for _, it := range items {
+		if it.rfcID != "" {
+			if _, ok := seen[it.rfcID]; ok {
+				continue
+			}
+			seen[it.rfcID] = struct{}{}
+		}
+		out = append(out, it)
+	}
+	return out
+}
+
+// groupMessages aggregates messages by sender address (busiest first) or by
+// local day (newest first).
+func groupMessages(items []messageItem, by string) []searchGroup {
+	groups := make(map[string]*searchGroup)
+	order := make([]string, 0)
+	for _, it := range items {
+		var key string
+		switch by {
+		case searchGroupSender:
+			key = normalizeEmailAddress(it.From)
+		case searchGroupDay:
+			if !it.internal.IsZero() {
+				key = it.internal.Local().Format("2006-01-02")
+			} else if len(it.Date) >= len("2006-01-02") {
+				key = it.Date[:len("2006-01-02")]
+			}
+		}
+		if key == "" {
+			key = "(unknown)"
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &searchGroup{Key: key, threadIDs: map[string]struct{}{}}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.Messages++
+		if it.ThreadID != "" {
+			g.threadIDs[it.ThreadID] = struct{}{}
+		}
+		if g.LatestDate == "" || it.internal.After(g.latest) {
+			g.latest = it.internal
+			g.LatestDate = it.Date
+			g.LatestSubject = it.Subject
+		}
+	}
+
+	out := make([]searchGroup, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		g.Threads = len(g.threadIDs)
+		out = append(out, *g)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if by == searchGroupDay {
+			return out[i].Key > out[j].Key
+		}
+		if out[i].Messages != out[j].Messages {
+			return out[i].Messages > out[j].Messages
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func runGmailMessageSearch(ctx context.Context, svc *gmail.Service, query string, max int64, page string, pages pageFlags, groupBy string, preview int) error {
+	refs, nextPageToken, err := fetchPages(ctx, pages, page, func(pageToken string) ([]*gmail.Message, string, error) {
+		resp, err := svc.Users.Messages.List("me").
+			Q(query).
+			MaxResults(max).
+			PageToken(pageToken).
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Messages, resp.NextPageToken, nil
+	})
+	partial, err := splitPageError(ctx, err, len(refs))
+	if err != nil {
+		return err
+	}
+
+	idToName, err := fetchLabelIDToName(ctx, svc)
+	if err != nil {
+		return err
+	}
+
+	items, err := fetchMessageDetails(ctx, svc, refs, idToName)
+	if err != nil {
+		return err
+	}
+	if err := writeMessageSearch(ctx, dedupeMessages(items), nextPageToken, groupBy, preview); err != nil {
+		return err
+	}
+	return partial
+}
+
+// writeMessageSearch prints message rows, or sender/day groups of them.
+func writeMessageSearch(ctx context.Context, items []messageItem, nextPageToken string, groupBy string, preview int) error {
+	u := ui.FromContext(ctx)
+	if groupBy == searchGroupMessage {
+		if outfmt.IsNDJSON(ctx) {
+			return writeNDJSONRows(ctx, items, nextPageToken)
+		}
+		if outfmt.IsJSON(ctx) {
+			return outfmt.WriteJSON(os.Stdout, map[string]any{
+				"messages":      items,
+				"nextPageToken": nextPageToken,
+			})
+		}
+		if len(items) == 0 {
+			u.Err().Println("No results")
+			return nil
+		}
+		w, flush := tableWriter(ctx)
+		defer flush()
+		if preview > 0 {
+			fmt.Fprintln(w, "ID\tTHREAD\tDATE\tFROM\tSUBJECT\tLABELS\tPREVIEW\tSTATE")
+		} else {
+			fmt.Fprintln(w, "ID\tTHREAD\tDATE\tFROM\tSUBJECT\tLABELS\tSTATE")
+		}
+		for _, it := range items {
+			state := stateCell(u, it.Unread, false)
+			if preview > 0 {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", it.ID, it.ThreadID, it.Date, it.From, it.Subject, strings.Join(it.Labels, ","), previewText(it.Snippet, preview), state)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", it.ID, it.ThreadID, it.Date, it.From, it.Subject, strings.Join(it.Labels, ","), state)
+		}
+		printNextPageHint(u, nextPageToken)
+		return nil
+	}
+
+	groups := groupMessages(items, groupBy)
+	if outfmt.IsNDJSON(ctx) {
+		return writeNDJSONRows(ctx, groups, nextPageToken)
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"groupBy":       groupBy,
+			"groups":        groups,
+			"nextPageToken": nextPageToken,
+		})
+	}
+	if len(groups) == 0 {
+		u.Err().Println("No results")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintf(w, "%s\tMESSAGES\tTHREADS\tLATEST\tSUBJECT\n", strings.ToUpper(groupBy))
+	for _, g := range groups {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", sanitizeTab(g.Key), g.Messages, g.Threads, g.LatestDate, g.LatestSubject)
+	}
+	printNextPageHint(u, nextPageToken)
+	return nil
+}
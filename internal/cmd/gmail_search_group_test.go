@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupeAndGroupMessages(t *testing.T) {
+	day1 := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	day2 := day1.Add(24 * time.Hour)
+	items := []messageItem{
+		{ID: "m1", ThreadID: "t1", From: "Ada <ada@example.com>", Subject: "old", Date: "2025-03-01 12:00", internal: day1, rfcID: "<a@x>"},
+		{ID: "m2", ThreadID: "t1", From: "ada@example.com", Subject: "new", Date: "2025-03-02 12:00", internal: day2, rfcID: "<b@x>"},
+		{ID: "m3", ThreadID: "t2", From: "ADA@example.com", Subject: "dup", internal: day2, rfcID: "<b@x>"},
+		{ID: "m4", ThreadID: "t3", From: "bob@example.com", Subject: "hi", Date: "2025-03-01 09:00", internal: day1},
+	}
+
+	items = dedupeMessages(items)
+	if len(items) != 3 {
+		t.Fatalf("expected 3 after dedupe, got %d", len(items))
+	}
+
+	bySender := groupMessages(items, searchGroupSender)
+	if len(bySender) != 2 || bySender[0].Key != "ada@example.com" || bySender[0].Messages != 2 || bySender[0].Threads != 1 {
+		t.Fatalf("unexpected sender groups: %#v", bySender)
+	}
+	if bySender[0].LatestSubject != "new" {
+		t.Fatalf("expected latest subject, got %q", bySender[0].LatestSubject)
+	}
+
+	byDay := groupMessages(items, searchGroupDay)
+	if len(byDay) != 2 || byDay[0].Key != "2025-03-02" || byDay[1].Messages != 2 || byDay[1].Threads != 2 {
+		t.Fatalf("unexpected day groups: %#v", byDay)
+	}
+}
+
+func TestValidateSearchGroupBy(t *testing.T) {
+	if v, err := validateSearchGroupBy(""); err != nil || v != searchGroupThread {
+		t.Fatalf("default: %q %v", v, err)
+	}
+	if v, err := validateSearchGroupBy("Sender"); err != nil || v != searchGroupSender {
+		t.Fatalf("sender: %q %v", v, err)
+	}
+	if _, err := validateSearchGroupBy("week"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestPreviewText(t *testing.T) {
+	cases := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"line one\n  line two", 0, "line one line two"},
+		{"héllo wörld", 6, "héllo…"},
+		{"abc", 1, "…"},
+	}
+	for _, tc := range cases {
+		if got := previewText(tc.in, tc.n); got != tc.want {
+			t.Fatalf("previewText(%q, %d) = %q, want %q", tc.in, tc.n, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"github.com/muesli/termenv"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+// threadState derives read/answered state from a thread's messages.
+//
+// A thread is unread when any message carries UNREAD. It is answered when it
+// has at least one incoming message and the newest non-draft message was sent
+// by the account (SENT label); it needs a reply when the newest one is incoming.
+func threadState(t *gmail.Thread) (unread, answered, needsReply bool) {
+	if t == nil {
+		return false, false, false
+	}
+	var last *gmail.Message
+	incoming := false
+	for _, m := range t.Messages {
+		if m == nil || hasLabel(m.LabelIds, "DRAFT") {
+			continue
+		}
+		if hasLabel(m.LabelIds, "UNREAD") {
+			unread = true
+		}
+		if !hasLabel(m.LabelIds, "SENT") {
+			incoming = true
+		}
+		last = m
+	}
+	if last == nil || !incoming {
+		return unread, false, false
+	}
+	if hasLabel(last.LabelIds, "SENT") {
+		return unread, true, false
+	}
+	return unread, false, true
+}
+
+func hasLabel(ids []string, id string) bool {
+	for _, l := range ids {
+		if l == id {
+			return true
+		}
+	}
+	return false
+}
+
+// stateCell renders the STATE column, which comes last so plain/TSV
+// consumers keep their column positions. Every cell gets exactly one basic ANSI
+// color when color is on, so escape sequences have the same length in each
+// row and tabwriter alignment holds.
+func stateCell(u *ui.UI, unread, answered bool) string {
+	text, color := "-", termenv.ANSIBrightBlack
+	switch {
+	case unread && answered:
+		text, color = "unread,answered", termenv.ANSIBrightBlue
+	case unread:
+		text, color = "unread", termenv.ANSIBrightBlue
+	case answered:
+		text, color = "answered", termenv.ANSIGreen
+	}
+	if u == nil || !u.Out().ColorEnabled() {
+		return text
+	}
+	return termenv.String(text).Foreground(color).String()
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestThreadState(t *testing.T) {
+	msg := func(labels ...string) *gmail.Message { return &gmail.Message{LabelIds: labels} }
+	cases := []struct {
+		name                         string
+		thread                       *gmail.Thread
+		unread, answered, needsReply bool
+	}{
+		{"nil", nil, false, false, false},
+		{"incoming unread", &gmail.Thread{Messages: []*gmail.Message{msg("INBOX", "UNREAD")}}, true, false, true},
+		{"replied", &gmail.Thread{Messages: []*gmail.Message{msg("INBOX"), msg("SENT")}}, false, true, false},
+		{"reply draft ignored", &gmail.Thread{Messages: []*gmail.Message{msg("INBOX"), msg("DRAFT")}}, false, false, true},
+		{"outgoing only", &gmail.Thread{Messages: []*gmail.Message{msg("SENT")}}, false, false, false},
+		{"new reply after mine", &gmail.Thread{Messages: []*gmail.Message{msg("INBOX"), msg("SENT"), msg("INBOX", "UNREAD")}}, true, false, true},
+	}
+	for _, tc := range cases {
+		unread, answered, needsReply := threadState(tc.thread)
+		if unread != tc.unread || answered != tc.answered || needsReply != tc.needsReply {
+			t.Fatalf("%s: got unread=%v answered=%v needsReply=%v", tc.name, unread, answered, needsReply)
+		}
+	}
+}
+
+func TestStateCell_Color(t *testing.T) {
+	u, err := ui.New(ui.Options{Stdout: &strings.Builder{}, Stderr: &strings.Builder{}, Color: "always"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+	unread, none := stateCell(u, true, false), stateCell(u, false, false)
+	if !strings.Contains(unread, "\x1b[") || !strings.Contains(unread, "unread") {
+		t.Fatalf("expected colored cell, got %q", unread)
+	}
+	// Equal escape overhead keeps tabwriter columns aligned.
+	if len(unread)-len("unread") != len(none)-len("-") {
+		t.Fatalf("escape overhead differs: %q vs %q", unread, none)
+	}
+	if got := stateCell(nil, false, true); got != "answered" {
+		t.Fatalf("unexpected plain cell %q", got)
+	}
+}
+
+func TestExecute_GmailSearch_StateFilters(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	threads := map[string][]map[string]any{
+		"t1": {{"id": "m1", "labelIds": []string{"INBOX", "UNREAD"}}},
+		"t2": {{"id": "m2", "labelIds": []string{"INBOX"}}, {"id": "m3", "labelIds": []string{"SENT"}}},
+	}
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(path, "/users/me/threads"):
+			gotQuery = r.URL.Query().Get("q")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"threads": []map[string]any{{"id": "t1"}, {"id": "t2"}},
+			})
+		case strings.Contains(path, "/users/me/threads/"):
+			id := path[strings.LastIndex(path, "/")+1:]
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "messages": threads[id]})
+		case strings.Contains(path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "search", "in:inbox"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "STATE") || !strings.Contains(out, "unread") || !strings.Contains(out, "answered") {
+		t.Fatalf("unexpected out=%q", out)
+	}
+
+	// STATE is appended, so plain/TSV consumers keep their column positions.
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--plain", "--account", "a@b.com", "gmail", "search", "in:inbox"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || lines[0] != "ID\tMSGS\tDATE\tFROM\tSUBJECT\tLABELS\tSTATE" {
+		t.Fatalf("unexpected plain out=%q", out)
+	}
+	if fields := strings.Split(lines[1], "\t"); fields[0] != "t1" || fields[1] != "1" || fields[len(fields)-1] != "unread" {
+		t.Fatalf("unexpected row %q", lines[1])
+	}
+
+	out = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "search", "in:inbox", "--unread-only", "--unanswered-only"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if gotQuery != "(in:inbox) is:unread" {
+		t.Fatalf("unexpected query %q", gotQuery)
+	}
+	var parsed struct {
+		Threads []struct {
+			ID           string `json:"id"`
+			Unread       bool   `json:"unread"`
+			MessageCount int    `json:"messageCount"`
+		} `json:"threads"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if len(parsed.Threads) != 1 || parsed.Threads[0].ID != "t1" || !parsed.Threads[0].Unread || parsed.Threads[0].MessageCount != 1 {
+		t.Fatalf("unexpected threads: %#v", parsed.Threads)
+	}
+
+	err = Execute([]string{"--account", "a@b.com", "gmail", "search", "x", "--group-by", "sender", "--unanswered-only"})
+	if err == nil || !strings.Contains(err.Error(), "--unanswered-only") {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"encoding/base64"
-	"fmt"
+	"mime"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -19,17 +21,80 @@ func newGmailSendCmd(flags *rootFlags) *cobra.Command {
 	var subject string
 	var body string
 	var bodyHTML string
+	var bodyMD string
 	var replyToMessageID string
 	var replyTo string
 	var attach []string
+	var inlineSpecs []string
+	var tracking trackingFlag
+	var pgp pgpFlag
 	var from string
+	var fromAlias string
+	var withSignature bool
+	var dryRun gmailDryRun
+	var tmpl mailTemplate
+	var sendAt string
+	var verify bool
+	var noSendAsRules bool
+	var quoteHTML bool
+	var labelOnSend []string
 
 	cmd := &cobra.Command{
 		Use:   "send",
 		Short: "Send an email",
 		Long: `Send an email. Use --from to send from a configured send-as alias.
 
-To see available send-as aliases: gog gmail sendas list`,
+To see available send-as aliases: gog gmail sendas list
+
+Without --from, config.json gmail.sendAsByDomain rules pick the alias from
+the To recipients' domains (e.g. "client.com": "consulting@me.com");
+--no-send-as-rules disables them.
+
+With --dry-run the RFC822 message is built and printed (or written to --out)
+after allowlist checks, without calling the Gmail API. --from is not validated
+and --reply-to-message-id is not resolved in that mode.
+
+With --template, subject and bodies come from a Go text/template file that
+defines "subject", "body", and/or "html" blocks (text outside any block is the
+plain body). Variables come from --vars key=value and --vars-file vars.json and
+are referenced as {{.key}}; use {{.key | html}} inside HTML. Unknown keys fail.
+
+  gog gmail send --to ada@example.com --template welcome.tmpl --vars name=Ada
+
+With --send-at the built message is stored in the local outbox instead of
+being sent; "gog queue run" sends it once the time has passed.
+
+--verify-recipients looks every recipient up in contacts, other contacts and
+recent mail and warns (without blocking) about unknown addresses, suggesting
+the closest match for likely typos such as @gamil.com.
+
+--body-md renders a Markdown file (headings, lists, tables, code blocks,
+links) to inline-styled HTML and derives the plain-text part from it unless
+--body is also given.
+
+--inline path.png[:cid] embeds an image for <img src="cid:..."> in the HTML
+body (multipart/related) instead of attaching it; the cid defaults to the
+file name.
+
+--quote-html (with --reply-to-message-id and --body-html) appends the original
+message inside Gmail's collapsed gmail_quote blockquote.
+
+--strip-tracking removes tracking parameters (utm_*, mkt_tok, fbclid, ...)
+from every URL in the bodies, quoted original included, and drops tracking
+pixels; config gmail.stripTracking turns it on by default.
+
+--pgp-sign and --pgp-encrypt build a PGP/MIME (RFC 3156) message with the
+local GnuPG keyring: signed with --pgp-key (config gmail.pgpKey, else the
+From address) and encrypted to every recipient plus the sender, Bcc as
+hidden recipients. Headers, including Subject, stay unencrypted.
+
+--from-alias is --from spelled for aliases: the address must be a verified
+send-as alias and its display name is used. --with-signature appends the
+alias's Gmail signature (the account's without --from/--from-alias) to the
+HTML and plain bodies; it is skipped with --dry-run.
+
+--label-on-send applies labels (names or IDs, which must already exist) to the
+sent thread, e.g. --label-on-send Waiting for follow-up workflows.`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			u := ui.FromContext(cmd.Context())
@@ -38,58 +103,152 @@ To see available send-as aliases: gog gmail sendas list`,
 				return err
 			}
 
+			if err := dryRun.validate(); err != nil {
+				return err
+			}
+			if strings.TrimSpace(fromAlias) != "" {
+				if strings.TrimSpace(from) != "" {
+					return usage("--from and --from-alias are mutually exclusive")
+				}
+				from = fromAlias
+			}
+			scheduledAt, err := parseSendAt(sendAt, queueNow())
+			if err != nil {
+				return err
+			}
+			if err := tmpl.apply(&subject, &body, &bodyHTML); err != nil {
+				return err
+			}
+			if err := applyBodyMarkdown(bodyMD, &body, &bodyHTML); err != nil {
+				return err
+			}
 			if strings.TrimSpace(to) == "" || strings.TrimSpace(subject) == "" {
 				return usage("required: --to, --subject")
 			}
 			if strings.TrimSpace(body) == "" && strings.TrimSpace(bodyHTML) == "" {
-				return usage("required: --body or --body-html")
+				return usage("required: --body, --body-html or --body-md")
+			}
+			if quoteHTML {
+				if strings.TrimSpace(replyToMessageID) == "" || strings.TrimSpace(bodyHTML) == "" {
+					return usage("--quote-html requires --reply-to-message-id and --body-html")
+				}
+				if dryRun.Enabled {
+					return usage("--quote-html cannot be combined with --dry-run")
+				}
+			}
+			labelOnSend = splitLabelArgs(labelOnSend)
+			if len(labelOnSend) > 0 && strings.TrimSpace(sendAt) != "" {
+				return usage("--label-on-send cannot be combined with --send-at")
 			}
 
 			recipients := append([]string{}, splitCSV(to)...)
 			recipients = append(recipients, splitCSV(cc)...)
 			recipients = append(recipients, splitCSV(bcc)...)
-			if err := checkGmailAllowlist(u, recipients); err != nil {
-				return err
-			}
-			if err := requireGmailSendArm(); err != nil {
+			if err := checkGmailAllowlist(u, account, recipients); err != nil {
 				return err
 			}
 
-			svc, err := newGmailService(cmd.Context(), account)
+			atts, err := parseAttachSpecs(attach)
 			if err != nil {
 				return err
 			}
+			if err := checkPolicyAttachments(account, attachmentPaths(atts)); err != nil {
+				return err
+			}
 
-			// Determine the From address
-			fromAddr := account
-			if strings.TrimSpace(from) != "" {
-				// Validate that this is a configured send-as alias
-				var sa *gmail.SendAs
-				sa, err = svc.Users.Settings.SendAs.Get("me", from).Context(cmd.Context()).Do()
+			var svc *gmail.Service
+			if !dryRun.Enabled {
+				if err := requireGmailSendArm(account); err != nil {
+					return err
+				}
+				if err := checkPolicySendQuota(account); err != nil {
+					return err
+				}
+				svc, err = newGmailService(cmd.Context(), account)
 				if err != nil {
-					return fmt.Errorf("invalid --from address %q: %w", from, err)
+					return err
 				}
-				if sa.VerificationStatus != "accepted" {
-					return fmt.Errorf("--from address %q is not verified (status: %s)", from, sa.VerificationStatus)
+			}
+
+			// Resolve labels before sending so a typo fails without sending.
+			var labelIDs []string
+			if len(labelOnSend) > 0 && svc != nil {
+				labelIDs, err = resolveExistingLabelIDs(cmd.Context(), svc, labelOnSend)
+				if err != nil {
+					return err
 				}
-				fromAddr = from
-				// Include display name if set
-				if sa.DisplayName != "" {
-					fromAddr = sa.DisplayName + " <" + from + ">"
+			}
+
+			if verify {
+				verifyRecipients(cmd.Context(), u, account, svc, recipients)
+			}
+
+			if strings.TrimSpace(from) == "" && !noSendAsRules {
+				alias, ruleErr := defaultSendAsFor(splitCSV(to))
+				if ruleErr != nil {
+					return ruleErr
+				}
+				if alias != "" {
+					u.Err().Printf("Sending as %s (sendAsByDomain rule; --from or --no-send-as-rules to override)", alias)
+					from = alias
 				}
 			}
 
-			inReplyTo, references, threadID, err := replyHeaders(cmd, svc, replyToMessageID)
+			fromAddr, err := resolveFromAddress(cmd.Context(), svc, account, from)
 			if err != nil {
 				return err
 			}
 
-			atts := make([]mailAttachment, 0, len(attach))
-			for _, p := range attach {
-				atts = append(atts, mailAttachment{Path: p})
+			if withSignature {
+				if svc == nil {
+					u.Err().Printf("WARN: --dry-run does not fetch the send-as signature; --with-signature is ignored")
+				} else {
+					sig, err := sendAsSignature(cmd.Context(), svc, account, fromAddr)
+					if err != nil {
+						return err
+					}
+					appendSignature(&body, &bodyHTML, sig)
+				}
+			}
+
+			var inReplyTo, references, threadID string
+			if svc != nil {
+				inReplyTo, references, threadID, err = replyHeaders(cmd, svc, replyToMessageID)
+				if err != nil {
+					return err
+				}
+				if quoteHTML {
+					bodyHTML, err = quotedReplyHTML(cmd.Context(), svc, replyToMessageID, bodyHTML)
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			if err := tracking.apply(cmd, &body, &bodyHTML); err != nil {
+				return err
 			}
 
-			raw, err := buildRFC822(mailOptions{
+			inline, err := parseInlineImages(inlineSpecs)
+			if err != nil {
+				return err
+			}
+			if len(inline) > 0 {
+				if strings.TrimSpace(bodyHTML) == "" {
+					return usage("--inline requires --body-html or --body-md")
+				}
+				warnUnreferencedInline(u, bodyHTML, inline)
+			}
+
+			hdrs, err := resolveComposeHeaders(account, fromAddr)
+			if err != nil {
+				return err
+			}
+			pgpOpts, err := pgp.options(fromAddr, splitCSV(to), splitCSV(cc), splitCSV(bcc))
+			if err != nil {
+				return err
+			}
+			composed, err := composeRFC822(u, mailOptions{
 				From:        fromAddr,
 				To:          splitCSV(to),
 				Cc:          splitCSV(cc),
@@ -101,33 +260,70 @@ To see available send-as aliases: gog gmail sendas list`,
 				InReplyTo:   inReplyTo,
 				References:  references,
 				Attachments: atts,
+				Inline:      inline,
+
+				AdditionalHeaders: hdrs.Extra,
+				MessageIDDomain:   hdrs.MessageIDDomain,
+				PGP:               pgpOpts,
 			})
 			if err != nil {
 				return err
 			}
-
-			msg := &gmail.Message{
-				Raw: base64.RawURLEncoding.EncodeToString(raw),
+			defer composed.Close()
+			if dryRun.Enabled {
+				return dryRun.write(cmd.Context(), composed, replyToMessageID)
 			}
-			if threadID != "" {
-				msg.ThreadId = threadID
+			if !scheduledAt.IsZero() {
+				raw, err := composed.bytes()
+				if err != nil {
+					return err
+				}
+				return enqueueGmailSend(cmd.Context(), queuedMessage{
+					Account:  account,
+					SendAt:   scheduledAt,
+					To:       splitCSV(to),
+					Subject:  subject,
+					ThreadID: threadID,
+					Raw:      base64.RawURLEncoding.EncodeToString(raw),
+				})
 			}
 
-			sent, err := svc.Users.Messages.Send("me", msg).Context(cmd.Context()).Do()
+			sent, err := sendComposed(cmd.Context(), svc, composed, threadID)
+			logEntry := sentLogFromRaw(sentLogEntry{Account: account, Source: "send", ThreadID: threadID}, composed.header())
 			if err != nil {
+				logEntry.Error = err.Error()
+				recordSend(u, logEntry)
 				return err
 			}
+			logEntry.MessageID, logEntry.ThreadID = sent.Id, sent.ThreadId
+			recordSend(u, logEntry)
+			// The message is already sent; a labeling failure only warns.
+			var labeled []string
+			if len(labelIDs) > 0 {
+				if labelErr := labelSentThread(cmd, svc, sent, labelIDs); labelErr != nil {
+					u.Err().Printf("WARN: sent, but applying --label-on-send failed: %v", labelErr)
+				} else {
+					labeled = labelIDs
+				}
+			}
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
+				out := map[string]any{
 					"messageId": sent.Id,
 					"threadId":  sent.ThreadId,
 					"from":      fromAddr,
-				})
+				}
+				if len(labelIDs) > 0 {
+					out["labels"] = labeled
+				}
+				return outfmt.WriteJSON(os.Stdout, out)
 			}
 			u.Out().Printf("message_id\t%s", sent.Id)
 			if sent.ThreadId != "" {
 				u.Out().Printf("thread_id\t%s", sent.ThreadId)
 			}
+			if len(labeled) > 0 {
+				u.Out().Printf("labels\t%s", strings.Join(labeled, ","))
+			}
 			return nil
 		},
 	}
@@ -138,10 +334,23 @@ To see available send-as aliases: gog gmail sendas list`,
 	cmd.Flags().StringVar(&subject, "subject", "", "Subject (required)")
 	cmd.Flags().StringVar(&body, "body", "", "Body (plain text; required unless --body-html is set)")
 	cmd.Flags().StringVar(&bodyHTML, "body-html", "", "Body (HTML; optional)")
+	cmd.Flags().StringVar(&bodyMD, "body-md", "", "Body from a Markdown file (- for stdin), sent as styled HTML plus a plain-text part")
 	cmd.Flags().StringVar(&replyToMessageID, "reply-to-message-id", "", "Reply to Gmail message ID (sets In-Reply-To/References and thread)")
+	cmd.Flags().BoolVar(&quoteHTML, "quote-html", false, "Append the replied-to message's HTML in a collapsed gmail_quote block (needs --reply-to-message-id and --body-html)")
 	cmd.Flags().StringVar(&replyTo, "reply-to", "", "Reply-To header address")
-	cmd.Flags().StringSliceVar(&attach, "attach", nil, "Attachment file path (repeatable)")
+	cmd.Flags().StringSliceVar(&attach, "attach", nil, attachFlagUsage)
+	tracking.addFlag(cmd)
+	pgp.addFlags(cmd)
+	cmd.Flags().StringArrayVar(&inlineSpecs, "inline", nil, "Inline image path[:cid] for <img src=\"cid:...\"> in the HTML body (repeatable; cid defaults to the file name)")
 	cmd.Flags().StringVar(&from, "from", "", "Send from this email address (must be a verified send-as alias)")
+	cmd.Flags().StringVar(&fromAlias, "from-alias", "", "Send as this send-as alias (validated against sendAs settings; uses its display name)")
+	cmd.Flags().BoolVar(&withSignature, "with-signature", false, "Append the sending alias's Gmail signature to the body")
+	cmd.Flags().BoolVar(&noSendAsRules, "no-send-as-rules", false, "Ignore config.json gmail.sendAsByDomain rules and send from the account")
+	cmd.Flags().StringSliceVar(&labelOnSend, "label-on-send", nil, "Apply labels (name or ID; repeatable or comma-separated) to the thread after sending")
+	cmd.Flags().BoolVar(&verify, "verify-recipients", false, "Warn about recipients not found in contacts or recent mail (suggests likely typo fixes)")
+	dryRun.addFlags(cmd)
+	tmpl.addFlags(cmd)
+	cmd.Flags().StringVar(&sendAt, "send-at", "", "Queue locally and send at this time (RFC3339, or YYYY-MM-DD HH:MM local); flushed by gog queue run")
 	return cmd
 }
 
@@ -173,3 +382,122 @@ func replyHeaders(cmd *cobra.Command, svc *gmail.Service, replyToMessageID strin
 	}
 	return inReplyTo, references, threadID, nil
 }
+
+// splitLabelArgs flattens repeatable, comma-separated label flags.
+func splitLabelArgs(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, splitCSV(v)...)
+	}
+	return out
+}
+
+// resolveExistingLabelIDs maps label names or IDs to IDs, failing on labels
+// that do not exist instead of passing them through.
+func resolveExistingLabelIDs(ctx context.Context, svc *gmail.Service, labels []string) ([]string, error) {
+	nameToID, err := fetchLabelNameToID(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(labels))
+	for _, label := range labels {
+		id, ok := nameToID[strings.ToLower(strings.TrimSpace(label))]
+		if !ok {
+			return nil, usagef("unknown label %q (see gog gmail labels list)", label)
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// labelSentThread adds labelIDs to the sent message's thread, or to the
+// message itself when Gmail returned no thread ID.
+func labelSentThread(cmd *cobra.Command, svc *gmail.Service, sent *gmail.Message, labelIDs []string) error {
+	if sent.ThreadId != "" {
+		_, err := svc.Users.Threads.Modify("me", sent.ThreadId, &gmail.ModifyThreadRequest{
+			AddLabelIds: labelIDs,
+		}).Context(cmd.Context()).Do()
+		return err
+	}
+	_, err := svc.Users.Messages.Modify("me", sent.Id, &gmail.ModifyMessageRequest{
+		AddLabelIds: labelIDs,
+	}).Context(cmd.Context()).Do()
+	return err
+}
+
+const attachFlagUsage = "Attachment path[::name[::mime/type]] (repeatable); name and type override the sent file name and Content-Type"
+
+// parseAttachSpecs turns --attach path[::name[::type]] values into
+// attachments. An empty name keeps the file's base name and an empty type
+// is guessed from the name.
+func parseAttachSpecs(specs []string) ([]mailAttachment, error) {
+	out := make([]mailAttachment, 0, len(specs))
+	for _, spec := range specs {
+		parts := strings.Split(spec, "::")
+		if len(parts) > 3 || strings.TrimSpace(parts[0]) == "" {
+			return nil, usagef("invalid --attach %q (expected path[::name[::mime/type]])", spec)
+		}
+		a := mailAttachment{Path: strings.TrimSpace(parts[0])}
+		if len(parts) > 1 {
+			a.Filename = strings.TrimSpace(parts[1])
+			if strings.ContainsAny(a.Filename, "/\\\r\n") {
+				return nil, usagef("invalid --attach name %q (no path separators)", a.Filename)
+			}
+		}
+		if len(parts) > 2 {
+			a.MIMEType = strings.TrimSpace(parts[2])
+			if a.MIMEType != "" {
+				if mt, _, err := mime.ParseMediaType(a.MIMEType); err != nil || !strings.Contains(mt, "/") {
+					return nil, usagef("invalid --attach type %q (expected e.g. application/pdf)", a.MIMEType)
+				}
+			}
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func attachmentPaths(atts []mailAttachment) []string {
+	paths := make([]string, 0, len(atts))
+	for _, a := range atts {
+		paths = append(paths, a.Path)
+	}
+	return paths
+}
+
+// parseInlineImages turns --inline path[:cid] values into inline parts. The
+// Content-ID defaults to the file name, so <img src="cid:logo.png"> works
+// for --inline logo.png.
+func parseInlineImages(specs []string) ([]mailAttachment, error) {
+	out := make([]mailAttachment, 0, len(specs))
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		path, cid := spec, ""
+		// The last colon splits off the cid unless what follows looks like a
+		// path (C:\img.png, ./a:b/c.png).
+		if i := strings.LastIndex(spec, ":"); i > 0 && !strings.ContainsAny(spec[i+1:], `/\`) {
+			path, cid = spec[:i], strings.TrimSpace(spec[i+1:])
+		}
+		if path == "" {
+			return nil, usagef("invalid --inline %q (expected path[:cid])", spec)
+		}
+		if cid == "" {
+			cid = filepath.Base(path)
+		}
+		if strings.ContainsAny(cid, " <>\t") {
+			return nil, usagef("invalid --inline content ID %q", cid)
+		}
+		out = append(out, mailAttachment{Path: path, ContentID: cid})
+	}
+	return out, nil
+}
+
+// warnUnreferencedInline flags inline images the HTML never uses; clients
+// show those as attachments.
+func warnUnreferencedInline(u *ui.UI, htmlBody string, inline []mailAttachment) {
+	for _, a := range inline {
+		if !strings.Contains(htmlBody, "cid:"+a.ContentID) {
+			u.Err().Printf("WARN: inline image %s is not referenced as cid:%s in the HTML body", a.Path, a.ContentID)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_GmailSend_LabelOnSend(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+
+	var sends int
+	var modifyBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{
+				{"id": "INBOX", "name": "INBOX"},
+				{"id": "Label_9", "name": "Waiting"},
+			}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/messages/send"):
+			sends++
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1", "threadId": "t1"})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/threads/t1/modify"):
+			b, _ := io.ReadAll(r.Body)
+			modifyBody = string(b)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "send", "--to", "x@y.com",
+				"--subject", "S", "--body", "B", "--label-on-send", "waiting"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(modifyBody, `"addLabelIds":["Label_9"]`) {
+		t.Fatalf("unexpected modify body: %q", modifyBody)
+	}
+	var parsed map[string]any
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v out=%q", err, out)
+	}
+	if labels, _ := parsed["labels"].([]any); len(labels) != 1 || labels[0] != "Label_9" {
+		t.Fatalf("unexpected labels: %#v", parsed["labels"])
+	}
+
+	err = Execute([]string{"--account", "a@b.com", "gmail", "send", "--to", "x@y.com",
+		"--subject", "S", "--body", "B", "--label-on-send", "Nope"})
+	if err == nil || !strings.Contains(err.Error(), `unknown label "Nope"`) {
+		t.Fatalf("expected unknown label error, got %v", err)
+	}
+	if sends != 1 {
+		t.Fatalf("expected unknown label to block sending, sends=%d", sends)
+	}
+}
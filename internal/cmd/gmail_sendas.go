@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/outfmt"
@@ -25,6 +24,7 @@ func newGmailSendAsCmd(flags *rootFlags) *cobra.Command {
 	cmd.AddCommand(newGmailSendAsVerifyCmd(flags))
 	cmd.AddCommand(newGmailSendAsDeleteCmd(flags))
 	cmd.AddCommand(newGmailSendAsUpdateCmd(flags))
+	cmd.AddCommand(newGmailSendAsSignatureCmd(flags))
 	return cmd
 }
 
@@ -59,7 +59,7 @@ func newGmailSendAsListCmd(flags *rootFlags) *cobra.Command {
 				return nil
 			}
 
-			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			tw, flush := tableWriter(cmd.Context())
 			fmt.Fprintln(tw, "EMAIL\tDISPLAY NAME\tDEFAULT\tVERIFIED\tTREAT AS ALIAS")
 			for _, sa := range resp.SendAs {
 				isDefault := ""
@@ -77,7 +77,7 @@ func newGmailSendAsListCmd(flags *rootFlags) *cobra.Command {
 				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
 					sa.SendAsEmail, sa.DisplayName, isDefault, verified, treatAsAlias)
 			}
-			_ = tw.Flush()
+			flush()
 			return nil
 		},
 	}
@@ -239,6 +239,9 @@ func newGmailSendAsDeleteCmd(flags *rootFlags) *cobra.Command {
 			if sendAsEmail == "" {
 				return errors.New("email is required")
 			}
+			if confirmErr := confirmDestructive(cmd, flags, fmt.Sprintf("delete send-as alias %s", sendAsEmail)); confirmErr != nil {
+				return confirmErr
+			}
 
 			svc, err := newGmailService(cmd.Context(), account)
 			if err != nil {
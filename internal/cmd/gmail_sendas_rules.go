@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// defaultSendAsFor picks the send-as alias configured for the To recipients'
+// domains (config.json gmail.sendAsByDomain). It returns "" when no rule
+// matches and fails when recipients match different aliases.
+func defaultSendAsFor(to []string) (string, error) {
+	cfg, err := config.ReadConfigFile()
+	if err != nil {
+		return "", err
+	}
+	return matchSendAsRules(cfg.Gmail.SendAsByDomain, to)
+}
+
+func matchSendAsRules(rules map[string]string, to []string) (string, error) {
+	if len(rules) == 0 {
+		return "", nil
+	}
+	aliases := map[string]struct{}{}
+	for _, email := range extractEmails(to) {
+		if alias := sendAsRuleFor(rules, email); alias != "" {
+			aliases[alias] = struct{}{}
+		}
+	}
+	switch len(aliases) {
+	case 0:
+		return "", nil
+	case 1:
+		for alias := range aliases {
+			return alias, nil
+		}
+	}
+	list := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		list = append(list, alias)
+	}
+	sort.Strings(list)
+	return "", usagef("recipients match different send-as rules (%s); pass --from", strings.Join(list, ", "))
+}
+
+// sendAsRuleFor returns the alias of the most specific rule matching email's
+// domain ("client.com" also matches "eu.client.com").
+func sendAsRuleFor(rules map[string]string, email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	domain := strings.ToLower(email[at+1:])
+	best, bestLen := "", -1
+	for rule, alias := range rules {
+		rule = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(rule), "@"))
+		alias = strings.TrimSpace(alias)
+		if rule == "" || alias == "" {
+			continue
+		}
+		if (domain == rule || strings.HasSuffix(domain, "."+rule)) && len(rule) > bestLen {
+			best, bestLen = alias, len(rule)
+		}
+	}
+	return best
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchSendAsRules(t *testing.T) {
+	rules := map[string]string{
+		"client.com":     "consulting@me.com",
+		"@eu.client.com": "eu@me.com",
+		"partner.org":    "partners@me.com",
+	}
+	cases := []struct {
+		to      []string
+		want    string
+		wantErr bool
+	}{
+		{[]string{"a@client.com"}, "consulting@me.com", false},
+		{[]string{"Ann <ann@sales.client.com>", "b@other.net"}, "consulting@me.com", false},
+		{[]string{"x@eu.client.com"}, "eu@me.com", false},
+		{[]string{"x@notclient.com"}, "", false},
+		{[]string{"a@client.com", "b@partner.org"}, "", true},
+	}
+	for _, tc := range cases {
+		got, err := matchSendAsRules(rules, tc.to)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Fatalf("matchSendAsRules(%v)=%q,%v want %q (err=%v)", tc.to, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestExecute_GmailSend_SendAsRule(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOG_CONFIG_DIR", dir)
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"gmail":{"sendAsByDomain":{"client.com":"consulting@me.com"}}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	run := func(extra ...string) string {
+		args := append([]string{"--account", "a@b.com", "gmail", "send", "--dry-run", "--to", "x@client.com", "--subject", "S", "--body", "B"}, extra...)
+		return captureStdout(t, func() {
+			_ = captureStderr(t, func() {
+				if err := Execute(args); err != nil {
+					t.Fatalf("Execute: %v", err)
+				}
+			})
+		})
+	}
+	if out := run(); !strings.Contains(out, "From: consulting@me.com\r\n") {
+		t.Fatalf("expected rule alias, got %q", out)
+	}
+	if out := run("--no-send-as-rules"); !strings.Contains(out, "From: a@b.com\r\n") {
+		t.Fatalf("expected account, got %q", out)
+	}
+	if out := run("--from", "other@me.com"); !strings.Contains(out, "From: other@me.com\r\n") {
+		t.Fatalf("expected explicit --from, got %q", out)
+	}
+}
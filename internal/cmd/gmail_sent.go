@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// bounceQuery finds delivery status notifications.
+const bounceQuery = "{from:mailer-daemon from:postmaster}"
+
+// sentReportRow is one send log entry checked against the mailbox.
+type sentReportRow struct {
+	sentLogEntry
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func newGmailSentCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sent",
+		Short: "Checks on messages sent by gog",
+	}
+	cmd.AddCommand(newGmailSentReportCmd(flags))
+	return cmd
+}
+
+func newGmailSentReportCmd(flags *rootFlags) *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Reconcile gog's send log with the Sent mailbox and bounces",
+		Long: `Cross-check the local send log (every gmail send, drafts send and queue run
+send) against the mailbox. Each send gets a status:
+
+  ok       the message is in Sent
+  missing  the API accepted it but it is not in Sent (deleted or never stored)
+  bounced  a mailer-daemon/postmaster bounce references its Message-ID
+  failed   the send call itself returned an error
+
+Exits non-zero when any send is missing, bounced or failed, so it can run as
+a scheduled integrity check. The log keeps 90 days.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			window, err := parseFollowupDelay(since)
+			if err != nil {
+				return usagef("invalid --since %q (use e.g. 7d, 2w, 12h)", since)
+			}
+
+			all, err := loadSentLog()
+			if err != nil {
+				return err
+			}
+			cutoff := sentLogNow().Add(-window)
+			var entries []sentLogEntry
+			for _, e := range all {
+				if strings.EqualFold(e.Account, account) && !e.Time.Before(cutoff) {
+					entries = append(entries, e)
+				}
+			}
+
+			var rows []sentReportRow
+			if len(entries) > 0 {
+				svc, err := newGmailService(cmd.Context(), account)
+				if err != nil {
+					return err
+				}
+				rows, err = reconcileSent(cmd.Context(), svc, entries, window)
+				if err != nil {
+					return err
+				}
+			}
+			issues := 0
+			for _, r := range rows {
+				if r.Status != "ok" {
+					issues++
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"since":   cutoff.UTC().Format(time.RFC3339),
+					"entries": rows,
+					"issues":  issues,
+				}); err != nil {
+					return err
+				}
+			} else if len(rows) == 0 {
+				u.Err().Printf("No sends by gog since %s", cutoff.Local().Format("2006-01-02 15:04"))
+			} else {
+				w, flush := tableWriter(cmd.Context())
+				fmt.Fprintln(w, "TIME\tSOURCE\tSTATUS\tTO\tSUBJECT\tMESSAGE_ID\tDETAIL")
+				for _, r := range rows {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+						r.Time.Local().Format("2006-01-02 15:04"),
+						r.Source,
+						r.Status,
+						strings.Join(r.To, ","),
+						sanitizeTab(r.Subject),
+						orDash(r.MessageID),
+						sanitizeTab(r.Detail),
+					)
+				}
+				flush()
+			}
+			if issues > 0 {
+				return fmt.Errorf("%d of %d sends are missing, bounced or failed", issues, len(rows))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "How far back to check (e.g. 7d, 2w, 12h; the log keeps 90 days)")
+	return cmd
+}
+
+// reconcileSent looks up each logged send in the mailbox and in recent bounces.
+func reconcileSent(ctx context.Context, svc *gmail.Service, entries []sentLogEntry, window time.Duration) ([]sentReportRow, error) {
+	bounces, err := recentBounceTexts(ctx, svc, window)
+	if err != nil {
+		return nil, fmt.Errorf("list bounces: %w", err)
+	}
+
+	rows := make([]sentReportRow, 0, len(entries))
+	for _, e := range entries {
+		row := sentReportRow{sentLogEntry: e, Status: "ok"}
+		switch {
+		case e.Error != "":
+			row.Status, row.Detail = "failed", e.Error
+		case e.MessageID == "":
+			row.Status, row.Detail = "missing", "no message ID was returned"
+		default:
+			msg, err := svc.Users.Messages.Get("me", e.MessageID).Format("minimal").Context(ctx).Do()
+			var apiErr *googleapi.Error
+			switch {
+			case errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound:
+				row.Status, row.Detail = "missing", "message no longer exists"
+			case err != nil:
+				return nil, err
+			case !hasLabel(msg.LabelIds, "SENT"):
+				row.Status, row.Detail = "missing", "message is not labeled SENT"
+			}
+		}
+		if row.Status == "ok" && e.RFC822ID != "" {
+			for _, b := range bounces {
+				if strings.Contains(b.text, e.RFC822ID) {
+					row.Status, row.Detail = "bounced", "bounce "+b.id
+					break
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+type bounceText struct {
+	id   string
+	text string
+}
+
+// recentBounceTexts returns the headers and text of bounce messages received
+// within window; bounces quote the original Message-ID.
+func recentBounceTexts(ctx context.Context, svc *gmail.Service, window time.Duration) ([]bounceText, error) {
+	days := int(math.Ceil(window.Hours() / 24))
+	query := fmt.Sprintf("%s newer_than:%dd", bounceQuery, max(days, 1))
+	var out []bounceText
+	pageToken := ""
+	for {
+		call := svc.Users.Messages.List("me").Q(query).IncludeSpamTrash(true).MaxResults(100).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range resp.Messages {
+			msg, err := svc.Users.Messages.Get("me", ref.Id).Format("full").Context(ctx).Do()
+			if err != nil {
+				return nil, err
+			}
+			var b strings.Builder
+			collectPartText(&b, msg.Payload)
+			out = append(out, bounceText{id: msg.Id, text: b.String()})
+		}
+		if resp.NextPageToken == "" {
+			return out, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// collectPartText writes every header value and decoded text body of p.
+func collectPartText(b *strings.Builder, p *gmail.MessagePart) {
+	if p == nil {
+		return
+	}
+	for _, h := range p.Headers {
+		b.WriteString(h.Value)
+		b.WriteByte('\n')
+	}
+	if p.Body != nil && p.Body.Data != "" && (strings.HasPrefix(p.MimeType, "text/") || strings.HasPrefix(p.MimeType, "message/")) {
+		if s, err := decodeBase64URL(p.Body.Data); err == nil {
+			b.WriteString(s)
+			b.WriteByte('\n')
+		}
+	}
+	for _, part := range p.Parts {
+		collectPartText(b, part)
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/statefile"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// sentLogRetention bounds how far back the send log (and so `gmail sent
+// report --since`) reaches.
+const sentLogRetention = 90 * 24 * time.Hour
+
+// sentLogEntry is one send attempt by gog (gmail send, drafts send, queue run).
+type sentLogEntry struct {
+	Time      time.Time `json:"time"`
+	Account   string    `json:"account"`
+	Source    string    `json:"source"`
+	MessageID string    `json:"messageId,omitempty"`
+	ThreadID  string    `json:"threadId,omitempty"`
+	RFC822ID  string    `json:"rfc822MessageId,omitempty"`
+	To        []string  `json:"to,omitempty"`
+	Subject   string    `json:"subject,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// sentLogNow is swapped in tests.
+var sentLogNow = time.Now
+
+func loadSentLog() ([]sentLogEntry, error) {
+	path, err := config.SentLogPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := statefile.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []sentLogEntry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var e sentLogEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			continue // a torn line from an interrupted write
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
+
+// appendSentLog adds e to the send log, dropping entries past the retention.
+// The whole file is rewritten so it stays readable when state is encrypted.
+func appendSentLog(e sentLogEntry) error {
+	entries, err := loadSentLog()
+	if err != nil {
+		return err
+	}
+	cutoff := sentLogNow().Add(-sentLogRetention)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, old := range append(entries, e) {
+		if old.Time.Before(cutoff) {
+			continue
+		}
+		if err := enc.Encode(old); err != nil {
+			return err
+		}
+	}
+	path, err := config.SentLogPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return statefile.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+// recordSend logs a send attempt. Logging never fails the send itself.
+func recordSend(u *ui.UI, e sentLogEntry) {
+	if e.Time.IsZero() {
+		e.Time = sentLogNow()
+	}
+	if err := appendSentLog(e); err != nil && u != nil {
+		u.Err().Printf("WARN: recording send in the sent log failed: %v", err)
+	}
+}
+
+// sentLogFromRaw fills the Message-ID, recipients and subject of an entry
+// from the RFC822 message that was sent.
+func sentLogFromRaw(e sentLogEntry, raw []byte) sentLogEntry {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return e
+	}
+	e.RFC822ID = strings.TrimSpace(msg.Header.Get("Message-ID"))
+	if e.Subject == "" {
+		e.Subject = msg.Header.Get("Subject")
+	}
+	if len(e.To) == 0 {
+		if addrs, err := msg.Header.AddressList("To"); err == nil {
+			for _, a := range addrs {
+				e.To = append(e.To, a.Address)
+			}
+		}
+	}
+	return e
+}
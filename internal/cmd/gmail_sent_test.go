@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_GmailSentReport(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+	t.Setenv("GOG_STATE_DIR", t.TempDir())
+
+	var bounceQ string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/messages/send"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1", "threadId": "t1"})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages"):
+			bounceQ = r.URL.Query().Get("q")
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "d1"}}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages/d1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "d1", "payload": map[string]any{
+				"headers": []map[string]any{{"name": "References", "value": "<bounced@example.com>"}},
+			}})
+		case r.Method == http.MethodGet && (strings.HasSuffix(r.URL.Path, "/users/me/messages/s1") || strings.HasSuffix(r.URL.Path, "/users/me/messages/s2")):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1", "labelIds": []string{"SENT"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "send", "--to", "x@y.com", "--subject", "S", "--body", "B"}); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	})
+	logged, err := loadSentLog()
+	if err != nil || len(logged) != 1 {
+		t.Fatalf("unexpected log %#v err=%v", logged, err)
+	}
+	if e := logged[0]; e.MessageID != "s1" || e.Source != "send" || e.RFC822ID == "" || len(e.To) != 1 || e.To[0] != "x@y.com" {
+		t.Fatalf("unexpected entry %#v", e)
+	}
+
+	now := time.Now()
+	for _, e := range []sentLogEntry{
+		{Time: now, Account: "a@b.com", Source: "queue", MessageID: "s2", RFC822ID: "<bounced@example.com>"},
+		{Time: now, Account: "a@b.com", Source: "send", MessageID: "gone"},
+		{Time: now, Account: "a@b.com", Source: "send", Error: "quota exceeded"},
+		{Time: now, Account: "other@b.com", Source: "send", MessageID: "x"},
+		{Time: now.Add(-30 * 24 * time.Hour), Account: "a@b.com", Source: "send", MessageID: "old"},
+	} {
+		if err := appendSentLog(e); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	out := captureStdout(t, func() {
+		err = Execute([]string{"--json", "--account", "a@b.com", "gmail", "sent", "report", "--since", "7d"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "3 of 4") {
+		t.Fatalf("expected issues error, got %v", err)
+	}
+	if bounceQ != bounceQuery+" newer_than:7d" {
+		t.Fatalf("unexpected bounce query %q", bounceQ)
+	}
+	var parsed struct {
+		Entries []sentReportRow `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v out=%q", err, out)
+	}
+	var statuses []string
+	for _, r := range parsed.Entries {
+		statuses = append(statuses, r.Status)
+	}
+	if strings.Join(statuses, ",") != "ok,bounced,missing,failed" {
+		t.Fatalf("unexpected statuses %v", statuses)
+	}
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+func newGmailSendAsSignatureCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signature",
+		Short: "Read and update an alias's HTML signature",
+	}
+	cmd.AddCommand(newGmailSendAsSignatureGetCmd(flags))
+	cmd.AddCommand(newGmailSendAsSignatureSetCmd(flags))
+	return cmd
+}
+
+func newGmailSendAsSignatureGetCmd(flags *rootFlags) *cobra.Command {
+	var preview bool
+
+	cmd := &cobra.Command{
+		Use:   "get <email>",
+		Short: "Print the HTML signature of a send-as alias",
+		Example: `  gog gmail sendas signature get me@example.com > signature.html
+  gog gmail sendas signature get sales@example.com --preview`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			sa, err := svc.Users.Settings.SendAs.Get("me", strings.TrimSpace(args[0])).Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			return printSignature(cmd, u, sa.SendAsEmail, sa.Signature, preview)
+		},
+	}
+
+	cmd.Flags().BoolVar(&preview, "preview", false, "Render the signature as plain text")
+	return cmd
+}
+
+func newGmailSendAsSignatureSetCmd(flags *rootFlags) *cobra.Command {
+	var file string
+	var preview bool
+
+	cmd := &cobra.Command{
+		Use:   "set <email>",
+		Short: "Replace the HTML signature of a send-as alias",
+		Long: `Replace an alias's signature with the HTML in --file (- for stdin). An empty
+file clears the signature. --preview prints the new signature as plain text
+and leaves the alias unchanged.`,
+		Example: `  gog gmail sendas signature set me@example.com --file signature.html
+  gog gmail sendas signature set me@example.com --file signature.html --preview`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(file) == "" {
+				return usage("--file is required (use - for stdin)")
+			}
+			data, err := readFileOrStdin(file)
+			if err != nil {
+				return err
+			}
+			sig := strings.TrimSpace(string(data))
+			email := strings.TrimSpace(args[0])
+			if preview {
+				return printSignature(cmd, u, email, sig, true)
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			current, err := svc.Users.Settings.SendAs.Get("me", email).Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			current.Signature = sig
+			// An empty signature must still be sent to clear the old one.
+			current.ForceSendFields = append(current.ForceSendFields, "Signature")
+			updated, err := svc.Users.Settings.SendAs.Update("me", email, current).Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"sendAsEmail": updated.SendAsEmail, "signature": updated.Signature})
+			}
+			if sig == "" {
+				u.Out().Printf("Cleared signature for %s", updated.SendAsEmail)
+				return nil
+			}
+			u.Out().Printf("Updated signature for %s", updated.SendAsEmail)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "HTML signature file (- for stdin)")
+	cmd.Flags().BoolVar(&preview, "preview", false, "Print the new signature as plain text without saving it")
+	return cmd
+}
+
+// printSignature writes a signature as HTML, or as text with preview.
+func printSignature(cmd *cobra.Command, u *ui.UI, email, sig string, preview bool) error {
+	text := htmlToText(sig)
+	if outfmt.IsJSON(cmd.Context()) {
+		out := map[string]any{"sendAsEmail": email, "signature": sig}
+		if preview {
+			out["text"] = text
+		}
+		return outfmt.WriteJSON(os.Stdout, out)
+	}
+	if sig == "" {
+		u.Err().Printf("No signature for %s", email)
+		return nil
+	}
+	if preview {
+		u.Out().Println(text)
+		return nil
+	}
+	u.Out().Println(sig)
+	return nil
+}
+
+// sendAsSignature returns the HTML signature configured in Gmail for the
+// send-as address in from (the account when from is empty).
+func sendAsSignature(ctx context.Context, svc *gmail.Service, account, from string) (string, error) {
+	addr := senderAddress(from)
+	if addr == "" {
+		addr = account
+	}
+	sa, err := svc.Users.Settings.SendAs.Get("me", addr).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("signature for %s: %w", addr, err)
+	}
+	return sa.Signature, nil
+}
+
+// appendSignature adds an HTML signature to the bodies the way Gmail does:
+// after a "-- " separator, in a gmail_signature block for HTML and as text
+// for the plain part. Empty bodies stay empty.
+func appendSignature(body, bodyHTML *string, sig string) {
+	if strings.TrimSpace(sig) == "" {
+		return
+	}
+	if strings.TrimSpace(*bodyHTML) != "" {
+		*bodyHTML += `<br><br>-- <br><div class="gmail_signature">` + sig + `</div>`
+	}
+	if strings.TrimSpace(*body) != "" {
+		*body = strings.TrimRight(*body, "\r\n") + "\n\n-- \n" + htmlToText(sig) + "\n"
+	}
+}
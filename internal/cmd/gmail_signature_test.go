@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestAppendSignature(t *testing.T) {
+	body, bodyHTML := "Hi\n", "<p>Hi</p>"
+	appendSignature(&body, &bodyHTML, "<b>Ana</b><br>Sales")
+	if body != "Hi\n\n-- \nAna\nSales\n" {
+		t.Fatalf("body = %q", body)
+	}
+	if !strings.HasSuffix(bodyHTML, `-- <br><div class="gmail_signature"><b>Ana</b><br>Sales</div>`) {
+		t.Fatalf("html = %q", bodyHTML)
+	}
+
+	body, bodyHTML = "Hi", ""
+	appendSignature(&body, &bodyHTML, "")
+	if body != "Hi" || bodyHTML != "" {
+		t.Fatalf("empty signature changed bodies: %q %q", body, bodyHTML)
+	}
+}
+
+func TestExecute_GmailSend_FromAliasWithSignature(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+
+	var raw string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/settings/sendAs/sales@corp.com"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"sendAsEmail":        "sales@corp.com",
+				"displayName":        "Corp Sales",
+				"verificationStatus": "accepted",
+				"signature":          "Corp Sales<br>+1 555 0100",
+			})
+		case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/messages/send"):
+			var msg gmail.Message
+			_ = json.NewDecoder(r.Body).Decode(&msg)
+			b, _ := base64.RawURLEncoding.DecodeString(msg.Raw)
+			raw = string(b)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1", "threadId": "t1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{
+				"--json", "--account", "a@b.com",
+				"gmail", "send", "--to", "x@y.com", "--subject", "S", "--body", "Hello",
+				"--from-alias", "sales@corp.com", "--with-signature",
+			}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(raw, "From: Corp Sales <sales@corp.com>\r\n") {
+		t.Fatalf("missing alias From in:\n%s", raw)
+	}
+	if !strings.Contains(raw, "-- \r\nCorp Sales\r\n+1 555 0100") {
+		t.Fatalf("missing signature in:\n%s", raw)
+	}
+
+	_ = captureStderr(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "send", "--to", "x@y.com", "--subject", "S", "--body", "B", "--from", "a@b.com", "--from-alias", "sales@corp.com"}); err == nil {
+			t.Fatalf("expected --from/--from-alias conflict")
+		}
+	})
+}
+
+func TestExecute_GmailSendAsSignatureSetGet(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	current := map[string]any{"sendAsEmail": "me@example.com", "displayName": "Me", "signature": "<p>Old</p>"}
+	var put map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/settings/sendAs/me@example.com") {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodPut {
+			_ = json.NewDecoder(r.Body).Decode(&put)
+			current = put
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(current)
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	path := filepath.Join(t.TempDir(), "sig.html")
+	if err := os.WriteFile(path, []byte("<b>Ana</b><br>Sales\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "me@example.com", "gmail", "sendas", "signature", "set", "me@example.com", "--file", path}); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	})
+	if put["signature"] != "<b>Ana</b><br>Sales" || put["displayName"] != "Me" {
+		t.Fatalf("put = %v", put)
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "me@example.com", "gmail", "sendas", "signature", "get", "me@example.com", "--preview"}); err != nil {
+			t.Fatalf("get: %v", err)
+		}
+	})
+	if out != "Ana\nSales\n" {
+		t.Fatalf("preview = %q", out)
+	}
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	// gmailMaxMessageBytes is Gmail's limit for a whole message, measured
+	// after MIME encoding (base64 attachments are ~4/3 of the file size).
+	gmailMaxMessageBytes = 25 << 20
+	// gmailSizeWarnDefault is the GOG_GMAIL_SIZE_WARN default.
+	gmailSizeWarnDefault = 20 << 20
+)
+
+// checkGmailMessageSize fails when the encoded message exceeds Gmail's limit
+// and warns above GOG_GMAIL_SIZE_WARN (bytes or KB/MB; 0 disables).
+func checkGmailMessageSize(u *ui.UI, raw []byte) error {
+	return checkGmailMessageBytes(u, int64(len(raw)))
+}
+
+func checkGmailMessageBytes(u *ui.UI, size int64) error {
+	if size > gmailMaxMessageBytes {
+		return fmt.Errorf("message is %s after encoding, over Gmail's 25 MB limit; upload large files with `gog drive upload` and share the link instead", formatDriveSize(size))
+	}
+	warnAt := int64(gmailSizeWarnDefault)
+	if v := strings.TrimSpace(os.Getenv("GOG_GMAIL_SIZE_WARN")); v != "" {
+		parsed, err := parseByteSize(v)
+		if err != nil {
+			return fmt.Errorf("invalid GOG_GMAIL_SIZE_WARN: %w", err)
+		}
+		warnAt = parsed
+	}
+	if warnAt > 0 && size > warnAt && u != nil {
+		u.Err().Printf("WARN: message is %s after encoding (Gmail limit: 25 MB); consider sharing large files via `gog drive upload`", formatDriveSize(size))
+	}
+	return nil
+}
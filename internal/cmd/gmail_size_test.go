@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func TestCheckGmailMessageSize(t *testing.T) {
+	var stderr strings.Builder
+	u, err := ui.New(ui.Options{Stdout: &strings.Builder{}, Stderr: &stderr, Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+
+	if err := checkGmailMessageSize(u, make([]byte, 1024)); err != nil || stderr.Len() != 0 {
+		t.Fatalf("small message: err=%v stderr=%q", err, stderr.String())
+	}
+
+	err = checkGmailMessageSize(u, make([]byte, gmailMaxMessageBytes+1))
+	if err == nil || !strings.Contains(err.Error(), "25 MB") || !strings.Contains(err.Error(), "drive upload") {
+		t.Fatalf("expected limit error, got %v", err)
+	}
+
+	t.Setenv("GOG_GMAIL_SIZE_WARN", "1KB")
+	if err := checkGmailMessageSize(u, make([]byte, 2048)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "WARN: message is 2.0 KB") {
+		t.Fatalf("expected warning, got %q", stderr.String())
+	}
+
+	t.Setenv("GOG_GMAIL_SIZE_WARN", "nope")
+	if err := checkGmailMessageSize(u, []byte("x")); err == nil {
+		t.Fatalf("expected invalid threshold error")
+	}
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// gmailSuperstar is one of Gmail's extra stars ("Settings > General > Stars").
+// They are hidden system labels: not returned by labels.list, but accepted
+// by messages.modify and searchable with has:<name>.
+type gmailSuperstar struct {
+	Name    string
+	LabelID string
+}
+
+var gmailSuperstars = []gmailSuperstar{
+	{"yellow-star", "^ss_sy"},
+	{"orange-star", "^ss_so"},
+	{"red-star", "^ss_sr"},
+	{"purple-star", "^ss_sp"},
+	{"blue-star", "^ss_sb"},
+	{"green-star", "^ss_sg"},
+	{"red-bang", "^ss_cr"},
+	{"orange-guillemet", "^ss_co"},
+	{"yellow-bang", "^ss_cy"},
+	{"green-check", "^ss_cg"},
+	{"blue-info", "^ss_cb"},
+	{"purple-question", "^ss_cp"},
+}
+
+// parseSuperstar accepts a star name with or without the -star suffix
+// (yellow, red-star, green-check, ...).
+func parseSuperstar(raw string) (gmailSuperstar, error) {
+	name := strings.ToLower(strings.TrimSpace(raw))
+	for _, s := range gmailSuperstars {
+		if name == s.Name || name+"-star" == s.Name {
+			return s, nil
+		}
+	}
+	names := make([]string, 0, len(gmailSuperstars))
+	for _, s := range gmailSuperstars {
+		names = append(names, strings.TrimSuffix(s.Name, "-star"))
+	}
+	return gmailSuperstar{}, usagef("unknown star %q (expected %s)", raw, strings.Join(names, "|"))
+}
+
+// superstarOf returns the star name for a message's labels ("" if none).
+func superstarOf(labelIDs []string) string {
+	for _, id := range labelIDs {
+		for _, s := range gmailSuperstars {
+			if id == s.LabelID {
+				return s.Name
+			}
+		}
+	}
+	return ""
+}
+
+func superstarLabelIDs() []string {
+	ids := make([]string, 0, len(gmailSuperstars))
+	for _, s := range gmailSuperstars {
+		ids = append(ids, s.LabelID)
+	}
+	return ids
+}
+
+// wrapSuperstarError explains the usual cause of a rejected superstar label.
+func wrapSuperstarError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest {
+		return fmt.Errorf("%w (enable the star in Gmail Settings > General > Stars first)", err)
+	}
+	return err
+}
+
+func newGmailStarCmd(flags *rootFlags) *cobra.Command {
+	var color string
+
+	cmd := &cobra.Command{
+		Use:   "star <messageId...>",
+		Short: "Star messages (optionally with a colored superstar)",
+		Long: `Star messages. --color picks one of Gmail's superstars (yellow, orange, red,
+purple, blue, green, red-bang, orange-guillemet, yellow-bang, green-check,
+blue-info, purple-question) and replaces any other superstar on the message.
+The star must be enabled in Gmail Settings > General > Stars.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			req := &gmail.ModifyMessageRequest{AddLabelIds: []string{"STARRED"}}
+			star := ""
+			if strings.TrimSpace(color) != "" {
+				s, err := parseSuperstar(color)
+				if err != nil {
+					return err
+				}
+				star = s.Name
+				req.AddLabelIds = append(req.AddLabelIds, s.LabelID)
+				for _, id := range superstarLabelIDs() {
+					if id != s.LabelID {
+						req.RemoveLabelIds = append(req.RemoveLabelIds, id)
+					}
+				}
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			for _, id := range args {
+				if _, err := svc.Users.Messages.Modify("me", id, req).Context(cmd.Context()).Do(); err != nil {
+					return fmt.Errorf("%s: %w", id, wrapSuperstarError(err))
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"starred": args,
+					"star":    star,
+				})
+			}
+			for _, id := range args {
+				if star != "" {
+					u.Out().Printf("%s\t%s", id, star)
+				} else {
+					u.Out().Printf("%s\tstarred", id)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&color, "color", "", "Superstar: yellow|orange|red|purple|blue|green|red-bang|orange-guillemet|yellow-bang|green-check|blue-info|purple-question")
+	return cmd
+}
+
+func newGmailUnstarCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unstar <messageId...>",
+		Short: "Remove stars (including superstars) from messages",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			for _, id := range args {
+				// Only remove superstars the message has; accounts without
+				// superstars enabled reject the hidden label IDs.
+				msg, err := svc.Users.Messages.Get("me", id).Format("minimal").Context(cmd.Context()).Do()
+				if err != nil {
+					return fmt.Errorf("%s: %w", id, err)
+				}
+				remove := []string{"STARRED"}
+				for _, l := range msg.LabelIds {
+					if strings.HasPrefix(l, "^ss_") {
+						remove = append(remove, l)
+					}
+				}
+				if _, err := svc.Users.Messages.Modify("me", id, &gmail.ModifyMessageRequest{RemoveLabelIds: remove}).Context(cmd.Context()).Do(); err != nil {
+					return fmt.Errorf("%s: %w", id, err)
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"unstarred": args})
+			}
+			for _, id := range args {
+				u.Out().Printf("%s\tunstarred", id)
+			}
+			return nil
+		},
+	}
+}
+
+func newGmailStarredCmd(flags *rootFlags) *cobra.Command {
+	var color string
+	var max int64
+	var page string
+	var pages pageFlags
+
+	cmd := &cobra.Command{
+		Use:   "starred",
+		Short: "List starred messages with their star",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			query := "is:starred"
+			if strings.TrimSpace(color) != "" {
+				s, err := parseSuperstar(color)
+				if err != nil {
+					return err
+				}
+				query = "has:" + s.Name
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			refs, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*gmail.Message, string, error) {
+				resp, err := svc.Users.Messages.List("me").Q(query).MaxResults(max).PageToken(pageToken).Context(cmd.Context()).Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Messages, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(refs))
+			if err != nil {
+				return err
+			}
+			idToName, err := fetchLabelIDToName(cmd.Context(), svc)
+			if err != nil {
+				return err
+			}
+			items, err := fetchMessageDetails(cmd.Context(), svc, refs, idToName)
+			if err != nil {
+				return err
+			}
+
+			type starredItem struct {
+				messageItem
+				Star string `json:"star"`
+			}
+			rows := make([]starredItem, 0, len(items))
+			for _, it := range items {
+				star := superstarOf(it.Labels)
+				if star == "" {
+					star = "starred"
+				}
+				rows = append(rows, starredItem{messageItem: it, Star: star})
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"messages":      rows,
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
+			}
+			if len(rows) == 0 {
+				u.Err().Println("No starred messages")
+				return nil
+			}
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "ID\tSTAR\tDATE\tFROM\tSUBJECT")
+			for _, r := range rows {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.ID, r.Star, r.Date, r.From, r.Subject)
+			}
+			printNextPageHint(u, nextPageToken)
+			return partial
+		},
+	}
+
+	cmd.Flags().StringVar(&color, "color", "", "Only this superstar (e.g. red, green-check)")
+	cmd.Flags().Int64Var(&max, "max", 20, "Max results")
+	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestParseSuperstar(t *testing.T) {
+	for raw, want := range map[string]string{"red": "^ss_sr", "Red-Star": "^ss_sr", "green-check": "^ss_cg"} {
+		s, err := parseSuperstar(raw)
+		if err != nil || s.LabelID != want {
+			t.Fatalf("%s: got %v %v", raw, s, err)
+		}
+	}
+	if _, err := parseSuperstar("pink"); err == nil {
+		t.Fatalf("expected error for unknown star")
+	}
+	if got := superstarOf([]string{"INBOX", "STARRED", "^ss_cb"}); got != "blue-info" {
+		t.Fatalf("unexpected superstar %q", got)
+	}
+}
+
+func TestExecute_GmailStar_Color(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var modify []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/messages/m1/modify"):
+			b, _ := io.ReadAll(r.Body)
+			modify = append(modify, string(b))
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1"})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages/m1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1", "labelIds": []string{"INBOX", "STARRED", "^ss_sr"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "star", "m1", "--color", "red"}); err != nil {
+			t.Fatalf("star: %v", err)
+		}
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "unstar", "m1"}); err != nil {
+			t.Fatalf("unstar: %v", err)
+		}
+	})
+	if len(modify) != 2 {
+		t.Fatalf("expected 2 modify calls, got %v", modify)
+	}
+	if !strings.Contains(modify[0], `"addLabelIds":["STARRED","^ss_sr"]`) || !strings.Contains(modify[0], `"^ss_sy"`) || strings.Contains(modify[0], `"removeLabelIds":["^ss_sr"`) {
+		t.Fatalf("unexpected star modify %s", modify[0])
+	}
+	if !strings.Contains(modify[1], `"removeLabelIds":["STARRED","^ss_sr"]`) {
+		t.Fatalf("unexpected unstar modify %s", modify[1])
+	}
+}
+
+func TestExecute_GmailStarred_JSON(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "STARRED", "name": "STARRED", "type": "system"}}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages"):
+			query = r.URL.Query().Get("q")
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1", "threadId": "t1"}}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages/m1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":       "m1",
+				"threadId": "t1",
+				"labelIds": []string{"STARRED", "^ss_cg"},
+				"payload": map[string]any{"headers": []map[string]any{
+					{"name": "From", "value": "a@example.com"},
+					{"name": "Subject", "value": "Pinned"},
+				}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "starred", "--color", "green-check"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	if query != "has:green-check" {
+		t.Fatalf("unexpected query %q", query)
+	}
+	var parsed struct {
+		Messages []struct {
+			ID   string `json:"id"`
+			Star string `json:"star"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil || len(parsed.Messages) != 1 || parsed.Messages[0].Star != "green-check" {
+		t.Fatalf("unexpected out %q err=%v", out, err)
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// Reply and forward subject prefixes as mail clients emit them in various
+// languages: Re/AW/Antw/SV/VS/RES/RIF/R/Odp/YNT/回复 for replies and
+// Fwd/FW/WG/TR/ENC/RV/Doorst/VL/PD/转发 for forwards. Counters such as
+// "Re[2]:" and full-width colons are accepted.
+const (
+	replyPrefixes   = `re|aw|antw|sv|vs|res|rif|r|odp|ynt|回复|回覆|答复`
+	forwardPrefixes = `fwd?|wg|tr|enc|rv|doorst|vl|pd|转发|轉寄`
+
+	defaultReplyPrefix = "Re:"
+)
+
+var (
+	replyPrefixRe     = regexp.MustCompile(`(?i)^\s*((` + replyPrefixes + `|` + forwardPrefixes + `)(\[\d+\])?\s*[:：]\s*)+`)
+	replyOnlyPrefixRe = regexp.MustCompile(`(?i)^\s*((` + replyPrefixes + `)(\[\d+\])?\s*[:：]\s*)+`)
+)
+
+// normalizeSubject strips reply/forward prefixes the way Gmail compares
+// subjects when threading.
+func normalizeSubject(s string) string {
+	return strings.Join(strings.Fields(replyPrefixRe.ReplaceAllString(s, "")), " ")
+}
+
+// replySubject replaces any leading (localized, possibly stacked) reply
+// prefixes of subject with the configured gmail.replyPrefix, so "AW: Re:
+// Plans" becomes "Re: Plans". Forward prefixes after them are kept.
+func replySubject(subject string) string {
+	rest := strings.TrimSpace(replyOnlyPrefixRe.ReplaceAllString(subject, ""))
+	prefix := replySubjectPrefix()
+	if rest == "" {
+		return prefix
+	}
+	return prefix + " " + rest
+}
+
+func replySubjectPrefix() string {
+	cfg, err := config.ReadConfigFile()
+	if err != nil {
+		slog.Debug("read config for reply prefix", "err", err)
+		return defaultReplyPrefix
+	}
+	if p := strings.TrimSpace(cfg.Gmail.ReplyPrefix); p != "" {
+		return p
+	}
+	return defaultReplyPrefix
+}
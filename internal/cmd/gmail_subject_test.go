@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplySubject(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	for in, want := range map[string]string{
+		"Plans":             "Re: Plans",
+		"AW: Re: SV: Plans": "Re: Plans",
+		"RES[2]: Proposta":  "Re: Proposta",
+		"Fwd: Plans":        "Re: Fwd: Plans",
+		"Regarding plans":   "Re: Regarding plans",
+		"":                  "Re:",
+	} {
+		if got := replySubject(in); got != want {
+			t.Fatalf("%q: got %q, want %q", in, got, want)
+		}
+	}
+
+	dir := t.TempDir()
+	t.Setenv("GOG_CONFIG_DIR", dir)
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"gmail":{"replyPrefix":"AW:"}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := replySubject("Re: Angebot"); got != "AW: Angebot" {
+		t.Fatalf("configured prefix: got %q", got)
+	}
+}
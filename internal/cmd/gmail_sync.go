@@ -0,0 +1,373 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+type gmailSyncResult struct {
+	Mode      string `json:"mode"`
+	Added     int    `json:"added"`
+	Updated   int    `json:"updated"`
+	Deleted   int    `json:"deleted"`
+	Messages  int    `json:"messages"`
+	HistoryID string `json:"historyId"`
+}
+
+func newGmailSyncCmd(flags *rootFlags) *cobra.Command {
+	var rebuild bool
+	var bodies bool
+	var max int64
+	var ttl time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Keep a local cache of message metadata up to date",
+		Long: `Sync a local cache of message metadata (headers, labels, snippets) for the
+account. The first run (or --rebuild) loads the newest --max messages; later
+runs apply only the changes since the cached history ID. A cache Gmail can no
+longer catch up (history older than about a week) is rebuilt.
+
+While a cache exists, gmail search reuses the thread details it fetched
+before as long as Gmail reports the thread unchanged and the copy is younger
+than --ttl (kept in the cache; default 24h). gmail search --no-sync-cache skips
+the cache. The cache lives in the gog cache dir and is safe to delete.
+
+--bodies also stores each message's text (up to 32 KB) so gmail search
+--local can match bodies; the choice sticks until the next --rebuild.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if max <= 0 {
+				return usage("--max must be > 0")
+			}
+			if cmd.Flags().Changed("ttl") && ttl <= 0 {
+				return usage("--ttl must be positive")
+			}
+
+			cache, err := loadGmailCache(account)
+			if err != nil {
+				if !rebuild {
+					return err
+				}
+				cache = nil
+			}
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			if cache != nil && bodies && !cache.Bodies {
+				u.Err().Println("Cache has no message bodies; rebuilding")
+				rebuild = true
+			}
+
+			var res gmailSyncResult
+			if cache == nil || rebuild || cache.HistoryID == "" {
+				path, err := gmailCachePath(account)
+				if err != nil {
+					return err
+				}
+				fresh := newGmailCache(account, path)
+				if cache != nil {
+					fresh.TTLSeconds = cache.TTLSeconds
+				}
+				fresh.Bodies = bodies
+				cache = fresh
+				if res, err = fullGmailSync(cmd.Context(), svc, cache, max); err != nil {
+					return err
+				}
+			} else {
+				threads, openErr := openGmailThreadCache(account)
+				if openErr != nil {
+					return openErr
+				}
+				res, err = incrementalGmailSync(cmd.Context(), svc, cache, threads)
+				var apiErr *googleapi.Error
+				if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+					u.Err().Printf("WARN: cached history %s expired; rebuilding the cache", cache.HistoryID)
+					ttlSeconds, withBodies := cache.TTLSeconds, cache.Bodies
+					cache = newGmailCache(account, cache.path)
+					cache.TTLSeconds, cache.Bodies = ttlSeconds, withBodies
+					res, err = fullGmailSync(cmd.Context(), svc, cache, max)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			if cache.LabelNames, err = fetchLabelIDToName(cmd.Context(), svc); err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("ttl") {
+				cache.TTLSeconds = int64(ttl / time.Second)
+			}
+			cache.SyncedAtMs = time.Now().UnixMilli()
+			if err := cache.save(); err != nil {
+				return err
+			}
+			if _, err := setupGmailThreadCache(account, cache.TTLSeconds, res.Mode == "full"); err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"sync": res})
+			}
+			u.Out().Printf("mode\t%s", res.Mode)
+			u.Out().Printf("added\t%d", res.Added)
+			u.Out().Printf("updated\t%d", res.Updated)
+			u.Out().Printf("deleted\t%d", res.Deleted)
+			u.Out().Printf("messages\t%d", res.Messages)
+			u.Out().Printf("history_id\t%s", res.HistoryID)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&rebuild, "rebuild", false, "Discard the cache and load it again")
+	cmd.Flags().BoolVar(&bodies, "bodies", false, "Also store message text for gmail search --local (rebuilds a cache without it)")
+	cmd.Flags().Int64Var(&max, "max", defaultGmailSyncMax, "Newest messages to load on a full sync")
+	cmd.Flags().DurationVar(&ttl, "ttl", defaultGmailCacheTTL, "How long gmail search may reuse cached thread details")
+	cmd.AddCommand(newGmailSyncStatusCmd(flags))
+	return cmd
+}
+
+func newGmailSyncStatusCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the local message cache",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			cache, err := loadGmailCache(account)
+			if err != nil {
+				return err
+			}
+			if cache == nil {
+				if outfmt.IsJSON(cmd.Context()) {
+					return outfmt.WriteJSON(os.Stdout, map[string]any{"cache": nil})
+				}
+				u.Err().Println("No cache (run gmail sync)")
+				return nil
+			}
+			threads, err := openGmailThreadCache(account)
+			if err != nil {
+				return err
+			}
+			var size int64
+			if st, err := os.Stat(cache.path); err == nil {
+				size = st.Size()
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"cache": map[string]any{
+					"path":       cache.path,
+					"account":    cache.Account,
+					"messages":   len(cache.Messages),
+					"threads":    threads.count(),
+					"historyId":  cache.HistoryID,
+					"syncedAt":   formatUnixMillis(cache.SyncedAtMs),
+					"ttlSeconds": int64(cache.ttl() / time.Second),
+					"sizeBytes":  size,
+				}})
+			}
+			u.Out().Printf("path\t%s", cache.path)
+			u.Out().Printf("messages\t%d", len(cache.Messages))
+			u.Out().Printf("threads\t%d", threads.count())
+			u.Out().Printf("history_id\t%s", cache.HistoryID)
+			u.Out().Printf("synced_at\t%s", formatUnixMillis(cache.SyncedAtMs))
+			u.Out().Printf("ttl\t%s", cache.ttl())
+			u.Out().Printf("size\t%s", formatDriveSize(size))
+			return nil
+		},
+	}
+}
+
+// fullGmailSync loads the newest max messages. The history ID is taken
+// first so changes made while listing are picked up by the next sync.
+func fullGmailSync(ctx context.Context, svc *gmail.Service, cache *gmailCache, max int64) (gmailSyncResult, error) {
+	profile, err := svc.Users.GetProfile("me").Context(ctx).Do()
+	if err != nil {
+		return gmailSyncResult{}, err
+	}
+	var ids []string
+	pageToken := ""
+	for int64(len(ids)) < max {
+		call := svc.Users.Messages.List("me").MaxResults(min(max-int64(len(ids)), 500)).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return gmailSyncResult{}, err
+		}
+		for _, m := range resp.Messages {
+			if m != nil && m.Id != "" {
+				ids = append(ids, m.Id)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	msgs, err := fetchCacheMessages(ctx, svc, ids, cache.Bodies)
+	if err != nil {
+		return gmailSyncResult{}, err
+	}
+	for _, m := range msgs {
+		cache.Messages[m.ID] = m
+	}
+	cache.HistoryID = formatHistoryID(profile.HistoryId)
+	return gmailSyncResult{
+		Mode:      "full",
+		Added:     len(msgs),
+		Messages:  len(cache.Messages),
+		HistoryID: cache.HistoryID,
+	}, nil
+}
+
+// incrementalGmailSync applies history since the cached history ID.
+// Threads touched by a change are dropped from the thread cache.
+func incrementalGmailSync(ctx context.Context, svc *gmail.Service, cache *gmailCache, threads *gmailThreadCache) (gmailSyncResult, error) {
+	startID, err := parseHistoryID(cache.HistoryID)
+	if err != nil {
+		return gmailSyncResult{}, err
+	}
+	res := gmailSyncResult{Mode: "incremental"}
+	latest := startID
+	var events []historyEvent
+	pageToken := ""
+	for {
+		call := svc.Users.History.List("me").StartHistoryId(startID).HistoryTypes(gmailHistoryTypes...).MaxResults(500).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return gmailSyncResult{}, err
+		}
+		events = append(events, historyEvents(resp)...)
+		if resp.HistoryId > latest {
+			latest = resp.HistoryId
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	// Refetch every added or relabeled message once; deletions win.
+	deleted := map[string]bool{}
+	changed := map[string]bool{}
+	var refetch []string
+	for _, ev := range events {
+		if err := threads.drop(ev.ThreadID); err != nil {
+			return gmailSyncResult{}, err
+		}
+		switch ev.Type {
+		case "messageDeleted":
+			deleted[ev.MessageID] = true
+		default:
+			if !changed[ev.MessageID] {
+				changed[ev.MessageID] = true
+				refetch = append(refetch, ev.MessageID)
+			}
+		}
+	}
+	for id := range deleted {
+		if _, ok := cache.Messages[id]; ok {
+			delete(cache.Messages, id)
+			res.Deleted++
+		}
+	}
+	ids := refetch[:0]
+	for _, id := range refetch {
+		if !deleted[id] {
+			ids = append(ids, id)
+		}
+	}
+	msgs, err := fetchCacheMessages(ctx, svc, ids, cache.Bodies)
+	if err != nil {
+		return gmailSyncResult{}, err
+	}
+	for _, m := range msgs {
+		if _, ok := cache.Messages[m.ID]; ok {
+			res.Updated++
+		} else {
+			res.Added++
+		}
+		cache.Messages[m.ID] = m
+	}
+
+	cache.HistoryID = formatHistoryID(latest)
+	res.Messages = len(cache.Messages)
+	res.HistoryID = cache.HistoryID
+	return res, nil
+}
+
+// fetchCacheMessages fetches metadata (or, with bodies, full messages) for
+// ids in parallel. Messages deleted in the meantime (404) are skipped.
+func fetchCacheMessages(ctx context.Context, svc *gmail.Service, ids []string, bodies bool) ([]*cachedMessage, error) {
+	out := make([]*cachedMessage, len(ids))
+	errs := make([]error, len(ids))
+	sem := make(chan struct{}, gmailSyncFetchParallel)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			call := svc.Users.Messages.Get("me", id)
+			if bodies {
+				call = call.Format("full")
+			} else {
+				call = call.Format("metadata").MetadataHeaders("From", "To", "Cc", "Subject", "Date", "Message-ID")
+			}
+			msg, err := call.Context(ctx).Do()
+			if err != nil {
+				var apiErr *googleapi.Error
+				if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+					return
+				}
+				errs[i] = err
+				return
+			}
+			out[i] = cachedMessageFrom(msg)
+		}(i, id)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	msgs := out[:0]
+	for _, m := range out {
+		if m != nil {
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs, nil
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_GmailSync_FullThenIncremental(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	t.Setenv("GOG_CACHE_DIR", t.TempDir())
+
+	message := func(id, thread, subject string, labels ...string) map[string]any {
+		return map[string]any{
+			"id": id, "threadId": thread, "historyId": "90", "labelIds": labels,
+			"payload": map[string]any{"headers": []map[string]any{
+				{"name": "From", "value": "a@example.com"},
+				{"name": "Subject", "value": subject},
+			}},
+		}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/users/me/profile"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"emailAddress": "a@b.com", "historyId": "100"})
+		case strings.HasSuffix(path, "/users/me/messages"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1"}, {"id": "m2"}}})
+		case strings.HasSuffix(path, "/users/me/messages/m1"):
+			_ = json.NewEncoder(w).Encode(message("m1", "t1", "One", "INBOX", "STARRED"))
+		case strings.HasSuffix(path, "/users/me/messages/m2"):
+			_ = json.NewEncoder(w).Encode(message("m2", "t2", "Two", "INBOX"))
+		case strings.HasSuffix(path, "/users/me/messages/m3"):
+			_ = json.NewEncoder(w).Encode(message("m3", "t3", "Three", "INBOX"))
+		case strings.HasSuffix(path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX", "type": "system"}}})
+		case strings.HasSuffix(path, "/users/me/history"):
+			if got := r.URL.Query().Get("startHistoryId"); got != "100" {
+				t.Errorf("startHistoryId = %q", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"historyId": "130",
+				"history": []map[string]any{
+					{"id": "110", "messagesAdded": []map[string]any{{"message": map[string]any{"id": "m3", "threadId": "t3"}}}},
+					{"id": "120", "messagesDeleted": []map[string]any{{"message": map[string]any{"id": "m2", "threadId": "t2"}}}},
+					{"id": "125", "labelsAdded": []map[string]any{{"message": map[string]any{"id": "m1", "threadId": "t1"}, "labelIds": []string{"STARRED"}}}},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	sync := func() gmailSyncResult {
+		t.Helper()
+		out := captureStdout(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "sync"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+		var parsed struct {
+			Sync gmailSyncResult `json:"sync"`
+		}
+		if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+			t.Fatalf("json parse: %v\nout=%q", err, out)
+		}
+		return parsed.Sync
+	}
+
+	first := sync()
+	if first.Mode != "full" || first.Added != 2 || first.Messages != 2 || first.HistoryID != "100" {
+		t.Fatalf("full sync = %#v", first)
+	}
+	second := sync()
+	if second.Mode != "incremental" || second.Added != 1 || second.Updated != 1 || second.Deleted != 1 || second.Messages != 2 || second.HistoryID != "130" {
+		t.Fatalf("incremental sync = %#v", second)
+	}
+
+	cache, err := loadGmailCache("a@b.com")
+	if err != nil || cache == nil {
+		t.Fatalf("load cache: %v, %v", cache, err)
+	}
+	if _, ok := cache.Messages["m2"]; ok {
+		t.Fatalf("deleted message still cached")
+	}
+	if m := cache.Messages["m3"]; m == nil || m.Subject != "Three" || m.From != "a@example.com" {
+		t.Fatalf("m3 = %#v", m)
+	}
+}
+
+func TestExecute_GmailSearch_ReusesCachedThread(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	t.Setenv("GOG_CACHE_DIR", t.TempDir())
+
+	threadGets := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/users/me/threads"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"threads": []map[string]any{{"id": "t1", "historyId": "200"}}})
+		case strings.HasSuffix(path, "/users/me/threads/t1"):
+			threadGets++
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id": "t1", "historyId": "200",
+				"messages": []map[string]any{{
+					"id": "m1", "labelIds": []string{"INBOX"},
+					"payload": map[string]any{"headers": []map[string]any{{"name": "Subject", "value": "Hello"}}},
+				}},
+			})
+		case strings.HasSuffix(path, "/users/me/labels"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX", "type": "system"}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	path, err := gmailCachePath("a@b.com")
+	if err != nil {
+		t.Fatalf("cache path: %v", err)
+	}
+	synced := newGmailCache("a@b.com", path)
+	synced.Messages["m9"] = &cachedMessage{ID: "m9", ThreadID: "t9"}
+	if err := synced.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := setupGmailThreadCache("a@b.com", 0, true); err != nil {
+		t.Fatalf("setup thread cache: %v", err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cache: %v", err)
+	}
+
+	search := func(extra ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			args := append([]string{"--json", "--account", "a@b.com", "gmail", "search", "hello"}, extra...)
+			if err := Execute(args); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	}
+
+	search()
+	out := search()
+	if threadGets != 1 {
+		t.Fatalf("thread fetched %d times, want 1", threadGets)
+	}
+	if !strings.Contains(out, "Hello") {
+		t.Fatalf("cached search output = %q", out)
+	}
+	search("--no-sync-cache")
+	if threadGets != 2 {
+		t.Fatalf("--no-sync-cache fetched %d times, want 2", threadGets)
+	}
+
+	// Searches keep their threads out of the sync cache file, so they never
+	// rewrite what a concurrent sync saved.
+	if after, err := os.ReadFile(path); err != nil || string(after) != string(before) {
+		t.Fatalf("search rewrote the sync cache (err=%v)", err)
+	}
+	threads, err := openGmailThreadCache("a@b.com")
+	if err != nil || threads.count() != 1 {
+		t.Fatalf("thread cache = %v, %v", threads, err)
+	}
+	if err := threads.drop("t1"); err != nil {
+		t.Fatalf("drop: %v", err)
+	}
+	search()
+	if threadGets != 3 {
+		t.Fatalf("dropped thread fetched %d times, want 3", threadGets)
+	}
+}
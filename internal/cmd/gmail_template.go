@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// mailTemplate renders subject/body/HTML from a text/template file.
+//
+// The file may define "subject", "body", and "html" blocks:
+//
+//	{{define "subject"}}Hi {{.name}}{{end}}
+//	{{define "body"}}Hello {{.name}},
+//	...{{end}}
+//	{{define "html"}}<p>Hello {{.name | html}}</p>{{end}}
+//
+// Without a "body" block, the text outside any block is the plain body.
+type mailTemplate struct {
+	Path     string
+	Vars     []string
+	VarsFile string
+}
+
+func (t *mailTemplate) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&t.Path, "template", "", "Render subject/body/HTML from a Go text/template file")
+	cmd.Flags().StringArrayVar(&t.Vars, "vars", nil, "Template variable key=value (repeatable; overrides --vars-file)")
+	cmd.Flags().StringVar(&t.VarsFile, "vars-file", "", "Template variables as a JSON object")
+}
+
+func (t *mailTemplate) enabled() bool {
+	return strings.TrimSpace(t.Path) != ""
+}
+
+func (t *mailTemplate) data() (map[string]any, error) {
+	data := map[string]any{}
+	if path := strings.TrimSpace(t.VarsFile); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &data); err != nil {
+			return nil, fmt.Errorf("parse --vars-file %s: %w", path, err)
+		}
+	}
+	for _, kv := range t.Vars {
+		k, v, ok := strings.Cut(kv, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, usagef("invalid --vars %q (expected key=value)", kv)
+		}
+		data[k] = v
+	}
+	return data, nil
+}
+
+// apply fills subject/body/bodyHTML from the template. Parts already set via
+// flags conflict with the same part defined by the template.
+func (t *mailTemplate) apply(subject, body, bodyHTML *string) error {
+	if !t.enabled() {
+		if len(t.Vars) > 0 || strings.TrimSpace(t.VarsFile) != "" {
+			return usage("--vars/--vars-file require --template")
+		}
+		return nil
+	}
+	src, err := os.ReadFile(t.Path)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New("root").Option("missingkey=error").Parse(string(src))
+	if err != nil {
+		return fmt.Errorf("parse template %s: %w", t.Path, err)
+	}
+	data, err := t.data()
+	if err != nil {
+		return err
+	}
+
+	render := func(name string) (string, bool, error) {
+		tt := tmpl.Lookup(name)
+		if tt == nil {
+			return "", false, nil
+		}
+		var b strings.Builder
+		if err := tt.Execute(&b, data); err != nil {
+			return "", false, fmt.Errorf("render template %s: %w", t.Path, err)
+		}
+		return b.String(), true, nil
+	}
+	set := func(flag string, dst *string, value string) error {
+		if strings.TrimSpace(*dst) != "" {
+			return usagef("--%s conflicts with the template's %s", flag, flag)
+		}
+		*dst = value
+		return nil
+	}
+
+	if v, ok, err := render("subject"); err != nil {
+		return err
+	} else if ok {
+		if err := set("subject", subject, strings.TrimSpace(v)); err != nil {
+			return err
+		}
+	}
+	if v, ok, err := render("html"); err != nil {
+		return err
+	} else if ok {
+		if err := set("body-html", bodyHTML, v); err != nil {
+			return err
+		}
+	}
+	v, ok, err := render("body")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		v, _, err = render("root")
+		if err != nil {
+			return err
+		}
+		ok = strings.TrimSpace(v) != ""
+	}
+	if ok {
+		if err := set("body", body, strings.Trim(v, "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
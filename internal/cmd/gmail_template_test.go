@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplateFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return path
+}
+
+func TestMailTemplate_Blocks(t *testing.T) {
+	path := writeTemplateFile(t, "t.tmpl", `{{define "subject"}} Hi {{.name}} {{end}}
+{{define "body"}}Hello {{.name}}, order {{.order}}.{{end}}
+{{define "html"}}<p>Hello {{.name | html}}</p>{{end}}`)
+	varsFile := writeTemplateFile(t, "vars.json", `{"name":"Bob","order":42}`)
+
+	tm := mailTemplate{Path: path, VarsFile: varsFile, Vars: []string{"name=<Ada>"}}
+	var subject, body, html string
+	if err := tm.apply(&subject, &body, &html); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if subject != "Hi <Ada>" || body != "Hello <Ada>, order 42." || html != "<p>Hello &lt;Ada&gt;</p>" {
+		t.Fatalf("unexpected render: %q %q %q", subject, body, html)
+	}
+}
+
+func TestMailTemplate_RootBodyAndErrors(t *testing.T) {
+	path := writeTemplateFile(t, "t.tmpl", "{{define \"subject\"}}S{{end}}\nDear {{.name}}\n")
+
+	subject, body, html := "", "", ""
+	tm := mailTemplate{Path: path, Vars: []string{"name=Ada"}}
+	if err := tm.apply(&subject, &body, &html); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if subject != "S" || body != "Dear Ada" || html != "" {
+		t.Fatalf("unexpected render: %q %q %q", subject, body, html)
+	}
+
+	subject, body = "", ""
+	if err := (&mailTemplate{Path: path}).apply(&subject, &body, &html); err == nil || !strings.Contains(err.Error(), "name") {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+
+	subject, body = "explicit", ""
+	if err := tm.apply(&subject, &body, &html); err == nil {
+		t.Fatalf("expected conflict error")
+	}
+
+	if err := (&mailTemplate{Vars: []string{"a=b"}}).apply(&subject, &body, &html); err == nil {
+		t.Fatalf("expected --vars without --template error")
+	}
+	if err := (&mailTemplate{Path: path, Vars: []string{"novalue"}}).apply(new(string), new(string), new(string)); err == nil {
+		t.Fatalf("expected invalid --vars error")
+	}
+}
@@ -18,11 +18,29 @@ import (
 func newGmailThreadCmd(flags *rootFlags) *cobra.Command {
 	var download bool
 	var outDir string
+	var render string
+	var renderOut string
+	var keepQuotes bool
+	var noAvatars bool
+	var redactSpec string
 
 	cmd := &cobra.Command{
 		Use:   "thread <threadId>",
 		Short: "Get a thread with all messages (optionally download attachments)",
-		Args:  cobra.ExactArgs(1),
+		Long: `Get a thread with all messages (optionally download attachments).
+
+Output starts with a summary: message count, participants (From/To/Cc in
+order of first appearance), and last activity. JSON adds it as "summary".
+
+--render prints the thread as a readable conversation instead: each body
+decoded (text/plain, else the HTML as text), oldest message first, quoted
+replies collapsed (--keep-quotes shows them). --render=markdown prints a
+Markdown transcript and --render=html a standalone page with sender photos
+(cached, see gog people photo; --no-avatars skips them). --out FILE writes
+the rendering to a file (HTML for .html, else Markdown, unless --render is
+given). JSON carries the rendered messages. --redact emails,phones,amounts
+masks PII in the rendering (see gmail get --redact).`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			u := ui.FromContext(cmd.Context())
 			account, err := requireAccount(flags)
@@ -41,6 +59,22 @@ func newGmailThreadCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
+			red, err := parseRedact(redactSpec)
+			if err != nil {
+				return err
+			}
+			if red != nil && render == "" && renderOut == "" {
+				return usage("--redact requires --render or --out")
+			}
+
+			if render != "" || renderOut != "" {
+				var avatar func(string) string
+				if !noAvatars {
+					avatar = threadAvatarFunc(cmd, account)
+				}
+				return writeRenderedThread(cmd, thread, render, renderOut, keepQuotes, avatar, red)
+			}
+
 			var attachDir string
 			if download {
 				if strings.TrimSpace(outDir) == "" {
@@ -88,6 +122,7 @@ func newGmailThreadCmd(flags *rootFlags) *cobra.Command {
 				}
 				return outfmt.WriteJSON(os.Stdout, map[string]any{
 					"thread":     thread,
+					"summary":    summarizeThread(thread),
 					"downloaded": downloadedFiles,
 				})
 			}
@@ -96,6 +131,15 @@ func newGmailThreadCmd(flags *rootFlags) *cobra.Command {
 				return nil
 			}
 
+			summary := summarizeThread(thread)
+			u.Out().Printf("Thread: %s", thread.Id)
+			u.Out().Printf("Messages: %d", summary.Messages)
+			u.Out().Printf("Participants: %s", strings.Join(summary.participantEmails(), ", "))
+			if !summary.lastActivity.IsZero() {
+				u.Out().Printf("Last activity: %s", summary.lastActivity.Local().Format("2006-01-02 15:04"))
+			}
+			u.Out().Println("")
+
 			for _, msg := range thread.Messages {
 				if msg == nil {
 					continue
@@ -144,6 +188,14 @@ func newGmailThreadCmd(flags *rootFlags) *cobra.Command {
 
 	cmd.Flags().BoolVar(&download, "download", false, "Download attachments")
 	cmd.Flags().StringVar(&outDir, "out-dir", "", "Directory to write attachments to (default: current directory)")
+	cmd.Flags().StringVar(&render, "render", "", "Print as a readable conversation (--render=markdown|html for a transcript)")
+	cmd.Flags().Lookup("render").NoOptDefVal = threadRenderText
+	cmd.Flags().StringVar(&renderOut, "out", "", "Write the rendered thread to this file (Markdown by default)")
+	cmd.Flags().BoolVar(&keepQuotes, "keep-quotes", false, "With --render: keep quoted replies")
+	cmd.Flags().StringVar(&redactSpec, "redact", "", "With --render: mask PII (emails,phones,amounts,cards or all)")
+	cmd.Flags().BoolVar(&noAvatars, "no-avatars", false, "With --render=html: don't look up sender photos")
+	cmd.AddCommand(newGmailThreadModifyCmd(flags))
+	cmd.AddCommand(newGmailThreadAdoptCmd(flags))
 	return cmd
 }
 
@@ -203,6 +255,40 @@ func collectAttachments(p *gmail.MessagePart) []attachmentInfo {
 	return out
 }
 
+// threadAvatarFunc returns sender photos as data URIs from the avatar
+// cache. Lookup failures (e.g. no contacts scope) warn once and disable it.
+func threadAvatarFunc(cmd *cobra.Command, account string) func(string) string {
+	u := ui.FromContext(cmd.Context())
+	cache, err := newAvatarCache(account)
+	if err != nil {
+		return nil
+	}
+	seen := map[string]string{}
+	return func(from string) string {
+		if cache == nil {
+			return ""
+		}
+		email := senderAddress(from)
+		if src, ok := seen[email]; ok {
+			return src
+		}
+		data, err := cache.get(cmd.Context(), email)
+		if err != nil {
+			u.Err().Printf("WARN: sender photos unavailable: %v", err)
+			cache = nil
+			return ""
+		}
+		src := ""
+		if data != nil {
+			src = avatarDataURI(data)
+		}
+		seen[email] = src
+		return src
+	}
+}
+
+// bestBodyText returns the text/plain body, or the text/html body converted
+// to plain text when the message has no plain part.
 func bestBodyText(p *gmail.MessagePart) string {
 	if p == nil {
 		return ""
@@ -211,8 +297,10 @@ func bestBodyText(p *gmail.MessagePart) string {
 	if plain != "" {
 		return plain
 	}
-	html := findPartBody(p, "text/html")
-	return html
+	if html := findPartBody(p, "text/html"); html != "" {
+		return htmlToText(html)
+	}
+	return ""
 }
 
 func findPartBody(p *gmail.MessagePart, mimeType string) string {
@@ -222,7 +310,7 @@ func findPartBody(p *gmail.MessagePart, mimeType string) string {
 	if p.MimeType == mimeType && p.Body != nil && p.Body.Data != "" {
 		s, err := decodeBase64URL(p.Body.Data)
 		if err == nil {
-			return s
+			return decodeCharset([]byte(s), partCharset(p))
 		}
 	}
 	for _, part := range p.Parts {
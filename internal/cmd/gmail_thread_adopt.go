@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+	gapi "google.golang.org/api/googleapi"
+)
+
+// gmailThreadMaxMessages is the point where Gmail starts a new thread.
+const gmailThreadMaxMessages = 100
+
+// threadingDiagnostics explains why Gmail kept msg out of thread. Gmail
+// threads a message when its subject matches and its In-Reply-To/References
+// name a message already in the thread.
+func threadingDiagnostics(msg *gmail.Message, thread *gmail.Thread) []string {
+	var out []string
+	if strings.TrimSpace(headerValue(msg.Payload, "Message-ID")) == "" {
+		out = append(out, "message has no Message-ID header")
+	}
+	if len(thread.Messages) >= gmailThreadMaxMessages {
+		out = append(out, fmt.Sprintf("thread already has %d messages (Gmail starts a new thread at %d)", len(thread.Messages), gmailThreadMaxMessages))
+	}
+
+	var threadSubject string
+	threadIDs := map[string]bool{}
+	for _, m := range thread.Messages {
+		if m == nil {
+			continue
+		}
+		if threadSubject == "" {
+			threadSubject = headerValue(m.Payload, "Subject")
+		}
+		if id := strings.TrimSpace(headerValue(m.Payload, "Message-ID")); id != "" {
+			threadIDs[id] = true
+		}
+	}
+	subject := headerValue(msg.Payload, "Subject")
+	if !strings.EqualFold(normalizeSubject(subject), normalizeSubject(threadSubject)) {
+		out = append(out, fmt.Sprintf("subject %q does not match the thread's %q (ignoring Re:/Fwd: prefixes)", subject, threadSubject))
+	}
+
+	refs := strings.Fields(headerValue(msg.Payload, "In-Reply-To") + " " + headerValue(msg.Payload, "References"))
+	switch {
+	case len(refs) == 0:
+		out = append(out, "message has no In-Reply-To or References header")
+	default:
+		linked := false
+		for _, r := range refs {
+			if threadIDs[r] {
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			out = append(out, "In-Reply-To/References name no message in the thread (e.g. a system that rewrote Message-IDs)")
+		}
+	}
+	return out
+}
+
+// setRawHeaders replaces (or adds) the given top-level headers of an RFC822
+// message, leaving the body untouched.
+func setRawHeaders(raw []byte, headers [][2]string) []byte {
+	sep := []byte("\r\n\r\n")
+	nl := "\r\n"
+	idx := bytes.Index(raw, sep)
+	if lf := bytes.Index(raw, []byte("\n\n")); idx < 0 || (lf >= 0 && lf < idx) {
+		sep, nl, idx = []byte("\n\n"), "\n", lf
+	}
+	if idx < 0 {
+		idx, sep = len(raw), nil
+	}
+	head, body := string(raw[:idx]), raw[idx:]
+
+	replace := map[string]bool{}
+	for _, h := range headers {
+		replace[strings.ToLower(h[0])] = true
+	}
+	var kept []string
+	skipping := false
+	for _, line := range strings.Split(strings.TrimRight(head, "\r\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line != "" && (line[0] == ' ' || line[0] == '\t') {
+			if !skipping {
+				kept = append(kept, line)
+			}
+			continue
+		}
+		name, _, _ := strings.Cut(line, ":")
+		skipping = replace[strings.ToLower(strings.TrimSpace(name))]
+		if !skipping {
+			kept = append(kept, line)
+		}
+	}
+	for _, h := range headers {
+		if h[1] != "" {
+			kept = append(kept, h[0]+": "+h[1])
+		}
+	}
+	out := []byte(strings.Join(kept, nl))
+	if sep == nil {
+		return append(out, []byte(nl+nl)...)
+	}
+	return append(out, body...)
+}
+
+func newGmailThreadAdoptCmd(flags *rootFlags) *cobra.Command {
+	var into string
+	var dryRun bool
+	var keepOriginal bool
+
+	cmd := &cobra.Command{
+		Use:   "adopt <messageId> --into <threadId>",
+		Short: "Move a message into another thread by repairing its threading headers",
+		Long: `Move a message that Gmail threaded apart into an existing thread.
+
+Gmail only threads a message whose subject matches and whose In-Reply-To/
+References name a message in the thread; systems that rewrite Message-IDs or
+subjects break that. adopt prints why the message was kept apart, then
+re-imports a copy with In-Reply-To/References pointing at the thread's newest
+message and the thread's subject, into the thread, with the original labels.
+The original is moved to trash unless --keep-original.
+
+--dry-run only prints the diagnostics and the headers that would change.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			messageID := strings.TrimSpace(args[0])
+			into = strings.TrimSpace(into)
+			if into == "" {
+				return usage("--into <threadId> is required")
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			thread, err := svc.Users.Threads.Get("me", into).
+				Format("metadata").
+				MetadataHeaders("Subject", "Message-ID", "References").
+				Context(cmd.Context()).
+				Do()
+			if err != nil {
+				return err
+			}
+			if len(thread.Messages) == 0 {
+				return usagef("thread %s has no messages", into)
+			}
+			meta, err := svc.Users.Messages.Get("me", messageID).
+				Format("metadata").
+				MetadataHeaders("Subject", "Message-ID", "In-Reply-To", "References").
+				Context(cmd.Context()).
+				Do()
+			if err != nil {
+				return err
+			}
+			if meta.ThreadId == into {
+				return usagef("message %s is already in thread %s", messageID, into)
+			}
+
+			reasons := threadingDiagnostics(meta, thread)
+			parent := thread.Messages[len(thread.Messages)-1]
+			parentID := strings.TrimSpace(headerValue(parent.Payload, "Message-ID"))
+			if parentID == "" {
+				return fmt.Errorf("thread %s: newest message has no Message-ID to reply to", into)
+			}
+			references := strings.TrimSpace(headerValue(parent.Payload, "References"))
+			if !strings.Contains(references, parentID) {
+				references = strings.TrimSpace(references + " " + parentID)
+			}
+			subject := headerValue(meta.Payload, "Subject")
+			threadSubject := normalizeSubject(headerValue(thread.Messages[0].Payload, "Subject"))
+			if !strings.EqualFold(normalizeSubject(subject), threadSubject) {
+				subject = replySubject(threadSubject)
+			}
+			changes := [][2]string{
+				{"In-Reply-To", parentID},
+				{"References", references},
+				{"Subject", subject},
+			}
+
+			result := map[string]any{
+				"messageId":   messageID,
+				"fromThread":  meta.ThreadId,
+				"intoThread":  into,
+				"diagnostics": reasons,
+				"headers": map[string]string{
+					"In-Reply-To": parentID,
+					"References":  references,
+					"Subject":     subject,
+				},
+				"dryRun": dryRun,
+			}
+			if !dryRun {
+				if !keepOriginal {
+					if confirmErr := confirmDestructive(cmd, flags, fmt.Sprintf("move gmail message %s to trash after adopting it into thread %s", messageID, into)); confirmErr != nil {
+						return confirmErr
+					}
+				}
+				full, err := svc.Users.Messages.Get("me", messageID).Format("raw").Context(cmd.Context()).Do()
+				if err != nil {
+					return err
+				}
+				raw, err := decodeGmailRaw(full.Raw)
+				if err != nil {
+					return err
+				}
+				labels := make([]string, 0, len(full.LabelIds))
+				for _, l := range full.LabelIds {
+					if l != "DRAFT" {
+						labels = append(labels, l)
+					}
+				}
+				adopted, err := svc.Users.Messages.Import("me", &gmail.Message{ThreadId: into, LabelIds: labels}).
+					Media(bytes.NewReader(setRawHeaders(raw, changes)), gapi.ContentType("message/rfc822")).
+					InternalDateSource("dateHeader").
+					NeverMarkSpam(true).
+					Context(cmd.Context()).
+					Do()
+				if err != nil {
+					return err
+				}
+				if adopted.ThreadId != into {
+					return fmt.Errorf("gmail put the repaired copy %s in thread %s instead of %s; original kept (%s)", adopted.Id, adopted.ThreadId, into, strings.Join(reasons, "; "))
+				}
+				result["newMessageId"] = adopted.Id
+				if !keepOriginal {
+					if _, err := svc.Users.Messages.Trash("me", messageID).Context(cmd.Context()).Do(); err != nil {
+						return fmt.Errorf("adopted as %s, but trashing the original failed: %w", adopted.Id, err)
+					}
+					result["trashedOriginal"] = true
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, result)
+			}
+			if len(reasons) == 0 {
+				u.Out().Println("why\tno threading problem found (Gmail may have split the thread for other reasons)")
+			}
+			for _, r := range reasons {
+				u.Out().Printf("why\t%s", r)
+			}
+			for _, h := range changes {
+				u.Out().Printf("set\t%s: %s", h[0], h[1])
+			}
+			if id, ok := result["newMessageId"].(string); ok {
+				u.Out().Printf("message_id\t%s", id)
+				u.Out().Printf("thread_id\t%s", into)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&into, "into", "", "Thread ID to move the message into (required)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only print diagnostics and the header changes")
+	cmd.Flags().BoolVar(&keepOriginal, "keep-original", false, "Do not trash the original message")
+	return cmd
+}
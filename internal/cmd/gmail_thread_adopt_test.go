@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestNormalizeSubject(t *testing.T) {
+	for in, want := range map[string]string{
+		"Re: RE: Fwd: Budget  2026": "Budget 2026",
+		"AW: Angebot":               "Angebot",
+		"TR: RES: Proposta":         "Proposta",
+		"回复：会议":                     "会议",
+		"Re[2]: status":             "status",
+		"Regarding the plan":        "Regarding the plan",
+	} {
+		if got := normalizeSubject(in); got != want {
+			t.Fatalf("%q: got %q want %q", in, got, want)
+		}
+	}
+}
+
+func TestSetRawHeaders(t *testing.T) {
+	raw := "From: a@example.com\r\nSubject: Old\r\nReferences: <x@1>\r\n <y@1>\r\nTo: b@example.com\r\n\r\nbody\r\n"
+	got := string(setRawHeaders([]byte(raw), [][2]string{{"Subject", "Re: New"}, {"References", "<p@1>"}, {"In-Reply-To", "<p@1>"}}))
+	want := "From: a@example.com\r\nTo: b@example.com\r\nSubject: Re: New\r\nReferences: <p@1>\r\nIn-Reply-To: <p@1>\r\n\r\nbody\r\n"
+	if got != want {
+		t.Fatalf("got %q\nwant %q", got, want)
+	}
+}
+
+func adoptHeaders(kv ...string) map[string]any {
+	var hs []map[string]any
+	for i := 0; i+1 < len(kv); i += 2 {
+		hs = append(hs, map[string]any{"name": kv[i], "value": kv[i+1]})
+	}
+	return map[string]any{"headers": hs}
+}
+
+func TestExecute_GmailThreadAdopt(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	raw := "From: a@example.com\r\nSubject: [EXT] Budget\r\nMessage-ID: <m2@ext>\r\n\r\nnumbers\r\n"
+	var imported string
+	var importThread string
+	trashed := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/threads/t1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1", "messages": []map[string]any{
+				{"id": "m1", "threadId": "t1", "payload": adoptHeaders("Subject", "Budget", "Message-ID", "<m1@mail>")},
+			}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/me/messages/m2"):
+			if r.URL.Query().Get("format") == "raw" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": "m2", "threadId": "t2", "labelIds": []string{"INBOX", "UNREAD"}, "raw": base64.RawURLEncoding.EncodeToString([]byte(raw))})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m2", "threadId": "t2", "payload": adoptHeaders("Subject", "[EXT] Budget", "Message-ID", "<m2@ext>")})
+		case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/users/me/messages/import"):
+			b, _ := io.ReadAll(r.Body)
+			imported = string(b)
+			if strings.Contains(imported, `"threadId":"t1"`) {
+				importThread = "t1"
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m3", "threadId": importThread})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/messages/m2/trash"):
+			trashed = true
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m2"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "thread", "adopt", "m2", "--into", "t1", "--dry-run"}); err != nil {
+			t.Fatalf("dry-run: %v", err)
+		}
+	})
+	if !strings.Contains(out, "no In-Reply-To or References") || !strings.Contains(out, `subject "[EXT] Budget"`) || imported != "" {
+		t.Fatalf("unexpected dry-run out=%q imported=%q", out, imported)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "thread", "adopt", "m2", "--into", "t1", "--force"}); err != nil {
+			t.Fatalf("adopt: %v", err)
+		}
+	})
+	for _, want := range []string{"In-Reply-To: <m1@mail>", "References: <m1@mail>", "Subject: Re: Budget", `"labelIds":["INBOX","UNREAD"]`} {
+		if !strings.Contains(imported, want) {
+			t.Fatalf("import missing %q:\n%s", want, imported)
+		}
+	}
+	if !trashed {
+		t.Fatalf("expected original to be trashed")
+	}
+}
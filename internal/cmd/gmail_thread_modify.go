@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+func newGmailThreadModifyCmd(flags *rootFlags) *cobra.Command {
+	var add string
+	var remove string
+	var archive bool
+	var trash bool
+
+	cmd := &cobra.Command{
+		Use:   "modify <threadId>",
+		Short: "Modify labels on all messages in a thread (add/remove, archive, trash)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			threadID := args[0]
+
+			addLabels := splitCSV(add)
+			removeLabels := splitCSV(remove)
+			if archive {
+				removeLabels = append(removeLabels, "INBOX")
+			}
+			if len(addLabels) == 0 && len(removeLabels) == 0 && !trash {
+				return usage("must specify --add-label, --remove-label, --archive, and/or --trash")
+			}
+			if trash {
+				if confirmErr := confirmDestructive(cmd, flags, fmt.Sprintf("move gmail thread %s to trash", threadID)); confirmErr != nil {
+					return confirmErr
+				}
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			var addIDs, removeIDs []string
+			if len(addLabels) > 0 || len(removeLabels) > 0 {
+				idMap, err := fetchLabelNameToID(cmd.Context(), svc)
+				if err != nil {
+					return err
+				}
+				addIDs = resolveLabelIDs(addLabels, idMap)
+				removeIDs = resolveLabelIDs(removeLabels, idMap)
+
+				_, err = svc.Users.Threads.Modify("me", threadID, &gmail.ModifyThreadRequest{
+					AddLabelIds:    addIDs,
+					RemoveLabelIds: removeIDs,
+				}).Context(cmd.Context()).Do()
+				if err != nil {
+					return err
+				}
+			}
+
+			if trash {
+				if _, err := svc.Users.Threads.Trash("me", threadID).Context(cmd.Context()).Do(); err != nil {
+					return err
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"threadId":      threadID,
+					"addedLabels":   addIDs,
+					"removedLabels": removeIDs,
+					"trashed":       trash,
+				})
+			}
+
+			switch {
+			case trash && len(addIDs)+len(removeIDs) > 0:
+				u.Out().Printf("Modified and trashed thread %s", threadID)
+			case trash:
+				u.Out().Printf("Trashed thread %s", threadID)
+			default:
+				u.Out().Printf("Modified thread %s", threadID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&add, "add-label", "", "Labels to add (comma-separated, name or ID)")
+	cmd.Flags().StringVar(&remove, "remove-label", "", "Labels to remove (comma-separated, name or ID)")
+	cmd.Flags().BoolVar(&archive, "archive", false, "Archive the thread (remove INBOX)")
+	cmd.Flags().BoolVar(&trash, "trash", false, "Move the thread to trash")
+	return cmd
+}
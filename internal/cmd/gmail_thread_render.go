@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	threadRenderText     = "text"
+	threadRenderMarkdown = "markdown"
+	threadRenderHTML     = "html"
+)
+
+var (
+	// htmlNoiseRe drops elements whose text is never part of the message.
+	htmlNoiseRe = regexp.MustCompile(`(?is)<(head|style|script|title)\b[^>]*>.*?</(head|style|script|title)>`)
+	// htmlQuoteStartRe finds where clients put the quoted previous message.
+	htmlQuoteStartRe = regexp.MustCompile(`(?is)<div[^>]*class="[^"]*\b(gmail_quote|gmail_extra)\b[^"]*"|<blockquote\b|<div[^>]*id="(divRplyFwdMsg|appendonsend)"`)
+	// quoteAttributionRe matches "On <date>, <sender> wrote:" lines.
+	quoteAttributionRe = regexp.MustCompile(`(?i)^\s*On .+ wrote:\s*$`)
+)
+
+// renderedMessage is one message of a rendered thread.
+type renderedMessage struct {
+	ID          string   `json:"id"`
+	From        string   `json:"from"`
+	Date        string   `json:"date,omitempty"`
+	Subject     string   `json:"subject,omitempty"`
+	Body        string   `json:"body"`
+	Quoted      int      `json:"quotedLines,omitempty"`
+	Attachments []string `json:"attachments,omitempty"`
+	at          time.Time
+}
+
+// renderThreadMessages decodes each message body (text/plain, else the
+// HTML converted to text), collapses quoted replies unless keepQuotes, and
+// orders messages oldest first.
+func renderThreadMessages(thread *gmail.Thread, keepQuotes bool) []renderedMessage {
+	if thread == nil {
+		return nil
+	}
+	out := make([]renderedMessage, 0, len(thread.Messages))
+	for _, msg := range thread.Messages {
+		if msg == nil {
+			continue
+		}
+		body := strings.ReplaceAll(findPartBody(msg.Payload, "text/plain"), "\r\n", "\n")
+		quoted := 0
+		if strings.TrimSpace(body) == "" {
+			htmlBody := findPartBody(msg.Payload, "text/html")
+			if !keepQuotes {
+				if loc := htmlQuoteStartRe.FindStringIndex(htmlBody); loc != nil {
+					quoted = strings.Count(simpleHTMLToMarkdown(htmlBody[loc[0]:]), "\n") + 1
+					htmlBody = htmlBody[:loc[0]]
+				}
+			}
+			body = simpleHTMLToMarkdown(htmlNoiseRe.ReplaceAllString(htmlBody, ""))
+		}
+		if !keepQuotes {
+			var n int
+			body, n = collapseQuotedLines(body)
+			quoted += n
+		}
+		rm := renderedMessage{
+			ID:      msg.Id,
+			From:    headerValue(msg.Payload, "From"),
+			Subject: headerValue(msg.Payload, "Subject"),
+			Body:    strings.TrimSpace(body),
+			Quoted:  quoted,
+		}
+		for _, a := range collectAttachments(msg.Payload) {
+			rm.Attachments = append(rm.Attachments, a.Filename)
+		}
+		if msg.InternalDate > 0 {
+			rm.at = time.UnixMilli(msg.InternalDate)
+		} else if t, err := mailParseDate(headerValue(msg.Payload, "Date")); err == nil {
+			rm.at = t
+		}
+		if !rm.at.IsZero() {
+			rm.Date = rm.at.UTC().Format(time.RFC3339)
+		}
+		out = append(out, rm)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].at.Before(out[j].at) })
+	return out
+}
+
+// collapseQuotedLines removes runs of "> " quoted lines, and the "On ...
+// wrote:" line introducing them, returning the remaining text and the
+// number of lines removed.
+func collapseQuotedLines(body string) (string, int) {
+	lines := strings.Split(body, "\n")
+	kept := make([]string, 0, len(lines))
+	removed := 0
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if quoteAttributionRe.MatchString(line) {
+			// Only an attribution followed (after blanks) by quoted lines.
+			j := i + 1
+			for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+				j++
+			}
+			if j < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[j]), ">") {
+				removed += j - i
+				i = j - 1
+				continue
+			}
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), ">") {
+			removed++
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return blankLinesRe.ReplaceAllString(strings.Join(kept, "\n"), "\n\n"), removed
+}
+
+// senderName is the display name of a From header, or its address.
+func senderName(from string) string {
+	addr, err := mail.ParseAddress(strings.TrimSpace(from))
+	if err != nil {
+		return strings.TrimSpace(from)
+	}
+	if addr.Name != "" {
+		return addr.Name
+	}
+	return addr.Address
+}
+
+func renderedDate(m renderedMessage) string {
+	if m.at.IsZero() {
+		return ""
+	}
+	return m.at.Local().Format("2006-01-02 15:04")
+}
+
+// formatThreadConversation renders messages as a plain-text conversation.
+func formatThreadConversation(subject string, msgs []renderedMessage) string {
+	var b strings.Builder
+	if subject != "" {
+		fmt.Fprintf(&b, "%s\n%s\n\n", subject, strings.Repeat("=", min(len([]rune(subject)), 72)))
+	}
+	for i, m := range msgs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "--- %s", senderName(m.From))
+		if d := renderedDate(m); d != "" {
+			fmt.Fprintf(&b, " (%s)", d)
+		}
+		b.WriteString(" ---\n")
+		if m.Body != "" {
+			b.WriteString(m.Body + "\n")
+		}
+		if m.Quoted > 0 {
+			fmt.Fprintf(&b, "[%d quoted lines hidden]\n", m.Quoted)
+		}
+		if len(m.Attachments) > 0 {
+			fmt.Fprintf(&b, "Attachments: %s\n", strings.Join(m.Attachments, ", "))
+		}
+	}
+	return b.String()
+}
+
+// formatThreadMarkdown renders messages as a Markdown transcript.
+func formatThreadMarkdown(subject string, msgs []renderedMessage) string {
+	var b strings.Builder
+	if subject == "" {
+		subject = "(no subject)"
+	}
+	fmt.Fprintf(&b, "# %s\n", subject)
+	for _, m := range msgs {
+		fmt.Fprintf(&b, "\n## %s", senderName(m.From))
+		if d := renderedDate(m); d != "" {
+			fmt.Fprintf(&b, " — %s", d)
+		}
+		b.WriteString("\n\n")
+		if m.Body != "" {
+			b.WriteString(m.Body + "\n")
+		}
+		if m.Quoted > 0 {
+			fmt.Fprintf(&b, "\n*%d quoted lines hidden*\n", m.Quoted)
+		}
+		if len(m.Attachments) > 0 {
+			b.WriteString("\nAttachments:\n\n")
+			for _, a := range m.Attachments {
+				fmt.Fprintf(&b, "- %s\n", a)
+			}
+		}
+	}
+	return b.String()
+}
+
+// formatThreadHTML renders messages as a self-contained HTML page. avatar,
+// when set, returns an image URL (usually a data: URI) for a From header.
+func formatThreadHTML(subject string, msgs []renderedMessage, avatar func(from string) string) string {
+	var b strings.Builder
+	if subject == "" {
+		subject = "(no subject)"
+	}
+	esc := html.EscapeString
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>%s</title>\n", esc(subject))
+	b.WriteString("<style>body{font-family:sans-serif;max-width:46em;margin:2em auto;color:#222}" +
+		".msg{border-top:1px solid #ddd;padding:1em 0}.hdr{display:flex;align-items:center;gap:.6em}" +
+		".avatar{width:40px;height:40px;border-radius:50%;object-fit:cover}.date{color:#777;font-size:.9em}" +
+		".body{white-space:pre-wrap}.note{color:#777;font-style:italic}</style></head><body>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", esc(subject))
+	for _, m := range msgs {
+		b.WriteString("<div class=\"msg\"><div class=\"hdr\">")
+		if avatar != nil {
+			if src := avatar(m.From); src != "" {
+				fmt.Fprintf(&b, "<img class=\"avatar\" src=\"%s\" alt=\"\">", esc(src))
+			}
+		}
+		fmt.Fprintf(&b, "<strong title=\"%s\">%s</strong>", esc(m.From), esc(senderName(m.From)))
+		if d := renderedDate(m); d != "" {
+			fmt.Fprintf(&b, " <span class=\"date\">%s</span>", esc(d))
+		}
+		b.WriteString("</div>\n")
+		if m.Body != "" {
+			fmt.Fprintf(&b, "<div class=\"body\">%s</div>\n", esc(m.Body))
+		}
+		if m.Quoted > 0 {
+			fmt.Fprintf(&b, "<p class=\"note\">%d quoted lines hidden</p>\n", m.Quoted)
+		}
+		if len(m.Attachments) > 0 {
+			b.WriteString("<p>Attachments:</p><ul>")
+			for _, a := range m.Attachments {
+				fmt.Fprintf(&b, "<li>%s</li>", esc(a))
+			}
+			b.WriteString("</ul>\n")
+		}
+		b.WriteString("</div>\n")
+	}
+	b.WriteString("</body></html>\n")
+	return b.String()
+}
+
+// writeRenderedThread prints (or with outPath, writes) the rendered thread.
+// avatar is used by the HTML rendering only; red, when set, masks PII in
+// senders, subjects and bodies (and drops avatars).
+func writeRenderedThread(cmd *cobra.Command, thread *gmail.Thread, format, outPath string, keepQuotes bool, avatar func(from string) string, red *redactor) error {
+	u := ui.FromContext(cmd.Context())
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "":
+		format = threadRenderMarkdown
+		if ext := strings.ToLower(filepath.Ext(outPath)); ext == ".html" || ext == ".htm" {
+			format = threadRenderHTML
+		}
+	case threadRenderText, "plain":
+		format = threadRenderText
+	case threadRenderMarkdown, "md":
+		format = threadRenderMarkdown
+	case threadRenderHTML, "htm":
+		format = threadRenderHTML
+	default:
+		return usagef("invalid --render %q (expected text|markdown|html)", format)
+	}
+
+	msgs := renderThreadMessages(thread, keepQuotes)
+	if red != nil {
+		// Sender photos would identify people the redaction hides.
+		avatar = nil
+		for i := range msgs {
+			msgs[i].From = red.redact(msgs[i].From)
+			msgs[i].Subject = red.redact(msgs[i].Subject)
+			msgs[i].Body = red.redact(msgs[i].Body)
+		}
+	}
+	subject := ""
+	if len(msgs) > 0 {
+		subject = msgs[0].Subject
+	}
+	var text string
+	switch format {
+	case threadRenderMarkdown:
+		text = formatThreadMarkdown(subject, msgs)
+	case threadRenderHTML:
+		text = formatThreadHTML(subject, msgs, avatar)
+	default:
+		text = formatThreadConversation(subject, msgs)
+	}
+
+	if outPath != "" {
+		if err := os.WriteFile(outPath, []byte(text), 0o644); err != nil {
+			return err
+		}
+	}
+	if outfmt.IsJSON(cmd.Context()) {
+		res := map[string]any{"threadId": thread.Id, "subject": subject, "messages": msgs}
+		if outPath != "" {
+			res["path"] = outPath
+		}
+		return outfmt.WriteJSON(os.Stdout, res)
+	}
+	if outPath != "" {
+		u.Out().Successf("Saved: %s", outPath)
+		return nil
+	}
+	_, err := fmt.Fprint(os.Stdout, text)
+	return err
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func renderPart(mimeType, body string) *gmail.MessagePart {
+	return &gmail.MessagePart{MimeType: mimeType, Body: &gmail.MessagePartBody{Data: base64.RawURLEncoding.EncodeToString([]byte(body))}}
+}
+
+func TestCollapseQuotedLines(t *testing.T) {
+	body := "Sounds good.\n\nOn Mon, Jan 5, 2026 at 9:00 AM Ada <ada@x.com> wrote:\n\n> Lunch?\n> Noon works.\n\nCheers"
+	got, n := collapseQuotedLines(body)
+	if got != "Sounds good.\n\nCheers" || n != 4 {
+		t.Fatalf("got %q, %d", got, n)
+	}
+	if got, n := collapseQuotedLines("On the other hand, Bob wrote:\nno quote follows"); n != 0 || !strings.Contains(got, "Bob wrote:") {
+		t.Fatalf("attribution without quote was dropped: %q", got)
+	}
+}
+
+func TestRenderThreadMessages(t *testing.T) {
+	thread := &gmail.Thread{Id: "t1", Messages: []*gmail.Message{
+		{
+			Id: "m2", InternalDate: 2000,
+			Payload: &gmail.MessagePart{
+				MimeType: "multipart/alternative",
+				Headers:  []*gmail.MessagePartHeader{{Name: "From", Value: "Bob <bob@x.com>"}, {Name: "Subject", Value: "Re: Lunch"}},
+				Parts: []*gmail.MessagePart{renderPart("text/html",
+					`<html><head><style>p{}</style></head><body><p>Yes, <b>noon</b>.</p><div class="gmail_quote">On Mon Ada wrote:<blockquote>Lunch?</blockquote></div></body></html>`)},
+			},
+		},
+		{
+			Id: "m1", InternalDate: 1000,
+			Payload: &gmail.MessagePart{
+				MimeType: "text/plain",
+				Headers:  []*gmail.MessagePartHeader{{Name: "From", Value: "Ada <ada@x.com>"}, {Name: "Subject", Value: "Lunch"}},
+				Body:     renderPart("text/plain", "Lunch?\r\n").Body,
+			},
+		},
+	}}
+
+	msgs := renderThreadMessages(thread, false)
+	if len(msgs) != 2 || msgs[0].ID != "m1" || msgs[1].ID != "m2" {
+		t.Fatalf("order = %#v", msgs)
+	}
+	if msgs[0].Body != "Lunch?" {
+		t.Fatalf("plain body = %q", msgs[0].Body)
+	}
+	if msgs[1].Body != "Yes, **noon**." || msgs[1].Quoted == 0 {
+		t.Fatalf("html body = %q (quoted %d)", msgs[1].Body, msgs[1].Quoted)
+	}
+
+	md := formatThreadMarkdown(msgs[0].Subject, msgs)
+	if !strings.HasPrefix(md, "# Lunch\n\n## Ada") || !strings.Contains(md, "## Bob") || !strings.Contains(md, "quoted lines hidden") {
+		t.Fatalf("markdown = %q", md)
+	}
+	text := formatThreadConversation(msgs[0].Subject, msgs)
+	if !strings.Contains(text, "--- Ada (") || !strings.Contains(text, "Yes, **noon**.") {
+		t.Fatalf("text = %q", text)
+	}
+
+	kept := renderThreadMessages(thread, true)
+	if !strings.Contains(kept[1].Body, "Lunch?") {
+		t.Fatalf("--keep-quotes body = %q", kept[1].Body)
+	}
+}
+
+func TestExecute_GmailThreadRenderOut(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/users/me/threads/t1") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "t1", "messages": []map[string]any{{
+			"id": "m1", "internalDate": "1000",
+			"payload": map[string]any{
+				"mimeType": "text/plain",
+				"headers":  []map[string]any{{"name": "From", "value": "Ada <ada@x.com>"}, {"name": "Subject", "value": "Lunch"}},
+				"body":     map[string]any{"data": base64.RawURLEncoding.EncodeToString([]byte("Lunch?"))},
+			},
+		}}})
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	path := filepath.Join(t.TempDir(), "lunch.md")
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "gmail", "thread", "t1", "--out", path}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# Lunch\n\n## Ada") || !strings.Contains(string(data), "Lunch?") {
+		t.Fatalf("transcript = %q", data)
+	}
+}
+
+func TestFormatThreadHTML(t *testing.T) {
+	msgs := []renderedMessage{{From: "Ada <ada@x.com>", Body: "1 < 2", Attachments: []string{"a.pdf"}}}
+	got := formatThreadHTML("Lunch & more", msgs, func(from string) string {
+		if senderAddress(from) == "ada@x.com" {
+			return "data:image/png;base64,AAAA"
+		}
+		return ""
+	})
+	for _, want := range []string{"<title>Lunch &amp; more</title>", `<img class="avatar" src="data:image/png;base64,AAAA"`, "<strong title=\"Ada &lt;ada@x.com&gt;\">Ada</strong>", "1 &lt; 2", "<li>a.pdf</li>"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// threadParticipant is one address seen in a thread's From/To/Cc headers.
+type threadParticipant struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+	// Sent counts the messages in the thread authored by this address.
+	Sent int `json:"sent"`
+}
+
+// threadSummary is computed from a thread payload (full or metadata with
+// From/To/Cc headers); drafts are not counted.
+type threadSummary struct {
+	Messages      int                 `json:"messageCount"`
+	Participants  []threadParticipant `json:"participants,omitempty"`
+	FirstActivity string              `json:"firstActivity,omitempty"`
+	LastActivity  string              `json:"lastActivity,omitempty"`
+	lastActivity  time.Time
+}
+
+func summarizeThread(t *gmail.Thread) threadSummary {
+	var s threadSummary
+	if t == nil {
+		return s
+	}
+	index := map[string]int{}
+	add := func(addr *mail.Address, sent bool) {
+		email := normalizeEmailAddress(addr.Address)
+		if email == "" {
+			return
+		}
+		i, ok := index[email]
+		if !ok {
+			i = len(s.Participants)
+			index[email] = i
+			s.Participants = append(s.Participants, threadParticipant{Email: email})
+		}
+		p := &s.Participants[i]
+		if p.Name == "" {
+			p.Name = strings.TrimSpace(addr.Name)
+		}
+		if sent {
+			p.Sent++
+		}
+	}
+
+	var first time.Time
+	for _, m := range t.Messages {
+		if m == nil || hasLabel(m.LabelIds, "DRAFT") {
+			continue
+		}
+		s.Messages++
+		for _, h := range []string{"From", "To", "Cc"} {
+			v := strings.TrimSpace(headerValue(m.Payload, h))
+			if v == "" {
+				continue
+			}
+			addrs, err := mail.ParseAddressList(v)
+			if err != nil {
+				addrs = []*mail.Address{{Address: v}}
+			}
+			for _, a := range addrs {
+				add(a, h == "From")
+			}
+		}
+		if m.InternalDate > 0 {
+			at := time.UnixMilli(m.InternalDate)
+			if first.IsZero() || at.Before(first) {
+				first = at
+			}
+			if at.After(s.lastActivity) {
+				s.lastActivity = at
+			}
+		}
+	}
+	if !first.IsZero() {
+		s.FirstActivity = first.UTC().Format(time.RFC3339)
+		s.LastActivity = s.lastActivity.UTC().Format(time.RFC3339)
+	}
+	return s
+}
+
+// participantEmails lists participant addresses in order of first appearance.
+func (s threadSummary) participantEmails() []string {
+	out := make([]string, 0, len(s.Participants))
+	for _, p := range s.Participants {
+		out = append(out, p.Email)
+	}
+	return out
+}
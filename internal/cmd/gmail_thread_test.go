@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/base64"
+	"strings"
 	"testing"
 
 	"google.golang.org/api/gmail/v1"
@@ -61,3 +62,35 @@ func TestDecodeBase64URL(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestSummarizeThread(t *testing.T) {
+	hdr := func(kv ...string) *gmail.MessagePart {
+		p := &gmail.MessagePart{}
+		for i := 0; i+1 < len(kv); i += 2 {
+			p.Headers = append(p.Headers, &gmail.MessagePartHeader{Name: kv[i], Value: kv[i+1]})
+		}
+		return p
+	}
+	thread := &gmail.Thread{Messages: []*gmail.Message{
+		{InternalDate: 1735732800000, Payload: hdr("From", "Ada <ADA@example.com>", "To", "me@example.com, Bob <bob@example.com>")},
+		{InternalDate: 1735819200000, LabelIds: []string{"SENT"}, Payload: hdr("From", "me@example.com", "To", "ada@example.com", "Cc", "carol@example.com")},
+		{InternalDate: 1735905600000, LabelIds: []string{"DRAFT"}, Payload: hdr("From", "me@example.com", "To", "dave@example.com")},
+	}}
+
+	s := summarizeThread(thread)
+	if s.Messages != 2 {
+		t.Fatalf("messages=%d", s.Messages)
+	}
+	if got := strings.Join(s.participantEmails(), ","); got != "ada@example.com,me@example.com,bob@example.com,carol@example.com" {
+		t.Fatalf("participants=%q", got)
+	}
+	if s.Participants[0].Name != "Ada" || s.Participants[0].Sent != 1 || s.Participants[1].Sent != 1 || s.Participants[2].Sent != 0 {
+		t.Fatalf("unexpected participants: %#v", s.Participants)
+	}
+	if s.FirstActivity != "2025-01-01T12:00:00Z" || s.LastActivity != "2025-01-02T12:00:00Z" {
+		t.Fatalf("activity=%q..%q", s.FirstActivity, s.LastActivity)
+	}
+	if summarizeThread(nil).Messages != 0 {
+		t.Fatalf("expected empty summary")
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// trackingParams are query parameters that only identify the campaign or
+// recipient. Keys starting with a trackingParamPrefixes entry also match.
+var (
+	trackingParams = map[string]bool{
+		"mkt_tok": true, "fbclid": true, "gclid": true, "dclid": true, "msclkid": true,
+		"mc_cid": true, "mc_eid": true, "_hsenc": true, "_hsmi": true, "yclid": true,
+		"igshid": true, "vero_id": true, "oly_anon_id": true, "oly_enc_id": true,
+	}
+	trackingParamPrefixes = []string{"utm_", "__hs", "pk_"}
+
+	trackingURLRe   = regexp.MustCompile(`https?://[^\s"'<>()]+`)
+	trackingQuerySe = regexp.MustCompile(`&amp;|&`)
+	htmlImgRe       = regexp.MustCompile(`(?is)<img\b[^>]*>`)
+	pixelAttrRe     = regexp.MustCompile(`(?i)\b(width|height)\s*=\s*["']?\s*[01](px)?\s*["'\s/>]`)
+	pixelStyleRe    = regexp.MustCompile(`(?i)style\s*=\s*["'][^"']*(display\s*:\s*none|(width|height)\s*:\s*[01]px)`)
+)
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	if trackingParams[key] {
+		return true
+	}
+	for _, p := range trackingParamPrefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripTrackingURL removes tracking parameters from one URL, keeping the
+// order (and &amp; escaping) of the rest. n is the number removed.
+func stripTrackingURL(u string) (string, int) {
+	q := strings.Index(u, "?")
+	if q < 0 {
+		return u, 0
+	}
+	base, query, frag := u[:q], u[q+1:], ""
+	if h := strings.Index(query, "#"); h >= 0 {
+		query, frag = query[:h], query[h:]
+	}
+	sep := "&"
+	if strings.Contains(query, "&amp;") {
+		sep = "&amp;"
+	}
+	var kept []string
+	n := 0
+	for _, kv := range trackingQuerySe.Split(query, -1) {
+		if kv == "" {
+			continue
+		}
+		key := kv
+		if eq := strings.Index(kv, "="); eq >= 0 {
+			key = kv[:eq]
+		}
+		if isTrackingParam(key) {
+			n++
+			continue
+		}
+		kept = append(kept, kv)
+	}
+	if n == 0 {
+		return u, 0
+	}
+	if len(kept) == 0 {
+		return base + frag, n
+	}
+	return base + "?" + strings.Join(kept, sep) + frag, n
+}
+
+// stripTracking rewrites every URL in s (HTML or plain text) without its
+// tracking parameters and, for HTML, drops tracking pixels: images sized
+// 0/1 px or hidden with display:none.
+func stripTracking(s string, isHTML bool) (out string, params int, pixels int) {
+	out = trackingURLRe.ReplaceAllStringFunc(s, func(u string) string {
+		clean, n := stripTrackingURL(u)
+		params += n
+		return clean
+	})
+	if isHTML {
+		out = htmlImgRe.ReplaceAllStringFunc(out, func(tag string) string {
+			if pixelAttrRe.MatchString(tag) || pixelStyleRe.MatchString(tag) {
+				pixels++
+				return ""
+			}
+			return tag
+		})
+	}
+	return out, params, pixels
+}
+
+// trackingFlag is --strip-tracking, defaulting to config gmail.stripTracking.
+type trackingFlag struct {
+	enabled bool
+}
+
+func (f *trackingFlag) addFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.enabled, "strip-tracking", false, "Remove tracking URL parameters (utm_*, mkt_tok, ...) and tracking pixels from the bodies, including quoted text (default: config gmail.stripTracking)")
+}
+
+func (f *trackingFlag) resolve(cmd *cobra.Command) (bool, error) {
+	if cmd.Flags().Changed("strip-tracking") {
+		return f.enabled, nil
+	}
+	cfg, err := config.ReadConfigFile()
+	if err != nil {
+		return false, err
+	}
+	return cfg.Gmail.StripTracking, nil
+}
+
+// apply strips tracking from the plain and HTML bodies when enabled and
+// notes what was removed on stderr.
+func (f *trackingFlag) apply(cmd *cobra.Command, body, bodyHTML *string) error {
+	on, err := f.resolve(cmd)
+	if err != nil || !on {
+		return err
+	}
+	var params, pixels, n int
+	*body, params, _ = stripTracking(*body, false)
+	*bodyHTML, n, pixels = stripTracking(*bodyHTML, true)
+	params += n
+	if params > 0 || pixels > 0 {
+		ui.FromContext(cmd.Context()).Err().Printf("Stripped %d tracking parameter(s) and %d tracking pixel(s)", params, pixels)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripTrackingURL(t *testing.T) {
+	cases := map[string]string{
+		"https://x.test/a?utm_source=news&id=7&utm_medium=email#top": "https://x.test/a?id=7#top",
+		"https://x.test/a?id=7&amp;mkt_tok=abc&amp;b=2":              "https://x.test/a?id=7&amp;b=2",
+		"https://x.test/a?fbclid=1":                                  "https://x.test/a",
+		"https://x.test/a?q=utm_source":                              "https://x.test/a?q=utm_source",
+		"https://x.test/plain":                                       "https://x.test/plain",
+	}
+	for in, want := range cases {
+		if got, _ := stripTrackingURL(in); got != want {
+			t.Errorf("stripTrackingURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestStripTrackingHTML(t *testing.T) {
+	in := `<p><a href="https://shop.test/p?id=1&amp;utm_campaign=x">Shop</a></p>` +
+		`<img src="https://t.test/open.gif" width="1" height="1">` +
+		`<img src="https://t.test/o2.gif" style="display:none">` +
+		`<img src="https://x.test/logo.png" width="120">`
+	out, params, pixels := stripTracking(in, true)
+	if params != 1 || pixels != 2 {
+		t.Fatalf("params=%d pixels=%d out=%q", params, pixels, out)
+	}
+	if !strings.Contains(out, `href="https://shop.test/p?id=1"`) || !strings.Contains(out, "logo.png") || strings.Contains(out, "open.gif") {
+		t.Fatalf("out = %q", out)
+	}
+
+	plain, params, _ := stripTracking("See https://x.test/?utm_source=a (thanks)", false)
+	if params != 1 || plain != "See https://x.test/ (thanks)" {
+		t.Fatalf("plain = %q (%d)", plain, params)
+	}
+}
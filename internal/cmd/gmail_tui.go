@@ -0,0 +1,569 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+const tuiHelpList = "j/k move  enter read  a archive  l label  r reply  g refresh  q quit"
+const tuiHelpRead = "j/k scroll  space page  a archive  l label  r reply  esc back  q quit"
+
+type tuiMode int
+
+const (
+	tuiModeList tuiMode = iota
+	tuiModeRead
+	tuiModeLabel
+)
+
+// Messages delivered to gmailTUIModel.Update by its commands.
+type (
+	tuiThreadsMsg struct {
+		items []threadItem
+		err   error
+	}
+	tuiThreadMsg struct {
+		id, lastMessageID string
+		text              string
+		err               error
+	}
+	tuiActionMsg struct {
+		status   string
+		removeID string // thread to drop from the list (archived)
+		err      error
+	}
+	tuiEditedMsg struct {
+		path, messageID string
+		err             error
+	}
+)
+
+// gmailTUIActions are the API calls behind the TUI, swapped in tests.
+type gmailTUIActions struct {
+	list    func() ([]threadItem, error)
+	thread  func(id string) (*gmail.Thread, error)
+	archive func(id string) error
+	label   func(id, label string) error
+	reply   func(messageID, body string) error
+	editor  func(path string) *exec.Cmd
+}
+
+// gmailTUIModel is the bubbletea model behind gmail tui: a thread list, a
+// reader for one thread, and a one-line label prompt.
+type gmailTUIModel struct {
+	query   string
+	act     gmailTUIActions
+	items   []threadItem
+	cursor  int
+	mode    tuiMode
+	prev    tuiMode // mode to return to from the label prompt
+	lines   []string
+	scroll  int
+	openID  string
+	lastMsg string
+	replyOn bool // reply once the opened thread has loaded
+	input   string
+	status  string
+	loading bool
+	width   int
+	height  int
+}
+
+func newGmailTUICmd(flags *rootFlags) *cobra.Command {
+	var max int64
+
+	cmd := &cobra.Command{
+		Use:   "tui [query]",
+		Short: "Browse threads in an interactive terminal UI",
+		Long: `Browse the threads matching a Gmail query (default in:inbox) in a
+full-screen terminal UI.
+
+Keys: j/k or arrows move, enter reads a thread, a archives (removes INBOX),
+l adds a label, r replies to the newest message (the body is written in
+$VISUAL/$EDITOR; an empty file cancels), g reloads, q quits.
+
+Replies go through gmail reply, so the send guards (allowlist, policy,
+arming, send log) apply.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if flags.NoInput || u == nil || !u.Interactive() {
+				return usage("gmail tui needs an interactive terminal")
+			}
+			query := strings.TrimSpace(strings.Join(args, " "))
+			if query == "" {
+				query = "in:inbox"
+			}
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			for _, path := range []string{"gmail thread modify", "gmail reply"} {
+				if err := tuiPolicyCheck(account, path); err != nil {
+					u.Err().Printf("WARN: %v", err)
+				}
+			}
+
+			ctx := cmd.Context()
+			m := newGmailTUIModel(query, gmailTUIActions{
+				list: func() ([]threadItem, error) {
+					resp, err := svc.Users.Threads.List("me").Q(query).MaxResults(max).Context(ctx).Do()
+					if err != nil {
+						return nil, err
+					}
+					idToName, err := fetchLabelIDToName(ctx, svc)
+					if err != nil {
+						return nil, err
+					}
+					return fetchThreadDetails(ctx, svc, resp.Threads, idToName)
+				},
+				thread: func(id string) (*gmail.Thread, error) {
+					return svc.Users.Threads.Get("me", id).Format("full").Context(ctx).Do()
+				},
+				archive: func(id string) error {
+					if err := tuiPolicyCheck(account, "gmail thread modify"); err != nil {
+						return err
+					}
+					_, err := svc.Users.Threads.Modify("me", id, &gmail.ModifyThreadRequest{RemoveLabelIds: []string{"INBOX"}}).Context(ctx).Do()
+					return err
+				},
+				label: func(id, label string) error {
+					if err := tuiPolicyCheck(account, "gmail thread modify"); err != nil {
+						return err
+					}
+					idMap, err := fetchLabelNameToID(ctx, svc)
+					if err != nil {
+						return err
+					}
+					ids, err := lookupLabelIDs([]string{label}, idMap)
+					if err != nil {
+						return err
+					}
+					_, err = svc.Users.Threads.Modify("me", id, &gmail.ModifyThreadRequest{AddLabelIds: ids}).Context(ctx).Do()
+					return err
+				},
+				reply: func(messageID, body string) error {
+					if err := tuiPolicyCheck(account, "gmail reply"); err != nil {
+						return err
+					}
+					return tuiSendReply(ctx, flags, messageID, body)
+				},
+				editor: editorCommand,
+			})
+			_, err = tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+			if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().Int64Var(&max, "max", 50, "Max threads to load")
+	return cmd
+}
+
+func newGmailTUIModel(query string, act gmailTUIActions) gmailTUIModel {
+	return gmailTUIModel{query: query, act: act, loading: true, width: 80, height: 24}
+}
+
+// tuiPolicyCheck applies policy.yaml to an action the TUI takes on behalf of
+// another command. The TUI cannot prompt, so confirm rules refuse too.
+func tuiPolicyCheck(account, path string) error {
+	p, err := loadPolicy(account)
+	if err != nil || p == nil {
+		return err
+	}
+	if rule := matchCommandRule(p.deny, path); rule != "" {
+		return fmt.Errorf("%s: %w (rule %q in %s)", path, errPolicyDenied, rule, p.source)
+	}
+	if rule := matchCommandRule(p.confirm, path); rule != "" {
+		return fmt.Errorf("%s needs confirmation (rule %q in %s); run it outside the TUI", path, rule, p.source)
+	}
+	return nil
+}
+
+// tuiSendReply runs gmail reply with body, discarding its output so the
+// TUI screen stays intact.
+func tuiSendReply(ctx context.Context, flags *rootFlags, messageID, body string) error {
+	reply := newGmailReplyCmd(flags)
+	if err := reply.Flags().Set("body", body); err != nil {
+		return err
+	}
+	u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+	if err != nil {
+		return err
+	}
+	reply.SetContext(ui.WithUI(ctx, u))
+	return reply.RunE(reply, []string{messageID})
+}
+
+// editorCommand opens path in $VISUAL, else $EDITOR, else vi.
+func editorCommand(path string) *exec.Cmd {
+	editor := strings.TrimSpace(os.Getenv("VISUAL"))
+	if editor == "" {
+		editor = strings.TrimSpace(os.Getenv("EDITOR"))
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	return exec.Command(parts[0], append(parts[1:], path)...)
+}
+
+func (m gmailTUIModel) Init() tea.Cmd {
+	return m.loadThreads()
+}
+
+func (m gmailTUIModel) loadThreads() tea.Cmd {
+	list := m.act.list
+	return func() tea.Msg {
+		items, err := list()
+		return tuiThreadsMsg{items: items, err: err}
+	}
+}
+
+func (m gmailTUIModel) openThread(id string) tea.Cmd {
+	get := m.act.thread
+	return func() tea.Msg {
+		t, err := get(id)
+		if err != nil {
+			return tuiThreadMsg{id: id, err: err}
+		}
+		msgs := renderThreadMessages(t, false)
+		subject := ""
+		last := ""
+		if len(msgs) > 0 {
+			subject = msgs[0].Subject
+			last = msgs[len(msgs)-1].ID
+		}
+		return tuiThreadMsg{id: id, lastMessageID: last, text: formatThreadConversation(subject, msgs)}
+	}
+}
+
+func (m gmailTUIModel) selected() *threadItem {
+	if m.cursor < 0 || m.cursor >= len(m.items) {
+		return nil
+	}
+	return &m.items[m.cursor]
+}
+
+// target is the thread an action applies to: the open one while reading,
+// else the selected row.
+func (m gmailTUIModel) target() string {
+	if m.mode == tuiModeRead || m.prev == tuiModeRead && m.mode == tuiModeLabel {
+		return m.openID
+	}
+	if it := m.selected(); it != nil {
+		return it.ID
+	}
+	return ""
+}
+
+func (m gmailTUIModel) startReply() (tea.Model, tea.Cmd) {
+	if m.lastMsg == "" {
+		m.status = "Nothing to reply to"
+		return m, nil
+	}
+	f, err := os.CreateTemp("", "gog-reply-*.txt")
+	if err != nil {
+		m.status = "Reply: " + err.Error()
+		return m, nil
+	}
+	path := f.Name()
+	_ = f.Close()
+	messageID := m.lastMsg
+	return m, tea.ExecProcess(m.act.editor(path), func(err error) tea.Msg {
+		return tuiEditedMsg{path: path, messageID: messageID, err: err}
+	})
+}
+
+func (m gmailTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case tuiThreadsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = "Load failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.items = msg.items
+		m.cursor = min(m.cursor, max(len(m.items)-1, 0))
+		m.status = fmt.Sprintf("%d thread(s)", len(m.items))
+		return m, nil
+
+	case tuiThreadMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = "Open failed: " + msg.err.Error()
+			m.replyOn = false
+			return m, nil
+		}
+		m.mode = tuiModeRead
+		m.openID = msg.id
+		m.lastMsg = msg.lastMessageID
+		m.lines = wrapLines(msg.text, m.width)
+		m.scroll = 0
+		m.status = ""
+		if m.replyOn {
+			m.replyOn = false
+			return m.startReply()
+		}
+		return m, nil
+
+	case tuiActionMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			return m, nil
+		}
+		m.status = msg.status
+		if msg.removeID != "" {
+			for i, it := range m.items {
+				if it.ID == msg.removeID {
+					m.items = append(m.items[:i], m.items[i+1:]...)
+					break
+				}
+			}
+			m.cursor = min(m.cursor, max(len(m.items)-1, 0))
+			if m.mode == tuiModeRead && m.openID == msg.removeID {
+				m.mode = tuiModeList
+			}
+		}
+		return m, nil
+
+	case tuiEditedMsg:
+		data, readErr := os.ReadFile(msg.path)
+		_ = os.Remove(msg.path)
+		switch {
+		case msg.err != nil:
+			m.status = "Editor: " + msg.err.Error()
+			return m, nil
+		case readErr != nil:
+			m.status = "Reply: " + readErr.Error()
+			return m, nil
+		}
+		body := strings.TrimSpace(string(data))
+		if body == "" {
+			m.status = "Reply cancelled (empty body)"
+			return m, nil
+		}
+		m.loading = true
+		m.status = "Sending reply..."
+		reply, id := m.act.reply, msg.messageID
+		return m, func() tea.Msg {
+			if err := reply(id, body); err != nil {
+				return tuiActionMsg{err: fmt.Errorf("reply failed: %w", err)}
+			}
+			return tuiActionMsg{status: "Reply sent"}
+		}
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m gmailTUIModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	if m.mode == tuiModeLabel {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.mode, m.input = m.prev, ""
+		case tea.KeyEnter:
+			label, id := strings.TrimSpace(m.input), m.target()
+			m.mode, m.input = m.prev, ""
+			if label == "" || id == "" {
+				return m, nil
+			}
+			m.loading = true
+			apply := m.act.label
+			return m, func() tea.Msg {
+				if err := apply(id, label); err != nil {
+					return tuiActionMsg{err: err}
+				}
+				return tuiActionMsg{status: "Labeled " + label}
+			}
+		case tea.KeyBackspace:
+			if r := []rune(m.input); len(r) > 0 {
+				m.input = string(r[:len(r)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.input += string(msg.Runes)
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "q":
+		if m.mode == tuiModeRead {
+			m.mode = tuiModeList
+			return m, nil
+		}
+		return m, tea.Quit
+	case "esc":
+		m.mode = tuiModeList
+		return m, nil
+	case "a":
+		id := m.target()
+		if id == "" {
+			return m, nil
+		}
+		m.loading = true
+		archive := m.act.archive
+		return m, func() tea.Msg {
+			if err := archive(id); err != nil {
+				return tuiActionMsg{err: err}
+			}
+			return tuiActionMsg{status: "Archived", removeID: id}
+		}
+	case "l":
+		if m.target() == "" {
+			return m, nil
+		}
+		m.prev, m.mode, m.input = m.mode, tuiModeLabel, ""
+		return m, nil
+	case "r":
+		if m.mode == tuiModeRead {
+			return m.startReply()
+		}
+		if it := m.selected(); it != nil {
+			m.loading, m.replyOn = true, true
+			return m, m.openThread(it.ID)
+		}
+		return m, nil
+	}
+
+	if m.mode == tuiModeRead {
+		page := max(m.height-3, 1)
+		switch key {
+		case "j", "down":
+			m.scroll++
+		case "k", "up":
+			m.scroll--
+		case " ", "pgdown":
+			m.scroll += page
+		case "b", "pgup":
+			m.scroll -= page
+		}
+		m.scroll = max(min(m.scroll, len(m.lines)-page), 0)
+		return m, nil
+	}
+
+	switch key {
+	case "j", "down":
+		m.cursor = min(m.cursor+1, max(len(m.items)-1, 0))
+	case "k", "up":
+		m.cursor = max(m.cursor-1, 0)
+	case "g":
+		m.loading = true
+		return m, m.loadThreads()
+	case "enter":
+		if it := m.selected(); it != nil {
+			m.loading = true
+			return m, m.openThread(it.ID)
+		}
+	}
+	return m, nil
+}
+
+func (m gmailTUIModel) View() string {
+	var b strings.Builder
+	body := max(m.height-3, 1)
+	title := "gog gmail: " + m.query
+	if m.loading {
+		title += "  (loading...)"
+	}
+	b.WriteString(previewText(title, m.width) + "\n")
+
+	shown := 0
+	if m.mode == tuiModeRead || m.mode == tuiModeLabel && m.prev == tuiModeRead {
+		for i := m.scroll; i < len(m.lines) && shown < body; i++ {
+			b.WriteString(m.lines[i] + "\n")
+			shown++
+		}
+	} else {
+		start := 0
+		if m.cursor >= body {
+			start = m.cursor - body + 1
+		}
+		for i := start; i < len(m.items) && shown < body; i++ {
+			it := m.items[i]
+			marker := "  "
+			if i == m.cursor {
+				marker = "> "
+			}
+			if it.Unread {
+				marker += "* "
+			} else {
+				marker += "  "
+			}
+			row := fmt.Sprintf("%s%-16s  %-20s  %s — %s", marker, previewText(it.Date, 16), previewText(senderName(it.From), 20), it.Subject, it.Snippet)
+			b.WriteString(previewText(row, m.width) + "\n")
+			shown++
+		}
+	}
+	for ; shown < body; shown++ {
+		b.WriteString("\n")
+	}
+
+	switch {
+	case m.mode == tuiModeLabel:
+		b.WriteString("Add label: " + m.input + "_\n")
+	case m.status != "":
+		b.WriteString(previewText(m.status, m.width) + "\n")
+	default:
+		b.WriteString("\n")
+	}
+	if m.mode == tuiModeRead {
+		b.WriteString(previewText(tuiHelpRead, m.width))
+	} else {
+		b.WriteString(previewText(tuiHelpList, m.width))
+	}
+	return b.String()
+}
+
+// wrapLines splits text into lines no wider than width runes, breaking at
+// spaces where possible.
+func wrapLines(text string, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+	var out []string
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		r := []rune(line)
+		for len(r) > width {
+			cut := width
+			for i := width; i > width/2; i-- {
+				if r[i] == ' ' {
+					cut = i
+					break
+				}
+			}
+			out = append(out, string(r[:cut]))
+			r = []rune(strings.TrimLeft(string(r[cut:]), " "))
+		}
+		out = append(out, string(r))
+	}
+	return out
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"google.golang.org/api/gmail/v1"
+)
+
+// runTUICmd runs cmd and feeds its message back into the model.
+func runTUICmd(t *testing.T, m gmailTUIModel, cmd tea.Cmd) gmailTUIModel {
+	t.Helper()
+	if cmd == nil {
+		return m
+	}
+	next, _ := m.Update(cmd())
+	return next.(gmailTUIModel)
+}
+
+func tuiKey(t *testing.T, m gmailTUIModel, key string) (gmailTUIModel, tea.Cmd) {
+	t.Helper()
+	var msg tea.KeyMsg
+	switch key {
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	next, cmd := m.Update(msg)
+	return next.(gmailTUIModel), cmd
+}
+
+func TestGmailTUIModel(t *testing.T) {
+	var archived, labeled, replied []string
+	m := newGmailTUIModel("in:inbox", gmailTUIActions{
+		list: func() ([]threadItem, error) {
+			return []threadItem{
+				{ID: "t1", From: "Ada <ada@example.com>", Subject: "Lunch?", Snippet: "tomorrow", Unread: true},
+				{ID: "t2", From: "bob@example.com", Subject: "Report", Snippet: "attached"},
+			}, nil
+		},
+		thread: func(id string) (*gmail.Thread, error) {
+			return &gmail.Thread{Id: id, Messages: []*gmail.Message{{
+				Id: "m-" + id, InternalDate: 1,
+				Payload: &gmail.MessagePart{MimeType: "text/plain", Headers: []*gmail.MessagePartHeader{{Name: "From", Value: "bob@example.com"}, {Name: "Subject", Value: "Report"}},
+					Body: &gmail.MessagePartBody{Data: "SGVyZSBpdCBpcw"}},
+			}}}, nil
+		},
+		archive: func(id string) error { archived = append(archived, id); return nil },
+		label: func(id, label string) error {
+			if label == "Nope" {
+				return errors.New(`label "Nope" not found`)
+			}
+			labeled = append(labeled, id+":"+label)
+			return nil
+		},
+		reply:  func(id, body string) error { replied = append(replied, id+":"+body); return nil },
+		editor: func(path string) *exec.Cmd { return exec.Command("true", path) },
+	})
+	m = runTUICmd(t, m, m.Init())
+	if len(m.items) != 2 || !strings.Contains(m.View(), "> * ") || !strings.Contains(m.View(), "Lunch?") {
+		t.Fatalf("list view:\n%s", m.View())
+	}
+
+	// Label the first thread, then archive it.
+	m, _ = tuiKey(t, m, "l")
+	for _, k := range []string{"T", "o", "d", "o"} {
+		m, _ = tuiKey(t, m, k)
+	}
+	if !strings.Contains(m.View(), "Add label: Todo_") {
+		t.Fatalf("label prompt:\n%s", m.View())
+	}
+	m, cmd := tuiKey(t, m, "enter")
+	m = runTUICmd(t, m, cmd)
+	m, cmd = tuiKey(t, m, "a")
+	m = runTUICmd(t, m, cmd)
+	if strings.Join(labeled, ",") != "t1:Todo" || strings.Join(archived, ",") != "t1" || len(m.items) != 1 || m.items[0].ID != "t2" {
+		t.Fatalf("labeled=%v archived=%v items=%v", labeled, archived, m.items)
+	}
+
+	// Errors end up in the status line.
+	m, _ = tuiKey(t, m, "l")
+	for _, k := range []string{"N", "o", "p", "e"} {
+		m, _ = tuiKey(t, m, k)
+	}
+	m, cmd = tuiKey(t, m, "enter")
+	m = runTUICmd(t, m, cmd)
+	if !strings.Contains(m.View(), `label "Nope" not found`) {
+		t.Fatalf("status:\n%s", m.View())
+	}
+
+	// Open the thread and read it.
+	m, cmd = tuiKey(t, m, "enter")
+	m = runTUICmd(t, m, cmd)
+	if m.mode != tuiModeRead || !strings.Contains(m.View(), "Here it is") || m.lastMsg != "m-t2" {
+		t.Fatalf("read view (mode %d):\n%s", m.mode, m.View())
+	}
+
+	// An empty editor buffer cancels the reply.
+	m, cmd = tuiKey(t, m, "r")
+	if cmd == nil {
+		t.Fatalf("reply should start the editor")
+	}
+	next, _ := m.Update(tuiEditedMsg{path: filepath.Join(t.TempDir(), "missing.txt"), messageID: "m-t2"})
+	m = next.(gmailTUIModel)
+	if len(replied) != 0 || !strings.Contains(m.status, "Reply") {
+		t.Fatalf("status=%q replied=%v", m.status, replied)
+	}
+
+	body := filepath.Join(t.TempDir(), "reply.txt")
+	if err := os.WriteFile(body, []byte("Sounds good\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	next, cmd = m.Update(tuiEditedMsg{path: body, messageID: "m-t2"})
+	m = runTUICmd(t, next.(gmailTUIModel), cmd)
+	if strings.Join(replied, ",") != "m-t2:Sounds good" || m.status != "Reply sent" {
+		t.Fatalf("status=%q replied=%v", m.status, replied)
+	}
+
+	m, _ = tuiKey(t, m, "esc")
+	if m.mode != tuiModeList {
+		t.Fatalf("esc should return to the list")
+	}
+	if _, cmd := tuiKey(t, m, "q"); cmd == nil {
+		t.Fatalf("q should quit from the list")
+	}
+}
+
+func TestWrapLines(t *testing.T) {
+	got := wrapLines("one two three four five six seven eight nine ten eleven", 20)
+	for _, l := range got {
+		if len([]rune(l)) > 20 {
+			t.Fatalf("line too long: %q", l)
+		}
+	}
+	if strings.Join(got, " ") != "one two three four five six seven eight nine ten eleven" {
+		t.Fatalf("wrap lost text: %q", got)
+	}
+}
@@ -2,7 +2,11 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"html"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -18,6 +22,7 @@ func newGmailVacationCmd(flags *rootFlags) *cobra.Command {
 	}
 
 	cmd.AddCommand(newGmailVacationGetCmd(flags))
+	cmd.AddCommand(newGmailVacationShowCmd(flags))
 	cmd.AddCommand(newGmailVacationUpdateCmd(flags))
 	return cmd
 }
@@ -70,6 +75,7 @@ func newGmailVacationUpdateCmd(flags *rootFlags) *cobra.Command {
 	var disable bool
 	var subject string
 	var body string
+	var bodyFile string
 	var startTime string
 	var endTime string
 	var contactsOnly bool
@@ -78,7 +84,13 @@ func newGmailVacationUpdateCmd(flags *rootFlags) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update vacation responder settings",
-		Args:  cobra.NoArgs,
+		Long: `Update vacation responder settings; unset flags keep their current value.
+
+--body-file reads the reply from a file: .md/.markdown is converted to HTML,
+.html/.htm is used as-is, anything else is sent as plain text. --start/--end
+take RFC3339 times or dates (YYYY-MM-DD, local time; --end covers that whole
+day).`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			u := ui.FromContext(cmd.Context())
 			account, err := requireAccount(flags)
@@ -89,6 +101,9 @@ func newGmailVacationUpdateCmd(flags *rootFlags) *cobra.Command {
 			if enable && disable {
 				return errors.New("cannot specify both --enable and --disable")
 			}
+			if bodyFile != "" && cmd.Flags().Changed("body") {
+				return usage("use either --body or --body-file")
+			}
 
 			svc, err := newGmailService(cmd.Context(), account)
 			if err != nil {
@@ -127,9 +142,15 @@ func newGmailVacationUpdateCmd(flags *rootFlags) *cobra.Command {
 				vacation.ResponseBodyHtml = body
 				vacation.ResponseBodyPlainText = stripHTML(body)
 			}
+			if bodyFile != "" {
+				vacation.ResponseBodyHtml, vacation.ResponseBodyPlainText, err = readVacationBody(bodyFile)
+				if err != nil {
+					return err
+				}
+			}
 			if cmd.Flags().Changed("start") {
 				var t int64
-				t, err = parseRFC3339ToMillis(startTime)
+				t, err = parseVacationTime(startTime, false)
 				if err != nil {
 					return err
 				}
@@ -137,12 +158,15 @@ func newGmailVacationUpdateCmd(flags *rootFlags) *cobra.Command {
 			}
 			if cmd.Flags().Changed("end") {
 				var t int64
-				t, err = parseRFC3339ToMillis(endTime)
+				t, err = parseVacationTime(endTime, true)
 				if err != nil {
 					return err
 				}
 				vacation.EndTime = t
 			}
+			if vacation.StartTime != 0 && vacation.EndTime != 0 && vacation.EndTime <= vacation.StartTime {
+				return usage("--end must be after --start")
+			}
 			if cmd.Flags().Changed("contacts-only") {
 				vacation.RestrictToContacts = contactsOnly
 			}
@@ -178,13 +202,127 @@ func newGmailVacationUpdateCmd(flags *rootFlags) *cobra.Command {
 	cmd.Flags().BoolVar(&disable, "disable", false, "Disable vacation responder")
 	cmd.Flags().StringVar(&subject, "subject", "", "Subject line for auto-reply")
 	cmd.Flags().StringVar(&body, "body", "", "HTML body of the auto-reply message")
-	cmd.Flags().StringVar(&startTime, "start", "", "Start time in RFC3339 format (e.g., 2024-12-20T00:00:00Z)")
-	cmd.Flags().StringVar(&endTime, "end", "", "End time in RFC3339 format (e.g., 2024-12-31T23:59:59Z)")
+	cmd.Flags().StringVar(&bodyFile, "body-file", "", "Read the auto-reply from a Markdown, HTML or text file (- for stdin: Markdown)")
+	cmd.Flags().StringVar(&startTime, "start", "", "Start time in RFC3339 or YYYY-MM-DD (e.g., 2024-12-20)")
+	cmd.Flags().StringVar(&endTime, "end", "", "End time in RFC3339 or YYYY-MM-DD (inclusive day, e.g., 2024-12-31)")
 	cmd.Flags().BoolVar(&contactsOnly, "contacts-only", false, "Only respond to contacts")
 	cmd.Flags().BoolVar(&domainOnly, "domain-only", false, "Only respond to same domain")
 	return cmd
 }
 
+// parseVacationTime accepts RFC3339 or a local date; an end date means the
+// end of that day (Gmail stops replying at EndTime).
+func parseVacationTime(s string, end bool) (int64, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		if end {
+			d = d.AddDate(0, 0, 1)
+		}
+		return d.UnixMilli(), nil
+	}
+	t, err := parseRFC3339ToMillis(s)
+	if err != nil {
+		return 0, usagef("invalid time %q (use RFC3339 or YYYY-MM-DD)", s)
+	}
+	return t, nil
+}
+
+// readVacationBody returns the HTML and plain-text reply from a file.
+func readVacationBody(path string) (htmlBody, plain string, err error) {
+	b, err := readFileOrStdin(path)
+	if err != nil {
+		return "", "", err
+	}
+	text := strings.TrimSpace(string(b))
+	if text == "" {
+		return "", "", usagef("%s is empty", path)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	switch {
+	case ext == ".html" || ext == ".htm":
+		return text, simpleHTMLToMarkdown(text), nil
+	case ext == ".md" || ext == ".markdown" || path == "-":
+		return markdownToSimpleHTML(text), text, nil
+	}
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>"), text, nil
+}
+
+// vacationStatus summarizes the responder: off, scheduled, active or ended.
+func vacationStatus(v *gmail.VacationSettings, now time.Time) string {
+	switch {
+	case !v.EnableAutoReply:
+		return "off"
+	case v.StartTime != 0 && now.UnixMilli() < v.StartTime:
+		return "scheduled"
+	case v.EndTime != 0 && now.UnixMilli() >= v.EndTime:
+		return "ended"
+	default:
+		return "active"
+	}
+}
+
+func formatVacationTime(ms int64) string {
+	if ms == 0 {
+		return "-"
+	}
+	return time.UnixMilli(ms).Local().Format("2006-01-02 15:04")
+}
+
+func newGmailVacationShowCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show whether the vacation responder is off, scheduled, active or ended",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			v, err := svc.Users.Settings.GetVacation("me").Context(cmd.Context()).Do()
+			if err != nil {
+				return err
+			}
+			status := vacationStatus(v, time.Now())
+			restrict := "anyone"
+			switch {
+			case v.RestrictToContacts && v.RestrictToDomain:
+				restrict = "contacts,domain"
+			case v.RestrictToContacts:
+				restrict = "contacts"
+			case v.RestrictToDomain:
+				restrict = "domain"
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				out := map[string]any{
+					"status":   status,
+					"enabled":  v.EnableAutoReply,
+					"subject":  v.ResponseSubject,
+					"replyTo":  restrict,
+					"vacation": v,
+				}
+				if v.StartTime != 0 {
+					out["start"] = time.UnixMilli(v.StartTime).UTC().Format(time.RFC3339)
+				}
+				if v.EndTime != 0 {
+					out["end"] = time.UnixMilli(v.EndTime).UTC().Format(time.RFC3339)
+				}
+				return outfmt.WriteJSON(os.Stdout, out)
+			}
+
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "STATUS\tSTART\tEND\tREPLY_TO\tSUBJECT")
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", status, formatVacationTime(v.StartTime), formatVacationTime(v.EndTime), restrict, orDash(sanitizeTab(v.ResponseSubject)))
+			return nil
+		},
+	}
+}
+
 func parseRFC3339ToMillis(rfc3339 string) (int64, error) {
 	if rfc3339 == "" {
 		return 0, nil
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"google.golang.org/api/gmail/v1"
 )
 
 func TestParseRFC3339ToMillis(t *testing.T) {
@@ -102,4 +106,58 @@ func TestVacationCommandExists(t *testing.T) {
 	_ = newGmailVacationCmd
 	_ = newGmailVacationGetCmd
 	_ = newGmailVacationUpdateCmd
+	_ = newGmailVacationShowCmd
+}
+
+func TestParseVacationTime(t *testing.T) {
+	start, err := parseVacationTime("2024-12-20", false)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	end, err := parseVacationTime("2024-12-20", true)
+	if err != nil {
+		t.Fatalf("end: %v", err)
+	}
+	want := time.Date(2024, 12, 20, 0, 0, 0, 0, time.Local)
+	if start != want.UnixMilli() || end != want.AddDate(0, 0, 1).UnixMilli() {
+		t.Fatalf("unexpected range %d..%d", start, end)
+	}
+	if _, err := parseVacationTime("next week", false); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestReadVacationBody(t *testing.T) {
+	dir := t.TempDir()
+	md := filepath.Join(dir, "away.md")
+	if err := os.WriteFile(md, []byte("Out until **Monday**.\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	html, plain, err := readVacationBody(md)
+	if err != nil || html != "Out until <b>Monday</b>." || plain != "Out until **Monday**." {
+		t.Fatalf("markdown: %q %q %v", html, plain, err)
+	}
+	txt := filepath.Join(dir, "away.txt")
+	if err := os.WriteFile(txt, []byte("a < b\nbye"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	html, _, err = readVacationBody(txt)
+	if err != nil || html != "a &lt; b<br>bye" {
+		t.Fatalf("text: %q %v", html, err)
+	}
+}
+
+func TestVacationStatus(t *testing.T) {
+	now := time.Date(2024, 12, 20, 12, 0, 0, 0, time.UTC)
+	cases := map[string]*gmail.VacationSettings{
+		"off":       {EnableAutoReply: false},
+		"active":    {EnableAutoReply: true, StartTime: now.Add(-time.Hour).UnixMilli()},
+		"scheduled": {EnableAutoReply: true, StartTime: now.Add(time.Hour).UnixMilli()},
+		"ended":     {EnableAutoReply: true, EndTime: now.Add(-time.Hour).UnixMilli()},
+	}
+	for want, v := range cases {
+		if got := vacationStatus(v, now); got != want {
+			t.Fatalf("got %q want %q", got, want)
+		}
+	}
 }
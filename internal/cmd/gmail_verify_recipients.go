@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/people/v1"
+)
+
+// commonMailDomains are checked for near-miss typos (gamil.com, hotmial.com).
+var commonMailDomains = []string{
+	"gmail.com", "googlemail.com", "outlook.com", "hotmail.com", "live.com",
+	"yahoo.com", "icloud.com", "me.com", "aol.com", "proton.me",
+	"protonmail.com", "gmx.com", "gmx.de", "web.de",
+}
+
+// recipientCheck is the --verify-recipients verdict for one address.
+type recipientCheck struct {
+	Email      string
+	Known      bool
+	Source     string // contacts|mail|self
+	Suggestion string
+}
+
+// recipientVerifier looks recipients up in contacts (incl. "other contacts")
+// and recent mail. Lookup failures are reported once and then skipped.
+type recipientVerifier struct {
+	account  string
+	gmail    *gmail.Service
+	contacts *people.Service
+	other    *people.Service
+	warned   map[string]bool
+	warn     func(format string, args ...any)
+}
+
+// verifyRecipients warns about recipients that are neither in contacts nor
+// in recent correspondence, suggesting the closest known address. It never
+// blocks sending. gsvc may be nil (--dry-run); a service is created then.
+func verifyRecipients(ctx context.Context, u *ui.UI, account string, gsvc *gmail.Service, recipients []string) []recipientCheck {
+	v := &recipientVerifier{
+		account: account,
+		gmail:   gsvc,
+		warned:  map[string]bool{},
+		warn: func(format string, args ...any) {
+			if u != nil {
+				u.Err().Printf("WARN: "+format, args...)
+			}
+		},
+	}
+	if v.gmail == nil {
+		if svc, err := newGmailService(ctx, account); err == nil {
+			v.gmail = svc
+		} else {
+			v.lookupFailed("mail", err)
+		}
+	}
+	if svc, err := newPeopleContactsService(ctx, account); err == nil {
+		v.contacts = svc
+	} else {
+		v.lookupFailed("contacts", err)
+	}
+	if svc, err := newPeopleOtherContactsService(ctx, account); err == nil {
+		v.other = svc
+	} else {
+		v.lookupFailed("other contacts", err)
+	}
+
+	seen := map[string]bool{}
+	out := make([]recipientCheck, 0, len(recipients))
+	for _, email := range extractEmails(recipients) {
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		c := v.check(ctx, email)
+		out = append(out, c)
+		if c.Known {
+			continue
+		}
+		if c.Suggestion != "" {
+			v.warn("%s is not in your contacts or recent mail; did you mean %s?", email, c.Suggestion)
+		} else {
+			v.warn("%s is not in your contacts or recent mail", email)
+		}
+	}
+	return out
+}
+
+func (v *recipientVerifier) lookupFailed(what string, err error) {
+	if v.warned[what] {
+		return
+	}
+	v.warned[what] = true
+	v.warn("--verify-recipients: %s lookup failed: %v", what, err)
+}
+
+func (v *recipientVerifier) check(ctx context.Context, email string) recipientCheck {
+	c := recipientCheck{Email: email}
+	if strings.EqualFold(email, v.account) {
+		c.Known, c.Source = true, "self"
+		return c
+	}
+	for _, known := range v.contactEmails(ctx, email) {
+		if known == email {
+			c.Known, c.Source = true, "contacts"
+			return c
+		}
+	}
+	if v.gmail != nil {
+		resp, err := v.gmail.Users.Messages.List("me").
+			Q(fmt.Sprintf("from:%s OR to:%s OR cc:%s", email, email, email)).
+			MaxResults(1).
+			Context(ctx).
+			Do()
+		switch {
+		case err != nil:
+			v.lookupFailed("mail", err)
+		case len(resp.Messages) > 0:
+			c.Known, c.Source = true, "mail"
+			return c
+		}
+	}
+	c.Suggestion = v.suggest(ctx, email)
+	return c
+}
+
+// contactEmails returns addresses of contacts and other contacts matching query.
+func (v *recipientVerifier) contactEmails(ctx context.Context, query string) []string {
+	var out []string
+	collect := func(results []*people.SearchResult) {
+		for _, r := range results {
+			if r == nil || r.Person == nil {
+				continue
+			}
+			for _, e := range r.Person.EmailAddresses {
+				if e != nil && e.Value != "" {
+					out = append(out, normalizeEmailAddress(e.Value))
+				}
+			}
+		}
+	}
+	if v.contacts != nil {
+		resp, err := v.contacts.People.SearchContacts().Query(query).PageSize(10).ReadMask("emailAddresses").Context(ctx).Do()
+		if err != nil {
+			v.lookupFailed("contacts", err)
+		} else {
+			collect(resp.Results)
+		}
+	}
+	if v.other != nil {
+		resp, err := v.other.OtherContacts.Search().Query(query).PageSize(10).ReadMask("emailAddresses").Context(ctx).Do()
+		if err != nil {
+			v.lookupFailed("other contacts", err)
+		} else {
+			collect(resp.Results)
+		}
+	}
+	return out
+}
+
+// suggest proposes the closest plausible address: first a near-miss of a
+// common or the account's own domain, then a near-miss contact address found
+// by searching for the local part.
+func (v *recipientVerifier) suggest(ctx context.Context, email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" || domain == "" {
+		return ""
+	}
+	domains := append([]string{}, commonMailDomains...)
+	if _, own, ok := strings.Cut(strings.ToLower(v.account), "@"); ok {
+		domains = append(domains, own)
+	}
+	if best := closestString(domain, domains, 2); best != "" && best != domain {
+		return local + "@" + best
+	}
+	if best := closestString(email, v.contactEmails(ctx, local), 2); best != "" && best != email {
+		return best
+	}
+	return ""
+}
+
+// closestString returns the candidate with the smallest edit distance to s,
+// if it is within maxDist.
+func closestString(s string, candidates []string, maxDist int) string {
+	best, bestDist := "", maxDist+1
+	for _, c := range candidates {
+		if d := editDistance(s, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Damerau-Levenshtein (optimal string alignment) distance,
+// so swapped letters (gamil/gmail) count as one edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
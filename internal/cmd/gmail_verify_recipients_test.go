@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+	"google.golang.org/api/people/v1"
+)
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"gmail.com", "gmail.com", 0},
+		{"gamil.com", "gmail.com", 1},
+		{"gmial.com", "gmail.com", 1},
+		{"gmai.com", "gmail.com", 1},
+		{"hotmial.com", "hotmail.com", 1},
+		{"example.org", "gmail.com", 8},
+	}
+	for _, tc := range cases {
+		if got := editDistance(tc.a, tc.b); got != tc.want {
+			t.Fatalf("editDistance(%q,%q)=%d want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+	if got := closestString("gamil.com", commonMailDomains, 2); got != "gmail.com" {
+		t.Fatalf("closestString=%q", got)
+	}
+	if got := closestString("example.org", commonMailDomains, 2); got != "" {
+		t.Fatalf("expected no match, got %q", got)
+	}
+}
+
+func TestVerifyRecipients(t *testing.T) {
+	origGmail, origContacts, origOther := newGmailService, newPeopleContactsService, newPeopleOtherContactsService
+	t.Cleanup(func() {
+		newGmailService = origGmail
+		newPeopleContactsService = origContacts
+		newPeopleOtherContactsService = origOther
+	})
+
+	person := func(email string) map[string]any {
+		return map[string]any{"person": map[string]any{"emailAddresses": []map[string]any{{"value": email}}}}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query().Get("query")
+		switch {
+		case strings.Contains(r.URL.Path, "people:searchContacts"):
+			var results []map[string]any
+			for _, known := range []string{"ada@example.com", "bob@example.com"} {
+				if strings.HasPrefix(known, q) {
+					results = append(results, person(known))
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"results": results})
+		case strings.Contains(r.URL.Path, "otherContacts:search"):
+			_ = json.NewEncoder(w).Encode(map[string]any{})
+		case strings.HasSuffix(r.URL.Path, "/users/me/messages"):
+			var msgs []map[string]any
+			if strings.Contains(r.URL.Query().Get("q"), "carol@corp.test") {
+				msgs = append(msgs, map[string]any{"id": "m1"})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": msgs})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	opts := []option.ClientOption{
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL + "/"),
+	}
+	gsvc, err := gmail.NewService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("gmail: %v", err)
+	}
+	psvc, err := people.NewService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("people: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return gsvc, nil }
+	newPeopleContactsService = func(context.Context, string) (*people.Service, error) { return psvc, nil }
+	newPeopleOtherContactsService = func(context.Context, string) (*people.Service, error) { return psvc, nil }
+
+	checks := verifyRecipients(context.Background(), nil, "me@corp.test", nil, []string{
+		"Ada <ada@example.com>",
+		"carol@corp.test",
+		"dan@gamil.com",
+		"bob@exmaple.com",
+		"eve@corp.tset",
+		"zed@nowhere.test",
+		"ada@example.com",
+	})
+	got := map[string]recipientCheck{}
+	for _, c := range checks {
+		got[c.Email] = c
+	}
+	if len(checks) != 6 {
+		t.Fatalf("expected duplicates dropped, got %#v", checks)
+	}
+	if c := got["ada@example.com"]; !c.Known || c.Source != "contacts" {
+		t.Fatalf("ada: %#v", c)
+	}
+	if c := got["carol@corp.test"]; !c.Known || c.Source != "mail" {
+		t.Fatalf("carol: %#v", c)
+	}
+	if c := got["dan@gamil.com"]; c.Known || c.Suggestion != "dan@gmail.com" {
+		t.Fatalf("dan: %#v", c)
+	}
+	if c := got["bob@exmaple.com"]; c.Known || c.Suggestion != "bob@example.com" {
+		t.Fatalf("bob: %#v", c)
+	}
+	if c := got["eve@corp.tset"]; c.Suggestion != "eve@corp.test" {
+		t.Fatalf("eve: %#v", c)
+	}
+	if c := got["zed@nowhere.test"]; c.Known || c.Suggestion != "" {
+		t.Fatalf("zed: %#v", c)
+	}
+}
+
+func TestExecute_GmailSend_VerifyRecipientsWarns(t *testing.T) {
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+	origGmail, origContacts, origOther := newGmailService, newPeopleContactsService, newPeopleOtherContactsService
+	t.Cleanup(func() {
+		newGmailService = origGmail
+		newPeopleContactsService = origContacts
+		newPeopleOtherContactsService = origOther
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{})
+	}))
+	defer srv.Close()
+	opts := []option.ClientOption{
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL + "/"),
+	}
+	gsvc, err := gmail.NewService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("gmail: %v", err)
+	}
+	psvc, err := people.NewService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("people: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return gsvc, nil }
+	newPeopleContactsService = func(context.Context, string) (*people.Service, error) { return psvc, nil }
+	newPeopleOtherContactsService = func(context.Context, string) (*people.Service, error) { return psvc, nil }
+
+	errOut := captureStderr(t, func() {
+		_ = captureStdout(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "send", "--dry-run", "--to", "x@gamil.com", "--subject", "S", "--body", "B", "--verify-recipients"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(errOut, "did you mean x@gmail.com?") {
+		t.Fatalf("unexpected stderr=%q", errOut)
+	}
+}
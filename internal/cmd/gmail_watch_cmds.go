@@ -30,6 +30,7 @@ func newGmailWatchCmd(flags *rootFlags) *cobra.Command {
 	cmd.AddCommand(newGmailWatchRenewCmd(flags))
 	cmd.AddCommand(newGmailWatchStopCmd(flags))
 	cmd.AddCommand(newGmailWatchServeCmd(flags))
+	cmd.AddCommand(newGmailWatchDaemonCmd(flags))
 	return cmd
 }
 
@@ -68,7 +69,7 @@ func newGmailWatchStartCmd(flags *rootFlags) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			labelIDs, err := resolveLabelIDsWithService(svc, labels)
+			labelIDs, err := resolveLabelIDsWithService(cmd.Context(), svc, labels)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	defaultWatchRenewBefore = 24 * time.Hour
+	defaultWatchCheckEvery  = time.Hour
+	watchDaemonRetryMin     = time.Minute
+)
+
+// watchDaemonSleep waits for d or until ctx is done.
+func watchDaemonSleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// watchHistoryFile is what `watch daemon --history-file` writes after every
+// check, for integrations that poll history instead of running watch serve.
+type watchHistoryFile struct {
+	Account      string `json:"account"`
+	HistoryID    string `json:"historyId"`
+	ExpirationMs int64  `json:"expirationMs,omitempty"`
+	UpdatedAtMs  int64  `json:"updatedAtMs"`
+}
+
+func newGmailWatchDaemonCmd(flags *rootFlags) *cobra.Command {
+	var renewBeforeRaw string
+	var checkEveryRaw string
+	var historyFile string
+	var once bool
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Stay resident and renew the Gmail watch before it expires",
+		Long: `Keep the stored watch alive. Gmail watches expire after 7 days; the daemon
+re-checks the stored state every --check-every and renews the watch once it is
+within --renew-before of expiry (or past the --ttl set by watch start).
+
+State is re-read on every check, so watch serve can run alongside it and keep
+advancing the history cursor. Failed renewals are retried with backoff.
+--history-file writes {account, historyId, expirationMs} after each check.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			renewBefore, err := parseDurationSeconds(renewBeforeRaw)
+			if err != nil {
+				return usagef("invalid --renew-before %q", renewBeforeRaw)
+			}
+			checkEvery, err := parseDurationSeconds(checkEveryRaw)
+			if err != nil || checkEvery <= 0 {
+				return usagef("invalid --check-every %q", checkEveryRaw)
+			}
+			if _, err := loadGmailWatchStore(account); err != nil {
+				return err
+			}
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			backoff := watchDaemonRetryMin
+			for {
+				store, err := loadGmailWatchStore(account)
+				if err != nil {
+					return err
+				}
+				state := store.Get()
+				renewed := false
+				now := time.Now()
+				if watchRenewDue(state, renewBefore, now) {
+					state, err = renewStoredWatch(ctx, svc, store)
+					if err != nil {
+						if once {
+							return err
+						}
+						u.Err().Printf("WARN: watch renew failed (retrying in %s): %v", backoff, err)
+						if err := watchDaemonSleep(ctx, backoff); err != nil {
+							return nil
+						}
+						backoff = min(backoff*2, checkEvery)
+						continue
+					}
+					backoff = watchDaemonRetryMin
+					renewed = true
+				}
+				if historyFile != "" {
+					if err := writeWatchHistoryFile(historyFile, state); err != nil {
+						u.Err().Printf("WARN: write %s: %v", historyFile, err)
+					}
+				}
+				next := watchNextRenewal(state, renewBefore)
+				if err := reportWatchDaemonCheck(ctx, state, renewed, next); err != nil {
+					return err
+				}
+				if once {
+					return nil
+				}
+
+				wait := checkEvery
+				if d := time.Until(next); d < wait {
+					wait = max(d, watchDaemonRetryMin)
+				}
+				if err := watchDaemonSleep(ctx, wait); err != nil {
+					return nil
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&renewBeforeRaw, "renew-before", defaultWatchRenewBefore.String(), "Renew this long before expiry (seconds or Go duration)")
+	cmd.Flags().StringVar(&checkEveryRaw, "check-every", defaultWatchCheckEvery.String(), "How often to re-check the stored watch (seconds or Go duration)")
+	cmd.Flags().StringVar(&historyFile, "history-file", "", "Write the current historyId (JSON) to this file after each check")
+	cmd.Flags().BoolVar(&once, "once", false, "Check (and renew if due) once, then exit")
+	return cmd
+}
+
+// watchNextRenewal is when the watch should be renewed: renewBefore ahead
+// of expiry, or the --ttl renew-after time if that comes first.
+func watchNextRenewal(state gmailWatchState, renewBefore time.Duration) time.Time {
+	var next time.Time
+	if state.ExpirationMs > 0 {
+		next = time.UnixMilli(state.ExpirationMs).Add(-renewBefore)
+	}
+	if state.RenewAfterMs > 0 {
+		if after := time.UnixMilli(state.RenewAfterMs); next.IsZero() || after.Before(next) {
+			next = after
+		}
+	}
+	return next
+}
+
+func watchRenewDue(state gmailWatchState, renewBefore time.Duration, now time.Time) bool {
+	return !watchNextRenewal(state, renewBefore).After(now)
+}
+
+// renewStoredWatch renews the watch from the stored topic/labels. Unlike
+// watch renew it keeps the stored history cursor and delivery status, so a
+// running watch serve does not replay or skip history.
+func renewStoredWatch(ctx context.Context, svc *gmail.Service, store *gmailWatchStore) (gmailWatchState, error) {
+	state := store.Get()
+	if strings.TrimSpace(state.Topic) == "" {
+		return gmailWatchState{}, errors.New("stored watch state missing topic")
+	}
+	resp, err := requestGmailWatch(ctx, svc, state.Topic, state.Labels)
+	if err != nil {
+		return gmailWatchState{}, err
+	}
+	var ttl time.Duration
+	if state.RenewAfterMs > 0 && state.UpdatedAtMs > 0 && state.RenewAfterMs > state.UpdatedAtMs {
+		ttl = time.Duration(state.RenewAfterMs-state.UpdatedAtMs) * time.Millisecond
+	}
+	updated, err := buildWatchState(state.Account, state.Topic, state.Labels, resp, ttl, state.Hook)
+	if err != nil {
+		return gmailWatchState{}, err
+	}
+	if updated.Account == "" {
+		updated.Account = state.Account
+	}
+	err = store.Update(func(s *gmailWatchState) error {
+		if s.HistoryID != "" {
+			updated.HistoryID = s.HistoryID
+		}
+		updated.LastDeliveryStatus = s.LastDeliveryStatus
+		updated.LastDeliveryAtMs = s.LastDeliveryAtMs
+		updated.LastDeliveryStatusNote = s.LastDeliveryStatusNote
+		*s = updated
+		return nil
+	})
+	return updated, err
+}
+
+func writeWatchHistoryFile(path string, state gmailWatchState) error {
+	payload, err := json.MarshalIndent(watchHistoryFile{
+		Account:      state.Account,
+		HistoryID:    state.HistoryID,
+		ExpirationMs: state.ExpirationMs,
+		UpdatedAtMs:  time.Now().UnixMilli(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Plain JSON (not sealed like gog state): other programs read it.
+	return os.WriteFile(path, append(payload, '\n'), 0o600)
+}
+
+func reportWatchDaemonCheck(ctx context.Context, state gmailWatchState, renewed bool, next time.Time) error {
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(os.Stdout, map[string]any{
+			"renewed":     renewed,
+			"nextRenewal": next.Format(time.RFC3339),
+			"watch":       state,
+		})
+	}
+	u := ui.FromContext(ctx)
+	if renewed {
+		u.Out().Printf("renewed\t%s\texpires %s", state.Account, formatUnixMillis(state.ExpirationMs))
+	}
+	u.Out().Printf("next_renewal\t%s", next.Format(time.RFC3339))
+	return nil
+}
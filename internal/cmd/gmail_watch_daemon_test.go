@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestWatchNextRenewal(t *testing.T) {
+	exp := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	state := gmailWatchState{ExpirationMs: exp.UnixMilli()}
+	if got := watchNextRenewal(state, 24*time.Hour); !got.Equal(exp.Add(-24 * time.Hour)) {
+		t.Fatalf("next = %v", got)
+	}
+	state.RenewAfterMs = exp.Add(-72 * time.Hour).UnixMilli()
+	if got := watchNextRenewal(state, 24*time.Hour); !got.Equal(exp.Add(-72 * time.Hour)) {
+		t.Fatalf("renew-after should win: %v", got)
+	}
+	if watchRenewDue(state, 24*time.Hour, exp.Add(-96*time.Hour)) {
+		t.Fatalf("not due yet")
+	}
+	if !watchRenewDue(state, 24*time.Hour, exp.Add(-48*time.Hour)) {
+		t.Fatalf("expected due")
+	}
+}
+
+func TestGmailWatchDaemon_OnceRenewsAndKeepsCursor(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	t.Setenv("HOME", t.TempDir())
+
+	watchCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/gmail/v1/users/me/watch") {
+			http.NotFound(w, r)
+			return
+		}
+		watchCalls++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"historyId":  "900",
+			"expiration": strconv.FormatInt(time.Now().Add(7*24*time.Hour).UnixMilli(), 10),
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	store, err := newGmailWatchStore("a@b.com")
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	store.state = gmailWatchState{
+		Account:            "a@b.com",
+		Topic:              "projects/p/topics/t",
+		HistoryID:          "500",
+		ExpirationMs:       time.Now().Add(2 * time.Hour).UnixMilli(),
+		LastDeliveryStatus: "ok",
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	historyFile := filepath.Join(t.TempDir(), "history.json")
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "watch", "daemon", "--once", "--history-file", historyFile}); err != nil {
+			t.Fatalf("daemon: %v", err)
+		}
+	})
+	if watchCalls != 1 {
+		t.Fatalf("watch calls = %d", watchCalls)
+	}
+	var parsed struct {
+		Renewed bool            `json:"renewed"`
+		Watch   gmailWatchState `json:"watch"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if !parsed.Renewed || parsed.Watch.HistoryID != "500" {
+		t.Fatalf("unexpected: %#v", parsed)
+	}
+
+	reloaded, err := loadGmailWatchStore("a@b.com")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	got := reloaded.Get()
+	if got.HistoryID != "500" || got.LastDeliveryStatus != "ok" || got.ExpirationMs < time.Now().Add(6*24*time.Hour).UnixMilli() {
+		t.Fatalf("stored state: %#v", got)
+	}
+
+	data, err := os.ReadFile(historyFile)
+	if err != nil {
+		t.Fatalf("history file: %v", err)
+	}
+	var hf watchHistoryFile
+	if err := json.Unmarshal(data, &hf); err != nil || hf.HistoryID != "500" || hf.Account != "a@b.com" {
+		t.Fatalf("history file: %s (%v)", data, err)
+	}
+
+	// Freshly renewed: a second check does nothing.
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "watch", "daemon", "--once"}); err != nil {
+			t.Fatalf("daemon: %v", err)
+		}
+	})
+	if watchCalls != 1 {
+		t.Fatalf("unexpected renewal, watch calls = %d", watchCalls)
+	}
+}
@@ -26,6 +26,9 @@ type gmailWatchServer struct {
 	hookClient *http.Client
 	logf       func(string, ...any)
 	warnf      func(string, ...any)
+	// dispatch, when set, receives resolved messages instead of the hook
+	// (gmail notify serve).
+	dispatch func(context.Context, *gmailHookPayload) error
 }
 
 func (s *gmailWatchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -72,6 +75,14 @@ func (s *gmailWatchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.dispatch != nil {
+		if err := s.dispatch(r.Context(), result); err != nil {
+			s.warnf("watch: dispatch failed: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	if s.cfg.HookURL == "" {
 		if s.cfg.AllowNoHook {
 			_ = json.NewEncoder(w).Encode(result)
@@ -13,6 +13,7 @@ import (
 	"unicode"
 
 	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/statefile"
 )
 
 type gmailWatchStore struct {
@@ -67,7 +68,7 @@ func loadGmailWatchStore(account string) (*gmailWatchStore, error) {
 	if err != nil {
 		return nil, err
 	}
-	data, err := os.ReadFile(store.path)
+	data, err := statefile.ReadFile(store.path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, errors.New("watch state not found; run gmail watch start")
@@ -103,7 +104,7 @@ func (s *gmailWatchStore) Save() error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(s.path, append(payload, '\n'), 0o600)
+	return statefile.WriteFile(s.path, append(payload, '\n'), 0o600)
 }
 
 func (s *gmailWatchStore) StartHistoryID(pushHistory string) (uint64, error) {
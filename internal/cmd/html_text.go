@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlSpaceRe     = regexp.MustCompile(`[ \t\r\n\f]+`)
+	htmlCommentRe   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlAltRe       = regexp.MustCompile(`(?is)\balt\s*=\s*("([^"]*)"|'([^']*)')`)
+	textLineSpaceRe = regexp.MustCompile(` *\n *`)
+)
+
+// htmlToText converts an HTML message body to plain text for the terminal:
+// block elements become line breaks, list items get "- ", and links keep
+// their text with the URL moved to a numbered footnote ("text [1]").
+func htmlToText(s string) string {
+	s = htmlNoiseRe.ReplaceAllString(s, "")
+	s = htmlCommentRe.ReplaceAllString(s, "")
+
+	var b strings.Builder
+	var links []string
+	linkNum := map[string]int{}
+	var href string
+	var linkText strings.Builder
+	inLink := false
+	pre := 0
+	write := func(t string) {
+		if inLink {
+			linkText.WriteString(t)
+		} else {
+			b.WriteString(t)
+		}
+	}
+	text := func(t string) {
+		if pre == 0 {
+			t = htmlSpaceRe.ReplaceAllString(t, " ")
+		}
+		write(html.UnescapeString(t))
+	}
+
+	last := 0
+	for _, m := range htmlTagRe.FindAllStringSubmatchIndex(s, -1) {
+		text(s[last:m[0]])
+		last = m[1]
+		closing := s[m[2]:m[3]] == "/"
+		tag := strings.ToLower(s[m[4]:m[5]])
+		attrs := s[m[6]:m[7]]
+		switch tag {
+		case "br":
+			write("\n")
+		case "p", "div", "table", "blockquote", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol":
+			write("\n\n")
+		case "tr", "hr":
+			write("\n")
+		case "td", "th":
+			if !closing {
+				write(" ")
+			}
+		case "li":
+			if !closing {
+				write("\n- ")
+			}
+		case "pre":
+			if closing {
+				pre = max(pre-1, 0)
+			} else {
+				pre++
+			}
+			write("\n")
+		case "img":
+			if am := htmlAltRe.FindStringSubmatch(attrs); am != nil {
+				if alt := strings.TrimSpace(html.UnescapeString(am[2] + am[3])); alt != "" {
+					write("[" + alt + "]")
+				}
+			}
+		case "a":
+			if !closing {
+				href = ""
+				if hm := htmlHrefRe.FindStringSubmatch(attrs); hm != nil {
+					href = strings.TrimSpace(html.UnescapeString(hm[2] + hm[3]))
+				}
+				inLink = true
+				linkText.Reset()
+			} else if inLink {
+				inLink = false
+				label := strings.TrimSpace(linkText.String())
+				target := strings.TrimPrefix(href, "mailto:")
+				switch {
+				case href == "" || strings.HasPrefix(href, "#"):
+					write(label)
+				case label == "":
+					write(href)
+				case label == href || label == target:
+					write(label)
+				default:
+					n, ok := linkNum[href]
+					if !ok {
+						links = append(links, href)
+						n = len(links)
+						linkNum[href] = n
+					}
+					write(fmt.Sprintf("%s [%d]", label, n))
+				}
+			}
+		}
+	}
+	text(s[last:])
+	if inLink {
+		b.WriteString(linkText.String())
+	}
+
+	out := textLineSpaceRe.ReplaceAllString(b.String(), "\n")
+	out = strings.TrimSpace(blankLinesRe.ReplaceAllString(out, "\n\n"))
+	if len(links) > 0 {
+		out += "\n"
+		for i, l := range links {
+			out += fmt.Sprintf("\n[%d] %s", i+1, l)
+		}
+	}
+	return out
+}
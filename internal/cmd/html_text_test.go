@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestHTMLToText(t *testing.T) {
+	in := `<html><head><style>p{color:red}</style></head><body>
+<p>Hi   Ana,</p>
+<p>See the <a href="https://example.com/r?a=1&amp;b=2">report</a> and
+<a href="https://example.com/r?a=1&amp;b=2">again</a>, or mail <a href="mailto:bob@example.com">bob@example.com</a>.</p>
+<ul><li>One</li><li>Two &amp; three</li></ul>
+<!-- tracking --><img src="x.png" alt="Logo"><br>Thanks<br>Bob</body></html>`
+	want := "Hi Ana,\n\nSee the report [1] and again [1], or mail bob@example.com.\n\n- One\n- Two & three\n\n[Logo]\nThanks\nBob\n\n[1] https://example.com/r?a=1&b=2"
+	if got := htmlToText(in); got != want {
+		t.Fatalf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestBestBodyTextConvertsHTML(t *testing.T) {
+	body := base64.RawURLEncoding.EncodeToString([]byte(`<div>Hello <b>there</b></div><div><a href="https://x.test/a">link</a></div>`))
+	p := &gmail.MessagePart{Parts: []*gmail.MessagePart{{MimeType: "text/html", Body: &gmail.MessagePartBody{Data: body}}}}
+	if got := bestBodyText(p); got != "Hello there\n\nlink [1]\n\n[1] https://x.test/a" {
+		t.Fatalf("unexpected: %q", got)
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+// Record API responses into a directory, or replay them from one without
+// credentials or network (for testing scripts and commands offline).
+const (
+	httpRecordEnv = "GOG_HTTP_RECORD"
+	httpReplayEnv = "GOG_HTTP_REPLAY"
+)
+
+func httpFixturesFromEnv() (*googleapi.HTTPFixtures, error) {
+	record := strings.TrimSpace(os.Getenv(httpRecordEnv))
+	replay := strings.TrimSpace(os.Getenv(httpReplayEnv))
+	switch {
+	case record != "" && replay != "":
+		return nil, usagef("%s and %s are mutually exclusive", httpRecordEnv, httpReplayEnv)
+	case record != "":
+		return googleapi.NewHTTPFixtures(record, false), nil
+	case replay != "":
+		st, err := os.Stat(replay)
+		if err != nil || !st.IsDir() {
+			return nil, usagef("%s=%s is not a directory of recorded fixtures", httpReplayEnv, replay)
+		}
+		return googleapi.NewHTTPFixtures(replay, true), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecute_HTTPReplay_NoCredentials(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	dir := t.TempDir()
+	fixture := `{
+  "method": "GET",
+  "url": "https://gmail.googleapis.com/gmail/v1/users/me/labels?prettyPrint=false&alt=json",
+  "status": 200,
+  "contentType": "application/json",
+  "json": {"labels": [{"id": "INBOX", "name": "INBOX", "type": "system"}, {"id": "Label_1", "name": "Receipts", "type": "user"}]}
+}`
+	if err := os.WriteFile(filepath.Join(dir, "0001-get-gmail.json"), []byte(fixture), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(httpReplayEnv, dir)
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "labels", "list"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var got struct {
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if len(got.Labels) != 2 || got.Labels[1].Name != "Receipts" {
+		t.Fatalf("out = %s", out)
+	}
+
+	t.Setenv(httpRecordEnv, t.TempDir())
+	err := Execute([]string{"--account", "a@b.com", "gmail", "labels", "list"})
+	if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Fatalf("expected record/replay conflict, got %v", err)
+	}
+}
@@ -0,0 +1,624 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	gapi "google.golang.org/api/googleapi"
+)
+
+// importResult summarizes a restore; failures don't stop the run.
+type importResult struct {
+	Source   string          `json:"source"`
+	Target   string          `json:"target,omitempty"`
+	Imported int             `json:"imported"`
+	Skipped  int             `json:"skipped,omitempty"`
+	Failed   []exportFailure `json:"failed,omitempty"`
+}
+
+func newImportCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Restore data exported with gog export (or Google Takeout)",
+		Long: `Restore a backup made with gog export all (or Google Takeout) service by
+service. Contacts restore with gog contacts import "All Contacts.vcf".`,
+	}
+	cmd.AddCommand(newImportGmailCmd(flags))
+	cmd.AddCommand(newImportDriveCmd(flags))
+	cmd.AddCommand(newImportCalendarCmd(flags))
+	return cmd
+}
+
+// writeImportResult prints res and turns per-item failures into an error.
+func writeImportResult(ctx context.Context, u *ui.UI, res importResult) error {
+	if outfmt.IsJSON(ctx) {
+		if err := outfmt.WriteJSON(os.Stdout, res); err != nil {
+			return err
+		}
+	} else {
+		u.Out().Printf("source\t%s", res.Source)
+		if res.Target != "" {
+			u.Out().Printf("target\t%s", res.Target)
+		}
+		u.Out().Printf("imported\t%d", res.Imported)
+		if res.Skipped > 0 {
+			u.Out().Printf("skipped\t%d", res.Skipped)
+		}
+		for _, f := range res.Failed {
+			u.Err().Printf("FAILED\t%s\t%s", orDash(f.Name), f.Error)
+		}
+	}
+	if len(res.Failed) > 0 {
+		return fmt.Errorf("%d item(s) failed to import", len(res.Failed))
+	}
+	return nil
+}
+
+func newImportGmailCmd(flags *rootFlags) *cobra.Command {
+	var addLabels string
+
+	cmd := &cobra.Command{
+		Use:   "gmail <file.mbox>",
+		Short: "Import an mbox into Gmail with its original dates and labels",
+		Long: `Import every message of an mbox file (- for stdin) with users.messages.import.
+Messages keep their Date header as the internal date and are never marked as
+spam. Labels come from the X-Gmail-Labels header written by gog export and
+Google Takeout: system labels (Inbox, Sent, Unread, Starred, Important, ...)
+map to Gmail's, missing user labels are created, and messages without Inbox
+stay archived. --add-label applies extra labels to every message.`,
+		Example: `  gog import gmail "backup/Takeout/Mail/All mail.mbox" --add-label Restored`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			var r io.Reader
+			if args[0] == "-" {
+				r = stdinReader()
+			} else {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+
+			svc, err := newGmailService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			labels := &mboxLabelResolver{svc: svc}
+			extra, err := labels.ids(cmd.Context(), splitCSV(addLabels))
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			res := importResult{Source: args[0], Target: account}
+			n := 0
+			err = readMbox(r, func(raw []byte) error {
+				n++
+				names, body := takeGmailLabelHeaders(raw)
+				ids, err := labels.ids(ctx, names)
+				if err == nil {
+					_, err = svc.Users.Messages.Import("me", &gmail.Message{LabelIds: append(ids, extra...)}).
+						Media(bytes.NewReader(body), gapi.ContentType("message/rfc822")).
+						InternalDateSource("dateHeader").
+						NeverMarkSpam(true).
+						Context(ctx).
+						Do()
+				}
+				if err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					res.Failed = append(res.Failed, exportFailure{ID: fmt.Sprint(n), Name: fmt.Sprintf("message %d", n), Error: err.Error()})
+					return nil
+				}
+				res.Imported++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return writeImportResult(ctx, u, res)
+		},
+	}
+
+	cmd.Flags().StringVar(&addLabels, "add-label", "", "Extra labels for every message (comma-separated, name or ID; created if missing)")
+	return cmd
+}
+
+// readMbox calls fn with each message of an mbox (mboxo or mboxrd), with
+// the "From " separator removed, ">From " quoting undone and LF line
+// endings.
+func readMbox(r io.Reader, fn func(raw []byte) error) error {
+	br := bufio.NewReader(r)
+	var msg bytes.Buffer
+	started, prevBlank := false, true
+	flush := func() error {
+		if !started {
+			return nil
+		}
+		raw := bytes.TrimRight(msg.Bytes(), "\r\n")
+		msg.Reset()
+		if len(raw) == 0 {
+			return nil
+		}
+		return fn(append(raw, '\n'))
+	}
+	for {
+		line, err := br.ReadString('\n')
+		if strings.HasSuffix(line, "\r\n") {
+			line = line[:len(line)-2] + "\n"
+		}
+		if line != "" {
+			switch {
+			case strings.HasPrefix(line, "From ") && prevBlank:
+				if ferr := flush(); ferr != nil {
+					return ferr
+				}
+				started = true
+			case started:
+				if q := strings.TrimLeft(line, ">"); len(q) < len(line) && strings.HasPrefix(q, "From ") {
+					line = line[1:]
+				}
+				msg.WriteString(line)
+			}
+			prevBlank = strings.TrimRight(line, "\r\n") == ""
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !started {
+		return errors.New("not an mbox file (no \"From \" separator line)")
+	}
+	return flush()
+}
+
+// takeGmailLabelHeaders returns the X-Gmail-Labels names of raw and the
+// message without the X-Gmail-Labels and X-GM-THRID headers.
+func takeGmailLabelHeaders(raw []byte) ([]string, []byte) {
+	end := bytes.Index(raw, []byte("\n\n"))
+	if end < 0 {
+		end = len(raw)
+	}
+	// Group folded continuation lines with the header line they belong to.
+	var fields []string
+	for _, line := range strings.SplitAfter(string(raw[:end]), "\n") {
+		if len(fields) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			fields[len(fields)-1] += line
+			continue
+		}
+		fields = append(fields, line)
+	}
+	var names []string
+	var out bytes.Buffer
+	for _, field := range fields {
+		name, value, _ := strings.Cut(field, ":")
+		switch {
+		case strings.EqualFold(name, "X-Gmail-Labels"):
+			rd := csv.NewReader(strings.NewReader(strings.Join(strings.Fields(value), " ")))
+			rd.LazyQuotes = true
+			rd.TrimLeadingSpace = true
+			if rec, err := rd.Read(); err == nil {
+				names = append(names, rec...)
+			}
+		case strings.EqualFold(name, "X-GM-THRID"):
+		default:
+			out.WriteString(field)
+		}
+	}
+	out.Write(raw[end:])
+	return names, out.Bytes()
+}
+
+// mboxLabelResolver maps Takeout label names to label IDs, creating user
+// labels on first use.
+type mboxLabelResolver struct {
+	svc    *gmail.Service
+	byName map[string]string
+}
+
+// gmailSystemLabels maps Takeout's names for system labels to their IDs;
+// an empty ID means the label can't be set on import.
+var gmailSystemLabels = map[string]string{
+	"inbox": "INBOX", "sent": "SENT", "unread": "UNREAD", "starred": "STARRED",
+	"important": "IMPORTANT", "spam": "SPAM", "trash": "TRASH",
+	"opened": "", "archived": "", "draft": "", "drafts": "", "chat": "", "sent mail": "SENT",
+	"category personal": "CATEGORY_PERSONAL", "category social": "CATEGORY_SOCIAL",
+	"category promotions": "CATEGORY_PROMOTIONS", "category updates": "CATEGORY_UPDATES",
+	"category forums": "CATEGORY_FORUMS",
+}
+
+func (m *mboxLabelResolver) ids(ctx context.Context, names []string) ([]string, error) {
+	var out []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(strings.ReplaceAll(name, "_", " "))
+		if id, ok := gmailSystemLabels[key]; ok {
+			if id != "" {
+				out = append(out, id)
+			}
+			continue
+		}
+		if m.byName == nil {
+			byName, err := fetchLabelNameToID(ctx, m.svc)
+			if err != nil {
+				return nil, err
+			}
+			m.byName = byName
+		}
+		id, ok := m.byName[strings.ToLower(name)]
+		if !ok {
+			var err error
+			if id, err = ensureLabelID(ctx, m.svc, name); err != nil {
+				return nil, err
+			}
+			m.byName[strings.ToLower(name)] = id
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+func newImportDriveCmd(flags *rootFlags) *cobra.Command {
+	var parent string
+	var convert bool
+
+	cmd := &cobra.Command{
+		Use:   "drive <dir>",
+		Short: "Upload a local directory tree (e.g. an exported Drive) into Drive",
+		Long: `Recreate the folders below <dir> in --parent (default: My Drive) and upload
+every file into them. Hidden files are skipped. --convert turns Office,
+OpenDocument and CSV files back into Google Docs, Sheets and Slides.
+Re-running creates new copies; restore into an empty folder.`,
+		Example: `  gog drive mkdir Restored
+  gog import drive backup/Takeout/Drive --parent <folderId> --convert`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			root := args[0]
+			if st, err := os.Stat(root); err != nil {
+				return err
+			} else if !st.IsDir() {
+				return usagef("%s is not a directory", root)
+			}
+			parent = strings.TrimSpace(parent)
+			if parent == "" {
+				parent = "root"
+			}
+
+			svc, err := newDriveService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			res := importResult{Source: root, Target: parent}
+			folders := map[string]string{".": parent}
+			err = filepath.WalkDir(root, func(path string, d os.DirEntry, walkErr error) error {
+				if walkErr != nil {
+					return walkErr
+				}
+				rel, err := filepath.Rel(root, path)
+				if err != nil || rel == "." {
+					return err
+				}
+				if strings.HasPrefix(d.Name(), ".") {
+					if d.IsDir() {
+						return filepath.SkipDir
+					}
+					res.Skipped++
+					return nil
+				}
+				parentID, ok := folders[filepath.Dir(rel)]
+				if !ok {
+					// The parent folder failed to upload.
+					res.Skipped++
+					if d.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if d.IsDir() {
+					created, err := svc.Files.Create(&drive.File{Name: d.Name(), MimeType: driveFolderMimeType, Parents: []string{parentID}}).
+						SupportsAllDrives(true).Fields("id").Context(ctx).Do()
+					if err != nil {
+						if ctx.Err() != nil {
+							return ctx.Err()
+						}
+						res.Failed = append(res.Failed, exportFailure{Name: rel, Error: err.Error()})
+						return filepath.SkipDir
+					}
+					folders[rel] = created.Id
+					return nil
+				}
+				if err := importDriveFile(ctx, svc, path, parentID, convert); err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					res.Failed = append(res.Failed, exportFailure{Name: rel, Error: err.Error()})
+					return nil
+				}
+				res.Imported++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return writeImportResult(ctx, u, res)
+		},
+	}
+
+	cmd.Flags().StringVar(&parent, "parent", "", "Destination folder ID (default: My Drive)")
+	cmd.Flags().BoolVar(&convert, "convert", false, "Convert Office/OpenDocument/CSV files to Google Docs, Sheets and Slides")
+	return cmd
+}
+
+func importDriveFile(ctx context.Context, svc *drive.Service, path, parentID string, convert bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	meta := &drive.File{Name: filepath.Base(path), Parents: []string{parentID}}
+	if convert {
+		if target := driveImportConversion(path); target != "" {
+			meta.MimeType = target
+			meta.Name = strings.TrimSuffix(meta.Name, filepath.Ext(meta.Name))
+		}
+	}
+	_, err = svc.Files.Create(meta).
+		SupportsAllDrives(true).
+		Media(f, gapi.ContentType(guessMimeType(path))).
+		Fields("id").
+		Context(ctx).
+		Do()
+	return err
+}
+
+// driveImportConversion returns the Google type a file converts to, or "".
+func driveImportConversion(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".docx", ".doc", ".odt", ".rtf":
+		return "application/vnd.google-apps.document"
+	case ".xlsx", ".xls", ".ods", ".csv":
+		return "application/vnd.google-apps.spreadsheet"
+	case ".pptx", ".ppt", ".odp":
+		return "application/vnd.google-apps.presentation"
+	default:
+		return ""
+	}
+}
+
+func newImportCalendarCmd(flags *rootFlags) *cobra.Command {
+	var calendarID string
+	var newCalendar bool
+
+	cmd := &cobra.Command{
+		Use:   "calendar <file.ics>",
+		Short: "Import the events of an iCalendar file",
+		Long: `Import every VEVENT of an .ics file (- for stdin) with events.import, which
+keeps each event's UID: importing the same file again updates the events
+instead of duplicating them. Recurrence rules and modified occurrences are
+kept. --new creates a calendar named after the file's X-WR-CALNAME (or the
+file name) and imports into it; otherwise events go to --calendar.`,
+		Example: `  gog import calendar backup/Takeout/Calendar/Work.ics --new
+  gog import calendar holidays.ics --calendar primary`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			if newCalendar && cmd.Flags().Changed("calendar") {
+				return usage("--new and --calendar are mutually exclusive")
+			}
+			data, err := readFileOrStdin(args[0])
+			if err != nil {
+				return err
+			}
+			name, events, err := parseICS(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+
+			svc, err := newCalendarService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			if newCalendar {
+				if name == "" {
+					name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+				}
+				created, err := svc.Calendars.Insert(&calendar.Calendar{Summary: name}).Context(ctx).Do()
+				if err != nil {
+					return fmt.Errorf("create calendar %q: %w", name, err)
+				}
+				calendarID = created.Id
+			}
+
+			res := importResult{Source: args[0], Target: calendarID}
+			for _, e := range events {
+				if _, err := svc.Events.Import(calendarID, e).Context(ctx).Do(); err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					res.Failed = append(res.Failed, exportFailure{ID: e.ICalUID, Name: orDash(e.Summary), Error: err.Error()})
+					continue
+				}
+				res.Imported++
+			}
+			return writeImportResult(ctx, u, res)
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "primary", "Calendar ID to import into")
+	cmd.Flags().BoolVar(&newCalendar, "new", false, "Create a new calendar for the events")
+	return cmd
+}
+
+// parseICS reads the calendar name and VEVENTs of an iCalendar file.
+// Nested components (VALARM, ...) are ignored.
+func parseICS(r io.Reader) (string, []*calendar.Event, error) {
+	lines, err := unfoldVCardLines(r)
+	if err != nil {
+		return "", nil, err
+	}
+	var name string
+	var events []*calendar.Event
+	var cur *calendar.Event
+	depth := 0
+	for _, l := range lines {
+		prop, params, value, ok := splitVCardLine(l)
+		if !ok {
+			continue
+		}
+		switch prop {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") && cur == nil {
+				cur = &calendar.Event{}
+			} else if cur != nil {
+				depth++
+			}
+			continue
+		case "END":
+			if cur == nil {
+				continue
+			}
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if cur.Start != nil {
+				if cur.End == nil {
+					cur.End = cur.Start
+				}
+				if cur.ICalUID == "" {
+					sum := sha256.Sum256([]byte(cur.Summary + cur.Start.Date + cur.Start.DateTime))
+					cur.ICalUID = hex.EncodeToString(sum[:16]) + "@gogcli"
+				}
+				events = append(events, cur)
+			}
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			if prop == "X-WR-CALNAME" {
+				name = unescapeVCard(value)
+			}
+			continue
+		}
+		if depth > 0 {
+			continue
+		}
+		switch prop {
+		case "UID":
+			cur.ICalUID = value
+		case "SUMMARY":
+			cur.Summary = unescapeVCard(value)
+		case "DESCRIPTION":
+			cur.Description = unescapeVCard(value)
+		case "LOCATION":
+			cur.Location = unescapeVCard(value)
+		case "STATUS":
+			cur.Status = strings.ToLower(value)
+		case "DTSTART":
+			if cur.Start, err = parseICSTime(params, value); err != nil {
+				return "", nil, err
+			}
+		case "DTEND":
+			if cur.End, err = parseICSTime(params, value); err != nil {
+				return "", nil, err
+			}
+		case "RECURRENCE-ID":
+			if cur.OriginalStartTime, err = parseICSTime(params, value); err != nil {
+				return "", nil, err
+			}
+		case "RRULE", "EXRULE", "RDATE", "EXDATE":
+			cur.Recurrence = append(cur.Recurrence, l)
+		}
+	}
+	if len(events) == 0 {
+		return "", nil, errors.New("no events found in iCalendar data")
+	}
+	return name, events, nil
+}
+
+// parseICSTime converts a DATE, UTC, TZID or floating DATE-TIME value.
+func parseICSTime(params []string, value string) (*calendar.EventDateTime, error) {
+	tzid := ""
+	for _, p := range params {
+		k, v, _ := strings.Cut(p, "=")
+		switch strings.ToUpper(k) {
+		case "VALUE":
+			if strings.EqualFold(v, "DATE") {
+				t, err := time.Parse("20060102", value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid date %q", value)
+				}
+				return &calendar.EventDateTime{Date: t.Format("2006-01-02")}, nil
+			}
+		case "TZID":
+			tzid = strings.Trim(v, `"`)
+		}
+	}
+	if len(value) == 8 {
+		return parseICSTime([]string{"VALUE=DATE"}, value)
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date-time %q", value)
+		}
+		return &calendar.EventDateTime{DateTime: t.Format(time.RFC3339)}, nil
+	}
+	loc := time.Local
+	if tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date-time %q", value)
+	}
+	out := &calendar.EventDateTime{DateTime: t.Format(time.RFC3339)}
+	if tzid != "" {
+		out.TimeZone = tzid
+	}
+	return out, nil
+}
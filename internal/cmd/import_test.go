@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_ImportGmailMbox(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+
+	var mbox bytes.Buffer
+	_ = writeMboxMessage(&mbox, &gmail.Message{Id: "m1", ThreadId: "t1", LabelIds: []string{"INBOX", "Label_1"}, InternalDate: 1735689600000},
+		[]byte("Subject: one\r\n\r\nFrom the top\r\n"), map[string]string{"Label_1": "Receipts"})
+	_ = writeMboxMessage(&mbox, &gmail.Message{Id: "m2", LabelIds: []string{"Opened", "New Label", "UNREAD"}},
+		[]byte("Subject: two\r\n\r\nbody\r\n"), nil)
+	path := filepath.Join(t.TempDir(), "All mail.mbox")
+	if err := os.WriteFile(path, mbox.Bytes(), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var mu sync.Mutex
+	var imports []string
+	created := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/me/labels") && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX"}, {"id": "Label_1", "name": "Receipts"}}})
+		case strings.HasSuffix(r.URL.Path, "/users/me/labels") && r.Method == http.MethodPost:
+			var l gmail.Label
+			_ = json.NewDecoder(r.Body).Decode(&l)
+			created = l.Name
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "Label_2", "name": l.Name})
+		case strings.HasSuffix(r.URL.Path, "/users/me/messages/import"):
+			if r.URL.Query().Get("internalDateSource") != "dateHeader" || r.URL.Query().Get("neverMarkSpam") != "true" {
+				t.Errorf("query = %s", r.URL.RawQuery)
+			}
+			b, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			imports = append(imports, string(b))
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "new"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "import", "gmail", path}); err != nil {
+			t.Fatalf("import: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"imported": 2`) || created != "New Label" || len(imports) != 2 {
+		t.Fatalf("out=%s created=%q imports=%d", out, created, len(imports))
+	}
+	if !strings.Contains(imports[0], `"labelIds":["INBOX","Label_1"]`) || !strings.Contains(imports[0], "\n\nFrom the top\n") ||
+		strings.Contains(imports[0], "X-Gmail-Labels") || strings.Contains(imports[0], "X-GM-THRID") {
+		t.Fatalf("first import = %s", imports[0])
+	}
+	if !strings.Contains(imports[1], `"labelIds":["Label_2","UNREAD"]`) {
+		t.Fatalf("second import = %s", imports[1])
+	}
+}
+
+func TestExecute_ImportDriveTree(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	root := t.TempDir()
+	_ = os.MkdirAll(filepath.Join(root, "Work"), 0o700)
+	_ = os.WriteFile(filepath.Join(root, "Work", "plan.docx"), []byte("doc"), 0o600)
+	_ = os.WriteFile(filepath.Join(root, "photo.png"), []byte("png"), 0o600)
+	_ = os.WriteFile(filepath.Join(root, ".DS_Store"), []byte("x"), 0o600)
+
+	var created []drive.File
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/files") {
+			http.NotFound(w, r)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		body := string(b)
+		if i := strings.Index(body, "{"); i >= 0 {
+			body = body[i:]
+		}
+		var f drive.File
+		_ = json.NewDecoder(strings.NewReader(body)).Decode(&f)
+		created = append(created, f)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "id-" + f.Name})
+	}))
+	defer srv.Close()
+	svc, err := drive.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "import", "drive", root, "--parent", "dest", "--convert"}); err != nil {
+			t.Fatalf("import: %v", err)
+		}
+	})
+	got := map[string]drive.File{}
+	for _, f := range created {
+		got[f.Name] = f
+	}
+	if len(created) != 3 || got["Work"].MimeType != driveFolderMimeType || got["Work"].Parents[0] != "dest" ||
+		got["photo.png"].Parents[0] != "dest" || got["plan"].Parents[0] != "id-Work" ||
+		got["plan"].MimeType != "application/vnd.google-apps.document" {
+		t.Fatalf("created = %+v", created)
+	}
+}
+
+func TestParseICSRoundTrip(t *testing.T) {
+	ics := eventsToICS("Team; EU", []*calendar.Event{
+		{ICalUID: "e1@google.com", Summary: "Standup, daily", Description: "line1\nline2",
+			Start: &calendar.EventDateTime{DateTime: "2025-01-06T09:00:00+01:00"}, End: &calendar.EventDateTime{DateTime: "2025-01-06T09:15:00+01:00"},
+			Recurrence: []string{"RRULE:FREQ=DAILY", "EXDATE:20250107T080000Z"}},
+		{Id: "e2", Summary: "Offsite", Start: &calendar.EventDateTime{Date: "2025-03-01"}, End: &calendar.EventDateTime{Date: "2025-03-02"}},
+	})
+	ics = strings.Replace(ics, "END:VEVENT", "BEGIN:VALARM\r\nSUMMARY:ignored\r\nEND:VALARM\r\nEND:VEVENT", 1)
+	name, events, err := parseICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if name != "Team; EU" || len(events) != 2 {
+		t.Fatalf("name=%q events=%d", name, len(events))
+	}
+	e := events[0]
+	if e.ICalUID != "e1@google.com" || e.Summary != "Standup, daily" || e.Description != "line1\nline2" ||
+		e.Start.DateTime != "2025-01-06T08:00:00Z" || len(e.Recurrence) != 2 {
+		t.Fatalf("event = %+v start=%+v", e, e.Start)
+	}
+	if events[1].Start.Date != "2025-03-01" || events[1].ICalUID != "e2" {
+		t.Fatalf("all-day = %+v", events[1])
+	}
+
+	dt, err := parseICSTime([]string{"TZID=Europe/Berlin"}, "20250106T090000")
+	if err != nil || dt.DateTime != "2025-01-06T09:00:00+01:00" || dt.TimeZone != "Europe/Berlin" {
+		t.Fatalf("tzid = %+v %v", dt, err)
+	}
+}
@@ -0,0 +1,401 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// --body-md renders Markdown to email HTML. Mail clients drop <style>
+// blocks unpredictably, so every element carries inline styles. The plain
+// text alternative is derived from the same parse.
+
+var (
+	mdFenceRe    = regexp.MustCompile("^\\s*(```|~~~)\\s*([\\w+-]*)\\s*$")
+	mdRuleRe     = regexp.MustCompile(`^\s{0,3}([-*_])(\s*([-*_])){2,}\s*$`)
+	mdQuoteRe    = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	mdTableSepRe = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	mdStrikeRe   = regexp.MustCompile(`~~(.+?)~~`)
+	mdAutoLinkRe = regexp.MustCompile(`&lt;(https?://[^\s&]+)&gt;`)
+)
+
+var mdEmailStyles = map[string]string{
+	"body":       "font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;font-size:14px;line-height:1.5;color:#222",
+	"p":          "margin:0 0 12px 0",
+	"h1":         "font-size:22px;margin:18px 0 10px 0",
+	"h2":         "font-size:18px;margin:16px 0 8px 0",
+	"h3":         "font-size:16px;margin:14px 0 6px 0",
+	"ul":         "margin:0 0 12px 0;padding-left:24px",
+	"li":         "margin:2px 0",
+	"pre":        "background:#f6f8fa;border:1px solid #e1e4e8;border-radius:4px;padding:10px;overflow:auto;font-family:Menlo,Consolas,monospace;font-size:13px;line-height:1.4;margin:0 0 12px 0",
+	"code":       "background:#f6f8fa;border-radius:3px;padding:1px 4px;font-family:Menlo,Consolas,monospace;font-size:13px",
+	"blockquote": "margin:0 0 12px 0;padding:0 12px;border-left:4px solid #dfe2e5;color:#555",
+	"table":      "border-collapse:collapse;margin:0 0 12px 0",
+	"th":         "border:1px solid #d0d7de;padding:6px 10px;background:#f6f8fa;font-weight:600",
+	"td":         "border:1px solid #d0d7de;padding:6px 10px",
+	"hr":         "border:0;border-top:1px solid #e1e4e8;margin:16px 0",
+	"a":          "color:#0969da",
+}
+
+type mdBlockKind int
+
+const (
+	mdParagraph mdBlockKind = iota
+	mdHeading
+	mdCode
+	mdRule
+	mdQuote
+	mdList
+	mdTable
+)
+
+// mdBlock is one parsed Markdown block. Lines holds paragraph/code lines,
+// list items, or table rows joined by "\x1f"; Children holds a quote's
+// blocks.
+type mdBlock struct {
+	Kind     mdBlockKind
+	Level    int // heading level
+	Ordered  bool
+	Lines    []string
+	Align    []string
+	Children []mdBlock
+}
+
+func parseMarkdownBlocks(md string) []mdBlock {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	var blocks []mdBlock
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			i++
+		case mdFenceRe.MatchString(line):
+			fence := mdFenceRe.FindStringSubmatch(line)[1]
+			var code []string
+			i++
+			for i < len(lines) && strings.TrimSpace(lines[i]) != fence {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // closing fence
+			blocks = append(blocks, mdBlock{Kind: mdCode, Lines: code})
+		case mdHeadingRe.MatchString(trimmed):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			blocks = append(blocks, mdBlock{Kind: mdHeading, Level: level, Lines: []string{mdHeadingRe.FindStringSubmatch(trimmed)[1]}})
+			i++
+		case mdRuleRe.MatchString(line):
+			blocks = append(blocks, mdBlock{Kind: mdRule})
+			i++
+		case mdQuoteRe.MatchString(line):
+			var inner []string
+			for i < len(lines) && mdQuoteRe.MatchString(lines[i]) {
+				inner = append(inner, mdQuoteRe.FindStringSubmatch(lines[i])[1])
+				i++
+			}
+			blocks = append(blocks, mdBlock{Kind: mdQuote, Children: parseMarkdownBlocks(strings.Join(inner, "\n"))})
+		case mdBulletRe.MatchString(line) || mdNumberRe.MatchString(line):
+			ordered := !mdBulletRe.MatchString(line)
+			b := mdBlock{Kind: mdList, Ordered: ordered}
+		items:
+			for i < len(lines) {
+				l := lines[i]
+				switch {
+				case !ordered && mdBulletRe.MatchString(l):
+					b.Lines = append(b.Lines, mdBulletRe.FindStringSubmatch(l)[1])
+				case ordered && mdNumberRe.MatchString(l):
+					b.Lines = append(b.Lines, mdNumberRe.FindStringSubmatch(l)[1])
+				case strings.TrimSpace(l) != "" && (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(b.Lines) > 0:
+					b.Lines[len(b.Lines)-1] += " " + strings.TrimSpace(l)
+				default:
+					break items
+				}
+				i++
+			}
+			blocks = append(blocks, b)
+		case strings.Contains(line, "|") && i+1 < len(lines) && mdTableSepRe.MatchString(lines[i+1]) && strings.Contains(lines[i+1], "-"):
+			b := mdBlock{Kind: mdTable, Lines: []string{strings.Join(splitTableRow(line), "\x1f")}}
+			for _, cell := range splitTableRow(lines[i+1]) {
+				switch c := strings.TrimSpace(cell); {
+				case strings.HasPrefix(c, ":") && strings.HasSuffix(c, ":"):
+					b.Align = append(b.Align, "center")
+				case strings.HasSuffix(c, ":"):
+					b.Align = append(b.Align, "right")
+				default:
+					b.Align = append(b.Align, "")
+				}
+			}
+			i += 2
+			for i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != "" {
+				b.Lines = append(b.Lines, strings.Join(splitTableRow(lines[i]), "\x1f"))
+				i++
+			}
+			blocks = append(blocks, b)
+		default:
+			b := mdBlock{Kind: mdParagraph}
+			for i < len(lines) {
+				l := lines[i]
+				if strings.TrimSpace(l) == "" || mdFenceRe.MatchString(l) || mdHeadingRe.MatchString(strings.TrimSpace(l)) ||
+					mdQuoteRe.MatchString(l) || mdBulletRe.MatchString(l) || mdNumberRe.MatchString(l) || (len(b.Lines) > 0 && mdRuleRe.MatchString(l)) {
+					break
+				}
+				b.Lines = append(b.Lines, l)
+				i++
+			}
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i, c := range cells {
+		cells[i] = strings.TrimSpace(c)
+	}
+	return cells
+}
+
+// mdEmailInline renders inline Markdown (code, links, bold, italic,
+// strikethrough) as styled HTML.
+func mdEmailInline(s string) string {
+	var codes []string
+	s = html.EscapeString(s)
+	s = mdCodeRe.ReplaceAllStringFunc(s, func(m string) string {
+		codes = append(codes, `<code style="`+mdEmailStyles["code"]+`">`+mdCodeRe.FindStringSubmatch(m)[1]+`</code>`)
+		return fmt.Sprintf("\x00%d\x00", len(codes)-1)
+	})
+	s = mdLinkRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdLinkRe.FindStringSubmatch(m)
+		return `<a href="` + sub[2] + `" style="` + mdEmailStyles["a"] + `">` + sub[1] + `</a>`
+	})
+	s = mdAutoLinkRe.ReplaceAllString(s, `<a href="$1" style="`+mdEmailStyles["a"]+`">$1</a>`)
+	s = mdBoldRe.ReplaceAllString(s, "<strong>$2</strong>")
+	s = mdItalicRe.ReplaceAllString(s, "$1<em>$2</em>$3")
+	s = mdStrikeRe.ReplaceAllString(s, "<s>$1</s>")
+	for i, c := range codes {
+		s = strings.Replace(s, fmt.Sprintf("\x00%d\x00", i), c, 1)
+	}
+	return s
+}
+
+// mdPlainInline strips inline Markdown; links become "text (url)".
+func mdPlainInline(s string) string {
+	var codes []string
+	s = mdCodeRe.ReplaceAllStringFunc(s, func(m string) string {
+		codes = append(codes, mdCodeRe.FindStringSubmatch(m)[1])
+		return fmt.Sprintf("\x00%d\x00", len(codes)-1)
+	})
+	s = mdLinkRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdLinkRe.FindStringSubmatch(m)
+		if sub[1] == sub[2] {
+			return sub[2]
+		}
+		return sub[1] + " (" + sub[2] + ")"
+	})
+	s = mdBoldRe.ReplaceAllString(s, "$2")
+	s = mdItalicRe.ReplaceAllString(s, "$1$2$3")
+	s = mdStrikeRe.ReplaceAllString(s, "$1")
+	for i, c := range codes {
+		s = strings.Replace(s, fmt.Sprintf("\x00%d\x00", i), c, 1)
+	}
+	return s
+}
+
+// paragraphLines joins paragraph lines; a trailing double space or
+// backslash forces a line break.
+func paragraphLines(lines []string, inline func(string) string, br string) string {
+	var b strings.Builder
+	for i, l := range lines {
+		hard := strings.HasSuffix(l, "  ") || strings.HasSuffix(l, "\\")
+		b.WriteString(inline(strings.TrimSpace(strings.TrimSuffix(l, "\\"))))
+		if i < len(lines)-1 {
+			if hard {
+				b.WriteString(br)
+			} else {
+				b.WriteString(" ")
+			}
+		}
+	}
+	return b.String()
+}
+
+func styled(tag string) string {
+	style := mdEmailStyles[tag]
+	if style == "" {
+		return "<" + tag + ">"
+	}
+	return "<" + tag + ` style="` + style + `">`
+}
+
+func renderMarkdownBlocksHTML(b *strings.Builder, blocks []mdBlock) {
+	for _, blk := range blocks {
+		switch blk.Kind {
+		case mdParagraph:
+			b.WriteString(styled("p") + paragraphLines(blk.Lines, mdEmailInline, "<br>") + "</p>\n")
+		case mdHeading:
+			tag := fmt.Sprintf("h%d", min(blk.Level, 6))
+			if blk.Level > 3 {
+				b.WriteString("<" + tag + ` style="` + mdEmailStyles["h3"] + `">`)
+			} else {
+				b.WriteString(styled(tag))
+			}
+			b.WriteString(mdEmailInline(blk.Lines[0]) + "</" + tag + ">\n")
+		case mdCode:
+			b.WriteString(styled("pre") + html.EscapeString(strings.Join(blk.Lines, "\n")) + "</pre>\n")
+		case mdRule:
+			b.WriteString(`<hr style="` + mdEmailStyles["hr"] + `">` + "\n")
+		case mdQuote:
+			b.WriteString(styled("blockquote") + "\n")
+			renderMarkdownBlocksHTML(b, blk.Children)
+			b.WriteString("</blockquote>\n")
+		case mdList:
+			tag := "ul"
+			if blk.Ordered {
+				tag = "ol"
+			}
+			b.WriteString("<" + tag + ` style="` + mdEmailStyles["ul"] + `">` + "\n")
+			for _, item := range blk.Lines {
+				b.WriteString(styled("li") + mdEmailInline(item) + "</li>\n")
+			}
+			b.WriteString("</" + tag + ">\n")
+		case mdTable:
+			b.WriteString(styled("table") + "\n")
+			for r, row := range blk.Lines {
+				tag := "td"
+				if r == 0 {
+					tag = "th"
+				}
+				b.WriteString("<tr>")
+				for c, cell := range strings.Split(row, "\x1f") {
+					style := mdEmailStyles[tag]
+					if c < len(blk.Align) && blk.Align[c] != "" {
+						style += ";text-align:" + blk.Align[c]
+					} else if tag == "th" {
+						style += ";text-align:left"
+					}
+					b.WriteString("<" + tag + ` style="` + style + `">` + mdEmailInline(cell) + "</" + tag + ">")
+				}
+				b.WriteString("</tr>\n")
+			}
+			b.WriteString("</table>\n")
+		}
+	}
+}
+
+func renderMarkdownBlocksText(blocks []mdBlock) string {
+	parts := make([]string, 0, len(blocks))
+	for _, blk := range blocks {
+		switch blk.Kind {
+		case mdParagraph:
+			parts = append(parts, paragraphLines(blk.Lines, mdPlainInline, "\n"))
+		case mdHeading:
+			text := mdPlainInline(blk.Lines[0])
+			switch blk.Level {
+			case 1:
+				text += "\n" + strings.Repeat("=", len([]rune(text)))
+			case 2:
+				text += "\n" + strings.Repeat("-", len([]rune(text)))
+			}
+			parts = append(parts, text)
+		case mdCode:
+			lines := make([]string, len(blk.Lines))
+			for i, l := range blk.Lines {
+				lines[i] = "    " + l
+			}
+			parts = append(parts, strings.Join(lines, "\n"))
+		case mdRule:
+			parts = append(parts, strings.Repeat("-", 40))
+		case mdQuote:
+			inner := strings.Split(renderMarkdownBlocksText(blk.Children), "\n")
+			for i, l := range inner {
+				inner[i] = strings.TrimRight("> "+l, " ")
+			}
+			parts = append(parts, strings.Join(inner, "\n"))
+		case mdList:
+			lines := make([]string, len(blk.Lines))
+			for i, item := range blk.Lines {
+				prefix := "- "
+				if blk.Ordered {
+					prefix = fmt.Sprintf("%d. ", i+1)
+				}
+				lines[i] = prefix + mdPlainInline(item)
+			}
+			parts = append(parts, strings.Join(lines, "\n"))
+		case mdTable:
+			parts = append(parts, markdownTableText(blk))
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// markdownTableText lays a table out in padded columns.
+func markdownTableText(blk mdBlock) string {
+	rows := make([][]string, len(blk.Lines))
+	var widths []int
+	for r, row := range blk.Lines {
+		for c, cell := range strings.Split(row, "\x1f") {
+			cell = mdPlainInline(cell)
+			rows[r] = append(rows[r], cell)
+			if c >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[c] = max(widths[c], len([]rune(cell)))
+		}
+	}
+	var lines []string
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for c, cell := range row {
+			cells[c] = cell + strings.Repeat(" ", widths[c]-len([]rune(cell)))
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, "  "), " "))
+		if r == 0 {
+			seps := make([]string, len(widths))
+			for c, w := range widths {
+				seps[c] = strings.Repeat("-", w)
+			}
+			lines = append(lines, strings.Join(seps, "  "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownToEmail renders Markdown to inline-styled HTML and a plain-text
+// alternative.
+func markdownToEmail(md string) (htmlBody, plain string) {
+	blocks := parseMarkdownBlocks(md)
+	var b strings.Builder
+	b.WriteString(`<div style="` + mdEmailStyles["body"] + `">` + "\n")
+	renderMarkdownBlocksHTML(&b, blocks)
+	b.WriteString("</div>")
+	return b.String(), renderMarkdownBlocksText(blocks)
+}
+
+// applyBodyMarkdown fills the HTML body (and the plain body, unless --body
+// was given) from a --body-md file.
+func applyBodyMarkdown(path string, body, bodyHTML *string) error {
+	if strings.TrimSpace(path) == "" {
+		return nil
+	}
+	if strings.TrimSpace(*bodyHTML) != "" {
+		return usage("--body-md cannot be combined with --body-html")
+	}
+	data, err := readFileOrStdin(path)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return usagef("%s is empty", path)
+	}
+	htmlBody, plain := markdownToEmail(string(data))
+	*bodyHTML = htmlBody
+	if strings.TrimSpace(*body) == "" {
+		*body = plain
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestMarkdownToEmail(t *testing.T) {
+	md := "# Status\n\nShip **v2** on `main`, see [notes](https://x.test/n?a=1&b=2).\n\n" +
+		"- one\n- two\n\n| Task | Owner |\n|------|------:|\n| Build | Ana |\n\n```go\nif a < b {}\n```\n\n> quoted"
+	htmlBody, plain := markdownToEmail(md)
+	for _, want := range []string{
+		`<h1 style="`,
+		"<strong>v2</strong>",
+		`<code style="`,
+		`<a href="https://x.test/n?a=1&amp;b=2" style="`,
+		`<li style="margin:2px 0">two</li>`,
+		`<th style="` + mdEmailStyles["th"] + `;text-align:left">Task</th>`,
+		`;text-align:right">Ana</td>`,
+		"if a &lt; b {}</pre>",
+		`<blockquote style="`,
+	} {
+		if !strings.Contains(htmlBody, want) {
+			t.Fatalf("html missing %q:\n%s", want, htmlBody)
+		}
+	}
+	wantPlain := "Status\n======\n\nShip v2 on main, see notes (https://x.test/n?a=1&b=2).\n\n- one\n- two\n\n" +
+		"Task   Owner\n-----  -----\nBuild  Ana\n\n    if a < b {}\n\n> quoted"
+	if plain != wantPlain {
+		t.Fatalf("plain = %q\nwant    %q", plain, wantPlain)
+	}
+}
+
+func TestExecute_GmailSend_BodyMarkdown(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) {
+		return nil, errors.New("dry run must not create a Gmail service")
+	}
+	t.Setenv("GOG_GMAIL_ALLOWLIST", "example.com")
+	t.Setenv("GOG_GMAIL_REQUIRE_ARM", "1")
+	path := filepath.Join(t.TempDir(), "body.md")
+	if err := os.WriteFile(path, []byte("Hello **Ana**"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "gmail", "send", "--dry-run", "--to", "x@example.com", "--subject", "Hi", "--body-md", path}); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "multipart/alternative") || !strings.Contains(out, "Hello Ana") || !strings.Contains(out, "<strong>Ana</strong>") {
+		t.Fatalf("unexpected out=%q", out)
+	}
+
+	if err := Execute([]string{"--account", "a@b.com", "gmail", "send", "--dry-run", "--to", "x@example.com", "--subject", "Hi", "--body-md", path, "--body-html", "<b>x</b>"}); err == nil {
+		t.Fatalf("expected --body-md with --body-html to fail")
+	}
+}
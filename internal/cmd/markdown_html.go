@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"html"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Markdown is mapped onto a small HTML subset: <b>, <i>, <a href>, <br> and
+// <ul>/<ol> lists, which is all Google Calendar keeps in event descriptions
+// and renders everywhere Gmail does. Headings become bold lines.
+
+var (
+	mdLinkRe    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldRe    = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdItalicRe  = regexp.MustCompile(`(^|[^*\w])[*_]([^*_\s][^*_]*?)[*_]($|[^*\w])`)
+	mdCodeRe    = regexp.MustCompile("`([^`]+)`")
+	mdHeadingRe = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+	mdBulletRe  = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdNumberRe  = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+)
+
+// readDescriptionMarkdown reads --description-md (a file path or - for stdin).
+func readDescriptionMarkdown(path string) (string, error) {
+	b, err := readFileOrStdin(path)
+	if err != nil {
+		return "", err
+	}
+	return markdownToSimpleHTML(string(b)), nil
+}
+
+func readFileOrStdin(path string) ([]byte, error) {
+	if path == "-" {
+		return readStdin()
+	}
+	return os.ReadFile(path)
+}
+
+func markdownInline(s string) string {
+	s = html.EscapeString(s)
+	s = mdCodeRe.ReplaceAllString(s, "$1")
+	s = mdLinkRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdLinkRe.FindStringSubmatch(m)
+		return `<a href="` + sub[2] + `">` + sub[1] + `</a>`
+	})
+	s = mdBoldRe.ReplaceAllString(s, "<b>$2</b>")
+	s = mdItalicRe.ReplaceAllString(s, "$1<i>$2</i>$3")
+	return s
+}
+
+// markdownToSimpleHTML converts Markdown to the HTML subset above.
+func markdownToSimpleHTML(md string) string {
+	var out []string
+	list := ""
+	closeList := func() {
+		if list != "" {
+			out = append(out, "</"+list+">")
+			list = ""
+		}
+	}
+	openList := func(tag string) {
+		if list != tag {
+			closeList()
+			out = append(out, "<"+tag+">")
+			list = tag
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(md), "\r\n", "\n"), "\n")
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case mdBulletRe.MatchString(line):
+			openList("ul")
+			out = append(out, "<li>"+markdownInline(mdBulletRe.FindStringSubmatch(line)[1])+"</li>")
+		case mdNumberRe.MatchString(line):
+			openList("ol")
+			out = append(out, "<li>"+markdownInline(mdNumberRe.FindStringSubmatch(line)[1])+"</li>")
+		case trimmed == "":
+			closeList()
+			out = append(out, "<br>")
+		case mdHeadingRe.MatchString(trimmed):
+			closeList()
+			out = append(out, "<b>"+markdownInline(mdHeadingRe.FindStringSubmatch(trimmed)[1])+"</b><br>")
+		default:
+			closeList()
+			out = append(out, markdownInline(trimmed)+"<br>")
+		}
+	}
+	closeList()
+	s := strings.Join(out, "")
+	return strings.TrimSuffix(s, "<br>")
+}
+
+var (
+	htmlTagRe    = regexp.MustCompile(`(?is)<(/?)([a-z0-9]+)([^>]*)>`)
+	htmlHrefRe   = regexp.MustCompile(`(?is)href\s*=\s*("([^"]*)"|'([^']*)')`)
+	htmlLooksRe  = regexp.MustCompile(`(?i)<(br|b|i|u|a|p|ul|ol|li|strong|em|span|div)\b`)
+	blankLinesRe = regexp.MustCompile(`\n{3,}`)
+)
+
+// simpleHTMLToMarkdown renders HTML (event descriptions, auto-replies) as
+// Markdown for the terminal; plain text is returned unchanged.
+func simpleHTMLToMarkdown(s string) string {
+	if !htmlLooksRe.MatchString(s) {
+		return s
+	}
+	var b strings.Builder
+	var lists []string
+	var href string
+	var linkText strings.Builder
+	inLink := false
+	write := func(t string) {
+		if inLink {
+			linkText.WriteString(t)
+		} else {
+			b.WriteString(t)
+		}
+	}
+
+	last := 0
+	for _, m := range htmlTagRe.FindAllStringSubmatchIndex(s, -1) {
+		write(html.UnescapeString(s[last:m[0]]))
+		last = m[1]
+		closing := s[m[2]:m[3]] == "/"
+		tag := strings.ToLower(s[m[4]:m[5]])
+		attrs := s[m[6]:m[7]]
+		switch tag {
+		case "br":
+			write("\n")
+		case "p", "div":
+			if closing {
+				write("\n\n")
+			}
+		case "b", "strong":
+			write("**")
+		case "i", "em":
+			write("*")
+		case "ul", "ol":
+			if closing {
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				write("\n")
+			} else {
+				lists = append(lists, tag)
+				write("\n")
+			}
+		case "li":
+			if closing {
+				write("\n")
+			} else {
+				prefix := "- "
+				if len(lists) > 0 && lists[len(lists)-1] == "ol" {
+					prefix = "1. "
+				}
+				write(strings.Repeat("  ", max(len(lists)-1, 0)) + prefix)
+			}
+		case "a":
+			if !closing {
+				href = ""
+				if hm := htmlHrefRe.FindStringSubmatch(attrs); hm != nil {
+					href = html.UnescapeString(hm[2] + hm[3])
+				}
+				inLink = true
+				linkText.Reset()
+			} else if inLink {
+				inLink = false
+				text := linkText.String()
+				switch {
+				case href == "" || href == text:
+					write(text)
+				default:
+					write("[" + text + "](" + href + ")")
+				}
+			}
+		}
+	}
+	write(html.UnescapeString(s[last:]))
+
+	out := blankLinesRe.ReplaceAllString(b.String(), "\n\n")
+	lines := strings.Split(out, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarkdownToCalendarHTML(t *testing.T) {
+	md := "# Agenda\n\n- Review **Q3** numbers\n- Plan *next* steps\n\n1. Intro\n2. See [doc](https://example.com/d?a=1&b=2)\n\nBring `laptop` & notes"
+	got := markdownToSimpleHTML(md)
+	want := `<b>Agenda</b><br><br><ul><li>Review <b>Q3</b> numbers</li><li>Plan <i>next</i> steps</li></ul><br><ol><li>Intro</li><li>See <a href="https://example.com/d?a=1&amp;b=2">doc</a></li></ol><br>Bring laptop &amp; notes`
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestCalendarHTMLToMarkdown(t *testing.T) {
+	in := `<b>Agenda</b><br><ul><li>Review <b>Q3</b></li><li>See <a href="https://example.com/d?a=1&amp;b=2">doc</a></li></ul>Join: <a href="https://meet.example.com/x">https://meet.example.com/x</a> &amp; more`
+	want := "**Agenda**\n\n- Review **Q3**\n- See [doc](https://example.com/d?a=1&b=2)\n\nJoin: https://meet.example.com/x & more"
+	if got := simpleHTMLToMarkdown(in); got != want {
+		t.Fatalf("got  %q\nwant %q", got, want)
+	}
+	if got := simpleHTMLToMarkdown("plain 1 < 2 text"); got != "plain 1 < 2 text" {
+		t.Fatalf("plain text changed: %q", got)
+	}
+}
+
+func TestReadDescriptionMarkdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agenda.md")
+	if err := os.WriteFile(path, []byte("- one\n- two\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readDescriptionMarkdown(path)
+	if err != nil || got != "<ul><li>one</li><li>two</li></ul>" {
+		t.Fatalf("got %q err=%v", got, err)
+	}
+}
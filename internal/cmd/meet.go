@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/docs/v1"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/meet/v2"
+	"google.golang.org/api/tasks/v1"
+)
+
+var newMeetService = googleapi.NewMeet
+
+// Heuristic cues for meet notes. A sentence matching a decision cue is a
+// decision; otherwise one matching an action cue is an action item.
+var (
+	meetDecisionCue = regexp.MustCompile(`(?i)\b(we decided|we've decided|decided to|decision is|decision:|we agreed|agreed to|agreed that|let's go with|we'll go with|going with)\b`)
+	meetActionCue   = regexp.MustCompile(`(?i)\b(action item|todo|to-do|i'll|i will|will follow up|follow up on|can you|could you|needs to|need to .* by)\b`)
+	meetSelfCue     = regexp.MustCompile(`(?i)^(action item:?\s*)?(i'll|i will)\b`)
+	meetLabelPrefix = regexp.MustCompile(`(?i)^(action item|todo|to-do|decision)\s*:\s*`)
+	meetSentenceEnd = regexp.MustCompile(`([.!?])\s+`)
+)
+
+// meetNotes is what meet notes extracts from a conference transcript.
+type meetNotes struct {
+	Conference  string               `json:"conference"`
+	Title       string               `json:"title"`
+	Start       string               `json:"start,omitempty"`
+	Attendees   []string             `json:"attendees"`
+	Decisions   []meetNoteLine       `json:"decisions"`
+	ActionItems []meetNoteLine       `json:"actionItems"`
+	Transcript  []meetTranscriptLine `json:"-"`
+}
+
+type meetNoteLine struct {
+	Text    string `json:"text"`
+	Speaker string `json:"speaker,omitempty"`
+	Owner   string `json:"owner,omitempty"`
+	Time    string `json:"time,omitempty"`
+}
+
+type meetTranscriptLine struct {
+	Speaker string
+	Time    string
+	Text    string
+}
+
+func newMeetCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "meet",
+		Short: "Google Meet conference records and transcripts",
+	}
+	cmd.AddCommand(newMeetNotesCmd(flags))
+	return cmd
+}
+
+func newMeetNotesCmd(flags *rootFlags) *cobra.Command {
+	var templateID, title, parent, tasklist string
+	var createTasks, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "notes <conferenceId>",
+		Short: "Turn a Meet transcript into a notes Doc (and Tasks)",
+		Long: `Read the transcript of a conference record and write a notes Doc with
+attendees, decisions and action items.
+
+With --doc-template the template is copied and these placeholders replaced:
+{{title}}, {{date}}, {{attendees}}, {{decisions}}, {{action_items}},
+{{transcript}}. A template without placeholders (or no template) gets the
+sections appended at the end.
+
+Decisions and action items are picked out by phrases such as "we decided",
+"agreed to", "action item", "I'll", "can you"; review the Doc afterwards.
+"I'll ..." items are owned by the speaker. --tasks creates one Google Task
+per action item in --tasklist.
+
+The conference ID is a conference record (conferenceRecords/<id> or <id>);
+transcription must have been on during the meeting.`,
+		Example: `  gog meet notes abc-123 --doc-template <docId> --tasks
+  gog meet notes conferenceRecords/abc-123 --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			name := meetConferenceName(args[0])
+			if name == "" {
+				return usagef("invalid conferenceId %q", args[0])
+			}
+
+			msvc, err := newMeetService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			notes, err := fetchMeetNotes(cmd.Context(), msvc, name)
+			if err != nil {
+				return err
+			}
+			if t := strings.TrimSpace(title); t != "" {
+				notes.Title = t
+			}
+
+			if dryRun {
+				if outfmt.IsJSON(cmd.Context()) {
+					return outfmt.WriteJSON(os.Stdout, map[string]any{"dryRun": true, "notes": notes})
+				}
+				u.Out().Println(strings.TrimRight(meetNotesText(notes), "\n"))
+				return nil
+			}
+
+			dsvc, err := newDriveService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			docSvc, err := newDocsService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			file, err := createMeetNotesDoc(cmd.Context(), dsvc, notes.Title, strings.TrimSpace(templateID), strings.TrimSpace(parent))
+			if err != nil {
+				return err
+			}
+			if err := fillMeetNotesDoc(cmd.Context(), docSvc, file.Id, notes); err != nil {
+				return fmt.Errorf("fill %s: %w", file.Id, err)
+			}
+
+			var created []*tasks.Task
+			if createTasks && len(notes.ActionItems) > 0 {
+				tsvc, err := newTasksService(cmd.Context(), account)
+				if err != nil {
+					return err
+				}
+				for _, item := range notes.ActionItems {
+					t, err := insertMeetTask(cmd.Context(), tsvc, tasklist, item, notes.Title, file.WebViewLink)
+					if err != nil {
+						return fmt.Errorf("create task %q (after %d created): %w", item.Text, len(created), err)
+					}
+					created = append(created, t)
+				}
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				if created == nil {
+					created = []*tasks.Task{}
+				}
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"documentId": file.Id,
+					"link":       file.WebViewLink,
+					"notes":      notes,
+					"tasks":      created,
+				})
+			}
+			u.Out().Printf("id\t%s", file.Id)
+			u.Out().Printf("title\t%s", notes.Title)
+			if file.WebViewLink != "" {
+				u.Out().Printf("link\t%s", file.WebViewLink)
+			}
+			u.Out().Printf("attendees\t%d", len(notes.Attendees))
+			u.Out().Printf("decisions\t%d", len(notes.Decisions))
+			u.Out().Printf("action_items\t%d", len(notes.ActionItems))
+			if createTasks {
+				u.Out().Printf("tasks\t%d", len(created))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&templateID, "doc-template", "", "Doc to copy as the notes template (default: a new blank Doc)")
+	cmd.Flags().StringVar(&title, "title", "", "Notes Doc title (default: Meeting notes <date>)")
+	cmd.Flags().StringVar(&parent, "parent", "", "Destination folder ID")
+	cmd.Flags().BoolVar(&createTasks, "tasks", false, "Create a Google Task for each action item")
+	cmd.Flags().StringVar(&tasklist, "tasklist", "@default", "Task list for --tasks")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the extracted notes without creating a Doc or Tasks")
+	return cmd
+}
+
+// meetConferenceName accepts conferenceRecords/<id> or a bare <id>.
+func meetConferenceName(raw string) string {
+	id := strings.TrimPrefix(strings.TrimSpace(raw), "conferenceRecords/")
+	if id == "" || strings.Contains(id, "/") {
+		return ""
+	}
+	return "conferenceRecords/" + id
+}
+
+func fetchMeetNotes(ctx context.Context, svc *meet.Service, name string) (*meetNotes, error) {
+	rec, err := svc.ConferenceRecords.Get(name).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	notes := &meetNotes{Conference: rec.Name, Start: rec.StartTime, Title: "Meeting notes"}
+	if t, err := time.Parse(time.RFC3339, rec.StartTime); err == nil {
+		notes.Title += " " + t.Local().Format("2006-01-02")
+	}
+
+	speakers := map[string]string{}
+	err = svc.ConferenceRecords.Participants.List(name).PageSize(250).Pages(ctx, func(resp *meet.ListParticipantsResponse) error {
+		for _, p := range resp.Participants {
+			n := meetParticipantName(p)
+			speakers[p.Name] = n
+			notes.Attendees = append(notes.Attendees, n)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("participants: %w", err)
+	}
+	sort.Strings(notes.Attendees)
+	notes.Attendees = dedupeStrings(notes.Attendees)
+
+	var transcripts []*meet.Transcript
+	err = svc.ConferenceRecords.Transcripts.List(name).Pages(ctx, func(resp *meet.ListTranscriptsResponse) error {
+		transcripts = append(transcripts, resp.Transcripts...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("transcripts: %w", err)
+	}
+	if len(transcripts) == 0 {
+		return nil, fmt.Errorf("no transcript for %s (was transcription on?)", name)
+	}
+	var entries []*meet.TranscriptEntry
+	for _, t := range transcripts {
+		err := svc.ConferenceRecords.Transcripts.Entries.List(t.Name).PageSize(100).Pages(ctx, func(resp *meet.ListTranscriptEntriesResponse) error {
+			entries = append(entries, resp.TranscriptEntries...)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.Name, err)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].StartTime < entries[j].StartTime })
+
+	for _, e := range entries {
+		text := strings.TrimSpace(e.Text)
+		if text == "" {
+			continue
+		}
+		speaker := speakers[e.Participant]
+		if speaker == "" {
+			speaker = "Unknown"
+		}
+		notes.Transcript = append(notes.Transcript, meetTranscriptLine{Speaker: speaker, Time: e.StartTime, Text: text})
+	}
+	notes.Decisions, notes.ActionItems = extractMeetNotes(notes.Transcript)
+	return notes, nil
+}
+
+func meetParticipantName(p *meet.Participant) string {
+	switch {
+	case p.SignedinUser != nil && p.SignedinUser.DisplayName != "":
+		return p.SignedinUser.DisplayName
+	case p.AnonymousUser != nil && p.AnonymousUser.DisplayName != "":
+		return p.AnonymousUser.DisplayName
+	case p.PhoneUser != nil && p.PhoneUser.DisplayName != "":
+		return p.PhoneUser.DisplayName
+	}
+	return "Unknown"
+}
+
+func dedupeStrings(sorted []string) []string {
+	out := sorted[:0]
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// extractMeetNotes splits transcript lines into sentences and keeps those
+// that read like decisions or action items.
+func extractMeetNotes(lines []meetTranscriptLine) (decisions, actions []meetNoteLine) {
+	decisions, actions = []meetNoteLine{}, []meetNoteLine{}
+	for _, l := range lines {
+		for _, s := range splitSentences(l.Text) {
+			item := meetNoteLine{Text: meetLabelPrefix.ReplaceAllString(s, ""), Speaker: l.Speaker, Time: l.Time}
+			switch {
+			case meetDecisionCue.MatchString(s):
+				decisions = append(decisions, item)
+			case meetActionCue.MatchString(s):
+				if meetSelfCue.MatchString(s) {
+					item.Owner = l.Speaker
+				}
+				actions = append(actions, item)
+			}
+		}
+	}
+	return decisions, actions
+}
+
+func splitSentences(text string) []string {
+	var out []string
+	for _, s := range strings.Split(meetSentenceEnd.ReplaceAllString(text, "$1\n"), "\n") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func meetNotesSections(n *meetNotes) map[string]string {
+	date := n.Start
+	if t, err := time.Parse(time.RFC3339, n.Start); err == nil {
+		date = t.Local().Format("2006-01-02 15:04")
+	}
+	list := func(items []string) string {
+		if len(items) == 0 {
+			return "(none)"
+		}
+		return "• " + strings.Join(items, "\n• ")
+	}
+	var decisions, actions, transcript []string
+	for _, d := range n.Decisions {
+		decisions = append(decisions, fmt.Sprintf("%s (%s)", d.Text, d.Speaker))
+	}
+	for _, a := range n.ActionItems {
+		if a.Owner != "" {
+			actions = append(actions, fmt.Sprintf("%s: %s", a.Owner, a.Text))
+		} else {
+			actions = append(actions, fmt.Sprintf("%s (raised by %s)", a.Text, a.Speaker))
+		}
+	}
+	for _, l := range n.Transcript {
+		transcript = append(transcript, l.Speaker+": "+l.Text)
+	}
+	return map[string]string{
+		"title":        n.Title,
+		"date":         date,
+		"attendees":    list(n.Attendees),
+		"decisions":    list(decisions),
+		"action_items": list(actions),
+		"transcript":   strings.Join(transcript, "\n"),
+	}
+}
+
+// meetNotesText is the section layout used when there is no template.
+func meetNotesText(n *meetNotes) string {
+	s := meetNotesSections(n)
+	return fmt.Sprintf("%s\n%s\n\nAttendees\n%s\n\nDecisions\n%s\n\nAction items\n%s\n",
+		s["title"], s["date"], s["attendees"], s["decisions"], s["action_items"])
+}
+
+func createMeetNotesDoc(ctx context.Context, svc *drive.Service, title, templateID, parent string) (*drive.File, error) {
+	f := &drive.File{Name: title}
+	if parent != "" {
+		f.Parents = []string{parent}
+	}
+	const fields = "id, name, webViewLink"
+	if templateID == "" {
+		f.MimeType = "application/vnd.google-apps.document"
+		return svc.Files.Create(f).SupportsAllDrives(true).Fields(fields).Context(ctx).Do()
+	}
+	meta, err := svc.Files.Get(templateID).SupportsAllDrives(true).Fields("id, mimeType").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if meta.MimeType != "application/vnd.google-apps.document" {
+		return nil, fmt.Errorf("template is not a Google Doc (mimeType=%q)", meta.MimeType)
+	}
+	return svc.Files.Copy(templateID, f).SupportsAllDrives(true).Fields(fields).Context(ctx).Do()
+}
+
+// fillMeetNotesDoc replaces the template placeholders, or appends the
+// sections when the document has none.
+func fillMeetNotesDoc(ctx context.Context, svc *docs.Service, id string, n *meetNotes) error {
+	sections := meetNotesSections(n)
+	keys := make([]string, 0, len(sections))
+	for k := range sections {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	reqs := make([]*docs.Request, 0, len(keys))
+	for _, k := range keys {
+		reqs = append(reqs, &docs.Request{ReplaceAllText: &docs.ReplaceAllTextRequest{
+			ContainsText: &docs.SubstringMatchCriteria{Text: "{{" + k + "}}", MatchCase: true},
+			ReplaceText:  sections[k],
+		}})
+	}
+	resp, err := svc.Documents.BatchUpdate(id, &docs.BatchUpdateDocumentRequest{Requests: reqs}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return errors.New("update failed")
+	}
+	for _, r := range resp.Replies {
+		if r != nil && r.ReplaceAllText != nil && r.ReplaceAllText.OccurrencesChanged > 0 {
+			return nil
+		}
+	}
+	_, err = svc.Documents.BatchUpdate(id, &docs.BatchUpdateDocumentRequest{
+		Requests: []*docs.Request{{
+			InsertText: &docs.InsertTextRequest{
+				Text:                 meetNotesText(n),
+				EndOfSegmentLocation: &docs.EndOfSegmentLocation{},
+			},
+		}},
+	}).Context(ctx).Do()
+	return err
+}
+
+func insertMeetTask(ctx context.Context, svc *tasks.Service, tasklist string, item meetNoteLine, title, link string) (*tasks.Task, error) {
+	notes := fmt.Sprintf("From %s (raised by %s)", title, item.Speaker)
+	if item.Owner != "" {
+		notes = fmt.Sprintf("From %s (owner: %s)", title, item.Owner)
+	}
+	if link != "" {
+		notes += "\n" + link
+	}
+	return svc.Tasks.Insert(tasklist, &tasks.Task{Title: item.Text, Notes: notes}).Context(ctx).Do()
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/docs/v1"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/meet/v2"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tasks/v1"
+)
+
+func TestExtractMeetNotes(t *testing.T) {
+	decisions, actions := extractMeetNotes([]meetTranscriptLine{
+		{Speaker: "Ada", Text: "Thanks for joining. We decided to ship on Friday. I'll update the changelog."},
+		{Speaker: "Bob", Text: "Action item: Ada can you email the customers? Sounds good!"},
+		{Speaker: "Cy", Text: "Nothing from me."},
+	})
+	if len(decisions) != 1 || decisions[0].Text != "We decided to ship on Friday." || decisions[0].Speaker != "Ada" {
+		t.Fatalf("decisions = %+v", decisions)
+	}
+	if len(actions) != 2 {
+		t.Fatalf("actions = %+v", actions)
+	}
+	if actions[0].Owner != "Ada" || actions[0].Text != "I'll update the changelog." {
+		t.Fatalf("first action = %+v", actions[0])
+	}
+	if actions[1].Owner != "" || actions[1].Speaker != "Bob" || actions[1].Text != "Ada can you email the customers?" {
+		t.Fatalf("second action = %+v", actions[1])
+	}
+}
+
+func TestMeetConferenceName(t *testing.T) {
+	for in, want := range map[string]string{
+		"abc":                   "conferenceRecords/abc",
+		" conferenceRecords/x ": "conferenceRecords/x",
+		"spaces/abc":            "",
+		"":                      "",
+	} {
+		if got := meetConferenceName(in); got != want {
+			t.Fatalf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestExecute_MeetNotes_TemplateAndTasks(t *testing.T) {
+	origMeet, origDrive, origDocs, origTasks := newMeetService, newDriveService, newDocsService, newTasksService
+	t.Cleanup(func() {
+		newMeetService, newDriveService, newDocsService, newTasksService = origMeet, origDrive, origDocs, origTasks
+	})
+
+	var copyReq drive.File
+	var batch docs.BatchUpdateDocumentRequest
+	var taskTitles []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		switch {
+		case path == "/v2/conferenceRecords/c1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "conferenceRecords/c1", "startTime": "2026-03-02T15:00:00Z"})
+		case path == "/v2/conferenceRecords/c1/participants":
+			_ = json.NewEncoder(w).Encode(map[string]any{"participants": []map[string]any{
+				{"name": "conferenceRecords/c1/participants/p1", "signedinUser": map[string]any{"displayName": "Ada"}},
+				{"name": "conferenceRecords/c1/participants/p2", "phoneUser": map[string]any{"displayName": "Bob"}},
+			}})
+		case path == "/v2/conferenceRecords/c1/transcripts":
+			_ = json.NewEncoder(w).Encode(map[string]any{"transcripts": []map[string]any{{"name": "conferenceRecords/c1/transcripts/t1"}}})
+		case path == "/v2/conferenceRecords/c1/transcripts/t1/entries":
+			_ = json.NewEncoder(w).Encode(map[string]any{"transcriptEntries": []map[string]any{
+				{"participant": "conferenceRecords/c1/participants/p2", "startTime": "2026-03-02T15:01:00Z", "text": "Can you send the draft by Monday?"},
+				{"participant": "conferenceRecords/c1/participants/p1", "startTime": "2026-03-02T15:00:10Z", "text": "We agreed to drop the beta."},
+			}})
+		case path == "/files/tmpl" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "tmpl", "mimeType": "application/vnd.google-apps.document"})
+		case path == "/files/tmpl/copy" && r.Method == http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&copyReq)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "doc1", "name": copyReq.Name, "webViewLink": "https://docs.google.com/d/doc1"})
+		case path == "/v1/documents/doc1:batchUpdate":
+			_ = json.NewDecoder(r.Body).Decode(&batch)
+			_ = json.NewEncoder(w).Encode(map[string]any{"documentId": "doc1", "replies": []map[string]any{
+				{"replaceAllText": map[string]any{"occurrencesChanged": 1}},
+			}})
+		case path == "/tasks/v1/lists/@default/tasks" && r.Method == http.MethodPost:
+			var task tasks.Task
+			_ = json.NewDecoder(r.Body).Decode(&task)
+			taskTitles = append(taskTitles, task.Title)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "task1", "title": task.Title})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	opts := []option.ClientOption{option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL + "/")}
+	msvc, err := meet.NewService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("meet: %v", err)
+	}
+	dsvc, err := drive.NewService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("drive: %v", err)
+	}
+	docSvc, err := docs.NewService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("docs: %v", err)
+	}
+	tsvc, err := tasks.NewService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("tasks: %v", err)
+	}
+	newMeetService = func(context.Context, string) (*meet.Service, error) { return msvc, nil }
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return dsvc, nil }
+	newDocsService = func(context.Context, string) (*docs.Service, error) { return docSvc, nil }
+	newTasksService = func(context.Context, string) (*tasks.Service, error) { return tsvc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "meet", "notes", "c1", "--doc-template", "tmpl", "--title", "Weekly sync", "--tasks"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	if copyReq.Name != "Weekly sync" {
+		t.Fatalf("copy name = %q", copyReq.Name)
+	}
+	replaced := map[string]string{}
+	for _, req := range batch.Requests {
+		if req.ReplaceAllText != nil {
+			replaced[req.ReplaceAllText.ContainsText.Text] = req.ReplaceAllText.ReplaceText
+		}
+	}
+	if replaced["{{attendees}}"] != "• Ada\n• Bob" {
+		t.Fatalf("attendees = %q", replaced["{{attendees}}"])
+	}
+	if replaced["{{decisions}}"] != "• We agreed to drop the beta. (Ada)" {
+		t.Fatalf("decisions = %q", replaced["{{decisions}}"])
+	}
+	if !strings.HasPrefix(replaced["{{transcript}}"], "Ada: We agreed") {
+		t.Fatalf("transcript = %q", replaced["{{transcript}}"])
+	}
+	if len(taskTitles) != 1 || taskTitles[0] != "Can you send the draft by Monday?" {
+		t.Fatalf("tasks = %v", taskTitles)
+	}
+
+	var got struct {
+		DocumentID string `json:"documentId"`
+		Notes      struct {
+			ActionItems []meetNoteLine `json:"actionItems"`
+		} `json:"notes"`
+		Tasks []map[string]any `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if got.DocumentID != "doc1" || len(got.Notes.ActionItems) != 1 || len(got.Tasks) != 1 {
+		t.Fatalf("out = %s", out)
+	}
+}
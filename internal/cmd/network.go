@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+// allowInsecureEnv must be set to 1 before --insecure-skip-verify is
+// accepted, so a copied command line cannot silently turn off TLS checks.
+const allowInsecureEnv = "GOG_ALLOW_INSECURE"
+
+// applyNetworkFlags sets the proxy, CA bundle and TLS verification options
+// from --proxy, --ca-bundle and --insecure-skip-verify. Without --proxy the
+// HTTPS_PROXY/HTTP_PROXY/NO_PROXY environment applies.
+func applyNetworkFlags(flags *rootFlags, opts *googleapi.TransportOptions) error {
+	if raw := strings.TrimSpace(flags.Proxy); raw != "" {
+		proxy, err := parseProxyURL(raw)
+		if err != nil {
+			return err
+		}
+		opts.Proxy = proxy
+	}
+	if path := strings.TrimSpace(flags.CABundle); path != "" {
+		bundle, err := googleapi.LoadCABundle(path)
+		if err != nil {
+			return usage(err.Error())
+		}
+		opts.CABundle = bundle
+	}
+	if flags.InsecureSkipVerify {
+		if strings.TrimSpace(os.Getenv(allowInsecureEnv)) != "1" {
+			return usagef("--insecure-skip-verify requires %s=1", allowInsecureEnv)
+		}
+		slog.Warn("TLS certificate verification is disabled (--insecure-skip-verify)")
+		opts.InsecureSkipVerify = true
+	}
+	return nil
+}
+
+// parseProxyURL accepts http, https and socks5 proxy URLs; a bare host:port
+// means an HTTP proxy.
+func parseProxyURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, usagef("invalid --proxy %q (expected e.g. http://proxy:3128)", raw)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "socks5", "socks5h":
+		return u, nil
+	default:
+		return nil, usagef("invalid --proxy scheme %q (use http, https or socks5)", u.Scheme)
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestParseProxyURL(t *testing.T) {
+	for raw, want := range map[string]string{
+		"proxy.corp:3128":          "http://proxy.corp:3128",
+		"https://proxy.corp:443":   "https://proxy.corp:443",
+		"socks5://127.0.0.1:1080":  "socks5://127.0.0.1:1080",
+		"http://u:p@proxy.corp:80": "http://u:p@proxy.corp:80",
+	} {
+		u, err := parseProxyURL(raw)
+		if err != nil || u.String() != want {
+			t.Fatalf("%q: got %v, %v", raw, u, err)
+		}
+	}
+	for _, bad := range []string{"ftp://proxy:21", "http://"} {
+		if _, err := parseProxyURL(bad); err == nil {
+			t.Fatalf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestApplyNetworkFlags_InsecureNeedsEnv(t *testing.T) {
+	t.Setenv(allowInsecureEnv, "")
+	var opts googleapi.TransportOptions
+	err := applyNetworkFlags(&rootFlags{InsecureSkipVerify: true}, &opts)
+	if err == nil || !strings.Contains(err.Error(), allowInsecureEnv) {
+		t.Fatalf("expected %s error, got %v", allowInsecureEnv, err)
+	}
+	t.Setenv(allowInsecureEnv, "1")
+	if err := applyNetworkFlags(&rootFlags{InsecureSkipVerify: true, Proxy: "proxy:8080"}, &opts); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !opts.InsecureSkipVerify || opts.Proxy == nil || opts.Proxy.Host != "proxy:8080" {
+		t.Fatalf("opts = %+v", opts)
+	}
+}
@@ -11,6 +11,10 @@ import (
 )
 
 func tableWriter(ctx context.Context) (io.Writer, func()) {
+	if format := outfmt.TableFormat(ctx); format != "" {
+		dw := outfmt.NewDelimitedWriter(os.Stdout, format)
+		return dw, func() { _ = dw.Flush() }
+	}
 	if outfmt.IsPlain(ctx) {
 		return os.Stdout, func() {}
 	}
@@ -24,3 +28,46 @@ func printNextPageHint(u *ui.UI, nextPageToken string) {
 	}
 	u.Err().Printf("# Next page: --page %s", nextPageToken)
 }
+
+// streamNDJSONPages follows page tokens starting at pageToken and writes each
+// item as one JSON line as soon as its page arrives. max caps the total number
+// of items (<= 0: no cap); pageMax is the largest page size the API accepts.
+func streamNDJSONPages[T any](ctx context.Context, pageToken string, max, pageMax int64, fetch func(pageToken string, pageSize int64) ([]T, string, error)) error {
+	var written int64
+	for {
+		size := pageMax
+		if max > 0 {
+			size = min(pageMax, max-written)
+		}
+		items, next, err := fetch(pageToken, size)
+		if err != nil {
+			return err
+		}
+		for _, it := range items {
+			if err := outfmt.WriteNDJSON(os.Stdout, it); err != nil {
+				return err
+			}
+			written++
+		}
+		if next == "" {
+			return nil
+		}
+		if max > 0 && written >= max {
+			printNextPageHint(ui.FromContext(ctx), next)
+			return nil
+		}
+		pageToken = next
+	}
+}
+
+// writeNDJSONRows writes one page of rows as JSON lines; the next page token
+// goes to stderr like the table hint.
+func writeNDJSONRows[T any](ctx context.Context, rows []T, nextPageToken string) error {
+	for _, r := range rows {
+		if err := outfmt.WriteNDJSON(os.Stdout, r); err != nil {
+			return err
+		}
+	}
+	printNextPageHint(ui.FromContext(ctx), nextPageToken)
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const defaultPageLimit = 1000
+
+// pageFlags adds --all/--limit to list commands that take --max/--page, so
+// the CLI follows nextPageToken instead of scripts looping over --page.
+// Pages are fetched one after another through the same client, so the
+// retry transport's backoff applies to rate-limited pages too.
+type pageFlags struct {
+	All   bool
+	Limit int64
+}
+
+func (p *pageFlags) addFlags(cmd *cobra.Command) {
+	p.addFlagsNamed(cmd, "all")
+}
+
+// addFlagsNamed is addFlags for commands where --all already means something
+// else (calendar events uses --all-pages).
+func (p *pageFlags) addFlagsNamed(cmd *cobra.Command, allName string) {
+	cmd.Flags().BoolVar(&p.All, allName, false, "Fetch every page (follows nextPageToken, up to --limit results)")
+	cmd.Flags().Int64Var(&p.Limit, "limit", defaultPageLimit, "Stop --"+allName+" after the page that reaches this many results (0: no cap)")
+}
+
+// ndjsonCap is the total number of results an NDJSON stream may write:
+// --limit with --all, otherwise --max.
+func (p pageFlags) ndjsonCap(max int64) int64 {
+	if p.All {
+		return p.Limit
+	}
+	return max
+}
+
+// fetchPages fetches the page at pageToken or, with --all, every page from
+// there until the results run out or --limit is reached. --limit only stops
+// between pages (the last page is kept whole, so up to a page more than
+// --limit is returned): page tokens cannot resume in the middle of a page.
+// The returned token resumes the listing ("" once exhausted). When a later
+// page fails (e.g. the
+// --max-api-calls budget runs out), the results fetched so far are returned
+// with the error and the token of the failed page.
+func fetchPages[T any](ctx context.Context, p pageFlags, pageToken string, fetch func(pageToken string) ([]T, string, error)) ([]T, string, error) {
+	items, next, err := fetch(pageToken)
+	if err != nil || !p.All {
+		return items, next, err
+	}
+	for next != "" && (p.Limit <= 0 || int64(len(items)) < p.Limit) {
+		pageToken = next
+		var page []T
+		page, next, err = fetch(pageToken)
+		if err != nil {
+			return items, pageToken, err
+		}
+		items = append(items, page...)
+	}
+	if next != "" && p.Limit > 0 && int64(len(items)) >= p.Limit {
+		if u := ui.FromContext(ctx); u != nil {
+			u.Err().Printf("WARN: stopped at --limit %d results; more are available", p.Limit)
+		}
+	}
+	return items, next, nil
+}
+
+// splitPageError sorts a fetchPages error: without results it is returned as
+// err and the command fails as before; after some pages it is returned as
+// partial, which the command returns once it printed the results (and the
+// nextPageToken that resumes at the failed page).
+func splitPageError(ctx context.Context, err error, fetched int) (partial, fatal error) {
+	if err == nil {
+		return nil, nil
+	}
+	if fetched == 0 {
+		return nil, err
+	}
+	if u := ui.FromContext(ctx); u != nil {
+		u.Err().Printf("WARN: listing stopped after %d results: %v", fetched, err)
+	}
+	return err, nil
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tasks/v1"
+)
+
+func testPages() func(string) ([]int, string, error) {
+	data := map[string]struct {
+		items []int
+		next  string
+	}{
+		"":   {[]int{1, 2}, "p2"},
+		"p2": {[]int{3, 4}, "p3"},
+		"p3": {[]int{5}, ""},
+	}
+	return func(token string) ([]int, string, error) {
+		page, ok := data[token]
+		if !ok {
+			return nil, "", errors.New("bad token " + token)
+		}
+		return page.items, page.next, nil
+	}
+}
+
+func TestFetchPages(t *testing.T) {
+	ctx := context.Background()
+
+	items, next, err := fetchPages(ctx, pageFlags{}, "", testPages())
+	if err != nil || !reflect.DeepEqual(items, []int{1, 2}) || next != "p2" {
+		t.Fatalf("single page: %v %q %v", items, next, err)
+	}
+
+	items, next, err = fetchPages(ctx, pageFlags{All: true}, "p2", testPages())
+	if err != nil || !reflect.DeepEqual(items, []int{3, 4, 5}) || next != "" {
+		t.Fatalf("all from p2: %v %q %v", items, next, err)
+	}
+
+	// --limit stops after the page that reaches it; that page is kept whole
+	// so the token resumes without skipping or repeating rows.
+	items, next, err = fetchPages(ctx, pageFlags{All: true, Limit: 3}, "", testPages())
+	if err != nil || !reflect.DeepEqual(items, []int{1, 2, 3, 4}) || next != "p3" {
+		t.Fatalf("limit 3: %v %q %v", items, next, err)
+	}
+
+	items, next, err = fetchPages(ctx, pageFlags{All: true, Limit: 4}, "", testPages())
+	if err != nil || !reflect.DeepEqual(items, []int{1, 2, 3, 4}) || next != "p3" {
+		t.Fatalf("limit 4: %v %q %v", items, next, err)
+	}
+
+	// Even when the first page overshoots, its next token is kept.
+	items, next, err = fetchPages(ctx, pageFlags{All: true, Limit: 1}, "", testPages())
+	if err != nil || !reflect.DeepEqual(items, []int{1, 2}) || next != "p2" {
+		t.Fatalf("limit 1: %v %q %v", items, next, err)
+	}
+
+	if _, _, err := fetchPages(ctx, pageFlags{All: true}, "nope", testPages()); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	// A failing later page keeps the earlier results and resumes there.
+	fail := errors.New("page 2 failed")
+	items, next, err = fetchPages(ctx, pageFlags{All: true}, "", func(token string) ([]int, string, error) {
+		if token == "p2" {
+			return nil, "", fail
+		}
+		return testPages()(token)
+	})
+	if !errors.Is(err, fail) || !reflect.DeepEqual(items, []int{1, 2}) || next != "p2" {
+		t.Fatalf("failed page 2: %v %q %v", items, next, err)
+	}
+}
+
+func TestExecute_TasksLists_BudgetPartial(t *testing.T) {
+	origNew := newTasksService
+	t.Cleanup(func() { newTasksService = origNew })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"items":         []map[string]any{{"id": "l1", "title": "One"}},
+			"nextPageToken": "p2",
+		})
+	}))
+	defer srv.Close()
+
+	// The budget allows the first page only.
+	client := &http.Client{Transport: &googleapi.BudgetTransport{Base: srv.Client().Transport, Budget: googleapi.NewRequestBudget(1)}}
+	svc, err := tasks.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(client),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newTasksService = func(context.Context, string) (*tasks.Service, error) { return svc, nil }
+
+	var runErr error
+	errOut := captureStderr(t, func() {
+		out := captureStdout(t, func() {
+			runErr = Execute([]string{"--json", "--account", "a@b.com", "tasks", "lists", "--max", "1", "--all"})
+		})
+		var parsed struct {
+			Tasklists     []tasks.TaskList `json:"tasklists"`
+			NextPageToken string           `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+			t.Fatalf("json: %v out=%q", err, out)
+		}
+		if len(parsed.Tasklists) != 1 || parsed.Tasklists[0].Id != "l1" || parsed.NextPageToken != "p2" {
+			t.Fatalf("unexpected partial out=%q", out)
+		}
+	})
+	if !googleapi.IsBudgetExceededError(runErr) {
+		t.Fatalf("expected budget error, got %v", runErr)
+	}
+	if !strings.Contains(errOut, "listing stopped after 1 results") {
+		t.Fatalf("expected partial warning, got %q", errOut)
+	}
+}
+
+func TestExecute_TasksLists_All(t *testing.T) {
+	origNew := newTasksService
+	t.Cleanup(func() { newTasksService = origNew })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("pageToken") == "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"items":         []map[string]any{{"id": "l1", "title": "One"}},
+				"nextPageToken": "p2",
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"items": []map[string]any{{"id": "l2", "title": "Two"}},
+		})
+	}))
+	defer srv.Close()
+
+	svc, err := tasks.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newTasksService = func(context.Context, string) (*tasks.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "tasks", "lists", "--max", "1", "--all"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	var parsed struct {
+		Tasklists     []tasks.TaskList `json:"tasklists"`
+		NextPageToken string           `json:"nextPageToken"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v out=%q", err, out)
+	}
+	if len(parsed.Tasklists) != 2 || parsed.Tasklists[1].Id != "l2" || parsed.NextPageToken != "" {
+		t.Fatalf("unexpected out=%q", out)
+	}
+
+	errOut := captureStderr(t, func() {
+		_ = captureStdout(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "tasks", "lists", "--max", "1", "--all", "--limit", "1"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(errOut, "stopped at --limit 1") || !strings.Contains(errOut, "--page p2") {
+		t.Fatalf("expected limit warning and next page hint, got %q", errOut)
+	}
+}
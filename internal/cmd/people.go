@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/outfmt"
@@ -14,6 +18,7 @@ func newPeopleCmd(flags *rootFlags) *cobra.Command {
 		Short: "Google People",
 	}
 	cmd.AddCommand(newPeopleMeCmd(flags))
+	cmd.AddCommand(newPeoplePhotoCmd(flags))
 	return cmd
 }
 
@@ -71,3 +76,91 @@ func newPeopleMeCmd(flags *rootFlags) *cobra.Command {
 		},
 	}
 }
+
+func newPeoplePhotoCmd(flags *rootFlags) *cobra.Command {
+	var outDir string
+	var refresh bool
+
+	cmd := &cobra.Command{
+		Use:   "photo <email>...",
+		Short: "Fetch and cache contact photos by email",
+		Long: `Looks up each address in your contacts, then other contacts, and caches
+the photo (or the fact that there is none) for a week. --out DIR also
+writes each photo to DIR. Thread HTML exports reuse the same cache.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			cache, err := newAvatarCache(account)
+			if err != nil {
+				return err
+			}
+			cache.refresh = refresh
+
+			type item struct {
+				Email       string `json:"email"`
+				Found       bool   `json:"found"`
+				ContentType string `json:"contentType,omitempty"`
+				Size        int    `json:"size,omitempty"`
+				Path        string `json:"path,omitempty"`
+			}
+			items := make([]item, 0, len(args))
+			for _, email := range args {
+				email = strings.ToLower(strings.TrimSpace(email))
+				data, err := cache.get(cmd.Context(), email)
+				if err != nil {
+					return fmt.Errorf("%s: %w", email, err)
+				}
+				it := item{Email: email, Found: data != nil}
+				if data != nil {
+					it.ContentType = http.DetectContentType(data)
+					it.Size = len(data)
+					if outDir != "" {
+						it.Path = filepath.Join(outDir, avatarFileName(email, it.ContentType))
+						if err := os.WriteFile(it.Path, data, 0o600); err != nil {
+							return err
+						}
+					}
+				}
+				items = append(items, it)
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"photos": items})
+			}
+			for _, it := range items {
+				switch {
+				case !it.Found:
+					u.Out().Printf("%s\tnone", it.Email)
+				case it.Path != "":
+					u.Out().Printf("%s\t%s", it.Email, it.Path)
+				default:
+					u.Out().Printf("%s\t%s\t%d bytes", it.Email, it.ContentType, it.Size)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&outDir, "out", "", "Also write each photo to this directory")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Ignore cached photos and look them up again")
+	return cmd
+}
+
+// avatarFileName names an exported photo after its address.
+func avatarFileName(email, contentType string) string {
+	ext := ".jpg"
+	switch contentType {
+	case "image/png":
+		ext = ".png"
+	case "image/gif":
+		ext = ".gif"
+	case "image/webp":
+		ext = ".webp"
+	}
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(email)
+	return name + ext
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+// policyFileEnv points at a policy file other than <config>/policy.yaml.
+const policyFileEnv = "GOG_POLICY_FILE"
+
+// errPolicyDenied marks commands blocked by policy.yaml.
+var errPolicyDenied = errors.New("denied by policy")
+
+// policyFile is policy.yaml: rules for every account plus per-account
+// sections. An account section adds to the default deny/confirm lists and
+// replaces any gmail setting it sets.
+type policyFile struct {
+	Default  policyRules            `yaml:"default"`
+	Accounts map[string]policyRules `yaml:"accounts"`
+}
+
+type policyRules struct {
+	// Deny and Confirm hold command paths ("gmail send", "drive"); a path
+	// covers its subcommands and "*" covers everything.
+	Deny    []string         `yaml:"deny"`
+	Confirm []string         `yaml:"confirm"`
+	Gmail   gmailPolicyRules `yaml:"gmail"`
+}
+
+type gmailPolicyRules struct {
+	// AllowRecipients uses the gmail-allowlist.txt syntax (addresses,
+	// @domain, *.domain).
+	AllowRecipients   []string `yaml:"allowRecipients"`
+	MaxAttachmentSize string   `yaml:"maxAttachmentSize"`
+	SendsPerDay       int      `yaml:"sendsPerDay"`
+	RequireArm        bool     `yaml:"requireArm"`
+}
+
+// commandPolicy is the policy in effect for one account.
+type commandPolicy struct {
+	source             string
+	deny               []string
+	confirm            []string
+	allowRecipients    *gmailAllowlist
+	maxAttachmentBytes int64
+	sendsPerDay        int
+	requireArm         bool
+}
+
+// loadPolicy returns the policy for account, or nil without a policy file.
+func loadPolicy(account string) (*commandPolicy, error) {
+	path := strings.TrimSpace(os.Getenv(policyFileEnv))
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = config.PolicyPath(); err != nil {
+			return nil, err
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !explicit {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read policy: %w", err)
+	}
+	var f policyFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	rules := f.Default
+	for name, r := range f.Accounts {
+		if account == "" || !strings.EqualFold(strings.TrimSpace(name), account) {
+			continue
+		}
+		rules.Deny = append(append([]string{}, rules.Deny...), r.Deny...)
+		rules.Confirm = append(append([]string{}, rules.Confirm...), r.Confirm...)
+		if r.Gmail.AllowRecipients != nil {
+			rules.Gmail.AllowRecipients = r.Gmail.AllowRecipients
+		}
+		if r.Gmail.MaxAttachmentSize != "" {
+			rules.Gmail.MaxAttachmentSize = r.Gmail.MaxAttachmentSize
+		}
+		if r.Gmail.SendsPerDay != 0 {
+			rules.Gmail.SendsPerDay = r.Gmail.SendsPerDay
+		}
+		if r.Gmail.RequireArm {
+			rules.Gmail.RequireArm = true
+		}
+	}
+
+	p := &commandPolicy{
+		source:      path,
+		deny:        normalizeCommandRules(rules.Deny),
+		confirm:     normalizeCommandRules(rules.Confirm),
+		sendsPerDay: rules.Gmail.SendsPerDay,
+		requireArm:  rules.Gmail.RequireArm,
+	}
+	if rules.Gmail.AllowRecipients != nil {
+		p.allowRecipients = parseAllowlistEntries(rules.Gmail.AllowRecipients)
+	}
+	if p.maxAttachmentBytes, err = parseByteSize(rules.Gmail.MaxAttachmentSize); err != nil {
+		return nil, fmt.Errorf("%s: gmail.maxAttachmentSize: %w", path, err)
+	}
+	if p.sendsPerDay < 0 {
+		return nil, fmt.Errorf("%s: gmail.sendsPerDay must be >= 0", path)
+	}
+	return p, nil
+}
+
+func normalizeCommandRules(rules []string) []string {
+	out := make([]string, 0, len(rules))
+	for _, r := range rules {
+		if r = strings.ToLower(strings.Join(strings.Fields(r), " ")); r != "" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// matchCommandRule returns the first rule covering path ("gmail filters
+// delete" is covered by "gmail filters delete", "gmail filters", "gmail"
+// and "*").
+func matchCommandRule(rules []string, path string) string {
+	path = strings.ToLower(path)
+	for _, r := range rules {
+		if r == "*" || path == r || strings.HasPrefix(path, r+" ") {
+			return r
+		}
+	}
+	return ""
+}
+
+// policyAccount is the account a command will use, without failing when
+// none is set.
+func policyAccount(flags *rootFlags) string {
+	account, _ := requireAccount(flags)
+	return account
+}
+
+// enforceCommandPolicy applies the deny and confirm rules to cmd. Policy
+// confirmations always need an interactive answer: --yes/--force do not
+// skip them, so an unattended caller cannot approve its own commands.
+func enforceCommandPolicy(cmd *cobra.Command, flags *rootFlags) error {
+	account := policyAccount(flags)
+	p, err := loadPolicy(account)
+	if err != nil || p == nil {
+		return err
+	}
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if rule := matchCommandRule(p.deny, path); rule != "" {
+		return fmt.Errorf("%s: %w (rule %q in %s)", path, errPolicyDenied, rule, p.source)
+	}
+	if rule := matchCommandRule(p.confirm, path); rule != "" {
+		u := ui.FromContext(cmd.Context())
+		if flags.NoInput || u == nil || !u.Interactive() {
+			return fmt.Errorf("%s needs interactive confirmation (rule %q in %s)", path, rule, p.source)
+		}
+		prompt := fmt.Sprintf("Policy requires confirmation: run %s?", path)
+		if account != "" {
+			prompt = fmt.Sprintf("Policy requires confirmation: run %s as %s?", path, account)
+		}
+		ok, err := u.Confirm(prompt)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &ExitError{Code: 1, Err: errors.New("cancelled")}
+		}
+	}
+	return nil
+}
+
+// checkPolicyRecipients fails when a recipient is outside the account's
+// gmail.allowRecipients.
+func checkPolicyRecipients(account string, recipients []string) error {
+	p, err := loadPolicy(account)
+	if err != nil || p == nil || p.allowRecipients == nil {
+		return err
+	}
+	if blocked := blockedRecipients(p.allowRecipients, recipients); len(blocked) > 0 {
+		return fmt.Errorf("recipient(s) %s: %w (gmail.allowRecipients in %s)", strings.Join(blocked, ", "), errPolicyDenied, p.source)
+	}
+	return nil
+}
+
+// checkPolicyAttachments fails when an attachment is larger than the
+// account's gmail.maxAttachmentSize.
+func checkPolicyAttachments(account string, paths []string) error {
+	p, err := loadPolicy(account)
+	if err != nil || p == nil || p.maxAttachmentBytes <= 0 {
+		return err
+	}
+	for _, path := range paths {
+		st, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if st.Size() > p.maxAttachmentBytes {
+			return fmt.Errorf("attachment %s is %s: %w (gmail.maxAttachmentSize %s in %s)", path, formatDriveSize(st.Size()), errPolicyDenied, formatDriveSize(p.maxAttachmentBytes), p.source)
+		}
+	}
+	return nil
+}
+
+// checkPolicySendQuota fails once the account has sent gmail.sendsPerDay
+// messages today, counted from the sent log.
+func checkPolicySendQuota(account string) error {
+	p, err := loadPolicy(account)
+	if err != nil || p == nil || p.sendsPerDay <= 0 {
+		return err
+	}
+	entries, err := loadSentLog()
+	if err != nil {
+		return err
+	}
+	now := sentLogNow()
+	y, m, d := now.Date()
+	sent := 0
+	for _, e := range entries {
+		ey, em, ed := e.Time.In(now.Location()).Date()
+		if e.Error == "" && strings.EqualFold(e.Account, account) && ey == y && em == m && ed == d {
+			sent++
+		}
+	}
+	if sent >= p.sendsPerDay {
+		return fmt.Errorf("%s sent %d message(s) today: %w (gmail.sendsPerDay %d in %s)", account, sent, errPolicyDenied, p.sendsPerDay, p.source)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPolicy = `
+default:
+  deny: ["gmail batch", "drive delete"]
+  gmail:
+    allowRecipients: ["@example.com"]
+    maxAttachmentSize: 1KB
+accounts:
+  bot@example.com:
+    deny: [gmail filters]
+    confirm: [gmail send]
+    gmail:
+      allowRecipients: ["ops@example.com"]
+      sendsPerDay: 2
+      requireArm: true
+`
+
+func writeTestPolicy(t *testing.T, body string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	t.Setenv(policyFileEnv, path)
+}
+
+func TestLoadPolicyMergesAccount(t *testing.T) {
+	writeTestPolicy(t, testPolicy)
+
+	p, err := loadPolicy("Bot@Example.com")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := strings.Join(p.deny, "|"); got != "gmail batch|drive delete|gmail filters" {
+		t.Fatalf("deny = %q", got)
+	}
+	if p.maxAttachmentBytes != 1024 || p.sendsPerDay != 2 || !p.requireArm {
+		t.Fatalf("policy = %+v", p)
+	}
+	if p.allowRecipients.allows("dev@example.com") || !p.allowRecipients.allows("ops@example.com") {
+		t.Fatalf("account allowRecipients should replace the default")
+	}
+
+	other, err := loadPolicy("me@example.com")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(other.deny) != 2 || len(other.confirm) != 0 || other.requireArm || !other.allowRecipients.allows("dev@example.com") {
+		t.Fatalf("default policy = %+v", other)
+	}
+
+	for rule, path := range map[string]string{"gmail batch": "gmail batch delete", "*": "tasks list", "drive delete": "drive delete"} {
+		if matchCommandRule([]string{rule}, path) != rule {
+			t.Fatalf("%q should cover %q", rule, path)
+		}
+	}
+	if matchCommandRule([]string{"gmail send"}, "gmail sendas list") != "" {
+		t.Fatalf("rules match whole words only")
+	}
+
+	writeTestPolicy(t, "default:\n  dney: [gmail]\n")
+	if _, err := loadPolicy(""); err == nil {
+		t.Fatalf("unknown keys should be rejected")
+	}
+}
+
+func TestExecute_PolicyDeniesCommandsAndRecipients(t *testing.T) {
+	writeTestPolicy(t, testPolicy)
+	t.Setenv("GOG_GMAIL_ALLOWLIST_MODE", "off")
+
+	err := Execute([]string{"--account", "me@example.com", "--force", "gmail", "batch", "delete", "m1"})
+	if !errors.Is(err, errPolicyDenied) {
+		t.Fatalf("batch delete: %v", err)
+	}
+
+	err = Execute([]string{"--account", "me@example.com", "gmail", "send", "--dry-run", "--to", "x@other.org", "--subject", "s", "--body", "b"})
+	if !errors.Is(err, errPolicyDenied) || !strings.Contains(err.Error(), "x@other.org") {
+		t.Fatalf("send to outsider: %v", err)
+	}
+
+	big := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(big, make([]byte, 2048), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	err = Execute([]string{"--account", "me@example.com", "gmail", "send", "--dry-run", "--to", "a@example.com", "--subject", "s", "--body", "b", "--attach", big})
+	if !errors.Is(err, errPolicyDenied) || !strings.Contains(err.Error(), "maxAttachmentSize") {
+		t.Fatalf("big attachment: %v", err)
+	}
+
+	// Policy confirmations can't be skipped with --yes.
+	err = Execute([]string{"--account", "bot@example.com", "--yes", "--no-input", "gmail", "send", "--to", "ops@example.com", "--subject", "s", "--body", "b"})
+	if err == nil || !strings.Contains(err.Error(), "needs interactive confirmation") {
+		t.Fatalf("confirm: %v", err)
+	}
+}
+
+func TestPolicySendQuotaAndArm(t *testing.T) {
+	writeTestPolicy(t, testPolicy)
+	t.Setenv("GOG_STATE_DIR", t.TempDir())
+	t.Setenv("GOG_GMAIL_REQUIRE_ARM", "")
+	t.Setenv("GOG_GMAIL_SEND_ARMED", "")
+	origNow := sentLogNow
+	t.Cleanup(func() { sentLogNow = origNow })
+	now := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	sentLogNow = func() time.Time { return now }
+
+	if err := requireGmailSendArm("bot@example.com"); err == nil {
+		t.Fatalf("gmail.requireArm should need GOG_GMAIL_SEND_ARMED")
+	}
+	if err := requireGmailSendArm("me@example.com"); err != nil {
+		t.Fatalf("arm for unrestricted account: %v", err)
+	}
+
+	recordSend(nil, sentLogEntry{Time: now.Add(-24 * time.Hour), Account: "bot@example.com"})
+	recordSend(nil, sentLogEntry{Account: "bot@example.com"})
+	recordSend(nil, sentLogEntry{Account: "bot@example.com", Error: "boom"})
+	if err := checkPolicySendQuota("bot@example.com"); err != nil {
+		t.Fatalf("one send today: %v", err)
+	}
+	recordSend(nil, sentLogEntry{Account: "bot@example.com"})
+	if err := checkPolicySendQuota("bot@example.com"); !errors.Is(err, errPolicyDenied) {
+		t.Fatalf("quota: %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+)
+
+// queueNow is swapped in tests.
+var queueNow = time.Now
+
+func newQueueCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Scheduled sends (gmail send --send-at) and follow-ups",
+		Long: `Messages scheduled with "gog gmail send --send-at" wait in a local outbox
+until "gog queue run" sends the ones that are due; the same run checks due
+"gog gmail followup" reminders. Gmail has no server-side scheduling API, so
+run it periodically (cron, launchd, systemd timer):
+
+  */5 * * * * gog queue run --no-input`,
+	}
+	cmd.AddCommand(newQueueListCmd(flags))
+	cmd.AddCommand(newQueueRunCmd(flags))
+	cmd.AddCommand(newQueueRemoveCmd(flags))
+	return cmd
+}
+
+func newQueueListCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List queued messages",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			items, err := listQueuedMessages()
+			if err != nil {
+				return err
+			}
+			items = filterQueueByAccount(items, flags.Account)
+
+			if outfmt.IsJSON(cmd.Context()) {
+				type item struct {
+					ID        string    `json:"id"`
+					Account   string    `json:"account"`
+					SendAt    time.Time `json:"sendAt"`
+					Due       bool      `json:"due"`
+					To        []string  `json:"to,omitempty"`
+					Subject   string    `json:"subject,omitempty"`
+					Attempts  int       `json:"attempts,omitempty"`
+					LastError string    `json:"lastError,omitempty"`
+					Failed    bool      `json:"failed,omitempty"`
+					Sending   bool      `json:"sending,omitempty"`
+				}
+				now := queueNow()
+				out := make([]item, 0, len(items))
+				for _, m := range items {
+					out = append(out, item{
+						ID:        m.ID,
+						Account:   m.Account,
+						SendAt:    m.SendAt,
+						Due:       m.due(now),
+						To:        m.To,
+						Subject:   m.Subject,
+						Attempts:  m.Attempts,
+						LastError: m.LastError,
+						Failed:    m.Failed,
+						Sending:   m.Sending,
+					})
+				}
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"queued": out})
+			}
+			if len(items) == 0 {
+				u.Err().Println("Queue empty")
+				return nil
+			}
+
+			now := queueNow()
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "ID\tSEND_AT\tSTATUS\tACCOUNT\tTO\tSUBJECT")
+			for _, m := range items {
+				status := "scheduled"
+				switch {
+				case m.Sending:
+					status = "sending"
+				case m.Failed:
+					status = "failed"
+				case m.LastError != "":
+					status = "retrying"
+				case m.due(now):
+					status = "due"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					m.ID,
+					m.SendAt.Local().Format("2006-01-02 15:04"),
+					status,
+					m.Account,
+					sanitizeTab(strings.Join(m.To, ",")),
+					sanitizeTab(m.Subject),
+				)
+			}
+			return nil
+		},
+	}
+}
+
+func newQueueRunCmd(flags *rootFlags) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Send queued messages that are due",
+		Long: `Send queued messages whose --send-at time has passed, then check due
+follow-up reminders (see gog gmail followup).
+
+Sent messages leave the queue. Failures stay queued with their error and are
+retried on the next run, up to 5 attempts; permanent errors (e.g. a rejected
+message) mark the message failed at once. Failed messages stay listed until
+removed. Each message is claimed before it is sent, so overlapping runs (cron
+plus a manual run) never send it twice; a message left "sending" by a run
+that crashed mid-send is not retried automatically, since it may have gone
+out. With --account, only that account's messages are sent.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			items, err := listQueuedMessages()
+			if err != nil {
+				return err
+			}
+			items = filterQueueByAccount(items, flags.Account)
+
+			now := queueNow()
+			due := make([]queuedMessage, 0, len(items))
+			for _, m := range items {
+				if m.due(now) {
+					due = append(due, m)
+				}
+			}
+			if !dryRun {
+				armed := map[string]bool{}
+				for _, m := range due {
+					if armed[strings.ToLower(m.Account)] {
+						continue
+					}
+					if err := requireGmailSendArm(m.Account); err != nil {
+						return err
+					}
+					armed[strings.ToLower(m.Account)] = true
+				}
+			}
+
+			type result struct {
+				ID        string `json:"id"`
+				Account   string `json:"account"`
+				MessageID string `json:"messageId,omitempty"`
+				ThreadID  string `json:"threadId,omitempty"`
+				Error     string `json:"error,omitempty"`
+			}
+			results := make([]result, 0, len(due))
+			services := map[string]*gmail.Service{}
+			failed := 0
+			for _, m := range due {
+				r := result{ID: m.ID, Account: m.Account}
+				if dryRun {
+					results = append(results, r)
+					continue
+				}
+				m, claimed, err := claimQueuedMessage(m.ID)
+				if err != nil {
+					return err
+				}
+				if !claimed {
+					continue // a concurrent queue run has it
+				}
+				if !m.due(now) {
+					// Updated by a concurrent run since it was listed.
+					if err := finishQueuedMessage(m, false); err != nil {
+						return err
+					}
+					continue
+				}
+				sent, sendErr := func() (*gmail.Message, error) {
+					if err := checkPolicySendQuota(m.Account); err != nil {
+						return nil, err
+					}
+					svc, ok := services[m.Account]
+					if !ok {
+						var err error
+						svc, err = newGmailService(cmd.Context(), m.Account)
+						if err != nil {
+							return nil, err
+						}
+						services[m.Account] = svc
+					}
+					msg := &gmail.Message{Raw: m.Raw, ThreadId: m.ThreadID}
+					return svc.Users.Messages.Send("me", msg).Context(cmd.Context()).Do()
+				}()
+				logEntry := sentLogEntry{Account: m.Account, Source: "queue", ThreadID: m.ThreadID, To: m.To, Subject: m.Subject}
+				if raw, err := decodeGmailRaw(m.Raw); err == nil {
+					logEntry = sentLogFromRaw(logEntry, raw)
+				}
+				if sendErr != nil {
+					logEntry.Error = sendErr.Error()
+				} else {
+					logEntry.MessageID, logEntry.ThreadID = sent.Id, sent.ThreadId
+				}
+				recordSend(u, logEntry)
+				if sendErr != nil {
+					failed++
+					r.Error = sendErr.Error()
+					m.Attempts++
+					m.LastError = sendErr.Error()
+					m.Failed = permanentSendError(sendErr) || m.Attempts >= maxQueueAttempts
+					if err := finishQueuedMessage(m, false); err != nil {
+						return err
+					}
+					results = append(results, r)
+					continue
+				}
+				r.MessageID = sent.Id
+				r.ThreadID = sent.ThreadId
+				if err := finishQueuedMessage(m, true); err != nil {
+					return fmt.Errorf("sent queued message %s but could not remove it from the queue: %w", m.ID, err)
+				}
+				results = append(results, r)
+			}
+
+			followups, err := runDueFollowups(cmd.Context(), flags.Account, dryRun, services)
+			if err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"dryRun":    dryRun,
+					"due":       len(due),
+					"failed":    failed,
+					"results":   results,
+					"followups": followups,
+				}); err != nil {
+					return err
+				}
+			} else if len(results) == 0 && len(followups) == 0 {
+				u.Err().Println("Nothing due")
+			} else if len(results) > 0 {
+				w, flush := tableWriter(cmd.Context())
+				fmt.Fprintln(w, "ID\tACCOUNT\tRESULT")
+				for _, r := range results {
+					status := "sent " + r.MessageID
+					switch {
+					case dryRun:
+						status = "due"
+					case r.Error != "":
+						status = "failed: " + sanitizeTab(r.Error)
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\n", r.ID, r.Account, status)
+				}
+				flush()
+			}
+			if len(followups) > 0 && !outfmt.IsJSON(cmd.Context()) {
+				writeFollowupResults(cmd.Context(), followups)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d queued messages failed to send", failed, len(due))
+			}
+			return followupFailures(followups)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List due messages without sending")
+	return cmd
+}
+
+func newQueueRemoveCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <id>",
+		Aliases: []string{"rm", "cancel"},
+		Short:   "Remove a queued message without sending it",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := strings.TrimSpace(args[0])
+			if _, err := loadQueuedMessage(id); err != nil {
+				return err
+			}
+			if err := confirmDestructive(cmd, flags, fmt.Sprintf("cancel queued message %s", id)); err != nil {
+				return err
+			}
+			if err := removeQueuedMessage(id); err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"removed": true, "id": id})
+			}
+			u := ui.FromContext(cmd.Context())
+			u.Out().Printf("removed\ttrue")
+			u.Out().Printf("id\t%s", id)
+			return nil
+		},
+	}
+}
+
+func filterQueueByAccount(items []queuedMessage, account string) []queuedMessage {
+	account = strings.TrimSpace(account)
+	if account == "" {
+		return items
+	}
+	out := make([]queuedMessage, 0, len(items))
+	for _, m := range items {
+		if strings.EqualFold(m.Account, account) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// redactKinds are the PII classes --redact understands, in the order they
+// are applied (emails first: addresses can contain digits).
+var redactKinds = []string{"emails", "cards", "amounts", "phones"}
+
+var (
+	redactEmailRe  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	redactCardRe   = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	redactAmountRe = regexp.MustCompile(`(?i)[$€£¥₹]\s?\d(?:[\d,.']*\d)?|\b(?:USD|EUR|GBP|CHF|JPY|CAD|AUD|INR)\s?\d(?:[\d,.']*\d)?|\d(?:[\d,.']*\d)?\s?(?:[$€£¥₹]|\b(?:USD|EUR|GBP|CHF|JPY|CAD|AUD|INR)\b)`)
+	redactPhoneRe  = regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?(?:\(\d{1,4}\)[\s.\-]?)?\d{2,4}(?:[\s.\-]\d{2,4}){1,4}`)
+)
+
+// redactor masks PII in text. Each distinct value gets a stable numbered
+// placeholder ([EMAIL-1], [PHONE-2], ...) so a redacted conversation still
+// shows who is who.
+type redactor struct {
+	kinds map[string]bool
+	seen  map[string]map[string]int
+}
+
+// parseRedact parses --redact (comma-separated kinds, or "all"); an empty
+// value returns nil.
+func parseRedact(spec string) (*redactor, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	r := &redactor{kinds: map[string]bool{}, seen: map[string]map[string]int{}}
+	for _, k := range splitCSV(spec) {
+		k = strings.ToLower(k)
+		switch k {
+		case "all":
+			for _, kind := range redactKinds {
+				r.kinds[kind] = true
+			}
+			continue
+		case "email", "phone", "amount", "card":
+			k += "s"
+		}
+		known := false
+		for _, kind := range redactKinds {
+			known = known || kind == k
+		}
+		if !known {
+			return nil, usagef("invalid --redact %q (expected %s or all)", k, strings.Join(redactKinds, ","))
+		}
+		r.kinds[k] = true
+	}
+	return r, nil
+}
+
+func (r *redactor) placeholder(kind, value string) string {
+	ids := r.seen[kind]
+	if ids == nil {
+		ids = map[string]int{}
+		r.seen[kind] = ids
+	}
+	key := strings.ToLower(value)
+	if kind == "phones" || kind == "cards" {
+		key = digitsOnly(value)
+	}
+	n, ok := ids[key]
+	if !ok {
+		n = len(ids) + 1
+		ids[key] = n
+	}
+	return fmt.Sprintf("[%s-%d]", strings.ToUpper(strings.TrimSuffix(kind, "s")), n)
+}
+
+// redact masks the configured kinds in s. A nil redactor returns s.
+func (r *redactor) redact(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	for _, kind := range redactKinds {
+		if !r.kinds[kind] {
+			continue
+		}
+		switch kind {
+		case "emails":
+			s = redactEmailRe.ReplaceAllStringFunc(s, func(m string) string { return r.placeholder(kind, m) })
+		case "cards":
+			s = redactCardRe.ReplaceAllStringFunc(s, func(m string) string {
+				if !luhnValid(digitsOnly(m)) {
+					return m
+				}
+				return r.placeholder(kind, m)
+			})
+		case "amounts":
+			s = redactAmountRe.ReplaceAllStringFunc(s, func(m string) string { return r.placeholder(kind, m) })
+		case "phones":
+			s = redactPhoneRe.ReplaceAllStringFunc(s, func(m string) string {
+				if !looksLikePhone(m) {
+					return m
+				}
+				return r.placeholder(kind, m)
+			})
+		}
+	}
+	return s
+}
+
+// looksLikePhone rejects dates, versions and other short digit groups: a
+// phone has 9-15 digits, or 7+ when written with a + or (area) prefix.
+func looksLikePhone(m string) bool {
+	n := len(digitsOnly(m))
+	if n > 15 {
+		return false
+	}
+	if strings.HasPrefix(m, "+") || strings.HasPrefix(m, "(") {
+		return n >= 7
+	}
+	return n >= 9
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+func luhnValid(digits string) bool {
+	if len(digits) < 13 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// summary lists how many distinct values of each kind were masked.
+func (r *redactor) summary() map[string]int {
+	if r == nil {
+		return nil
+	}
+	out := map[string]int{}
+	for k, ids := range r.seen {
+		if len(ids) > 0 {
+			out[k] = len(ids)
+		}
+	}
+	return out
+}
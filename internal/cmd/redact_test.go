@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	r, err := parseRedact("emails,phones,amounts,cards")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	in := "Ana <ana@example.com> wrote on 2024-03-15: call +1 (415) 555-0100 or 415.555.0100, " +
+		"pay $1,250.00 with 4111 1111 1111 1111. Ask Bob@example.org, cc ana@EXAMPLE.com. Order 12345."
+	got := r.redact(in)
+	for _, want := range []string{
+		"Ana <[EMAIL-1]>", "[EMAIL-2]", "cc [EMAIL-1]",
+		"2024-03-15", "call [PHONE-1] or [PHONE-2]",
+		"pay [AMOUNT-1]", "with [CARD-1]", "Order 12345",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in %q", want, got)
+		}
+	}
+	for _, leak := range []string{"ana@", "555", "1,250", "4111"} {
+		if strings.Contains(got, leak) {
+			t.Fatalf("leaked %q in %q", leak, got)
+		}
+	}
+	if s := r.summary(); s["emails"] != 2 || s["cards"] != 1 || s["amounts"] != 1 {
+		t.Fatalf("summary = %v", s)
+	}
+
+	only, err := parseRedact("email")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := only.redact("x@y.com paid $5"); got != "[EMAIL-1] paid $5" {
+		t.Fatalf("emails only = %q", got)
+	}
+	if r, err := parseRedact(""); err != nil || r != nil || r.redact("a@b.com") != "a@b.com" {
+		t.Fatalf("empty spec = %v, %v", r, err)
+	}
+	if _, err := parseRedact("emails,ssn"); err == nil {
+		t.Fatalf("expected error for unknown kind")
+	}
+}
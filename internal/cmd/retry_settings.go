@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+// Env overrides for the http.* retry and rate-limit keys in config.json.
+const (
+	maxRetriesEnv     = "GOG_MAX_RETRIES"
+	max5xxRetriesEnv  = "GOG_MAX_5XX_RETRIES"
+	retryBaseDelayEnv = "GOG_RETRY_BASE_DELAY"
+	maxRetryDelayEnv  = "GOG_MAX_RETRY_DELAY"
+	qpsEnv            = "GOG_QPS"
+)
+
+// retryPolicyFromEnv returns the retry policy from env and config, or nil
+// when neither changes the defaults.
+func retryPolicyFromEnv(cfg config.HTTPConfig) (*googleapi.RetryPolicy, error) {
+	p := googleapi.DefaultRetryPolicy()
+	changed := false
+
+	intSetting := func(env, key string, fromConfig *int, dst *int) error {
+		raw := strings.TrimSpace(os.Getenv(env))
+		switch {
+		case raw != "":
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 0 {
+				return usagef("invalid %s=%q (must be a number >= 0)", env, raw)
+			}
+			*dst = n
+		case fromConfig != nil:
+			if *fromConfig < 0 {
+				return usagef("invalid http.%s %d in config.json (must be >= 0)", key, *fromConfig)
+			}
+			*dst = *fromConfig
+		default:
+			return nil
+		}
+		changed = true
+		return nil
+	}
+	durationSetting := func(env, key, fromConfig string, dsts ...*time.Duration) error {
+		raw, source := strings.TrimSpace(os.Getenv(env)), env
+		if raw == "" {
+			raw, source = strings.TrimSpace(fromConfig), "http."+key
+		}
+		if raw == "" {
+			return nil
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			return usagef("invalid %s %q (use e.g. 500ms, 2s)", source, raw)
+		}
+		for _, dst := range dsts {
+			*dst = d
+		}
+		changed = true
+		return nil
+	}
+
+	if err := intSetting(maxRetriesEnv, "maxRetries", cfg.MaxRetries, &p.MaxRetries429); err != nil {
+		return nil, err
+	}
+	if err := intSetting(max5xxRetriesEnv, "max5xxRetries", cfg.Max5xxRetries, &p.MaxRetries5xx); err != nil {
+		return nil, err
+	}
+	if err := durationSetting(retryBaseDelayEnv, "retryBaseDelay", cfg.RetryBaseDelay, &p.BaseDelay, &p.ServerErrorDelay); err != nil {
+		return nil, err
+	}
+	if err := durationSetting(maxRetryDelayEnv, "maxRetryDelay", cfg.MaxRetryDelay, &p.MaxDelay); err != nil {
+		return nil, err
+	}
+	if !changed {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// qpsFromEnv returns the per-account request rate limit (0 = unlimited).
+func qpsFromEnv(cfg config.HTTPConfig) (float64, error) {
+	raw := strings.TrimSpace(os.Getenv(qpsEnv))
+	if raw == "" {
+		if cfg.QPS < 0 {
+			return 0, usagef("invalid http.qps %v in config.json (must be >= 0)", cfg.QPS)
+		}
+		return cfg.QPS, nil
+	}
+	qps, err := strconv.ParseFloat(raw, 64)
+	if err != nil || qps < 0 {
+		return 0, usagef("invalid %s=%q (must be a number >= 0)", qpsEnv, raw)
+	}
+	return qps, nil
+}
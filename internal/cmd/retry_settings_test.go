@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestRetryPolicyFromEnv(t *testing.T) {
+	for _, env := range []string{maxRetriesEnv, max5xxRetriesEnv, retryBaseDelayEnv, maxRetryDelayEnv, qpsEnv} {
+		t.Setenv(env, "")
+	}
+	if p, err := retryPolicyFromEnv(config.HTTPConfig{}); err != nil || p != nil {
+		t.Fatalf("defaults: %+v %v", p, err)
+	}
+
+	zero, eight := 0, 8
+	p, err := retryPolicyFromEnv(config.HTTPConfig{MaxRetries: &eight, Max5xxRetries: &zero, RetryBaseDelay: "2s"})
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	want := googleapi.DefaultRetryPolicy()
+	want.MaxRetries429, want.MaxRetries5xx, want.BaseDelay, want.ServerErrorDelay = 8, 0, 2*time.Second, 2*time.Second
+	if *p != want {
+		t.Fatalf("config policy = %+v", *p)
+	}
+
+	t.Setenv(maxRetriesEnv, "10")
+	t.Setenv(maxRetryDelayEnv, "1m")
+	p, err = retryPolicyFromEnv(config.HTTPConfig{MaxRetries: &eight})
+	if err != nil || p.MaxRetries429 != 10 || p.MaxDelay != time.Minute {
+		t.Fatalf("env policy = %+v %v", p, err)
+	}
+
+	t.Setenv(retryBaseDelayEnv, "soon")
+	if _, err := retryPolicyFromEnv(config.HTTPConfig{}); err == nil {
+		t.Fatalf("expected error for bad %s", retryBaseDelayEnv)
+	}
+	t.Setenv(retryBaseDelayEnv, "")
+	t.Setenv(maxRetriesEnv, "-1")
+	if _, err := retryPolicyFromEnv(config.HTTPConfig{}); err == nil {
+		t.Fatalf("expected error for negative %s", maxRetriesEnv)
+	}
+}
+
+func TestQPSFromEnv(t *testing.T) {
+	t.Setenv(qpsEnv, "")
+	if q, err := qpsFromEnv(config.HTTPConfig{QPS: 4}); err != nil || q != 4 {
+		t.Fatalf("config qps = %v %v", q, err)
+	}
+	t.Setenv(qpsEnv, "2.5")
+	if q, err := qpsFromEnv(config.HTTPConfig{QPS: 4}); err != nil || q != 2.5 {
+		t.Fatalf("env qps = %v %v", q, err)
+	}
+	t.Setenv(qpsEnv, "fast")
+	if _, err := qpsFromEnv(config.HTTPConfig{}); err == nil {
+		t.Fatalf("expected error for bad %s", qpsEnv)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -9,8 +10,11 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/steipete/gogcli/internal/config"
 	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/secrets"
 	"github.com/steipete/gogcli/internal/ui"
 )
 
@@ -19,9 +23,28 @@ type rootFlags struct {
 	Account string
 	JSON    bool
 	Plain   bool
+	Table   string
+	NDJSON  bool
 	Force   bool
 	NoInput bool
 	Verbose bool
+
+	Hedge           bool
+	HedgePercentile float64
+	MaxConnsPerHost int
+	MaxAPICalls     int64
+	Endpoints       []string
+	NoCache         bool
+	Stats           bool
+
+	Proxy              string
+	CABundle           string
+	InsecureSkipVerify bool
+
+	Impersonate string
+	SAKey       string
+	TokenStore  string
+	AutoConsent bool
 }
 
 func applyLegacyOutputFlag(flags *rootFlags, output string) error {
@@ -32,20 +55,43 @@ func applyLegacyOutputFlag(flags *rootFlags, output string) error {
 	switch strings.ToLower(out) {
 	case "json":
 		flags.JSON = true
-	case "plain", "text", "tsv":
+	case "ndjson", "jsonl":
+		flags.JSON = true
+		flags.NDJSON = true
+	case "plain", "text":
+		flags.Plain = true
+	case outfmt.TableCSV, outfmt.TableTSV:
+		flags.JSON = false
 		flags.Plain = true
+		flags.Table = strings.ToLower(out)
 	default:
-		return fmt.Errorf("unsupported --output value %q (use --json or --plain)", output)
+		return fmt.Errorf("unsupported --output value %q (use json, ndjson, plain, csv, or tsv)", output)
 	}
 	return nil
 }
 
 func Execute(args []string) error {
-	flags := rootFlags{Color: envOr("GOG_COLOR", "auto")}
+	return execute(args, false)
+}
+
+// execute runs one invocation; scopeRetried is set for the re-run after a
+// scope upgrade, so a token that still lacks scopes does not loop.
+func execute(args []string, scopeRetried bool) error {
+	stdinUsed.Store(false)
+	flags := rootFlags{
+		Color:       envOr("GOG_COLOR", "auto"),
+		Impersonate: os.Getenv("GOG_IMPERSONATE"),
+		SAKey:       os.Getenv("GOG_SA_KEY"),
+		Proxy:       os.Getenv("GOG_PROXY"),
+		CABundle:    os.Getenv("GOG_CA_BUNDLE"),
+		TokenStore:  os.Getenv("GOG_TOKEN_STORE"),
+	}
 	envMode := outfmt.FromEnv()
 	flags.JSON = envMode.JSON
 	flags.Plain = envMode.Plain
 	var output string
+	runStats := googleapi.NewUsageStats()
+	resetGmailLabelCache()
 
 	// Avoid dangerous prefix-matching for commands (future-proofing).
 	cobra.EnablePrefixMatching = false
@@ -122,8 +168,31 @@ func Execute(args []string) error {
 			if err != nil {
 				return err
 			}
+			mode.Table = flags.Table
+			mode.NDJSON = flags.NDJSON
 			cmd.SetContext(outfmt.WithMode(cmd.Context(), mode))
 
+			transportOpts, err := transportOptionsFromFlags(&flags)
+			if err != nil {
+				return err
+			}
+			transportOpts.Audit = auditRecorder(cmd, args)
+			transportOpts.Stats = runStats
+			cmd.SetContext(googleapi.WithTransportOptions(cmd.Context(), transportOpts))
+
+			if err := secrets.SetBackend(flags.TokenStore); err != nil {
+				return usage(err.Error())
+			}
+			if err := applyServiceAccountFlags(&flags); err != nil {
+				return err
+			}
+			if key := strings.TrimSpace(flags.SAKey); key != "" {
+				cmd.SetContext(googleapi.WithCredentialProvider(cmd.Context(), googleapi.ServiceAccountCredentials{KeyFile: key}))
+			}
+			if sessionTokens != nil {
+				cmd.SetContext(googleapi.WithCredentialProvider(cmd.Context(), sessionTokens.Wrap(googleapi.CredentialProviderFromContext(cmd.Context()))))
+			}
+
 			u, err := ui.New(ui.Options{
 				Stdout: os.Stdout,
 				Stderr: os.Stderr,
@@ -138,7 +207,7 @@ func Execute(args []string) error {
 				return err
 			}
 			cmd.SetContext(ui.WithUI(cmd.Context(), u))
-			return nil
+			return enforceCommandPolicy(cmd, &flags)
 		},
 	}
 
@@ -146,12 +215,26 @@ func Execute(args []string) error {
 	root.PersistentFlags().StringVar(&flags.Color, "color", flags.Color, "Color output: auto|always|never")
 	root.PersistentFlags().StringVar(&flags.Account, "account", "", "Account email for API commands (gmail/calendar/drive/docs/slides/contacts/tasks/people/sheets)")
 	root.PersistentFlags().BoolVar(&flags.JSON, "json", flags.JSON, "Output JSON to stdout (best for scripting)")
-	root.PersistentFlags().StringVar(&output, "output", "", "Deprecated: use --json or --plain")
-	_ = root.PersistentFlags().MarkHidden("output")
+	root.PersistentFlags().StringVar(&output, "output", "", "Output format: json|ndjson|plain|csv|tsv (ndjson streams paginated lists; csv/tsv apply to tables)")
 	root.PersistentFlags().BoolVar(&flags.Plain, "plain", flags.Plain, "Output stable, parseable text to stdout (TSV; no colors)")
 	root.PersistentFlags().BoolVar(&flags.Force, "force", false, "Skip confirmations for destructive commands")
+	root.PersistentFlags().BoolVarP(&flags.Force, "yes", "y", false, "Assume yes for confirmations (same as --force)")
 	root.PersistentFlags().BoolVar(&flags.NoInput, "no-input", false, "Never prompt; fail instead (useful for CI)")
 	root.PersistentFlags().BoolVar(&flags.Verbose, "verbose", false, "Enable verbose logging")
+	root.PersistentFlags().BoolVar(&flags.Hedge, "hedge", false, "Send a second attempt for slow idempotent GETs and use the first response")
+	root.PersistentFlags().Float64Var(&flags.HedgePercentile, "hedge-percentile", googleapi.DefaultHedgePercentile, "Latency percentile (1-100) after which --hedge sends the second attempt")
+	root.PersistentFlags().IntVar(&flags.MaxConnsPerHost, "max-conns-per-host", 0, "Max concurrent connections per Google API host (0 = unlimited)")
+	root.PersistentFlags().Int64Var(&flags.MaxAPICalls, "max-api-calls", 0, "Abort once this many Google API requests have been sent (0 = unlimited)")
+	root.PersistentFlags().StringArrayVar(&flags.Endpoints, "endpoint", nil, "Override an API base URL: api=URL (repeatable; apis: "+strings.Join(googleapi.EndpointAPIs, ",")+"; env GOG_<API>_ENDPOINT)")
+	root.PersistentFlags().BoolVar(&flags.NoCache, "no-cache", false, "Bypass the HTTP response cache for this run (see GOG_HTTP_CACHE)")
+	root.PersistentFlags().StringVar(&flags.Proxy, "proxy", flags.Proxy, "Proxy URL for Google API requests: http(s)://host:port or socks5://host:port (default: HTTPS_PROXY; env GOG_PROXY)")
+	root.PersistentFlags().StringVar(&flags.CABundle, "ca-bundle", flags.CABundle, "PEM file of extra CA certificates to trust, e.g. a TLS-intercepting proxy's (env GOG_CA_BUNDLE)")
+	root.PersistentFlags().BoolVar(&flags.InsecureSkipVerify, "insecure-skip-verify", false, "Skip TLS certificate verification (requires "+allowInsecureEnv+"=1; debugging only)")
+	root.PersistentFlags().BoolVar(&flags.Stats, "stats", false, "Print this run's Google API calls, retries and rate-limit hits per API to stderr (see gog stats)")
+	root.PersistentFlags().StringVar(&flags.SAKey, "sa-key", flags.SAKey, "Service account key JSON; authenticate without the keyring (Workspace domain-wide delegation)")
+	root.PersistentFlags().BoolVar(&flags.AutoConsent, "auto-consent", false, "On missing OAuth scopes, re-authorize with the stored plus required scopes and retry")
+	root.PersistentFlags().StringVar(&flags.TokenStore, "token-store", flags.TokenStore, "Refresh token store: keyring|file|pass|env (default keyring)")
+	root.PersistentFlags().StringVar(&flags.Impersonate, "impersonate", flags.Impersonate, "User to impersonate with --sa-key (defaults to --account)")
 
 	root.AddCommand(newAuthCmd(&flags))
 	root.AddCommand(newDriveCmd(&flags))
@@ -163,7 +246,21 @@ func Execute(args []string) error {
 	root.AddCommand(newTasksCmd(&flags))
 	root.AddCommand(newPeopleCmd(&flags))
 	root.AddCommand(newSheetsCmd(&flags))
+	root.AddCommand(newQueueCmd(&flags))
+	root.AddCommand(newEventsCmd(&flags))
+	root.AddCommand(newStatusCmd(&flags))
+	root.AddCommand(newExportCmd(&flags))
+	root.AddCommand(newImportCmd(&flags))
+	root.AddCommand(newAuditCmd(&flags))
+	root.AddCommand(newAdminCmd(&flags))
+	root.AddCommand(newMeetCmd(&flags))
+	root.AddCommand(newCacheCmd(&flags))
+	root.AddCommand(newStatsCmd(&flags))
+	root.AddCommand(newShellCmd(&flags))
+	root.AddCommand(newConfigCmd())
+	root.AddCommand(newSecureCmd(&flags))
 	root.AddCommand(newVersionCmd())
+	root.AddCommand(newVerifyReleaseCmd())
 
 	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
 		// pflag already includes helpful context ("unknown flag", "invalid argument", ...).
@@ -171,7 +268,10 @@ func Execute(args []string) error {
 	})
 	root.AddCommand(newCompletionCmd())
 
-	err := root.Execute()
+	executed, err := root.ExecuteC()
+	if executed != nil {
+		finishRunStats(executed, &flags, runStats)
+	}
 	if err == nil {
 		return nil
 	}
@@ -179,6 +279,24 @@ func Execute(args []string) error {
 		return nil
 	}
 
+	var scopeErr *googleapi.InsufficientScopeError
+	if errors.As(err, &scopeErr) {
+		u := ui.FromContext(root.Context())
+		if !scopeRetried && offerScopeUpgrade(u, &flags, scopeErr) {
+			switch upErr := upgradeAccountScopes(context.Background(), scopeErr); {
+			case upErr != nil:
+				err = upErr
+			case runStats.Succeeded() == 0 && !stdinUsed.Load():
+				// The scope error was the first response: nothing was
+				// changed and no results were printed yet, and stdin is
+				// still unread, so running again from the start is safe.
+				return execute(args, true)
+			default:
+				err = fmt.Errorf("re-authorized %s with the missing %s scopes; the command had already made changes, printed output or read stdin before it failed, so it was not re-run: run it again", scopeErr.Email, scopeErr.Service)
+			}
+		}
+	}
+
 	if ExitCode(err) == 1 && isUsageError(err) {
 		err = &ExitError{Code: 2, Err: err}
 	}
@@ -191,6 +309,76 @@ func Execute(args []string) error {
 	return err
 }
 
+func transportOptionsFromFlags(flags *rootFlags) (googleapi.TransportOptions, error) {
+	if flags.HedgePercentile <= 0 || flags.HedgePercentile > 100 {
+		return googleapi.TransportOptions{}, usagef("invalid --hedge-percentile %v (must be in (0, 100])", flags.HedgePercentile)
+	}
+	if flags.MaxConnsPerHost < 0 {
+		return googleapi.TransportOptions{}, usagef("invalid --max-conns-per-host %d (must be >= 0)", flags.MaxConnsPerHost)
+	}
+	if flags.MaxAPICalls < 0 {
+		return googleapi.TransportOptions{}, usagef("invalid --max-api-calls %d (must be >= 0)", flags.MaxAPICalls)
+	}
+	opts := googleapi.TransportOptions{
+		Hedge:           flags.Hedge,
+		HedgePercentile: flags.HedgePercentile,
+		MaxConnsPerHost: flags.MaxConnsPerHost,
+	}
+	if flags.MaxAPICalls > 0 {
+		opts.Budget = googleapi.NewRequestBudget(flags.MaxAPICalls)
+	}
+	if len(flags.Endpoints) > 0 {
+		endpoints, err := googleapi.ParseEndpoints(flags.Endpoints)
+		if err != nil {
+			return googleapi.TransportOptions{}, usage(err.Error())
+		}
+		opts.Endpoints = endpoints
+	}
+	// Every command gets here, including ones that never call an API
+	// (config, version, ...), so a broken config.json only costs the http.*
+	// settings; commands that need the rest of the config report it.
+	cfg, err := config.ReadConfigFile()
+	if err != nil {
+		slog.Warn("ignoring http settings from unreadable config", "err", err)
+		cfg = config.File{}
+	}
+	if !flags.NoCache {
+		if opts.Cache, err = httpCacheFromEnv(cfg.HTTP); err != nil {
+			return googleapi.TransportOptions{}, err
+		}
+	}
+	if opts.Retry, err = retryPolicyFromEnv(cfg.HTTP); err != nil {
+		return googleapi.TransportOptions{}, err
+	}
+	if opts.QPS, err = qpsFromEnv(cfg.HTTP); err != nil {
+		return googleapi.TransportOptions{}, err
+	}
+	if err := applyNetworkFlags(flags, &opts); err != nil {
+		return googleapi.TransportOptions{}, err
+	}
+	if opts.Fixtures, err = httpFixturesFromEnv(); err != nil {
+		return googleapi.TransportOptions{}, err
+	}
+	return opts, nil
+}
+
+// applyServiceAccountFlags validates --sa-key/--impersonate. The impersonated
+// user becomes the account, so "me" in API calls refers to them.
+func applyServiceAccountFlags(flags *rootFlags) error {
+	impersonate := strings.TrimSpace(flags.Impersonate)
+	if impersonate == "" {
+		return nil
+	}
+	if strings.TrimSpace(flags.SAKey) == "" {
+		return usage("--impersonate requires --sa-key (or GOG_SA_KEY)")
+	}
+	if account := strings.TrimSpace(flags.Account); account != "" && !strings.EqualFold(account, impersonate) {
+		return usagef("--account %s conflicts with --impersonate %s", account, impersonate)
+	}
+	flags.Account = impersonate
+	return nil
+}
+
 func envOr(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
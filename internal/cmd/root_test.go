@@ -54,3 +54,56 @@ func TestExecute_UnknownFlag(t *testing.T) {
 		t.Fatalf("expected stderr output")
 	}
 }
+
+func TestTransportOptionsFromFlags(t *testing.T) {
+	opts, err := transportOptionsFromFlags(&rootFlags{HedgePercentile: 90, MaxConnsPerHost: 8, MaxAPICalls: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.MaxConnsPerHost != 8 || opts.Budget == nil || opts.Budget.Limit != 5 {
+		t.Fatalf("unexpected: %#v", opts)
+	}
+
+	opts, err = transportOptionsFromFlags(&rootFlags{HedgePercentile: 95})
+	if err != nil || opts.Budget != nil {
+		t.Fatalf("expected no budget by default: %#v %v", opts, err)
+	}
+
+	opts, err = transportOptionsFromFlags(&rootFlags{HedgePercentile: 95, Endpoints: []string{"gmail=http://localhost:8080"}})
+	if err != nil || opts.Endpoints["gmail"] != "http://localhost:8080/" {
+		t.Fatalf("unexpected endpoints: %#v %v", opts.Endpoints, err)
+	}
+
+	for _, f := range []rootFlags{
+		{HedgePercentile: 0},
+		{HedgePercentile: 95, MaxConnsPerHost: -1},
+		{HedgePercentile: 95, MaxAPICalls: -1},
+		{HedgePercentile: 95, Endpoints: []string{"mail=http://localhost"}},
+	} {
+		if _, err := transportOptionsFromFlags(&f); ExitCode(err) != 2 {
+			t.Fatalf("expected usage error for %#v, got %v", f, err)
+		}
+	}
+}
+
+func TestApplyServiceAccountFlags(t *testing.T) {
+	flags := rootFlags{Impersonate: "user@example.com"}
+	if err := applyServiceAccountFlags(&flags); err == nil || !strings.Contains(err.Error(), "--sa-key") {
+		t.Fatalf("expected --sa-key error, got %v", err)
+	}
+
+	flags = rootFlags{Impersonate: "user@example.com", SAKey: "key.json", Account: "other@example.com"}
+	if err := applyServiceAccountFlags(&flags); err == nil || !strings.Contains(err.Error(), "conflicts") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+
+	flags = rootFlags{Impersonate: "user@example.com", SAKey: "key.json"}
+	if err := applyServiceAccountFlags(&flags); err != nil || flags.Account != "user@example.com" {
+		t.Fatalf("unexpected: err=%v account=%q", err, flags.Account)
+	}
+
+	flags = rootFlags{}
+	if err := applyServiceAccountFlags(&flags); err != nil || flags.Account != "" {
+		t.Fatalf("unexpected: err=%v account=%q", err, flags.Account)
+	}
+}
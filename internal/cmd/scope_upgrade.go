@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/googleauth"
+	"github.com/steipete/gogcli/internal/secrets"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// offerScopeUpgrade decides whether to re-authorize after an insufficient
+// scope error: always with --auto-consent, after a prompt on a TTY, never
+// with --no-input or a service account (whose scopes are granted by an admin).
+func offerScopeUpgrade(u *ui.UI, flags *rootFlags, scopeErr *googleapi.InsufficientScopeError) bool {
+	if strings.TrimSpace(flags.SAKey) != "" {
+		return false
+	}
+	if flags.AutoConsent {
+		return true
+	}
+	if flags.NoInput || u == nil || !u.Interactive() {
+		return false
+	}
+	ok, err := u.Confirm(fmt.Sprintf("%s needs additional %s scopes. Re-authorize now?", scopeErr.Email, scopeErr.Service))
+	return err == nil && ok
+}
+
+// upgradeAccountScopes runs the OAuth flow for the account again, requesting
+// the union of its stored scopes and the ones the failed call needed, and
+// stores the new refresh token with the merged services and scopes.
+func upgradeAccountScopes(ctx context.Context, scopeErr *googleapi.InsufficientScopeError) error {
+	store, err := openSecretsStore()
+	if err != nil {
+		return err
+	}
+	// A missing token just means there is nothing to merge.
+	prev, _ := store.GetToken(scopeErr.Email)
+
+	serviceNames := mergeSorted(prev.Services, nil)
+	if _, err := googleauth.ParseService(scopeErr.Service); err == nil {
+		serviceNames = mergeSorted(serviceNames, []string{scopeErr.Service})
+	}
+	services := make([]googleauth.Service, 0, len(serviceNames))
+	for _, name := range serviceNames {
+		if svc, parseErr := googleauth.ParseService(name); parseErr == nil {
+			services = append(services, svc)
+		}
+	}
+	// Tokens stored before scopes were tracked only know their services.
+	serviceScopes, err := googleauth.ScopesForServices(services)
+	if err != nil {
+		return err
+	}
+	scopes := mergeSorted(prev.Scopes, append(serviceScopes, scopeErr.Required...))
+
+	refreshToken, err := authorizeGoogle(ctx, googleauth.AuthorizeOptions{
+		Services:     services,
+		Scopes:       scopes,
+		ForceConsent: true,
+	})
+	if err != nil {
+		return fmt.Errorf("re-authorize %s: %w", scopeErr.Email, err)
+	}
+	return store.SetToken(scopeErr.Email, secrets.Token{
+		Email:        scopeErr.Email,
+		Services:     serviceNames,
+		Scopes:       scopes,
+		RefreshToken: refreshToken,
+	})
+}
+
+// mergeSorted returns the sorted union of a and b without empty entries.
+func mergeSorted(a, b []string) []string {
+	seen := map[string]struct{}{}
+	out := make([]string, 0, len(a)+len(b))
+	for _, v := range append(append([]string{}, a...), b...) {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/googleauth"
+	"github.com/steipete/gogcli/internal/secrets"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_AutoConsentUpgradesScopesAndRetries(t *testing.T) {
+	origGmail, origOpen, origAuth := newGmailService, openSecretsStore, authorizeGoogle
+	t.Cleanup(func() {
+		newGmailService = origGmail
+		openSecretsStore = origOpen
+		authorizeGoogle = origAuth
+	})
+
+	store := newMemSecretsStore()
+	if err := store.SetToken("a@b.com", secrets.Token{Services: []string{"calendar"}, RefreshToken: "old"}); err != nil {
+		t.Fatalf("SetToken: %v", err)
+	}
+	openSecretsStore = func() (secrets.Store, error) { return store, nil }
+
+	var authOpts googleauth.AuthorizeOptions
+	authorizeGoogle = func(_ context.Context, opts googleauth.AuthorizeOptions) (string, error) {
+		authOpts = opts
+		return "new", nil
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if tok, _ := store.GetToken("a@b.com"); tok.RefreshToken != "new" {
+			w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":403,"message":"Request had insufficient authentication scopes."}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX"}}})
+	}))
+	defer srv.Close()
+
+	newGmailService = func(ctx context.Context, email string) (*gmail.Service, error) {
+		client := &http.Client{Transport: &googleapi.ScopeTransport{
+			Base:     srv.Client().Transport,
+			Service:  "gmail",
+			Email:    email,
+			Required: []string{"https://mail.google.com/"},
+		}}
+		return gmail.NewService(ctx, option.WithoutAuthentication(), option.WithHTTPClient(client), option.WithEndpoint(srv.URL+"/"))
+	}
+
+	var errOut string
+	err := func() error {
+		var runErr error
+		errOut = captureStderr(t, func() {
+			_ = captureStdout(t, func() {
+				runErr = Execute([]string{"--account", "a@b.com", "--no-input", "gmail", "labels", "list"})
+			})
+		})
+		return runErr
+	}()
+	if err == nil || !strings.Contains(errOut, "--auto-consent") {
+		t.Fatalf("expected scope error hint, err=%v stderr=%q", err, errOut)
+	}
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "--auto-consent", "gmail", "labels", "list"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "INBOX") {
+		t.Fatalf("expected retried output, got %q", out)
+	}
+	if !authOpts.ForceConsent || !reflect.DeepEqual(authOpts.Scopes, []string{"https://mail.google.com/", "https://www.googleapis.com/auth/calendar"}) {
+		t.Fatalf("unexpected authorize opts: %#v", authOpts)
+	}
+	tok, _ := store.GetToken("a@b.com")
+	if !reflect.DeepEqual(tok.Services, []string{"calendar", "gmail"}) || len(tok.Scopes) != 2 {
+		t.Fatalf("unexpected stored token: %#v", tok)
+	}
+}
+
+func TestOfferScopeUpgrade_Prompt(t *testing.T) {
+	scopeErr := &googleapi.InsufficientScopeError{Email: "a@b.com", Service: "gmail"}
+	for answer, want := range map[string]bool{"y\n": true, "no\n": false, "": false} {
+		u, err := ui.New(ui.Options{Stdin: strings.NewReader(answer), Stdout: io.Discard, Stderr: io.Discard})
+		if err != nil {
+			t.Fatalf("ui.New: %v", err)
+		}
+		if got := offerScopeUpgrade(u, &rootFlags{}, scopeErr); got != want {
+			t.Fatalf("answer %q = %v, want %v", answer, got, want)
+		}
+		if offerScopeUpgrade(u, &rootFlags{NoInput: true}, scopeErr) {
+			t.Fatalf("--no-input must not prompt")
+		}
+	}
+}
+
+func TestExecute_AutoConsentDoesNotRerunAfterReadingStdin(t *testing.T) {
+	origGmail, origOpen, origAuth, origStdin := newGmailService, openSecretsStore, authorizeGoogle, os.Stdin
+	t.Cleanup(func() {
+		newGmailService = origGmail
+		openSecretsStore = origOpen
+		authorizeGoogle = origAuth
+		os.Stdin = origStdin
+	})
+
+	store := newMemSecretsStore()
+	if err := store.SetToken("a@b.com", secrets.Token{Services: []string{"calendar"}, RefreshToken: "old"}); err != nil {
+		t.Fatalf("SetToken: %v", err)
+	}
+	openSecretsStore = func() (secrets.Store, error) { return store, nil }
+	authorizeGoogle = func(context.Context, googleauth.AuthorizeOptions) (string, error) { return "new", nil }
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"code":403,"message":"Request had insufficient authentication scopes."}}`))
+	}))
+	defer srv.Close()
+	newGmailService = func(ctx context.Context, email string) (*gmail.Service, error) {
+		client := &http.Client{Transport: &googleapi.ScopeTransport{
+			Base:     srv.Client().Transport,
+			Service:  "gmail",
+			Email:    email,
+			Required: []string{"https://mail.google.com/"},
+		}}
+		return gmail.NewService(ctx, option.WithoutAuthentication(), option.WithHTTPClient(client), option.WithEndpoint(srv.URL+"/"))
+	}
+
+	path := filepath.Join(t.TempDir(), "msg.eml")
+	if err := os.WriteFile(path, []byte("Subject: hi\r\n\r\nbody\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	os.Stdin = f
+
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			err = Execute([]string{"--account", "a@b.com", "--auto-consent", "gmail", "import", "-"})
+		})
+	})
+	if err == nil || !strings.Contains(err.Error(), "run it again") {
+		t.Fatalf("expected a run-again error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("import sent %d times, want 1 (no automatic re-run)", calls)
+	}
+	if tok, _ := store.GetToken("a@b.com"); tok.RefreshToken != "new" {
+		t.Fatalf("expected the scopes to be upgraded, got %#v", tok)
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/statefile"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func newSecureCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secure",
+		Short: "Encrypt local state (watch state, caches, indexes) at rest",
+	}
+	cmd.AddCommand(newSecureStatusCmd())
+	cmd.AddCommand(newSecureEnableCmd())
+	cmd.AddCommand(newSecureDisableCmd(flags))
+	return cmd
+}
+
+// secureStateDirs lists every directory whose files are covered by `gog secure`.
+func secureStateDirs() ([]string, error) {
+	stateDir, err := config.StateDir()
+	if err != nil {
+		return nil, err
+	}
+	cacheDir, err := config.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return []string{stateDir, cacheDir}, nil
+}
+
+func newSecureStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether local state is encrypted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			st, err := statefile.LoadSettings()
+			if err != nil {
+				return err
+			}
+			mode := string(st.Mode)
+			if mode == "" {
+				mode = "off"
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"enabled": st.Mode != statefile.ModeOff,
+					"mode":    mode,
+				})
+			}
+			u := ui.FromContext(cmd.Context())
+			u.Out().Printf("enabled\t%t", st.Mode != statefile.ModeOff)
+			u.Out().Printf("mode\t%s", mode)
+			return nil
+		},
+	}
+}
+
+func newSecureEnableCmd() *cobra.Command {
+	var passphrase bool
+
+	cmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Enable encryption and migrate existing plaintext state",
+		Long: `Enable AES-256-GCM encryption for local state files.
+
+By default a random key is stored in the OS keyring. With --passphrase the key
+is derived from a passphrase (prompted on TTY, or read from GOG_STATE_PASSPHRASE).
+Existing plaintext state files are encrypted in place.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			dirs, err := secureStateDirs()
+			if err != nil {
+				return err
+			}
+			mode := statefile.ModeKeyring
+			if passphrase {
+				mode = statefile.ModePassphrase
+			}
+			migrated, err := statefile.Enable(mode, dirs...)
+			if err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"enabled":  true,
+					"mode":     string(mode),
+					"migrated": migrated,
+				})
+			}
+			u := ui.FromContext(cmd.Context())
+			u.Out().Printf("enabled\ttrue")
+			u.Out().Printf("mode\t%s", mode)
+			u.Out().Printf("migrated\t%d", len(migrated))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&passphrase, "passphrase", false, "Derive the key from a passphrase instead of the OS keyring")
+	return cmd
+}
+
+func newSecureDisableCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable",
+		Short: "Decrypt local state and disable encryption",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := confirmDestructive(cmd, flags, "store local state unencrypted"); err != nil {
+				return err
+			}
+			dirs, err := secureStateDirs()
+			if err != nil {
+				return err
+			}
+			decrypted, err := statefile.Disable(dirs...)
+			if err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"enabled":   false,
+					"decrypted": decrypted,
+				})
+			}
+			u := ui.FromContext(cmd.Context())
+			u.Out().Printf("enabled\tfalse")
+			u.Out().Printf("decrypted\t%d", len(decrypted))
+			return nil
+		},
+	}
+}
@@ -1,11 +1,9 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/steipete/gogcli/internal/googleapi"
@@ -35,6 +33,8 @@ func newSheetsCmd(flags *rootFlags) *cobra.Command {
 	cmd.AddCommand(newSheetsCreateCmd(flags))
 	cmd.AddCommand(newSheetsCopyCmd(flags))
 	cmd.AddCommand(newSheetsExportCmd(flags))
+	cmd.AddCommand(newSheetsSnapshotCmd(flags))
+	cmd.AddCommand(newSheetsSnapshotsCmd(flags))
 	return cmd
 }
 
@@ -109,7 +109,7 @@ func newSheetsGetCmd(flags *rootFlags) *cobra.Command {
 				return nil
 			}
 
-			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			tw, flush := tableWriter(cmd.Context())
 			for _, row := range resp.Values {
 				cells := make([]string, len(row))
 				for i, cell := range row {
@@ -117,7 +117,7 @@ func newSheetsGetCmd(flags *rootFlags) *cobra.Command {
 				}
 				fmt.Fprintln(tw, strings.Join(cells, "\t"))
 			}
-			_ = tw.Flush()
+			flush()
 			return nil
 		},
 	}
@@ -129,7 +129,7 @@ func newSheetsGetCmd(flags *rootFlags) *cobra.Command {
 
 func newSheetsUpdateCmd(flags *rootFlags) *cobra.Command {
 	var valueInputOption string
-	var jsonValues string
+	var input sheetsValuesInput
 
 	cmd := &cobra.Command{
 		Use:   "update <spreadsheetId> <range> [values...]",
@@ -143,9 +143,13 @@ Values can be provided as:
 2. JSON via --values-json flag:
    gog sheets update ID 'A1' --values-json '[["a","b"],["c","d"]]'
 
+3. CSV/TSV/JSON file or stdin via --values-file (format from extension or content):
+   cat data.csv | gog sheets update ID 'A1' --values-file -
+
 Examples:
   gog sheets update 1BxiMVs... 'Sheet1!A1' 'Hello|World'
-  gog sheets update 1BxiMVs... 'Sheet1!A1:B2' --values-json '[["a","b"],["c","d"]]'`,
+  gog sheets update 1BxiMVs... 'Sheet1!A1:B2' --values-json '[["a","b"],["c","d"]]'
+  gog sheets update 1BxiMVs... 'Sheet1!A1' --values-file data.tsv`,
 		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			u := ui.FromContext(cmd.Context())
@@ -157,26 +161,9 @@ Examples:
 			spreadsheetID := args[0]
 			rangeSpec := cleanRange(args[1])
 
-			var values [][]interface{}
-
-			if jsonValues != "" {
-				if unmarshalErr := json.Unmarshal([]byte(jsonValues), &values); unmarshalErr != nil {
-					return fmt.Errorf("invalid JSON values: %w", unmarshalErr)
-				}
-			} else if len(args) > 2 {
-				// Parse comma-separated rows, pipe-separated cells
-				rawValues := strings.Join(args[2:], " ")
-				rows := strings.Split(rawValues, ",")
-				for _, row := range rows {
-					cells := strings.Split(strings.TrimSpace(row), "|")
-					rowData := make([]interface{}, len(cells))
-					for i, cell := range cells {
-						rowData[i] = strings.TrimSpace(cell)
-					}
-					values = append(values, rowData)
-				}
-			} else {
-				return fmt.Errorf("provide values as args or via --values-json")
+			values, err := input.values(args[2:])
+			if err != nil {
+				return err
 			}
 
 			svc, err := newSheetsService(cmd.Context(), account)
@@ -188,10 +175,13 @@ Examples:
 				Values: values,
 			}
 
-			call := svc.Spreadsheets.Values.Update(spreadsheetID, rangeSpec, vr)
 			if valueInputOption == "" {
 				valueInputOption = "USER_ENTERED"
 			}
+			if n := input.sanitize(values, valueInputOption); n > 0 {
+				u.Err().Printf("Wrote %d formula-like cells as text (--sanitize-formulas=false to allow formulas)", n)
+			}
+			call := svc.Spreadsheets.Values.Update(spreadsheetID, rangeSpec, vr)
 			call = call.ValueInputOption(valueInputOption)
 
 			resp, err := call.Do()
@@ -214,14 +204,14 @@ Examples:
 	}
 
 	cmd.Flags().StringVar(&valueInputOption, "input", "USER_ENTERED", "Value input option: RAW or USER_ENTERED")
-	cmd.Flags().StringVar(&jsonValues, "values-json", "", "Values as JSON 2D array")
+	input.addFlags(cmd)
 	return cmd
 }
 
 func newSheetsAppendCmd(flags *rootFlags) *cobra.Command {
 	var valueInputOption string
 	var insertDataOption string
-	var jsonValues string
+	var input sheetsValuesInput
 
 	cmd := &cobra.Command{
 		Use:   "append <spreadsheetId> <range> [values...]",
@@ -232,7 +222,8 @@ Values format same as 'update' command.
 
 Examples:
   gog sheets append 1BxiMVs... 'Sheet1!A:C' 'val1|val2|val3'
-  gog sheets append 1BxiMVs... 'Sheet1!A:C' --values-json '[["a","b","c"]]'`,
+  gog sheets append 1BxiMVs... 'Sheet1!A:C' --values-json '[["a","b","c"]]'
+  printf 'a,b,c\n' | gog sheets append 1BxiMVs... 'Sheet1!A:C' --values-file -`,
 		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			u := ui.FromContext(cmd.Context())
@@ -244,25 +235,9 @@ Examples:
 			spreadsheetID := args[0]
 			rangeSpec := cleanRange(args[1])
 
-			var values [][]interface{}
-
-			if jsonValues != "" {
-				if unmarshalErr := json.Unmarshal([]byte(jsonValues), &values); unmarshalErr != nil {
-					return fmt.Errorf("invalid JSON values: %w", unmarshalErr)
-				}
-			} else if len(args) > 2 {
-				rawValues := strings.Join(args[2:], " ")
-				rows := strings.Split(rawValues, ",")
-				for _, row := range rows {
-					cells := strings.Split(strings.TrimSpace(row), "|")
-					rowData := make([]interface{}, len(cells))
-					for i, cell := range cells {
-						rowData[i] = strings.TrimSpace(cell)
-					}
-					values = append(values, rowData)
-				}
-			} else {
-				return fmt.Errorf("provide values as args or via --values-json")
+			values, err := input.values(args[2:])
+			if err != nil {
+				return err
 			}
 
 			svc, err := newSheetsService(cmd.Context(), account)
@@ -274,10 +249,13 @@ Examples:
 				Values: values,
 			}
 
-			call := svc.Spreadsheets.Values.Append(spreadsheetID, rangeSpec, vr)
 			if valueInputOption == "" {
 				valueInputOption = "USER_ENTERED"
 			}
+			if n := input.sanitize(values, valueInputOption); n > 0 {
+				u.Err().Printf("Wrote %d formula-like cells as text (--sanitize-formulas=false to allow formulas)", n)
+			}
+			call := svc.Spreadsheets.Values.Append(spreadsheetID, rangeSpec, vr)
 			call = call.ValueInputOption(valueInputOption)
 			if insertDataOption != "" {
 				call = call.InsertDataOption(insertDataOption)
@@ -304,7 +282,7 @@ Examples:
 
 	cmd.Flags().StringVar(&valueInputOption, "input", "USER_ENTERED", "Value input option: RAW or USER_ENTERED")
 	cmd.Flags().StringVar(&insertDataOption, "insert", "", "Insert data option: OVERWRITE or INSERT_ROWS")
-	cmd.Flags().StringVar(&jsonValues, "values-json", "", "Values as JSON 2D array")
+	input.addFlags(cmd)
 	return cmd
 }
 
@@ -323,6 +301,9 @@ func newSheetsClearCmd(flags *rootFlags) *cobra.Command {
 
 			spreadsheetID := args[0]
 			rangeSpec := cleanRange(args[1])
+			if confirmErr := confirmDestructive(cmd, flags, fmt.Sprintf("clear %s in spreadsheet %s", rangeSpec, spreadsheetID)); confirmErr != nil {
+				return confirmErr
+			}
 
 			svc, err := newSheetsService(cmd.Context(), account)
 			if err != nil {
@@ -389,7 +370,7 @@ func newSheetsMetadataCmd(flags *rootFlags) *cobra.Command {
 			u.Out().Println("")
 			u.Out().Println("Sheets:")
 
-			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			tw, flush := tableWriter(cmd.Context())
 			fmt.Fprintln(tw, "ID\tTITLE\tROWS\tCOLS")
 			for _, sheet := range resp.Sheets {
 				props := sheet.Properties
@@ -400,7 +381,7 @@ func newSheetsMetadataCmd(flags *rootFlags) *cobra.Command {
 					props.GridProperties.ColumnCount,
 				)
 			}
-			_ = tw.Flush()
+			flush()
 			return nil
 		},
 	}
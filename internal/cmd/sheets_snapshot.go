@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+	"google.golang.org/api/drive/v3"
+	gapi "google.golang.org/api/googleapi"
+)
+
+const (
+	sheetsMimeType = "application/vnd.google-apps.spreadsheet"
+	xlsxMimeType   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	// Snapshots are tagged with Drive appProperties so they can be listed
+	// per spreadsheet without a local index.
+	snapshotOfProp    = "gogSnapshotOf"
+	snapshotLabelProp = "gogSnapshotLabel"
+	snapshotAtProp    = "gogSnapshotAt"
+)
+
+// sheetsSnapshot is one snapshot of a spreadsheet.
+type sheetsSnapshot struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Label    string `json:"label,omitempty"`
+	Created  string `json:"created"`
+	Format   string `json:"format"`
+	WebView  string `json:"webViewLink,omitempty"`
+	Original string `json:"spreadsheetId"`
+}
+
+func snapshotFromFile(f *drive.File) sheetsSnapshot {
+	s := sheetsSnapshot{
+		ID:       f.Id,
+		Name:     f.Name,
+		Label:    f.AppProperties[snapshotLabelProp],
+		Created:  f.AppProperties[snapshotAtProp],
+		Format:   "sheet",
+		WebView:  f.WebViewLink,
+		Original: f.AppProperties[snapshotOfProp],
+	}
+	if s.Created == "" {
+		s.Created = f.CreatedTime
+	}
+	if f.MimeType == xlsxMimeType {
+		s.Format = "xlsx"
+	}
+	return s
+}
+
+func newSheetsSnapshotCmd(flags *rootFlags) *cobra.Command {
+	var label string
+	var parent string
+	var asXLSX bool
+
+	cmd := &cobra.Command{
+		Use:   "snapshot <spreadsheetId>",
+		Short: "Snapshot a spreadsheet before risky writes",
+		Long: `Copy a spreadsheet as a snapshot (or, with --xlsx, export it to an .xlsx
+file in Drive), tagged so "sheets snapshots list" finds it and "sheets
+snapshots restore" can roll the spreadsheet back in place (same ID).`,
+		Example: `  gog sheets snapshot <spreadsheetId> --name pre-import
+  gog sheets snapshot <spreadsheetId> --xlsx --parent <folderId>`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			svc, err := newDriveService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			snap, err := snapshotSpreadsheet(cmd.Context(), svc, strings.TrimSpace(args[0]), strings.TrimSpace(label), strings.TrimSpace(parent), asXLSX)
+			if err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"snapshot": snap})
+			}
+			u.Out().Printf("id\t%s", snap.ID)
+			u.Out().Printf("name\t%s", snap.Name)
+			u.Out().Printf("format\t%s", snap.Format)
+			if snap.WebView != "" {
+				u.Out().Printf("link\t%s", snap.WebView)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&label, "name", "", "Snapshot label (e.g. pre-import)")
+	cmd.Flags().StringVar(&parent, "parent", "", "Destination folder ID (default: the spreadsheet's folder)")
+	cmd.Flags().BoolVar(&asXLSX, "xlsx", false, "Store the snapshot as an exported .xlsx file instead of a sheet copy")
+	return cmd
+}
+
+func snapshotSpreadsheet(ctx context.Context, svc *drive.Service, id, label, parent string, asXLSX bool) (sheetsSnapshot, error) {
+	if id == "" {
+		return sheetsSnapshot{}, usage("empty spreadsheetId")
+	}
+	meta, err := svc.Files.Get(id).SupportsAllDrives(true).Fields("id, name, mimeType").Context(ctx).Do()
+	if err != nil {
+		return sheetsSnapshot{}, err
+	}
+	if meta.MimeType != sheetsMimeType {
+		return sheetsSnapshot{}, fmt.Errorf("file is not a Google Sheet (mimeType=%q)", meta.MimeType)
+	}
+
+	at := time.Now().UTC().Format(time.RFC3339)
+	name := meta.Name + " (snapshot"
+	if label != "" {
+		name += " " + label
+	}
+	name += " " + at + ")"
+	req := &drive.File{
+		Name:          name,
+		AppProperties: map[string]string{snapshotOfProp: id, snapshotAtProp: at},
+	}
+	if label != "" {
+		req.AppProperties[snapshotLabelProp] = label
+	}
+	if parent != "" {
+		req.Parents = []string{parent}
+	}
+	const fields = "id, name, mimeType, createdTime, webViewLink, appProperties"
+
+	if !asXLSX {
+		created, err := svc.Files.Copy(id, req).SupportsAllDrives(true).Fields(fields).Context(ctx).Do()
+		if err != nil {
+			return sheetsSnapshot{}, err
+		}
+		return snapshotFromFile(created), nil
+	}
+
+	data, err := readDriveResponse(driveExportDownload(ctx, svc, id, xlsxMimeType))
+	if err != nil {
+		return sheetsSnapshot{}, err
+	}
+	req.Name += ".xlsx"
+	req.MimeType = xlsxMimeType
+	created, err := svc.Files.Create(req).
+		SupportsAllDrives(true).
+		Media(bytes.NewReader(data), gapi.ContentType(xlsxMimeType)).
+		Fields(fields).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return sheetsSnapshot{}, err
+	}
+	return snapshotFromFile(created), nil
+}
+
+func newSheetsSnapshotsCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshots",
+		Short: "List and restore spreadsheet snapshots",
+	}
+	cmd.AddCommand(newSheetsSnapshotsListCmd(flags))
+	cmd.AddCommand(newSheetsSnapshotsRestoreCmd(flags))
+	return cmd
+}
+
+func newSheetsSnapshotsListCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <spreadsheetId>",
+		Short: "List snapshots of a spreadsheet (newest first)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			svc, err := newDriveService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			snaps, err := listSpreadsheetSnapshots(cmd.Context(), svc, strings.TrimSpace(args[0]))
+			if err != nil {
+				return err
+			}
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{"snapshots": snaps})
+			}
+			if len(snaps) == 0 {
+				u.Err().Println("No snapshots")
+				return nil
+			}
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintln(w, "ID\tLABEL\tCREATED\tFORMAT")
+			for _, s := range snaps {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.ID, orDash(s.Label), formatDateTime(s.Created), s.Format)
+			}
+			return nil
+		},
+	}
+}
+
+func listSpreadsheetSnapshots(ctx context.Context, svc *drive.Service, id string) ([]sheetsSnapshot, error) {
+	q := fmt.Sprintf("appProperties has { key='%s' and value='%s' } and trashed = false", snapshotOfProp, escapeDriveQueryString(id))
+	var out []sheetsSnapshot
+	page := ""
+	for {
+		resp, err := svc.Files.List().
+			Q(q).
+			PageSize(100).
+			PageToken(page).
+			OrderBy("createdTime desc").
+			SupportsAllDrives(true).
+			IncludeItemsFromAllDrives(true).
+			Fields("nextPageToken, files(id, name, mimeType, createdTime, webViewLink, appProperties)").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range resp.Files {
+			if f != nil {
+				out = append(out, snapshotFromFile(f))
+			}
+		}
+		if resp.NextPageToken == "" {
+			return out, nil
+		}
+		page = resp.NextPageToken
+	}
+}
+
+func newSheetsSnapshotsRestoreCmd(flags *rootFlags) *cobra.Command {
+	var noBackup bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <spreadsheetId> <snapshotId>",
+		Short: "Roll a spreadsheet back to a snapshot (keeps its ID)",
+		Long: `Replace the spreadsheet's contents with a snapshot, in place: the snapshot
+is uploaded as .xlsx over the spreadsheet, so its ID, sharing and links
+stay the same. The current state is snapshotted first (label
+"pre-restore") unless --no-backup is given.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			id, snapID := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+
+			svc, err := newDriveService(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			snapMeta, err := svc.Files.Get(snapID).
+				SupportsAllDrives(true).
+				Fields("id, name, mimeType, createdTime, appProperties").
+				Context(cmd.Context()).
+				Do()
+			if err != nil {
+				return err
+			}
+			if snapMeta.AppProperties[snapshotOfProp] != id {
+				return usagef("%s is not a snapshot of %s", snapID, id)
+			}
+			if err := confirmDestructive(cmd, flags, fmt.Sprintf("overwrite spreadsheet %s with snapshot %q", id, snapMeta.Name)); err != nil {
+				return err
+			}
+
+			var backup *sheetsSnapshot
+			if !noBackup {
+				b, err := snapshotSpreadsheet(cmd.Context(), svc, id, "pre-restore", "", false)
+				if err != nil {
+					return fmt.Errorf("backup before restore: %w", err)
+				}
+				backup = &b
+			}
+
+			var resp *http.Response
+			if snapMeta.MimeType == sheetsMimeType {
+				resp, err = driveExportDownload(cmd.Context(), svc, snapID, xlsxMimeType)
+			} else {
+				resp, err = driveDownload(cmd.Context(), svc, snapID)
+			}
+			data, err := readDriveResponse(resp, err)
+			if err != nil {
+				return err
+			}
+			if _, err := svc.Files.Update(id, &drive.File{}).
+				SupportsAllDrives(true).
+				Media(bytes.NewReader(data), gapi.ContentType(xlsxMimeType)).
+				Fields("id").
+				Context(cmd.Context()).
+				Do(); err != nil {
+				return err
+			}
+
+			if outfmt.IsJSON(cmd.Context()) {
+				out := map[string]any{"restored": true, "spreadsheetId": id, "snapshot": snapshotFromFile(snapMeta)}
+				if backup != nil {
+					out["backup"] = backup
+				}
+				return outfmt.WriteJSON(os.Stdout, out)
+			}
+			u.Out().Printf("restored\t%s", id)
+			u.Out().Printf("snapshot\t%s", snapID)
+			if backup != nil {
+				u.Out().Printf("backup\t%s", backup.ID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noBackup, "no-backup", false, "Don't snapshot the current state before restoring")
+	return cmd
+}
+
+// readDriveResponse reads a download/export response, turning non-2xx
+// statuses into errors.
+func readDriveResponse(resp *http.Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("download failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestExecute_SheetsSnapshotAndRestore(t *testing.T) {
+	origNew, origExport := newDriveService, driveExportDownload
+	t.Cleanup(func() { newDriveService, driveExportDownload = origNew, origExport })
+
+	var copies []map[string]any
+	var listQuery, uploaded string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/files/s1/copy") && r.Method == http.MethodPost:
+			var req map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			copies = append(copies, req)
+			req["id"] = "snap" + string(rune('0'+len(copies)))
+			req["mimeType"] = sheetsMimeType
+			_ = json.NewEncoder(w).Encode(req)
+		case strings.Contains(path, "/upload/drive/v3/files/s1") && r.Method == http.MethodPatch:
+			body, _ := io.ReadAll(r.Body)
+			uploaded = string(body)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1"})
+		case strings.HasSuffix(path, "/files/s1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1", "name": "Budget", "mimeType": sheetsMimeType})
+		case strings.HasSuffix(path, "/files/snap1"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "snap1", "name": "Budget (snapshot)", "mimeType": sheetsMimeType,
+				"appProperties": map[string]string{snapshotOfProp: "s1", snapshotLabelProp: "pre-import"}})
+		case strings.HasSuffix(path, "/files") && r.Method == http.MethodGet:
+			listQuery = r.URL.Query().Get("q")
+			_ = json.NewEncoder(w).Encode(map[string]any{"files": []map[string]any{{"id": "snap1", "name": "Budget (snapshot)", "mimeType": sheetsMimeType,
+				"appProperties": map[string]string{snapshotOfProp: "s1", snapshotLabelProp: "pre-import", snapshotAtProp: "2026-10-16T10:00:00Z"}}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc, err := drive.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newDriveService = func(context.Context, string) (*drive.Service, error) { return svc, nil }
+	driveExportDownload = func(_ context.Context, _ *drive.Service, id, mimeType string) (*http.Response, error) {
+		if id != "snap1" || mimeType != xlsxMimeType {
+			t.Fatalf("export %s as %s", id, mimeType)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(strings.NewReader("XLSX-SNAPSHOT"))}, nil
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "sheets", "snapshot", "s1", "--name", "pre-import"}); err != nil {
+			t.Fatalf("snapshot: %v", err)
+		}
+	})
+	props, _ := copies[0]["appProperties"].(map[string]any)
+	if props[snapshotOfProp] != "s1" || props[snapshotLabelProp] != "pre-import" || !strings.Contains(out, `"label": "pre-import"`) {
+		t.Fatalf("copy request = %v, out = %s", copies[0], out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--account", "a@b.com", "sheets", "snapshots", "list", "s1"}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(listQuery, "key='gogSnapshotOf' and value='s1'") || !strings.Contains(out, `"id": "snap1"`) {
+		t.Fatalf("query = %q, out = %s", listQuery, out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := Execute([]string{"--json", "--force", "--account", "a@b.com", "sheets", "snapshots", "restore", "s1", "snap1"}); err != nil {
+			t.Fatalf("restore: %v", err)
+		}
+	})
+	if len(copies) != 2 || copies[1]["appProperties"].(map[string]any)[snapshotLabelProp] != "pre-restore" {
+		t.Fatalf("expected pre-restore backup, copies = %v", copies)
+	}
+	if !strings.Contains(uploaded, "XLSX-SNAPSHOT") || !strings.Contains(out, `"restored": true`) {
+		t.Fatalf("uploaded = %q, out = %s", uploaded, out)
+	}
+
+	if err := Execute([]string{"--force", "--account", "a@b.com", "sheets", "snapshots", "restore", "other", "snap1"}); err == nil {
+		t.Fatalf("expected error restoring a snapshot of another spreadsheet")
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// sheetsValuesInput collects the cell values for `sheets update|append` from
+// --values-json, --values-file (CSV/TSV/JSON; "-" reads stdin), or inline args.
+type sheetsValuesInput struct {
+	JSON     string
+	File     string
+	Format   string
+	Sanitize bool
+}
+
+func (in *sheetsValuesInput) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&in.JSON, "values-json", "", "Values as JSON 2D array")
+	cmd.Flags().StringVar(&in.File, "values-file", "", "Read values from a CSV/TSV/JSON file (use - for stdin)")
+	cmd.Flags().StringVar(&in.Format, "values-format", "auto", "Format of --values-file: auto|csv|tsv|json")
+	cmd.Flags().BoolVar(&in.Sanitize, "sanitize-formulas", true, "Write cells starting with = + - @ as text, not formulas (--sanitize-formulas=false to allow formulas)")
+}
+
+// sanitize neutralizes formula-like text cells in place when --sanitize-formulas
+// is on and returns how many it changed. RAW input is never parsed, so it is
+// left alone.
+func (in *sheetsValuesInput) sanitize(values [][]interface{}, valueInputOption string) int {
+	if !in.Sanitize || strings.EqualFold(strings.TrimSpace(valueInputOption), "RAW") {
+		return 0
+	}
+	n := 0
+	for _, row := range values {
+		for i, cell := range row {
+			s, ok := cell.(string)
+			if !ok || !isFormulaLike(s) {
+				continue
+			}
+			// A leading apostrophe makes Sheets store the rest as literal text.
+			row[i] = "'" + s
+			n++
+		}
+	}
+	return n
+}
+
+// isFormulaLike reports whether Sheets would evaluate s as a formula: it
+// starts with = + - or @ and isn't just a signed number like -5 or +1.5.
+func isFormulaLike(s string) bool {
+	if s == "" || !strings.ContainsRune("=+-@", rune(s[0])) {
+		return false
+	}
+	if _, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+		return false
+	}
+	return true
+}
+
+func (in *sheetsValuesInput) values(args []string) ([][]interface{}, error) {
+	sources := 0
+	for _, set := range []bool{in.JSON != "", strings.TrimSpace(in.File) != "", len(args) > 0} {
+		if set {
+			sources++
+		}
+	}
+	switch {
+	case sources == 0:
+		return nil, usage("provide values as args, --values-json, or --values-file PATH|-")
+	case sources > 1:
+		return nil, usage("use only one of [values...], --values-json, or --values-file")
+	}
+
+	if in.JSON != "" {
+		return parseSheetsJSONValues([]byte(in.JSON))
+	}
+	if len(args) > 0 {
+		return parseSheetsArgValues(args), nil
+	}
+
+	var data []byte
+	var err error
+	if in.File == "-" {
+		data, err = readStdin()
+	} else {
+		data, err = os.ReadFile(in.File)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	format := strings.ToLower(strings.TrimSpace(in.Format))
+	if format == "" || format == "auto" {
+		format = detectSheetsValuesFormat(in.File, data)
+	}
+	switch format {
+	case "json":
+		return parseSheetsJSONValues(data)
+	case "csv":
+		return parseSheetsDelimitedValues(data, ',')
+	case "tsv":
+		return parseSheetsDelimitedValues(data, '\t')
+	default:
+		return nil, usagef("invalid --values-format %q (expected auto|csv|tsv|json)", in.Format)
+	}
+}
+
+// parseSheetsArgValues parses comma-separated rows with pipe-separated cells.
+func parseSheetsArgValues(args []string) [][]interface{} {
+	var values [][]interface{}
+	rawValues := strings.Join(args, " ")
+	for _, row := range strings.Split(rawValues, ",") {
+		cells := strings.Split(strings.TrimSpace(row), "|")
+		rowData := make([]interface{}, len(cells))
+		for i, cell := range cells {
+			rowData[i] = strings.TrimSpace(cell)
+		}
+		values = append(values, rowData)
+	}
+	return values
+}
+
+func parseSheetsJSONValues(data []byte) ([][]interface{}, error) {
+	var values [][]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("invalid JSON values: %w", err)
+	}
+	return values, nil
+}
+
+func parseSheetsDelimitedValues(data []byte, comma rune) ([][]interface{}, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = comma == '\t'
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid values: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, usage("no values in input")
+	}
+	values := make([][]interface{}, len(records))
+	for i, rec := range records {
+		row := make([]interface{}, len(rec))
+		for j, cell := range rec {
+			row[j] = cell
+		}
+		values[i] = row
+	}
+	return values, nil
+}
+
+func detectSheetsValuesFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".tsv", ".tab":
+		return "tsv"
+	case ".csv":
+		return "csv"
+	}
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		return "json"
+	}
+	firstLine, _, _ := bytes.Cut(trimmed, []byte("\n"))
+	if bytes.ContainsRune(firstLine, '\t') {
+		return "tsv"
+	}
+	return "csv"
+}
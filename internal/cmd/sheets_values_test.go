@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSheetsValuesInput_Delimited(t *testing.T) {
+	csvValues, err := parseSheetsDelimitedValues([]byte("\ufeffname,note\nAda,\"a, b\"\nBob\n"), ',')
+	if err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	want := [][]interface{}{{"name", "note"}, {"Ada", "a, b"}, {"Bob"}}
+	if !reflect.DeepEqual(csvValues, want) {
+		t.Fatalf("csv: got %#v", csvValues)
+	}
+
+	tsvValues, err := parseSheetsDelimitedValues([]byte("a\tb \"x\"\n1\t2\n"), '\t')
+	if err != nil {
+		t.Fatalf("tsv: %v", err)
+	}
+	want = [][]interface{}{{"a", `b "x"`}, {"1", "2"}}
+	if !reflect.DeepEqual(tsvValues, want) {
+		t.Fatalf("tsv: got %#v", tsvValues)
+	}
+}
+
+func TestDetectSheetsValuesFormat(t *testing.T) {
+	cases := []struct {
+		path string
+		data string
+		want string
+	}{
+		{"data.csv", "a\tb", "csv"},
+		{"data.TSV", "a,b", "tsv"},
+		{"-", `  [["a"]]`, "json"},
+		{"-", "a\tb\nc\td", "tsv"},
+		{"-", "a,b", "csv"},
+	}
+	for _, tc := range cases {
+		if got := detectSheetsValuesFormat(tc.path, []byte(tc.data)); got != tc.want {
+			t.Fatalf("detect(%q, %q) = %q, want %q", tc.path, tc.data, got, tc.want)
+		}
+	}
+}
+
+func TestSheetsValuesInput_Sources(t *testing.T) {
+	in := sheetsValuesInput{JSON: `[["a"]]`}
+	if _, err := in.values([]string{"x"}); err == nil {
+		t.Fatalf("expected error for multiple sources")
+	}
+	if _, err := (&sheetsValuesInput{}).values(nil); err == nil {
+		t.Fatalf("expected error for missing values")
+	}
+	got, err := (&sheetsValuesInput{}).values([]string{"a|b,c|d"})
+	if err != nil || !reflect.DeepEqual(got, [][]interface{}{{"a", "b"}, {"c", "d"}}) {
+		t.Fatalf("args: %#v %v", got, err)
+	}
+}
+
+func TestSheetsValuesInput_SanitizeFormulas(t *testing.T) {
+	values := [][]interface{}{{"=HYPERLINK(\"http://x\")", "+cmd", "-5", "@SUM(A1)", "ok", 3.0, "-1.5e3", "- item"}}
+	in := sheetsValuesInput{Sanitize: true}
+	if n := in.sanitize(values, "USER_ENTERED"); n != 4 {
+		t.Fatalf("sanitized %d cells", n)
+	}
+	want := [][]interface{}{{"'=HYPERLINK(\"http://x\")", "'+cmd", "-5", "'@SUM(A1)", "ok", 3.0, "-1.5e3", "'- item"}}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("got %#v", values)
+	}
+
+	raw := [][]interface{}{{"=1+1"}}
+	if n := in.sanitize(raw, "RAW"); n != 0 || raw[0][0] != "=1+1" {
+		t.Fatalf("RAW input changed: %#v", raw)
+	}
+	off := sheetsValuesInput{}
+	if n := off.sanitize(raw, "USER_ENTERED"); n != 0 {
+		t.Fatalf("disabled sanitize changed %d cells", n)
+	}
+}
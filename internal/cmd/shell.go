@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/statefile"
+	"github.com/steipete/gogcli/internal/ui"
+	"golang.org/x/term"
+)
+
+// maxShellHistory bounds the history kept on disk and in memory.
+const maxShellHistory = 1000
+
+// sessionTokens is set while gog shell runs, so the commands it executes
+// share access tokens.
+var sessionTokens *googleapi.TokenCache
+
+func newShellCmd(flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell",
+		Short: "Interactive prompt for running gog commands",
+		Long: `Start an interactive prompt that runs gog commands in one process, so
+access tokens are fetched once instead of per command.
+
+Type commands without the leading "gog". Tab completes commands and flags;
+up/down walk the history (kept in the state dir). Built-ins:
+
+  use <email>   run following commands as this account (use - to clear)
+  exit, quit    leave the shell (or Ctrl-D)
+
+The account from --account/GOG_ACCOUNT is the initial selection. When stdin
+is not a terminal, commands are read one per line.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			if sessionTokens != nil {
+				return usage("already in gog shell")
+			}
+			sessionTokens = googleapi.NewTokenCache()
+			defer func() { sessionTokens = nil }()
+
+			s := &gogShell{account: policyAccount(flags), root: cmd.Root(), u: u}
+			if fd := int(os.Stdin.Fd()); !flags.NoInput && term.IsTerminal(fd) {
+				return s.runInteractive(fd)
+			}
+			return s.runScript(os.Stdin)
+		},
+	}
+}
+
+type gogShell struct {
+	account string
+	root    *cobra.Command
+	u       *ui.UI
+}
+
+func (s *gogShell) prompt() string {
+	if s.account != "" {
+		return fmt.Sprintf("gog(%s)> ", s.account)
+	}
+	return "gog> "
+}
+
+func (s *gogShell) runInteractive(fd int) error {
+	hist := loadShellHistory()
+	var restore func()
+	raw := func() error {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		restore = func() { _ = term.Restore(fd, state) }
+		return nil
+	}
+	if err := raw(); err != nil {
+		return err
+	}
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, s.prompt())
+	t.History = hist
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' || pos != len(line) {
+			return "", 0, false
+		}
+		completed, ok := completeShellLine(s.root, line)
+		return completed, len(completed), ok
+	}
+	if w, h, err := term.GetSize(fd); err == nil {
+		_ = t.SetSize(w, h)
+	}
+
+	for {
+		t.SetPrompt(s.prompt())
+		line, err := t.ReadLine()
+		if err != nil {
+			restore()
+			if errors.Is(err, io.EOF) {
+				fmt.Fprintln(os.Stdout)
+				return nil
+			}
+			return err
+		}
+		restore()
+		done := s.runLine(line)
+		if done {
+			return nil
+		}
+		if err := raw(); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *gogShell) runScript(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		if s.runLine(sc.Text()) {
+			return nil
+		}
+	}
+	return sc.Err()
+}
+
+// runLine runs one shell line and reports whether the shell should exit.
+// Command errors are already printed by Execute, so the shell carries on.
+func (s *gogShell) runLine(line string) bool {
+	args, err := splitShellWords(line)
+	if err != nil {
+		s.u.Err().Error(err.Error())
+		return false
+	}
+	if len(args) > 0 && args[0] == "gog" {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "use":
+		switch {
+		case len(args) != 2:
+			s.u.Err().Error("usage: use <email> (or use - to clear)")
+		case args[1] == "-":
+			s.account = ""
+		default:
+			s.account = strings.TrimSpace(args[1])
+		}
+		return false
+	case "shell":
+		s.u.Err().Error("already in gog shell")
+		return false
+	}
+
+	first := args[0]
+	if s.account != "" && !hasAccountFlag(args) {
+		args = append([]string{"--account", s.account}, args...)
+	}
+	_ = Execute(args)
+	if first == "auth" {
+		// Stored refresh tokens may have changed.
+		sessionTokens.Reset()
+	}
+	return false
+}
+
+func hasAccountFlag(args []string) bool {
+	for _, a := range args {
+		if a == "--" {
+			return false
+		}
+		if a == "--account" || strings.HasPrefix(a, "--account=") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitShellWords splits a line into words, honouring single and double
+// quotes and backslash escapes (outside single quotes).
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+// completeShellLine completes the last word of line against the command
+// tree: subcommands, or flags when the word starts with "-". A unique match
+// is completed with a trailing space, several to their common prefix.
+func completeShellLine(root *cobra.Command, line string) (string, bool) {
+	words := strings.Fields(line)
+	partial := ""
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	cmd := root
+	for _, w := range words {
+		if strings.HasPrefix(w, "-") {
+			continue
+		}
+		next := shellSubcommand(cmd, w)
+		if next == nil {
+			break
+		}
+		cmd = next
+	}
+
+	var candidates []string
+	if strings.HasPrefix(partial, "-") {
+		add := func(f *pflag.Flag) {
+			if !f.Hidden && strings.HasPrefix("--"+f.Name, partial) {
+				candidates = append(candidates, "--"+f.Name)
+			}
+		}
+		cmd.LocalFlags().VisitAll(add)
+		cmd.InheritedFlags().VisitAll(add)
+	} else {
+		for _, c := range cmd.Commands() {
+			if c.IsAvailableCommand() && strings.HasPrefix(c.Name(), partial) {
+				candidates = append(candidates, c.Name())
+			}
+		}
+		if cmd == root {
+			for _, b := range []string{"exit", "quit", "use"} {
+				if strings.HasPrefix(b, partial) {
+					candidates = append(candidates, b)
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.Strings(candidates)
+	head := strings.TrimSuffix(line, partial)
+	if len(candidates) == 1 {
+		return head + candidates[0] + " ", true
+	}
+	prefix := candidates[0]
+	for _, c := range candidates[1:] {
+		for !strings.HasPrefix(c, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(prefix) <= len(partial) {
+		return "", false
+	}
+	return head + prefix, true
+}
+
+func shellSubcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, c := range cmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// shellHistory is the term.History for gog shell. The history file is a
+// state file (encrypted after gog secure enable), rewritten whole on each
+// new line.
+type shellHistory struct {
+	lines []string // oldest first
+	path  string
+}
+
+func loadShellHistory() *shellHistory {
+	h := &shellHistory{}
+	path, err := config.ShellHistoryPath()
+	if err != nil {
+		return h
+	}
+	h.path = path
+	h.lines = readShellHistory(path)
+	return h
+}
+
+// readShellHistory returns the last maxShellHistory lines of the history
+// file; a missing or unreadable file is an empty history.
+func readShellHistory(path string) []string {
+	data, err := statefile.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("ignoring shell history", "path", path, "err", err)
+		}
+		return nil
+	}
+	var lines []string
+	for _, l := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > maxShellHistory {
+		lines = lines[len(lines)-maxShellHistory:]
+	}
+	return lines
+}
+
+func (h *shellHistory) Add(entry string) {
+	if strings.TrimSpace(entry) == "" || len(h.lines) > 0 && h.lines[len(h.lines)-1] == entry {
+		return
+	}
+	h.lines = append(h.lines, entry)
+	if len(h.lines) > maxShellHistory {
+		h.lines = h.lines[1:]
+	}
+	if h.path == "" {
+		return
+	}
+	// Re-read so lines added by other shells since the start are kept.
+	lines := append(readShellHistory(h.path), entry)
+	if len(lines) > maxShellHistory {
+		lines = lines[len(lines)-maxShellHistory:]
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return
+	}
+	if err := statefile.WriteFile(h.path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		slog.Warn("shell history write failed", "path", h.path, "err", err)
+	}
+}
+
+func (h *shellHistory) Len() int { return len(h.lines) }
+
+func (h *shellHistory) At(idx int) string { return h.lines[len(h.lines)-1-idx] }
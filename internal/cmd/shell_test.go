@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/statefile"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	got, err := splitShellWords(`gmail search 'from:ada subject:"q3 plan"' --max 5 a\ b ""`)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	want := []string{"gmail", "search", `from:ada subject:"q3 plan"`, "--max", "5", "a b", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q", got)
+	}
+	if _, err := splitShellWords(`send --body "oops`); err == nil {
+		t.Fatalf("expected unterminated quote error")
+	}
+}
+
+func TestCompleteShellLine(t *testing.T) {
+	root := &cobra.Command{Use: "gog"}
+	root.PersistentFlags().String("account", "", "")
+	gmailCmd := &cobra.Command{Use: "gmail"}
+	for _, name := range []string{"search", "send", "sendas", "thread"} {
+		c := &cobra.Command{Use: name, Run: func(*cobra.Command, []string) {}}
+		c.Flags().Int64("max", 10, "")
+		gmailCmd.AddCommand(c)
+	}
+	root.AddCommand(gmailCmd, &cobra.Command{Use: "drive", Run: func(*cobra.Command, []string) {}})
+
+	for line, want := range map[string]string{
+		"gm":                    "gmail ",
+		"gmail th":              "gmail thread ",
+		"gmail se":              "gmail se",
+		"gmail sen":             "gmail send",
+		"gmail search --m":      "gmail search --max ",
+		"gmail search --acc":    "gmail search --account ",
+		"--account a@b.com dri": "--account a@b.com drive ",
+		"ex":                    "exit ",
+	} {
+		got, ok := completeShellLine(root, line)
+		if want == line {
+			if ok {
+				t.Fatalf("%q: expected no completion, got %q", line, got)
+			}
+			continue
+		}
+		if !ok || got != want {
+			t.Fatalf("%q: got %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestExecute_ShellScript(t *testing.T) {
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	t.Setenv("GOG_STATE_DIR", t.TempDir())
+
+	var accounts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX", "type": "system"}}})
+	}))
+	defer srv.Close()
+	svc, err := gmail.NewService(context.Background(), option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newGmailService = func(_ context.Context, email string) (*gmail.Service, error) {
+		accounts = append(accounts, email)
+		return svc, nil
+	}
+
+	script := "gmail labels list\nuse b@example.com\n\ngog gmail labels list --json\nshell\nexit\ngmail labels list\n"
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			withStdin(t, script, func() {
+				if err := Execute([]string{"--account", "a@example.com", "shell"}); err != nil {
+					t.Fatalf("shell: %v", err)
+				}
+			})
+		})
+	})
+	if strings.Join(accounts, ",") != "a@example.com,b@example.com" {
+		t.Fatalf("accounts = %v", accounts)
+	}
+	if !strings.Contains(out, "INBOX") || !strings.Contains(out, `"labels"`) {
+		t.Fatalf("out = %q", out)
+	}
+	if sessionTokens != nil {
+		t.Fatalf("token cache should be cleared after the shell exits")
+	}
+}
+
+func TestShellHistory_EncryptedState(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	stateDir := t.TempDir()
+	t.Setenv("GOG_STATE_DIR", stateDir)
+	t.Setenv(statefile.PassphraseEnv, "history-test")
+
+	h := loadShellHistory()
+	h.Add("gmail search is:unread")
+	if _, err := statefile.Enable(statefile.ModePassphrase, stateDir); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	t.Cleanup(func() { _, _ = statefile.Disable(stateDir) })
+
+	h = loadShellHistory()
+	h.Add("calendar events")
+	h.Add("calendar events") // repeated lines are not stored twice
+
+	path, err := config.ShellHistoryPath()
+	if err != nil {
+		t.Fatalf("ShellHistoryPath: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !statefile.IsEncrypted(raw) || strings.Contains(string(raw), "calendar") {
+		t.Fatalf("history not sealed: %q", raw)
+	}
+
+	h = loadShellHistory()
+	if h.Len() != 2 || h.At(0) != "calendar events" || h.At(1) != "gmail search is:unread" {
+		t.Fatalf("unexpected history: %q", h.lines)
+	}
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/statefile"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// usageLogRetention bounds how far back `gog stats --since` reaches.
+const usageLogRetention = 30 * 24 * time.Hour
+
+// usageRun is one invocation that talked to Google APIs.
+type usageRun struct {
+	Time    time.Time            `json:"time"`
+	Account string               `json:"account,omitempty"`
+	Command string               `json:"command"`
+	APIs    []googleapi.APIUsage `json:"apis"`
+}
+
+// usageNow is swapped in tests.
+var usageNow = time.Now
+
+func loadUsageLog() ([]usageRun, error) {
+	path, err := config.UsageLogPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := statefile.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []usageRun
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var r usageRun
+		if err := json.Unmarshal(line, &r); err != nil {
+			continue // a torn line from an interrupted write
+		}
+		out = append(out, r)
+	}
+	return out, sc.Err()
+}
+
+// appendUsageRun adds r to the usage log, dropping runs past the retention.
+func appendUsageRun(r usageRun) error {
+	runs, err := loadUsageLog()
+	if err != nil {
+		return err
+	}
+	cutoff := usageNow().Add(-usageLogRetention)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, old := range append(runs, r) {
+		if old.Time.Before(cutoff) {
+			continue
+		}
+		if err := enc.Encode(old); err != nil {
+			return err
+		}
+	}
+	path, err := config.UsageLogPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return statefile.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+// finishRunStats prints the run's API counts to stderr with --stats and
+// records them in the usage log. Runs without API calls are not recorded.
+func finishRunStats(cmd *cobra.Command, flags *rootFlags, stats *googleapi.UsageStats) {
+	apis := stats.Snapshot()
+	if len(apis) == 0 {
+		return
+	}
+	if flags.Stats {
+		if u := ui.FromContext(cmd.Context()); u != nil {
+			for _, a := range apis {
+				u.Err().Printf("stats\t%s\tcalls=%d retries=%d rate_limited=%d errors=%d", a.API, a.Calls, a.Retries, a.RateLimited, a.Errors)
+			}
+		}
+	}
+	run := usageRun{
+		Time:    usageNow().UTC(),
+		Account: policyAccount(flags),
+		Command: strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		APIs:    apis,
+	}
+	if err := appendUsageRun(run); err != nil {
+		slog.Warn("usage log write failed", "err", err)
+	}
+}
+
+// usageRow aggregates the recorded runs under one api, command or account.
+type usageRow struct {
+	Key         string `json:"key"`
+	Runs        int    `json:"runs"`
+	Calls       int64  `json:"calls"`
+	Retries     int64  `json:"retries"`
+	RateLimited int64  `json:"rateLimited"`
+	Errors      int64  `json:"errors"`
+}
+
+func aggregateUsage(runs []usageRun, by string) []usageRow {
+	rows := map[string]*usageRow{}
+	for _, r := range runs {
+		seen := map[string]bool{}
+		for _, a := range r.APIs {
+			key := a.API
+			switch by {
+			case "command":
+				key = r.Command
+			case "account":
+				key = orDash(r.Account)
+			}
+			row, ok := rows[key]
+			if !ok {
+				row = &usageRow{Key: key}
+				rows[key] = row
+			}
+			if !seen[key] {
+				seen[key] = true
+				row.Runs++
+			}
+			row.Calls += a.Calls
+			row.Retries += a.Retries
+			row.RateLimited += a.RateLimited
+			row.Errors += a.Errors
+		}
+	}
+	out := make([]usageRow, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, *row)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Calls != out[j].Calls {
+			return out[i].Calls > out[j].Calls
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func newStatsCmd(flags *rootFlags) *cobra.Command {
+	var since, by string
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show Google API usage (calls, retries, rate limits) of recent runs",
+		Long: `Every gog run that calls Google APIs records its request count, retries,
+429 (rate limited) responses and other errors per API in a local log
+(<state>/usage.jsonl, kept 30 days). gog stats sums them up per API,
+command or account, so you can see where quota is going.
+
+--stats on any command prints that run's counts to stderr.`,
+		Example: `  gog stats --since 7d
+  gog stats --by command
+  gog --stats gmail search 'newer_than:1d'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			by = strings.ToLower(strings.TrimSpace(by))
+			if by != "api" && by != "command" && by != "account" {
+				return usagef("invalid --by %q (expected api|command|account)", by)
+			}
+			window, err := parseFollowupDelay(since)
+			if err != nil {
+				return usagef("invalid --since %q (use e.g. 24h, 7d, 2w)", since)
+			}
+			cutoff := usageNow().Add(-window)
+			all, err := loadUsageLog()
+			if err != nil {
+				return err
+			}
+			account := strings.TrimSpace(flags.Account)
+			var runs []usageRun
+			for _, r := range all {
+				if r.Time.Before(cutoff) || account != "" && !strings.EqualFold(r.Account, account) {
+					continue
+				}
+				runs = append(runs, r)
+			}
+			rows := aggregateUsage(runs, by)
+
+			if outfmt.IsJSON(cmd.Context()) {
+				return outfmt.WriteJSON(os.Stdout, map[string]any{
+					"since": cutoff.UTC().Format(time.RFC3339),
+					"by":    by,
+					"runs":  len(runs),
+					"rows":  rows,
+				})
+			}
+			if len(rows) == 0 {
+				u.Err().Printf("No API usage recorded in the last %s", since)
+				return nil
+			}
+			w, flush := tableWriter(cmd.Context())
+			defer flush()
+			fmt.Fprintf(w, "%s\tRUNS\tCALLS\tRETRIES\tRATE_LIMITED\tERRORS\n", strings.ToUpper(by))
+			for _, row := range rows {
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n", row.Key, row.Runs, row.Calls, row.Retries, row.RateLimited, row.Errors)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "7d", "Time window (e.g. 24h, 7d; at most 30d are kept)")
+	cmd.Flags().StringVar(&by, "by", "api", "Group by: api|command|account")
+	return cmd
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestAggregateUsage(t *testing.T) {
+	runs := []usageRun{
+		{Account: "a@b.com", Command: "gmail search", APIs: []googleapi.APIUsage{{API: "gmail", Calls: 10, Retries: 1}}},
+		{Account: "a@b.com", Command: "drive ls", APIs: []googleapi.APIUsage{{API: "drive", Calls: 2}, {API: "gmail", Calls: 1, RateLimited: 1}}},
+	}
+	apis := aggregateUsage(runs, "api")
+	if len(apis) != 2 || apis[0] != (usageRow{Key: "gmail", Runs: 2, Calls: 11, Retries: 1, RateLimited: 1}) {
+		t.Fatalf("by api = %+v", apis)
+	}
+	accounts := aggregateUsage(runs, "account")
+	if len(accounts) != 1 || accounts[0].Runs != 2 || accounts[0].Calls != 13 {
+		t.Fatalf("by account = %+v", accounts)
+	}
+}
+
+func TestExecute_Stats(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+	t.Setenv("GOG_STATE_DIR", t.TempDir())
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	origNow := usageNow
+	t.Cleanup(func() { usageNow = origNow })
+
+	usageNow = func() time.Time { return now.Add(-10 * 24 * time.Hour) }
+	if err := appendUsageRun(usageRun{Time: usageNow(), Command: "drive ls", APIs: []googleapi.APIUsage{{API: "drive", Calls: 50}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	usageNow = func() time.Time { return now }
+	if err := appendUsageRun(usageRun{Time: now.Add(-time.Hour), Account: "a@b.com", Command: "gmail search", APIs: []googleapi.APIUsage{{API: "gmail", Calls: 4, Retries: 2}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := Execute([]string{"--json", "stats", "--since", "7d", "--by", "command"}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	})
+	var got struct {
+		Runs int        `json:"runs"`
+		Rows []usageRow `json:"rows"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if got.Runs != 1 || len(got.Rows) != 1 || got.Rows[0].Key != "gmail search" || got.Rows[0].Retries != 2 {
+		t.Fatalf("out = %s", out)
+	}
+
+	if err := Execute([]string{"stats", "--by", "nope"}); err == nil || !strings.Contains(err.Error(), "--by") {
+		t.Fatalf("expected --by error, got %v", err)
+	}
+}
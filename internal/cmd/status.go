@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/statefile"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const defaultStatusFormat = "{unread} ✉ {next_event_in}"
+
+var statusPlaceholderRe = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// statusPlaceholders maps each --format placeholder to the data it needs.
+var statusPlaceholders = map[string]string{
+	"account":       "",
+	"unread":        "gmail",
+	"next_event":    "calendar",
+	"next_event_in": "calendar",
+	"next_event_at": "calendar",
+}
+
+// statusCache is what gog status keeps per account between runs.
+type statusCache struct {
+	Account     string        `json:"account"`
+	FetchedAt   time.Time     `json:"fetchedAt"`
+	RefreshedAt time.Time     `json:"refreshStartedAt,omitempty"`
+	HasUnread   bool          `json:"hasUnread,omitempty"`
+	Unread      int64         `json:"unread"`
+	HasEvents   bool          `json:"hasEvents,omitempty"`
+	Events      []statusEvent `json:"events,omitempty"`
+}
+
+type statusEvent struct {
+	Summary string    `json:"summary"`
+	Start   time.Time `json:"start"`
+}
+
+// statusSpawnRefresh starts a detached `gog status --max-age 0` with args
+// (see statusRefreshArgs) that rewrites the cache; tests replace it.
+var statusSpawnRefresh = func(args []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return exec.Command(exe, args...).Start()
+}
+
+// statusRefreshArgs builds the background refresh command line, carrying
+// over the global flags that pick the credentials and reach the API; GOG_*
+// environment variables are inherited anyway.
+func statusRefreshArgs(flags *rootFlags, account, format string) []string {
+	args := []string{"--account", account}
+	for _, f := range []struct{ name, value string }{
+		{"--token-store", flags.TokenStore},
+		{"--sa-key", flags.SAKey},
+		{"--impersonate", flags.Impersonate},
+		{"--proxy", flags.Proxy},
+		{"--ca-bundle", flags.CABundle},
+	} {
+		if strings.TrimSpace(f.value) != "" {
+			args = append(args, f.name, f.value)
+		}
+	}
+	if flags.InsecureSkipVerify {
+		args = append(args, "--insecure-skip-verify")
+	}
+	for _, e := range flags.Endpoints {
+		args = append(args, "--endpoint", e)
+	}
+	return append(args, "status", "--max-age", "0", "--format", format)
+}
+
+func newStatusCmd(flags *rootFlags) *cobra.Command {
+	var format string
+	var maxAge time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "One-line status for shell prompts and status bars",
+		Long: `Print a single compact line for shell prompts, tmux status bars and window
+managers. Placeholders: {unread} (unread in Inbox), {next_event} (title),
+{next_event_in} (e.g. 25m, 2h05m), {next_event_at} (HH:MM) and {account}.
+
+Results are cached per account. Within --max-age the line is rendered from
+the cache without any network calls; once stale, the cached line is printed
+immediately and a background "gog status" refreshes the cache. Only a cold
+cache (or --max-age 0) waits for the APIs. {next_event_in} is always
+computed from the cached start times, so it stays current.`,
+		Example: `  gog status
+  gog status --format '{unread}✉ {next_event} in {next_event_in}'
+  set -g status-right '#(gog status)'   # tmux`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			u := ui.FromContext(cmd.Context())
+			account, err := requireAccount(flags)
+			if err != nil {
+				return err
+			}
+			needs, err := statusNeeds(format)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			cache, cacheErr := loadStatusCache(account)
+			switch {
+			case cacheErr != nil || !cache.covers(needs) || maxAge <= 0:
+				cache, err = refreshStatusCache(cmd.Context(), account, needs, now)
+				if err != nil {
+					return err
+				}
+			case now.Sub(cache.FetchedAt) > maxAge && now.Sub(cache.RefreshedAt) > time.Minute:
+				// Serve the stale line now; one refresh at a time in the background.
+				cache.RefreshedAt = now
+				_ = saveStatusCache(cache)
+				if spawnErr := statusSpawnRefresh(statusRefreshArgs(flags, account, format)); spawnErr != nil && flags.Verbose {
+					u.Err().Printf("WARN: status refresh: %v", spawnErr)
+				}
+			}
+
+			values := statusValues(cache, now)
+			if outfmt.IsJSON(cmd.Context()) {
+				out := map[string]any{"line": renderStatus(format, values), "fetchedAt": cache.FetchedAt}
+				for k, v := range values {
+					out[k] = v
+				}
+				return outfmt.WriteJSON(os.Stdout, out)
+			}
+			u.Out().Println(renderStatus(format, values))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", defaultStatusFormat, "Line template ({unread}, {next_event}, {next_event_in}, {next_event_at}, {account})")
+	cmd.Flags().DurationVar(&maxAge, "max-age", 2*time.Minute, "Serve cached data this fresh without refreshing (0 = always fetch)")
+	return cmd
+}
+
+// statusNeeds validates the placeholders in format and returns the data
+// sources they need.
+func statusNeeds(format string) (map[string]bool, error) {
+	needs := map[string]bool{}
+	for _, m := range statusPlaceholderRe.FindAllStringSubmatch(format, -1) {
+		src, ok := statusPlaceholders[m[1]]
+		if !ok {
+			return nil, usagef("unknown placeholder {%s} in --format", m[1])
+		}
+		if src != "" {
+			needs[src] = true
+		}
+	}
+	return needs, nil
+}
+
+func (c statusCache) covers(needs map[string]bool) bool {
+	return (!needs["gmail"] || c.HasUnread) && (!needs["calendar"] || c.HasEvents)
+}
+
+func refreshStatusCache(ctx context.Context, account string, needs map[string]bool, now time.Time) (statusCache, error) {
+	c := statusCache{Account: account, FetchedAt: now}
+	if needs["gmail"] {
+		svc, err := newGmailService(ctx, account)
+		if err != nil {
+			return c, err
+		}
+		inbox, err := svc.Users.Labels.Get("me", "INBOX").Context(ctx).Do()
+		if err != nil {
+			return c, err
+		}
+		c.HasUnread, c.Unread = true, inbox.MessagesUnread
+	}
+	if needs["calendar"] {
+		svc, err := newCalendarService(ctx, account)
+		if err != nil {
+			return c, err
+		}
+		resp, err := svc.Events.List("primary").
+			TimeMin(now.Format(time.RFC3339)).
+			TimeMax(now.Add(24 * time.Hour).Format(time.RFC3339)).
+			SingleEvents(true).
+			OrderBy("startTime").
+			MaxResults(10).
+			Fields("items(summary,start)").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return c, err
+		}
+		c.HasEvents = true
+		for _, e := range resp.Items {
+			// All-day events have no start time worth counting down to.
+			if e == nil || e.Start == nil || e.Start.DateTime == "" {
+				continue
+			}
+			start, err := time.Parse(time.RFC3339, e.Start.DateTime)
+			if err != nil {
+				continue
+			}
+			c.Events = append(c.Events, statusEvent{Summary: e.Summary, Start: start})
+		}
+	}
+	if err := saveStatusCache(c); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// statusValues resolves every placeholder; the next event is the first
+// cached one that hasn't started yet.
+func statusValues(c statusCache, now time.Time) map[string]string {
+	values := map[string]string{
+		"account":       c.Account,
+		"unread":        strconv.FormatInt(c.Unread, 10),
+		"next_event":    "",
+		"next_event_in": "",
+		"next_event_at": "",
+	}
+	for _, e := range c.Events {
+		if e.Start.After(now) {
+			values["next_event"] = e.Summary
+			values["next_event_in"] = formatStatusDuration(e.Start.Sub(now))
+			values["next_event_at"] = e.Start.Local().Format("15:04")
+			break
+		}
+	}
+	return values
+}
+
+func renderStatus(format string, values map[string]string) string {
+	line := statusPlaceholderRe.ReplaceAllStringFunc(format, func(m string) string {
+		return values[m[1:len(m)-1]]
+	})
+	return strings.TrimSpace(line)
+}
+
+// formatStatusDuration is a compact countdown: 45s, 25m, 2h05m.
+func formatStatusDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+func statusCachePath(account string) (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "status", avatarKey(account)+".json"), nil
+}
+
+func loadStatusCache(account string) (statusCache, error) {
+	path, err := statusCachePath(account)
+	if err != nil {
+		return statusCache{}, err
+	}
+	data, err := statefile.ReadFile(path)
+	if err != nil {
+		return statusCache{}, err
+	}
+	var c statusCache
+	if err := json.Unmarshal(data, &c); err != nil {
+		return statusCache{}, err
+	}
+	return c, nil
+}
+
+func saveStatusCache(c statusCache) error {
+	path, err := statusCachePath(c.Account)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return statefile.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestRenderStatus(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	c := statusCache{Account: "a@b.com", Unread: 4, Events: []statusEvent{
+		{Summary: "Past", Start: now.Add(-time.Hour)},
+		{Summary: "Standup", Start: now.Add(2*time.Hour + 5*time.Minute)},
+	}}
+	got := renderStatus("{unread} ✉ {next_event} in {next_event_in}", statusValues(c, now))
+	if got != "4 ✉ Standup in 2h05m" {
+		t.Fatalf("line = %q", got)
+	}
+	if got := renderStatus(defaultStatusFormat, statusValues(statusCache{Unread: 1}, now)); got != "1 ✉" {
+		t.Fatalf("no events: %q", got)
+	}
+	if _, err := statusNeeds("{bogus}"); err == nil {
+		t.Fatalf("expected unknown placeholder error")
+	}
+	if d := formatStatusDuration(25*time.Minute + 30*time.Second); d != "25m" {
+		t.Fatalf("duration = %q", d)
+	}
+}
+
+func TestExecute_StatusCaches(t *testing.T) {
+	origGmail, origCal, origSpawn := newGmailService, newCalendarService, statusSpawnRefresh
+	t.Cleanup(func() {
+		newGmailService, newCalendarService, statusSpawnRefresh = origGmail, origCal, origSpawn
+	})
+	t.Setenv("GOG_CACHE_DIR", t.TempDir())
+
+	var calls atomic.Int32
+	start := time.Now().Add(30 * time.Minute).UTC().Format(time.RFC3339)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/labels/INBOX"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "INBOX", "messagesUnread": 7})
+		case strings.HasSuffix(r.URL.Path, "/calendars/primary/events"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{
+				{"summary": "All day", "start": map[string]any{"date": "2025-03-01"}},
+				{"summary": "Sync", "start": map[string]any{"dateTime": start}},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	opts := []option.ClientOption{option.WithoutAuthentication(), option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL + "/")}
+	gsvc, err := gmail.NewService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("gmail: %v", err)
+	}
+	csvc, err := calendar.NewService(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("calendar: %v", err)
+	}
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return gsvc, nil }
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return csvc, nil }
+	var spawned atomic.Int32
+	var spawnArgs []string
+	statusSpawnRefresh = func(args []string) error { spawned.Add(1); spawnArgs = args; return nil }
+
+	run := func(args ...string) string {
+		return captureStdout(t, func() {
+			if err := Execute(append([]string{"--account", "a@b.com", "status"}, args...)); err != nil {
+				t.Fatalf("status: %v", err)
+			}
+		})
+	}
+
+	if out := run("--format", "{unread} {next_event}"); strings.TrimSpace(out) != "7 Sync" {
+		t.Fatalf("cold = %q", out)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("cold fetch calls = %d", calls.Load())
+	}
+	if out := run("--format", "{unread} {next_event}"); strings.TrimSpace(out) != "7 Sync" || calls.Load() != 2 {
+		t.Fatalf("warm = %q calls=%d", out, calls.Load())
+	}
+
+	// Stale: the cached line is served and one background refresh starts.
+	c, err := loadStatusCache("a@b.com")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	c.FetchedAt = c.FetchedAt.Add(-time.Hour)
+	if err := saveStatusCache(c); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	_ = captureStdout(t, func() {
+		if err := Execute([]string{"--account", "a@b.com", "--token-store", "file", "--endpoint", "gmail=http://127.0.0.1:1", "status", "--format", "{unread}"}); err != nil {
+			t.Fatalf("status: %v", err)
+		}
+	})
+	_ = run("--format", "{unread}")
+	if spawned.Load() != 1 || calls.Load() != 2 {
+		t.Fatalf("stale: spawned=%d calls=%d", spawned.Load(), calls.Load())
+	}
+	want := []string{"--account", "a@b.com", "--token-store", "file", "--endpoint", "gmail=http://127.0.0.1:1", "status", "--max-age", "0", "--format", "{unread}"}
+	if !reflect.DeepEqual(spawnArgs, want) {
+		t.Fatalf("refresh args = %q, want %q", spawnArgs, want)
+	}
+}
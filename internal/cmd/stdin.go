@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// stdinUsed records that the running command consumed stdin (`-` as an input
+// file), so a scope-upgrade re-run, which would find it empty, is not tried.
+var stdinUsed atomic.Bool
+
+// readStdin reads all of stdin.
+func readStdin() ([]byte, error) {
+	stdinUsed.Store(true)
+	return io.ReadAll(os.Stdin)
+}
+
+// stdinReader returns stdin for streaming input.
+func stdinReader() io.Reader {
+	stdinUsed.Store(true)
+	return os.Stdin
+}
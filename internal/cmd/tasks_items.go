@@ -14,6 +14,7 @@ import (
 func newTasksListCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 	var showCompleted bool
 	var showDeleted bool
 	var showHidden bool
@@ -67,19 +68,29 @@ func newTasksListCmd(flags *rootFlags) *cobra.Command {
 				call = call.UpdatedMin(strings.TrimSpace(updatedMin))
 			}
 
-			resp, err := call.Do()
+			items, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*tasks.Task, string, error) {
+				resp, err := call.PageToken(pageToken).Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Items, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(items))
 			if err != nil {
 				return err
 			}
 
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
-					"tasks":         resp.Items,
-					"nextPageToken": resp.NextPageToken,
-				})
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"tasks":         items,
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
 
-			if len(resp.Items) == 0 {
+			if len(items) == 0 {
 				u.Err().Println("No tasks")
 				return nil
 			}
@@ -87,20 +98,21 @@ func newTasksListCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tDUE\tUPDATED")
-			for _, t := range resp.Items {
+			for _, t := range items {
 				status := strings.TrimSpace(t.Status)
 				if status == "" {
 					status = "needsAction"
 				}
 				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Id, t.Title, status, strings.TrimSpace(t.Due), strings.TrimSpace(t.Updated))
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 20, "Max results (max allowed: 100)")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 
 	cmd.Flags().BoolVar(&showCompleted, "show-completed", true, "Include completed tasks (requires --show-hidden for some clients)")
 	cmd.Flags().BoolVar(&showDeleted, "show-deleted", false, "Include deleted tasks")
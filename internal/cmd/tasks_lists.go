@@ -14,6 +14,7 @@ import (
 func newTasksListsCmd(flags *rootFlags) *cobra.Command {
 	var max int64
 	var page string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "lists",
@@ -31,20 +32,30 @@ func newTasksListsCmd(flags *rootFlags) *cobra.Command {
 				return err
 			}
 
-			call := svc.Tasklists.List().MaxResults(max).PageToken(page)
-			resp, err := call.Do()
+			call := svc.Tasklists.List().MaxResults(max)
+			items, nextPageToken, err := fetchPages(cmd.Context(), pages, page, func(pageToken string) ([]*tasks.TaskList, string, error) {
+				resp, err := call.PageToken(pageToken).Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Items, resp.NextPageToken, nil
+			})
+			partial, err := splitPageError(cmd.Context(), err, len(items))
 			if err != nil {
 				return err
 			}
 
 			if outfmt.IsJSON(cmd.Context()) {
-				return outfmt.WriteJSON(os.Stdout, map[string]any{
-					"tasklists":     resp.Items,
-					"nextPageToken": resp.NextPageToken,
-				})
+				if err := outfmt.WriteJSON(os.Stdout, map[string]any{
+					"tasklists":     items,
+					"nextPageToken": nextPageToken,
+				}); err != nil {
+					return err
+				}
+				return partial
 			}
 
-			if len(resp.Items) == 0 {
+			if len(items) == 0 {
 				u.Err().Println("No task lists")
 				return nil
 			}
@@ -52,16 +63,17 @@ func newTasksListsCmd(flags *rootFlags) *cobra.Command {
 			w, flush := tableWriter(cmd.Context())
 			defer flush()
 			fmt.Fprintln(w, "ID\tTITLE")
-			for _, tl := range resp.Items {
+			for _, tl := range items {
 				fmt.Fprintf(w, "%s\t%s\n", tl.Id, tl.Title)
 			}
-			printNextPageHint(u, resp.NextPageToken)
-			return nil
+			printNextPageHint(u, nextPageToken)
+			return partial
 		},
 	}
 
 	cmd.Flags().Int64Var(&max, "max", 100, "Max results (max allowed: 1000)")
 	cmd.Flags().StringVar(&page, "page", "", "Page token")
+	pages.addFlags(cmd)
 	cmd.AddCommand(newTasksListsCreateCmd(flags))
 	return cmd
 }
@@ -12,27 +12,41 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
-	oldHome := os.Getenv("HOME")
-	oldXDG := os.Getenv("XDG_CONFIG_HOME")
-
-	home := filepath.Join(root, "home")
-	xdg := filepath.Join(root, "xdg")
-	_ = os.MkdirAll(home, 0o755)
-	_ = os.MkdirAll(xdg, 0o755)
-	_ = os.Setenv("HOME", home)
-	_ = os.Setenv("XDG_CONFIG_HOME", xdg)
+	env := map[string]string{
+		"HOME":            filepath.Join(root, "home"),
+		"XDG_CONFIG_HOME": filepath.Join(root, "xdg"),
+		"XDG_STATE_HOME":  filepath.Join(root, "xdg-state"),
+		"XDG_CACHE_HOME":  filepath.Join(root, "xdg-cache"),
+		"GOG_CONFIG_DIR":  "",
+		"GOG_STATE_DIR":   "",
+		"GOG_CACHE_DIR":   "",
+		"GOG_SA_KEY":      "",
+		"GOG_IMPERSONATE": "",
+		"GOG_TOKEN_STORE": "",
+	}
+	old := make(map[string]*string, len(env))
+	for k, v := range env {
+		if prev, ok := os.LookupEnv(k); ok {
+			old[k] = &prev
+		} else {
+			old[k] = nil
+		}
+		if v == "" {
+			_ = os.Unsetenv(k)
+			continue
+		}
+		_ = os.MkdirAll(v, 0o755)
+		_ = os.Setenv(k, v)
+	}
 
 	code := m.Run()
 
-	if oldHome == "" {
-		_ = os.Unsetenv("HOME")
-	} else {
-		_ = os.Setenv("HOME", oldHome)
-	}
-	if oldXDG == "" {
-		_ = os.Unsetenv("XDG_CONFIG_HOME")
-	} else {
-		_ = os.Setenv("XDG_CONFIG_HOME", oldXDG)
+	for k, prev := range old {
+		if prev == nil {
+			_ = os.Unsetenv(k)
+		} else {
+			_ = os.Setenv(k, *prev)
+		}
 	}
 	_ = os.RemoveAll(root)
 	os.Exit(code)
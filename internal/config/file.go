@@ -0,0 +1,173 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// File is the optional user config file (config.json in Dir). Every field
+// is optional; a missing file is the zero File.
+type File struct {
+	Gmail    GmailConfig    `json:"gmail,omitempty"`
+	Calendar CalendarConfig `json:"calendar,omitempty"`
+	Download DownloadConfig `json:"download,omitempty"`
+	HTTP     HTTPConfig     `json:"http,omitempty"`
+}
+
+// GmailConfig tunes messages built by gmail send / drafts create.
+type GmailConfig struct {
+	// MessageIDDomain replaces the From domain in generated Message-IDs.
+	MessageIDDomain string `json:"messageIdDomain,omitempty"`
+	// XMailer and UserAgent are text/template strings for those headers
+	// ({{.Version}}, {{.Account}}, {{.From}}); empty omits the header.
+	XMailer   string `json:"xMailer,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	// SendAsByDomain maps recipient domains (subdomains included) to the
+	// send-as alias gmail send uses when --from is not given.
+	SendAsByDomain map[string]string `json:"sendAsByDomain,omitempty"`
+	// PubSubSubscription is the pull subscription gmail notify serve reads
+	// (projects/<p>/subscriptions/<s>) when --subscription is not given.
+	PubSubSubscription string `json:"pubsubSubscription,omitempty"`
+	// StripTracking makes gmail send/drafts create remove tracking URL
+	// parameters and pixels by default (--strip-tracking=false opts out).
+	StripTracking bool `json:"stripTracking,omitempty"`
+	// PGPKey is the gpg signing key (ID or address) for gmail send
+	// --pgp-sign when --pgp-key is not given.
+	PGPKey string `json:"pgpKey,omitempty"`
+	// ReplyPrefix is the subject prefix gmail reply emits (default "Re:"),
+	// e.g. "AW:" or "SV:".
+	ReplyPrefix string `json:"replyPrefix,omitempty"`
+	// LabelCacheTTL (e.g. "10m") keeps label lists on disk between runs
+	// so name lookups skip labels.list; empty disables it.
+	LabelCacheTTL string `json:"labelCacheTTL,omitempty"`
+	// SavedSearches are the named queries of gmail saved-search.
+	SavedSearches map[string]SavedSearch `json:"savedSearches,omitempty"`
+}
+
+// SavedSearch is a gmail search query with the search flags (name ->
+// value, e.g. "max": "50") it runs with by default.
+type SavedSearch struct {
+	Query       string            `json:"query"`
+	Flags       map[string]string `json:"flags,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+// CalendarConfig tunes calendar table output.
+type CalendarConfig struct {
+	// SecondaryTimezone (IANA name) adds a column with event starts in
+	// that zone.
+	SecondaryTimezone string `json:"secondaryTimezone,omitempty"`
+	// WeekNumbers adds an ISO week number column.
+	WeekNumbers bool `json:"weekNumbers,omitempty"`
+}
+
+// DownloadConfig gates files written by attachment and Drive downloads.
+type DownloadConfig struct {
+	// ScanHook is a shell command run on every downloaded file while it
+	// sits in the quarantine dir (path in $GOG_SCAN_FILE); exit status 0
+	// releases it to its destination, anything else keeps it quarantined.
+	ScanHook string `json:"scanHook,omitempty"`
+	// QuarantineDir overrides where files wait for ScanHook.
+	QuarantineDir string `json:"quarantineDir,omitempty"`
+}
+
+// HTTPConfig tunes the HTTP stack shared by all API clients.
+type HTTPConfig struct {
+	// Cache keeps GET responses with an ETag in the cache dir and
+	// revalidates them with If-None-Match (GOG_HTTP_CACHE overrides).
+	Cache bool `json:"cache,omitempty"`
+	// MaxRetries and Max5xxRetries bound retries of 429 and 5xx responses
+	// (pointers, so 0 can turn retries off).
+	MaxRetries    *int `json:"maxRetries,omitempty"`
+	Max5xxRetries *int `json:"max5xxRetries,omitempty"`
+	// RetryBaseDelay is the first backoff ("1s"), doubled per retry;
+	// MaxRetryDelay caps it. Retry-After headers always win.
+	RetryBaseDelay string `json:"retryBaseDelay,omitempty"`
+	MaxRetryDelay  string `json:"maxRetryDelay,omitempty"`
+	// QPS caps API requests per second per account (0 = unlimited).
+	QPS float64 `json:"qps,omitempty"`
+}
+
+// ConfigFilePath is the user config file.
+func ConfigFilePath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// ReadConfigFile loads config.json; a missing file is not an error.
+func ReadConfigFile() (File, error) {
+	path, err := ConfigFilePath()
+	if err != nil {
+		return File{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return File{}, nil
+		}
+		return File{}, err
+	}
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return File{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// SetGmailSavedSearches replaces gmail.savedSearches in config.json. The
+// rest of the file, including keys gog does not know, is kept.
+func SetGmailSavedSearches(searches map[string]SavedSearch) error {
+	if _, err := EnsureDir(); err != nil {
+		return err
+	}
+	path, err := ConfigFilePath()
+	if err != nil {
+		return err
+	}
+	root := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &root); err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+	gmail := map[string]json.RawMessage{}
+	if raw, ok := root["gmail"]; ok {
+		if err := json.Unmarshal(raw, &gmail); err != nil {
+			return fmt.Errorf("parse %s: gmail: %w", path, err)
+		}
+	}
+	if len(searches) == 0 {
+		delete(gmail, "savedSearches")
+	} else {
+		raw, err := json.Marshal(searches)
+		if err != nil {
+			return err
+		}
+		gmail["savedSearches"] = raw
+	}
+	raw, err := json.Marshal(gmail)
+	if err != nil {
+		return err
+	}
+	root["gmail"] = raw
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadConfigFile(t *testing.T) {
+	t.Setenv(ConfigDirEnv, t.TempDir())
+
+	f, err := ReadConfigFile()
+	if err != nil || f.Gmail.MessageIDDomain != "" {
+		t.Fatalf("missing file: %#v %v", f, err)
+	}
+
+	path, _ := ConfigFilePath()
+	if err := os.WriteFile(path, []byte(`{"gmail":{"messageIdDomain":"corp.example","xMailer":"gog {{.Version}}"}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	f, err = ReadConfigFile()
+	if err != nil || f.Gmail.MessageIDDomain != "corp.example" || f.Gmail.XMailer != "gog {{.Version}}" {
+		t.Fatalf("unexpected: %#v %v", f, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := ReadConfigFile(); err == nil {
+		t.Fatalf("expected parse error")
+	}
+	if filepath.Base(path) != "config.json" {
+		t.Fatalf("unexpected path %q", path)
+	}
+}
+
+func TestSetGmailSavedSearches(t *testing.T) {
+	t.Setenv(ConfigDirEnv, t.TempDir())
+
+	path, _ := ConfigFilePath()
+	if err := os.WriteFile(path, []byte(`{"gmail":{"replyPrefix":"AW:"},"custom":{"keep":true}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	searches := map[string]SavedSearch{
+		"triage": {Query: "in:inbox is:unread", Flags: map[string]string{"max": "50"}},
+	}
+	if err := SetGmailSavedSearches(searches); err != nil {
+		t.Fatalf("SetGmailSavedSearches: %v", err)
+	}
+	f, err := ReadConfigFile()
+	if err != nil {
+		t.Fatalf("ReadConfigFile: %v", err)
+	}
+	if f.Gmail.ReplyPrefix != "AW:" || f.Gmail.SavedSearches["triage"].Flags["max"] != "50" {
+		t.Fatalf("unexpected: %#v", f.Gmail)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"keep": true`) {
+		t.Fatalf("unknown keys dropped: %s", data)
+	}
+
+	if err := SetGmailSavedSearches(nil); err != nil {
+		t.Fatalf("SetGmailSavedSearches(nil): %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "savedSearches") || !strings.Contains(string(data), "AW:") {
+		t.Fatalf("unexpected after clear: %s", data)
+	}
+}
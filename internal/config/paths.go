@@ -1,13 +1,34 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 )
 
 const AppName = "gogcli"
 
+// Environment overrides for the base directories.
+const (
+	ConfigDirEnv = "GOG_CONFIG_DIR"
+	StateDirEnv  = "GOG_STATE_DIR"
+	CacheDirEnv  = "GOG_CACHE_DIR"
+)
+
+// goos is swapped in tests to exercise per-platform layouts.
+var goos = runtime.GOOS
+
+// Dir is the config directory (credentials, keyring, settings).
+//
+//	Linux/BSD: $XDG_CONFIG_HOME/gogcli (~/.config/gogcli)
+//	macOS:     ~/Library/Application Support/gogcli
+//	Windows:   %AppData%\gogcli
 func Dir() (string, error) {
+	if dir := envDir(ConfigDirEnv); dir != "" {
+		return dir, nil
+	}
 	base, err := os.UserConfigDir()
 	if err != nil {
 		return "", err
@@ -93,6 +114,24 @@ func GmailAllowlistPath() (string, error) {
 	return filepath.Join(dir, "gmail-allowlist.txt"), nil
 }
 
+// AuditLogPath is the append-only log of mutating API calls.
+func AuditLogPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit.jsonl"), nil
+}
+
+// PolicyPath is the command policy file (policy.yaml).
+func PolicyPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "policy.yaml"), nil
+}
+
 func EnsureGmailAttachmentsDir() (string, error) {
 	dir, err := GmailAttachmentsDir()
 	if err != nil {
@@ -104,12 +143,175 @@ func EnsureGmailAttachmentsDir() (string, error) {
 	return dir, nil
 }
 
-func GmailWatchDir() (string, error) {
+// StateDir holds local state (watch state, sync cursors, outboxes) that may
+// contain message metadata and is covered by `gog secure enable`.
+//
+//	Linux/BSD: $XDG_STATE_HOME/gogcli (~/.local/state/gogcli)
+//	macOS:     ~/Library/Application Support/gogcli/state
+//	Windows:   %LocalAppData%\gogcli\state
+//
+// On Linux/BSD, state written by older versions under the config dir keeps
+// being used until the XDG location exists.
+func StateDir() (string, error) {
+	if dir := envDir(StateDirEnv); dir != "" {
+		return dir, nil
+	}
+	switch goos {
+	case "darwin", "ios":
+		dir, err := Dir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "state"), nil
+	case "windows":
+		base, err := os.UserCacheDir() // %LocalAppData%
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(base, AppName, "state"), nil
+	}
+
+	base := os.Getenv("XDG_STATE_HOME")
+	if !filepath.IsAbs(base) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".local", "state")
+	}
+	dir := filepath.Join(base, AppName)
+	if !exists(dir) {
+		if legacy, err := Dir(); err == nil && exists(filepath.Join(legacy, "state")) {
+			return filepath.Join(legacy, "state"), nil
+		}
+	}
+	return dir, nil
+}
+
+// CacheDir holds disposable data (message caches, indexes, avatars).
+// Deleting it is always safe; it is also covered by `gog secure enable`.
+//
+//	Linux/BSD: $XDG_CACHE_HOME/gogcli (~/.cache/gogcli)
+//	macOS:     ~/Library/Caches/gogcli
+//	Windows:   %LocalAppData%\gogcli\cache
+func CacheDir() (string, error) {
+	if dir := envDir(CacheDirEnv); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	if goos == "windows" {
+		return filepath.Join(base, AppName, "cache"), nil
+	}
+	return filepath.Join(base, AppName), nil
+}
+
+// GmailCacheDir holds the per-account message caches built by `gmail sync`.
+func GmailCacheDir() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gmail"), nil
+}
+
+func EnsureGmailCacheDir() (string, error) {
+	dir, err := GmailCacheDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// HTTPCacheDir holds API responses kept for ETag revalidation.
+func HTTPCacheDir() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "http"), nil
+}
+
+// AvatarCacheDir holds contact photos cached by email address.
+func AvatarCacheDir() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "avatars"), nil
+}
+
+func EnsureAvatarCacheDir() (string, error) {
+	dir, err := AvatarCacheDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func EnsureStateDir() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func EnsureCacheDir() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func envDir(key string) string {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return ""
+	}
+	if v == "~" || strings.HasPrefix(v, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			v = filepath.Join(home, strings.TrimPrefix(v, "~"))
+		}
+	}
+	return filepath.Clean(v)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil || !errors.Is(err, os.ErrNotExist)
+}
+
+// SecureSettingsPath stores the at-rest encryption settings for StateDir.
+func SecureSettingsPath() (string, error) {
 	dir, err := Dir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dir, "state", "gmail-watch"), nil
+	return filepath.Join(dir, "secure.json"), nil
+}
+
+func GmailWatchDir() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gmail-watch"), nil
 }
 
 func EnsureGmailWatchDir() (string, error) {
@@ -122,3 +324,102 @@ func EnsureGmailWatchDir() (string, error) {
 	}
 	return dir, nil
 }
+
+// GmailHistoryDir holds the per-account cursors of `gmail history --follow`.
+func GmailHistoryDir() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gmail-history"), nil
+}
+
+func EnsureGmailHistoryDir() (string, error) {
+	dir, err := GmailHistoryDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// OutboxDir holds messages queued by `gmail send --send-at` until `gog queue run`.
+func OutboxDir() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "outbox"), nil
+}
+
+func EnsureOutboxDir() (string, error) {
+	dir, err := OutboxDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// FollowupsDir holds `gmail followup` reminders until `gog queue run` (or
+// `gog gmail followup run`) resolves them.
+func FollowupsDir() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "followups"), nil
+}
+
+func EnsureFollowupsDir() (string, error) {
+	dir, err := FollowupsDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// QuarantineDir holds downloads waiting for (or blocked by) the
+// download.scanHook.
+func QuarantineDir() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "quarantine"), nil
+}
+
+// ShellHistoryPath keeps the lines entered in `gog shell`.
+func ShellHistoryPath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shell-history"), nil
+}
+
+// SentLogPath records every message gog sends (or fails to send), for
+// `gmail sent report`.
+func SentLogPath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sent-log.jsonl"), nil
+}
+
+// UsageLogPath keeps per-run API call counts for `gog stats`.
+func UsageLogPath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.jsonl"), nil
+}
@@ -61,3 +61,63 @@ func TestPaths_CreateDirs(t *testing.T) {
 		t.Fatalf("unexpected creds file: %q", filepath.Base(credsPath))
 	}
 }
+
+func TestPaths_EnvOverrides(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(ConfigDirEnv, filepath.Join(root, "cfg"))
+	t.Setenv(StateDirEnv, filepath.Join(root, "st"))
+	t.Setenv(CacheDirEnv, filepath.Join(root, "cache"))
+
+	if dir, _ := Dir(); dir != filepath.Join(root, "cfg") {
+		t.Fatalf("Dir: %q", dir)
+	}
+	if dir, _ := StateDir(); dir != filepath.Join(root, "st") {
+		t.Fatalf("StateDir: %q", dir)
+	}
+	if dir, _ := GmailWatchDir(); dir != filepath.Join(root, "st", "gmail-watch") {
+		t.Fatalf("GmailWatchDir: %q", dir)
+	}
+	if dir, _ := CacheDir(); dir != filepath.Join(root, "cache") {
+		t.Fatalf("CacheDir: %q", dir)
+	}
+	if path, _ := ClientCredentialsPath(); path != filepath.Join(root, "cfg", "credentials.json") {
+		t.Fatalf("ClientCredentialsPath: %q", path)
+	}
+}
+
+func TestStateDir_XDGAndLegacy(t *testing.T) {
+	orig := goos
+	t.Cleanup(func() { goos = orig })
+	goos = "linux"
+
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "cfg"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(root, "st"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(root, "cache"))
+
+	dir, err := StateDir()
+	if err != nil || dir != filepath.Join(root, "st", AppName) {
+		t.Fatalf("StateDir: %q %v", dir, err)
+	}
+	if dir, _ := CacheDir(); dir != filepath.Join(root, "cache", AppName) {
+		t.Fatalf("CacheDir: %q", dir)
+	}
+
+	// Pre-XDG state under the config dir keeps being used.
+	legacy := filepath.Join(root, "cfg", AppName, "state")
+	if err := os.MkdirAll(legacy, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if dir, _ := StateDir(); dir != legacy {
+		t.Fatalf("expected legacy state dir, got %q", dir)
+	}
+
+	// Until the XDG location exists.
+	if err := os.MkdirAll(filepath.Join(root, "st", AppName), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if dir, _ := StateDir(); dir != filepath.Join(root, "st", AppName) {
+		t.Fatalf("expected XDG state dir, got %q", dir)
+	}
+}
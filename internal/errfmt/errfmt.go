@@ -22,6 +22,16 @@ func Format(err error) string {
 		return fmt.Sprintf("No refresh token for %s %s. Run: gog auth add %s --services %s", authErr.Service, authErr.Email, authErr.Email, authErr.Service)
 	}
 
+	var scopeErr *gogapi.InsufficientScopeError
+	if errors.As(err, &scopeErr) {
+		return fmt.Sprintf("Missing OAuth scopes for %s on %s. Re-run with --auto-consent to re-authorize with the additional scopes (your other services are kept).", scopeErr.Service, scopeErr.Email)
+	}
+
+	var budgetErr *gogapi.BudgetExceededError
+	if errors.As(err, &budgetErr) {
+		return fmt.Sprintf("Stopped: API call budget of %d requests exhausted (--max-api-calls). Output above may be partial; narrow the query or raise the limit.", budgetErr.Limit)
+	}
+
 	var credErr *config.CredentialsMissingError
 	if errors.As(err, &credErr) {
 		return fmt.Sprintf("OAuth credentials missing. Run: gog auth credentials <credentials.json> (expected at %s)", credErr.Path)
@@ -2,6 +2,7 @@ package errfmt
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -37,6 +38,14 @@ func TestFormat_CredentialsMissing(t *testing.T) {
 	}
 }
 
+func TestFormat_BudgetExceeded(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &gogapi.BudgetExceededError{Limit: 25})
+	got := Format(err)
+	if !containsAll(got, "25", "--max-api-calls", "partial") {
+		t.Fatalf("unexpected: %q", got)
+	}
+}
+
 func TestFormat_KeyNotFound(t *testing.T) {
 	got := Format(keyring.ErrKeyNotFound)
 	if !containsAll(got, "Secret not found", "gog auth add") {
@@ -0,0 +1,80 @@
+package googleapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxAuditBody bounds how much of a response is read to find its ID.
+const maxAuditBody = 1 << 20
+
+// AuditCall describes one mutating API request (anything but GET/HEAD).
+type AuditCall struct {
+	Account string
+	Method  string
+	URL     string // host and path, without the query
+	Status  int
+	ID      string // resource ID from the JSON response, if any
+	Err     string
+}
+
+// AuditTransport reports every mutating request to Record after it
+// completes. Read-only requests pass through untouched.
+type AuditTransport struct {
+	Base    http.RoundTripper
+	Account string
+	Record  func(AuditCall)
+}
+
+func (t *AuditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead || t.Record == nil {
+		return t.Base.RoundTrip(req)
+	}
+	call := AuditCall{Account: t.Account, Method: req.Method, URL: req.URL.Host + req.URL.Path}
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		call.Err = err.Error()
+		t.Record(call)
+		return resp, err
+	}
+	call.Status = resp.StatusCode
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") && resp.Body != nil {
+		head, readErr := io.ReadAll(io.LimitReader(resp.Body, maxAuditBody))
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+		if readErr == nil && len(head) < maxAuditBody {
+			call.ID = auditResponseID(head)
+		}
+	}
+	t.Record(call)
+	return resp, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// auditResponseID picks the identifier of the created or changed resource.
+func auditResponseID(body []byte) string {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return ""
+	}
+	for _, k := range []string{"id", "spreadsheetId", "documentId", "presentationId", "resourceName", "name"} {
+		var s string
+		if raw, ok := fields[k]; ok && json.Unmarshal(raw, &s) == nil && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// withAudit wraps c's transport when the context asks for auditing.
+func withAudit(opts TransportOptions, c *http.Client, email string) {
+	if opts.Audit != nil {
+		c.Transport = &AuditTransport{Base: c.Transport, Account: email, Record: opts.Audit}
+	}
+}
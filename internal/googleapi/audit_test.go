@@ -0,0 +1,47 @@
+package googleapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuditTransport_RecordsMutatingCalls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		_, _ = io.WriteString(w, `{"id":"m1","threadId":"t1"}`)
+	}))
+	defer srv.Close()
+
+	var calls []AuditCall
+	at := &AuditTransport{Base: http.DefaultTransport, Account: "a@b.com", Record: func(c AuditCall) { calls = append(calls, c) }}
+
+	get, _ := http.NewRequest(http.MethodGet, srv.URL+"/gmail/v1/users/me/messages", nil)
+	resp, err := at.RoundTrip(get)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	post, _ := http.NewRequest(http.MethodPost, srv.URL+"/gmail/v1/users/me/messages/send?alt=json", strings.NewReader("{}"))
+	resp, err = at.RoundTrip(post)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != `{"id":"m1","threadId":"t1"}` {
+		t.Fatalf("body not preserved: %q", body)
+	}
+
+	if len(calls) != 1 {
+		t.Fatalf("calls = %+v", calls)
+	}
+	c := calls[0]
+	if c.Method != http.MethodPost || c.Account != "a@b.com" || c.Status != 200 || c.ID != "m1" ||
+		!strings.HasSuffix(c.URL, "/gmail/v1/users/me/messages/send") {
+		t.Fatalf("call = %+v", c)
+	}
+}
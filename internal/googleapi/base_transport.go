@@ -0,0 +1,129 @@
+package googleapi
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultMaxIdleConnsPerHost keeps warm connections around for concurrent
+// bulk commands (net/http defaults to 2, which forces cold TLS handshakes).
+const DefaultMaxIdleConnsPerHost = 16
+
+// baseTransportKey identifies the options that shape the connection pool.
+// The CA bundle counts by path and content, so loading the same bundle again
+// (e.g. per `gog shell` line) reuses the transport.
+type baseTransportKey struct {
+	maxConnsPerHost int
+	proxy           string
+	caPath          string
+	caSHA256        string
+	insecure        bool
+}
+
+// CABundle is a PEM bundle loaded by LoadCABundle.
+type CABundle struct {
+	Path   string
+	SHA256 string // hex digest of the file contents
+	Pool   *x509.CertPool
+}
+
+type caBundleKey struct {
+	path, sha256 string
+}
+
+var (
+	baseTransportsMu sync.Mutex
+	baseTransports   = map[baseTransportKey]*http.Transport{}
+
+	caBundlesMu sync.Mutex
+	caBundles   = map[caBundleKey]*CABundle{}
+)
+
+// sharedBaseTransport returns a process-wide transport so every API client
+// created during one invocation reuses the same connection pool.
+func sharedBaseTransport(opts TransportOptions) *http.Transport {
+	baseTransportsMu.Lock()
+	defer baseTransportsMu.Unlock()
+
+	key := baseTransportKey{
+		maxConnsPerHost: max(opts.MaxConnsPerHost, 0),
+		insecure:        opts.InsecureSkipVerify,
+	}
+	if opts.Proxy != nil {
+		key.proxy = opts.Proxy.String()
+	}
+	if opts.CABundle != nil {
+		key.caPath, key.caSHA256 = opts.CABundle.Path, opts.CABundle.SHA256
+	}
+	if t, ok := baseTransports[key]; ok {
+		return t
+	}
+	t := newBaseTransport(key.maxConnsPerHost)
+	if opts.Proxy != nil {
+		t.Proxy = http.ProxyURL(opts.Proxy)
+	}
+	if opts.CABundle != nil {
+		t.TLSClientConfig.RootCAs = opts.CABundle.Pool
+	}
+	t.TLSClientConfig.InsecureSkipVerify = opts.InsecureSkipVerify
+	baseTransports[key] = t
+	return t
+}
+
+func newBaseTransport(maxConnsPerHost int) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	// A custom TLS config disables HTTP/2 unless explicitly requested.
+	t.ForceAttemptHTTP2 = true
+	// Transparent gzip for JSON responses (Accept-Encoding + decode).
+	t.DisableCompression = false
+	t.MaxIdleConns = 100
+	t.MaxIdleConnsPerHost = max(DefaultMaxIdleConnsPerHost, maxConnsPerHost)
+	t.MaxConnsPerHost = maxConnsPerHost
+	t.IdleConnTimeout = 90 * time.Second
+	return t
+}
+
+// LoadCABundle returns the system roots plus the PEM certificates in path,
+// for TLS-intercepting corporate proxies. An unchanged file returns the pool
+// built the first time.
+func LoadCABundle(path string) (*CABundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read CA bundle: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	key := caBundleKey{path: path, sha256: hex.EncodeToString(sum[:])}
+
+	caBundlesMu.Lock()
+	defer caBundlesMu.Unlock()
+	if b, ok := caBundles[key]; ok {
+		return b, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("CA bundle %s: no PEM certificates found", path)
+	}
+	b := &CABundle{Path: path, SHA256: key.sha256, Pool: pool}
+	caBundles[key] = b
+	return b, nil
+}
+
+// tokenHTTPClient is the client for OAuth token exchanges: the shared base
+// transport (proxy and CA settings included) with a timeout so refreshes
+// don't hang forever.
+func tokenHTTPClient(opts TransportOptions) *http.Client {
+	return &http.Client{Timeout: defaultHTTPTimeout, Transport: sharedBaseTransport(opts)}
+}
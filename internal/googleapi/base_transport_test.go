@@ -0,0 +1,110 @@
+package googleapi
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSharedBaseTransport_Tuning(t *testing.T) {
+	tr := sharedBaseTransport(TransportOptions{MaxConnsPerHost: 4})
+	if !tr.ForceAttemptHTTP2 {
+		t.Fatalf("expected HTTP/2 to be enabled")
+	}
+	if tr.DisableCompression {
+		t.Fatalf("expected gzip to be enabled")
+	}
+	if tr.MaxConnsPerHost != 4 {
+		t.Fatalf("expected MaxConnsPerHost=4, got %d", tr.MaxConnsPerHost)
+	}
+	if tr.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost {
+		t.Fatalf("expected idle pool of %d, got %d", DefaultMaxIdleConnsPerHost, tr.MaxIdleConnsPerHost)
+	}
+	if tr.TLSClientConfig == nil || tr.TLSClientConfig.MinVersion == 0 {
+		t.Fatalf("expected TLS min version")
+	}
+}
+
+func TestSharedBaseTransport_ReusedPerOptions(t *testing.T) {
+	a := sharedBaseTransport(TransportOptions{})
+	b := sharedBaseTransport(TransportOptions{Hedge: true})
+	if a != b {
+		t.Fatalf("expected the same pooled transport")
+	}
+	c := sharedBaseTransport(TransportOptions{MaxConnsPerHost: 64})
+	if c == a {
+		t.Fatalf("expected a distinct transport for a different connection cap")
+	}
+	if c.MaxIdleConnsPerHost != 64 {
+		t.Fatalf("expected idle pool to grow with cap, got %d", c.MaxIdleConnsPerHost)
+	}
+}
+
+func TestSharedBaseTransport_ProxyAndCABundle(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	tr := sharedBaseTransport(TransportOptions{Proxy: proxyURL})
+	if tr == sharedBaseTransport(TransportOptions{}) {
+		t.Fatalf("expected a distinct transport for a proxy")
+	}
+	resp, err := (&http.Client{Transport: tr}).Get("http://gmail.example.invalid/gmail/v1/users/me/profile")
+	if err != nil {
+		t.Fatalf("get via proxy: %v", err)
+	}
+	_ = resp.Body.Close()
+	if proxied != "http://gmail.example.invalid/gmail/v1/users/me/profile" {
+		t.Fatalf("proxy saw %q", proxied)
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	if _, err := (&http.Client{Transport: sharedBaseTransport(TransportOptions{})}).Get(srv.URL); err == nil {
+		t.Fatalf("expected an untrusted certificate error")
+	}
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(path, pemData, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := LoadCABundle(path)
+	if err != nil {
+		t.Fatalf("LoadCABundle: %v", err)
+	}
+	tr = sharedBaseTransport(TransportOptions{CABundle: bundle})
+	resp, err = (&http.Client{Transport: tr}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("get with CA bundle: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	// Loading the same bundle again (e.g. per shell line) reuses pool and transport.
+	again, err := LoadCABundle(path)
+	if err != nil {
+		t.Fatalf("LoadCABundle again: %v", err)
+	}
+	if again.Pool != bundle.Pool || sharedBaseTransport(TransportOptions{CABundle: again}) != tr {
+		t.Fatalf("expected the unchanged bundle to reuse its pool and transport")
+	}
+	if sharedBaseTransport(TransportOptions{CABundle: &CABundle{Path: path, SHA256: "other", Pool: bundle.Pool}}) == tr {
+		t.Fatalf("expected changed bundle contents to get a new transport")
+	}
+
+	if err := os.WriteFile(path, []byte("not a cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCABundle(path); err == nil {
+		t.Fatalf("expected an error for a bundle without certificates")
+	}
+}
@@ -0,0 +1,55 @@
+package googleapi
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// RequestBudget caps the number of HTTP requests one invocation may send.
+// It is shared by every API client created for the invocation.
+type RequestBudget struct {
+	Limit int64
+	used  atomic.Int64
+}
+
+func NewRequestBudget(limit int64) *RequestBudget {
+	return &RequestBudget{Limit: limit}
+}
+
+// Take reserves one request, failing once the limit has been reached.
+func (b *RequestBudget) Take() error {
+	if b == nil || b.Limit <= 0 {
+		return nil
+	}
+	if n := b.used.Add(1); n > b.Limit {
+		b.used.Add(-1)
+		return &BudgetExceededError{Limit: b.Limit}
+	}
+	return nil
+}
+
+// Used reports how many requests have been sent so far.
+func (b *RequestBudget) Used() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.used.Load()
+}
+
+// BudgetTransport counts every request (including retries and hedged
+// attempts) against a RequestBudget.
+type BudgetTransport struct {
+	Base   http.RoundTripper
+	Budget *RequestBudget
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *BudgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.Budget.Take(); err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.Base.RoundTrip(req)
+}
@@ -0,0 +1,62 @@
+package googleapi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBudgetTransport_StopsAtLimit(t *testing.T) {
+	mock := &mockTransport{}
+	budget := NewRequestBudget(2)
+	bt := &BudgetTransport{Base: mock, Budget: budget}
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+		resp, err := bt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i+1, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	_, err := bt.RoundTrip(req)
+	if !IsBudgetExceededError(err) {
+		t.Fatalf("expected BudgetExceededError, got %v", err)
+	}
+	if mock.calls != 2 {
+		t.Fatalf("expected 2 calls to reach the base transport, got %d", mock.calls)
+	}
+	if budget.Used() != 2 {
+		t.Fatalf("expected 2 used, got %d", budget.Used())
+	}
+}
+
+func TestRequestBudget_Unlimited(t *testing.T) {
+	var nilBudget *RequestBudget
+	if err := nilBudget.Take(); err != nil {
+		t.Fatalf("nil budget should be unlimited: %v", err)
+	}
+	b := NewRequestBudget(0)
+	for range 10 {
+		if err := b.Take(); err != nil {
+			t.Fatalf("zero limit should be unlimited: %v", err)
+		}
+	}
+}
+
+func TestRetryTransport_BudgetCountsRetries(t *testing.T) {
+	mock := &mockTransport{
+		responses: []*http.Response{
+			{StatusCode: 503, Body: http.NoBody},
+			{StatusCode: 200, Body: http.NoBody},
+		},
+	}
+	rt := NewRetryTransport(&BudgetTransport{Base: mock, Budget: NewRequestBudget(1)})
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	_, err := rt.RoundTrip(req)
+	if !IsBudgetExceededError(err) {
+		t.Fatalf("expected retry to hit the budget, got %v", err)
+	}
+}
@@ -13,5 +13,8 @@ func NewCalendar(ctx context.Context, email string) (*calendar.Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	if opts, err = withEndpoint(ctx, "calendar", opts); err != nil {
+		return nil, err
+	}
 	return calendar.NewService(ctx, opts...)
 }
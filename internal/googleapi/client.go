@@ -2,7 +2,6 @@ package googleapi
 
 import (
 	"context"
-	"crypto/tls"
 	"log/slog"
 	"net/http"
 	"time"
@@ -25,17 +24,12 @@ var (
 )
 
 func tokenSourceForAccount(ctx context.Context, service googleauth.Service, email string) (oauth2.TokenSource, error) {
-	creds, err := readClientCredentials()
-	if err != nil {
-		return nil, err
-	}
-
 	requiredScopes, err := googleauth.Scopes(service)
 	if err != nil {
 		return nil, err
 	}
 
-	return tokenSourceForAccountScopes(ctx, string(service), email, creds.ClientID, creds.ClientSecret, requiredScopes)
+	return CredentialProviderFromContext(ctx).TokenSource(ctx, string(service), email, requiredScopes)
 }
 
 func tokenSourceForAccountScopes(ctx context.Context, serviceLabel string, email string, clientID string, clientSecret string, requiredScopes []string) (oauth2.TokenSource, error) {
@@ -59,32 +53,29 @@ func tokenSourceForAccountScopes(ctx context.Context, serviceLabel string, email
 	}
 
 	// Ensure refresh-token exchanges don't hang forever.
-	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: defaultHTTPTimeout})
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, tokenHTTPClient(TransportOptionsFromContext(ctx)))
 
 	return cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: tok.RefreshToken}), nil
 }
 
 func optionsForAccount(ctx context.Context, service googleauth.Service, email string) ([]option.ClientOption, error) {
 	slog.Debug("creating client options", "service", service, "email", email)
+	if c := replayClient(TransportOptionsFromContext(ctx)); c != nil {
+		return []option.ClientOption{option.WithHTTPClient(c)}, nil
+	}
 
 	ts, err := tokenSourceForAccount(ctx, service, email)
 	if err != nil {
 		return nil, err
 	}
-	baseTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		},
-	}
-	// Wrap with retry logic for 429 and 5xx errors
-	retryTransport := NewRetryTransport(&oauth2.Transport{
-		Source: ts,
-		Base:   baseTransport,
-	})
-	c := &http.Client{
-		Transport: retryTransport,
-		Timeout:   defaultHTTPTimeout,
+	scopes, err := googleauth.Scopes(service)
+	if err != nil {
+		return nil, err
 	}
+	c := newHTTPClient(ctx, ts, email)
+	c.Transport = &ScopeTransport{Base: c.Transport, Service: string(service), Email: email, Required: scopes}
+	withCache(TransportOptionsFromContext(ctx), c, email)
+	withAudit(TransportOptionsFromContext(ctx), c, email)
 
 	slog.Debug("client options created successfully", "service", service, "email", email)
 	return []option.ClientOption{option.WithHTTPClient(c)}, nil
@@ -92,30 +83,57 @@ func optionsForAccount(ctx context.Context, service googleauth.Service, email st
 
 func optionsForAccountScopes(ctx context.Context, serviceLabel string, email string, scopes []string) ([]option.ClientOption, error) {
 	slog.Debug("creating client options with custom scopes", "serviceLabel", serviceLabel, "email", email)
+	if c := replayClient(TransportOptionsFromContext(ctx)); c != nil {
+		return []option.ClientOption{option.WithHTTPClient(c)}, nil
+	}
 
-	creds, err := readClientCredentials()
+	ts, err := CredentialProviderFromContext(ctx).TokenSource(ctx, serviceLabel, email, scopes)
 	if err != nil {
 		return nil, err
 	}
-	ts, err := tokenSourceForAccountScopes(ctx, serviceLabel, email, creds.ClientID, creds.ClientSecret, scopes)
-	if err != nil {
-		return nil, err
+	c := newHTTPClient(ctx, ts, email)
+	c.Transport = &ScopeTransport{Base: c.Transport, Service: serviceLabel, Email: email, Required: scopes}
+	withCache(TransportOptionsFromContext(ctx), c, email)
+	withAudit(TransportOptionsFromContext(ctx), c, email)
+
+	slog.Debug("client options with custom scopes created successfully", "serviceLabel", serviceLabel, "email", email)
+	return []option.ClientOption{option.WithHTTPClient(c)}, nil
+}
+
+func newHTTPClient(ctx context.Context, ts oauth2.TokenSource, email string) *http.Client {
+	opts := TransportOptionsFromContext(ctx)
+
+	var authed http.RoundTripper = &oauth2.Transport{
+		Source: ts,
+		Base:   sharedBaseTransport(opts),
+	}
+	if limiter := accountLimiter(email, opts.QPS); limiter != nil {
+		authed = &RateLimitTransport{Base: authed, Limiter: limiter}
+	}
+	if opts.Budget != nil {
+		authed = &BudgetTransport{Base: authed, Budget: opts.Budget}
 	}
-	baseTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		},
+	if opts.Stats != nil {
+		authed = &StatsTransport{Base: authed, Stats: opts.Stats}
+	}
+	if opts.Hedge {
+		authed = NewHedgeTransport(authed, opts.HedgePercentile)
 	}
 	// Wrap with retry logic for 429 and 5xx errors
-	retryTransport := NewRetryTransport(&oauth2.Transport{
-		Source: ts,
-		Base:   baseTransport,
-	})
-	c := &http.Client{
-		Transport: retryTransport,
-		Timeout:   defaultHTTPTimeout,
+	retry := NewRetryTransport(authed)
+	retry.Stats = opts.Stats
+	timeout := defaultHTTPTimeout
+	if opts.Retry != nil {
+		opts.Retry.apply(retry)
+		// The client timeout spans every attempt and backoff sleep.
+		timeout += opts.Retry.maxBackoff()
+	}
+	var rt http.RoundTripper = retry
+	if opts.Fixtures != nil && !opts.Fixtures.Replay {
+		rt = &RecordTransport{Base: retry, Fixtures: opts.Fixtures}
+	}
+	return &http.Client{
+		Transport: rt,
+		Timeout:   timeout,
 	}
-
-	slog.Debug("client options with custom scopes created successfully", "serviceLabel", serviceLabel, "email", email)
-	return []option.ClientOption{option.WithHTTPClient(c)}, nil
 }
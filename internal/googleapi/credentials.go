@@ -0,0 +1,115 @@
+package googleapi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// CredentialProvider supplies the token source used for an account's API
+// clients. The default reads the OAuth refresh token from the keyring.
+type CredentialProvider interface {
+	TokenSource(ctx context.Context, serviceLabel string, email string, scopes []string) (oauth2.TokenSource, error)
+}
+
+// KeyringCredentials is the default provider: the installed-app OAuth client
+// (credentials.json) plus the refresh token stored by "gog auth add".
+type KeyringCredentials struct{}
+
+func (KeyringCredentials) TokenSource(ctx context.Context, serviceLabel string, email string, scopes []string) (oauth2.TokenSource, error) {
+	creds, err := readClientCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return tokenSourceForAccountScopes(ctx, serviceLabel, email, creds.ClientID, creds.ClientSecret, scopes)
+}
+
+// ServiceAccountCredentials authenticates with a service account key. With
+// domain-wide delegation the account email is impersonated (JWT subject), so
+// no browser flow or keyring is needed.
+type ServiceAccountCredentials struct {
+	KeyFile string
+}
+
+func (c ServiceAccountCredentials) TokenSource(ctx context.Context, _ string, email string, scopes []string) (oauth2.TokenSource, error) {
+	b, err := os.ReadFile(c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read service account key: %w", err)
+	}
+	cfg, err := google.JWTConfigFromJSON(b, scopes...)
+	if err != nil {
+		return nil, fmt.Errorf("service account key %s: %w", c.KeyFile, err)
+	}
+	cfg.Subject = strings.TrimSpace(email)
+
+	// Ensure token exchanges don't hang forever.
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, tokenHTTPClient(TransportOptionsFromContext(ctx)))
+	return cfg.TokenSource(ctx), nil
+}
+
+// TokenCache keeps token sources alive across the commands of one process
+// (gog shell), so an access token is fetched once per account and scope set
+// instead of once per command.
+type TokenCache struct {
+	mu      sync.Mutex
+	sources map[string]oauth2.TokenSource
+}
+
+func NewTokenCache() *TokenCache {
+	return &TokenCache{sources: map[string]oauth2.TokenSource{}}
+}
+
+// Wrap returns p with its token sources cached in c.
+func (c *TokenCache) Wrap(p CredentialProvider) CredentialProvider {
+	return cachedCredentials{cache: c, base: p}
+}
+
+// Reset drops every cached token source, e.g. after the stored refresh
+// tokens changed.
+func (c *TokenCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sources = map[string]oauth2.TokenSource{}
+}
+
+type cachedCredentials struct {
+	cache *TokenCache
+	base  CredentialProvider
+}
+
+func (cc cachedCredentials) TokenSource(ctx context.Context, serviceLabel string, email string, scopes []string) (oauth2.TokenSource, error) {
+	key := fmt.Sprintf("%#v\x00%s\x00%s", cc.base, strings.ToLower(email), strings.Join(scopes, " "))
+	cc.cache.mu.Lock()
+	defer cc.cache.mu.Unlock()
+	if ts, ok := cc.cache.sources[key]; ok {
+		return ts, nil
+	}
+	// The source outlives the command whose context created it.
+	ts, err := cc.base.TokenSource(context.WithoutCancel(ctx), serviceLabel, email, scopes)
+	if err != nil {
+		return nil, err
+	}
+	ts = oauth2.ReuseTokenSource(nil, ts)
+	cc.cache.sources[key] = ts
+	return ts, nil
+}
+
+type credentialProviderKey struct{}
+
+func WithCredentialProvider(ctx context.Context, p CredentialProvider) context.Context {
+	return context.WithValue(ctx, credentialProviderKey{}, p)
+}
+
+func CredentialProviderFromContext(ctx context.Context) CredentialProvider {
+	if ctx != nil {
+		if p, ok := ctx.Value(credentialProviderKey{}).(CredentialProvider); ok && p != nil {
+			return p
+		}
+	}
+	return KeyringCredentials{}
+}
@@ -0,0 +1,128 @@
+package googleapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestServiceAccountCredentials_ImpersonatesSubject(t *testing.T) {
+	var claims map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		parts := strings.Split(r.Form.Get("assertion"), ".")
+		if len(parts) != 3 {
+			t.Fatalf("unexpected assertion %q", r.Form.Get("assertion"))
+		}
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		_ = json.Unmarshal(payload, &claims)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "at", "token_type": "Bearer", "expires_in": 3600})
+	}))
+	defer srv.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: mustPKCS8(t, key)})
+	keyJSON, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "robot@project.iam.gserviceaccount.com",
+		"private_key_id": "kid",
+		"private_key":    string(keyPEM),
+		"token_uri":      srv.URL,
+	})
+	path := filepath.Join(t.TempDir(), "sa.json")
+	if err := os.WriteFile(path, keyJSON, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	ctx := WithCredentialProvider(context.Background(), ServiceAccountCredentials{KeyFile: path})
+	ts, err := tokenSourceForAccount(ctx, "gmail", "user@example.com")
+	if err != nil {
+		t.Fatalf("tokenSourceForAccount: %v", err)
+	}
+	tok, err := ts.Token()
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if tok.AccessToken != "at" {
+		t.Fatalf("unexpected token %#v", tok)
+	}
+	if claims["sub"] != "user@example.com" || claims["iss"] != "robot@project.iam.gserviceaccount.com" {
+		t.Fatalf("unexpected claims %#v", claims)
+	}
+	if scope, _ := claims["scope"].(string); !strings.Contains(scope, "mail.google.com") {
+		t.Fatalf("unexpected scope %q", scope)
+	}
+}
+
+func TestServiceAccountCredentials_BadKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sa.json")
+	if err := os.WriteFile(path, []byte(`{"type":"authorized_user"}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err := ServiceAccountCredentials{KeyFile: path}.TokenSource(context.Background(), "gmail", "u@example.com", []string{"s"})
+	if err == nil || !strings.Contains(err.Error(), "service account key") {
+		t.Fatalf("expected key error, got %v", err)
+	}
+	if _, ok := CredentialProviderFromContext(context.Background()).(KeyringCredentials); !ok {
+		t.Fatalf("expected keyring default")
+	}
+}
+
+func mustPKCS8(t *testing.T, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	b, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return b
+}
+
+type countingCredentials struct{ calls *int }
+
+func (c countingCredentials) TokenSource(context.Context, string, string, []string) (oauth2.TokenSource, error) {
+	*c.calls++
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "tok", Expiry: time.Now().Add(time.Hour)}), nil
+}
+
+func TestTokenCache(t *testing.T) {
+	calls := 0
+	cache := NewTokenCache()
+	p := cache.Wrap(countingCredentials{calls: &calls})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range 3 {
+		if _, err := p.TokenSource(ctx, "gmail", "A@b.com", []string{"s1"}); err != nil {
+			t.Fatalf("TokenSource: %v", err)
+		}
+	}
+	_, _ = p.TokenSource(ctx, "gmail", "a@b.com", []string{"s1", "s2"})
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2 (one per account and scope set)", calls)
+	}
+	cache.Reset()
+	_, _ = p.TokenSource(ctx, "gmail", "a@b.com", []string{"s1"})
+	if calls != 3 {
+		t.Fatalf("calls after Reset = %d", calls)
+	}
+}
@@ -0,0 +1,22 @@
+package googleapi
+
+import (
+	"context"
+
+	"google.golang.org/api/docs/v1"
+
+	"github.com/steipete/gogcli/internal/googleauth"
+)
+
+// NewDocs creates a Docs API client. The Docs API accepts the Drive scope, so
+// accounts authorized for drive can read and edit documents without re-auth.
+func NewDocs(ctx context.Context, email string) (*docs.Service, error) {
+	opts, err := optionsForAccount(ctx, googleauth.ServiceDrive, email)
+	if err != nil {
+		return nil, err
+	}
+	if opts, err = withEndpoint(ctx, "docs", opts); err != nil {
+		return nil, err
+	}
+	return docs.NewService(ctx, opts...)
+}
@@ -13,5 +13,8 @@ func NewDrive(ctx context.Context, email string) (*drive.Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	if opts, err = withEndpoint(ctx, "drive", opts); err != nil {
+		return nil, err
+	}
 	return drive.NewService(ctx, opts...)
 }
@@ -0,0 +1,88 @@
+package googleapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"google.golang.org/api/option"
+)
+
+// EndpointAPIs are the API names accepted by --endpoint and
+// GOG_<API>_ENDPOINT.
+var EndpointAPIs = []string{"calendar", "docs", "drive", "gmail", "licensing", "meet", "people", "pubsub", "sheets", "tasks", "workspaceevents"}
+
+// ParseEndpoints parses api=URL overrides (e.g. gmail=http://localhost:8080).
+func ParseEndpoints(raw []string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, r := range raw {
+		api, base, ok := strings.Cut(strings.TrimSpace(r), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid endpoint %q (expected api=URL)", r)
+		}
+		api = strings.ToLower(strings.TrimSpace(api))
+		if !isEndpointAPI(api) {
+			return nil, fmt.Errorf("unknown API %q in endpoint (expected %s)", api, strings.Join(EndpointAPIs, "|"))
+		}
+		base, err := normalizeEndpoint(base)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s: %w", api, err)
+		}
+		out[api] = base
+	}
+	return out, nil
+}
+
+// EndpointFor returns the base URL override for api: the --endpoint value
+// attached to ctx, else GOG_<API>_ENDPOINT, else "" (Google's default).
+func EndpointFor(ctx context.Context, api string) (string, error) {
+	if base := TransportOptionsFromContext(ctx).Endpoints[api]; base != "" {
+		return base, nil
+	}
+	env := "GOG_" + strings.ToUpper(api) + "_ENDPOINT"
+	raw := strings.TrimSpace(os.Getenv(env))
+	if raw == "" {
+		return "", nil
+	}
+	base, err := normalizeEndpoint(raw)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", env, err)
+	}
+	return base, nil
+}
+
+func withEndpoint(ctx context.Context, api string, opts []option.ClientOption) ([]option.ClientOption, error) {
+	base, err := EndpointFor(ctx, api)
+	if err != nil || base == "" {
+		return opts, err
+	}
+	return append(opts, option.WithEndpoint(base)), nil
+}
+
+func isEndpointAPI(api string) bool {
+	for _, a := range EndpointAPIs {
+		if a == api {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeEndpoint requires an absolute http(s) URL and adds the trailing
+// slash the generated clients join request paths onto.
+func normalizeEndpoint(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%q is not an http(s) URL", raw)
+	}
+	s := u.String()
+	if !strings.HasSuffix(s, "/") {
+		s += "/"
+	}
+	return s, nil
+}
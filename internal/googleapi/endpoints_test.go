@@ -0,0 +1,40 @@
+package googleapi
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseEndpoints(t *testing.T) {
+	got, err := ParseEndpoints([]string{"gmail=http://localhost:8080", "Drive=https://gw.example.com/google/"})
+	if err != nil {
+		t.Fatalf("ParseEndpoints: %v", err)
+	}
+	if got["gmail"] != "http://localhost:8080/" || got["drive"] != "https://gw.example.com/google/" {
+		t.Fatalf("got %v", got)
+	}
+	for _, bad := range []string{"gmail", "mail=http://x", "gmail=localhost:8080", "gmail=ftp://x"} {
+		if _, err := ParseEndpoints([]string{bad}); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestEndpointFor(t *testing.T) {
+	t.Setenv("GOG_GMAIL_ENDPOINT", "http://env:1")
+	t.Setenv("GOG_CALENDAR_ENDPOINT", "")
+
+	ctx := WithTransportOptions(context.Background(), TransportOptions{Endpoints: map[string]string{"drive": "http://flag:2/"}})
+	cases := map[string]string{"gmail": "http://env:1/", "drive": "http://flag:2/", "calendar": ""}
+	for api, want := range cases {
+		got, err := EndpointFor(ctx, api)
+		if err != nil || got != want {
+			t.Errorf("EndpointFor(%s) = %q, %v; want %q", api, got, err, want)
+		}
+	}
+
+	t.Setenv("GOG_TASKS_ENDPOINT", "not a url")
+	if _, err := EndpointFor(ctx, "tasks"); err == nil {
+		t.Fatalf("expected invalid env endpoint error")
+	}
+}
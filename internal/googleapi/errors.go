@@ -52,6 +52,15 @@ func (e *QuotaExceededError) Error() string {
 	return "API quota exceeded"
 }
 
+// BudgetExceededError indicates the per-invocation request budget (--max-api-calls) is spent
+type BudgetExceededError struct {
+	Limit int64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("API call budget exhausted (limit %d)", e.Limit)
+}
+
 // NotFoundError indicates the requested resource was not found
 type NotFoundError struct {
 	Resource string
@@ -102,6 +111,12 @@ func IsQuotaExceededError(err error) bool {
 	return errors.As(err, &e)
 }
 
+// IsBudgetExceededError checks if the error is a request budget error
+func IsBudgetExceededError(err error) bool {
+	var e *BudgetExceededError
+	return errors.As(err, &e)
+}
+
 // IsNotFoundError checks if the error is a not found error
 func IsNotFoundError(err error) bool {
 	var e *NotFoundError
@@ -0,0 +1,215 @@
+package googleapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// HTTPFixture is one recorded API response. Exactly one of JSON (for JSON
+// bodies), Text or Body (raw bytes, base64 in the file) is set.
+type HTTPFixture struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	Status      int             `json:"status"`
+	ContentType string          `json:"contentType,omitempty"`
+	JSON        json.RawMessage `json:"json,omitempty"`
+	Text        string          `json:"text,omitempty"`
+	Body        []byte          `json:"body,omitempty"`
+}
+
+// HTTPFixtures records API responses as one JSON file per request in Dir,
+// or replays them from there without credentials or network. Requests are
+// matched by method and URL; repeated requests get the recorded responses
+// in order, the last one again once they run out.
+type HTTPFixtures struct {
+	Dir    string
+	Replay bool
+
+	mu      sync.Mutex
+	loaded  bool
+	entries map[string][]HTTPFixture
+	served  map[string]int
+	seq     int
+}
+
+func NewHTTPFixtures(dir string, replay bool) *HTTPFixtures {
+	return &HTTPFixtures{Dir: dir, Replay: replay}
+}
+
+// fixtureKey matches requests regardless of query parameter order.
+func fixtureKey(method, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return strings.ToUpper(method) + " " + rawURL
+	}
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+	return strings.ToUpper(method) + " " + u.String()
+}
+
+func (f *HTTPFixtures) files() ([]string, error) {
+	names, err := filepath.Glob(filepath.Join(f.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *HTTPFixtures) load() error {
+	if f.loaded {
+		return nil
+	}
+	names, err := f.files()
+	if err != nil {
+		return err
+	}
+	f.entries = map[string][]HTTPFixture{}
+	f.served = map[string]int{}
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		var fx HTTPFixture
+		if err := json.Unmarshal(data, &fx); err != nil {
+			return fmt.Errorf("fixture %s: %w", name, err)
+		}
+		key := fixtureKey(fx.Method, fx.URL)
+		f.entries[key] = append(f.entries[key], fx)
+	}
+	f.loaded = true
+	return nil
+}
+
+func (f *HTTPFixtures) next(req *http.Request) (HTTPFixture, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.load(); err != nil {
+		return HTTPFixture{}, err
+	}
+	key := fixtureKey(req.Method, req.URL.String())
+	list := f.entries[key]
+	if len(list) == 0 {
+		return HTTPFixture{}, fmt.Errorf("replay: no recorded response for %s in %s", key, f.Dir)
+	}
+	i := min(f.served[key], len(list)-1)
+	f.served[key]++
+	return list[i], nil
+}
+
+// RoundTrip serves req from the recorded fixtures.
+func (f *HTTPFixtures) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	fx, err := f.next(req)
+	if err != nil {
+		return nil, err
+	}
+	body := fx.Body
+	switch {
+	case len(fx.JSON) > 0:
+		body = fx.JSON
+	case fx.Text != "":
+		body = []byte(fx.Text)
+	}
+	header := http.Header{}
+	if fx.ContentType != "" {
+		header.Set("Content-Type", fx.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fx.Status, http.StatusText(fx.Status)),
+		StatusCode:    fx.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (f *HTTPFixtures) record(req *http.Request, resp *http.Response, body []byte) error {
+	fx := HTTPFixture{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	switch {
+	case len(body) == 0:
+	case json.Valid(body):
+		fx.JSON = body
+	case utf8.Valid(body):
+		fx.Text = string(body)
+	default:
+		fx.Body = body
+	}
+	data, err := json.MarshalIndent(fx, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return err
+	}
+	if f.seq == 0 {
+		// Continue after fixtures recorded by earlier runs.
+		names, err := f.files()
+		if err != nil {
+			return err
+		}
+		f.seq = len(names)
+	}
+	f.seq++
+	name := fmt.Sprintf("%04d-%s-%s.json", f.seq, strings.ToLower(req.Method), usageAPI(req.URL))
+	return os.WriteFile(filepath.Join(f.Dir, name), data, 0o600)
+}
+
+// RecordTransport saves every response Base returns to Fixtures.
+type RecordTransport struct {
+	Base     http.RoundTripper
+	Fixtures *HTTPFixtures
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err := t.Fixtures.record(req, resp, body); err != nil {
+		slog.Warn("recording HTTP fixture failed", "dir", t.Fixtures.Dir, "err", err)
+	}
+	return resp, nil
+}
+
+// replayClient returns a client answering every request from the
+// fixtures, or nil when not replaying. It needs no credentials.
+func replayClient(opts TransportOptions) *http.Client {
+	if opts.Fixtures == nil || !opts.Fixtures.Replay {
+		return nil
+	}
+	return &http.Client{Transport: opts.Fixtures}
+}
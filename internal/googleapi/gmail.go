@@ -13,5 +13,8 @@ func NewGmail(ctx context.Context, email string) (*gmail.Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	if opts, err = withEndpoint(ctx, "gmail", opts); err != nil {
+		return nil, err
+	}
 	return gmail.NewService(ctx, opts...)
 }
@@ -0,0 +1,166 @@
+package googleapi
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHedgePercentile is the observed latency percentile after which a hedged attempt is sent.
+	DefaultHedgePercentile = 95
+	// HedgeDefaultDelay is the hedge delay used until enough latency samples exist.
+	HedgeDefaultDelay = 750 * time.Millisecond
+	// HedgeMinDelay keeps fast endpoints from hedging every request.
+	HedgeMinDelay = 50 * time.Millisecond
+
+	hedgeMinSamples = 5
+	hedgeMaxSamples = 128
+)
+
+// HedgeTransport sends a second attempt for idempotent requests that are
+// slower than the configured latency percentile and returns whichever
+// response arrives first.
+type HedgeTransport struct {
+	Base       http.RoundTripper
+	Percentile float64
+
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// NewHedgeTransport creates a HedgeTransport; a non-positive percentile uses DefaultHedgePercentile.
+func NewHedgeTransport(base http.RoundTripper, percentile float64) *HedgeTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if percentile <= 0 || percentile > 100 {
+		percentile = DefaultHedgePercentile
+	}
+	return &HedgeTransport{Base: base, Percentile: percentile}
+}
+
+type hedgeAttempt struct {
+	index   int
+	resp    *http.Response
+	err     error
+	elapsed time.Duration
+}
+
+// RoundTrip implements http.RoundTripper with request hedging.
+func (t *HedgeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isHedgeable(req) {
+		return t.Base.RoundTrip(req)
+	}
+
+	results := make(chan hedgeAttempt, 2)
+	var cancels []context.CancelFunc
+	launch := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		idx := len(cancels)
+		cancels = append(cancels, cancel)
+		attemptReq := req.Clone(ctx)
+		start := time.Now()
+		go func() {
+			resp, err := t.Base.RoundTrip(attemptReq)
+			results <- hedgeAttempt{index: idx, resp: resp, err: err, elapsed: time.Since(start)}
+		}()
+	}
+
+	launch()
+	timer := time.NewTimer(t.delay())
+	defer timer.Stop()
+
+	inflight := 1
+	var firstErr error
+	for {
+		select {
+		case <-timer.C:
+			slog.Debug("hedging slow request", "method", req.Method, "url", req.URL.Redacted())
+			launch()
+			inflight++
+		case a := <-results:
+			inflight--
+			if a.err == nil {
+				t.record(a.elapsed)
+				for i, cancel := range cancels {
+					if i != a.index {
+						cancel()
+					}
+				}
+				go discardHedgeResults(results, inflight)
+				a.resp.Body = &cancelOnCloseBody{ReadCloser: a.resp.Body, cancel: cancels[a.index]}
+				return a.resp, nil
+			}
+			cancels[a.index]()
+			if firstErr == nil {
+				firstErr = a.err
+			}
+			// Errors are not hedged: only wait if another attempt is already in flight.
+			if inflight == 0 {
+				return nil, firstErr
+			}
+		}
+	}
+}
+
+func (t *HedgeTransport) delay() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.samples) < hedgeMinSamples {
+		return HedgeDefaultDelay
+	}
+	sorted := slices.Clone(t.samples)
+	slices.Sort(sorted)
+	idx := int(math.Ceil(t.Percentile/100*float64(len(sorted)))) - 1
+	idx = max(0, min(idx, len(sorted)-1))
+	return max(sorted[idx], HedgeMinDelay)
+}
+
+func (t *HedgeTransport) record(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.samples) < hedgeMaxSamples {
+		t.samples = append(t.samples, d)
+		return
+	}
+	t.samples[t.next] = d
+	t.next = (t.next + 1) % hedgeMaxSamples
+}
+
+func isHedgeable(req *http.Request) bool {
+	if req == nil {
+		return false
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody
+}
+
+func discardHedgeResults(results <-chan hedgeAttempt, n int) {
+	for range n {
+		a := <-results
+		if a.resp != nil {
+			drainAndClose(a.resp.Body)
+		}
+	}
+}
+
+// cancelOnCloseBody releases the winning attempt's context once the caller is done reading.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,131 @@
+package googleapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowFirstTransport delays the first call until its context is canceled or a long timeout.
+type slowFirstTransport struct {
+	calls atomic.Int32
+	delay time.Duration
+}
+
+func (s *slowFirstTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := s.calls.Add(1)
+	if n == 1 {
+		select {
+		case <-time.After(s.delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("slow"))}, nil
+	}
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("fast"))}, nil
+}
+
+func TestHedgeTransport_SecondAttemptWins(t *testing.T) {
+	base := &slowFirstTransport{delay: 2 * time.Second}
+	ht := NewHedgeTransport(base, 50)
+	for range hedgeMinSamples {
+		ht.record(10 * time.Millisecond)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	start := time.Now()
+	resp, err := ht.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(b) != "fast" {
+		t.Fatalf("expected hedged response, got %q", b)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("hedge did not cut latency: %s", time.Since(start))
+	}
+	if got := base.calls.Load(); got != 2 {
+		t.Fatalf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestHedgeTransport_FastRequestNotHedged(t *testing.T) {
+	mock := &mockTransport{
+		responses: []*http.Response{
+			{StatusCode: 200, Body: io.NopCloser(strings.NewReader("ok"))},
+		},
+	}
+	ht := NewHedgeTransport(mock, 0)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	resp, err := ht.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if mock.calls != 1 {
+		t.Fatalf("expected 1 call, got %d", mock.calls)
+	}
+	if ht.Percentile != DefaultHedgePercentile {
+		t.Fatalf("expected default percentile, got %v", ht.Percentile)
+	}
+}
+
+func TestHedgeTransport_NonIdempotentPassThrough(t *testing.T) {
+	base := &slowFirstTransport{delay: 100 * time.Millisecond}
+	ht := NewHedgeTransport(base, 50)
+	for range hedgeMinSamples {
+		ht.record(time.Millisecond)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com", strings.NewReader("x"))
+	resp, err := ht.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if got := base.calls.Load(); got != 1 {
+		t.Fatalf("expected POST to skip hedging, got %d calls", got)
+	}
+}
+
+func TestHedgeTransport_ErrorNotHedged(t *testing.T) {
+	boom := errors.New("boom")
+	mock := &mockTransport{errors: []error{boom}}
+	ht := NewHedgeTransport(mock, 0)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if _, err := ht.RoundTrip(req); !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if mock.calls != 1 {
+		t.Fatalf("expected 1 call, got %d", mock.calls)
+	}
+}
+
+func TestHedgeTransport_Delay(t *testing.T) {
+	ht := NewHedgeTransport(nil, 50)
+	if got := ht.delay(); got != HedgeDefaultDelay {
+		t.Fatalf("expected default delay without samples, got %s", got)
+	}
+	for i := 1; i <= 10; i++ {
+		ht.record(time.Duration(i) * 100 * time.Millisecond)
+	}
+	if got := ht.delay(); got != 500*time.Millisecond {
+		t.Fatalf("expected p50 of 500ms, got %s", got)
+	}
+
+	ht = NewHedgeTransport(nil, 99)
+	for range 10 {
+		ht.record(time.Millisecond)
+	}
+	if got := ht.delay(); got != HedgeMinDelay {
+		t.Fatalf("expected min delay clamp, got %s", got)
+	}
+}
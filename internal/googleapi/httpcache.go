@@ -0,0 +1,176 @@
+package googleapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/statefile"
+)
+
+// maxHTTPCacheBody bounds the responses kept in the cache; larger ones
+// (media downloads, big exports) pass through uncached.
+const maxHTTPCacheBody = 4 << 20
+
+// HTTPCacheHeader is set on responses served from the cache after the
+// server answered 304 Not Modified.
+const HTTPCacheHeader = "X-Gog-Cache"
+
+// HTTPCache keeps GET responses that carried an ETag on disk, one directory
+// per account, so repeated calls can be revalidated with If-None-Match.
+type HTTPCache struct {
+	Dir string
+}
+
+type httpCacheEntry struct {
+	URL         string    `json:"url"`
+	ETag        string    `json:"etag"`
+	ContentType string    `json:"contentType,omitempty"`
+	Body        []byte    `json:"body"`
+	Stored      time.Time `json:"stored"`
+}
+
+func hashKey(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (c *HTTPCache) accountDir(account string) string {
+	return filepath.Join(c.Dir, hashKey(strings.ToLower(strings.TrimSpace(account)))[:16])
+}
+
+func (c *HTTPCache) path(account, url string) string {
+	return filepath.Join(c.accountDir(account), hashKey(url)+".json")
+}
+
+func (c *HTTPCache) load(account, url string) *httpCacheEntry {
+	data, err := statefile.ReadFile(c.path(account, url))
+	if err != nil {
+		return nil
+	}
+	var e httpCacheEntry
+	if json.Unmarshal(data, &e) != nil || e.URL != url || e.ETag == "" {
+		return nil
+	}
+	return &e
+}
+
+func (c *HTTPCache) store(account string, e httpCacheEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	path := c.path(account, e.URL)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return statefile.WriteFile(path, data, 0o600)
+}
+
+// Clear removes the cached responses of account, or of every account when
+// account is empty, and reports how many were removed.
+func (c *HTTPCache) Clear(account string) (int, error) {
+	root := c.Dir
+	if strings.TrimSpace(account) != "" {
+		root = c.accountDir(account)
+	}
+	n := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".json") {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+	_ = os.RemoveAll(root)
+	return n, nil
+}
+
+// CacheTransport answers repeated GETs from an HTTPCache: requests for a
+// cached URL carry If-None-Match, and a 304 is turned back into the cached
+// 200 response. Other methods pass through.
+type CacheTransport struct {
+	Base    http.RoundTripper
+	Account string
+	Cache   *HTTPCache
+}
+
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" || t.Cache == nil {
+		return t.Base.RoundTrip(req)
+	}
+	url := req.URL.String()
+	cached := t.Cache.load(t.Account, url)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		drainAndClose(resp.Body)
+		slog.Debug("http cache revalidated", "url", url)
+		header := http.Header{}
+		header.Set("ETag", cached.ETag)
+		header.Set(HTTPCacheHeader, "revalidated")
+		if cached.ContentType != "" {
+			header.Set("Content-Type", cached.ContentType)
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+			ContentLength: int64(len(cached.Body)),
+			Request:       req,
+		}, nil
+	case resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "" && resp.Body != nil:
+		head, readErr := io.ReadAll(io.LimitReader(resp.Body, maxHTTPCacheBody+1))
+		if readErr != nil || len(head) > maxHTTPCacheBody {
+			resp.Body = readCloser{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(head))
+		e := httpCacheEntry{URL: url, ETag: resp.Header.Get("ETag"), ContentType: resp.Header.Get("Content-Type"), Body: head, Stored: time.Now().UTC()}
+		if err := t.Cache.store(t.Account, e); err != nil {
+			slog.Debug("http cache write failed", "url", url, "err", err)
+		}
+	}
+	return resp, nil
+}
+
+// withCache wraps c's transport when the context enables the HTTP cache.
+func withCache(opts TransportOptions, c *http.Client, email string) {
+	if opts.Cache != nil {
+		c.Transport = &CacheTransport{Base: c.Transport, Account: email, Cache: opts.Cache}
+	}
+}
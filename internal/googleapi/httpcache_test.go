@@ -0,0 +1,78 @@
+package googleapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCacheTransport_RevalidatesWithETag(t *testing.T) {
+	t.Setenv("GOG_CONFIG_DIR", t.TempDir())
+
+	var hits, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"items":[1,2]}`)
+	}))
+	defer srv.Close()
+
+	cache := &HTTPCache{Dir: t.TempDir()}
+	ct := &CacheTransport{Base: http.DefaultTransport, Account: "a@b.com", Cache: cache}
+	get := func(account string) (*http.Response, string) {
+		t.Helper()
+		ct.Account = account
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/calendar/v3/calendars/primary/events?maxResults=10", nil)
+		resp, err := ct.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return resp, string(body)
+	}
+
+	resp, body := get("a@b.com")
+	if resp.StatusCode != 200 || body != `{"items":[1,2]}` || resp.Header.Get(HTTPCacheHeader) != "" {
+		t.Fatalf("first: %d %q", resp.StatusCode, body)
+	}
+	resp, body = get("A@b.com")
+	if resp.StatusCode != 200 || body != `{"items":[1,2]}` || resp.Header.Get(HTTPCacheHeader) != "revalidated" ||
+		resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("second: %d %q %v", resp.StatusCode, body, resp.Header)
+	}
+	// Another account has its own cache.
+	if resp, _ = get("c@d.com"); resp.Header.Get(HTTPCacheHeader) != "" {
+		t.Fatalf("cache shared across accounts")
+	}
+	if hits != 3 || notModified != 1 {
+		t.Fatalf("hits=%d notModified=%d", hits, notModified)
+	}
+
+	post, _ := http.NewRequest(http.MethodPost, srv.URL+"/calendar/v3/calendars/primary/events?maxResults=10", strings.NewReader("{}"))
+	if _, err := ct.RoundTrip(post); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if notModified != 1 {
+		t.Fatalf("POST must not be revalidated")
+	}
+
+	n, err := cache.Clear("a@b.com")
+	if err != nil || n != 1 {
+		t.Fatalf("clear account: n=%d err=%v", n, err)
+	}
+	if n, err = cache.Clear(""); err != nil || n != 1 {
+		t.Fatalf("clear all: n=%d err=%v", n, err)
+	}
+	if resp, _ = get("a@b.com"); resp.Header.Get(HTTPCacheHeader) != "" {
+		t.Fatalf("served from a cleared cache")
+	}
+}
@@ -0,0 +1,21 @@
+package googleapi
+
+import (
+	"context"
+
+	"google.golang.org/api/licensing/v1"
+)
+
+// NewLicensing creates an Enterprise License Manager client. The scope is
+// only granted to Workspace admins, so it is requested on first use rather
+// than at `gog auth add`.
+func NewLicensing(ctx context.Context, email string) (*licensing.Service, error) {
+	opts, err := optionsForAccountScopes(ctx, "admin", email, []string{licensing.AppsLicensingScope})
+	if err != nil {
+		return nil, err
+	}
+	if opts, err = withEndpoint(ctx, "licensing", opts); err != nil {
+		return nil, err
+	}
+	return licensing.NewService(ctx, opts...)
+}
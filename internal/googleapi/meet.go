@@ -0,0 +1,20 @@
+package googleapi
+
+import (
+	"context"
+
+	"google.golang.org/api/meet/v2"
+)
+
+// NewMeet creates a Meet REST API client for reading conference records,
+// participants and transcripts (meetings.space.readonly scope).
+func NewMeet(ctx context.Context, email string) (*meet.Service, error) {
+	opts, err := optionsForAccountScopes(ctx, "meet", email, []string{meet.MeetingsSpaceReadonlyScope})
+	if err != nil {
+		return nil, err
+	}
+	if opts, err = withEndpoint(ctx, "meet", opts); err != nil {
+		return nil, err
+	}
+	return meet.NewService(ctx, opts...)
+}
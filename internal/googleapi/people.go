@@ -17,6 +17,9 @@ func NewPeopleContacts(ctx context.Context, email string) (*people.Service, erro
 	if err != nil {
 		return nil, err
 	}
+	if opts, err = withEndpoint(ctx, "people", opts); err != nil {
+		return nil, err
+	}
 	return people.NewService(ctx, opts...)
 }
 
@@ -25,6 +28,9 @@ func NewPeopleOtherContacts(ctx context.Context, email string) (*people.Service,
 	if err != nil {
 		return nil, err
 	}
+	if opts, err = withEndpoint(ctx, "people", opts); err != nil {
+		return nil, err
+	}
 	return people.NewService(ctx, opts...)
 }
 
@@ -33,5 +39,8 @@ func NewPeopleDirectory(ctx context.Context, email string) (*people.Service, err
 	if err != nil {
 		return nil, err
 	}
+	if opts, err = withEndpoint(ctx, "people", opts); err != nil {
+		return nil, err
+	}
 	return people.NewService(ctx, opts...)
 }
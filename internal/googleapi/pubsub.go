@@ -0,0 +1,19 @@
+package googleapi
+
+import (
+	"context"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/pubsub/v1"
+)
+
+// NewPubSub authenticates with Application Default Credentials rather than
+// an account token: a Gmail watch's subscription belongs to a GCP project,
+// which the Gmail OAuth grant has no access to.
+func NewPubSub(ctx context.Context) (*pubsub.Service, error) {
+	opts, err := withEndpoint(ctx, "pubsub", []option.ClientOption{option.WithScopes(pubsub.PubsubScope)})
+	if err != nil {
+		return nil, err
+	}
+	return pubsub.NewService(ctx, opts...)
+}
@@ -0,0 +1,90 @@
+package googleapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter spaces requests evenly at a fixed rate (no bursts).
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func NewRateLimiter(qps float64) *RateLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	return &RateLimiter{interval: time.Duration(float64(time.Second) / qps)}
+}
+
+// Wait blocks until the caller's slot comes up or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	d := time.Until(slot)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var (
+	accountLimitersMu sync.Mutex
+	accountLimiters   = map[string]*RateLimiter{}
+)
+
+// accountLimiter returns the process-wide limiter of email at qps, so every
+// API client for the account shares one request rate.
+func accountLimiter(email string, qps float64) *RateLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	key := strings.ToLower(strings.TrimSpace(email))
+	accountLimitersMu.Lock()
+	defer accountLimitersMu.Unlock()
+	l, ok := accountLimiters[key]
+	if !ok || l.interval != NewRateLimiter(qps).interval {
+		l = NewRateLimiter(qps)
+		accountLimiters[key] = l
+	}
+	return l
+}
+
+// RateLimitTransport waits for the limiter before every attempt, retries
+// and hedged requests included.
+type RateLimitTransport struct {
+	Base    http.RoundTripper
+	Limiter *RateLimiter
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.Limiter.Wait(req.Context()); err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.Base.RoundTrip(req)
+}
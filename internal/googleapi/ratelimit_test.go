@@ -0,0 +1,73 @@
+package googleapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_SpacesRequests(t *testing.T) {
+	l := NewRateLimiter(50) // 20ms apart
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 55*time.Millisecond {
+		t.Fatalf("4 requests at 50 qps took %v", elapsed)
+	}
+	if NewRateLimiter(0) != nil {
+		t.Fatalf("qps 0 should mean no limiter")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slow := NewRateLimiter(0.01)
+	_ = slow.Wait(context.Background())
+	if err := slow.Wait(ctx); err == nil {
+		t.Fatalf("expected context error")
+	}
+}
+
+func TestAccountLimiter_SharedPerAccount(t *testing.T) {
+	a := accountLimiter("Ada@x.com", 5)
+	if a == nil || accountLimiter("ada@x.com", 5) != a {
+		t.Fatalf("limiter should be shared per account")
+	}
+	if accountLimiter("bob@x.com", 5) == a {
+		t.Fatalf("accounts should not share a limiter")
+	}
+	if accountLimiter("ada@x.com", 0) != nil {
+		t.Fatalf("qps 0 should disable limiting")
+	}
+}
+
+func TestRetryTransport_PolicyAndServerRetryAfter(t *testing.T) {
+	mock := &mockTransport{responses: []*http.Response{
+		{StatusCode: 503, Header: http.Header{"Retry-After": []string{"0"}}, Body: http.NoBody},
+		{StatusCode: 500, Header: http.Header{}, Body: http.NoBody},
+		{StatusCode: 500, Header: http.Header{}, Body: http.NoBody},
+		{StatusCode: 200, Body: http.NoBody},
+	}}
+	rt := NewRetryTransport(mock)
+	RetryPolicy{MaxRetries429: 0, MaxRetries5xx: 3, ServerErrorDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}.apply(rt)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil || resp.StatusCode != 200 || mock.calls != 4 {
+		t.Fatalf("resp=%v err=%v calls=%d", resp, err, mock.calls)
+	}
+	if d := rt.serverErrorBackoff(10, &http.Response{Header: http.Header{}}); d != 2*time.Millisecond {
+		t.Fatalf("backoff not capped: %v", d)
+	}
+	if d := rt.calculateBackoff(0, &http.Response{Header: http.Header{"Retry-After": []string{"5"}}}); d != 5*time.Second {
+		t.Fatalf("Retry-After must not be capped: %v", d)
+	}
+
+	p := DefaultRetryPolicy()
+	if p.maxBackoff() != 3*time.Second/2+3*time.Second+6*time.Second+time.Second {
+		t.Fatalf("maxBackoff = %v", p.maxBackoff())
+	}
+}
@@ -12,3 +12,50 @@ const (
 	// ServerErrorRetryDelay is the delay before retrying on 5xx errors.
 	ServerErrorRetryDelay = 1 * time.Second
 )
+
+// RetryPolicy holds the RetryTransport knobs that users can tune (config
+// http.* keys and GOG_* env vars).
+type RetryPolicy struct {
+	MaxRetries429    int
+	MaxRetries5xx    int
+	BaseDelay        time.Duration
+	ServerErrorDelay time.Duration
+	MaxDelay         time.Duration
+}
+
+// DefaultRetryPolicy is the policy used when nothing is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries429:    MaxRateLimitRetries,
+		MaxRetries5xx:    Max5xxRetries,
+		BaseDelay:        RateLimitBaseDelay,
+		ServerErrorDelay: ServerErrorRetryDelay,
+	}
+}
+
+// maxBackoff is the longest total backoff the policy can sleep (jitter
+// included, Retry-After excluded).
+func (p RetryPolicy) maxBackoff() time.Duration {
+	capped := func(d time.Duration) time.Duration {
+		if p.MaxDelay > 0 && d > p.MaxDelay {
+			return p.MaxDelay
+		}
+		return d
+	}
+	var total time.Duration
+	for i := 0; i < p.MaxRetries429; i++ {
+		total += capped(p.BaseDelay * time.Duration(1<<min(i, 20)) * 3 / 2)
+	}
+	for i := 0; i < p.MaxRetries5xx; i++ {
+		total += capped(p.ServerErrorDelay * time.Duration(1<<min(i, 20)))
+	}
+	return total
+}
+
+func (p RetryPolicy) apply(t *RetryTransport) {
+	t.MaxRetries429 = p.MaxRetries429
+	t.MaxRetries5xx = p.MaxRetries5xx
+	t.BaseDelay = p.BaseDelay
+	t.ServerErrorDelay = p.ServerErrorDelay
+	t.MaxDelay = p.MaxDelay
+}
@@ -0,0 +1,66 @@
+package googleapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxScopeErrorBody bounds how much of a 403 body is inspected.
+const maxScopeErrorBody = 64 << 10
+
+// InsufficientScopeError means the account's token lacks OAuth scopes the
+// API call needs (typically the account was added with fewer --services).
+type InsufficientScopeError struct {
+	Service  string
+	Email    string
+	Required []string
+}
+
+func (e *InsufficientScopeError) Error() string {
+	return fmt.Sprintf("insufficient OAuth scopes for %s %s", e.Service, e.Email)
+}
+
+// ScopeTransport turns Google's "insufficient authentication scopes" 403
+// into an InsufficientScopeError so callers can offer a scope upgrade.
+type ScopeTransport struct {
+	Base     http.RoundTripper
+	Service  string
+	Email    string
+	Required []string
+}
+
+func (t *ScopeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusForbidden {
+		return resp, err
+	}
+	if strings.Contains(resp.Header.Get("WWW-Authenticate"), "insufficient_scope") {
+		_ = resp.Body.Close()
+		return nil, t.scopeError()
+	}
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxScopeErrorBody))
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr == nil && isInsufficientScopeBody(body) {
+		return nil, t.scopeError()
+	}
+	return resp, nil
+}
+
+func (t *ScopeTransport) scopeError() error {
+	return &InsufficientScopeError{
+		Service:  t.Service,
+		Email:    t.Email,
+		Required: append([]string(nil), t.Required...),
+	}
+}
+
+func isInsufficientScopeBody(body []byte) bool {
+	s := string(body)
+	return strings.Contains(s, "ACCESS_TOKEN_SCOPE_INSUFFICIENT") ||
+		strings.Contains(s, "insufficient authentication scopes") ||
+		strings.Contains(s, "Insufficient Permission: Request had insufficient authentication scopes")
+}
@@ -0,0 +1,53 @@
+package googleapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type scopeStubTransport struct {
+	header string
+	body   string
+}
+
+func (s scopeStubTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	h := http.Header{}
+	if s.header != "" {
+		h.Set("WWW-Authenticate", s.header)
+	}
+	return &http.Response{StatusCode: http.StatusForbidden, Header: h, Body: io.NopCloser(strings.NewReader(s.body))}, nil
+}
+
+func TestScopeTransport(t *testing.T) {
+	cases := []struct {
+		name    string
+		base    scopeStubTransport
+		isScope bool
+	}{
+		{"header", scopeStubTransport{header: `Bearer realm="https://accounts.google.com/", error="insufficient_scope"`}, true},
+		{"body", scopeStubTransport{body: `{"error":{"code":403,"message":"Request had insufficient authentication scopes.","details":[{"reason":"ACCESS_TOKEN_SCOPE_INSUFFICIENT"}]}}`}, true},
+		{"other403", scopeStubTransport{body: `{"error":{"code":403,"message":"The user does not have sufficient permissions for file x."}}`}, false},
+	}
+	for _, tc := range cases {
+		st := &ScopeTransport{Base: tc.base, Service: "gmail", Email: "a@b.com", Required: []string{"s1"}}
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+		resp, err := st.RoundTrip(req)
+		var scopeErr *InsufficientScopeError
+		if tc.isScope {
+			if !errors.As(err, &scopeErr) || scopeErr.Service != "gmail" || scopeErr.Required[0] != "s1" {
+				t.Fatalf("%s: expected InsufficientScopeError, got %v", tc.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != tc.base.body {
+			t.Fatalf("%s: body not preserved: %q", tc.name, body)
+		}
+	}
+}
@@ -32,6 +32,9 @@ func TestNewServices_HappyPath(t *testing.T) {
 	if svc, err := NewDrive(ctx, "a@b.com"); err != nil || svc == nil {
 		t.Fatalf("NewDrive: %v", err)
 	}
+	if svc, err := NewDocs(ctx, "a@b.com"); err != nil || svc == nil {
+		t.Fatalf("NewDocs: %v", err)
+	}
 	if svc, err := NewCalendar(ctx, "a@b.com"); err != nil || svc == nil {
 		t.Fatalf("NewCalendar: %v", err)
 	}
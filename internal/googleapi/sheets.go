@@ -16,6 +16,9 @@ func NewSheets(ctx context.Context, email string) (*sheets.Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	if opts, err = withEndpoint(ctx, "sheets", opts); err != nil {
+		return nil, err
+	}
 
 	svc, err := sheets.NewService(ctx, opts...)
 	if err != nil {
@@ -12,5 +12,8 @@ func NewTasks(ctx context.Context, email string) (*tasks.Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	if opts, err = withEndpoint(ctx, "tasks", opts); err != nil {
+		return nil, err
+	}
 	return tasks.NewService(ctx, opts...)
 }
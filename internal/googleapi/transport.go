@@ -13,11 +13,18 @@ import (
 // RetryTransport wraps an http.RoundTripper with retry logic for
 // rate limits (429) and server errors (5xx).
 type RetryTransport struct {
-	Base           http.RoundTripper
-	MaxRetries429  int
-	MaxRetries5xx  int
-	BaseDelay      time.Duration
+	Base          http.RoundTripper
+	MaxRetries429 int
+	MaxRetries5xx int
+	BaseDelay     time.Duration
+	// ServerErrorDelay is the first 5xx retry delay; it doubles per retry.
+	ServerErrorDelay time.Duration
+	// MaxDelay caps computed backoff (0 = no cap). Retry-After is always
+	// honored as sent.
+	MaxDelay       time.Duration
 	CircuitBreaker *CircuitBreaker
+	// Stats, when set, counts the retries sent.
+	Stats *UsageStats
 }
 
 // NewRetryTransport creates a RetryTransport with sensible defaults.
@@ -25,13 +32,9 @@ func NewRetryTransport(base http.RoundTripper) *RetryTransport {
 	if base == nil {
 		base = http.DefaultTransport
 	}
-	return &RetryTransport{
-		Base:           base,
-		MaxRetries429:  MaxRateLimitRetries,
-		MaxRetries5xx:  Max5xxRetries,
-		BaseDelay:      RateLimitBaseDelay,
-		CircuitBreaker: NewCircuitBreaker(),
-	}
+	t := &RetryTransport{Base: base, CircuitBreaker: NewCircuitBreaker()}
+	DefaultRetryPolicy().apply(t)
+	return t
 }
 
 // RoundTrip implements http.RoundTripper with retry logic.
@@ -93,6 +96,7 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			}
 
 			retries429++
+			t.Stats.recordRetry(req)
 			continue
 		}
 
@@ -112,11 +116,13 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 			drainAndClose(resp.Body)
 
-			if err := t.sleep(req.Context(), ServerErrorRetryDelay); err != nil {
+			delay := t.serverErrorBackoff(retries5xx, resp)
+			if err := t.sleep(req.Context(), delay); err != nil {
 				return nil, err
 			}
 
 			retries5xx++
+			t.Stats.recordRetry(req)
 			continue
 		}
 
@@ -125,39 +131,58 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 }
 
+// retryAfter parses a Retry-After header (seconds or HTTP date).
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+func (t *RetryTransport) capDelay(d time.Duration) time.Duration {
+	if t.MaxDelay > 0 && d > t.MaxDelay {
+		return t.MaxDelay
+	}
+	return d
+}
+
+func (t *RetryTransport) serverErrorBackoff(attempt int, resp *http.Response) time.Duration {
+	if d, ok := retryAfter(resp); ok {
+		return d
+	}
+	if t.ServerErrorDelay <= 0 {
+		return 0
+	}
+	return t.capDelay(t.ServerErrorDelay * time.Duration(1<<min(attempt, 20)))
+}
+
 func (t *RetryTransport) calculateBackoff(attempt int, resp *http.Response) time.Duration {
-	// Check Retry-After header
-	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
-		if seconds, err := strconv.Atoi(retryAfter); err == nil {
-			if seconds < 0 {
-				return 0
-			}
-			return time.Duration(seconds) * time.Second
-		}
-		if t, err := http.ParseTime(retryAfter); err == nil {
-			d := time.Until(t)
-			if d < 0 {
-				return 0
-			}
-			return d
-		}
+	if d, ok := retryAfter(resp); ok {
+		return d
 	}
 
 	// Exponential backoff with jitter: 1s, 2s, 4s...
 	if t.BaseDelay <= 0 {
 		return 0
 	}
-	baseDelay := t.BaseDelay * time.Duration(1<<attempt)
+	baseDelay := t.BaseDelay * time.Duration(1<<min(attempt, 20))
 	if baseDelay <= 0 {
 		return 0
 	}
 
 	jitterRange := baseDelay / 2
 	if jitterRange <= 0 {
-		return baseDelay
+		return t.capDelay(baseDelay)
 	}
 	jitter := time.Duration(rand.Int64N(int64(jitterRange)))
-	return baseDelay + jitter
+	return t.capDelay(baseDelay + jitter)
 }
 
 func (t *RetryTransport) sleep(ctx context.Context, d time.Duration) error {
@@ -0,0 +1,60 @@
+package googleapi
+
+import (
+	"context"
+	"net/url"
+)
+
+// TransportOptions tunes the HTTP stack built for API clients.
+// Commands attach them to the context; service constructors read them back.
+type TransportOptions struct {
+	// Hedge enables hedged requests for idempotent GETs.
+	Hedge bool
+	// HedgePercentile selects the observed latency percentile after which a
+	// second attempt is sent (0 uses DefaultHedgePercentile).
+	HedgePercentile float64
+	// MaxConnsPerHost caps concurrent connections per API host (0 = unlimited).
+	MaxConnsPerHost int
+	// Budget, when set, caps the total number of requests sent.
+	Budget *RequestBudget
+	// Retry, when set, replaces the default retry/backoff policy.
+	Retry *RetryPolicy
+	// QPS caps requests per second per account (0 = unlimited).
+	QPS float64
+	// Endpoints maps API names (see EndpointAPIs) to base URLs replacing
+	// Google's, e.g. for emulators or gateways.
+	Endpoints map[string]string
+	// Audit, when set, is told about every mutating request.
+	Audit func(AuditCall)
+	// Cache, when set, revalidates repeated GETs with their ETag.
+	Cache *HTTPCache
+	// Stats, when set, counts requests, retries and 429s per API.
+	Stats *UsageStats
+	// Proxy, when set, replaces the HTTPS_PROXY/HTTP_PROXY environment.
+	Proxy *url.URL
+	// CABundle, when set, verifies servers against its pool (see LoadCABundle).
+	CABundle *CABundle
+	// InsecureSkipVerify turns off TLS certificate verification.
+	InsecureSkipVerify bool
+	// Fixtures, when set, records responses to disk or replays them
+	// (GOG_HTTP_RECORD / GOG_HTTP_REPLAY).
+	Fixtures *HTTPFixtures
+}
+
+type transportOptionsKey struct{}
+
+func WithTransportOptions(ctx context.Context, opts TransportOptions) context.Context {
+	return context.WithValue(ctx, transportOptionsKey{}, opts)
+}
+
+func TransportOptionsFromContext(ctx context.Context) TransportOptions {
+	if ctx == nil {
+		return TransportOptions{}
+	}
+	if v := ctx.Value(transportOptionsKey{}); v != nil {
+		if o, ok := v.(TransportOptions); ok {
+			return o
+		}
+	}
+	return TransportOptions{}
+}
@@ -0,0 +1,133 @@
+package googleapi
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// APIUsage counts the requests one API received during a run.
+type APIUsage struct {
+	API         string `json:"api"`
+	Calls       int64  `json:"calls"`
+	Retries     int64  `json:"retries,omitempty"`
+	RateLimited int64  `json:"rateLimited,omitempty"`
+	Errors      int64  `json:"errors,omitempty"`
+}
+
+// UsageStats collects per-API request counts for one invocation. It is
+// shared by every API client created for the invocation.
+type UsageStats struct {
+	mu        sync.Mutex
+	apis      map[string]*APIUsage
+	succeeded int64
+}
+
+func NewUsageStats() *UsageStats {
+	return &UsageStats{apis: map[string]*APIUsage{}}
+}
+
+func (s *UsageStats) add(api string, f func(*APIUsage)) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.apis[api]
+	if !ok {
+		u = &APIUsage{API: api}
+		s.apis[api] = u
+	}
+	f(u)
+}
+
+// Snapshot returns the counts so far, busiest API first.
+func (s *UsageStats) Snapshot() []APIUsage {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]APIUsage, 0, len(s.apis))
+	for _, u := range s.apis {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Calls != out[j].Calls {
+			return out[i].Calls > out[j].Calls
+		}
+		return out[i].API < out[j].API
+	})
+	return out
+}
+
+// Succeeded reports how many requests got a non-error response, i.e.
+// whether the invocation may already have changed something or printed
+// results.
+func (s *UsageStats) Succeeded() int64 {
+	if s == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.succeeded
+}
+
+// usageAPI names the API a request goes to: the googleapis.com subdomain
+// (gmail, people, sheets, ...), the first path segment on www.googleapis.com
+// (drive, calendar, ...), or the host for other endpoints.
+func usageAPI(u *url.URL) string {
+	if u == nil {
+		return "unknown"
+	}
+	host := strings.ToLower(u.Hostname())
+	sub, ok := strings.CutSuffix(host, ".googleapis.com")
+	if !ok {
+		return host
+	}
+	if sub != "www" {
+		return sub
+	}
+	for _, seg := range strings.Split(strings.Trim(u.Path, "/"), "/") {
+		if seg != "" && seg != "upload" && seg != "batch" {
+			return seg
+		}
+	}
+	return host
+}
+
+// StatsTransport counts every attempt it sends, the 429 responses and the
+// other failures in a UsageStats.
+type StatsTransport struct {
+	Base  http.RoundTripper
+	Stats *UsageStats
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *StatsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	t.Stats.add(usageAPI(req.URL), func(u *APIUsage) {
+		u.Calls++
+		switch {
+		case err != nil:
+			u.Errors++
+		case resp.StatusCode == http.StatusTooManyRequests:
+			u.RateLimited++
+		case resp.StatusCode >= 400:
+			u.Errors++
+		}
+	})
+	if err == nil && resp.StatusCode < 400 && t.Stats != nil {
+		t.Stats.mu.Lock()
+		t.Stats.succeeded++
+		t.Stats.mu.Unlock()
+	}
+	return resp, err
+}
+
+// recordRetry notes that req is being sent again.
+func (s *UsageStats) recordRetry(req *http.Request) {
+	s.add(usageAPI(req.URL), func(u *APIUsage) { u.Retries++ })
+}
@@ -0,0 +1,69 @@
+package googleapi
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestUsageAPI(t *testing.T) {
+	for raw, want := range map[string]string{
+		"https://gmail.googleapis.com/gmail/v1/users/me/messages": "gmail",
+		"https://www.googleapis.com/drive/v3/files":               "drive",
+		"https://www.googleapis.com/upload/drive/v3/files":        "drive",
+		"https://www.googleapis.com/calendar/v3/calendars":        "calendar",
+		"http://127.0.0.1:8080/gmail/v1/users/me":                 "127.0.0.1",
+	} {
+		u, _ := url.Parse(raw)
+		if got := usageAPI(u); got != want {
+			t.Fatalf("%s: got %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestStatsTransport_CountsAttemptsRetriesAndRateLimits(t *testing.T) {
+	stats := NewUsageStats()
+	mock := &mockTransport{responses: []*http.Response{
+		{StatusCode: 429, Header: http.Header{"Retry-After": []string{"0"}}, Body: http.NoBody},
+		{StatusCode: 200, Body: http.NoBody},
+		{StatusCode: 404, Body: http.NoBody},
+	}}
+	rt := NewRetryTransport(&StatsTransport{Base: mock, Stats: stats})
+	rt.Stats = stats
+	RetryPolicy{MaxRetries429: 2, BaseDelay: time.Millisecond}.apply(rt)
+
+	for _, raw := range []string{"https://gmail.googleapis.com/gmail/v1/a", "https://gmail.googleapis.com/gmail/v1/b"} {
+		req, _ := http.NewRequest(http.MethodGet, raw, nil)
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip: %v", err)
+		}
+	}
+	got := stats.Snapshot()
+	want := APIUsage{API: "gmail", Calls: 3, Retries: 1, RateLimited: 1, Errors: 1}
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestStatsTransport_CountsSucceeded(t *testing.T) {
+	stats := NewUsageStats()
+	mock := &mockTransport{responses: []*http.Response{
+		{StatusCode: 200, Body: http.NoBody},
+		{StatusCode: 403, Body: http.NoBody},
+		{StatusCode: 200, Body: http.NoBody},
+	}}
+	rt := &StatsTransport{Base: mock, Stats: stats}
+
+	// The rejected POST does not count.
+	for i, method := range []string{http.MethodGet, http.MethodPost, http.MethodPost} {
+		req, _ := http.NewRequest(method, "https://gmail.googleapis.com/gmail/v1/users/me/messages/send", nil)
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip: %v", err)
+		}
+		want := []int64{1, 1, 2}[i]
+		if got := stats.Succeeded(); got != want {
+			t.Fatalf("after request %d: Succeeded() = %d, want %d", i, got, want)
+		}
+	}
+}
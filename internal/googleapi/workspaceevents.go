@@ -0,0 +1,21 @@
+package googleapi
+
+import (
+	"context"
+
+	"google.golang.org/api/workspaceevents/v1"
+)
+
+// NewWorkspaceEvents creates a Workspace Events API client. The API
+// requires read access to the subscribed resource, so callers pass the
+// scopes of the target (Chat, Drive or Meet).
+func NewWorkspaceEvents(ctx context.Context, email string, scopes []string) (*workspaceevents.Service, error) {
+	opts, err := optionsForAccountScopes(ctx, "events", email, scopes)
+	if err != nil {
+		return nil, err
+	}
+	if opts, err = withEndpoint(ctx, "workspaceevents", opts); err != nil {
+		return nil, err
+	}
+	return workspaceevents.NewService(ctx, opts...)
+}
@@ -11,8 +11,19 @@ import (
 type Mode struct {
 	JSON  bool
 	Plain bool
+	// Table is TableCSV or TableTSV for delimited table output; both imply
+	// Plain for everything that is not a table.
+	Table string
+	// NDJSON streams paginated results one compact JSON object per line;
+	// it implies JSON for commands that do not stream.
+	NDJSON bool
 }
 
+const (
+	TableCSV = "csv"
+	TableTSV = "tsv"
+)
+
 type ParseError struct{ msg string }
 
 func (e *ParseError) Error() string { return e.msg }
@@ -46,8 +57,12 @@ func FromContext(ctx context.Context) Mode {
 	return Mode{}
 }
 
-func IsJSON(ctx context.Context) bool  { return FromContext(ctx).JSON }
-func IsPlain(ctx context.Context) bool { return FromContext(ctx).Plain }
+func IsJSON(ctx context.Context) bool   { return FromContext(ctx).JSON }
+func IsPlain(ctx context.Context) bool  { return FromContext(ctx).Plain }
+func IsNDJSON(ctx context.Context) bool { return FromContext(ctx).NDJSON }
+
+// TableFormat reports TableCSV, TableTSV, or "" for aligned/plain tables.
+func TableFormat(ctx context.Context) string { return FromContext(ctx).Table }
 
 func WriteJSON(w io.Writer, v any) error {
 	enc := json.NewEncoder(w)
@@ -56,6 +71,13 @@ func WriteJSON(w io.Writer, v any) error {
 	return enc.Encode(v)
 }
 
+// WriteNDJSON writes v as a single compact JSON line.
+func WriteNDJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
 func envBool(key string) bool {
 	v := strings.TrimSpace(strings.ToLower(os.Getenv(key)))
 	switch v {
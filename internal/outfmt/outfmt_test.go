@@ -39,3 +39,13 @@ func TestWriteJSON(t *testing.T) {
 		t.Fatalf("expected output")
 	}
 }
+
+func TestWriteNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, map[string]any{"a": "<b>", "n": 1}); err != nil {
+		t.Fatalf("WriteNDJSON: %v", err)
+	}
+	if buf.String() != "{\"a\":\"<b>\",\"n\":1}\n" {
+		t.Fatalf("got %q", buf.String())
+	}
+}
@@ -0,0 +1,68 @@
+package outfmt
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// DelimitedWriter converts tab-separated table rows (as written for
+// tabwriter) into CSV or TSV. Rows are emitted per complete line; Flush
+// writes a trailing partial line.
+type DelimitedWriter struct {
+	out    io.Writer
+	csv    *csv.Writer
+	buf    bytes.Buffer
+	format string
+}
+
+func NewDelimitedWriter(w io.Writer, format string) *DelimitedWriter {
+	d := &DelimitedWriter{out: w, format: format}
+	if format == TableCSV {
+		d.csv = csv.NewWriter(w)
+	}
+	return d
+}
+
+func (d *DelimitedWriter) Write(p []byte) (int, error) {
+	d.buf.Write(p)
+	for {
+		i := bytes.IndexByte(d.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(d.buf.Next(i + 1))
+		if err := d.writeRow(strings.TrimRight(line, "\r\n")); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+func (d *DelimitedWriter) Flush() error {
+	if d.buf.Len() > 0 {
+		line := d.buf.String()
+		d.buf.Reset()
+		if err := d.writeRow(line); err != nil {
+			return err
+		}
+	}
+	if d.csv != nil {
+		d.csv.Flush()
+		return d.csv.Error()
+	}
+	return nil
+}
+
+func (d *DelimitedWriter) writeRow(line string) error {
+	cells := strings.Split(line, "\t")
+	if d.csv != nil {
+		return d.csv.Write(cells)
+	}
+	// TSV: tabs already delimit cells; keep stray CRs out of the stream.
+	for i, c := range cells {
+		cells[i] = strings.ReplaceAll(c, "\r", " ")
+	}
+	_, err := io.WriteString(d.out, strings.Join(cells, "\t")+"\n")
+	return err
+}
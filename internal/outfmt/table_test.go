@@ -0,0 +1,44 @@
+package outfmt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestDelimitedWriter_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewDelimitedWriter(&buf, TableCSV)
+	fmt.Fprintln(w, "ID\tNAME\tNOTE")
+	fmt.Fprintf(w, "1\tSmith, Ann\tsays \"hi\"\n2\tBo")
+	fmt.Fprint(w, "b\t\n")
+	fmt.Fprint(w, "3\tpartial\tx")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	want := "ID,NAME,NOTE\n1,\"Smith, Ann\",\"says \"\"hi\"\"\"\n2,Bob,\n3,partial,x\n"
+	if buf.String() != want {
+		t.Fatalf("got %q want %q", buf.String(), want)
+	}
+}
+
+func TestDelimitedWriter_TSV(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewDelimitedWriter(&buf, TableTSV)
+	fmt.Fprint(w, "A\tB\r\n1\t2\n")
+	_ = w.Flush()
+	if buf.String() != "A\tB\n1\t2\n" {
+		t.Fatalf("got %q", buf.String())
+	}
+}
+
+func TestTableFormat(t *testing.T) {
+	ctx := WithMode(context.Background(), Mode{Plain: true, Table: TableCSV})
+	if TableFormat(ctx) != TableCSV || !IsPlain(ctx) {
+		t.Fatalf("unexpected mode")
+	}
+	if TableFormat(context.Background()) != "" {
+		t.Fatalf("expected no table format")
+	}
+}
@@ -0,0 +1,134 @@
+// Package release verifies downloaded release archives against the signed
+// checksums.txt published with every GitHub release.
+//
+// The release workflow signs checksums.txt with an Ed25519 key
+// (checksums.txt.sig, raw or base64 signature); archives are then checked
+// against their SHA-256 entry in the signed file.
+package release
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PublicKey is the project's Ed25519 release signing key (PEM). The release
+// workflow refuses to sign with a key that doesn't match it.
+//
+//go:embed release.pub
+var PublicKey string
+
+// Result describes a successful verification.
+type Result struct {
+	File           string `json:"file"`
+	SHA256         string `json:"sha256"`
+	KeyFingerprint string `json:"keyFingerprint"`
+}
+
+// ParsePublicKey accepts a base64 raw Ed25519 key or a PEM "PUBLIC KEY" block.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("empty release public key")
+	}
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse release public key: %w", err)
+		}
+		key, ok := pub.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("release public key is not Ed25519")
+		}
+		return key, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("parse release public key: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("release public key: expected %d bytes, got %d", ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// Fingerprint is a short, stable identifier for key.
+func Fingerprint(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// VerifyChecksums checks sig over checksums with key.
+func VerifyChecksums(checksums, sig []byte, key ed25519.PublicKey) error {
+	raw := sig
+	if len(raw) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil || len(decoded) != ed25519.SignatureSize {
+			return errors.New("malformed checksums signature")
+		}
+		raw = decoded
+	}
+	if !ed25519.Verify(key, checksums, raw) {
+		return errors.New("checksums signature does not match the release key")
+	}
+	return nil
+}
+
+// ExpectedSHA256 returns the checksum recorded for name in a sha256sum-style file.
+func ExpectedSHA256(checksums []byte, name string) (string, error) {
+	for _, line := range bytes.Split(checksums, []byte("\n")) {
+		fields := strings.Fields(string(line))
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s is not listed in checksums", name)
+}
+
+// VerifyFile verifies the signature over checksumsPath and then path's SHA-256.
+func VerifyFile(path, checksumsPath, sigPath string, key ed25519.PublicKey) (Result, error) {
+	checksums, err := os.ReadFile(checksumsPath)
+	if err != nil {
+		return Result{}, err
+	}
+	sig, err := os.ReadFile(sigPath)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := VerifyChecksums(checksums, sig, key); err != nil {
+		return Result{}, err
+	}
+
+	want, err := ExpectedSHA256(checksums, filepath.Base(path))
+	if err != nil {
+		return Result{}, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return Result{}, err
+	}
+	got := hex.EncodeToString(h.Sum(nil))
+	if got != want {
+		return Result{}, fmt.Errorf("sha256 mismatch for %s: got %s, want %s", filepath.Base(path), got, want)
+	}
+	return Result{File: path, SHA256: got, KeyFingerprint: Fingerprint(key)}, nil
+}
@@ -0,0 +1,108 @@
+package release
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRelease(t *testing.T, priv ed25519.PrivateKey, archive []byte) (dir string) {
+	t.Helper()
+	dir = t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "gogcli_1.0.0_linux_amd64.tar.gz"), archive, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	sum := sha256.Sum256(archive)
+	checksums := hex.EncodeToString(sum[:]) + "  gogcli_1.0.0_linux_amd64.tar.gz\n" +
+		strings.Repeat("0", 64) + "  gogcli_1.0.0_darwin_arm64.tar.gz\n"
+	if err := os.WriteFile(filepath.Join(dir, "checksums.txt"), []byte(checksums), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	sig := ed25519.Sign(priv, []byte(checksums))
+	if err := os.WriteFile(filepath.Join(dir, "checksums.txt.sig"), sig, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return dir
+}
+
+func TestVerifyFile(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	dir := writeRelease(t, priv, []byte("archive bytes"))
+	archive := filepath.Join(dir, "gogcli_1.0.0_linux_amd64.tar.gz")
+	checksums := filepath.Join(dir, "checksums.txt")
+	sig := filepath.Join(dir, "checksums.txt.sig")
+
+	res, err := VerifyFile(archive, checksums, sig, pub)
+	if err != nil {
+		t.Fatalf("VerifyFile: %v", err)
+	}
+	if res.KeyFingerprint != Fingerprint(pub) || len(res.SHA256) != 64 {
+		t.Fatalf("unexpected result: %#v", res)
+	}
+
+	// Tampered archive.
+	if err := os.WriteFile(archive, []byte("evil"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := VerifyFile(archive, checksums, sig, pub); err == nil || !strings.Contains(err.Error(), "mismatch") {
+		t.Fatalf("expected mismatch, got %v", err)
+	}
+
+	// Wrong key.
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := VerifyFile(archive, checksums, sig, otherPub); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Fatalf("expected signature error, got %v", err)
+	}
+}
+
+func TestVerifyChecksums_Base64Signature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	msg := []byte("abc  file\n")
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, msg)) + "\n"
+	if err := VerifyChecksums(msg, []byte(sig), pub); err != nil {
+		t.Fatalf("VerifyChecksums: %v", err)
+	}
+	if err := VerifyChecksums(msg, []byte("short"), pub); err == nil {
+		t.Fatalf("expected malformed signature error")
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	got, err := ParsePublicKey(base64.StdEncoding.EncodeToString(pub))
+	if err != nil || !got.Equal(pub) {
+		t.Fatalf("base64: %v", err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	got, err = ParsePublicKey(string(pemKey))
+	if err != nil || !got.Equal(pub) {
+		t.Fatalf("pem: %v", err)
+	}
+
+	if _, err := ParsePublicKey("AAAA"); err == nil {
+		t.Fatalf("expected size error")
+	}
+}
+
+func TestEmbeddedPublicKey(t *testing.T) {
+	if _, err := ParsePublicKey(PublicKey); err != nil {
+		t.Fatalf("release.pub: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/99designs/keyring"
+)
+
+// Token store backends selectable with --token-store / GOG_TOKEN_STORE.
+const (
+	// BackendKeyring uses the OS keychain, falling back to the encrypted file.
+	BackendKeyring = "keyring"
+	// BackendFile always uses the encrypted file keyring (GOG_KEYRING_PASSWORD).
+	BackendFile = "file"
+	// BackendPass stores entries in pass(1) (gpg), under GOG_PASS_DIR if set.
+	BackendPass = "pass"
+	// BackendEnv reads a single token from the environment (read-only).
+	BackendEnv = "env"
+)
+
+const tokenStoreEnv = "GOG_TOKEN_STORE"
+
+var (
+	backendMu       sync.Mutex
+	selectedBackend string
+)
+
+// Backends lists the supported token store backends.
+func Backends() []string {
+	return []string{BackendKeyring, BackendFile, BackendPass, BackendEnv}
+}
+
+// ParseBackend normalizes a backend name; empty means BackendKeyring.
+func ParseBackend(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return BackendKeyring, nil
+	}
+	for _, b := range Backends() {
+		if name == b {
+			return b, nil
+		}
+	}
+	return "", fmt.Errorf("unknown token store %q (expected %s)", name, strings.Join(Backends(), "|"))
+}
+
+// SetBackend selects the backend OpenDefault uses for this process. An empty
+// name falls back to GOG_TOKEN_STORE, then BackendKeyring.
+func SetBackend(name string) error {
+	if strings.TrimSpace(name) != "" {
+		if _, err := ParseBackend(name); err != nil {
+			return err
+		}
+	}
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	selectedBackend = strings.TrimSpace(name)
+	return nil
+}
+
+// CurrentBackend reports the backend OpenDefault would open.
+func CurrentBackend() (string, error) {
+	backendMu.Lock()
+	name := selectedBackend
+	backendMu.Unlock()
+	if name == "" {
+		name = os.Getenv(tokenStoreEnv)
+	}
+	return ParseBackend(name)
+}
+
+// OpenDefault opens the selected token store (see SetBackend).
+func OpenDefault() (Store, error) {
+	name, err := CurrentBackend()
+	if err != nil {
+		return nil, err
+	}
+	return Open(name)
+}
+
+// Open opens a specific token store backend.
+func Open(backend string) (Store, error) {
+	name, err := ParseBackend(backend)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case BackendFile:
+		return openKeyring([]keyring.BackendType{keyring.FileBackend})
+	case BackendPass:
+		return openKeyring([]keyring.BackendType{keyring.PassBackend})
+	case BackendEnv:
+		return EnvStore{}, nil
+	default:
+		return openKeyring(nil)
+	}
+}
+
+// Migrate copies every token (and the default account, if set) from src to
+// dst and returns the migrated emails. With deleteSource the tokens are
+// removed from src once all of them were written.
+func Migrate(src, dst Store, deleteSource bool) ([]string, error) {
+	toks, err := src.ListTokens()
+	if err != nil {
+		return nil, fmt.Errorf("list source tokens: %w", err)
+	}
+	emails := make([]string, 0, len(toks))
+	for _, tok := range toks {
+		if err := dst.SetToken(tok.Email, tok); err != nil {
+			return emails, fmt.Errorf("write %s: %w", tok.Email, err)
+		}
+		emails = append(emails, tok.Email)
+	}
+	if def, err := src.GetDefaultAccount(); err == nil && def != "" {
+		if err := dst.SetDefaultAccount(def); err != nil {
+			return emails, fmt.Errorf("write default account: %w", err)
+		}
+	}
+	if deleteSource {
+		for _, email := range emails {
+			if err := src.DeleteToken(email); err != nil {
+				return emails, fmt.Errorf("delete %s from source: %w", email, err)
+			}
+		}
+	}
+	return emails, nil
+}
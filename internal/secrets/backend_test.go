@@ -0,0 +1,102 @@
+package secrets
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/99designs/keyring"
+)
+
+func TestParseBackend(t *testing.T) {
+	for in, want := range map[string]string{"": BackendKeyring, " File ": BackendFile, "pass": BackendPass, "ENV": BackendEnv} {
+		got, err := ParseBackend(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseBackend(%q)=%q,%v want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseBackend("vault"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestCurrentBackend_FlagOverridesEnv(t *testing.T) {
+	t.Cleanup(func() { _ = SetBackend("") })
+	t.Setenv(tokenStoreEnv, "pass")
+
+	if err := SetBackend(""); err != nil {
+		t.Fatalf("SetBackend: %v", err)
+	}
+	if got, _ := CurrentBackend(); got != BackendPass {
+		t.Fatalf("expected env backend, got %q", got)
+	}
+	if err := SetBackend("env"); err != nil {
+		t.Fatalf("SetBackend: %v", err)
+	}
+	if got, _ := CurrentBackend(); got != BackendEnv {
+		t.Fatalf("expected flag backend, got %q", got)
+	}
+	if err := SetBackend("nope"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestEnvStore(t *testing.T) {
+	t.Setenv(envTokenJSON, "")
+	t.Setenv(envRefreshToken, "rt")
+	t.Setenv(envAccount, "A@B.com")
+
+	var s EnvStore
+	tok, err := s.GetToken("a@b.com")
+	if err != nil || tok.RefreshToken != "rt" || tok.Email != "a@b.com" {
+		t.Fatalf("GetToken: %#v %v", tok, err)
+	}
+	if _, err := s.GetToken("c@d.com"); !errors.Is(err, keyring.ErrKeyNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if def, _ := s.GetDefaultAccount(); def != "a@b.com" {
+		t.Fatalf("default: %q", def)
+	}
+	if err := s.SetToken("a@b.com", Token{RefreshToken: "x"}); err == nil {
+		t.Fatalf("expected read-only error")
+	}
+
+	t.Setenv(envTokenJSON, `{"email":"c@d.com","services":["gmail"],"refresh_token":"rt2","created_at":"2025-01-02T03:04:05Z"}`)
+	list, err := s.ListTokens()
+	if err != nil || len(list) != 1 || list[0].Email != "c@d.com" || list[0].RefreshToken != "rt2" || list[0].CreatedAt.IsZero() {
+		t.Fatalf("ListTokens: %#v %v", list, err)
+	}
+
+	t.Setenv(envTokenJSON, "")
+	t.Setenv(envAccount, "")
+	if _, err := s.ListTokens(); err == nil {
+		t.Fatalf("expected error without GOG_ACCOUNT")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	src := &KeyringStore{ring: keyring.NewArrayKeyring(nil)}
+	dst := &KeyringStore{ring: keyring.NewArrayKeyring(nil)}
+	for _, email := range []string{"a@b.com", "c@d.com"} {
+		if err := src.SetToken(email, Token{RefreshToken: "rt-" + email, Services: []string{"gmail"}}); err != nil {
+			t.Fatalf("SetToken: %v", err)
+		}
+	}
+	if err := src.SetDefaultAccount("c@d.com"); err != nil {
+		t.Fatalf("SetDefaultAccount: %v", err)
+	}
+
+	emails, err := Migrate(src, dst, true)
+	if err != nil || len(emails) != 2 {
+		t.Fatalf("Migrate: %v %v", emails, err)
+	}
+	tok, err := dst.GetToken("a@b.com")
+	if err != nil || tok.RefreshToken != "rt-a@b.com" || len(tok.Services) != 1 {
+		t.Fatalf("dst token: %#v %v", tok, err)
+	}
+	if def, _ := dst.GetDefaultAccount(); def != "c@d.com" {
+		t.Fatalf("default: %q", def)
+	}
+	if left, _ := src.ListTokens(); len(left) != 0 {
+		t.Fatalf("expected source emptied, got %#v", left)
+	}
+}
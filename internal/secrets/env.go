@@ -0,0 +1,110 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/99designs/keyring"
+)
+
+const (
+	// envTokenJSON holds a token in "gog auth tokens export" format.
+	envTokenJSON = "GOG_TOKEN_JSON"
+	// envRefreshToken holds a bare refresh token for GOG_ACCOUNT.
+	envRefreshToken = "GOG_REFRESH_TOKEN"
+	envAccount      = "GOG_ACCOUNT"
+)
+
+var errEnvStoreReadOnly = errors.New("env token store is read-only; set GOG_TOKEN_JSON or GOG_REFRESH_TOKEN (with GOG_ACCOUNT) instead")
+
+// EnvStore serves a single token injected through the environment, for
+// containers and CI where no keyring exists. It never writes.
+type EnvStore struct{}
+
+type envToken struct {
+	Email        string   `json:"email"`
+	Services     []string `json:"services,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	CreatedAt    string   `json:"created_at,omitempty"`
+	RefreshToken string   `json:"refresh_token"`
+}
+
+func (EnvStore) token() (Token, bool, error) {
+	if raw := strings.TrimSpace(os.Getenv(envTokenJSON)); raw != "" {
+		var et envToken
+		if err := json.Unmarshal([]byte(raw), &et); err != nil {
+			return Token{}, false, fmt.Errorf("parse %s: %w", envTokenJSON, err)
+		}
+		if normalize(et.Email) == "" || strings.TrimSpace(et.RefreshToken) == "" {
+			return Token{}, false, fmt.Errorf("%s needs email and refresh_token", envTokenJSON)
+		}
+		tok := Token{
+			Email:        normalize(et.Email),
+			Services:     et.Services,
+			Scopes:       et.Scopes,
+			RefreshToken: strings.TrimSpace(et.RefreshToken),
+		}
+		if et.CreatedAt != "" {
+			if t, err := time.Parse(time.RFC3339, et.CreatedAt); err == nil {
+				tok.CreatedAt = t
+			}
+		}
+		return tok, true, nil
+	}
+	rt := strings.TrimSpace(os.Getenv(envRefreshToken))
+	if rt == "" {
+		return Token{}, false, nil
+	}
+	email := normalize(os.Getenv(envAccount))
+	if email == "" {
+		return Token{}, false, fmt.Errorf("%s requires %s", envRefreshToken, envAccount)
+	}
+	return Token{Email: email, RefreshToken: rt}, true, nil
+}
+
+func (s EnvStore) Keys() ([]string, error) {
+	tok, ok, err := s.token()
+	if err != nil || !ok {
+		return nil, err
+	}
+	return []string{tokenKey(tok.Email)}, nil
+}
+
+func (s EnvStore) GetToken(email string) (Token, error) {
+	email = normalize(email)
+	if email == "" {
+		return Token{}, fmt.Errorf("missing email")
+	}
+	tok, ok, err := s.token()
+	if err != nil {
+		return Token{}, err
+	}
+	if !ok || tok.Email != email {
+		return Token{}, keyring.ErrKeyNotFound
+	}
+	return tok, nil
+}
+
+func (s EnvStore) ListTokens() ([]Token, error) {
+	tok, ok, err := s.token()
+	if err != nil || !ok {
+		return []Token{}, err
+	}
+	return []Token{tok}, nil
+}
+
+func (s EnvStore) GetDefaultAccount() (string, error) {
+	tok, ok, err := s.token()
+	if err != nil || !ok {
+		return "", err
+	}
+	return tok.Email, nil
+}
+
+func (EnvStore) SetToken(string, Token) error   { return errEnvStoreReadOnly }
+func (EnvStore) DeleteToken(string) error       { return errEnvStoreReadOnly }
+func (EnvStore) SetDefaultAccount(string) error { return errEnvStoreReadOnly }
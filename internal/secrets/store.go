@@ -56,7 +56,9 @@ func fileKeyringPasswordFunc() keyring.PromptFunc {
 	return fileKeyringPasswordFuncFrom(os.Getenv(keyringPasswordEnv), term.IsTerminal(int(os.Stdin.Fd())))
 }
 
-func OpenDefault() (Store, error) {
+// openKeyring opens the 99designs keyring. allowed restricts the backends
+// (nil lets the library pick the OS keychain, falling back to the file backend).
+func openKeyring(allowed []keyring.BackendType) (Store, error) {
 	// On Linux/WSL/containers, OS keychains (secret-service/kwallet) may be unavailable.
 	// In that case github.com/99designs/keyring falls back to the "file" backend,
 	// which *requires* both a directory and a password prompt function.
@@ -72,9 +74,8 @@ func OpenDefault() (Store, error) {
 
 	// If a file keyring dir/password is explicitly set, prefer the file backend.
 	// This avoids hanging on secret-service in headless/non-interactive sessions.
-	var allowedBackends []keyring.BackendType
-	if strings.TrimSpace(os.Getenv("GOG_KEYRING_FILE_DIR")) != "" || strings.TrimSpace(os.Getenv(keyringPasswordEnv)) != "" {
-		allowedBackends = []keyring.BackendType{keyring.FileBackend}
+	if allowed == nil && (strings.TrimSpace(os.Getenv("GOG_KEYRING_FILE_DIR")) != "" || strings.TrimSpace(os.Getenv(keyringPasswordEnv)) != "") {
+		allowed = []keyring.BackendType{keyring.FileBackend}
 	}
 
 	ring, err := keyring.Open(keyring.Config{
@@ -82,7 +83,9 @@ func OpenDefault() (Store, error) {
 		KeychainTrustApplication: runtime.GOOS == "darwin",
 		FileDir:                  fileDir,
 		FilePasswordFunc:         fileKeyringPasswordFunc(),
-		AllowedBackends:          allowedBackends,
+		PassDir:                  strings.TrimSpace(os.Getenv("GOG_PASS_DIR")),
+		PassPrefix:               config.AppName,
+		AllowedBackends:          allowed,
 	})
 	if err != nil {
 		return nil, err
@@ -222,3 +225,26 @@ func (s *KeyringStore) SetDefaultAccount(email string) error {
 		Data: []byte(email),
 	})
 }
+
+// GetSecret reads an arbitrary (non-token) secret, e.g. the state encryption key.
+func (s *KeyringStore) GetSecret(key string) ([]byte, error) {
+	it, err := s.ring.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return it.Data, nil
+}
+
+func (s *KeyringStore) SetSecret(key string, data []byte) error {
+	if strings.TrimSpace(key) == "" {
+		return fmt.Errorf("missing key")
+	}
+	return s.ring.Set(keyring.Item{
+		Key:  key,
+		Data: data,
+	})
+}
+
+func (s *KeyringStore) DeleteSecret(key string) error {
+	return s.ring.Remove(key)
+}
@@ -0,0 +1,444 @@
+// Package statefile reads and writes local state files (watch state, caches,
+// indexes) and transparently encrypts them once `gog secure enable` is on.
+package statefile
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/secrets"
+)
+
+type Mode string
+
+const (
+	ModeOff        Mode = ""
+	ModeKeyring    Mode = "keyring"
+	ModePassphrase Mode = "passphrase"
+)
+
+// PassphraseEnv supplies the passphrase for ModePassphrase in non-interactive runs.
+const PassphraseEnv = "GOG_STATE_PASSPHRASE"
+
+const (
+	magic            = "GOGENC1\n"
+	keyringSecretKey = "state_encryption_key"
+	keyLen           = 32
+	pbkdf2Iterations = 600_000
+	checkPlaintext   = "gogcli-state"
+)
+
+// Settings is persisted at config.SecureSettingsPath.
+type Settings struct {
+	Mode  Mode   `json:"mode"`
+	Salt  string `json:"salt,omitempty"`
+	Check string `json:"check"`
+}
+
+type keyStore interface {
+	GetSecret(key string) ([]byte, error)
+	SetSecret(key string, data []byte) error
+	DeleteSecret(key string) error
+}
+
+var (
+	openKeyStore = func() (keyStore, error) {
+		s, err := secrets.OpenDefault()
+		if err != nil {
+			return nil, err
+		}
+		ks, ok := s.(keyStore)
+		if !ok {
+			return nil, errors.New("secret store does not support encryption keys")
+		}
+		return ks, nil
+	}
+	readPassphrase = defaultReadPassphrase
+
+	keyMu     sync.Mutex
+	cachedKey []byte
+)
+
+// IsEncrypted reports whether data was written by an encrypting WriteFile.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(magic))
+}
+
+func LoadSettings() (Settings, error) {
+	path, err := config.SecureSettingsPath()
+	if err != nil {
+		return Settings{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Settings{}, nil
+		}
+		return Settings{}, err
+	}
+	var st Settings
+	if err := json.Unmarshal(data, &st); err != nil {
+		return Settings{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return st, nil
+}
+
+// ReadFile reads path, decrypting it when it is encrypted.
+// Plaintext files are returned unchanged so state written before
+// `gog secure enable` keeps working.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	st, err := LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	if st.Mode == ModeOff {
+		return nil, fmt.Errorf("%s is encrypted but secure state is disabled", path)
+	}
+	key, err := unlock(st)
+	if err != nil {
+		return nil, err
+	}
+	return open(key, data)
+}
+
+// WriteFile writes data to path (mode perm), encrypting it when secure state is enabled.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	st, err := LoadSettings()
+	if err != nil {
+		return err
+	}
+	if st.Mode == ModeOff {
+		return os.WriteFile(path, data, perm)
+	}
+	key, err := unlock(st)
+	if err != nil {
+		return err
+	}
+	sealed, err := seal(key, data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, sealed, 0o600)
+}
+
+// Enable turns on encryption and migrates existing plaintext files under dirs.
+// It returns the migrated file paths.
+func Enable(mode Mode, dirs ...string) ([]string, error) {
+	st, err := LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	if st.Mode != ModeOff {
+		return nil, fmt.Errorf("secure state already enabled (mode %s)", st.Mode)
+	}
+
+	var key []byte
+	switch mode {
+	case ModeKeyring:
+		key = make([]byte, keyLen)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		ks, err := openKeyStore()
+		if err != nil {
+			return nil, err
+		}
+		if err := ks.SetSecret(keyringSecretKey, key); err != nil {
+			return nil, err
+		}
+		st = Settings{Mode: ModeKeyring}
+	case ModePassphrase:
+		pass, err := readPassphrase(true)
+		if err != nil {
+			return nil, err
+		}
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		key, err = deriveKey(pass, salt)
+		if err != nil {
+			return nil, err
+		}
+		st = Settings{Mode: ModePassphrase, Salt: base64.StdEncoding.EncodeToString(salt)}
+	default:
+		return nil, fmt.Errorf("unsupported secure mode %q", mode)
+	}
+
+	check, err := seal(key, []byte(checkPlaintext))
+	if err != nil {
+		return nil, err
+	}
+	st.Check = base64.StdEncoding.EncodeToString(check)
+	if err := saveSettings(st); err != nil {
+		return nil, err
+	}
+	setCachedKey(key)
+
+	return rewriteAll(dirs, func(data []byte) ([]byte, bool, error) {
+		if IsEncrypted(data) {
+			return nil, false, nil
+		}
+		out, err := seal(key, data)
+		return out, true, err
+	})
+}
+
+// Disable decrypts every encrypted file under dirs and removes the settings.
+// It returns the decrypted file paths.
+func Disable(dirs ...string) ([]string, error) {
+	st, err := LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	if st.Mode == ModeOff {
+		return nil, errors.New("secure state is not enabled")
+	}
+	key, err := unlock(st)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := rewriteAll(dirs, func(data []byte) ([]byte, bool, error) {
+		if !IsEncrypted(data) {
+			return nil, false, nil
+		}
+		out, err := open(key, data)
+		return out, true, err
+	})
+	if err != nil {
+		return changed, err
+	}
+
+	path, err := config.SecureSettingsPath()
+	if err != nil {
+		return changed, err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return changed, err
+	}
+	if st.Mode == ModeKeyring {
+		if ks, ksErr := openKeyStore(); ksErr == nil {
+			_ = ks.DeleteSecret(keyringSecretKey)
+		}
+	}
+	setCachedKey(nil)
+	return changed, nil
+}
+
+func rewriteAll(dirs []string, fn func([]byte) ([]byte, bool, error)) ([]string, error) {
+	var changed []string
+	for _, dir := range dirs {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || !d.Type().IsRegular() {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			out, ok, err := fn(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			if !ok {
+				return nil
+			}
+			if err := writeAtomic(path, out); err != nil {
+				return err
+			}
+			changed = append(changed, path)
+			return nil
+		})
+		if err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+func writeAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func saveSettings(st Settings) error {
+	if _, err := config.EnsureDir(); err != nil {
+		return err
+	}
+	path, err := config.SecureSettingsPath()
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, append(b, '\n'))
+}
+
+func unlock(st Settings) ([]byte, error) {
+	keyMu.Lock()
+	if cachedKey != nil {
+		k := cachedKey
+		keyMu.Unlock()
+		return k, nil
+	}
+	keyMu.Unlock()
+
+	var key []byte
+	switch st.Mode {
+	case ModeKeyring:
+		ks, err := openKeyStore()
+		if err != nil {
+			return nil, err
+		}
+		key, err = ks.GetSecret(keyringSecretKey)
+		if err != nil {
+			return nil, fmt.Errorf("read state encryption key from keyring: %w", err)
+		}
+	case ModePassphrase:
+		salt, err := base64.StdEncoding.DecodeString(st.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secure settings salt: %w", err)
+		}
+		pass, err := readPassphrase(false)
+		if err != nil {
+			return nil, err
+		}
+		key, err = deriveKey(pass, salt)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported secure mode %q", st.Mode)
+	}
+
+	check, err := base64.StdEncoding.DecodeString(st.Check)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secure settings check: %w", err)
+	}
+	if plain, err := open(key, check); err != nil || string(plain) != checkPlaintext {
+		return nil, errors.New("wrong state encryption key or passphrase")
+	}
+	setCachedKey(key)
+	return key, nil
+}
+
+func setCachedKey(key []byte) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	cachedKey = key
+}
+
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("empty passphrase")
+	}
+	return pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, keyLen)
+}
+
+func seal(key []byte, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(magic), nonce...)
+	return gcm.Seal(out, nonce, plaintext, []byte(magic)), nil
+}
+
+func open(key []byte, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	body := bytes.TrimPrefix(data, []byte(magic))
+	if len(body) < gcm.NonceSize() {
+		return nil, errors.New("encrypted state file is truncated")
+	}
+	nonce, ciphertext := body[:gcm.NonceSize()], body[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, []byte(magic))
+	if err != nil {
+		return nil, errors.New("decrypt state file: authentication failed")
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func defaultReadPassphrase(confirm bool) (string, error) {
+	if v := os.Getenv(PassphraseEnv); v != "" {
+		return v, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("no TTY available for state passphrase prompt; set %s", PassphraseEnv)
+	}
+	pass, err := promptPassphrase("State passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		again, err := promptPassphrase("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != pass {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return pass, nil
+}
+
+func promptPassphrase(prompt string) (string, error) {
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(int(os.Stdin.Fd()))
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
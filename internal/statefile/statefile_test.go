@@ -0,0 +1,164 @@
+package statefile
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/99designs/keyring"
+)
+
+type memKeyStore struct {
+	data map[string][]byte
+}
+
+func (m *memKeyStore) GetSecret(key string) ([]byte, error) {
+	v, ok := m.data[key]
+	if !ok {
+		return nil, keyring.ErrKeyNotFound
+	}
+	return v, nil
+}
+
+func (m *memKeyStore) SetSecret(key string, data []byte) error {
+	m.data[key] = data
+	return nil
+}
+
+func (m *memKeyStore) DeleteSecret(key string) error {
+	delete(m.data, key)
+	return nil
+}
+
+func setupConfig(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	setCachedKey(nil)
+	t.Cleanup(func() { setCachedKey(nil) })
+
+	stateDir := filepath.Join(root, "state")
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	return stateDir
+}
+
+func TestWriteFile_PlaintextWhenDisabled(t *testing.T) {
+	dir := setupConfig(t)
+	path := filepath.Join(dir, "a.json")
+	if err := WriteFile(path, []byte(`{"a":1}`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	if IsEncrypted(raw) || string(raw) != `{"a":1}` {
+		t.Fatalf("expected plaintext, got %q", raw)
+	}
+}
+
+func TestEnableKeyring_MigratesAndRoundTrips(t *testing.T) {
+	dir := setupConfig(t)
+	store := &memKeyStore{data: map[string][]byte{}}
+	origOpen := openKeyStore
+	t.Cleanup(func() { openKeyStore = origOpen })
+	openKeyStore = func() (keyStore, error) { return store, nil }
+
+	existing := filepath.Join(dir, "nested", "watch.json")
+	_ = os.MkdirAll(filepath.Dir(existing), 0o700)
+	if err := os.WriteFile(existing, []byte("snippet"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	migrated, err := Enable(ModeKeyring, dir)
+	if err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if len(migrated) != 1 || migrated[0] != existing {
+		t.Fatalf("unexpected migrated: %#v", migrated)
+	}
+	raw, _ := os.ReadFile(existing)
+	if !IsEncrypted(raw) || bytes.Contains(raw, []byte("snippet")) {
+		t.Fatalf("expected ciphertext on disk, got %q", raw)
+	}
+
+	// Fresh process: key must come back from the keyring.
+	setCachedKey(nil)
+	got, err := ReadFile(existing)
+	if err != nil || string(got) != "snippet" {
+		t.Fatalf("ReadFile: %q %v", got, err)
+	}
+
+	newPath := filepath.Join(dir, "new.json")
+	if err := WriteFile(newPath, []byte("fresh"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	raw, _ = os.ReadFile(newPath)
+	if !IsEncrypted(raw) {
+		t.Fatalf("expected new writes to be encrypted")
+	}
+
+	if _, err := Enable(ModeKeyring, dir); err == nil {
+		t.Fatalf("expected error when enabling twice")
+	}
+
+	decrypted, err := Disable(dir)
+	if err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	if len(decrypted) != 2 {
+		t.Fatalf("unexpected decrypted: %#v", decrypted)
+	}
+	raw, _ = os.ReadFile(existing)
+	if string(raw) != "snippet" {
+		t.Fatalf("expected plaintext after disable, got %q", raw)
+	}
+	if _, ok := store.data[keyringSecretKey]; ok {
+		t.Fatalf("expected keyring secret to be removed")
+	}
+}
+
+func TestPassphraseMode_WrongPassphrase(t *testing.T) {
+	dir := setupConfig(t)
+	origRead := readPassphrase
+	t.Cleanup(func() { readPassphrase = origRead })
+	pass := "correct horse"
+	readPassphrase = func(bool) (string, error) { return pass, nil }
+
+	if _, err := Enable(ModePassphrase, dir); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	path := filepath.Join(dir, "s.json")
+	if err := WriteFile(path, []byte("secret"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	setCachedKey(nil)
+	pass = "wrong"
+	if _, err := ReadFile(path); err == nil {
+		t.Fatalf("expected wrong passphrase error")
+	}
+
+	setCachedKey(nil)
+	pass = "correct horse"
+	got, err := ReadFile(path)
+	if err != nil || string(got) != "secret" {
+		t.Fatalf("ReadFile: %q %v", got, err)
+	}
+}
+
+func TestOpen_Tampered(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, keyLen)
+	sealed, err := seal(key, []byte("hello"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	sealed[len(sealed)-1] ^= 0xff
+	if _, err := open(key, sealed); err == nil {
+		t.Fatalf("expected authentication failure")
+	}
+	if _, err := open(key, []byte(magic)); err == nil {
+		t.Fatalf("expected truncated error")
+	}
+}
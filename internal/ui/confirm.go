@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrNotInteractive is returned by Confirm when stdin is not a terminal.
+var ErrNotInteractive = errors.New("not interactive")
+
+// Interactive reports whether Confirm can prompt: stdin must be a terminal
+// when it is a file (readers injected by tests always count).
+func (u *UI) Interactive() bool {
+	f, ok := u.in.(*os.File)
+	if !ok {
+		return u.in != nil
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// Confirm prints "<prompt> [y/N]: " on stderr and reads one answer line.
+// Only y/yes accept; anything else, including EOF, declines.
+func (u *UI) Confirm(prompt string) (bool, error) {
+	if !u.Interactive() {
+		return false, ErrNotInteractive
+	}
+	_, _ = io.WriteString(u.err.o, prompt+" [y/N]: ")
+	line, err := bufio.NewReader(u.in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	ans := strings.ToLower(strings.TrimSpace(line))
+	return ans == "y" || ans == "yes", nil
+}
@@ -11,12 +11,14 @@ import (
 )
 
 type Options struct {
+	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
 	Color  string // auto|always|never
 }
 
 type UI struct {
+	in  io.Reader
 	out *Printer
 	err *Printer
 }
@@ -26,6 +28,9 @@ type ParseError struct{ msg string }
 func (e *ParseError) Error() string { return e.msg }
 
 func New(opts Options) (*UI, error) {
+	if opts.Stdin == nil {
+		opts.Stdin = os.Stdin
+	}
 	if opts.Stdout == nil {
 		opts.Stdout = os.Stdout
 	}
@@ -48,6 +53,7 @@ func New(opts Options) (*UI, error) {
 	errProfile := chooseProfile(errOut.Profile, colorMode)
 
 	return &UI{
+		in:  opts.Stdin,
 		out: newPrinter(out, outProfile),
 		err: newPrinter(errOut, errProfile),
 	}, nil
@@ -3,6 +3,8 @@ package ui
 import (
 	"bytes"
 	"context"
+	"errors"
+	"os"
 	"strings"
 	"testing"
 
@@ -114,3 +116,33 @@ func TestWithUIFromContext(t *testing.T) {
 		t.Fatalf("expected nil when absent")
 	}
 }
+
+func TestConfirm(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "": false}
+	for in, want := range cases {
+		var errBuf bytes.Buffer
+		u, err := New(Options{Stdin: strings.NewReader(in), Stdout: &bytes.Buffer{}, Stderr: &errBuf, Color: "never"})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		got, err := u.Confirm("Delete it?")
+		if err != nil || got != want {
+			t.Fatalf("Confirm(%q) = %v, %v", in, got, err)
+		}
+		if errBuf.String() != "Delete it? [y/N]: " {
+			t.Fatalf("prompt = %q", errBuf.String())
+		}
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatalf("CreateTemp: %v", err)
+	}
+	defer f.Close()
+	u, _ := New(Options{Stdin: f, Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}})
+	if _, err := u.Confirm("Delete it?"); !errors.Is(err, ErrNotInteractive) {
+		t.Fatalf("expected ErrNotInteractive, got %v", err)
+	}
+}